@@ -2,47 +2,137 @@ package main
 
 import (
 	"collaborative-bucket-list/internal/handlers"
+	"collaborative-bucket-list/internal/jobs"
 	"collaborative-bucket-list/internal/middleware"
 	"collaborative-bucket-list/internal/repositories"
+	"collaborative-bucket-list/internal/services"
 	"collaborative-bucket-list/internal/websocket"
+	"collaborative-bucket-list/migrations"
 	"collaborative-bucket-list/pkg/database"
+	"collaborative-bucket-list/pkg/storage"
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 )
 
+// shutdownTimeout is how long requests and running jobs get to finish when
+// the server is stopped
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	// Load environment variables from .env file
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using system environment variables")
 	}
 
-	// Initialize database connection
+	// STORAGE selects postgres (default), sqlite or memory. Memory storage
+	// runs the server without a database; data is lost on exit.
 	dbConfig := database.LoadConfigFromEnv()
-	if err := database.Connect(dbConfig); err != nil {
-		log.Fatal("Failed to connect to database:", err)
-	}
-	defer database.Close()
 
-	// Run database migrations
-	if err := database.RunMigrations("migrations"); err != nil {
-		log.Fatal("Failed to run database migrations:", err)
-	}
+	var repoManager repositories.RepositoryManager
+	var jobStore jobs.Store
+	switch dbConfig.Driver {
+	case database.DriverMemory:
+		if len(os.Args) > 1 && os.Args[1] == "migrate" {
+			log.Fatal("Migrations need a database; unset STORAGE=memory")
+		}
+		log.Println("Using in-memory storage; data will not survive a restart")
+		repoManager = repositories.NewMemoryRepositoryManager()
+		jobStore = jobs.NewMemoryStore()
+
+	case database.DriverPostgres, database.DriverSQLite:
+		// Initialize database connection
+		if err := database.Connect(dbConfig); err != nil {
+			log.Fatal("Failed to connect to database:", err)
+		}
+		defer database.Close()
+
+		// `main migrate up|down|status|redo` manages the schema and exits
+		if len(os.Args) > 1 && os.Args[1] == "migrate" {
+			if err := runMigrateCommand(dbConfig.Driver, os.Args[2:]); err != nil {
+				log.Fatal("Migration failed:", err)
+			}
+			return
+		}
+
+		// Verify applied migrations and run pending ones
+		if err := database.RunMigrations(migrations.ForDriver(dbConfig.Driver)); err != nil {
+			log.Fatal("Failed to run database migrations:", err)
+		}
+
+		// Initialize repository manager
+		if dbConfig.Driver == database.DriverSQLite {
+			repoManager = repositories.NewSQLiteRepositoryManager(database.DB)
+			jobStore = jobs.NewSQLiteStore(database.DB)
+		} else {
+			repoManager = repositories.NewPostgresRepositoryManager(database.DB)
+			jobStore = jobs.NewPostgresStore(database.DB)
+		}
 
-	// Initialize repository manager
-	repoManager := repositories.NewPostgresRepositoryManager(database.DB)
+	default:
+		log.Fatalf("Unknown STORAGE %q: expected postgres, sqlite or memory", dbConfig.Driver)
+	}
 
 	// Initialize WebSocket hub
-	hub := websocket.NewHub()
+	wsConfig := websocket.LoadConfigFromEnv()
+	if err := wsConfig.Validate(); err != nil {
+		log.Fatal("Invalid WebSocket configuration:", err)
+	}
+	hub := websocket.NewHubWithConfig(wsConfig)
 	go hub.Run()
 
+	// Run background jobs. Servers sharing a database share the queue, and
+	// each job runs on one of them.
+	jobConfig := jobs.LoadConfigFromEnv()
+	if err := jobConfig.Validate(); err != nil {
+		log.Fatal("Invalid job queue configuration:", err)
+	}
+	jobQueue := jobs.NewQueue(jobStore, jobConfig)
+
+	// Remind group members of their deadline. Reminders missed while no
+	// server ran are caught up on, as far back as sent ones are remembered.
+	reminderCatchUp := min(services.DefaultReminderCatchUp, jobConfig.Retention)
+	reminderScheduler := services.NewReminderScheduler(repoManager, jobQueue, services.NewNotifierFromEnv(), reminderCatchUp)
+	jobQueue.Start()
+	go reminderScheduler.Run(context.Background(), services.DefaultReminderCheckInterval)
+
+	// Tell group rooms when their deadline is approaching or has passed
+	deadlineScheduler := services.NewDeadlineScheduler(repoManager, hub)
+	go deadlineScheduler.Run(context.Background(), services.DefaultDeadlineCheckInterval)
+
+	// Initialize the blob store for attachments. BLOB_STORE selects a local
+	// directory (default) or an S3-compatible bucket.
+	blobConfig := storage.LoadConfigFromEnv()
+	blobStore, err := storage.New(blobConfig)
+	if err != nil {
+		log.Fatal("Failed to initialize blob store:", err)
+	}
+
+	// Remove the blobs of deleted attachments in the background
+	blobCleaner := services.NewBlobCleaner(repoManager, blobStore)
+	go blobCleaner.Run(context.Background(), blobConfig.CleanupInterval)
+
+	// Strip metadata from uploaded photos and render their thumbnails
+	imageProcessor := services.NewImageProcessor(repoManager, blobStore)
+	go imageProcessor.Run(context.Background(), blobConfig.ProcessInterval)
+
 	// Initialize handlers
-	groupHandler := handlers.NewGroupHandler(repoManager)
+	groupHandler := handlers.NewGroupHandlerWithBlobStore(repoManager, blobStore, blobConfig.URLExpiry)
 	bucketItemHandler := handlers.NewBucketItemHandler(repoManager)
+	tagHandler := handlers.NewTagHandler(repoManager)
+	commentHandler := handlers.NewCommentHandler(repoManager, hub)
+	assignmentHandler := handlers.NewAssignmentHandler(repoManager, hub)
+	attachmentHandler := handlers.NewAttachmentHandler(repoManager, blobStore, blobConfig.URLExpiry)
 	wsHandler := handlers.NewWebSocketHandler(hub, repoManager)
 
 	// Set up Gin router
@@ -62,6 +152,15 @@ func main() {
 
 	// Basic health check endpoint
 	r.GET("/health", func(c *gin.Context) {
+		if dbConfig.Driver == database.DriverMemory {
+			c.JSON(200, gin.H{
+				"status": "ok",
+				"message": "Collaborative Bucket List API is running",
+				"database": "not used (in-memory storage)",
+			})
+			return
+		}
+
 		// Check database health
 		if err := database.HealthCheck(); err != nil {
 			c.JSON(503, gin.H{
@@ -79,6 +178,13 @@ func main() {
 		})
 	})
 
+	// Signed attachment downloads, when blobs are kept on the local disk
+	if localStore, ok := blobStore.(*storage.LocalStore); ok {
+		blobHandler := gin.WrapH(http.StripPrefix("/blobs", localStore))
+		r.GET("/blobs/*key", blobHandler)
+		r.HEAD("/blobs/*key", blobHandler)
+	}
+
 	// Auth verification endpoint
 	r.POST("/api/auth/verify", middleware.AuthMiddleware(), func(c *gin.Context) {
 		user, exists := middleware.GetUserFromContext(c)
@@ -108,23 +214,116 @@ func main() {
 		// GET /api/groups/:id - Get group details
 		api.GET("/groups/:id", groupHandler.GetGroup)
 		
+		// PATCH /api/groups/:id - Change a group's name, deadline, timezone or deadline lock (requires authentication, group creator only)
+		api.PATCH("/groups/:id", middleware.AuthMiddleware(), groupHandler.UpdateGroup)
+		
+		// GET /api/groups/:id/reminders - Get the days before the deadline members are reminded of it
+		api.GET("/groups/:id/reminders", groupHandler.GetReminders)
+		
+		// PUT /api/groups/:id/reminders - Replace the reminder schedule (requires authentication, group creator only)
+		api.PUT("/groups/:id/reminders", middleware.AuthMiddleware(), groupHandler.UpdateReminders)
+		
 		// POST /api/groups/:id/join - Join existing group
 		api.POST("/groups/:id/join", groupHandler.JoinGroup)
 		
 		// POST /api/groups/:id/items - Add new bucket list item
 		api.POST("/groups/:id/items", bucketItemHandler.CreateItem)
 		
+		// GET /api/groups/:id/items - List a group's items with filters, sorting and cursor pagination
+		api.GET("/groups/:id/items", bucketItemHandler.ListItems)
+		
+		// GET /api/groups/:id/items/search - Full-text search of a group's items
+		api.GET("/groups/:id/items/search", bucketItemHandler.SearchItems)
+		
+		// GET /api/groups/:id/memories - Timeline of a group's completed items with their journals
+		api.GET("/groups/:id/memories", bucketItemHandler.GetMemories)
+		
+		// GET /api/groups/:id/calendar - Scheduled items by day (query: from, to)
+		api.GET("/groups/:id/calendar", bucketItemHandler.GetCalendar)
+		
+		// GET /api/groups/:id/calendar.ics - iCalendar feed of the deadline and scheduled items (query: token)
+		api.GET("/groups/:id/calendar.ics", bucketItemHandler.GetCalendarFeed)
+		
+		// POST /api/groups/:id/calendar-feed - Create the calendar feed URL, shown only once (requires authentication, group member)
+		api.POST("/groups/:id/calendar-feed", middleware.AuthMiddleware(), bucketItemHandler.EnableCalendarFeed)
+		
+		// DELETE /api/groups/:id/calendar-feed - Revoke the calendar feed URL (requires authentication, group creator only)
+		api.DELETE("/groups/:id/calendar-feed", middleware.AuthMiddleware(), bucketItemHandler.RevokeCalendarFeed)
+		
+		// GET /api/groups/:id/tags - List a group's tags with per-tag progress
+		api.GET("/groups/:id/tags", tagHandler.GetGroupTags)
+		
+		// POST /api/groups/:id/tags - Create a tag (requires authentication, group creator only)
+		api.POST("/groups/:id/tags", middleware.AuthMiddleware(), tagHandler.CreateTag)
+		
+		// GET /api/groups/:id/events - Server-Sent Events stream of group updates
+		api.GET("/groups/:id/events", wsHandler.HandleEventStream)
+		
 		// GET /api/users/groups - Get user's groups (requires authentication)
 		api.GET("/users/groups", middleware.AuthMiddleware(), groupHandler.GetUserGroups)
 		
+		// GET /api/users/search - Search groups and items across the user's groups (requires authentication)
+		api.GET("/users/search", middleware.AuthMiddleware(), groupHandler.SearchUserGroups)
+		
+		// GET /api/users/assignments - Get items assigned to the user across their groups (requires authentication)
+		api.GET("/users/assignments", middleware.AuthMiddleware(), assignmentHandler.GetUserAssignments)
+		
 		// Bucket list item endpoints
 		// PATCH /api/items/:id/complete - Toggle item completion status
 		api.PATCH("/items/:id/complete", bucketItemHandler.ToggleCompletion)
 		
+		// PUT /api/items/:id/journal - Replace the note, rating and date of a completed item
+		api.PUT("/items/:id/journal", bucketItemHandler.UpdateJournal)
+		
+		// PUT /api/items/:id/schedule - Schedule, reschedule or unschedule an item
+		api.PUT("/items/:id/schedule", bucketItemHandler.UpdateSchedule)
+		
+		// PUT /api/items/:id/tags - Replace an item's tags
+		api.PUT("/items/:id/tags", tagHandler.SetItemTags)
+		
+		// POST /api/items/:id/assignees - Assign a group member to organize an item
+		api.POST("/items/:id/assignees", assignmentHandler.AssignItem)
+		
+		// DELETE /api/items/:id/assignees/:assigneeId - Remove a member's assignment (query: memberId)
+		api.DELETE("/items/:id/assignees/:assigneeId", assignmentHandler.UnassignItem)
+		
+		// GET /api/items/:id/comments - List an item's comment threads
+		api.GET("/items/:id/comments", commentHandler.GetItemComments)
+		
+		// POST /api/items/:id/comments - Comment on an item or reply to a comment
+		api.POST("/items/:id/comments", commentHandler.CreateComment)
+		
+		// GET /api/items/:id/attachments - List an item's attachments with signed download URLs
+		api.GET("/items/:id/attachments", attachmentHandler.GetItemAttachments)
+		
+		// POST /api/items/:id/attachments - Upload a photo or file to an item (multipart: file, memberId)
+		api.POST("/items/:id/attachments", attachmentHandler.UploadAttachment)
+		
+		// Tag endpoints
+		// PUT /api/tags/:id - Rename or recolor a tag (requires authentication, group creator only)
+		api.PUT("/tags/:id", middleware.AuthMiddleware(), tagHandler.UpdateTag)
+		
+		// DELETE /api/tags/:id - Delete a tag and remove it from every item (requires authentication, group creator only)
+		api.DELETE("/tags/:id", middleware.AuthMiddleware(), tagHandler.DeleteTag)
+		
+		// Comment endpoints
+		// PUT /api/comments/:id - Edit a comment (author only)
+		api.PUT("/comments/:id", commentHandler.UpdateComment)
+		
+		// DELETE /api/comments/:id?memberId= - Delete a comment and its replies (author or group creator)
+		api.DELETE("/comments/:id", commentHandler.DeleteComment)
+		
+		// Attachment endpoints
+		// DELETE /api/attachments/:id?memberId= - Delete an attachment and its file (uploader or group creator)
+		api.DELETE("/attachments/:id", attachmentHandler.DeleteAttachment)
+		
 		// WebSocket endpoints
 		// GET /api/ws/groups/:id - WebSocket connection for group
 		api.GET("/ws/groups/:id", wsHandler.HandleWebSocket)
 		
+		// GET /api/ws/protocol - WebSocket protocol description and event schemas
+		api.GET("/ws/protocol", wsHandler.GetProtocol)
+		
 		// GET /api/ws/rooms/:id/stats - Get room statistics
 		api.GET("/ws/rooms/:id/stats", wsHandler.GetRoomStats)
 		
@@ -138,8 +337,26 @@ func main() {
 		port = "8080"
 	}
 
-	log.Printf("Server starting on port %s", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	server := &http.Server{Addr: ":" + port, Handler: r}
+	go func() {
+		log.Printf("Server starting on port %s", port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	// Stop on SIGINT or SIGTERM, letting requests and running jobs finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	<-ctx.Done()
+	stop()
+
+	log.Println("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server shutdown failed: %v", err)
+	}
+	if err := jobQueue.Stop(shutdownCtx); err != nil {
+		log.Printf("Job queue shutdown failed: %v", err)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"collaborative-bucket-list/migrations"
+	"collaborative-bucket-list/pkg/database"
+)
+
+const migrateUsage = `usage: main migrate <command>
+
+commands:
+  up          apply all pending migrations
+  down [N]    revert the last N applied migrations (default 1)
+  status      list migrations and whether they are applied
+  redo        revert and reapply the last applied migration`
+
+// runMigrateCommand runs a migrate subcommand against the connected database
+func runMigrateCommand(driver string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate command\n%s", migrateUsage)
+	}
+
+	migrator, err := database.NewMigratorForDriver(driver, database.DB, migrations.ForDriver(driver))
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		return migrator.Up(ctx)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid step count %q", args[1])
+			}
+		}
+		return migrator.Down(ctx, steps)
+
+	case "redo":
+		return migrator.Redo(ctx)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printMigrationStatus(statuses)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", args[0], migrateUsage)
+	}
+}
+
+// printMigrationStatus writes the migration status as a table to stdout
+func printMigrationStatus(statuses []database.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tMIGRATION\tSTATUS\tAPPLIED AT\tDOWN")
+
+	for _, status := range statuses {
+		state := "pending"
+		switch {
+		case status.Missing:
+			state = "applied (file missing)"
+		case status.Modified:
+			state = "applied (modified)"
+		case status.Applied:
+			state = "applied"
+		}
+
+		appliedAt := "-"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+		}
+
+		down := "no"
+		if status.HasDown {
+			down = "yes"
+		}
+
+		fmt.Fprintf(w, "%03d\t%s\t%s\t%s\t%s\n", status.Version, status.Filename, state, appliedAt, down)
+	}
+	w.Flush()
+}
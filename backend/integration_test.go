@@ -12,6 +12,7 @@ import (
 	"collaborative-bucket-list/internal/middleware"
 	"collaborative-bucket-list/internal/models"
 	"collaborative-bucket-list/internal/repositories"
+	"collaborative-bucket-list/migrations"
 	"collaborative-bucket-list/pkg/database"
 
 	"github.com/gin-gonic/gin"
@@ -35,7 +36,7 @@ func setupTestServer(t *testing.T) (*gin.Engine, repositories.RepositoryManager)
 	require.NoError(t, err)
 
 	// Run migrations
-	err = database.RunMigrations("migrations")
+	err = database.RunMigrations(migrations.FS)
 	require.NoError(t, err)
 
 	// Initialize repository manager
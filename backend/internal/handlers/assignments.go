@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"collaborative-bucket-list/internal/middleware"
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+	"collaborative-bucket-list/internal/websocket"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AssignmentHandler handles item assignment HTTP requests. Every change is
+// broadcast to the item's group room as item-assigned.
+type AssignmentHandler struct {
+	repos repositories.RepositoryManager
+	hub   websocket.HubInterface
+}
+
+// NewAssignmentHandler creates a new assignment handler
+func NewAssignmentHandler(repos repositories.RepositoryManager, hub websocket.HubInterface) *AssignmentHandler {
+	return &AssignmentHandler{
+		repos: repos,
+		hub:   hub,
+	}
+}
+
+// AssignItem handles POST /api/items/:id/assignees, assigning a member of
+// the item's group to organize it. Any member of the group may assign.
+func (h *AssignmentHandler) AssignItem(c *gin.Context) {
+	item, ok := bindItem(c, h.repos)
+	if !ok {
+		return
+	}
+
+	var req models.AssignItemRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	h.setAssignment(c, item, req.AssigneeID, req.MemberID, true)
+}
+
+// UnassignItem handles DELETE /api/items/:id/assignees/:assigneeId?memberId=,
+// removing a member's assignment to the item
+func (h *AssignmentHandler) UnassignItem(c *gin.Context) {
+	item, ok := bindItem(c, h.repos)
+	if !ok {
+		return
+	}
+
+	assigneeID := c.Param("assigneeId")
+	if validation := models.ValidateUUID(assigneeID); !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_MEMBER_ID",
+				"message": "Invalid member ID format",
+				"details": validation.Errors,
+			},
+		})
+		return
+	}
+
+	h.setAssignment(c, item, assigneeID, c.Query("memberId"), false)
+}
+
+// setAssignment changes an assignment on behalf of a member of the item's
+// group and responds with the updated item
+func (h *AssignmentHandler) setAssignment(c *gin.Context, item *models.BucketListItem, assigneeID, memberID string, assigned bool) {
+	member, ok := requireItemGroupMember(c, h.repos, item, memberID)
+	if !ok {
+		return
+	}
+
+	if _, ok := requireUnlockedGroup(c, h.repos, item.GroupID); !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	var updatedItem *models.BucketListItem
+	err := h.repos.WithTx(ctx, func(txRepos repositories.RepositoryManager) error {
+		if err := txRepos.BucketItems().SetAssignment(ctx, item.ID, assigneeID, assigned); err != nil {
+			return err
+		}
+		var err error
+		updatedItem, err = txRepos.BucketItems().GetByID(ctx, item.ID)
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrMemberNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": gin.H{
+					"code":    "UNKNOWN_ASSIGNEE",
+					"message": "The assignee must be a member of this item's group",
+				},
+			})
+		case errors.Is(err, repositories.ErrItemNotFound):
+			respondItemNotFound(c)
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": gin.H{
+					"code":    "ASSIGNMENT_UPDATE_FAILED",
+					"message": "Failed to update item assignees",
+					"details": err.Error(),
+				},
+			})
+		}
+		return
+	}
+
+	h.hub.BroadcastToRoom(item.GroupID, websocket.EventItemAssigned, websocket.ItemAssignedPayload{
+		Item:       *updatedItem,
+		AssigneeID: assigneeID,
+		Assigned:   assigned,
+		MemberID:   member.ID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"item": updatedItem,
+	})
+}
+
+// GetUserAssignments handles GET /api/users/assignments, returning the items
+// assigned to any of the authenticated user's memberships across their
+// groups, newest first
+func (h *AssignmentHandler) GetUserAssignments(c *gin.Context) {
+	user, ok := middleware.RequireAuth(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	members, err := h.repos.Members().GetByUserID(ctx, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "MEMBERS_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve memberships",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	assignments := []models.AssignedItem{}
+	if len(members) == 0 {
+		c.JSON(http.StatusOK, gin.H{
+			"assignments": assignments,
+		})
+		return
+	}
+
+	// A user has at most one membership per group
+	memberIDs := make([]string, len(members))
+	memberByGroup := make(map[string]string, len(members))
+	for i, member := range members {
+		memberIDs[i] = member.ID
+		memberByGroup[member.GroupID] = member.ID
+	}
+
+	items, err := h.repos.BucketItems().GetAssignedTo(ctx, memberIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "ASSIGNMENTS_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve assigned items",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	groupNames := make(map[string]string)
+	for _, item := range items {
+		name, known := groupNames[item.GroupID]
+		if !known {
+			group, err := h.repos.Groups().GetByID(ctx, item.GroupID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": gin.H{
+						"code":    "GROUP_RETRIEVAL_FAILED",
+						"message": "Failed to retrieve group",
+						"details": err.Error(),
+					},
+				})
+				return
+			}
+			name = group.Name
+			groupNames[item.GroupID] = name
+		}
+
+		assignments = append(assignments, models.AssignedItem{
+			BucketListItem: item,
+			GroupName:      name,
+			MemberID:       memberByGroup[item.GroupID],
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"assignments": assignments,
+	})
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+	"collaborative-bucket-list/internal/websocket"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// assignmentRoutes registers the assignment routes served by handler
+func assignmentRoutes(handler *AssignmentHandler) func(gin.IRoutes) {
+	return func(r gin.IRoutes) {
+		r.POST("/items/:id/assignees", handler.AssignItem)
+		r.DELETE("/items/:id/assignees/:assigneeId", handler.UnassignItem)
+		r.GET("/users/assignments", handler.GetUserAssignments)
+	}
+}
+
+func TestAssignmentHandler_AssignItem(t *testing.T) {
+	groupID := uuid.New().String()
+	itemID := uuid.New().String()
+	memberID := uuid.New().String()
+	assigneeID := uuid.New().String()
+	item := &models.BucketListItem{ID: itemID, GroupID: groupID, Title: "Skydiving", CreatedBy: &memberID}
+	path := fmt.Sprintf("/items/%s/assignees", itemID)
+
+	tests := []struct {
+		name           string
+		body           interface{}
+		setupMocks     func(*MockRepositoryManager)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name: "member assigned",
+			body: models.AssignItemRequest{AssigneeID: assigneeID, MemberID: memberID},
+			setupMocks: func(m *MockRepositoryManager) {
+				assigned := *item
+				assigned.AssigneeIDs = []string{assigneeID}
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil).Once()
+				m.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: groupID}, nil)
+				m.expectOpenGroup(groupID)
+				m.bucketItems.On("SetAssignment", mock.Anything, itemID, assigneeID, true).Return(nil)
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(&assigned, nil).Once()
+				m.expectTx()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "assignee of another group",
+			body: models.AssignItemRequest{AssigneeID: assigneeID, MemberID: memberID},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+				m.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: groupID}, nil)
+				m.expectOpenGroup(groupID)
+				m.bucketItems.On("SetAssignment", mock.Anything, itemID, assigneeID, true).
+					Return(fmt.Errorf("failed to assign item: %w", repositories.ErrMemberNotFound))
+				m.expectTx()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "UNKNOWN_ASSIGNEE",
+		},
+		{
+			name: "member of another group",
+			body: models.AssignItemRequest{AssigneeID: assigneeID, MemberID: memberID},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+				m.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: uuid.New().String()}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "MEMBER_NOT_IN_GROUP",
+		},
+		{
+			name: "locked after the deadline",
+			body: models.AssignItemRequest{AssigneeID: assigneeID, MemberID: memberID},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+				m.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: groupID}, nil)
+				m.groups.On("GetByID", mock.Anything, groupID).Return(lockedGroup(groupID), nil)
+			},
+			expectedStatus: http.StatusLocked,
+			expectedError:  "GROUP_LOCKED",
+		},
+		{
+			name: "malformed assignee ID",
+			body: models.AssignItemRequest{AssigneeID: "someone", MemberID: memberID},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+		{
+			name: "item not found",
+			body: models.AssignItemRequest{AssigneeID: assigneeID, MemberID: memberID},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.bucketItems.On("GetByID", mock.Anything, itemID).
+					Return(nil, fmt.Errorf("%w: %s", repositories.ErrItemNotFound, itemID))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "ITEM_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepos := NewMockRepositoryManager()
+			tt.setupMocks(mockRepos)
+			hub := &recordingHub{}
+
+			w := serveTestRequest(assignmentRoutes(NewAssignmentHandler(mockRepos, hub)), nil, http.MethodPost, path, tt.body)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				assert.Equal(t, tt.expectedError, errorCode(t, w))
+				assert.Empty(t, hub.broadcasts)
+			} else {
+				require.Len(t, hub.broadcasts, 1)
+				assert.Equal(t, groupID, hub.broadcasts[0].roomID)
+				assert.Equal(t, websocket.EventItemAssigned, hub.broadcasts[0].messageType)
+				payload := hub.broadcasts[0].data.(websocket.ItemAssignedPayload)
+				assert.Equal(t, assigneeID, payload.AssigneeID)
+				assert.True(t, payload.Assigned)
+				assert.Equal(t, memberID, payload.MemberID)
+				assert.Equal(t, []string{assigneeID}, payload.Item.AssigneeIDs)
+			}
+			mockRepos.bucketItems.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAssignmentHandler_UnassignItem(t *testing.T) {
+	groupID := uuid.New().String()
+	itemID := uuid.New().String()
+	memberID := uuid.New().String()
+	assigneeID := uuid.New().String()
+	item := &models.BucketListItem{ID: itemID, GroupID: groupID, Title: "Skydiving", AssigneeIDs: []string{assigneeID}}
+
+	t.Run("assignment removed", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		unassigned := *item
+		unassigned.AssigneeIDs = nil
+		mockRepos.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil).Once()
+		mockRepos.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: groupID}, nil)
+		mockRepos.expectOpenGroup(groupID)
+		mockRepos.bucketItems.On("SetAssignment", mock.Anything, itemID, assigneeID, false).Return(nil)
+		mockRepos.bucketItems.On("GetByID", mock.Anything, itemID).Return(&unassigned, nil).Once()
+		mockRepos.expectTx()
+		hub := &recordingHub{}
+
+		path := fmt.Sprintf("/items/%s/assignees/%s?memberId=%s", itemID, assigneeID, memberID)
+		w := serveTestRequest(assignmentRoutes(NewAssignmentHandler(mockRepos, hub)), nil, http.MethodDelete, path, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		require.Len(t, hub.broadcasts, 1)
+		payload := hub.broadcasts[0].data.(websocket.ItemAssignedPayload)
+		assert.False(t, payload.Assigned)
+		assert.Empty(t, payload.Item.AssigneeIDs)
+		mockRepos.bucketItems.AssertExpectations(t)
+	})
+
+	t.Run("locked after the deadline", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+		mockRepos.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: groupID}, nil)
+		mockRepos.groups.On("GetByID", mock.Anything, groupID).Return(lockedGroup(groupID), nil)
+		hub := &recordingHub{}
+
+		path := fmt.Sprintf("/items/%s/assignees/%s?memberId=%s", itemID, assigneeID, memberID)
+		w := serveTestRequest(assignmentRoutes(NewAssignmentHandler(mockRepos, hub)), nil, http.MethodDelete, path, nil)
+		assert.Equal(t, http.StatusLocked, w.Code)
+		assert.Equal(t, "GROUP_LOCKED", errorCode(t, w))
+		assert.Empty(t, hub.broadcasts)
+		mockRepos.bucketItems.AssertNotCalled(t, "SetAssignment", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("malformed assignee ID", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+
+		path := fmt.Sprintf("/items/%s/assignees/someone?memberId=%s", itemID, memberID)
+		w := serveTestRequest(assignmentRoutes(NewAssignmentHandler(mockRepos, &recordingHub{})), nil, http.MethodDelete, path, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_MEMBER_ID", errorCode(t, w))
+	})
+}
+
+func TestAssignmentHandler_GetUserAssignments(t *testing.T) {
+	user := createTestUser()
+	hikers := &models.Group{ID: uuid.New().String(), Name: "Hikers"}
+	cooks := &models.Group{ID: uuid.New().String(), Name: "Cooks"}
+	hikerID, cookID := uuid.New().String(), uuid.New().String()
+	now := time.Now()
+
+	t.Run("items across groups", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.members.On("GetByUserID", mock.Anything, user.ID).Return([]models.Member{
+			{ID: hikerID, GroupID: hikers.ID, UserID: &user.ID},
+			{ID: cookID, GroupID: cooks.ID, UserID: &user.ID},
+		}, nil)
+		mockRepos.bucketItems.On("GetAssignedTo", mock.Anything, []string{hikerID, cookID}).Return([]models.BucketListItem{
+			{ID: "summit", GroupID: hikers.ID, Title: "Summit", AssigneeIDs: []string{hikerID}, CreatedAt: now},
+			{ID: "pasta", GroupID: cooks.ID, Title: "Fresh pasta", AssigneeIDs: []string{cookID}, CreatedAt: now.Add(-time.Hour)},
+			{ID: "trail", GroupID: hikers.ID, Title: "Trail", AssigneeIDs: []string{hikerID}, CreatedAt: now.Add(-2 * time.Hour)},
+		}, nil)
+		mockRepos.groups.On("GetByID", mock.Anything, hikers.ID).Return(hikers, nil).Once()
+		mockRepos.groups.On("GetByID", mock.Anything, cooks.ID).Return(cooks, nil).Once()
+
+		w := serveTestRequest(assignmentRoutes(NewAssignmentHandler(mockRepos, &recordingHub{})), user, http.MethodGet, "/users/assignments", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Assignments []models.AssignedItem `json:"assignments"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Assignments, 3)
+		assert.Equal(t, "summit", response.Assignments[0].ID)
+		assert.Equal(t, "Hikers", response.Assignments[0].GroupName)
+		assert.Equal(t, hikerID, response.Assignments[0].MemberID)
+		assert.Equal(t, "Cooks", response.Assignments[1].GroupName)
+		assert.Equal(t, cookID, response.Assignments[1].MemberID)
+		assert.Equal(t, "Hikers", response.Assignments[2].GroupName)
+
+		// Each group is looked up once
+		mockRepos.groups.AssertExpectations(t)
+	})
+
+	t.Run("no memberships", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.members.On("GetByUserID", mock.Anything, user.ID).Return([]models.Member{}, nil)
+
+		w := serveTestRequest(assignmentRoutes(NewAssignmentHandler(mockRepos, &recordingHub{})), user, http.MethodGet, "/users/assignments", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"assignments":[]}`, w.Body.String())
+		mockRepos.bucketItems.AssertNotCalled(t, "GetAssignedTo", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		w := serveTestRequest(assignmentRoutes(NewAssignmentHandler(mockRepos, &recordingHub{})), nil, http.MethodGet, "/users/assignments", nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
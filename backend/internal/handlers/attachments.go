@@ -0,0 +1,361 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+	"collaborative-bucket-list/pkg/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxAttachmentRequestSize bounds upload request bodies, leaving room for
+// the multipart headers and the other form fields next to the file
+const maxAttachmentRequestSize = models.MaxAttachmentSize + 1<<20
+
+// AttachmentHandler handles item attachment HTTP requests. Files are kept
+// in a blob store and handed out through signed URLs that expire after
+// urlExpiry.
+type AttachmentHandler struct {
+	repos     repositories.RepositoryManager
+	blobs     storage.BlobStore
+	urlExpiry time.Duration
+}
+
+// NewAttachmentHandler creates a new attachment handler
+func NewAttachmentHandler(repos repositories.RepositoryManager, blobs storage.BlobStore, urlExpiry time.Duration) *AttachmentHandler {
+	return &AttachmentHandler{
+		repos:     repos,
+		blobs:     blobs,
+		urlExpiry: urlExpiry,
+	}
+}
+
+// GetItemAttachments handles GET /api/items/:id/attachments, returning the
+// item's attachments oldest first with signed download URLs
+func (h *AttachmentHandler) GetItemAttachments(c *gin.Context) {
+	item, ok := bindItem(c, h.repos)
+	if !ok {
+		return
+	}
+
+	attachments, err := h.repos.Attachments().GetByItemID(c.Request.Context(), item.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "ATTACHMENTS_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve attachments",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	if attachments == nil {
+		attachments = []models.Attachment{}
+	}
+	for i := range attachments {
+		if !h.signURL(c, &attachments[i]) {
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"attachments": attachments,
+	})
+}
+
+// UploadAttachment handles POST /api/items/:id/attachments, a multipart
+// form with the uploading member's memberId and the file. The file's type
+// is sniffed from its content rather than trusted from the client.
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	item, ok := bindItem(c, h.repos)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxAttachmentRequestSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondFileTooLarge(c)
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_REQUEST_BODY",
+				"message": "Request must be a multipart form with a file",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	if fileHeader.Size > models.MaxAttachmentSize {
+		respondFileTooLarge(c)
+		return
+	}
+	if validation := models.ValidateAttachmentSize(fileHeader.Size); !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Request validation failed",
+				"details": validation.Errors,
+			},
+		})
+		return
+	}
+
+	member, ok := requireItemGroupMember(c, h.repos, item, c.PostForm("memberId"))
+	if !ok {
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_REQUEST_BODY",
+				"message": "Failed to read the uploaded file",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+	defer file.Close()
+
+	contentType, err := sniffContentType(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_REQUEST_BODY",
+				"message": "Failed to read the uploaded file",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+	if validation := models.ValidateAttachmentContentType(contentType); !validation.IsValid {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error": gin.H{
+				"code":    "UNSUPPORTED_FILE_TYPE",
+				"message": "Unsupported file type",
+				"details": validation.Errors,
+			},
+		})
+		return
+	}
+
+	attachmentID := uuid.New().String()
+	attachment := &models.Attachment{
+		ID:          attachmentID,
+		ItemID:      item.ID,
+		MemberID:    member.ID,
+		FileName:    fileHeader.Filename,
+		ContentType: contentType,
+		Size:        fileHeader.Size,
+		StorageKey:  models.AttachmentStorageKey(item.GroupID, item.ID, attachmentID),
+		CreatedAt:   time.Now(),
+	}
+
+	ctx := c.Request.Context()
+	if err := h.blobs.Put(ctx, attachment.StorageKey, file, attachment.Size, contentType); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "ATTACHMENT_UPLOAD_FAILED",
+				"message": "Failed to store the uploaded file",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.repos.Attachments().Create(ctx, attachment); err != nil {
+		// Nothing refers to the blob yet, so it can go right away
+		h.blobs.Delete(ctx, attachment.StorageKey)
+
+		switch {
+		case errors.Is(err, repositories.ErrItemNotFound):
+			respondItemNotFound(c)
+		case errors.Is(err, repositories.ErrMemberNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": gin.H{
+					"code":    "MEMBER_NOT_FOUND",
+					"message": "Member not found",
+				},
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": gin.H{
+					"code":    "ATTACHMENT_CREATION_FAILED",
+					"message": "Failed to create attachment",
+					"details": err.Error(),
+				},
+			})
+		}
+		return
+	}
+
+	if !h.signURL(c, attachment) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"attachment": attachment,
+	})
+}
+
+// DeleteAttachment handles DELETE /api/attachments/:id?memberId=. The
+// uploader and the group's creator can delete an attachment; its blob is
+// removed by the blob cleaner.
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	attachmentID := c.Param("id")
+
+	// Validate UUID format
+	validation := models.ValidateUUID(attachmentID)
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_ATTACHMENT_ID",
+				"message": "Invalid attachment ID format",
+				"details": validation.Errors,
+			},
+		})
+		return
+	}
+
+	attachment, err := h.repos.Attachments().GetByID(c.Request.Context(), attachmentID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrAttachmentNotFound) {
+			respondAttachmentNotFound(c)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "ATTACHMENT_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve attachment",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	member, ok := getMember(c, h.repos, c.Query("memberId"))
+	if !ok {
+		return
+	}
+
+	isModerator := member.IsCreator && member.GroupID == attachment.GroupID
+	if member.ID != attachment.MemberID && !isModerator {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": gin.H{
+				"code":    "NOT_ATTACHMENT_UPLOADER",
+				"message": "Only the attachment's uploader or the group's creator can delete it",
+			},
+		})
+		return
+	}
+
+	if err := h.repos.Attachments().Delete(c.Request.Context(), attachment.ID); err != nil {
+		if errors.Is(err, repositories.ErrAttachmentNotFound) {
+			respondAttachmentNotFound(c)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "ATTACHMENT_DELETION_FAILED",
+				"message": "Failed to delete attachment",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// signURL sets the signed download URLs of an attachment and its
+// thumbnails, writing a 500 response if it cannot
+func (h *AttachmentHandler) signURL(c *gin.Context, attachment *models.Attachment) bool {
+	if err := signAttachment(c.Request.Context(), h.blobs, h.urlExpiry, attachment); err != nil {
+		respondAttachmentURLFailed(c, err)
+		return false
+	}
+	return true
+}
+
+// signAttachment sets the signed download URL of an attachment and lists
+// its thumbnails with theirs
+func signAttachment(ctx context.Context, blobs storage.BlobStore, expiry time.Duration, attachment *models.Attachment) error {
+	url, err := blobs.SignedURL(ctx, attachment.StorageKey, expiry)
+	if err != nil {
+		return err
+	}
+	attachment.URL = url
+
+	attachment.Thumbnails = attachment.ThumbnailVariants()
+	for i := range attachment.Thumbnails {
+		thumbnail := &attachment.Thumbnails[i]
+		if thumbnail.URL, err = blobs.SignedURL(ctx, thumbnail.StorageKey, expiry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func respondAttachmentURLFailed(c *gin.Context, err error) {
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": gin.H{
+			"code":    "ATTACHMENT_URL_FAILED",
+			"message": "Failed to create a download URL",
+			"details": err.Error(),
+		},
+	})
+}
+
+// sniffContentType detects the media type of a file from its first bytes
+// and rewinds it
+func sniffContentType(file io.ReadSeeker) (string, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	// Drop parameters such as "; charset=utf-8"
+	contentType, _, _ := strings.Cut(http.DetectContentType(head[:n]), ";")
+	return contentType, nil
+}
+
+func respondFileTooLarge(c *gin.Context) {
+	c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+		"error": gin.H{
+			"code":    "FILE_TOO_LARGE",
+			"message": "File is too large",
+			"details": models.ValidateAttachmentSize(models.MaxAttachmentSize + 1).Errors,
+		},
+	})
+}
+
+func respondAttachmentNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, gin.H{
+		"error": gin.H{
+			"code":    "ATTACHMENT_NOT_FOUND",
+			"message": "Attachment not found",
+		},
+	})
+}
@@ -0,0 +1,379 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+	"collaborative-bucket-list/pkg/storage"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// pngContent starts with the PNG signature, which is all content sniffing
+// looks at
+var pngContent = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 64)...)
+
+// newTestBlobStore creates a local blob store in a temporary directory
+func newTestBlobStore(t *testing.T) (*storage.LocalStore, string) {
+	dir := t.TempDir()
+	store, err := storage.NewLocalStore(dir, "http://localhost:8080/blobs", []byte("test-secret"))
+	require.NoError(t, err)
+	return store, dir
+}
+
+// storedFiles lists the files in a blob store directory
+func storedFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(dir, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	require.NoError(t, err)
+	return files
+}
+
+func serveAttachmentRequest(handler *AttachmentHandler, req *http.Request) *httptest.ResponseRecorder {
+	router := setupTestRouter()
+	router.GET("/items/:id/attachments", handler.GetItemAttachments)
+	router.POST("/items/:id/attachments", handler.UploadAttachment)
+	router.DELETE("/attachments/:id", handler.DeleteAttachment)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// newUploadRequest builds a multipart upload of content as fileName
+func newUploadRequest(t *testing.T, itemID, memberID, fileName string, content []byte) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	require.NoError(t, writer.WriteField("memberId", memberID))
+	part, err := writer.CreateFormFile("file", fileName)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/items/%s/attachments", itemID), &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestAttachmentHandler_UploadAttachment(t *testing.T) {
+	groupID := uuid.New().String()
+	itemID := uuid.New().String()
+	memberID := uuid.New().String()
+	item := &models.BucketListItem{ID: itemID, GroupID: groupID}
+	member := &models.Member{ID: memberID, GroupID: groupID, Name: "Alice"}
+
+	t.Run("stores the file and returns a signed URL", func(t *testing.T) {
+		blobs, dir := newTestBlobStore(t)
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+		mockRepos.members.On("GetByID", mock.Anything, memberID).Return(member, nil)
+		mockRepos.attachments.On("Create", mock.Anything, mock.MatchedBy(func(a *models.Attachment) bool {
+			return a.ItemID == itemID && a.MemberID == memberID && a.ContentType == "image/png" &&
+				a.Size == int64(len(pngContent)) && a.FileName == "beach.png" &&
+				a.StorageKey == models.AttachmentStorageKey(groupID, itemID, a.ID)
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Attachment).GroupID = groupID
+		}).Return(nil)
+
+		// The client's claimed type is ignored in favor of the content
+		w := serveAttachmentRequest(NewAttachmentHandler(mockRepos, blobs, time.Minute),
+			newUploadRequest(t, itemID, memberID, "beach.png", pngContent))
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var response struct {
+			Attachment map[string]interface{} `json:"attachment"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "image/png", response.Attachment["contentType"])
+		assert.Equal(t, groupID, response.Attachment["groupId"])
+		assert.Contains(t, response.Attachment["url"], "signature=")
+		assert.NotContains(t, response.Attachment, "storageKey", "storage keys stay internal")
+
+		attachmentID := response.Attachment["id"].(string)
+		assert.Equal(t, []string{models.AttachmentStorageKey(groupID, itemID, attachmentID)}, storedFiles(t, dir))
+		mockRepos.attachments.AssertExpectations(t)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		blobs, dir := newTestBlobStore(t)
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+		mockRepos.members.On("GetByID", mock.Anything, memberID).Return(member, nil)
+
+		w := serveAttachmentRequest(NewAttachmentHandler(mockRepos, blobs, time.Minute),
+			newUploadRequest(t, itemID, memberID, "photo.png", []byte("<html><script>alert(1)</script></html>")))
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+		assert.Equal(t, "UNSUPPORTED_FILE_TYPE", errorCode(t, w))
+		assert.Empty(t, storedFiles(t, dir))
+		mockRepos.attachments.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		blobs, dir := newTestBlobStore(t)
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+
+		content := append(append([]byte{}, pngContent...), make([]byte, models.MaxAttachmentSize)...)
+		w := serveAttachmentRequest(NewAttachmentHandler(mockRepos, blobs, time.Minute),
+			newUploadRequest(t, itemID, memberID, "huge.png", content))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Equal(t, "FILE_TOO_LARGE", errorCode(t, w))
+		assert.Empty(t, storedFiles(t, dir))
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		blobs, _ := newTestBlobStore(t)
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+
+		w := serveAttachmentRequest(NewAttachmentHandler(mockRepos, blobs, time.Minute),
+			newUploadRequest(t, itemID, memberID, "empty.png", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "VALIDATION_ERROR", errorCode(t, w))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		blobs, _ := newTestBlobStore(t)
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/items/%s/attachments", itemID),
+			strings.NewReader(`{"memberId":"`+memberID+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := serveAttachmentRequest(NewAttachmentHandler(mockRepos, blobs, time.Minute), req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_REQUEST_BODY", errorCode(t, w))
+	})
+
+	t.Run("member of another group", func(t *testing.T) {
+		blobs, dir := newTestBlobStore(t)
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+		mockRepos.members.On("GetByID", mock.Anything, memberID).Return(
+			&models.Member{ID: memberID, GroupID: uuid.New().String()}, nil)
+
+		w := serveAttachmentRequest(NewAttachmentHandler(mockRepos, blobs, time.Minute),
+			newUploadRequest(t, itemID, memberID, "beach.png", pngContent))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "MEMBER_NOT_IN_GROUP", errorCode(t, w))
+		assert.Empty(t, storedFiles(t, dir))
+	})
+
+	t.Run("failed insert removes the blob", func(t *testing.T) {
+		blobs, dir := newTestBlobStore(t)
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+		mockRepos.members.On("GetByID", mock.Anything, memberID).Return(member, nil)
+		mockRepos.attachments.On("Create", mock.Anything, mock.Anything).Return(
+			fmt.Errorf("failed to create attachment: %w", repositories.ErrItemNotFound))
+
+		w := serveAttachmentRequest(NewAttachmentHandler(mockRepos, blobs, time.Minute),
+			newUploadRequest(t, itemID, memberID, "beach.png", pngContent))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "ITEM_NOT_FOUND", errorCode(t, w))
+		assert.Empty(t, storedFiles(t, dir))
+	})
+}
+
+func TestAttachmentHandler_GetItemAttachments(t *testing.T) {
+	groupID := uuid.New().String()
+	itemID := uuid.New().String()
+	attachmentID := uuid.New().String()
+	storageKey := models.AttachmentStorageKey(groupID, itemID, attachmentID)
+
+	blobs, _ := newTestBlobStore(t)
+	mockRepos := NewMockRepositoryManager()
+	mockRepos.bucketItems.On("GetByID", mock.Anything, itemID).Return(&models.BucketListItem{ID: itemID, GroupID: groupID}, nil)
+	width, height := 800, 600
+	mockRepos.attachments.On("GetByItemID", mock.Anything, itemID).Return([]models.Attachment{
+		{ID: attachmentID, ItemID: itemID, GroupID: groupID, FileName: "beach.png", ContentType: "image/png", StorageKey: storageKey,
+			Width: &width, Height: &height, ProcessingStatus: models.AttachmentProcessed},
+		{ID: uuid.New().String(), ItemID: itemID, GroupID: groupID, FileName: "new.png", ContentType: "image/png", StorageKey: storageKey + "-new",
+			ProcessingStatus: models.AttachmentPending},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/items/%s/attachments", itemID), nil)
+	w := serveAttachmentRequest(NewAttachmentHandler(mockRepos, blobs, time.Minute), req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Attachments []models.Attachment `json:"attachments"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Attachments, 2)
+	assert.True(t, strings.HasPrefix(response.Attachments[0].URL, "http://localhost:8080/blobs/"+storageKey+"?"))
+
+	// Processed images list their thumbnails with signed URLs
+	thumbnails := response.Attachments[0].Thumbnails
+	require.Len(t, thumbnails, len(models.ThumbnailSizes))
+	assert.Equal(t, "small", thumbnails[0].Size)
+	assert.Equal(t, 160, thumbnails[0].Width)
+	assert.Equal(t, 120, thumbnails[0].Height)
+	assert.True(t, strings.HasPrefix(thumbnails[0].URL, "http://localhost:8080/blobs/"+storageKey+"-small?"))
+	assert.Empty(t, response.Attachments[1].Thumbnails, "pending images have none yet")
+	assert.NotEmpty(t, response.Attachments[1].URL)
+
+	t.Run("no attachments is an empty list", func(t *testing.T) {
+		otherItemID := uuid.New().String()
+		mockRepos.bucketItems.On("GetByID", mock.Anything, otherItemID).Return(&models.BucketListItem{ID: otherItemID, GroupID: groupID}, nil)
+		mockRepos.attachments.On("GetByItemID", mock.Anything, otherItemID).Return([]models.Attachment(nil), nil)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/items/%s/attachments", otherItemID), nil)
+		w := serveAttachmentRequest(NewAttachmentHandler(mockRepos, blobs, time.Minute), req)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"attachments":[]}`, w.Body.String())
+	})
+}
+
+func TestGroupHandler_GetGroupSignsAttachments(t *testing.T) {
+	groupID := uuid.New().String()
+	itemID := uuid.New().String()
+	attachmentID := uuid.New().String()
+	storageKey := models.AttachmentStorageKey(groupID, itemID, attachmentID)
+	width, height := 2000, 1000
+
+	details := &models.GroupWithDetails{
+		Group: models.Group{ID: groupID, Name: "Trip"},
+		Items: []models.BucketListItem{{ID: itemID, GroupID: groupID, Title: "Beach"}},
+		Attachments: map[string][]models.Attachment{
+			itemID: {{ID: attachmentID, ItemID: itemID, GroupID: groupID, ContentType: "image/jpeg", StorageKey: storageKey,
+				Width: &width, Height: &height, ProcessingStatus: models.AttachmentProcessed}},
+		},
+	}
+
+	serve := func(handler *GroupHandler) models.GroupWithDetails {
+		t.Helper()
+		router := setupTestRouter()
+		router.GET("/groups/:id", handler.GetGroup)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/groups/"+groupID, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response models.GroupWithDetails
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	blobs, _ := newTestBlobStore(t)
+	mockRepos := NewMockRepositoryManager()
+	mockRepos.groups.On("GetWithDetails", mock.Anything, groupID).Return(details, nil)
+
+	response := serve(NewGroupHandlerWithBlobStore(mockRepos, blobs, time.Minute))
+	require.Len(t, response.Attachments[itemID], 1)
+	attachment := response.Attachments[itemID][0]
+	assert.True(t, strings.HasPrefix(attachment.URL, "http://localhost:8080/blobs/"+storageKey+"?"))
+	require.Len(t, attachment.Thumbnails, len(models.ThumbnailSizes))
+	large := attachment.Thumbnails[2]
+	assert.Equal(t, "large", large.Size)
+	assert.Equal(t, 1280, large.Width)
+	assert.Equal(t, 640, large.Height)
+	assert.True(t, strings.HasPrefix(large.URL, "http://localhost:8080/blobs/"+storageKey+"-large?"))
+
+	// Without a blob store attachments are listed without URLs
+	details.Attachments[itemID][0].URL = ""
+	details.Attachments[itemID][0].Thumbnails = nil
+	response = serve(NewGroupHandler(mockRepos))
+	require.Len(t, response.Attachments[itemID], 1)
+	assert.Empty(t, response.Attachments[itemID][0].URL)
+	assert.Empty(t, response.Attachments[itemID][0].Thumbnails)
+}
+
+func TestAttachmentHandler_DeleteAttachment(t *testing.T) {
+	groupID := uuid.New().String()
+	uploaderID := uuid.New().String()
+	attachment := &models.Attachment{ID: uuid.New().String(), GroupID: groupID, MemberID: uploaderID}
+	path := func(memberID string) string {
+		return fmt.Sprintf("/attachments/%s?memberId=%s", attachment.ID, memberID)
+	}
+
+	tests := []struct {
+		name     string
+		member   *models.Member
+		expected int
+	}{
+		{"uploader", &models.Member{ID: uploaderID, GroupID: groupID}, http.StatusNoContent},
+		{"group creator", &models.Member{ID: uuid.New().String(), GroupID: groupID, IsCreator: true}, http.StatusNoContent},
+		{"other member", &models.Member{ID: uuid.New().String(), GroupID: groupID}, http.StatusForbidden},
+		{"creator of another group", &models.Member{ID: uuid.New().String(), GroupID: uuid.New().String(), IsCreator: true}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blobs, _ := newTestBlobStore(t)
+			mockRepos := NewMockRepositoryManager()
+			mockRepos.attachments.On("GetByID", mock.Anything, attachment.ID).Return(attachment, nil)
+			mockRepos.members.On("GetByID", mock.Anything, tt.member.ID).Return(tt.member, nil)
+			mockRepos.attachments.On("Delete", mock.Anything, attachment.ID).Return(nil)
+
+			req := httptest.NewRequest(http.MethodDelete, path(tt.member.ID), nil)
+			w := serveAttachmentRequest(NewAttachmentHandler(mockRepos, blobs, time.Minute), req)
+			assert.Equal(t, tt.expected, w.Code)
+			if tt.expected == http.StatusNoContent {
+				mockRepos.attachments.AssertCalled(t, "Delete", mock.Anything, attachment.ID)
+			} else {
+				mockRepos.attachments.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+			}
+		})
+	}
+
+	t.Run("unknown attachment", func(t *testing.T) {
+		blobs, _ := newTestBlobStore(t)
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.attachments.On("GetByID", mock.Anything, attachment.ID).Return(nil,
+			fmt.Errorf("%w: %s", repositories.ErrAttachmentNotFound, attachment.ID))
+
+		req := httptest.NewRequest(http.MethodDelete, path(uploaderID), nil)
+		w := serveAttachmentRequest(NewAttachmentHandler(mockRepos, blobs, time.Minute), req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "ATTACHMENT_NOT_FOUND", errorCode(t, w))
+	})
+}
+
+func TestSniffContentType(t *testing.T) {
+	file := bytes.NewReader(pngContent)
+	contentType, err := sniffContentType(file)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+
+	// The file is rewound for the upload
+	rest, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.Equal(t, pngContent, rest)
+
+	contentType, err = sniffContentType(strings.NewReader("plain text"))
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain", contentType, "parameters are dropped")
+
+	_, err = sniffContentType(failingReader{})
+	assert.Error(t, err)
+}
+
+// failingReader is a file that cannot be read
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error)       { return 0, errors.New("disk error") }
+func (failingReader) Seek(int64, int) (int64, error) { return 0, nil }
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"collaborative-bucket-list/internal/models"
@@ -89,26 +92,9 @@ func (h *BucketItemHandler) CreateItem(c *gin.Context) {
 		return
 	}
 
-	// Check if group exists
-	_, err := h.repos.Groups().GetByID(c.Request.Context(), groupID)
-	if err != nil {
-		if err.Error() == fmt.Sprintf("group not found: %s", groupID) {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":    "GROUP_NOT_FOUND",
-					"message": "Group not found",
-				},
-			})
-			return
-		}
-
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":    "GROUP_RETRIEVAL_FAILED",
-				"message": "Failed to retrieve group",
-				"details": err.Error(),
-			},
-		})
+	// Check if group exists; nothing can be added once its list has locked
+	group, ok := requireUnlockedGroup(c, h.repos, groupID)
+	if !ok {
 		return
 	}
 
@@ -146,17 +132,30 @@ func (h *BucketItemHandler) CreateItem(c *gin.Context) {
 		return
 	}
 
+	// The item must be scheduled before the group's deadline
+	if validation := req.ItemSchedule.Validate(group.Deadline); !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Request validation failed",
+				"details": validation.Errors,
+			},
+		})
+		return
+	}
+
 	// Create bucket list item
 	item := &models.BucketListItem{
-		ID:          uuid.New().String(),
-		GroupID:     groupID,
-		Title:       req.Title,
-		Description: req.Description,
-		Completed:   false,
-		CompletedBy: nil,
-		CompletedAt: nil,
-		CreatedBy:   req.MemberID,
-		CreatedAt:   time.Now(),
+		ID:           uuid.New().String(),
+		GroupID:      groupID,
+		Title:        req.Title,
+		Description:  req.Description,
+		Completed:    false,
+		CompletedBy:  nil,
+		CompletedAt:  nil,
+		ItemSchedule: req.ItemSchedule,
+		CreatedBy:    &req.MemberID,
+		CreatedAt:    time.Now(),
 	}
 
 	// Save item to database
@@ -298,8 +297,42 @@ func (h *BucketItemHandler) ToggleCompletion(c *gin.Context) {
 		return
 	}
 
-	// Toggle completion status
-	if err := h.repos.BucketItems().ToggleCompletion(c.Request.Context(), itemID, req.MemberID, req.Completed); err != nil {
+	// Items cannot be checked off or reopened once the list has locked
+	group, ok := requireUnlockedGroup(c, h.repos, item.GroupID)
+	if !ok {
+		return
+	}
+
+	// The item cannot have happened after today in the group's timezone
+	if validation := req.CompletionJournal.ValidateOccurredOn(time.Now(), group.Location()); !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Request validation failed",
+				"details": validation.Errors,
+			},
+		})
+		return
+	}
+
+	// Toggle completion status, recording the journal given with it
+	ctx := c.Request.Context()
+	req.CompletionJournal.Sanitize()
+	toggle := func(repos repositories.RepositoryManager) error {
+		if err := repos.BucketItems().ToggleCompletion(ctx, itemID, req.MemberID, req.Completed); err != nil {
+			return err
+		}
+		if req.CompletionJournal.IsEmpty() {
+			return nil
+		}
+		return repos.BucketItems().UpdateCompletionJournal(ctx, itemID, req.CompletionJournal)
+	}
+	if req.CompletionJournal.IsEmpty() {
+		err = toggle(h.repos)
+	} else {
+		err = h.repos.WithTx(ctx, toggle)
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": gin.H{
 				"code":    "COMPLETION_TOGGLE_FAILED",
@@ -327,4 +360,178 @@ func (h *BucketItemHandler) ToggleCompletion(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"item": updatedItem,
 	})
-}
\ No newline at end of file
+}
+// ListItems handles GET /api/groups/:id/items, returning a page of items
+// filtered by completed, createdBy, tag, assignedTo, createdAfter and
+// createdBefore and ordered by sort. Pass the nextCursor of a page as cursor to get the next.
+func (h *BucketItemHandler) ListItems(c *gin.Context) {
+	groupID := c.Param("id")
+
+	// Validate UUID format
+	validation := models.ValidateUUID(groupID)
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_GROUP_ID",
+				"message": "Invalid group ID format",
+				"details": validation.Errors,
+			},
+		})
+		return
+	}
+
+	opts := models.ItemListOptions{
+		CreatedBy:  c.Query("createdBy"),
+		TagID:      c.Query("tag"),
+		AssignedTo: c.Query("assignedTo"),
+		Sort:       models.ItemSort(c.Query("sort")),
+		Cursor:     c.Query("cursor"),
+	}
+	var params queryParams
+	opts.Completed = params.boolean(c, "completed")
+	opts.CreatedAfter = params.time(c, "createdAfter")
+	opts.CreatedBefore = params.time(c, "createdBefore")
+	opts.Limit = params.limit(c)
+	if !params.validate(c, opts.Validate()) {
+		return
+	}
+
+	if !h.requireGroup(c, groupID) {
+		return
+	}
+
+	page, err := h.repos.BucketItems().List(c.Request.Context(), groupID, opts)
+	if err != nil {
+		if errors.Is(err, repositories.ErrInvalidCursor) {
+			respondInvalidCursor(c)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "ITEMS_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve bucket list items",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// requireGroup checks that a group exists, writing a 404 or 500 response
+// if it does not
+func (h *BucketItemHandler) requireGroup(c *gin.Context, groupID string) bool {
+	_, ok := h.getGroup(c, groupID)
+	return ok
+}
+
+// getGroup looks up a group, writing a 404 or 500 response if it cannot
+func (h *BucketItemHandler) getGroup(c *gin.Context, groupID string) (*models.Group, bool) {
+	group, err := h.repos.Groups().GetByID(c.Request.Context(), groupID)
+	if err == nil {
+		return group, true
+	}
+
+	if errors.Is(err, repositories.ErrGroupNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": gin.H{
+				"code":    "GROUP_NOT_FOUND",
+				"message": "Group not found",
+			},
+		})
+		return nil, false
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": gin.H{
+			"code":    "GROUP_RETRIEVAL_FAILED",
+			"message": "Failed to retrieve group",
+			"details": err.Error(),
+		},
+	})
+	return nil, false
+}
+
+// SearchItems handles GET /api/groups/:id/items/search?q=
+func (h *BucketItemHandler) SearchItems(c *gin.Context) {
+	groupID := c.Param("id")
+
+	// Validate UUID format
+	validation := models.ValidateUUID(groupID)
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_GROUP_ID",
+				"message": "Invalid group ID format",
+				"details": validation.Errors,
+			},
+		})
+		return
+	}
+
+	query, limit, ok := bindSearchParams(c)
+	if !ok {
+		return
+	}
+
+	if !h.requireGroup(c, groupID) {
+		return
+	}
+
+	results, err := h.repos.BucketItems().Search(c.Request.Context(), groupID, query, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "SEARCH_FAILED",
+				"message": "Failed to search bucket list items",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	if results == nil {
+		results = []models.ItemSearchResult{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query": query,
+		"items": results,
+	})
+}
+
+// bindSearchParams reads and validates the q and limit query parameters,
+// writing a 400 response if they are invalid
+func bindSearchParams(c *gin.Context) (query string, limit int, ok bool) {
+	query = strings.TrimSpace(c.Query("q"))
+	validation := models.ValidateSearchQuery(query)
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_SEARCH_QUERY",
+				"message": "Invalid search query",
+				"details": validation.Errors,
+			},
+		})
+		return "", 0, false
+	}
+
+	limit = models.DefaultSearchLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > models.MaxSearchLimit {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": gin.H{
+					"code":    "INVALID_LIMIT",
+					"message": fmt.Sprintf("limit must be between 1 and %d", models.MaxSearchLimit),
+				},
+			})
+			return "", 0, false
+		}
+		limit = parsed
+	}
+
+	return query, limit, true
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -142,7 +143,7 @@ func TestBucketItemHandler_CreateItem(t *testing.T) {
 		mockRepoManager := NewMockRepositoryManager()
 		groupID := uuid.New().String()
 		
-		mockRepoManager.groups.On("GetByID", mock.Anything, groupID).Return(nil, fmt.Errorf("group not found: %s", groupID))
+		mockRepoManager.groups.On("GetByID", mock.Anything, groupID).Return(nil, fmt.Errorf("%w: %s", repositories.ErrGroupNotFound, groupID))
 		
 		handler := NewBucketItemHandler(mockRepoManager)
 		
@@ -308,6 +309,7 @@ func TestBucketItemHandler_ToggleCompletion(t *testing.T) {
 		
 		mockRepoManager.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil).Once()
 		mockRepoManager.members.On("GetByID", mock.Anything, memberID).Return(member, nil)
+		mockRepoManager.groups.On("GetByID", mock.Anything, groupID).Return(&models.Group{ID: groupID, Name: "Test Group"}, nil)
 		mockRepoManager.bucketItems.On("ToggleCompletion", mock.Anything, itemID, memberID, true).Return(nil)
 		mockRepoManager.bucketItems.On("GetByID", mock.Anything, itemID).Return(updatedItem, nil).Once()
 		
@@ -457,6 +459,73 @@ func TestBucketItemHandler_ToggleCompletion(t *testing.T) {
 	})
 }
 
+func TestBucketItemHandler_LockedAfterDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	groupID := uuid.New().String()
+	memberID := uuid.New().String()
+	itemID := uuid.New().String()
+	passed := time.Now().Add(-time.Hour)
+	member := &models.Member{ID: memberID, GroupID: groupID, Name: "Test Member"}
+	item := &models.BucketListItem{ID: itemID, GroupID: groupID, Title: "Visit Paris", CreatedBy: &memberID}
+
+	serve := func(handler gin.HandlerFunc, method, path, id string, body interface{}) *httptest.ResponseRecorder {
+		encoded, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(method, path, bytes.NewBuffer(encoded))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		handler(c)
+		return w
+	}
+
+	t.Run("adding an item", func(t *testing.T) {
+		mockRepoManager := NewMockRepositoryManager()
+		group := &models.Group{ID: groupID, Name: "Test Group", Deadline: &passed, LockAfterDeadline: true}
+		mockRepoManager.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+		mockRepoManager.members.On("GetByID", mock.Anything, memberID).Return(member, nil)
+
+		handler := NewBucketItemHandler(mockRepoManager)
+		w := serve(handler.CreateItem, http.MethodPost, "/api/groups/"+groupID+"/items", groupID,
+			models.CreateItemRequest{Title: "Too late", MemberID: memberID})
+
+		assert.Equal(t, http.StatusLocked, w.Code)
+		assert.Equal(t, "GROUP_LOCKED", errorCode(t, w))
+		mockRepoManager.bucketItems.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("completing an item", func(t *testing.T) {
+		mockRepoManager := NewMockRepositoryManager()
+		group := &models.Group{ID: groupID, Name: "Test Group", Deadline: &passed, LockAfterDeadline: true}
+		mockRepoManager.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+		mockRepoManager.members.On("GetByID", mock.Anything, memberID).Return(member, nil)
+		mockRepoManager.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+
+		handler := NewBucketItemHandler(mockRepoManager)
+		w := serve(handler.ToggleCompletion, http.MethodPatch, "/api/items/"+itemID+"/complete", itemID,
+			models.ToggleCompletionRequest{Completed: true, MemberID: memberID})
+
+		assert.Equal(t, http.StatusLocked, w.Code)
+		assert.Equal(t, "GROUP_LOCKED", errorCode(t, w))
+		mockRepoManager.bucketItems.AssertNotCalled(t, "ToggleCompletion", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("groups that do not lock stay open", func(t *testing.T) {
+		mockRepoManager := NewMockRepositoryManager()
+		group := &models.Group{ID: groupID, Name: "Test Group", Deadline: &passed}
+		mockRepoManager.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+		mockRepoManager.members.On("GetByID", mock.Anything, memberID).Return(member, nil)
+		mockRepoManager.bucketItems.On("Create", mock.Anything, mock.AnythingOfType("*models.BucketListItem")).Return(nil)
+
+		handler := NewBucketItemHandler(mockRepoManager)
+		w := serve(handler.CreateItem, http.MethodPost, "/api/groups/"+groupID+"/items", groupID,
+			models.CreateItemRequest{Title: "Still welcome", MemberID: memberID})
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockRepoManager.bucketItems.AssertExpectations(t)
+	})
+}
+
 // Helper functions
 func stringPtr(s string) *string {
 	return &s
@@ -464,4 +533,218 @@ func stringPtr(s string) *string {
 
 func timePtr(t time.Time) *time.Time {
 	return &t
-}
\ No newline at end of file
+}
+
+func TestBucketItemHandler_ListItems(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	groupID := uuid.New().String()
+	memberID := uuid.New().String()
+	tagID := uuid.New().String()
+	group := &models.Group{ID: groupID, Name: "Test Group", CreatedAt: time.Now()}
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMocks     func(*MockRepositoryManager)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:  "default options",
+			query: "",
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+				m.bucketItems.On("List", mock.Anything, groupID, models.ItemListOptions{}).
+					Return(&models.ItemPage{Items: []models.BucketListItem{}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "filters, sort and cursor",
+			query: "?completed=false&createdBy=" + memberID + "&createdAfter=2025-01-01&sort=title&cursor=abc&limit=2",
+			setupMocks: func(m *MockRepositoryManager) {
+				completed := false
+				createdAfter := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+				opts := models.ItemListOptions{
+					Completed:    &completed,
+					CreatedBy:    memberID,
+					CreatedAfter: &createdAfter,
+					Sort:         models.ItemSortTitle,
+					Cursor:       "abc",
+					Limit:        2,
+				}
+				m.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+				m.bucketItems.On("List", mock.Anything, groupID, opts).
+					Return(&models.ItemPage{Items: []models.BucketListItem{}, NextCursor: "def"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "tag filter",
+			query: "?tag=" + tagID,
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+				m.bucketItems.On("List", mock.Anything, groupID, models.ItemListOptions{TagID: tagID}).
+					Return(&models.ItemPage{Items: []models.BucketListItem{}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid tag",
+			query:          "?tag=food",
+			setupMocks:     func(m *MockRepositoryManager) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_QUERY_PARAMETERS",
+		},
+		{
+			name:           "invalid parameters",
+			query:          "?completed=maybe&sort=random&limit=0",
+			setupMocks:     func(m *MockRepositoryManager) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_QUERY_PARAMETERS",
+		},
+		{
+			name:  "invalid cursor",
+			query: "?cursor=garbage",
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+				m.bucketItems.On("List", mock.Anything, groupID, models.ItemListOptions{Cursor: "garbage"}).
+					Return(nil, repositories.ErrInvalidCursor)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_CURSOR",
+		},
+		{
+			name:  "group not found",
+			query: "",
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, groupID).Return(nil, fmt.Errorf("%w: %s", repositories.ErrGroupNotFound, groupID))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "GROUP_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepoManager := NewMockRepositoryManager()
+			tt.setupMocks(mockRepoManager)
+
+			handler := NewBucketItemHandler(mockRepoManager)
+			router := gin.New()
+			router.GET("/api/groups/:id/items", handler.ListItems)
+
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/groups/%s/items%s", groupID, tt.query), nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			if tt.expectedError != "" {
+				errorObj, exists := response["error"].(map[string]interface{})
+				assert.True(t, exists)
+				assert.Equal(t, tt.expectedError, errorObj["code"])
+			} else {
+				_, isList := response["items"].([]interface{})
+				assert.True(t, isList, "items should always be a list")
+			}
+
+			mockRepoManager.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBucketItemHandler_SearchItems(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	groupID := uuid.New().String()
+	group := &models.Group{ID: groupID, Name: "Test Group", CreatedAt: time.Now()}
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMocks     func(*MockRepositoryManager)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:  "successful search",
+			query: "?q=hik&limit=5",
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+				m.bucketItems.On("Search", mock.Anything, groupID, "hik", 5).Return([]models.ItemSearchResult{
+					{
+						BucketListItem: models.BucketListItem{ID: uuid.New().String(), GroupID: groupID, Title: "Hiking"},
+						TitleHighlight: "<mark>Hiking</mark>",
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "no results",
+			query: "?q=scuba",
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+				m.bucketItems.On("Search", mock.Anything, groupID, "scuba", models.DefaultSearchLimit).Return([]models.ItemSearchResult(nil), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing query",
+			query:          "",
+			setupMocks:     func(m *MockRepositoryManager) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_SEARCH_QUERY",
+		},
+		{
+			name:           "invalid limit",
+			query:          "?q=hik&limit=500",
+			setupMocks:     func(m *MockRepositoryManager) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_LIMIT",
+		},
+		{
+			name:  "group not found",
+			query: "?q=hik",
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, groupID).Return(nil, fmt.Errorf("%w: %s", repositories.ErrGroupNotFound, groupID))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "GROUP_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepoManager := NewMockRepositoryManager()
+			tt.setupMocks(mockRepoManager)
+
+			handler := NewBucketItemHandler(mockRepoManager)
+			router := gin.New()
+			router.GET("/api/groups/:id/items/search", handler.SearchItems)
+
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/groups/%s/items/search%s", groupID, tt.query), nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			if tt.expectedError != "" {
+				errorObj, exists := response["error"].(map[string]interface{})
+				assert.True(t, exists)
+				assert.Equal(t, tt.expectedError, errorObj["code"])
+			} else {
+				_, isList := response["items"].([]interface{})
+				assert.True(t, isList, "items should always be a list")
+			}
+
+			mockRepoManager.AssertExpectations(t)
+		})
+	}
+}
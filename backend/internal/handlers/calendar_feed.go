@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"collaborative-bucket-list/internal/middleware"
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+	"collaborative-bucket-list/pkg/ical"
+
+	"github.com/gin-gonic/gin"
+)
+
+// calendarProdID identifies the app in the calendar feeds it serves
+const calendarProdID = "-//Collaborative Bucket List//Calendar Feed//EN"
+
+// calendarUIDDomain makes the UIDs of feed events globally unique
+const calendarUIDDomain = "collaborative-bucket-list"
+
+// calendarRefreshInterval is how often subscribers are asked to poll feeds
+const calendarRefreshInterval = time.Hour
+
+// EnableCalendarFeed handles POST /api/groups/:id/calendar-feed, creating
+// the URL calendar apps subscribe to for the group's deadline and scheduled
+// items. Only a hash of its token is stored, so the URL is returned once;
+// later calls get a 409 until the group's creator revokes the feed.
+// Requires authentication as a member or the creator of the group.
+func (h *BucketItemHandler) EnableCalendarFeed(c *gin.Context) {
+	user, ok := middleware.RequireAuth(c)
+	if !ok {
+		return
+	}
+
+	groupID, ok := bindCalendarFeedGroupID(c)
+	if !ok {
+		return
+	}
+
+	group, ok := h.getGroup(c, groupID)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if group.CreatedBy != user.ID {
+		isMember, err := h.repos.Members().ExistsByGroupAndUser(ctx, groupID, user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": gin.H{
+					"code":    "MEMBERSHIP_CHECK_FAILED",
+					"message": "Failed to check group membership",
+					"details": err.Error(),
+				},
+			})
+			return
+		}
+
+		if !isMember {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": gin.H{
+					"code":    "NOT_GROUP_MEMBER",
+					"message": "Only the group's members can subscribe to its calendar",
+				},
+			})
+			return
+		}
+	}
+
+	candidate, err := newCalendarToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "CALENDAR_FEED_CREATION_FAILED",
+				"message": "Failed to create calendar feed",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	tokenHash, err := h.repos.Groups().EnsureCalendarTokenHash(ctx, groupID, hashCalendarToken(candidate))
+	if err != nil {
+		if errors.Is(err, repositories.ErrGroupNotFound) {
+			respondGroupNotFound(c)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "CALENDAR_FEED_CREATION_FAILED",
+				"message": "Failed to create calendar feed",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	if tokenHash != hashCalendarToken(candidate) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": gin.H{
+				"code":    "CALENDAR_FEED_ENABLED",
+				"message": "The calendar feed is already enabled; its URL is only shown when it is created",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"token": candidate,
+		"url":   fmt.Sprintf("%s/api/groups/%s/calendar.ics?token=%s", getBaseURL(c), groupID, candidate),
+	})
+}
+
+// RevokeCalendarFeed handles DELETE /api/groups/:id/calendar-feed, turning
+// off the group's calendar feed so every existing subscription stops
+// working. The next EnableCalendarFeed call issues a new URL. Requires
+// authentication as the group's creator.
+func (h *BucketItemHandler) RevokeCalendarFeed(c *gin.Context) {
+	user, ok := middleware.RequireAuth(c)
+	if !ok {
+		return
+	}
+
+	groupID, ok := bindCalendarFeedGroupID(c)
+	if !ok {
+		return
+	}
+
+	group, ok := h.getGroup(c, groupID)
+	if !ok {
+		return
+	}
+
+	if group.CreatedBy != user.ID {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": gin.H{
+				"code":    "NOT_GROUP_ADMIN",
+				"message": "Only the group's creator can revoke its calendar feed",
+			},
+		})
+		return
+	}
+
+	if err := h.repos.Groups().RevokeCalendarToken(c.Request.Context(), groupID); err != nil {
+		if errors.Is(err, repositories.ErrGroupNotFound) {
+			respondGroupNotFound(c)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "CALENDAR_FEED_REVOCATION_FAILED",
+				"message": "Failed to revoke calendar feed",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetCalendarFeed handles GET /api/groups/:id/calendar.ics?token=, the
+// iCalendar feed of the group's deadline and scheduled items. The token
+// from EnableCalendarFeed stands in for authentication, since calendar apps
+// cannot log in; a missing, wrong or revoked token gets a 404. Responses
+// carry an ETag so apps polling an unchanged feed get a 304.
+func (h *BucketItemHandler) GetCalendarFeed(c *gin.Context) {
+	groupID := c.Param("id")
+	token := c.Query("token")
+
+	// Invalid IDs are not found either, so the feed reveals nothing
+	// without its token
+	if !models.ValidateUUID(groupID).IsValid || token == "" {
+		respondCalendarFeedNotFound(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	storedHash, err := h.repos.Groups().GetCalendarTokenHash(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrGroupNotFound) {
+			respondCalendarFeedNotFound(c)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "CALENDAR_FEED_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve calendar feed",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	if storedHash == "" || subtle.ConstantTimeCompare([]byte(storedHash), []byte(hashCalendarToken(token))) != 1 {
+		respondCalendarFeedNotFound(c)
+		return
+	}
+
+	group, ok := h.getGroup(c, groupID)
+	if !ok {
+		return
+	}
+
+	items, err := h.repos.BucketItems().GetByGroupID(ctx, groupID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "ITEMS_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve bucket list items",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	calendar := groupCalendarFeed(group, items, fmt.Sprintf("%s/groups/%s", getFrontendURL(), group.ID))
+	body := calendar.Marshal()
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, max-age=0, must-revalidate")
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="calendar.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", body)
+}
+
+// groupCalendarFeed lays out a group's deadline and scheduled items as
+// calendar events linking to groupURL. Event UIDs are derived from the
+// group and item IDs, so an item keeps its event when it is rescheduled.
+// A deadline ending a day in the group's timezone is an all-day event on
+// that day.
+func groupCalendarFeed(group *models.Group, items []models.BucketListItem, groupURL string) *ical.Calendar {
+	calendar := &ical.Calendar{
+		ProdID:          calendarProdID,
+		Name:            group.Name,
+		Timezone:        group.Location().String(),
+		RefreshInterval: calendarRefreshInterval,
+	}
+
+	if group.Deadline != nil {
+		event := ical.Event{
+			UID:     fmt.Sprintf("deadline-%s@%s", group.ID, calendarUIDDomain),
+			Stamp:   group.CreatedAt,
+			Start:   *group.Deadline,
+			Summary: fmt.Sprintf("%s deadline", group.Name),
+			URL:     groupURL,
+		}
+		if deadline := group.Deadline.In(group.Location()); models.EndsDay(deadline) {
+			event.Start = deadline.AddDate(0, 0, -1)
+			event.End = deadline
+			event.AllDay = true
+		}
+		calendar.Events = append(calendar.Events, event)
+	}
+
+	var scheduled []models.BucketListItem
+	for _, item := range items {
+		if item.ScheduledStart != nil {
+			scheduled = append(scheduled, item)
+		}
+	}
+	// Order events by start, so the feed does not change with the order
+	// items are listed in
+	sort.SliceStable(scheduled, func(i, j int) bool {
+		if !scheduled[i].ScheduledStart.Equal(*scheduled[j].ScheduledStart) {
+			return scheduled[i].ScheduledStart.Before(*scheduled[j].ScheduledStart)
+		}
+		return scheduled[i].ID < scheduled[j].ID
+	})
+
+	for _, item := range scheduled {
+		event := ical.Event{
+			UID:     fmt.Sprintf("item-%s@%s", item.ID, calendarUIDDomain),
+			Stamp:   item.CreatedAt,
+			Start:   *item.ScheduledStart,
+			Summary: item.Title,
+			URL:     groupURL,
+		}
+		if item.ScheduledEnd != nil {
+			event.End = *item.ScheduledEnd
+		}
+		if item.Completed {
+			event.Summary = "✓ " + item.Title
+		}
+		if item.Description != nil {
+			event.Description = *item.Description
+		}
+		calendar.Events = append(calendar.Events, event)
+	}
+
+	return calendar
+}
+
+// etagMatches reports whether an If-None-Match header lists etag. Weak
+// comparison is used, as RFC 9110 requires for If-None-Match.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// newCalendarToken generates the secret in a calendar feed URL
+func newCalendarToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate calendar token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// hashCalendarToken returns the SHA-256 hash, in hex, stored in place of a
+// calendar feed token
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// bindCalendarFeedGroupID validates the group ID in the path, writing a
+// 400 response if it is invalid
+func bindCalendarFeedGroupID(c *gin.Context) (string, bool) {
+	groupID := c.Param("id")
+
+	validation := models.ValidateUUID(groupID)
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_GROUP_ID",
+				"message": "Invalid group ID format",
+				"details": validation.Errors,
+			},
+		})
+		return "", false
+	}
+
+	return groupID, true
+}
+
+func respondCalendarFeedNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, gin.H{
+		"error": gin.H{
+			"code":    "CALENDAR_FEED_NOT_FOUND",
+			"message": "Calendar feed not found",
+		},
+	})
+}
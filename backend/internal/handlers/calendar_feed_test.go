@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"collaborative-bucket-list/internal/middleware"
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// calendarFeedRoutes registers the calendar feed routes served by handler
+func calendarFeedRoutes(handler *BucketItemHandler) func(gin.IRoutes) {
+	return func(r gin.IRoutes) {
+		r.GET("/groups/:id/calendar", handler.GetCalendar)
+		r.GET("/groups/:id/calendar.ics", handler.GetCalendarFeed)
+		r.POST("/groups/:id/calendar-feed", handler.EnableCalendarFeed)
+		r.DELETE("/groups/:id/calendar-feed", handler.RevokeCalendarFeed)
+	}
+}
+
+func TestBucketItemHandler_EnableCalendarFeed(t *testing.T) {
+	creator := createTestUser()
+	member := createTestUser()
+	group := &models.Group{ID: uuid.New().String(), Name: "Summer", CreatedBy: creator.ID}
+	path := fmt.Sprintf("/groups/%s/calendar-feed", group.ID)
+
+	tests := []struct {
+		name           string
+		user           *middleware.SupabaseUser
+		setupMocks     func(*MockRepositoryManager)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name: "created for the creator",
+			user: creator,
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, group.ID).Return(group, nil)
+				// A group without a feed takes the candidate token's hash
+				call := m.groups.On("EnsureCalendarTokenHash", mock.Anything, group.ID, mock.AnythingOfType("string"))
+				call.Run(func(args mock.Arguments) {
+					call.ReturnArguments = mock.Arguments{args.String(2), nil}
+				})
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "existing feed for a member",
+			user: member,
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, group.ID).Return(group, nil)
+				m.members.On("ExistsByGroupAndUser", mock.Anything, group.ID, member.ID).Return(true, nil)
+				m.groups.On("EnsureCalendarTokenHash", mock.Anything, group.ID, mock.AnythingOfType("string")).
+					Return(hashCalendarToken("existing"), nil)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedError:  "CALENDAR_FEED_ENABLED",
+		},
+		{
+			name: "not a member",
+			user: member,
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, group.ID).Return(group, nil)
+				m.members.On("ExistsByGroupAndUser", mock.Anything, group.ID, member.ID).Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "NOT_GROUP_MEMBER",
+		},
+		{
+			name:           "not authenticated",
+			setupMocks:     func(m *MockRepositoryManager) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "group not found",
+			user: creator,
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, group.ID).
+					Return(nil, fmt.Errorf("%w: %s", repositories.ErrGroupNotFound, group.ID))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "GROUP_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepos := NewMockRepositoryManager()
+			tt.setupMocks(mockRepos)
+
+			w := serveTestRequest(calendarFeedRoutes(NewBucketItemHandler(mockRepos)), tt.user, http.MethodPost, path, nil)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				assert.Equal(t, tt.expectedError, errorCode(t, w))
+			} else if tt.expectedStatus < http.StatusBadRequest {
+				var response struct {
+					Token string `json:"token"`
+					URL   string `json:"url"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				require.NotEmpty(t, response.Token)
+				// Only the token's hash is stored
+				mockRepos.groups.AssertCalled(t, "EnsureCalendarTokenHash", mock.Anything, group.ID, hashCalendarToken(response.Token))
+				assert.True(t, strings.HasSuffix(response.URL,
+					fmt.Sprintf("/api/groups/%s/calendar.ics?token=%s", group.ID, response.Token)))
+			}
+			mockRepos.groups.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBucketItemHandler_RevokeCalendarFeed(t *testing.T) {
+	creator := createTestUser()
+	group := &models.Group{ID: uuid.New().String(), Name: "Summer", CreatedBy: creator.ID}
+	path := fmt.Sprintf("/groups/%s/calendar-feed", group.ID)
+
+	t.Run("revoked by the creator", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.groups.On("GetByID", mock.Anything, group.ID).Return(group, nil)
+		mockRepos.groups.On("RevokeCalendarToken", mock.Anything, group.ID).Return(nil)
+
+		w := serveTestRequest(calendarFeedRoutes(NewBucketItemHandler(mockRepos)), creator, http.MethodDelete, path, nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		mockRepos.groups.AssertExpectations(t)
+	})
+
+	t.Run("not the creator", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.groups.On("GetByID", mock.Anything, group.ID).Return(group, nil)
+
+		w := serveTestRequest(calendarFeedRoutes(NewBucketItemHandler(mockRepos)), createTestUser(), http.MethodDelete, path, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "NOT_GROUP_ADMIN", errorCode(t, w))
+		mockRepos.groups.AssertNotCalled(t, "RevokeCalendarToken", mock.Anything, mock.Anything)
+	})
+}
+
+func TestBucketItemHandler_GetCalendarFeed(t *testing.T) {
+	// The end of December 31 in Tokyo
+	deadline := time.Date(2026, 12, 31, 15, 0, 0, 0, time.UTC)
+	group := &models.Group{ID: uuid.New().String(), Name: "Summer", Deadline: &deadline, Timezone: "Asia/Tokyo"}
+	start := time.Date(2026, 7, 4, 18, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	items := []models.BucketListItem{
+		{ID: "unscheduled", GroupID: group.ID, Title: "Someday"},
+		{ID: "fireworks", GroupID: group.ID, Title: "Fireworks", ItemSchedule: models.ItemSchedule{ScheduledStart: &start, ScheduledEnd: &end}},
+	}
+	path := fmt.Sprintf("/groups/%s/calendar.ics?token=secret", group.ID)
+
+	feedRepos := func() *MockRepositoryManager {
+		m := NewMockRepositoryManager()
+		m.groups.On("GetCalendarTokenHash", mock.Anything, group.ID).Return(hashCalendarToken("secret"), nil)
+		m.groups.On("GetByID", mock.Anything, group.ID).Return(group, nil)
+		m.bucketItems.On("GetByGroupID", mock.Anything, group.ID).Return(items, nil)
+		return m
+	}
+
+	t.Run("events for the deadline and scheduled items", func(t *testing.T) {
+		w := serveTestRequest(calendarFeedRoutes(NewBucketItemHandler(feedRepos())), nil, http.MethodGet, path, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.NotEmpty(t, w.Header().Get("ETag"))
+
+		body := w.Body.String()
+		assert.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\n"))
+		assert.Equal(t, 2, strings.Count(body, "BEGIN:VEVENT"))
+		assert.Contains(t, body, fmt.Sprintf("UID:deadline-%s@collaborative-bucket-list\r\n", group.ID))
+		assert.Contains(t, body, "X-WR-TIMEZONE:Asia/Tokyo\r\n")
+		assert.Contains(t, body, "DTSTART;VALUE=DATE:20261231\r\nDTEND;VALUE=DATE:20270101\r\n")
+		assert.Contains(t, body, "UID:item-fireworks@collaborative-bucket-list\r\n")
+		assert.Contains(t, body, "DTSTART:20260704T180000Z\r\nDTEND:20260704T200000Z\r\n")
+		assert.NotContains(t, body, "Someday")
+	})
+
+	t.Run("unchanged feed is not modified", func(t *testing.T) {
+		first := serveTestRequest(calendarFeedRoutes(NewBucketItemHandler(feedRepos())), nil, http.MethodGet, path, nil)
+		require.Equal(t, http.StatusOK, first.Code)
+		etag := first.Header().Get("ETag")
+
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("If-None-Match", `"other", `+etag)
+		w := serveTestRouter(calendarFeedRoutes(NewBucketItemHandler(feedRepos())), nil, req)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+		assert.Empty(t, w.Body.String())
+
+		req = httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("If-None-Match", `"other"`)
+		w = serveTestRouter(calendarFeedRoutes(NewBucketItemHandler(feedRepos())), nil, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("wrong or revoked token", func(t *testing.T) {
+		// The token itself is not its hash
+		for _, storedHash := range []string{hashCalendarToken("other"), "secret", ""} {
+			mockRepos := NewMockRepositoryManager()
+			mockRepos.groups.On("GetCalendarTokenHash", mock.Anything, group.ID).Return(storedHash, nil)
+
+			w := serveTestRequest(calendarFeedRoutes(NewBucketItemHandler(mockRepos)), nil, http.MethodGet, path, nil)
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.Equal(t, "CALENDAR_FEED_NOT_FOUND", errorCode(t, w))
+			mockRepos.bucketItems.AssertNotCalled(t, "GetByGroupID", mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+
+		w := serveTestRequest(calendarFeedRoutes(NewBucketItemHandler(mockRepos)), nil, http.MethodGet,
+			fmt.Sprintf("/groups/%s/calendar.ics", group.ID), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "CALENDAR_FEED_NOT_FOUND", errorCode(t, w))
+	})
+
+	t.Run("group not found", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.groups.On("GetCalendarTokenHash", mock.Anything, group.ID).
+			Return("", fmt.Errorf("%w: %s", repositories.ErrGroupNotFound, group.ID))
+
+		w := serveTestRequest(calendarFeedRoutes(NewBucketItemHandler(mockRepos)), nil, http.MethodGet, path, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "CALENDAR_FEED_NOT_FOUND", errorCode(t, w))
+	})
+}
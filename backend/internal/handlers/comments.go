@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+	"collaborative-bucket-list/internal/websocket"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CommentHandler handles item comment HTTP requests. Every change is
+// broadcast to the item's group room, so open clients update live.
+type CommentHandler struct {
+	repos repositories.RepositoryManager
+	hub   websocket.HubInterface
+}
+
+// NewCommentHandler creates a new comment handler
+func NewCommentHandler(repos repositories.RepositoryManager, hub websocket.HubInterface) *CommentHandler {
+	return &CommentHandler{
+		repos: repos,
+		hub:   hub,
+	}
+}
+
+// GetItemComments handles GET /api/items/:id/comments, returning the item's
+// comment threads oldest first
+func (h *CommentHandler) GetItemComments(c *gin.Context) {
+	item, ok := bindItem(c, h.repos)
+	if !ok {
+		return
+	}
+
+	threads, err := h.repos.Comments().GetByItemID(c.Request.Context(), item.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "COMMENTS_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve comments",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	if threads == nil {
+		threads = []models.CommentThread{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": threads,
+	})
+}
+
+// CreateComment handles POST /api/items/:id/comments. A comment with a
+// parentId replies to a top-level comment on the same item.
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	item, ok := bindItem(c, h.repos)
+	if !ok {
+		return
+	}
+
+	var req models.CreateCommentRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	member, ok := requireItemGroupMember(c, h.repos, item, req.MemberID)
+	if !ok {
+		return
+	}
+
+	comment := &models.Comment{
+		ID:        uuid.New().String(),
+		ItemID:    item.ID,
+		ParentID:  req.ParentID,
+		MemberID:  member.ID,
+		Body:      req.Body,
+		CreatedAt: time.Now(),
+	}
+
+	if err := h.repos.Comments().Create(c.Request.Context(), comment); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrInvalidReply):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": gin.H{
+					"code":    "INVALID_REPLY",
+					"message": "Replies must answer a top-level comment on the same item",
+				},
+			})
+		case errors.Is(err, repositories.ErrCommentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": gin.H{
+					"code":    "PARENT_COMMENT_NOT_FOUND",
+					"message": "The comment being replied to was not found",
+				},
+			})
+		case errors.Is(err, repositories.ErrItemNotFound):
+			respondItemNotFound(c)
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": gin.H{
+					"code":    "COMMENT_CREATION_FAILED",
+					"message": "Failed to create comment",
+					"details": err.Error(),
+				},
+			})
+		}
+		return
+	}
+
+	h.hub.BroadcastToRoom(comment.GroupID, websocket.EventCommentAdded, comment)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"comment": comment,
+	})
+}
+
+// UpdateComment handles PUT /api/comments/:id. Only the comment's author
+// can edit it.
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	var req models.UpdateCommentRequest
+	comment, ok := h.bindComment(c)
+	if !ok || !bindRequest(c, &req) {
+		return
+	}
+
+	if req.MemberID != comment.MemberID {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": gin.H{
+				"code":    "NOT_COMMENT_AUTHOR",
+				"message": "Only the comment's author can edit it",
+			},
+		})
+		return
+	}
+
+	comment.Body = req.Body
+	if err := h.repos.Comments().Update(c.Request.Context(), comment); err != nil {
+		if errors.Is(err, repositories.ErrCommentNotFound) {
+			respondCommentNotFound(c)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "COMMENT_UPDATE_FAILED",
+				"message": "Failed to update comment",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	h.hub.BroadcastToRoom(comment.GroupID, websocket.EventCommentEdited, comment)
+
+	c.JSON(http.StatusOK, gin.H{
+		"comment": comment,
+	})
+}
+
+// DeleteComment handles DELETE /api/comments/:id?memberId=, deleting the
+// comment and its replies. The comment's author and the group's creator
+// can delete it.
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	comment, ok := h.bindComment(c)
+	if !ok {
+		return
+	}
+
+	member, ok := getMember(c, h.repos, c.Query("memberId"))
+	if !ok {
+		return
+	}
+
+	isModerator := member.IsCreator && member.GroupID == comment.GroupID
+	if member.ID != comment.MemberID && !isModerator {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": gin.H{
+				"code":    "NOT_COMMENT_AUTHOR",
+				"message": "Only the comment's author or the group's creator can delete it",
+			},
+		})
+		return
+	}
+
+	if err := h.repos.Comments().Delete(c.Request.Context(), comment.ID); err != nil {
+		if errors.Is(err, repositories.ErrCommentNotFound) {
+			respondCommentNotFound(c)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "COMMENT_DELETION_FAILED",
+				"message": "Failed to delete comment",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	h.hub.BroadcastToRoom(comment.GroupID, websocket.EventCommentDeleted, websocket.CommentDeletedPayload{
+		ID:       comment.ID,
+		ItemID:   comment.ItemID,
+		ParentID: comment.ParentID,
+	})
+
+	c.Status(http.StatusNoContent)
+}
+
+// bindComment looks up the comment named by the id path parameter, writing
+// a 400, 404 or 500 response if it cannot
+func (h *CommentHandler) bindComment(c *gin.Context) (*models.Comment, bool) {
+	commentID := c.Param("id")
+
+	// Validate UUID format
+	validation := models.ValidateUUID(commentID)
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_COMMENT_ID",
+				"message": "Invalid comment ID format",
+				"details": validation.Errors,
+			},
+		})
+		return nil, false
+	}
+
+	comment, err := h.repos.Comments().GetByID(c.Request.Context(), commentID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrCommentNotFound) {
+			respondCommentNotFound(c)
+			return nil, false
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "COMMENT_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve comment",
+				"details": err.Error(),
+			},
+		})
+		return nil, false
+	}
+
+	return comment, true
+}
+
+func respondCommentNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, gin.H{
+		"error": gin.H{
+			"code":    "COMMENT_NOT_FOUND",
+			"message": "Comment not found",
+		},
+	})
+}
@@ -0,0 +1,354 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+	"collaborative-bucket-list/internal/websocket"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// broadcast is a message sent through a recordingHub
+type broadcast struct {
+	roomID      string
+	messageType string
+	data        interface{}
+}
+
+// recordingHub implements websocket.HubInterface by recording broadcasts
+type recordingHub struct {
+	broadcasts []broadcast
+}
+
+func (h *recordingHub) BroadcastToRoom(roomID, messageType string, data interface{}) {
+	h.broadcasts = append(h.broadcasts, broadcast{roomID, messageType, data})
+}
+
+var _ websocket.HubInterface = (*recordingHub)(nil)
+
+// commentRoutes registers the comment routes served by handler
+func commentRoutes(handler *CommentHandler) func(gin.IRoutes) {
+	return func(r gin.IRoutes) {
+		r.GET("/items/:id/comments", handler.GetItemComments)
+		r.POST("/items/:id/comments", handler.CreateComment)
+		r.PUT("/comments/:id", handler.UpdateComment)
+		r.DELETE("/comments/:id", handler.DeleteComment)
+	}
+}
+
+func TestCommentHandler_GetItemComments(t *testing.T) {
+	groupID := uuid.New().String()
+	itemID := uuid.New().String()
+
+	t.Run("threads", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.bucketItems.On("GetByID", mock.Anything, itemID).Return(&models.BucketListItem{ID: itemID, GroupID: groupID}, nil)
+		parentID := uuid.New().String()
+		mockRepos.comments.On("GetByItemID", mock.Anything, itemID).Return([]models.CommentThread{
+			{
+				Comment: models.Comment{ID: parentID, ItemID: itemID, GroupID: groupID, Body: "Which weekend?"},
+				Replies: []models.Comment{{ID: uuid.New().String(), ItemID: itemID, ParentID: &parentID, Body: "The first one"}},
+			},
+		}, nil)
+
+		w := serveTestRequest(commentRoutes(NewCommentHandler(mockRepos, &recordingHub{})), nil, http.MethodGet, fmt.Sprintf("/items/%s/comments", itemID), nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Comments []models.CommentThread `json:"comments"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Comments, 1)
+		assert.Equal(t, "Which weekend?", response.Comments[0].Body)
+		require.Len(t, response.Comments[0].Replies, 1)
+		assert.Equal(t, "The first one", response.Comments[0].Replies[0].Body)
+	})
+
+	t.Run("no comments is an empty list", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.bucketItems.On("GetByID", mock.Anything, itemID).Return(&models.BucketListItem{ID: itemID, GroupID: groupID}, nil)
+		mockRepos.comments.On("GetByItemID", mock.Anything, itemID).Return([]models.CommentThread(nil), nil)
+
+		w := serveTestRequest(commentRoutes(NewCommentHandler(mockRepos, &recordingHub{})), nil, http.MethodGet, fmt.Sprintf("/items/%s/comments", itemID), nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"comments":[]}`, w.Body.String())
+	})
+
+	t.Run("unknown item", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.bucketItems.On("GetByID", mock.Anything, itemID).Return(nil, fmt.Errorf("%w: %s", repositories.ErrItemNotFound, itemID))
+
+		w := serveTestRequest(commentRoutes(NewCommentHandler(mockRepos, &recordingHub{})), nil, http.MethodGet, fmt.Sprintf("/items/%s/comments", itemID), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "ITEM_NOT_FOUND", errorCode(t, w))
+	})
+
+	t.Run("invalid item ID", func(t *testing.T) {
+		w := serveTestRequest(commentRoutes(NewCommentHandler(NewMockRepositoryManager(), &recordingHub{})), nil, http.MethodGet, "/items/not-a-uuid/comments", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_ITEM_ID", errorCode(t, w))
+	})
+}
+
+func TestCommentHandler_CreateComment(t *testing.T) {
+	groupID := uuid.New().String()
+	itemID := uuid.New().String()
+	memberID := uuid.New().String()
+	path := fmt.Sprintf("/items/%s/comments", itemID)
+
+	setup := func() *MockRepositoryManager {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.bucketItems.On("GetByID", mock.Anything, itemID).Return(&models.BucketListItem{ID: itemID, GroupID: groupID}, nil)
+		mockRepos.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: groupID}, nil)
+		return mockRepos
+	}
+
+	t.Run("creates and broadcasts", func(t *testing.T) {
+		mockRepos := setup()
+		mockRepos.comments.On("Create", mock.Anything, mock.MatchedBy(func(comment *models.Comment) bool {
+			return comment.ItemID == itemID && comment.MemberID == memberID && comment.Body == "I'll book it" && comment.ParentID == nil
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Comment).GroupID = groupID
+		}).Return(nil)
+		hub := &recordingHub{}
+
+		w := serveTestRequest(commentRoutes(NewCommentHandler(mockRepos, hub)), nil, http.MethodPost, path, map[string]interface{}{
+			"memberId": memberID,
+			"body":     "  I'll   book it ",
+		})
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var response struct {
+			Comment models.Comment `json:"comment"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "I'll book it", response.Comment.Body)
+		assert.Equal(t, groupID, response.Comment.GroupID)
+
+		require.Len(t, hub.broadcasts, 1)
+		assert.Equal(t, groupID, hub.broadcasts[0].roomID)
+		assert.Equal(t, websocket.EventCommentAdded, hub.broadcasts[0].messageType)
+		assert.Equal(t, response.Comment.ID, hub.broadcasts[0].data.(*models.Comment).ID)
+	})
+
+	t.Run("reply to a reply", func(t *testing.T) {
+		mockRepos := setup()
+		parentID := uuid.New().String()
+		mockRepos.comments.On("Create", mock.Anything, mock.Anything).Return(fmt.Errorf("failed to create comment: %w", repositories.ErrInvalidReply))
+		hub := &recordingHub{}
+
+		w := serveTestRequest(commentRoutes(NewCommentHandler(mockRepos, hub)), nil, http.MethodPost, path, map[string]interface{}{
+			"memberId": memberID,
+			"body":     "Agreed",
+			"parentId": parentID,
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_REPLY", errorCode(t, w))
+		assert.Empty(t, hub.broadcasts)
+	})
+
+	t.Run("unknown parent", func(t *testing.T) {
+		mockRepos := setup()
+		mockRepos.comments.On("Create", mock.Anything, mock.Anything).Return(fmt.Errorf("failed to create comment: %w", repositories.ErrCommentNotFound))
+
+		w := serveTestRequest(commentRoutes(NewCommentHandler(mockRepos, &recordingHub{})), nil, http.MethodPost, path, map[string]interface{}{
+			"memberId": memberID,
+			"body":     "Agreed",
+			"parentId": uuid.New().String(),
+		})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "PARENT_COMMENT_NOT_FOUND", errorCode(t, w))
+	})
+
+	t.Run("member of another group", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.bucketItems.On("GetByID", mock.Anything, itemID).Return(&models.BucketListItem{ID: itemID, GroupID: groupID}, nil)
+		mockRepos.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: uuid.New().String()}, nil)
+
+		w := serveTestRequest(commentRoutes(NewCommentHandler(mockRepos, &recordingHub{})), nil, http.MethodPost, path, map[string]interface{}{
+			"memberId": memberID,
+			"body":     "Hello",
+		})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "MEMBER_NOT_IN_GROUP", errorCode(t, w))
+		mockRepos.comments.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		mockRepos := setup()
+		tests := []struct {
+			name string
+			body interface{}
+			code string
+		}{
+			{"blank body", map[string]interface{}{"memberId": memberID, "body": "   "}, "VALIDATION_ERROR"},
+			{"body too long", map[string]interface{}{"memberId": memberID, "body": string(bytes.Repeat([]byte("a"), models.MaxCommentLength+1))}, "VALIDATION_ERROR"},
+			{"invalid parent ID", map[string]interface{}{"memberId": memberID, "body": "Hi", "parentId": "nope"}, "VALIDATION_ERROR"},
+			{"invalid member ID", map[string]interface{}{"memberId": "nope", "body": "Hi"}, "INVALID_MEMBER_ID"},
+			{"malformed JSON", `{"memberId":`, "INVALID_REQUEST_BODY"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				w := serveTestRequest(commentRoutes(NewCommentHandler(mockRepos, &recordingHub{})), nil, http.MethodPost, path, tt.body)
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				assert.Equal(t, tt.code, errorCode(t, w))
+			})
+		}
+	})
+}
+
+func TestCommentHandler_UpdateComment(t *testing.T) {
+	groupID := uuid.New().String()
+	authorID := uuid.New().String()
+	commentID := uuid.New().String()
+	path := fmt.Sprintf("/comments/%s", commentID)
+
+	existing := func() *models.Comment {
+		return &models.Comment{
+			ID:        commentID,
+			ItemID:    uuid.New().String(),
+			GroupID:   groupID,
+			MemberID:  authorID,
+			Body:      "Which weekend?",
+			CreatedAt: time.Now(),
+		}
+	}
+
+	t.Run("author edits and broadcasts", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.comments.On("GetByID", mock.Anything, commentID).Return(existing(), nil)
+		mockRepos.comments.On("Update", mock.Anything, mock.MatchedBy(func(comment *models.Comment) bool {
+			return comment.Body == "Which long weekend?"
+		})).Run(func(args mock.Arguments) {
+			editedAt := time.Now()
+			args.Get(1).(*models.Comment).EditedAt = &editedAt
+		}).Return(nil)
+		hub := &recordingHub{}
+
+		w := serveTestRequest(commentRoutes(NewCommentHandler(mockRepos, hub)), nil, http.MethodPut, path, map[string]interface{}{
+			"memberId": authorID,
+			"body":     "Which long weekend?",
+		})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Comment models.Comment `json:"comment"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Which long weekend?", response.Comment.Body)
+		assert.NotNil(t, response.Comment.EditedAt)
+
+		require.Len(t, hub.broadcasts, 1)
+		assert.Equal(t, groupID, hub.broadcasts[0].roomID)
+		assert.Equal(t, websocket.EventCommentEdited, hub.broadcasts[0].messageType)
+	})
+
+	t.Run("only the author can edit", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.comments.On("GetByID", mock.Anything, commentID).Return(existing(), nil)
+		hub := &recordingHub{}
+
+		w := serveTestRequest(commentRoutes(NewCommentHandler(mockRepos, hub)), nil, http.MethodPut, path, map[string]interface{}{
+			"memberId": uuid.New().String(),
+			"body":     "Hijacked",
+		})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "NOT_COMMENT_AUTHOR", errorCode(t, w))
+		assert.Empty(t, hub.broadcasts)
+	})
+
+	t.Run("unknown comment", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.comments.On("GetByID", mock.Anything, commentID).Return(nil, fmt.Errorf("%w: %s", repositories.ErrCommentNotFound, commentID))
+
+		w := serveTestRequest(commentRoutes(NewCommentHandler(mockRepos, &recordingHub{})), nil, http.MethodPut, path, map[string]interface{}{
+			"memberId": authorID,
+			"body":     "Hello",
+		})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "COMMENT_NOT_FOUND", errorCode(t, w))
+	})
+
+	t.Run("invalid comment ID", func(t *testing.T) {
+		w := serveTestRequest(commentRoutes(NewCommentHandler(NewMockRepositoryManager(), &recordingHub{})), nil, http.MethodPut, "/comments/nope", map[string]interface{}{
+			"memberId": authorID,
+			"body":     "Hello",
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_COMMENT_ID", errorCode(t, w))
+	})
+}
+
+func TestCommentHandler_DeleteComment(t *testing.T) {
+	groupID := uuid.New().String()
+	authorID := uuid.New().String()
+	commentID := uuid.New().String()
+	parentID := uuid.New().String()
+	itemID := uuid.New().String()
+
+	setup := func() *MockRepositoryManager {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.comments.On("GetByID", mock.Anything, commentID).Return(&models.Comment{
+			ID:       commentID,
+			ItemID:   itemID,
+			GroupID:  groupID,
+			ParentID: &parentID,
+			MemberID: authorID,
+			Body:     "The first one",
+		}, nil)
+		return mockRepos
+	}
+
+	tests := []struct {
+		name   string
+		member models.Member
+		status int
+	}{
+		{"author", models.Member{ID: authorID, GroupID: groupID}, http.StatusNoContent},
+		{"group creator", models.Member{ID: uuid.New().String(), GroupID: groupID, IsCreator: true}, http.StatusNoContent},
+		{"other member", models.Member{ID: uuid.New().String(), GroupID: groupID}, http.StatusForbidden},
+		{"creator of another group", models.Member{ID: uuid.New().String(), GroupID: uuid.New().String(), IsCreator: true}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepos := setup()
+			member := tt.member
+			mockRepos.members.On("GetByID", mock.Anything, member.ID).Return(&member, nil)
+			mockRepos.comments.On("Delete", mock.Anything, commentID).Return(nil)
+			hub := &recordingHub{}
+
+			w := serveTestRequest(commentRoutes(NewCommentHandler(mockRepos, hub)), nil, http.MethodDelete,
+				fmt.Sprintf("/comments/%s?memberId=%s", commentID, member.ID), nil)
+			require.Equal(t, tt.status, w.Code)
+
+			if tt.status != http.StatusNoContent {
+				assert.Equal(t, "NOT_COMMENT_AUTHOR", errorCode(t, w))
+				mockRepos.comments.AssertNotCalled(t, "Delete", mock.Anything, commentID)
+				assert.Empty(t, hub.broadcasts)
+				return
+			}
+
+			require.Len(t, hub.broadcasts, 1)
+			assert.Equal(t, groupID, hub.broadcasts[0].roomID)
+			assert.Equal(t, websocket.EventCommentDeleted, hub.broadcasts[0].messageType)
+			assert.Equal(t, websocket.CommentDeletedPayload{ID: commentID, ItemID: itemID, ParentID: &parentID}, hub.broadcasts[0].data)
+		})
+	}
+
+	t.Run("missing member ID", func(t *testing.T) {
+		w := serveTestRequest(commentRoutes(NewCommentHandler(setup(), &recordingHub{})), nil, http.MethodDelete, fmt.Sprintf("/comments/%s", commentID), nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_MEMBER_ID", errorCode(t, w))
+	})
+}
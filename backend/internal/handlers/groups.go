@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -10,6 +10,7 @@ import (
 	"collaborative-bucket-list/internal/middleware"
 	"collaborative-bucket-list/internal/models"
 	"collaborative-bucket-list/internal/repositories"
+	"collaborative-bucket-list/pkg/storage"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -18,6 +19,10 @@ import (
 // GroupHandler handles group-related HTTP requests
 type GroupHandler struct {
 	repos repositories.RepositoryManager
+	// blobs signs the download URLs of attachments in group details for
+	// urlExpiry; without it attachments are listed without URLs
+	blobs     storage.BlobStore
+	urlExpiry time.Duration
 }
 
 // NewGroupHandler creates a new group handler
@@ -25,6 +30,16 @@ func NewGroupHandler(repos repositories.RepositoryManager) *GroupHandler {
 	return &GroupHandler{repos: repos}
 }
 
+// NewGroupHandlerWithBlobStore creates a new group handler that signs the
+// download URLs of attachments in group details
+func NewGroupHandlerWithBlobStore(repos repositories.RepositoryManager, blobs storage.BlobStore, urlExpiry time.Duration) *GroupHandler {
+	return &GroupHandler{
+		repos:     repos,
+		blobs:     blobs,
+		urlExpiry: urlExpiry,
+	}
+}
+
 // CreateGroup handles POST /api/groups
 func (h *GroupHandler) CreateGroup(c *gin.Context) {
 	// Require authentication
@@ -63,18 +78,18 @@ func (h *GroupHandler) CreateGroup(c *gin.Context) {
 
 	// Create group model
 	group := &models.Group{
-		ID:        uuid.New().String(),
-		Name:      req.Name,
-		Deadline:  req.Deadline,
-		CreatedAt: time.Now(),
-		CreatedBy: user.ID,
+		ID:                uuid.New().String(),
+		Name:              req.Name,
+		Deadline:          req.ParsedDeadline(),
+		Timezone:          req.Timezone,
+		LockAfterDeadline: req.LockAfterDeadline,
+		CreatedAt:         time.Now(),
+		CreatedBy:         user.ID,
 	}
 
-	// Create group and creator member in a transaction
-	err := h.repos.WithTx(c.Request.Context(), func(tx *sql.Tx) error {
-		// Create transactional repository manager
-		txRepos := repositories.NewTransactionalRepositoryManager(tx)
-		
+	// Create group, creator member and reminder schedule in a transaction
+	reminderDays := req.ReminderSchedule()
+	err := h.repos.WithTx(c.Request.Context(), func(txRepos repositories.RepositoryManager) error {
 		// Create the group
 		if err := txRepos.Groups().Create(c.Request.Context(), group); err != nil {
 			return fmt.Errorf("failed to create group: %w", err)
@@ -94,6 +109,10 @@ func (h *GroupHandler) CreateGroup(c *gin.Context) {
 			return fmt.Errorf("failed to create creator member: %w", err)
 		}
 
+		if err := txRepos.Groups().SetReminderDays(c.Request.Context(), group.ID, reminderDays); err != nil {
+			return fmt.Errorf("failed to schedule reminders: %w", err)
+		}
+
 		return nil
 	})
 
@@ -112,13 +131,18 @@ func (h *GroupHandler) CreateGroup(c *gin.Context) {
 	shareLink := fmt.Sprintf("%s/groups/%s", getFrontendURL(), group.ID)
 
 	// Return created group with share link
+	group.Localize()
 	response := gin.H{
-		"id":        group.ID,
-		"name":      group.Name,
-		"deadline":  group.Deadline,
-		"createdAt": group.CreatedAt,
-		"createdBy": group.CreatedBy,
-		"shareLink": shareLink,
+		"id":                group.ID,
+		"name":              group.Name,
+		"deadline":          group.Deadline,
+		"deadlineDate":      group.DeadlineDate,
+		"timezone":          group.Timezone,
+		"lockAfterDeadline": group.LockAfterDeadline,
+		"reminderDays":      reminderDays,
+		"createdAt":         group.CreatedAt,
+		"createdBy":         group.CreatedBy,
+		"shareLink":         shareLink,
 	}
 
 	c.JSON(http.StatusCreated, response)
@@ -173,10 +197,26 @@ func (h *GroupHandler) GetGroup(c *gin.Context) {
 		return
 	}
 
+	groupDetails.UpdateDeadlineState(time.Now())
+
+	if h.blobs != nil {
+		for _, attachments := range groupDetails.Attachments {
+			for i := range attachments {
+				if err := signAttachment(c.Request.Context(), h.blobs, h.urlExpiry, &attachments[i]); err != nil {
+					respondAttachmentURLFailed(c, err)
+					return
+				}
+			}
+		}
+	}
+
 	c.JSON(http.StatusOK, groupDetails)
 }
 
-// GetUserGroups handles GET /api/users/groups
+// GetUserGroups handles GET /api/users/groups, returning a page of the
+// user's group summaries filtered by deadlineAfter, deadlineBefore,
+// minProgress and maxProgress and ordered by sort. Pass the nextCursor of a
+// page as cursor to get the next.
 func (h *GroupHandler) GetUserGroups(c *gin.Context) {
 	// Require authentication
 	user, ok := middleware.RequireAuth(c)
@@ -184,9 +224,28 @@ func (h *GroupHandler) GetUserGroups(c *gin.Context) {
 		return
 	}
 
-	// Get group summaries for the user
-	summaries, err := h.repos.Groups().GetSummariesByUserID(c.Request.Context(), user.ID)
+	opts := models.GroupSummaryListOptions{
+		Sort:   models.GroupSort(c.Query("sort")),
+		Cursor: c.Query("cursor"),
+	}
+	var params queryParams
+	opts.DeadlineAfter = params.time(c, "deadlineAfter")
+	opts.DeadlineBefore = params.time(c, "deadlineBefore")
+	opts.MinProgress = params.float(c, "minProgress")
+	opts.MaxProgress = params.float(c, "maxProgress")
+	opts.Limit = params.limit(c)
+	if !params.validate(c, opts.Validate()) {
+		return
+	}
+
+	// Get a page of group summaries for the user
+	page, err := h.repos.Groups().ListSummariesByUserID(c.Request.Context(), user.ID, opts)
 	if err != nil {
+		if errors.Is(err, repositories.ErrInvalidCursor) {
+			respondInvalidCursor(c)
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": gin.H{
 				"code":    "USER_GROUPS_RETRIEVAL_FAILED",
@@ -197,8 +256,63 @@ func (h *GroupHandler) GetUserGroups(c *gin.Context) {
 		return
 	}
 
+	now := time.Now()
+	for i := range page.Groups {
+		page.Groups[i].UpdateDeadlineState(now)
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// SearchUserGroups handles GET /api/users/search?q=, searching group names
+// and items across every group the user created or belongs to
+func (h *GroupHandler) SearchUserGroups(c *gin.Context) {
+	// Require authentication
+	user, ok := middleware.RequireAuth(c)
+	if !ok {
+		return
+	}
+
+	query, limit, ok := bindSearchParams(c)
+	if !ok {
+		return
+	}
+
+	groups, err := h.repos.Groups().SearchByUserID(c.Request.Context(), user.ID, query, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "SEARCH_FAILED",
+				"message": "Failed to search groups",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	items, err := h.repos.BucketItems().SearchByUserID(c.Request.Context(), user.ID, query, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "SEARCH_FAILED",
+				"message": "Failed to search bucket list items",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	if groups == nil {
+		groups = []models.GroupSearchResult{}
+	}
+	if items == nil {
+		items = []models.ItemSearchResult{}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"groups": summaries,
+		"query":  query,
+		"groups": groups,
+		"items":  items,
 	})
 }
 
@@ -320,6 +434,17 @@ func (h *GroupHandler) JoinGroup(c *gin.Context) {
 
 	// Create member in database
 	if err := h.repos.Members().Create(c.Request.Context(), member); err != nil {
+		// A concurrent join by the same user can slip past the check above
+		if errors.Is(err, repositories.ErrAlreadyMember) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": gin.H{
+					"code":    "ALREADY_MEMBER",
+					"message": "User is already a member of this group",
+				},
+			})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": gin.H{
 				"code":    "MEMBER_CREATION_FAILED",
@@ -336,6 +461,172 @@ func (h *GroupHandler) JoinGroup(c *gin.Context) {
 	})
 }
 
+// UpdateGroup handles PATCH /api/groups/:id, changing the group's name,
+// deadline, timezone or whether it locks after the deadline. A deadline
+// moved before items already scheduled is rejected, listing the items.
+// Requires authentication as the group's creator.
+func (h *GroupHandler) UpdateGroup(c *gin.Context) {
+	user, ok := middleware.RequireAuth(c)
+	if !ok {
+		return
+	}
+
+	groupID := c.Param("id")
+
+	// Validate UUID format
+	validation := models.ValidateUUID(groupID)
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_GROUP_ID",
+				"message": "Invalid group ID format",
+				"details": validation.Errors,
+			},
+		})
+		return
+	}
+
+	var req models.UpdateGroupRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	group, err := h.repos.Groups().GetByID(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrGroupNotFound) {
+			respondGroupNotFound(c)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "GROUP_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve group",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	if group.CreatedBy != user.ID {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": gin.H{
+				"code":    "NOT_GROUP_ADMIN",
+				"message": "Only the group's creator can change its settings",
+			},
+		})
+		return
+	}
+
+	// A new deadline must be ahead in the group's (new) timezone
+	if validation := req.Apply(group); !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Request validation failed",
+				"details": validation.Errors,
+			},
+		})
+		return
+	}
+
+	// Items already scheduled must still be over by a moved deadline
+	if (req.Deadline != nil || req.Timezone != nil) && group.Deadline != nil {
+		items, err := h.repos.BucketItems().GetByGroupID(ctx, group.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": gin.H{
+					"code":    "ITEMS_RETRIEVAL_FAILED",
+					"message": "Failed to retrieve bucket list items",
+					"details": err.Error(),
+				},
+			})
+			return
+		}
+
+		if validation := models.ValidateSchedulesByDeadline(items, group.Deadline); !validation.IsValid {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": gin.H{
+					"code":    "VALIDATION_ERROR",
+					"message": "Items are scheduled after the new deadline",
+					"details": validation.Errors,
+				},
+			})
+			return
+		}
+	}
+
+	if err := h.repos.Groups().Update(ctx, group); err != nil {
+		if errors.Is(err, repositories.ErrGroupNotFound) {
+			respondGroupNotFound(c)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "GROUP_UPDATE_FAILED",
+				"message": "Failed to update group",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"group": group,
+	})
+}
+
+func respondGroupNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, gin.H{
+		"error": gin.H{
+			"code":    "GROUP_NOT_FOUND",
+			"message": "Group not found",
+		},
+	})
+}
+
+// requireUnlockedGroup retrieves the group whose list a request changes,
+// writing a 404 or 500 response if it cannot and a 423 if the list locked
+// after its deadline.
+func requireUnlockedGroup(c *gin.Context, repos repositories.RepositoryManager, groupID string) (*models.Group, bool) {
+	group, err := repos.Groups().GetByID(c.Request.Context(), groupID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrGroupNotFound) {
+			respondGroupNotFound(c)
+			return nil, false
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "GROUP_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve group",
+				"details": err.Error(),
+			},
+		})
+		return nil, false
+	}
+
+	if group.IsLocked(time.Now()) {
+		respondGroupLocked(c)
+		return nil, false
+	}
+
+	return group, true
+}
+
+// respondGroupLocked rejects a change to a list that locked after its
+// deadline
+func respondGroupLocked(c *gin.Context) {
+	c.JSON(http.StatusLocked, gin.H{
+		"error": gin.H{
+			"code":    "GROUP_LOCKED",
+			"message": "The list is read-only since its deadline has passed",
+		},
+	})
+}
+
 // getBaseURL extracts the base URL from the request
 func getBaseURL(c *gin.Context) string {
 	scheme := "http"
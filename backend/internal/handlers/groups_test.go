@@ -4,18 +4,22 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"collaborative-bucket-list/internal/middleware"
 	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // Mock definitions are in mocks_test.go
@@ -26,6 +30,43 @@ func setupTestRouter() *gin.Engine {
 	return gin.New()
 }
 
+// serveTestRequest serves a request on a router with routes, as user or
+// anonymously if user is nil. A string body is sent as is and any other
+// non-nil body as JSON.
+func serveTestRequest(routes func(gin.IRoutes), user *middleware.SupabaseUser, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		reader = strings.NewReader(b)
+	default:
+		encoded, _ := json.Marshal(b)
+		reader = bytes.NewReader(encoded)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return serveTestRouter(routes, user, req)
+}
+
+// serveTestRouter serves req on a router with routes, as user or
+// anonymously if user is nil
+func serveTestRouter(routes func(gin.IRoutes), user *middleware.SupabaseUser, req *http.Request) *httptest.ResponseRecorder {
+	router := setupTestRouter()
+	if user != nil {
+		router.Use(func(c *gin.Context) {
+			addUserToContext(c, user)
+			c.Next()
+		})
+	}
+	routes(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
 func createTestUser() *middleware.SupabaseUser {
 	return &middleware.SupabaseUser{
 		ID:    uuid.New().String(),
@@ -55,7 +96,7 @@ func TestGroupHandler_CreateGroup(t *testing.T) {
 				Deadline: nil,
 			},
 			setupMocks: func(m *MockRepositoryManager) {
-				m.On("WithTx", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				m.On("WithTx", mock.Anything, mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
 			},
 			expectedStatus: http.StatusCreated,
 		},
@@ -68,6 +109,13 @@ func TestGroupHandler_CreateGroup(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedError:  "VALIDATION_ERROR",
 		},
+		{
+			name:           "reminder too far ahead",
+			requestBody:    `{"name": "Test Group", "reminderDays": [400]}`,
+			setupMocks:     func(m *MockRepositoryManager) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
 		{
 			name:           "malformed JSON",
 			requestBody:    "invalid json",
@@ -214,6 +262,7 @@ func TestGroupHandler_GetGroup(t *testing.T) {
 func TestGroupHandler_GetUserGroups(t *testing.T) {
 	tests := []struct {
 		name           string
+		query          string
 		setupMocks     func(*MockRepositoryManager, string)
 		expectedStatus int
 		expectedError  string
@@ -235,18 +284,54 @@ func TestGroupHandler_GetUserGroups(t *testing.T) {
 						ProgressPercent: 40.0,
 					},
 				}
-				m.groups.On("GetSummariesByUserID", mock.Anything, userID).Return(summaries, nil)
+				m.groups.On("ListSummariesByUserID", mock.Anything, userID, models.GroupSummaryListOptions{}).
+					Return(&models.GroupSummaryPage{Groups: summaries}, nil)
 			},
 			expectedStatus: http.StatusOK,
 		},
 		{
 			name: "database error",
 			setupMocks: func(m *MockRepositoryManager, userID string) {
-				m.groups.On("GetSummariesByUserID", mock.Anything, userID).Return([]models.GroupSummary{}, fmt.Errorf("database error"))
+				m.groups.On("ListSummariesByUserID", mock.Anything, userID, models.GroupSummaryListOptions{}).Return(nil, fmt.Errorf("database error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedError:  "USER_GROUPS_RETRIEVAL_FAILED",
 		},
+		{
+			name:  "filters, sort and cursor",
+			query: "?sort=progress&minProgress=50&deadlineBefore=2030-01-01&cursor=abc&limit=10",
+			setupMocks: func(m *MockRepositoryManager, userID string) {
+				minProgress := 50.0
+				deadlineBefore := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+				opts := models.GroupSummaryListOptions{
+					DeadlineBefore: &deadlineBefore,
+					MinProgress:    &minProgress,
+					Sort:           models.GroupSortProgress,
+					Cursor:         "abc",
+					Limit:          10,
+				}
+				m.groups.On("ListSummariesByUserID", mock.Anything, userID, opts).
+					Return(&models.GroupSummaryPage{Groups: []models.GroupSummary{}, NextCursor: "def"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid parameters",
+			query:          "?sort=alphabetical&minProgress=lots",
+			setupMocks:     func(m *MockRepositoryManager, userID string) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_QUERY_PARAMETERS",
+		},
+		{
+			name:  "invalid cursor",
+			query: "?cursor=garbage",
+			setupMocks: func(m *MockRepositoryManager, userID string) {
+				m.groups.On("ListSummariesByUserID", mock.Anything, userID, models.GroupSummaryListOptions{Cursor: "garbage"}).
+					Return(nil, repositories.ErrInvalidCursor)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_CURSOR",
+		},
 	}
 
 	for _, tt := range tests {
@@ -268,7 +353,7 @@ func TestGroupHandler_GetUserGroups(t *testing.T) {
 			router.GET("/users/groups", handler.GetUserGroups)
 
 			// Create request
-			req, err := http.NewRequest("GET", "/users/groups", nil)
+			req, err := http.NewRequest("GET", "/users/groups"+tt.query, nil)
 			assert.NoError(t, err)
 
 			// Execute
@@ -293,6 +378,87 @@ func TestGroupHandler_GetUserGroups(t *testing.T) {
 	}
 }
 
+func TestGroupHandler_SearchUserGroups(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		setupMocks     func(*MockRepositoryManager, string)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:  "successful search",
+			query: "?q=travel",
+			setupMocks: func(m *MockRepositoryManager, userID string) {
+				m.groups.On("SearchByUserID", mock.Anything, userID, "travel", models.DefaultSearchLimit).Return([]models.GroupSearchResult{
+					{Group: models.Group{ID: uuid.New().String(), Name: "Travel"}, NameHighlight: "<mark>Travel</mark>"},
+				}, nil)
+				m.bucketItems.On("SearchByUserID", mock.Anything, userID, "travel", models.DefaultSearchLimit).Return([]models.ItemSearchResult(nil), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid query",
+			query:          "?q=%20",
+			setupMocks:     func(m *MockRepositoryManager, userID string) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_SEARCH_QUERY",
+		},
+		{
+			name:  "database error",
+			query: "?q=travel",
+			setupMocks: func(m *MockRepositoryManager, userID string) {
+				m.groups.On("SearchByUserID", mock.Anything, userID, "travel", models.DefaultSearchLimit).Return([]models.GroupSearchResult(nil), fmt.Errorf("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  "SEARCH_FAILED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			mockRepos := NewMockRepositoryManager()
+			user := createTestUser()
+			tt.setupMocks(mockRepos, user.ID)
+
+			handler := NewGroupHandler(mockRepos)
+			router := setupTestRouter()
+
+			// Add middleware to set user context
+			router.Use(func(c *gin.Context) {
+				addUserToContext(c, user)
+				c.Next()
+			})
+
+			router.GET("/users/search", handler.SearchUserGroups)
+
+			// Execute
+			req := httptest.NewRequest(http.MethodGet, "/users/search"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			if tt.expectedError != "" {
+				errorObj, exists := response["error"].(map[string]interface{})
+				assert.True(t, exists)
+				assert.Equal(t, tt.expectedError, errorObj["code"])
+			} else {
+				_, groupsIsList := response["groups"].([]interface{})
+				_, itemsIsList := response["items"].([]interface{})
+				assert.True(t, groupsIsList)
+				assert.True(t, itemsIsList)
+			}
+
+			mockRepos.AssertExpectations(t)
+		})
+	}
+}
+
 func TestGroupHandler_JoinGroup(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -361,6 +527,28 @@ func TestGroupHandler_JoinGroup(t *testing.T) {
 			expectedStatus: http.StatusConflict,
 			expectedError:  "ALREADY_MEMBER",
 		},
+		{
+			name:    "concurrent join by same user",
+			groupID: uuid.New().String(),
+			requestBody: models.JoinGroupRequest{
+				MemberName: "Jane Smith",
+				UserID:     func() *string { s := uuid.New().String(); return &s }(),
+			},
+			setupMocks: func(m *MockRepositoryManager, groupID string) {
+				group := &models.Group{
+					ID:        groupID,
+					Name:      "Test Group",
+					CreatedAt: time.Now(),
+					CreatedBy: uuid.New().String(),
+				}
+				m.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+				m.members.On("ExistsByGroupAndUser", mock.Anything, groupID, mock.AnythingOfType("string")).Return(false, nil)
+				m.members.On("Create", mock.Anything, mock.AnythingOfType("*models.Member")).
+					Return(fmt.Errorf("failed to create member: %w", repositories.ErrAlreadyMember))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedError:  "ALREADY_MEMBER",
+		},
 		{
 			name:    "group not found",
 			groupID: uuid.New().String(),
@@ -501,4 +689,171 @@ func TestGroupHandler_JoinGroup(t *testing.T) {
 			mockRepos.AssertExpectations(t)
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestGroupHandler_CreateGroupWithTimezone(t *testing.T) {
+	mockRepos := NewMockRepositoryManager()
+	mockRepos.On("WithTx", mock.Anything, mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		addUserToContext(c, createTestUser())
+		c.Next()
+	})
+	router.POST("/groups", NewGroupHandler(mockRepos).CreateGroup)
+
+	body, err := json.Marshal(gin.H{"name": "Tokyo trip", "deadline": "2099-12-31", "timezone": "Asia/Tokyo"})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/groups", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var response struct {
+		Deadline     time.Time `json:"deadline"`
+		DeadlineDate string    `json:"deadlineDate"`
+		Timezone     string    `json:"timezone"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	// The end of December 31 in Tokyo, rendered in Tokyo time
+	assert.True(t, time.Date(2099, 12, 31, 15, 0, 0, 0, time.UTC).Equal(response.Deadline))
+	assert.Contains(t, w.Body.String(), `"deadline":"2100-01-01T00:00:00+09:00"`)
+	assert.Equal(t, "2099-12-31", response.DeadlineDate)
+	assert.Equal(t, "Asia/Tokyo", response.Timezone)
+}
+
+func TestGroupHandler_UpdateGroup(t *testing.T) {
+	creator := createTestUser()
+	groupID := uuid.New().String()
+	deadline := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+	newGroup := func() *models.Group {
+		return &models.Group{ID: groupID, Name: "Trip", Deadline: &deadline, Timezone: "UTC", CreatedBy: creator.ID}
+	}
+	path := fmt.Sprintf("/groups/%s", groupID)
+	early := time.Date(2098, 3, 1, 10, 0, 0, 0, time.UTC)
+	late := time.Date(2098, 9, 1, 10, 0, 0, 0, time.UTC)
+	// Before the deadline in UTC, after it in Tokyo
+	newYearsEve := time.Date(2098, 12, 31, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		user           *middleware.SupabaseUser
+		body           string
+		setupMocks     func(*MockRepositoryManager)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name: "moved to another timezone",
+			user: creator,
+			body: `{"timezone": "Asia/Tokyo"}`,
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, groupID).Return(newGroup(), nil)
+				m.bucketItems.On("GetByGroupID", mock.Anything, groupID).Return([]models.BucketListItem{}, nil)
+				m.groups.On("Update", mock.Anything, mock.MatchedBy(func(group *models.Group) bool {
+					// The deadline still ends December 31, now in Tokyo
+					return group.Timezone == "Asia/Tokyo" &&
+						group.Deadline.Equal(time.Date(2098, 12, 31, 15, 0, 0, 0, time.UTC))
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "deadline moved before a scheduled item",
+			user: creator,
+			body: `{"deadline": "2098-06-30"}`,
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, groupID).Return(newGroup(), nil)
+				m.bucketItems.On("GetByGroupID", mock.Anything, groupID).Return([]models.BucketListItem{
+					{ID: "early", Title: "Early", ItemSchedule: models.ItemSchedule{ScheduledStart: &early}},
+					{ID: "late", Title: "Late", ItemSchedule: models.ItemSchedule{ScheduledStart: &late}},
+					{ID: "unscheduled", Title: "Someday"},
+				}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+		{
+			name: "timezone moves the deadline before a scheduled item",
+			user: creator,
+			body: `{"timezone": "Asia/Tokyo"}`,
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, groupID).Return(newGroup(), nil)
+				m.bucketItems.On("GetByGroupID", mock.Anything, groupID).Return([]models.BucketListItem{
+					{ID: "new-years-eve", Title: "Fireworks", ItemSchedule: models.ItemSchedule{ScheduledStart: &newYearsEve}},
+				}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+		{
+			name:           "unknown timezone",
+			user:           creator,
+			body:           `{"timezone": "Mars/Olympus_Mons"}`,
+			setupMocks:     func(m *MockRepositoryManager) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+		{
+			name: "passed deadline",
+			user: creator,
+			body: `{"deadline": "2001-01-01"}`,
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, groupID).Return(newGroup(), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+		{
+			name: "not the creator",
+			user: createTestUser(),
+			body: `{"name": "Hijacked"}`,
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, groupID).Return(newGroup(), nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "NOT_GROUP_ADMIN",
+		},
+		{
+			name: "group not found",
+			user: creator,
+			body: `{"name": "Renamed"}`,
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, groupID).
+					Return(nil, fmt.Errorf("%w: %s", repositories.ErrGroupNotFound, groupID))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "GROUP_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepos := NewMockRepositoryManager()
+			tt.setupMocks(mockRepos)
+
+			router := setupTestRouter()
+			router.Use(func(c *gin.Context) {
+				addUserToContext(c, tt.user)
+				c.Next()
+			})
+			router.PATCH("/groups/:id", NewGroupHandler(mockRepos).UpdateGroup)
+
+			req := httptest.NewRequest(http.MethodPatch, path, bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				assert.Equal(t, tt.expectedError, errorCode(t, w))
+			}
+			mockRepos.groups.AssertExpectations(t)
+			mockRepos.bucketItems.AssertExpectations(t)
+			if tt.expectedStatus != http.StatusOK {
+				mockRepos.groups.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
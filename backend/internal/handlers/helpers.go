@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondItemNotFound writes the 404 response for a missing bucket list item
+func respondItemNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, gin.H{
+		"error": gin.H{
+			"code":    "ITEM_NOT_FOUND",
+			"message": "Bucket list item not found",
+		},
+	})
+}
+
+// bindItem looks up the item named by the id path parameter, writing a 400,
+// 404 or 500 response if it cannot
+func bindItem(c *gin.Context, repos repositories.RepositoryManager) (*models.BucketListItem, bool) {
+	itemID := c.Param("id")
+
+	// Validate UUID format
+	validation := models.ValidateUUID(itemID)
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_ITEM_ID",
+				"message": "Invalid item ID format",
+				"details": validation.Errors,
+			},
+		})
+		return nil, false
+	}
+
+	item, err := repos.BucketItems().GetByID(c.Request.Context(), itemID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrItemNotFound) {
+			respondItemNotFound(c)
+			return nil, false
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "ITEM_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve bucket list item",
+				"details": err.Error(),
+			},
+		})
+		return nil, false
+	}
+
+	return item, true
+}
+
+// getMember validates a member ID and retrieves the member, writing a 400,
+// 404 or 500 response if it cannot
+func getMember(c *gin.Context, repos repositories.RepositoryManager, memberID string) (*models.Member, bool) {
+	// Validate member ID format
+	validation := models.ValidateUUID(memberID)
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_MEMBER_ID",
+				"message": "Invalid member ID format",
+				"details": validation.Errors,
+			},
+		})
+		return nil, false
+	}
+
+	member, err := repos.Members().GetByID(c.Request.Context(), memberID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrMemberNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": gin.H{
+					"code":    "MEMBER_NOT_FOUND",
+					"message": "Member not found",
+				},
+			})
+			return nil, false
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "MEMBER_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve member",
+				"details": err.Error(),
+			},
+		})
+		return nil, false
+	}
+
+	return member, true
+}
+
+// requireItemGroupMember retrieves the member acting on an item as
+// getMember does, writing a 403 response if they belong to another group
+func requireItemGroupMember(c *gin.Context, repos repositories.RepositoryManager, item *models.BucketListItem, memberID string) (*models.Member, bool) {
+	member, ok := getMember(c, repos, memberID)
+	if !ok {
+		return nil, false
+	}
+
+	if member.GroupID != item.GroupID {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": gin.H{
+				"code":    "MEMBER_NOT_IN_GROUP",
+				"message": "Member does not belong to the same group as this item",
+			},
+		})
+		return nil, false
+	}
+
+	return member, true
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateJournal handles PUT /api/items/:id/journal, replacing the note,
+// rating and date recorded for a completed item. Any member of the item's
+// group may edit it.
+func (h *BucketItemHandler) UpdateJournal(c *gin.Context) {
+	item, ok := bindItem(c, h.repos)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateJournalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_REQUEST_BODY",
+				"message": "Invalid request body",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	// Validate request
+	validation := req.Validate()
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Request validation failed",
+				"details": validation.Errors,
+			},
+		})
+		return
+	}
+
+	if _, ok := requireItemGroupMember(c, h.repos, item, req.MemberID); !ok {
+		return
+	}
+
+	group, ok := requireUnlockedGroup(c, h.repos, item.GroupID)
+	if !ok {
+		return
+	}
+
+	// The item cannot have happened after today in the group's timezone
+	if validation := req.CompletionJournal.ValidateOccurredOn(time.Now(), group.Location()); !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Request validation failed",
+				"details": validation.Errors,
+			},
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.repos.BucketItems().UpdateCompletionJournal(ctx, item.ID, req.CompletionJournal); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrItemNotFound):
+			respondItemNotFound(c)
+		case errors.Is(err, repositories.ErrItemNotCompleted):
+			c.JSON(http.StatusConflict, gin.H{
+				"error": gin.H{
+					"code":    "ITEM_NOT_COMPLETED",
+					"message": "A journal can only be recorded for a completed item",
+				},
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": gin.H{
+					"code":    "JOURNAL_UPDATE_FAILED",
+					"message": "Failed to update completion journal",
+					"details": err.Error(),
+				},
+			})
+		}
+		return
+	}
+
+	updatedItem, err := h.repos.BucketItems().GetByID(ctx, item.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "UPDATED_ITEM_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve updated item",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"item": updatedItem,
+	})
+}
+
+// GetMemories handles GET /api/groups/:id/memories, returning the group's
+// completed items dated by when they happened, most recent first
+func (h *BucketItemHandler) GetMemories(c *gin.Context) {
+	groupID := c.Param("id")
+
+	// Validate UUID format
+	validation := models.ValidateUUID(groupID)
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_GROUP_ID",
+				"message": "Invalid group ID format",
+				"details": validation.Errors,
+			},
+		})
+		return
+	}
+
+	group, ok := h.getGroup(c, groupID)
+	if !ok {
+		return
+	}
+
+	items, err := h.repos.BucketItems().GetByGroupID(c.Request.Context(), groupID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "ITEMS_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve bucket list items",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"memories": models.BuildMemories(items, group.Location()),
+	})
+}
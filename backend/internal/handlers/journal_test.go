@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// journalRoutes registers the journal routes served by handler
+func journalRoutes(handler *BucketItemHandler) func(gin.IRoutes) {
+	return func(r gin.IRoutes) {
+		r.PATCH("/items/:id/complete", handler.ToggleCompletion)
+		r.PUT("/items/:id/journal", handler.UpdateJournal)
+		r.GET("/groups/:id/memories", handler.GetMemories)
+	}
+}
+
+func TestBucketItemHandler_ToggleCompletionWithJournal(t *testing.T) {
+	groupID := uuid.New().String()
+	itemID := uuid.New().String()
+	memberID := uuid.New().String()
+	item := &models.BucketListItem{ID: itemID, GroupID: groupID, Title: "Skydiving", CreatedBy: &memberID}
+	path := fmt.Sprintf("/items/%s/complete", itemID)
+
+	t.Run("journal recorded with completion", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		note, rating, occurredOn := "Terrifying, then wonderful", 5, "2024-06-01"
+		journal := models.CompletionJournal{CompletionNote: &note, CompletionRating: &rating, OccurredOn: &occurredOn}
+
+		completed := *item
+		completed.Completed = true
+		completed.CompletedBy = &memberID
+		completed.CompletedAt = timePtr(time.Now())
+		completed.CompletionJournal = journal
+
+		mockRepos.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil).Once()
+		mockRepos.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: groupID}, nil)
+		mockRepos.groups.On("GetByID", mock.Anything, groupID).Return(&models.Group{ID: groupID, Name: "Trip"}, nil)
+		mockRepos.bucketItems.On("ToggleCompletion", mock.Anything, itemID, memberID, true).Return(nil)
+		mockRepos.bucketItems.On("UpdateCompletionJournal", mock.Anything, itemID, journal).Return(nil)
+		mockRepos.bucketItems.On("GetByID", mock.Anything, itemID).Return(&completed, nil).Once()
+		mockRepos.expectTx()
+
+		// The note is trimmed before it is stored
+		padded := "  " + note + "  "
+		body := models.ToggleCompletionRequest{
+			Completed: true,
+			MemberID:  memberID,
+			CompletionJournal: models.CompletionJournal{
+				CompletionNote:   &padded,
+				CompletionRating: &rating,
+				OccurredOn:       &occurredOn,
+			},
+		}
+		w := serveTestRequest(journalRoutes(NewBucketItemHandler(mockRepos)), nil, http.MethodPatch, path, body)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Item models.BucketListItem `json:"item"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, journal, response.Item.CompletionJournal)
+
+		mockRepos.AssertExpectations(t)
+		mockRepos.bucketItems.AssertExpectations(t)
+	})
+
+	t.Run("journal rejected when uncompleting", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		rating := 3
+		body := models.ToggleCompletionRequest{
+			Completed:         false,
+			MemberID:          memberID,
+			CompletionJournal: models.CompletionJournal{CompletionRating: &rating},
+		}
+
+		w := serveTestRequest(journalRoutes(NewBucketItemHandler(mockRepos)), nil, http.MethodPatch, path, body)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "VALIDATION_ERROR", errorCode(t, w))
+		mockRepos.bucketItems.AssertNotCalled(t, "ToggleCompletion", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestBucketItemHandler_UpdateJournal(t *testing.T) {
+	groupID := uuid.New().String()
+	itemID := uuid.New().String()
+	memberID := uuid.New().String()
+	item := &models.BucketListItem{ID: itemID, GroupID: groupID, Title: "Skydiving", CreatedBy: &memberID}
+	path := fmt.Sprintf("/items/%s/journal", itemID)
+	note, rating := "Would do it again", 4
+	journal := models.CompletionJournal{CompletionNote: &note, CompletionRating: &rating}
+	badRating := 9
+
+	tests := []struct {
+		name           string
+		body           interface{}
+		setupMocks     func(*MockRepositoryManager)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name: "journal replaced",
+			body: models.UpdateJournalRequest{MemberID: memberID, CompletionJournal: journal},
+			setupMocks: func(m *MockRepositoryManager) {
+				updated := *item
+				updated.Completed = true
+				updated.CompletionJournal = journal
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil).Once()
+				m.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: groupID}, nil)
+				m.expectOpenGroup(groupID)
+				m.bucketItems.On("UpdateCompletionJournal", mock.Anything, itemID, journal).Return(nil)
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(&updated, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "item not completed",
+			body: models.UpdateJournalRequest{MemberID: memberID, CompletionJournal: journal},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+				m.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: groupID}, nil)
+				m.expectOpenGroup(groupID)
+				m.bucketItems.On("UpdateCompletionJournal", mock.Anything, itemID, journal).
+					Return(fmt.Errorf("%w: %s", repositories.ErrItemNotCompleted, itemID))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedError:  "ITEM_NOT_COMPLETED",
+		},
+		{
+			name: "locked after the deadline",
+			body: models.UpdateJournalRequest{MemberID: memberID, CompletionJournal: journal},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+				m.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: groupID}, nil)
+				m.groups.On("GetByID", mock.Anything, groupID).Return(lockedGroup(groupID), nil)
+			},
+			expectedStatus: http.StatusLocked,
+			expectedError:  "GROUP_LOCKED",
+		},
+		{
+			name: "member of another group",
+			body: models.UpdateJournalRequest{MemberID: memberID, CompletionJournal: journal},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+				m.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: uuid.New().String()}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "MEMBER_NOT_IN_GROUP",
+		},
+		{
+			name: "invalid rating",
+			body: models.UpdateJournalRequest{MemberID: memberID, CompletionJournal: models.CompletionJournal{CompletionRating: &badRating}},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+		{
+			name: "item not found",
+			body: models.UpdateJournalRequest{MemberID: memberID, CompletionJournal: journal},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.bucketItems.On("GetByID", mock.Anything, itemID).
+					Return(nil, fmt.Errorf("%w: %s", repositories.ErrItemNotFound, itemID))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "ITEM_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepos := NewMockRepositoryManager()
+			tt.setupMocks(mockRepos)
+
+			w := serveTestRequest(journalRoutes(NewBucketItemHandler(mockRepos)), nil, http.MethodPut, path, tt.body)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				assert.Equal(t, tt.expectedError, errorCode(t, w))
+			} else {
+				var response struct {
+					Item models.BucketListItem `json:"item"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, journal, response.Item.CompletionJournal)
+			}
+			mockRepos.bucketItems.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBucketItemHandler_GetMemories(t *testing.T) {
+	groupID := uuid.New().String()
+	path := fmt.Sprintf("/groups/%s/memories", groupID)
+
+	t.Run("completed items by date", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		occurredOn := "2023-08-14"
+		completedAt := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+		mockRepos.groups.On("GetByID", mock.Anything, groupID).Return(&models.Group{ID: groupID}, nil)
+		mockRepos.bucketItems.On("GetByGroupID", mock.Anything, groupID).Return([]models.BucketListItem{
+			{ID: "open", GroupID: groupID, Title: "Northern lights"},
+			{ID: "recent", GroupID: groupID, Title: "Skydiving", Completed: true, CompletedAt: &completedAt},
+			{ID: "backdated", GroupID: groupID, Title: "Road trip", Completed: true, CompletedAt: &completedAt,
+				CompletionJournal: models.CompletionJournal{OccurredOn: &occurredOn}},
+		}, nil)
+
+		w := serveTestRequest(journalRoutes(NewBucketItemHandler(mockRepos)), nil, http.MethodGet, path, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Memories []models.Memory `json:"memories"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Memories, 2)
+		assert.Equal(t, "recent", response.Memories[0].ID)
+		assert.Equal(t, "2024-06-01", response.Memories[0].Date)
+		assert.Equal(t, "backdated", response.Memories[1].ID)
+		assert.Equal(t, "2023-08-14", response.Memories[1].Date)
+	})
+
+	t.Run("group not found", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.groups.On("GetByID", mock.Anything, groupID).
+			Return(nil, fmt.Errorf("%w: %s", repositories.ErrGroupNotFound, groupID))
+
+		w := serveTestRequest(journalRoutes(NewBucketItemHandler(mockRepos)), nil, http.MethodGet, path, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "GROUP_NOT_FOUND", errorCode(t, w))
+	})
+}
@@ -2,7 +2,7 @@ package handlers
 
 import (
 	"context"
-	"database/sql"
+	"time"
 
 	"collaborative-bucket-list/internal/models"
 	"collaborative-bucket-list/internal/repositories"
@@ -53,6 +53,49 @@ func (m *MockBucketItemRepository) GetCompletionStats(ctx context.Context, group
 	return args.Int(0), args.Int(1), args.Error(2)
 }
 
+func (m *MockBucketItemRepository) Search(ctx context.Context, groupID, query string, limit int) ([]models.ItemSearchResult, error) {
+	args := m.Called(ctx, groupID, query, limit)
+	return args.Get(0).([]models.ItemSearchResult), args.Error(1)
+}
+
+func (m *MockBucketItemRepository) SearchByUserID(ctx context.Context, userID, query string, limit int) ([]models.ItemSearchResult, error) {
+	args := m.Called(ctx, userID, query, limit)
+	return args.Get(0).([]models.ItemSearchResult), args.Error(1)
+}
+
+func (m *MockBucketItemRepository) List(ctx context.Context, groupID string, opts models.ItemListOptions) (*models.ItemPage, error) {
+	args := m.Called(ctx, groupID, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ItemPage), args.Error(1)
+}
+
+func (m *MockBucketItemRepository) GetCompletionStatsByTag(ctx context.Context, groupID string) ([]models.TagStats, error) {
+	args := m.Called(ctx, groupID)
+	return args.Get(0).([]models.TagStats), args.Error(1)
+}
+
+func (m *MockBucketItemRepository) UpdateCompletionJournal(ctx context.Context, itemID string, journal models.CompletionJournal) error {
+	args := m.Called(ctx, itemID, journal)
+	return args.Error(0)
+}
+
+func (m *MockBucketItemRepository) SetAssignment(ctx context.Context, itemID, memberID string, assigned bool) error {
+	args := m.Called(ctx, itemID, memberID, assigned)
+	return args.Error(0)
+}
+
+func (m *MockBucketItemRepository) GetAssignedTo(ctx context.Context, memberIDs []string) ([]models.BucketListItem, error) {
+	args := m.Called(ctx, memberIDs)
+	return args.Get(0).([]models.BucketListItem), args.Error(1)
+}
+
+func (m *MockBucketItemRepository) UpdateSchedule(ctx context.Context, itemID string, schedule models.ItemSchedule) error {
+	args := m.Called(ctx, itemID, schedule)
+	return args.Error(0)
+}
+
 type MockGroupRepository struct {
 	mock.Mock
 }
@@ -75,6 +118,11 @@ func (m *MockGroupRepository) GetByUserID(ctx context.Context, userID string) ([
 	return args.Get(0).([]models.Group), args.Error(1)
 }
 
+func (m *MockGroupRepository) GetByDeadlineBetween(ctx context.Context, after, until time.Time) ([]models.Group, error) {
+	args := m.Called(ctx, after, until)
+	return args.Get(0).([]models.Group), args.Error(1)
+}
+
 func (m *MockGroupRepository) Update(ctx context.Context, group *models.Group) error {
 	args := m.Called(ctx, group)
 	return args.Error(0)
@@ -98,6 +146,52 @@ func (m *MockGroupRepository) GetSummariesByUserID(ctx context.Context, userID s
 	return args.Get(0).([]models.GroupSummary), args.Error(1)
 }
 
+func (m *MockGroupRepository) SearchByUserID(ctx context.Context, userID, query string, limit int) ([]models.GroupSearchResult, error) {
+	args := m.Called(ctx, userID, query, limit)
+	return args.Get(0).([]models.GroupSearchResult), args.Error(1)
+}
+
+func (m *MockGroupRepository) ListSummariesByUserID(ctx context.Context, userID string, opts models.GroupSummaryListOptions) (*models.GroupSummaryPage, error) {
+	args := m.Called(ctx, userID, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GroupSummaryPage), args.Error(1)
+}
+
+func (m *MockGroupRepository) GetCalendarTokenHash(ctx context.Context, groupID string) (string, error) {
+	args := m.Called(ctx, groupID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockGroupRepository) EnsureCalendarTokenHash(ctx context.Context, groupID, candidateHash string) (string, error) {
+	args := m.Called(ctx, groupID, candidateHash)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockGroupRepository) RevokeCalendarToken(ctx context.Context, groupID string) error {
+	args := m.Called(ctx, groupID)
+	return args.Error(0)
+}
+
+func (m *MockGroupRepository) GetReminderDays(ctx context.Context, groupID string) ([]int, error) {
+	args := m.Called(ctx, groupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockGroupRepository) SetReminderDays(ctx context.Context, groupID string, days []int) error {
+	args := m.Called(ctx, groupID, days)
+	return args.Error(0)
+}
+
+func (m *MockGroupRepository) GetDueReminders(ctx context.Context, after, until time.Time) ([]models.DueReminder, error) {
+	args := m.Called(ctx, after, until)
+	return args.Get(0).([]models.DueReminder), args.Error(1)
+}
+
 type MockMemberRepository struct {
 	mock.Mock
 }
@@ -148,11 +242,173 @@ func (m *MockMemberRepository) GetCreatorByGroupID(ctx context.Context, groupID
 	return args.Get(0).(*models.Member), args.Error(1)
 }
 
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) Create(ctx context.Context, tag *models.Tag) error {
+	args := m.Called(ctx, tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) GetByID(ctx context.Context, id string) (*models.Tag, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) GetByGroupID(ctx context.Context, groupID string) ([]models.Tag, error) {
+	args := m.Called(ctx, groupID)
+	return args.Get(0).([]models.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) Update(ctx context.Context, tag *models.Tag) error {
+	args := m.Called(ctx, tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) SetItemTags(ctx context.Context, itemID string, tagIDs []string) error {
+	args := m.Called(ctx, itemID, tagIDs)
+	return args.Error(0)
+}
+
+type MockCommentRepository struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepository) Create(ctx context.Context, comment *models.Comment) error {
+	args := m.Called(ctx, comment)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) GetByID(ctx context.Context, id string) (*models.Comment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) GetByItemID(ctx context.Context, itemID string) ([]models.CommentThread, error) {
+	args := m.Called(ctx, itemID)
+	return args.Get(0).([]models.CommentThread), args.Error(1)
+}
+
+func (m *MockCommentRepository) Update(ctx context.Context, comment *models.Comment) error {
+	args := m.Called(ctx, comment)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) CountByGroupID(ctx context.Context, groupID string) (map[string]int, error) {
+	args := m.Called(ctx, groupID)
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+type MockReactionRepository struct {
+	mock.Mock
+}
+
+func (m *MockReactionRepository) SetVote(ctx context.Context, itemID, memberID string, voted bool) error {
+	args := m.Called(ctx, itemID, memberID, voted)
+	return args.Error(0)
+}
+
+func (m *MockReactionRepository) AddReaction(ctx context.Context, reaction *models.Reaction) error {
+	args := m.Called(ctx, reaction)
+	return args.Error(0)
+}
+
+func (m *MockReactionRepository) RemoveReaction(ctx context.Context, itemID, memberID, emoji string) error {
+	args := m.Called(ctx, itemID, memberID, emoji)
+	return args.Error(0)
+}
+
+func (m *MockReactionRepository) GetByItemID(ctx context.Context, itemID string) (*models.ItemReactions, error) {
+	args := m.Called(ctx, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ItemReactions), args.Error(1)
+}
+
+func (m *MockReactionRepository) GetByGroupID(ctx context.Context, groupID string) (map[string]models.ItemReactions, error) {
+	args := m.Called(ctx, groupID)
+	return args.Get(0).(map[string]models.ItemReactions), args.Error(1)
+}
+
+type MockAttachmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentRepository) Create(ctx context.Context, attachment *models.Attachment) error {
+	args := m.Called(ctx, attachment)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) GetByID(ctx context.Context, id string) (*models.Attachment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) GetByItemID(ctx context.Context, itemID string) ([]models.Attachment, error) {
+	args := m.Called(ctx, itemID)
+	return args.Get(0).([]models.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) PendingBlobDeletions(ctx context.Context, limit int) ([]string, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) ClearBlobDeletions(ctx context.Context, keys []string) error {
+	args := m.Called(ctx, keys)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) GetByGroupID(ctx context.Context, groupID string) (map[string][]models.Attachment, error) {
+	args := m.Called(ctx, groupID)
+	return args.Get(0).(map[string][]models.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) GetPendingProcessing(ctx context.Context, limit int) ([]models.Attachment, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]models.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) UpdateProcessing(ctx context.Context, attachment *models.Attachment) error {
+	args := m.Called(ctx, attachment)
+	return args.Error(0)
+}
+
 type MockRepositoryManager struct {
 	mock.Mock
 	groups      *MockGroupRepository
 	members     *MockMemberRepository
 	bucketItems *MockBucketItemRepository
+	tags        *MockTagRepository
+	comments    *MockCommentRepository
+	reactions   *MockReactionRepository
+	attachments *MockAttachmentRepository
 }
 
 func NewMockRepositoryManager() *MockRepositoryManager {
@@ -160,6 +416,10 @@ func NewMockRepositoryManager() *MockRepositoryManager {
 		groups:      &MockGroupRepository{},
 		members:     &MockMemberRepository{},
 		bucketItems: &MockBucketItemRepository{},
+		tags:        &MockTagRepository{},
+		comments:    &MockCommentRepository{},
+		reactions:   &MockReactionRepository{},
+		attachments: &MockAttachmentRepository{},
 	}
 }
 
@@ -175,7 +435,50 @@ func (m *MockRepositoryManager) BucketItems() repositories.BucketItemRepository
 	return m.bucketItems
 }
 
-func (m *MockRepositoryManager) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+func (m *MockRepositoryManager) Tags() repositories.TagRepository {
+	return m.tags
+}
+
+func (m *MockRepositoryManager) Comments() repositories.CommentRepository {
+	return m.comments
+}
+
+func (m *MockRepositoryManager) Reactions() repositories.ReactionRepository {
+	return m.reactions
+}
+
+func (m *MockRepositoryManager) Attachments() repositories.AttachmentRepository {
+	return m.attachments
+}
+
+func (m *MockRepositoryManager) WithTx(ctx context.Context, fn func(repos repositories.RepositoryManager) error) error {
 	args := m.Called(ctx, fn)
 	return args.Error(0)
+}
+
+func (m *MockRepositoryManager) WithTxOptions(ctx context.Context, opts repositories.TxOptions, fn func(repos repositories.RepositoryManager) error) error {
+	args := m.Called(ctx, opts, fn)
+	return args.Error(0)
+}
+
+// lockedGroup returns a group whose list locked when its deadline passed
+func lockedGroup(groupID string) *models.Group {
+	passed := time.Now().Add(-time.Hour)
+	return &models.Group{ID: groupID, Name: "Test Group", Deadline: &passed, LockAfterDeadline: true}
+}
+
+// expectOpenGroup expects the group a request changes to be looked up,
+// returning a group that has not locked
+func (m *MockRepositoryManager) expectOpenGroup(groupID string) {
+	m.groups.On("GetByID", mock.Anything, groupID).Return(&models.Group{ID: groupID, Name: "Test Group"}, nil)
+}
+
+// expectTx expects a WithTx call and runs its function against the mock
+// repositories, returning the function's error
+func (m *MockRepositoryManager) expectTx() {
+	call := m.On("WithTx", mock.Anything, mock.Anything)
+	call.Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(repositories.RepositoryManager) error)
+		call.ReturnArguments = mock.Arguments{fn(m)}
+	})
 }
\ No newline at end of file
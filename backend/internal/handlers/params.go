@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// queryParams parses optional query parameters, collecting an error for
+// each one that is malformed
+type queryParams struct {
+	errors []models.ValidationError
+}
+
+func (p *queryParams) invalid(field, message string) {
+	p.errors = append(p.errors, models.ValidationError{Field: field, Message: message})
+}
+
+// boolean parses a true or false parameter
+func (p *queryParams) boolean(c *gin.Context, name string) *bool {
+	raw, ok := c.GetQuery(name)
+	if !ok || raw == "" {
+		return nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		p.invalid(name, name+" must be true or false")
+		return nil
+	}
+	return &value
+}
+
+// float parses a number parameter
+func (p *queryParams) float(c *gin.Context, name string) *float64 {
+	raw, ok := c.GetQuery(name)
+	if !ok || raw == "" {
+		return nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		p.invalid(name, name+" must be a number")
+		return nil
+	}
+	return &value
+}
+
+// time parses an RFC 3339 timestamp, or a date meaning midnight UTC
+func (p *queryParams) time(c *gin.Context, name string) *time.Time {
+	raw, ok := c.GetQuery(name)
+	if !ok || raw == "" {
+		return nil
+	}
+	for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+		if value, err := time.Parse(layout, raw); err == nil {
+			return &value
+		}
+	}
+	p.invalid(name, name+" must be a date (YYYY-MM-DD) or RFC 3339 timestamp")
+	return nil
+}
+
+// limit parses the page size parameter, returning 0 if it is absent
+func (p *queryParams) limit(c *gin.Context) int {
+	raw, ok := c.GetQuery("limit")
+	if !ok || raw == "" {
+		return 0
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 1 {
+		p.invalid("limit", "limit must be a positive whole number")
+		return 0
+	}
+	return value
+}
+
+// validate writes a 400 response listing the malformed parameters and the
+// errors of the parsed options' validation, if there are any
+func (p *queryParams) validate(c *gin.Context, validation models.ValidationResult) bool {
+	errors := append(p.errors, validation.Errors...)
+	if len(errors) == 0 {
+		return true
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": gin.H{
+			"code":    "INVALID_QUERY_PARAMETERS",
+			"message": "Invalid query parameters",
+			"details": errors,
+		},
+	})
+	return false
+}
+
+// respondInvalidCursor writes the response for a cursor that was not issued
+// by the same listing and sort
+func respondInvalidCursor(c *gin.Context) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": gin.H{
+			"code":    "INVALID_CURSOR",
+			"message": "Invalid page cursor",
+		},
+	})
+}
+
+// validatedRequest is a request body that can be sanitized and validated
+type validatedRequest interface {
+	Sanitize()
+	Validate() models.ValidationResult
+}
+
+// bindRequest binds, sanitizes and validates a request body, writing a
+// 400 response if it is invalid
+func bindRequest(c *gin.Context, req validatedRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_REQUEST_BODY",
+				"message": "Invalid request body",
+				"details": err.Error(),
+			},
+		})
+		return false
+	}
+
+	// Sanitize input
+	req.Sanitize()
+
+	// Validate request
+	validation := req.Validate()
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Request validation failed",
+				"details": validation.Errors,
+			},
+		})
+		return false
+	}
+
+	return true
+}
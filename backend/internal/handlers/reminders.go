@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"collaborative-bucket-list/internal/middleware"
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetReminders handles GET /api/groups/:id/reminders, returning the days
+// before the group's deadline that its members are reminded of it
+func (h *GroupHandler) GetReminders(c *gin.Context) {
+	groupID, ok := bindReminderGroupID(c)
+	if !ok {
+		return
+	}
+
+	days, err := h.repos.Groups().GetReminderDays(c.Request.Context(), groupID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrGroupNotFound) {
+			respondGroupNotFound(c)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "REMINDER_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve reminders",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reminderDays": days,
+	})
+}
+
+// UpdateReminders handles PUT /api/groups/:id/reminders, replacing the
+// group's reminder schedule. Reminders already sent are not sent again.
+// Requires authentication as the group's creator.
+func (h *GroupHandler) UpdateReminders(c *gin.Context) {
+	user, ok := middleware.RequireAuth(c)
+	if !ok {
+		return
+	}
+
+	groupID, ok := bindReminderGroupID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateRemindersRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	group, err := h.repos.Groups().GetByID(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrGroupNotFound) {
+			respondGroupNotFound(c)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "GROUP_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve group",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	if group.CreatedBy != user.ID {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": gin.H{
+				"code":    "NOT_GROUP_ADMIN",
+				"message": "Only the group's creator can change its settings",
+			},
+		})
+		return
+	}
+
+	err = h.repos.WithTx(ctx, func(txRepos repositories.RepositoryManager) error {
+		return txRepos.Groups().SetReminderDays(ctx, groupID, req.Days)
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrGroupNotFound) {
+			respondGroupNotFound(c)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "REMINDER_UPDATE_FAILED",
+				"message": "Failed to update reminders",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reminderDays": req.Days,
+	})
+}
+
+// bindReminderGroupID reads and validates the group ID of a reminders route
+func bindReminderGroupID(c *gin.Context) (string, bool) {
+	groupID := c.Param("id")
+
+	validation := models.ValidateUUID(groupID)
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_GROUP_ID",
+				"message": "Invalid group ID format",
+				"details": validation.Errors,
+			},
+		})
+		return "", false
+	}
+
+	return groupID, true
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"collaborative-bucket-list/internal/middleware"
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupHandler_GetReminders(t *testing.T) {
+	groupID := uuid.New().String()
+
+	t.Run("returns the schedule", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.groups.On("GetReminderDays", mock.Anything, groupID).Return([]int{14, 2}, nil)
+
+		router := setupTestRouter()
+		router.GET("/groups/:id/reminders", NewGroupHandler(mockRepos).GetReminders)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/groups/%s/reminders", groupID), nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			ReminderDays []int `json:"reminderDays"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []int{14, 2}, response.ReminderDays)
+	})
+
+	t.Run("group not found", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.groups.On("GetReminderDays", mock.Anything, groupID).
+			Return(nil, fmt.Errorf("%w: %s", repositories.ErrGroupNotFound, groupID))
+
+		router := setupTestRouter()
+		router.GET("/groups/:id/reminders", NewGroupHandler(mockRepos).GetReminders)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/groups/%s/reminders", groupID), nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "GROUP_NOT_FOUND", errorCode(t, w))
+	})
+}
+
+func TestGroupHandler_UpdateReminders(t *testing.T) {
+	creator := createTestUser()
+	groupID := uuid.New().String()
+	group := &models.Group{ID: groupID, Name: "Trip", Timezone: "UTC", CreatedBy: creator.ID}
+	path := fmt.Sprintf("/groups/%s/reminders", groupID)
+
+	tests := []struct {
+		name           string
+		user           *middleware.SupabaseUser
+		body           string
+		setupMocks     func(*MockRepositoryManager)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name: "replaces the schedule, deduplicated and furthest first",
+			user: creator,
+			body: `{"days": [1, 14, 1, 3]}`,
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+				m.expectTx()
+				m.groups.On("SetReminderDays", mock.Anything, groupID, []int{14, 3, 1}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "turns reminders off",
+			user: creator,
+			body: `{"days": []}`,
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+				m.expectTx()
+				m.groups.On("SetReminderDays", mock.Anything, groupID, []int{}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "day out of range",
+			user:           creator,
+			body:           `{"days": [0]}`,
+			setupMocks:     func(m *MockRepositoryManager) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+		{
+			name: "not the creator",
+			user: createTestUser(),
+			body: `{"days": [7]}`,
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "NOT_GROUP_ADMIN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepos := NewMockRepositoryManager()
+			tt.setupMocks(mockRepos)
+
+			router := setupTestRouter()
+			router.Use(func(c *gin.Context) {
+				addUserToContext(c, tt.user)
+				c.Next()
+			})
+			router.PUT("/groups/:id/reminders", NewGroupHandler(mockRepos).UpdateReminders)
+
+			req := httptest.NewRequest(http.MethodPut, path, bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				assert.Equal(t, tt.expectedError, errorCode(t, w))
+			}
+			mockRepos.AssertExpectations(t)
+			mockRepos.groups.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateSchedule handles PUT /api/items/:id/schedule, replacing when the
+// item is planned. Any member of the item's group may reschedule it; an
+// empty schedule unschedules it.
+func (h *BucketItemHandler) UpdateSchedule(c *gin.Context) {
+	item, ok := bindItem(c, h.repos)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateScheduleRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	if _, ok := requireItemGroupMember(c, h.repos, item, req.MemberID); !ok {
+		return
+	}
+
+	group, ok := requireUnlockedGroup(c, h.repos, item.GroupID)
+	if !ok {
+		return
+	}
+
+	// The item must be scheduled before the group's deadline
+	if validation := req.ItemSchedule.Validate(group.Deadline); !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Request validation failed",
+				"details": validation.Errors,
+			},
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.repos.BucketItems().UpdateSchedule(ctx, item.ID, req.ItemSchedule); err != nil {
+		if errors.Is(err, repositories.ErrItemNotFound) {
+			respondItemNotFound(c)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "SCHEDULE_UPDATE_FAILED",
+				"message": "Failed to update item schedule",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	updatedItem, err := h.repos.BucketItems().GetByID(ctx, item.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "UPDATED_ITEM_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve updated item",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"item": updatedItem,
+	})
+}
+
+// GetCalendar handles GET /api/groups/:id/calendar?from=&to=, laying out
+// the group's scheduled items by day. from and to are inclusive dates and
+// default to the six weeks starting today.
+func (h *BucketItemHandler) GetCalendar(c *gin.Context) {
+	groupID := c.Param("id")
+
+	// Validate UUID format
+	validation := models.ValidateUUID(groupID)
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_GROUP_ID",
+				"message": "Invalid group ID format",
+				"details": validation.Errors,
+			},
+		})
+		return
+	}
+
+	group, ok := h.getGroup(c, groupID)
+	if !ok {
+		return
+	}
+
+	// Days run from midnight in the group's timezone
+	var params queryParams
+	calendarRange, validation := models.ParseCalendarRange(c.Query("from"), c.Query("to"), time.Now(), group.Location())
+	if !params.validate(c, validation) {
+		return
+	}
+
+	items, err := h.repos.BucketItems().GetByGroupID(c.Request.Context(), groupID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "ITEMS_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve bucket list items",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"calendar": models.BuildCalendar(items, calendarRange),
+	})
+}
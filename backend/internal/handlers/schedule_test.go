@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// scheduleRoutes registers the schedule routes served by handler
+func scheduleRoutes(handler *BucketItemHandler) func(gin.IRoutes) {
+	return func(r gin.IRoutes) {
+		r.POST("/groups/:id/items", handler.CreateItem)
+		r.PUT("/items/:id/schedule", handler.UpdateSchedule)
+		r.GET("/groups/:id/calendar", handler.GetCalendar)
+	}
+}
+
+func TestBucketItemHandler_CreateScheduledItem(t *testing.T) {
+	groupID := uuid.New().String()
+	memberID := uuid.New().String()
+	deadline := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	group := &models.Group{ID: groupID, Name: "Summer", Deadline: &deadline}
+	path := fmt.Sprintf("/groups/%s/items", groupID)
+
+	t.Run("scheduled before the deadline", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		start := time.Date(2026, 7, 4, 18, 0, 0, 0, time.UTC)
+		mockRepos.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+		mockRepos.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: groupID}, nil)
+		mockRepos.bucketItems.On("Create", mock.Anything, mock.MatchedBy(func(item *models.BucketListItem) bool {
+			return item.ScheduledStart != nil && item.ScheduledStart.Equal(start)
+		})).Return(nil)
+
+		body := models.CreateItemRequest{
+			Title:        "Fireworks",
+			MemberID:     memberID,
+			ItemSchedule: models.ItemSchedule{ScheduledStart: &start},
+		}
+		w := serveTestRequest(scheduleRoutes(NewBucketItemHandler(mockRepos)), nil, http.MethodPost, path, body)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockRepos.bucketItems.AssertExpectations(t)
+	})
+
+	t.Run("scheduled after the deadline", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		start := deadline.AddDate(0, 0, 1)
+		mockRepos.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+		mockRepos.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: groupID}, nil)
+
+		body := models.CreateItemRequest{
+			Title:        "Fireworks",
+			MemberID:     memberID,
+			ItemSchedule: models.ItemSchedule{ScheduledStart: &start},
+		}
+		w := serveTestRequest(scheduleRoutes(NewBucketItemHandler(mockRepos)), nil, http.MethodPost, path, body)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "VALIDATION_ERROR", errorCode(t, w))
+		mockRepos.bucketItems.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestBucketItemHandler_UpdateSchedule(t *testing.T) {
+	groupID := uuid.New().String()
+	itemID := uuid.New().String()
+	memberID := uuid.New().String()
+	deadline := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	group := &models.Group{ID: groupID, Name: "Summer", Deadline: &deadline}
+	item := &models.BucketListItem{ID: itemID, GroupID: groupID, Title: "Fireworks", CreatedBy: &memberID}
+	path := fmt.Sprintf("/items/%s/schedule", itemID)
+
+	start := time.Date(2026, 7, 4, 18, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	schedule := models.ItemSchedule{ScheduledStart: &start, ScheduledEnd: &end}
+	late := deadline.Add(time.Hour)
+
+	tests := []struct {
+		name           string
+		body           interface{}
+		setupMocks     func(*MockRepositoryManager)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name: "item scheduled",
+			body: models.UpdateScheduleRequest{MemberID: memberID, ItemSchedule: schedule},
+			setupMocks: func(m *MockRepositoryManager) {
+				scheduled := *item
+				scheduled.ItemSchedule = schedule
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil).Once()
+				m.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: groupID}, nil)
+				m.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+				m.bucketItems.On("UpdateSchedule", mock.Anything, itemID, schedule).Return(nil)
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(&scheduled, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "after the deadline",
+			body: models.UpdateScheduleRequest{MemberID: memberID, ItemSchedule: models.ItemSchedule{ScheduledStart: &start, ScheduledEnd: &late}},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+				m.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: groupID}, nil)
+				m.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+		{
+			name: "locked after the deadline",
+			body: models.UpdateScheduleRequest{MemberID: memberID, ItemSchedule: schedule},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+				m.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: groupID}, nil)
+				m.groups.On("GetByID", mock.Anything, groupID).Return(lockedGroup(groupID), nil)
+			},
+			expectedStatus: http.StatusLocked,
+			expectedError:  "GROUP_LOCKED",
+		},
+		{
+			name: "end before start",
+			body: models.UpdateScheduleRequest{MemberID: memberID, ItemSchedule: models.ItemSchedule{ScheduledStart: &end, ScheduledEnd: &start}},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+		{
+			name: "member of another group",
+			body: models.UpdateScheduleRequest{MemberID: memberID, ItemSchedule: schedule},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+				m.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: uuid.New().String()}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "MEMBER_NOT_IN_GROUP",
+		},
+		{
+			name: "item not found",
+			body: models.UpdateScheduleRequest{MemberID: memberID, ItemSchedule: schedule},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.bucketItems.On("GetByID", mock.Anything, itemID).
+					Return(nil, fmt.Errorf("%w: %s", repositories.ErrItemNotFound, itemID))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "ITEM_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepos := NewMockRepositoryManager()
+			tt.setupMocks(mockRepos)
+
+			w := serveTestRequest(scheduleRoutes(NewBucketItemHandler(mockRepos)), nil, http.MethodPut, path, tt.body)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				assert.Equal(t, tt.expectedError, errorCode(t, w))
+			} else {
+				var response struct {
+					Item models.BucketListItem `json:"item"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				require.NotNil(t, response.Item.ScheduledEnd)
+				assert.True(t, end.Equal(*response.Item.ScheduledEnd))
+			}
+			mockRepos.bucketItems.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBucketItemHandler_GetCalendar(t *testing.T) {
+	groupID := uuid.New().String()
+
+	t.Run("items by day", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		start := time.Date(2026, 7, 4, 18, 0, 0, 0, time.UTC)
+		end := time.Date(2026, 7, 6, 12, 0, 0, 0, time.UTC)
+		mockRepos.groups.On("GetByID", mock.Anything, groupID).Return(&models.Group{ID: groupID}, nil)
+		mockRepos.bucketItems.On("GetByGroupID", mock.Anything, groupID).Return([]models.BucketListItem{
+			{ID: "unscheduled", GroupID: groupID, Title: "Someday"},
+			{ID: "camping", GroupID: groupID, Title: "Camping", ItemSchedule: models.ItemSchedule{ScheduledStart: &start, ScheduledEnd: &end}},
+		}, nil)
+
+		path := fmt.Sprintf("/groups/%s/calendar?from=2026-07-01&to=2026-07-05", groupID)
+		w := serveTestRequest(scheduleRoutes(NewBucketItemHandler(mockRepos)), nil, http.MethodGet, path, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Calendar models.Calendar `json:"calendar"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "2026-07-01", response.Calendar.From)
+		assert.Equal(t, "2026-07-05", response.Calendar.To)
+		assert.Equal(t, "UTC", response.Calendar.Timezone)
+		require.Len(t, response.Calendar.Days, 2)
+		assert.Equal(t, "2026-07-04", response.Calendar.Days[0].Date)
+		assert.Equal(t, "2026-07-05", response.Calendar.Days[1].Date)
+		assert.Equal(t, "camping", response.Calendar.Days[1].Items[0].ID)
+	})
+
+	t.Run("days in the group's timezone", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		start := time.Date(2026, 7, 4, 18, 0, 0, 0, time.UTC)
+		mockRepos.groups.On("GetByID", mock.Anything, groupID).Return(&models.Group{ID: groupID, Timezone: "Asia/Tokyo"}, nil)
+		mockRepos.bucketItems.On("GetByGroupID", mock.Anything, groupID).Return([]models.BucketListItem{
+			{ID: "fireworks", GroupID: groupID, Title: "Fireworks", ItemSchedule: models.ItemSchedule{ScheduledStart: &start}},
+		}, nil)
+
+		path := fmt.Sprintf("/groups/%s/calendar?from=2026-07-01&to=2026-07-07", groupID)
+		w := serveTestRequest(scheduleRoutes(NewBucketItemHandler(mockRepos)), nil, http.MethodGet, path, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Calendar models.Calendar `json:"calendar"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Asia/Tokyo", response.Calendar.Timezone)
+		require.Len(t, response.Calendar.Days, 1)
+		assert.Equal(t, "2026-07-05", response.Calendar.Days[0].Date)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.groups.On("GetByID", mock.Anything, groupID).Return(&models.Group{ID: groupID}, nil)
+
+		path := fmt.Sprintf("/groups/%s/calendar?from=2026-07-05&to=2026-07-01", groupID)
+		w := serveTestRequest(scheduleRoutes(NewBucketItemHandler(mockRepos)), nil, http.MethodGet, path, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_QUERY_PARAMETERS", errorCode(t, w))
+	})
+
+	t.Run("group not found", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.groups.On("GetByID", mock.Anything, groupID).
+			Return(nil, fmt.Errorf("%w: %s", repositories.ErrGroupNotFound, groupID))
+
+		path := fmt.Sprintf("/groups/%s/calendar", groupID)
+		w := serveTestRequest(scheduleRoutes(NewBucketItemHandler(mockRepos)), nil, http.MethodGet, path, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "GROUP_NOT_FOUND", errorCode(t, w))
+	})
+}
@@ -0,0 +1,402 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"collaborative-bucket-list/internal/middleware"
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TagHandler handles tag-related HTTP requests. Tags are managed by the
+// group's creator; any member can tag items.
+type TagHandler struct {
+	repos repositories.RepositoryManager
+}
+
+// NewTagHandler creates a new tag handler
+func NewTagHandler(repos repositories.RepositoryManager) *TagHandler {
+	return &TagHandler{repos: repos}
+}
+
+// GetGroupTags handles GET /api/groups/:id/tags, returning every tag of the
+// group with the completion counts of its items
+func (h *TagHandler) GetGroupTags(c *gin.Context) {
+	groupID := c.Param("id")
+
+	// Validate UUID format
+	validation := models.ValidateUUID(groupID)
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_GROUP_ID",
+				"message": "Invalid group ID format",
+				"details": validation.Errors,
+			},
+		})
+		return
+	}
+
+	if _, ok := h.getGroup(c, groupID); !ok {
+		return
+	}
+
+	stats, err := h.repos.BucketItems().GetCompletionStatsByTag(c.Request.Context(), groupID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "TAGS_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve tags",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	if stats == nil {
+		stats = []models.TagStats{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tags": stats,
+	})
+}
+
+// CreateTag handles POST /api/groups/:id/tags
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	groupID := c.Param("id")
+
+	// Validate UUID format
+	validation := models.ValidateUUID(groupID)
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_GROUP_ID",
+				"message": "Invalid group ID format",
+				"details": validation.Errors,
+			},
+		})
+		return
+	}
+
+	var req models.CreateTagRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	if !h.requireGroupAdmin(c, groupID) {
+		return
+	}
+
+	color := req.Color
+	if color == "" {
+		color = models.DefaultTagColor
+	}
+	tag := &models.Tag{
+		ID:        uuid.New().String(),
+		GroupID:   groupID,
+		Name:      req.Name,
+		Color:     color,
+		CreatedAt: time.Now(),
+	}
+
+	if err := h.repos.Tags().Create(c.Request.Context(), tag); err != nil {
+		respondTagWriteError(c, err, "TAG_CREATION_FAILED", "Failed to create tag")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"tag": tag,
+	})
+}
+
+// UpdateTag handles PUT /api/tags/:id
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	var req models.UpdateTagRequest
+	tag, ok := h.bindTag(c)
+	if !ok || !bindRequest(c, &req) {
+		return
+	}
+
+	if !h.requireGroupAdmin(c, tag.GroupID) {
+		return
+	}
+
+	if req.Name != nil {
+		tag.Name = *req.Name
+	}
+	if req.Color != nil {
+		tag.Color = *req.Color
+	}
+
+	if err := h.repos.Tags().Update(c.Request.Context(), tag); err != nil {
+		respondTagWriteError(c, err, "TAG_UPDATE_FAILED", "Failed to update tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tag": tag,
+	})
+}
+
+// DeleteTag handles DELETE /api/tags/:id, removing the tag from every item
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	tag, ok := h.bindTag(c)
+	if !ok {
+		return
+	}
+
+	if !h.requireGroupAdmin(c, tag.GroupID) {
+		return
+	}
+
+	if err := h.repos.Tags().Delete(c.Request.Context(), tag.ID); err != nil {
+		if errors.Is(err, repositories.ErrTagNotFound) {
+			respondTagNotFound(c)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "TAG_DELETION_FAILED",
+				"message": "Failed to delete tag",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetItemTags handles PUT /api/items/:id/tags, replacing the tags of an
+// item on behalf of a member of its group
+func (h *TagHandler) SetItemTags(c *gin.Context) {
+	itemID := c.Param("id")
+
+	// Validate UUID format
+	validation := models.ValidateUUID(itemID)
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_ITEM_ID",
+				"message": "Invalid item ID format",
+				"details": validation.Errors,
+			},
+		})
+		return
+	}
+
+	var req models.SetItemTagsRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	// Validate member ID format
+	memberValidation := models.ValidateUUID(req.MemberID)
+	if !memberValidation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_MEMBER_ID",
+				"message": "Invalid member ID format",
+				"details": memberValidation.Errors,
+			},
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	item, err := h.repos.BucketItems().GetByID(ctx, itemID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrItemNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": gin.H{
+					"code":    "ITEM_NOT_FOUND",
+					"message": "Bucket list item not found",
+				},
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "ITEM_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve bucket list item",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	if _, ok := requireItemGroupMember(c, h.repos, item, req.MemberID); !ok {
+		return
+	}
+
+	if _, ok := requireUnlockedGroup(c, h.repos, item.GroupID); !ok {
+		return
+	}
+
+	var updatedItem *models.BucketListItem
+	err = h.repos.WithTx(ctx, func(txRepos repositories.RepositoryManager) error {
+		if err := txRepos.Tags().SetItemTags(ctx, itemID, req.TagIDs); err != nil {
+			return err
+		}
+		var err error
+		updatedItem, err = txRepos.BucketItems().GetByID(ctx, itemID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrTagNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": gin.H{
+					"code":    "UNKNOWN_TAG",
+					"message": "Every tag must be a tag of the item's group",
+				},
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "ITEM_TAGGING_FAILED",
+				"message": "Failed to set item tags",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"item": updatedItem,
+	})
+}
+
+// bindTag looks up the tag named by the id path parameter, writing a 400,
+// 404 or 500 response if it cannot
+func (h *TagHandler) bindTag(c *gin.Context) (*models.Tag, bool) {
+	tagID := c.Param("id")
+
+	// Validate UUID format
+	validation := models.ValidateUUID(tagID)
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_TAG_ID",
+				"message": "Invalid tag ID format",
+				"details": validation.Errors,
+			},
+		})
+		return nil, false
+	}
+
+	tag, err := h.repos.Tags().GetByID(c.Request.Context(), tagID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrTagNotFound) {
+			respondTagNotFound(c)
+			return nil, false
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    "TAG_RETRIEVAL_FAILED",
+				"message": "Failed to retrieve tag",
+				"details": err.Error(),
+			},
+		})
+		return nil, false
+	}
+
+	return tag, true
+}
+
+// getGroup retrieves a group, writing a 404 or 500 response if it cannot
+func (h *TagHandler) getGroup(c *gin.Context, groupID string) (*models.Group, bool) {
+	group, err := h.repos.Groups().GetByID(c.Request.Context(), groupID)
+	if err == nil {
+		return group, true
+	}
+
+	if errors.Is(err, repositories.ErrGroupNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": gin.H{
+				"code":    "GROUP_NOT_FOUND",
+				"message": "Group not found",
+			},
+		})
+		return nil, false
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": gin.H{
+			"code":    "GROUP_RETRIEVAL_FAILED",
+			"message": "Failed to retrieve group",
+			"details": err.Error(),
+		},
+	})
+	return nil, false
+}
+
+// requireGroupAdmin checks that the authenticated user created the group,
+// writing a 401, 403, 404 or 500 response if they did not
+func (h *TagHandler) requireGroupAdmin(c *gin.Context, groupID string) bool {
+	user, ok := middleware.RequireAuth(c)
+	if !ok {
+		return false
+	}
+
+	group, ok := h.getGroup(c, groupID)
+	if !ok {
+		return false
+	}
+
+	if group.CreatedBy != user.ID {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": gin.H{
+				"code":    "NOT_GROUP_ADMIN",
+				"message": "Only the group's creator can manage its tags",
+			},
+		})
+		return false
+	}
+
+	return true
+}
+
+func respondTagNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, gin.H{
+		"error": gin.H{
+			"code":    "TAG_NOT_FOUND",
+			"message": "Tag not found",
+		},
+	})
+}
+
+// respondTagWriteError writes the response for a failed tag create or
+// update
+func respondTagWriteError(c *gin.Context, err error, code, message string) {
+	switch {
+	case errors.Is(err, repositories.ErrDuplicateTagName):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": gin.H{
+				"code":    "TAG_NAME_TAKEN",
+				"message": "The group already has a tag with this name",
+			},
+		})
+	case errors.Is(err, repositories.ErrTagNotFound):
+		respondTagNotFound(c)
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{
+				"code":    code,
+				"message": message,
+				"details": err.Error(),
+			},
+		})
+	}
+}
@@ -0,0 +1,363 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"collaborative-bucket-list/internal/middleware"
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock definitions are in mocks_test.go
+
+// tagRoutes registers the tag routes served by handler
+func tagRoutes(handler *TagHandler) func(gin.IRoutes) {
+	return func(r gin.IRoutes) {
+		r.GET("/groups/:id/tags", handler.GetGroupTags)
+		r.POST("/groups/:id/tags", handler.CreateTag)
+		r.PUT("/tags/:id", handler.UpdateTag)
+		r.DELETE("/tags/:id", handler.DeleteTag)
+		r.PUT("/items/:id/tags", handler.SetItemTags)
+	}
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	errorObj, exists := response["error"].(map[string]interface{})
+	require.True(t, exists)
+	return errorObj["code"].(string)
+}
+
+func TestTagHandler_GetGroupTags(t *testing.T) {
+	groupID := uuid.New().String()
+
+	t.Run("tags with progress", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.groups.On("GetByID", mock.Anything, groupID).Return(&models.Group{ID: groupID}, nil)
+		mockRepos.bucketItems.On("GetCompletionStatsByTag", mock.Anything, groupID).Return([]models.TagStats{
+			{
+				Tag:             models.Tag{ID: uuid.New().String(), GroupID: groupID, Name: "food", Color: "#ff0000"},
+				ItemCount:       4,
+				CompletedCount:  1,
+				ProgressPercent: 25,
+			},
+		}, nil)
+
+		w := serveTestRequest(tagRoutes(NewTagHandler(mockRepos)), nil, http.MethodGet, fmt.Sprintf("/groups/%s/tags", groupID), nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Tags []models.TagStats `json:"tags"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Tags, 1)
+		assert.Equal(t, "food", response.Tags[0].Name)
+		assert.Equal(t, 4, response.Tags[0].ItemCount)
+		assert.Equal(t, 25.0, response.Tags[0].ProgressPercent)
+	})
+
+	t.Run("no tags", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.groups.On("GetByID", mock.Anything, groupID).Return(&models.Group{ID: groupID}, nil)
+		mockRepos.bucketItems.On("GetCompletionStatsByTag", mock.Anything, groupID).Return([]models.TagStats(nil), nil)
+
+		w := serveTestRequest(tagRoutes(NewTagHandler(mockRepos)), nil, http.MethodGet, fmt.Sprintf("/groups/%s/tags", groupID), nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"tags": []}`, w.Body.String())
+	})
+
+	t.Run("group not found", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.groups.On("GetByID", mock.Anything, groupID).
+			Return(nil, fmt.Errorf("%w: %s", repositories.ErrGroupNotFound, groupID))
+
+		w := serveTestRequest(tagRoutes(NewTagHandler(mockRepos)), nil, http.MethodGet, fmt.Sprintf("/groups/%s/tags", groupID), nil)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "GROUP_NOT_FOUND", errorCode(t, w))
+	})
+
+	t.Run("invalid group ID", func(t *testing.T) {
+		w := serveTestRequest(tagRoutes(NewTagHandler(NewMockRepositoryManager())), nil, http.MethodGet, "/groups/not-a-uuid/tags", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_GROUP_ID", errorCode(t, w))
+	})
+}
+
+func TestTagHandler_CreateTag(t *testing.T) {
+	admin := createTestUser()
+	groupID := uuid.New().String()
+	group := &models.Group{ID: groupID, Name: "Trip", CreatedAt: time.Now(), CreatedBy: admin.ID}
+	path := fmt.Sprintf("/groups/%s/tags", groupID)
+
+	tests := []struct {
+		name           string
+		user           *middleware.SupabaseUser
+		body           interface{}
+		setupMocks     func(*MockRepositoryManager)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name: "created with the default color",
+			user: admin,
+			body: models.CreateTagRequest{Name: " Food "},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+				m.tags.On("Create", mock.Anything, mock.MatchedBy(func(tag *models.Tag) bool {
+					return tag.GroupID == groupID && tag.Name == "Food" && tag.Color == models.DefaultTagColor
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "duplicate name",
+			user: admin,
+			body: models.CreateTagRequest{Name: "food", Color: "#ff0000"},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+				m.tags.On("Create", mock.Anything, mock.AnythingOfType("*models.Tag")).
+					Return(fmt.Errorf("failed to create tag: %w", repositories.ErrDuplicateTagName))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedError:  "TAG_NAME_TAKEN",
+		},
+		{
+			name: "not the group's creator",
+			user: createTestUser(),
+			body: models.CreateTagRequest{Name: "food"},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "NOT_GROUP_ADMIN",
+		},
+		{
+			name:           "not authenticated",
+			body:           models.CreateTagRequest{Name: "food"},
+			setupMocks:     func(m *MockRepositoryManager) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid color",
+			user:           admin,
+			body:           models.CreateTagRequest{Name: "food", Color: "red"},
+			setupMocks:     func(m *MockRepositoryManager) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+		{
+			name:           "missing name",
+			user:           admin,
+			body:           `{"color": "#ff0000"}`,
+			setupMocks:     func(m *MockRepositoryManager) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_REQUEST_BODY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepos := NewMockRepositoryManager()
+			tt.setupMocks(mockRepos)
+
+			w := serveTestRequest(tagRoutes(NewTagHandler(mockRepos)), tt.user, http.MethodPost, path, tt.body)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				assert.Equal(t, tt.expectedError, errorCode(t, w))
+			}
+			mockRepos.tags.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTagHandler_UpdateAndDeleteTag(t *testing.T) {
+	admin := createTestUser()
+	groupID := uuid.New().String()
+	group := &models.Group{ID: groupID, Name: "Trip", CreatedAt: time.Now(), CreatedBy: admin.ID}
+	tagID := uuid.New().String()
+	newTag := func() *models.Tag {
+		return &models.Tag{ID: tagID, GroupID: groupID, Name: "food", Color: "#ff0000"}
+	}
+
+	t.Run("rename keeps the color", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+		mockRepos.tags.On("GetByID", mock.Anything, tagID).Return(newTag(), nil)
+		mockRepos.tags.On("Update", mock.Anything, mock.MatchedBy(func(tag *models.Tag) bool {
+			return tag.Name == "Eating out" && tag.Color == "#ff0000"
+		})).Return(nil)
+
+		w := serveTestRequest(tagRoutes(NewTagHandler(mockRepos)), admin, http.MethodPut, "/tags/"+tagID, `{"name": "Eating out"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockRepos.tags.AssertExpectations(t)
+	})
+
+	t.Run("empty update", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.tags.On("GetByID", mock.Anything, tagID).Return(newTag(), nil)
+
+		w := serveTestRequest(tagRoutes(NewTagHandler(mockRepos)), admin, http.MethodPut, "/tags/"+tagID, `{}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "VALIDATION_ERROR", errorCode(t, w))
+	})
+
+	t.Run("tag not found", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.tags.On("GetByID", mock.Anything, tagID).
+			Return(nil, fmt.Errorf("%w: %s", repositories.ErrTagNotFound, tagID))
+
+		w := serveTestRequest(tagRoutes(NewTagHandler(mockRepos)), admin, http.MethodPut, "/tags/"+tagID, `{"name": "x"}`)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "TAG_NOT_FOUND", errorCode(t, w))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+		mockRepos.tags.On("GetByID", mock.Anything, tagID).Return(newTag(), nil)
+		mockRepos.tags.On("Delete", mock.Anything, tagID).Return(nil)
+
+		w := serveTestRequest(tagRoutes(NewTagHandler(mockRepos)), admin, http.MethodDelete, "/tags/"+tagID, nil)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		mockRepos.tags.AssertExpectations(t)
+	})
+
+	t.Run("delete by another user", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockRepos.groups.On("GetByID", mock.Anything, groupID).Return(group, nil)
+		mockRepos.tags.On("GetByID", mock.Anything, tagID).Return(newTag(), nil)
+
+		w := serveTestRequest(tagRoutes(NewTagHandler(mockRepos)), createTestUser(), http.MethodDelete, "/tags/"+tagID, nil)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockRepos.tags.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTagHandler_SetItemTags(t *testing.T) {
+	groupID := uuid.New().String()
+	itemID := uuid.New().String()
+	memberID := uuid.New().String()
+	tagID := uuid.New().String()
+	item := &models.BucketListItem{ID: itemID, GroupID: groupID, Title: "Visit Paris", CreatedBy: &memberID}
+	path := fmt.Sprintf("/items/%s/tags", itemID)
+
+	tests := []struct {
+		name           string
+		body           interface{}
+		setupMocks     func(*MockRepositoryManager)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name: "tags replaced",
+			body: models.SetItemTagsRequest{TagIDs: []string{tagID}, MemberID: memberID},
+			setupMocks: func(m *MockRepositoryManager) {
+				tagged := *item
+				tagged.TagIDs = []string{tagID}
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil).Once()
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(&tagged, nil).Once()
+				m.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: groupID}, nil)
+				m.expectOpenGroup(groupID)
+				m.tags.On("SetItemTags", mock.Anything, itemID, []string{tagID}).Return(nil)
+				m.expectTx()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "tag of another group",
+			body: models.SetItemTagsRequest{TagIDs: []string{tagID}, MemberID: memberID},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+				m.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: groupID}, nil)
+				m.expectOpenGroup(groupID)
+				m.tags.On("SetItemTags", mock.Anything, itemID, []string{tagID}).
+					Return(fmt.Errorf("failed to set item tags: %w", repositories.ErrTagNotFound))
+				m.expectTx()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "UNKNOWN_TAG",
+		},
+		{
+			name: "locked after the deadline",
+			body: models.SetItemTagsRequest{TagIDs: []string{tagID}, MemberID: memberID},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+				m.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: groupID}, nil)
+				m.groups.On("GetByID", mock.Anything, groupID).Return(lockedGroup(groupID), nil)
+			},
+			expectedStatus: http.StatusLocked,
+			expectedError:  "GROUP_LOCKED",
+		},
+		{
+			name: "member of another group",
+			body: models.SetItemTagsRequest{TagIDs: []string{tagID}, MemberID: memberID},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.bucketItems.On("GetByID", mock.Anything, itemID).Return(item, nil)
+				m.members.On("GetByID", mock.Anything, memberID).Return(&models.Member{ID: memberID, GroupID: uuid.New().String()}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "MEMBER_NOT_IN_GROUP",
+		},
+		{
+			name: "item not found",
+			body: models.SetItemTagsRequest{MemberID: memberID},
+			setupMocks: func(m *MockRepositoryManager) {
+				m.bucketItems.On("GetByID", mock.Anything, itemID).
+					Return(nil, fmt.Errorf("%w: %s", repositories.ErrItemNotFound, itemID))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "ITEM_NOT_FOUND",
+		},
+		{
+			name:           "invalid tag ID",
+			body:           models.SetItemTagsRequest{TagIDs: []string{"food"}, MemberID: memberID},
+			setupMocks:     func(m *MockRepositoryManager) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepos := NewMockRepositoryManager()
+			tt.setupMocks(mockRepos)
+
+			w := serveTestRequest(tagRoutes(NewTagHandler(mockRepos)), nil, http.MethodPut, path, tt.body)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				assert.Equal(t, tt.expectedError, errorCode(t, w))
+			} else {
+				var response struct {
+					Item models.BucketListItem `json:"item"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, []string{tagID}, response.Item.TagIDs)
+			}
+			mockRepos.AssertExpectations(t)
+			mockRepos.tags.AssertExpectations(t)
+		})
+	}
+}
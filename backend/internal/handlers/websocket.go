@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"collaborative-bucket-list/internal/models"
 	"collaborative-bucket-list/internal/repositories"
 	"collaborative-bucket-list/internal/websocket"
 	"net/http"
@@ -53,6 +54,44 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 	websocket.ServeWS(h.hub, c.Writer, c.Request, roomID, memberID, h.eventHandler)
 }
 
+// HandleEventStream handles GET /api/groups/:id/events, a read-only
+// Server-Sent Events fallback for clients that cannot open a WebSocket.
+// Mutations still go through the REST endpoints.
+func (h *WebSocketHandler) HandleEventStream(c *gin.Context) {
+	groupID := c.Param("id")
+	if groupID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "MISSING_GROUP_ID",
+				"message": "Group ID is required",
+			},
+		})
+		return
+	}
+
+	// Validate UUID format
+	validation := models.ValidateUUID(groupID)
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_GROUP_ID",
+				"message": "Invalid group ID format",
+				"details": validation.Errors,
+			},
+		})
+		return
+	}
+
+	// EventSource sends Last-Event-ID when it reconnects; the query parameter
+	// lets a fresh page load resume from a stored ID
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("lastEventId")
+	}
+
+	websocket.ServeSSE(h.hub, c.Writer, c.Request, groupID, c.Query("memberId"), lastEventID)
+}
+
 // GetRoomStats returns statistics about active rooms and connections
 func (h *WebSocketHandler) GetRoomStats(c *gin.Context) {
 	roomID := c.Param("id")
@@ -91,4 +130,10 @@ func (h *WebSocketHandler) GetAllRoomStats(c *gin.Context) {
 		"activeRooms": roomStats,
 		"totalRooms":  len(activeRooms),
 	})
-}
\ No newline at end of file
+}
+
+// GetProtocol handles GET /api/ws/protocol and publishes the WebSocket
+// protocol version, features and a JSON Schema for every event
+func (h *WebSocketHandler) GetProtocol(c *gin.Context) {
+	c.JSON(http.StatusOK, websocket.DescribeProtocol())
+}
@@ -12,7 +12,7 @@ import (
 
 func TestNewWebSocketHandler(t *testing.T) {
 	hub := websocket.NewHub()
-	handler := NewWebSocketHandler(hub)
+	handler := NewWebSocketHandler(hub, NewMockRepositoryManager())
 	
 	assert.NotNil(t, handler)
 	assert.Equal(t, hub, handler.hub)
@@ -20,7 +20,7 @@ func TestNewWebSocketHandler(t *testing.T) {
 
 func TestWebSocketHandler_GetRoomStats(t *testing.T) {
 	hub := websocket.NewHub()
-	handler := NewWebSocketHandler(hub)
+	handler := NewWebSocketHandler(hub, NewMockRepositoryManager())
 	
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
@@ -38,7 +38,7 @@ func TestWebSocketHandler_GetRoomStats(t *testing.T) {
 
 func TestWebSocketHandler_GetRoomStats_MissingRoomID(t *testing.T) {
 	hub := websocket.NewHub()
-	handler := NewWebSocketHandler(hub)
+	handler := NewWebSocketHandler(hub, NewMockRepositoryManager())
 	
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
@@ -55,7 +55,7 @@ func TestWebSocketHandler_GetRoomStats_MissingRoomID(t *testing.T) {
 
 func TestWebSocketHandler_GetAllRoomStats(t *testing.T) {
 	hub := websocket.NewHub()
-	handler := NewWebSocketHandler(hub)
+	handler := NewWebSocketHandler(hub, NewMockRepositoryManager())
 	
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
@@ -68,4 +68,22 @@ func TestWebSocketHandler_GetAllRoomStats(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Contains(t, w.Body.String(), "activeRooms")
 	assert.Contains(t, w.Body.String(), "totalRooms")
-}
\ No newline at end of file
+}
+
+func TestWebSocketHandler_GetProtocol(t *testing.T) {
+	hub := websocket.NewHub()
+	handler := NewWebSocketHandler(hub, NewMockRepositoryManager())
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ws/protocol", handler.GetProtocol)
+
+	req, _ := http.NewRequest("GET", "/ws/protocol", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "protocolVersion")
+	assert.Contains(t, w.Body.String(), `"type":"hello"`)
+	assert.Contains(t, w.Body.String(), "schema")
+}
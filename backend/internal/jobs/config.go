@@ -0,0 +1,137 @@
+package jobs
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config holds the worker pool's size, timings and retry policy
+type Config struct {
+	// Number of jobs a server runs at once
+	Workers int
+
+	// How often idle workers look for runnable jobs. Jobs enqueued on the
+	// same server start without waiting for the next poll.
+	PollInterval time.Duration
+
+	// How long a worker holds a job. Handlers are cancelled when it runs
+	// out, and a job held by a worker that died runs again after it.
+	Lease time.Duration
+
+	// Attempts of a job before it dies, unless enqueued with its own
+	MaxAttempts int
+
+	// Delay before the first retry, doubling with every further attempt up
+	// to BackoffMax
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+
+	// How long completed jobs, and so their unique keys, are kept
+	Retention time.Duration
+
+	// How often expired locks are rescued and completed jobs pruned
+	MaintenanceInterval time.Duration
+}
+
+// DefaultConfig returns the default job queue configuration
+func DefaultConfig() *Config {
+	return &Config{
+		Workers:             4,
+		PollInterval:        time.Second,
+		Lease:               5 * time.Minute,
+		MaxAttempts:         10,
+		BackoffBase:         10 * time.Second,
+		BackoffMax:          time.Hour,
+		Retention:           7 * 24 * time.Hour,
+		MaintenanceInterval: time.Minute,
+	}
+}
+
+// LoadConfigFromEnv loads job queue configuration from environment
+// variables, falling back to DefaultConfig for anything unset or malformed
+func LoadConfigFromEnv() *Config {
+	config := DefaultConfig()
+
+	config.Workers = getEnvInt("JOB_WORKERS", config.Workers)
+	config.PollInterval = getEnvDuration("JOB_POLL_INTERVAL", config.PollInterval)
+	config.Lease = getEnvDuration("JOB_LEASE", config.Lease)
+	config.MaxAttempts = getEnvInt("JOB_MAX_ATTEMPTS", config.MaxAttempts)
+	config.BackoffBase = getEnvDuration("JOB_BACKOFF_BASE", config.BackoffBase)
+	config.BackoffMax = getEnvDuration("JOB_BACKOFF_MAX", config.BackoffMax)
+	config.Retention = getEnvDuration("JOB_RETENTION", config.Retention)
+	config.MaintenanceInterval = getEnvDuration("JOB_MAINTENANCE_INTERVAL", config.MaintenanceInterval)
+
+	return config
+}
+
+// Validate checks that the configuration is usable
+func (c *Config) Validate() error {
+	if c.Workers <= 0 {
+		return fmt.Errorf("job workers must be positive")
+	}
+	if c.PollInterval <= 0 {
+		return fmt.Errorf("job poll interval must be positive")
+	}
+	if c.Lease <= 0 {
+		return fmt.Errorf("job lease must be positive")
+	}
+	if c.MaxAttempts <= 0 {
+		return fmt.Errorf("job max attempts must be positive")
+	}
+	if c.BackoffBase <= 0 || c.BackoffMax < c.BackoffBase {
+		return fmt.Errorf("job backoff base (%s) must be positive and not above the maximum (%s)", c.BackoffBase, c.BackoffMax)
+	}
+	if c.Retention <= 0 {
+		return fmt.Errorf("job retention must be positive")
+	}
+	if c.MaintenanceInterval <= 0 {
+		return fmt.Errorf("job maintenance interval must be positive")
+	}
+	return nil
+}
+
+// Backoff returns the delay before retrying a job that failed its attempt'th
+// attempt: BackoffBase doubled for every earlier attempt, capped at
+// BackoffMax, plus up to a tenth more so jobs failing together spread out
+func (c *Config) Backoff(attempt int) time.Duration {
+	delay := c.BackoffBase
+	for i := 1; i < attempt && delay < c.BackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > c.BackoffMax {
+		delay = c.BackoffMax
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/10+1))
+}
+
+// getEnvDuration parses a duration such as "30s" from the environment
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid %s %q, using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return d
+}
+
+// getEnvInt parses an integer from the environment
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid %s %q, using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return n
+}
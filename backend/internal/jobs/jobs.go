@@ -0,0 +1,165 @@
+// Package jobs runs background work from a durable queue. Jobs are stored in
+// the database, so they survive restarts, and are claimed by a pool of
+// workers on every server: PostgreSQL hands each job to one worker with
+// SELECT ... FOR UPDATE SKIP LOCKED, so any number of API replicas can share
+// the queue.
+//
+// A job has a kind, naming its handler, and JSON arguments. Jobs can be
+// scheduled to run later and given a unique key so the same work is not
+// queued twice. Failed jobs are retried with exponential backoff; a job
+// that keeps failing is dead and kept, as a dead letter queue, until it is
+// requeued.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// State is where a job is in its life
+type State string
+
+// Job states. A scheduled job runs once its RunAt has come; a running job is
+// held by one worker until LockedUntil; completed and dead jobs are
+// finished, dead ones having failed MaxAttempts times.
+const (
+	StateScheduled State = "scheduled"
+	StateRunning   State = "running"
+	StateCompleted State = "completed"
+	StateDead      State = "dead"
+)
+
+// Errors returned by stores. Use errors.Is to test for them.
+var (
+	// ErrJobNotFound is returned when a job does not exist
+	ErrJobNotFound = errors.New("job not found")
+
+	// ErrDuplicateJob is returned when enqueuing a job whose unique key is
+	// taken by another job of its kind
+	ErrDuplicateJob = errors.New("a job with this unique key already exists")
+
+	// ErrLockLost is returned when finishing a job the worker no longer
+	// holds, because its lock expired and the job was handed to another
+	ErrLockLost = errors.New("job is no longer held by this worker")
+)
+
+// Job is a unit of background work
+type Job struct {
+	ID   string          `json:"id"`
+	Kind string          `json:"kind"`
+	Args json.RawMessage `json:"args"`
+	// State is where the job is in its life
+	State State `json:"state"`
+	// UniqueKey, if set, is not shared with any other job of the same kind
+	UniqueKey *string `json:"uniqueKey,omitempty"`
+	// Attempts counts the runs started so far, including the current one
+	Attempts    int       `json:"attempts"`
+	MaxAttempts int       `json:"maxAttempts"`
+	RunAt       time.Time `json:"runAt"`
+	// LockedBy and LockedUntil name the worker holding a running job and
+	// when its hold expires
+	LockedBy    *string    `json:"lockedBy,omitempty"`
+	LockedUntil *time.Time `json:"lockedUntil,omitempty"`
+	// LastError is the error of the latest failed attempt
+	LastError  *string    `json:"lastError,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// Args are the arguments of a kind of job. They are stored as JSON, so only
+// exported fields survive.
+type Args interface {
+	// Kind names the handler of the job. It must not depend on the
+	// arguments' values, as it is read from the zero value.
+	Kind() string
+}
+
+// EnqueueOptions tune how a job is queued. The zero value runs the job as
+// soon as possible with the queue's default attempts.
+type EnqueueOptions struct {
+	// UniqueKey keeps a second job of the same kind with this key from
+	// being queued while the first is kept, including after it finishes
+	UniqueKey string
+	// RunAt delays the job until this time
+	RunAt time.Time
+	// MaxAttempts overrides the queue's default number of attempts
+	MaxAttempts int
+}
+
+// Store keeps the jobs of a queue. Claim hands each runnable job to a single
+// worker, even when several servers share the store.
+type Store interface {
+	// Insert adds a scheduled job. Returns ErrDuplicateJob if another job
+	// of its kind has its unique key.
+	Insert(ctx context.Context, job *Job) error
+
+	// Get retrieves a job by ID
+	Get(ctx context.Context, id string) (*Job, error)
+
+	// Claim hands up to limit jobs of the given kinds whose run time has
+	// come to worker until lockedUntil, soonest first. Claimed jobs are
+	// running and have their attempt counted.
+	Claim(ctx context.Context, worker string, kinds []string, now, lockedUntil time.Time, limit int) ([]Job, error)
+
+	// Complete marks a job the worker holds completed. Returns ErrLockLost
+	// if the worker no longer holds it.
+	Complete(ctx context.Context, job *Job, now time.Time) error
+
+	// Retry schedules a job the worker holds to run again at runAt after a
+	// failed attempt. Returns ErrLockLost if the worker no longer holds it.
+	Retry(ctx context.Context, job *Job, runAt time.Time, lastError string) error
+
+	// Kill moves a job the worker holds to the dead letter queue. Returns
+	// ErrLockLost if the worker no longer holds it.
+	Kill(ctx context.Context, job *Job, now time.Time, lastError string) error
+
+	// RescueExpired reschedules running jobs whose lock expired by now, as
+	// their worker stopped without finishing them, and returns how many it
+	// found. Jobs that have used all their attempts die instead.
+	RescueExpired(ctx context.Context, now time.Time) (int, error)
+
+	// Prune deletes jobs completed before before, freeing their unique
+	// keys, and returns how many it deleted
+	Prune(ctx context.Context, before time.Time) (int, error)
+
+	// ListDead retrieves up to limit dead jobs, most recently failed first
+	ListDead(ctx context.Context, limit int) ([]Job, error)
+
+	// Requeue schedules a dead job to run at now with fresh attempts.
+	// Returns ErrJobNotFound if there is no dead job with the ID.
+	Requeue(ctx context.Context, id string, now time.Time) error
+}
+
+// lockLostMessage is the error recorded on jobs rescued by RescueExpired
+const lockLostMessage = "worker stopped before finishing the job"
+
+// permanentError marks a handler error that retrying will not fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks a handler error as one that retrying will not fix, such as
+// a job about a deleted record, so the job dies without using its
+// remaining attempts
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
@@ -0,0 +1,272 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps jobs in process memory, for servers running without a
+// database and for tests. Jobs are lost on exit.
+type MemoryStore struct {
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+// NewMemoryStore creates an empty in-memory job store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{jobs: make(map[string]*Job)}
+}
+
+// cloneJob copies a job so callers cannot change the stored one
+func cloneJob(job *Job) Job {
+	clone := *job
+	clone.Args = append([]byte(nil), job.Args...)
+	if job.UniqueKey != nil {
+		key := *job.UniqueKey
+		clone.UniqueKey = &key
+	}
+	if job.LockedBy != nil {
+		worker := *job.LockedBy
+		clone.LockedBy = &worker
+	}
+	if job.LockedUntil != nil {
+		until := *job.LockedUntil
+		clone.LockedUntil = &until
+	}
+	if job.LastError != nil {
+		message := *job.LastError
+		clone.LastError = &message
+	}
+	if job.FinishedAt != nil {
+		finished := *job.FinishedAt
+		clone.FinishedAt = &finished
+	}
+	return clone
+}
+
+// Insert adds a scheduled job
+func (s *MemoryStore) Insert(ctx context.Context, job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.ID]; exists {
+		return fmt.Errorf("job %s already exists", job.ID)
+	}
+	if job.UniqueKey != nil {
+		for _, other := range s.jobs {
+			if other.Kind == job.Kind && other.UniqueKey != nil && *other.UniqueKey == *job.UniqueKey {
+				return fmt.Errorf("%w: %s %s", ErrDuplicateJob, job.Kind, *job.UniqueKey)
+			}
+		}
+	}
+
+	stored := cloneJob(job)
+	stored.State = StateScheduled
+	s.jobs[job.ID] = &stored
+	return nil
+}
+
+// Get retrieves a job by ID
+func (s *MemoryStore) Get(ctx context.Context, id string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, exists := s.jobs[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	clone := cloneJob(job)
+	return &clone, nil
+}
+
+// Claim hands up to limit runnable jobs of the given kinds to worker
+func (s *MemoryStore) Claim(ctx context.Context, worker string, kinds []string, now, lockedUntil time.Time, limit int) ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wanted := make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		wanted[kind] = true
+	}
+
+	var runnable []*Job
+	for _, job := range s.jobs {
+		if job.State == StateScheduled && !job.RunAt.After(now) && wanted[job.Kind] {
+			runnable = append(runnable, job)
+		}
+	}
+	sortJobs(runnable)
+	if len(runnable) > limit {
+		runnable = runnable[:limit]
+	}
+
+	claimed := make([]Job, 0, len(runnable))
+	for _, job := range runnable {
+		job.State = StateRunning
+		job.Attempts++
+		job.LockedBy = &worker
+		until := lockedUntil
+		job.LockedUntil = &until
+		claimed = append(claimed, cloneJob(job))
+	}
+	return claimed, nil
+}
+
+// sortJobs orders jobs by run time, then ID
+func sortJobs(jobs []*Job) {
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].RunAt.Equal(jobs[j].RunAt) {
+			return jobs[i].RunAt.Before(jobs[j].RunAt)
+		}
+		return jobs[i].ID < jobs[j].ID
+	})
+}
+
+// held returns the stored job if job's worker still holds it
+func (s *MemoryStore) held(job *Job) (*Job, error) {
+	stored, exists := s.jobs[job.ID]
+	if !exists || stored.State != StateRunning || job.LockedBy == nil ||
+		stored.LockedBy == nil || *stored.LockedBy != *job.LockedBy {
+		return nil, fmt.Errorf("%w: %s", ErrLockLost, job.ID)
+	}
+	return stored, nil
+}
+
+// release finishes a held job in state
+func release(job *Job, state State) {
+	job.State = state
+	job.LockedBy = nil
+	job.LockedUntil = nil
+}
+
+// Complete marks a job the worker holds completed
+func (s *MemoryStore) Complete(ctx context.Context, job *Job, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.held(job)
+	if err != nil {
+		return err
+	}
+	release(stored, StateCompleted)
+	stored.FinishedAt = &now
+	return nil
+}
+
+// Retry schedules a job the worker holds to run again at runAt
+func (s *MemoryStore) Retry(ctx context.Context, job *Job, runAt time.Time, lastError string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.held(job)
+	if err != nil {
+		return err
+	}
+	release(stored, StateScheduled)
+	stored.RunAt = runAt
+	stored.LastError = &lastError
+	return nil
+}
+
+// Kill moves a job the worker holds to the dead letter queue
+func (s *MemoryStore) Kill(ctx context.Context, job *Job, now time.Time, lastError string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.held(job)
+	if err != nil {
+		return err
+	}
+	release(stored, StateDead)
+	stored.FinishedAt = &now
+	stored.LastError = &lastError
+	return nil
+}
+
+// RescueExpired reschedules running jobs whose lock expired by now
+func (s *MemoryStore) RescueExpired(ctx context.Context, now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rescued := 0
+	for _, job := range s.jobs {
+		if job.State != StateRunning || job.LockedUntil.After(now) {
+			continue
+		}
+
+		message := lockLostMessage
+		job.LastError = &message
+		if job.Attempts >= job.MaxAttempts {
+			release(job, StateDead)
+			finished := now
+			job.FinishedAt = &finished
+		} else {
+			release(job, StateScheduled)
+			job.RunAt = now
+		}
+		rescued++
+	}
+	return rescued, nil
+}
+
+// Prune deletes jobs completed before before
+func (s *MemoryStore) Prune(ctx context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pruned := 0
+	for id, job := range s.jobs {
+		if job.State == StateCompleted && job.FinishedAt.Before(before) {
+			delete(s.jobs, id)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+// ListDead retrieves up to limit dead jobs, most recently failed first
+func (s *MemoryStore) ListDead(ctx context.Context, limit int) ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var dead []*Job
+	for _, job := range s.jobs {
+		if job.State == StateDead {
+			dead = append(dead, job)
+		}
+	}
+	sort.Slice(dead, func(i, j int) bool {
+		if !dead[i].FinishedAt.Equal(*dead[j].FinishedAt) {
+			return dead[i].FinishedAt.After(*dead[j].FinishedAt)
+		}
+		return dead[i].ID < dead[j].ID
+	})
+	if len(dead) > limit {
+		dead = dead[:limit]
+	}
+
+	jobs := make([]Job, 0, len(dead))
+	for _, job := range dead {
+		jobs = append(jobs, cloneJob(job))
+	}
+	return jobs, nil
+}
+
+// Requeue schedules a dead job to run at now with fresh attempts
+func (s *MemoryStore) Requeue(ctx context.Context, id string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, exists := s.jobs[id]
+	if !exists || job.State != StateDead {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	job.State = StateScheduled
+	job.Attempts = 0
+	job.RunAt = now
+	job.FinishedAt = nil
+	return nil
+}
@@ -0,0 +1,245 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// jobColumns are the columns the stores scan jobs from, in order
+const jobColumns = `id, kind, args, state, unique_key, attempts, max_attempts, run_at,
+	locked_by, locked_until, last_error, created_at, finished_at`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// PostgresStore keeps jobs in PostgreSQL. Workers on any number of servers
+// can share it: claims skip the rows other transactions have locked, so
+// each job goes to one worker without them waiting on each other.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore creates a job store over a migrated PostgreSQL database
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// scanPostgresJob scans a row of jobColumns
+func scanPostgresJob(row rowScanner, job *Job) error {
+	var state string
+	var args []byte
+	err := row.Scan(&job.ID, &job.Kind, &args, &state, &job.UniqueKey, &job.Attempts, &job.MaxAttempts,
+		&job.RunAt, &job.LockedBy, &job.LockedUntil, &job.LastError, &job.CreatedAt, &job.FinishedAt)
+	if err != nil {
+		return err
+	}
+	job.State = State(state)
+	job.Args = args
+	return nil
+}
+
+// queryPostgresJobs runs a query returning jobColumns
+func queryPostgresJobs(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]Job, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []Job
+	for rows.Next() {
+		var job Job
+		if err := scanPostgresJob(rows, &job); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// Insert adds a scheduled job
+func (s *PostgresStore) Insert(ctx context.Context, job *Job) error {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO jobs (id, kind, args, state, unique_key, attempts, max_attempts, run_at, created_at)
+		VALUES ($1, $2, $3, 'scheduled', $4, 0, $5, $6, $7)
+		ON CONFLICT (kind, unique_key) WHERE unique_key IS NOT NULL DO NOTHING`,
+		job.ID, job.Kind, string(job.Args), job.UniqueKey, job.MaxAttempts, job.RunAt, job.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert job: %w", err)
+	}
+	return insertedOrDuplicate(result, job)
+}
+
+// insertedOrDuplicate returns ErrDuplicateJob if an insert skipped its row
+// for a taken unique key
+func insertedOrDuplicate(result sql.Result, job *Job) error {
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to insert job: %w", err)
+	}
+	if inserted == 0 {
+		return fmt.Errorf("%w: %s %s", ErrDuplicateJob, job.Kind, *job.UniqueKey)
+	}
+	return nil
+}
+
+// Get retrieves a job by ID
+func (s *PostgresStore) Get(ctx context.Context, id string) (*Job, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+
+	var job Job
+	err := scanPostgresJob(s.db.QueryRowContext(ctx,
+		`SELECT `+jobColumns+` FROM jobs WHERE id = $1`, id), &job)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	return &job, nil
+}
+
+// Claim hands up to limit runnable jobs of the given kinds to worker. The
+// subquery locks the rows it picks and skips rows locked by other claims,
+// so concurrent workers get different jobs.
+func (s *PostgresStore) Claim(ctx context.Context, worker string, kinds []string, now, lockedUntil time.Time, limit int) ([]Job, error) {
+	jobs, err := queryPostgresJobs(ctx, s.db, `
+		UPDATE jobs
+		SET state = 'running', attempts = attempts + 1, locked_by = $1, locked_until = $2
+		WHERE id IN (
+			SELECT id FROM jobs
+			WHERE state = 'scheduled' AND run_at <= $3 AND kind = ANY($4)
+			ORDER BY run_at, id
+			LIMIT $5
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+jobColumns,
+		worker, lockedUntil, now, pq.Array(kinds), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim jobs: %w", err)
+	}
+
+	// RETURNING does not keep the subquery's order
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].RunAt.Equal(jobs[j].RunAt) {
+			return jobs[i].RunAt.Before(jobs[j].RunAt)
+		}
+		return jobs[i].ID < jobs[j].ID
+	})
+	return jobs, nil
+}
+
+// finishHeld updates a job the worker still holds, or returns ErrLockLost
+func (s *PostgresStore) finishHeld(ctx context.Context, job *Job, set string, args ...interface{}) error {
+	if job.LockedBy == nil {
+		return fmt.Errorf("%w: %s", ErrLockLost, job.ID)
+	}
+	args = append([]interface{}{job.ID, *job.LockedBy}, args...)
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE jobs SET `+set+`, locked_by = NULL, locked_until = NULL
+		WHERE id = $1 AND state = 'running' AND locked_by = $2`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+	return heldOrLost(result, job)
+}
+
+// heldOrLost returns ErrLockLost if an update of a held job matched no row
+func heldOrLost(result sql.Result, job *Job) error {
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+	if updated == 0 {
+		return fmt.Errorf("%w: %s", ErrLockLost, job.ID)
+	}
+	return nil
+}
+
+// Complete marks a job the worker holds completed
+func (s *PostgresStore) Complete(ctx context.Context, job *Job, now time.Time) error {
+	return s.finishHeld(ctx, job, `state = 'completed', finished_at = $3`, now)
+}
+
+// Retry schedules a job the worker holds to run again at runAt
+func (s *PostgresStore) Retry(ctx context.Context, job *Job, runAt time.Time, lastError string) error {
+	return s.finishHeld(ctx, job, `state = 'scheduled', run_at = $3, last_error = $4`, runAt, lastError)
+}
+
+// Kill moves a job the worker holds to the dead letter queue
+func (s *PostgresStore) Kill(ctx context.Context, job *Job, now time.Time, lastError string) error {
+	return s.finishHeld(ctx, job, `state = 'dead', finished_at = $3, last_error = $4`, now, lastError)
+}
+
+// RescueExpired reschedules running jobs whose lock expired by now
+func (s *PostgresStore) RescueExpired(ctx context.Context, now time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE jobs
+		SET state = CASE WHEN attempts >= max_attempts THEN 'dead' ELSE 'scheduled' END,
+			finished_at = CASE WHEN attempts >= max_attempts THEN $1::timestamptz END,
+			run_at = $1, last_error = $2, locked_by = NULL, locked_until = NULL
+		WHERE state = 'running' AND locked_until <= $1`,
+		now, lockLostMessage)
+	if err != nil {
+		return 0, fmt.Errorf("failed to rescue jobs: %w", err)
+	}
+	rescued, err := result.RowsAffected()
+	return int(rescued), err
+}
+
+// Prune deletes jobs completed before before
+func (s *PostgresStore) Prune(ctx context.Context, before time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM jobs WHERE state = 'completed' AND finished_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune jobs: %w", err)
+	}
+	pruned, err := result.RowsAffected()
+	return int(pruned), err
+}
+
+// ListDead retrieves up to limit dead jobs, most recently failed first
+func (s *PostgresStore) ListDead(ctx context.Context, limit int) ([]Job, error) {
+	jobs, err := queryPostgresJobs(ctx, s.db, `
+		SELECT `+jobColumns+` FROM jobs
+		WHERE state = 'dead' AND finished_at IS NOT NULL
+		ORDER BY finished_at DESC, id
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// Requeue schedules a dead job to run at now with fresh attempts
+func (s *PostgresStore) Requeue(ctx context.Context, id string, now time.Time) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE jobs SET state = 'scheduled', attempts = 0, run_at = $2, finished_at = NULL
+		WHERE id = $1 AND state = 'dead'`, id, now)
+	if err != nil {
+		return fmt.Errorf("failed to requeue job: %w", err)
+	}
+	requeued, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to requeue job: %w", err)
+	}
+	if requeued == 0 {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	return nil
+}
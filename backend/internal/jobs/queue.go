@@ -0,0 +1,346 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// finishTimeout bounds recording a job's outcome, which happens even while
+// the queue is stopping
+const finishTimeout = 10 * time.Second
+
+// handlerFunc runs a job whose arguments are still JSON
+type handlerFunc func(ctx context.Context, job *Job) error
+
+// Handler runs a job of the kind its arguments name
+type Handler[T Args] func(ctx context.Context, job *Job, args T) error
+
+// Queue enqueues jobs and runs them with a pool of workers. Every server
+// runs its own queue over the shared store.
+type Queue struct {
+	store  Store
+	config *Config
+	// worker identifies this queue's claims in the store
+	worker string
+
+	mu       sync.RWMutex
+	handlers map[string]handlerFunc
+	started  bool
+
+	// wake tells an idle worker a job was just enqueued
+	wake chan struct{}
+	// stopping is closed by Stop to keep workers from claiming more jobs
+	stopping chan struct{}
+	// cancel cancels running handlers when Stop runs out of time
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	now     func() time.Time
+	backoff func(attempt int) time.Duration
+}
+
+// NewQueue creates a queue over store. Register handlers with Handle before
+// calling Start.
+func NewQueue(store Store, config *Config) *Queue {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return &Queue{
+		store:    store,
+		config:   config,
+		worker:   fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.New().String()[:8]),
+		handlers: make(map[string]handlerFunc),
+		wake:     make(chan struct{}, config.Workers),
+		stopping: make(chan struct{}),
+		now:      time.Now,
+		backoff:  config.Backoff,
+	}
+}
+
+// Handle registers the handler of the jobs whose arguments are of type T.
+// Arguments that do not decode kill the job. It panics if the kind already
+// has a handler or the queue has started.
+func Handle[T Args](q *Queue, handler Handler[T]) {
+	var zero T
+	kind := zero.Kind()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.started {
+		panic("jobs: Handle called after Start")
+	}
+	if _, exists := q.handlers[kind]; exists {
+		panic(fmt.Sprintf("jobs: kind %q already has a handler", kind))
+	}
+
+	q.handlers[kind] = func(ctx context.Context, job *Job) error {
+		var args T
+		if err := json.Unmarshal(job.Args, &args); err != nil {
+			return Permanent(fmt.Errorf("invalid %s job arguments: %w", kind, err))
+		}
+		return handler(ctx, job, args)
+	}
+}
+
+// Enqueue queues a job with args. Returns ErrDuplicateJob if opts.UniqueKey
+// is taken by another job of the same kind.
+func (q *Queue) Enqueue(ctx context.Context, args Args, opts EnqueueOptions) (*Job, error) {
+	encoded, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s job arguments: %w", args.Kind(), err)
+	}
+
+	now := q.now()
+	job := &Job{
+		ID:          uuid.New().String(),
+		Kind:        args.Kind(),
+		Args:        encoded,
+		State:       StateScheduled,
+		MaxAttempts: opts.MaxAttempts,
+		RunAt:       opts.RunAt,
+		CreatedAt:   now,
+	}
+	if opts.UniqueKey != "" {
+		job.UniqueKey = &opts.UniqueKey
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = q.config.MaxAttempts
+	}
+	if job.RunAt.IsZero() || job.RunAt.Before(now) {
+		job.RunAt = now
+	}
+
+	if err := q.store.Insert(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// Start it here rather than on the next poll, if a worker is idle
+	if !job.RunAt.After(now) {
+		select {
+		case q.wake <- struct{}{}:
+		default:
+		}
+	}
+	return job, nil
+}
+
+// Start starts the workers and the maintenance of expired locks and
+// completed jobs. They run until Stop.
+func (q *Queue) Start() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.started {
+		return
+	}
+	q.started = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q.cancel = cancel
+
+	for i := 0; i < q.config.Workers; i++ {
+		q.wg.Add(1)
+		go q.work(ctx)
+	}
+	q.wg.Add(1)
+	go q.maintain(ctx)
+}
+
+// Stop stops claiming jobs and waits for running ones to finish. If ctx is
+// done first, running handlers are cancelled, their jobs are retried
+// later, and ctx's error is returned once the workers have returned.
+func (q *Queue) Stop(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.started {
+		q.mu.Unlock()
+		return nil
+	}
+	select {
+	case <-q.stopping:
+	default:
+		close(q.stopping)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// kinds returns the kinds of job this queue has handlers for, so servers
+// running an older build leave new kinds of job to the servers that know
+// them
+func (q *Queue) kinds() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	kinds := make([]string, 0, len(q.handlers))
+	for kind := range q.handlers {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// work runs jobs one at a time until the queue stops
+func (q *Queue) work(ctx context.Context) {
+	defer q.wg.Done()
+
+	timer := time.NewTimer(q.config.PollInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-q.stopping:
+			return
+		default:
+		}
+
+		ran, err := q.RunNext(ctx)
+		if err != nil {
+			log.Printf("Job worker failed: %v", err)
+		}
+		if ran {
+			continue
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(q.config.PollInterval)
+
+		select {
+		case <-q.stopping:
+			return
+		case <-q.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// RunNext claims one runnable job and runs it, reporting whether there was
+// one. The returned error is about the queue, not the job: a failing job
+// is retried or killed.
+func (q *Queue) RunNext(ctx context.Context) (bool, error) {
+	kinds := q.kinds()
+	if len(kinds) == 0 {
+		return false, nil
+	}
+
+	now := q.now()
+	claimed, err := q.store.Claim(ctx, q.worker, kinds, now, now.Add(q.config.Lease), 1)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim a job: %w", err)
+	}
+	if len(claimed) == 0 {
+		return false, nil
+	}
+
+	job := &claimed[0]
+	jobErr := q.run(ctx, job)
+	return true, q.finish(ctx, job, jobErr)
+}
+
+// run calls the job's handler within its lease, recovering from panics
+func (q *Queue) run(ctx context.Context, job *Job) (err error) {
+	q.mu.RLock()
+	handler := q.handlers[job.Kind]
+	q.mu.RUnlock()
+
+	ctx, cancel := context.WithDeadline(ctx, *job.LockedUntil)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// finish records a job's outcome: completed, retried after a backoff, or
+// dead once it fails permanently or runs out of attempts
+func (q *Queue) finish(ctx context.Context, job *Job, jobErr error) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), finishTimeout)
+	defer cancel()
+
+	now := q.now()
+	var err error
+	switch {
+	case jobErr == nil:
+		err = q.store.Complete(ctx, job, now)
+	case IsPermanent(jobErr) || job.Attempts >= job.MaxAttempts:
+		log.Printf("Job %s (%s) died after %d attempts: %v", job.ID, job.Kind, job.Attempts, jobErr)
+		err = q.store.Kill(ctx, job, now, jobErr.Error())
+	default:
+		err = q.store.Retry(ctx, job, now.Add(q.backoff(job.Attempts)), jobErr.Error())
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to finish job %s (%s): %w", job.ID, job.Kind, err)
+	}
+	return nil
+}
+
+// Maintain rescues jobs whose worker died and prunes completed jobs past
+// their retention. Several servers maintaining the same store is harmless.
+func (q *Queue) Maintain(ctx context.Context) error {
+	now := q.now()
+
+	rescued, err := q.store.RescueExpired(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to rescue expired jobs: %w", err)
+	}
+	if rescued > 0 {
+		log.Printf("Rescued %d jobs whose worker stopped", rescued)
+	}
+
+	if _, err := q.store.Prune(ctx, now.Add(-q.config.Retention)); err != nil {
+		return fmt.Errorf("failed to prune completed jobs: %w", err)
+	}
+	return nil
+}
+
+// maintain runs Maintain every MaintenanceInterval until the queue stops
+func (q *Queue) maintain(ctx context.Context) {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(q.config.MaintenanceInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := q.Maintain(ctx); err != nil {
+			log.Printf("Job maintenance failed: %v", err)
+		}
+
+		select {
+		case <-q.stopping:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type greetArgs struct {
+	Name string `json:"name"`
+}
+
+func (greetArgs) Kind() string { return "greet" }
+
+// newTestQueue returns a queue over a memory store whose clock the test
+// moves, with a fixed backoff of a minute per attempt
+func newTestQueue(t *testing.T) (*Queue, *MemoryStore, *time.Time) {
+	store := NewMemoryStore()
+	config := DefaultConfig()
+	config.PollInterval = 10 * time.Millisecond
+	require.NoError(t, config.Validate())
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	queue := NewQueue(store, config)
+	queue.now = func() time.Time { return now }
+	queue.backoff = func(attempt int) time.Duration { return time.Duration(attempt) * time.Minute }
+	return queue, store, &now
+}
+
+func TestQueue_RunNext(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("typed handlers", func(t *testing.T) {
+		queue, store, _ := newTestQueue(t)
+		var greeted []string
+		Handle(queue, func(ctx context.Context, job *Job, args greetArgs) error {
+			greeted = append(greeted, args.Name)
+			return nil
+		})
+
+		job, err := queue.Enqueue(ctx, greetArgs{Name: "Ada"}, EnqueueOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "greet", job.Kind)
+		assert.Equal(t, DefaultConfig().MaxAttempts, job.MaxAttempts)
+
+		ran, err := queue.RunNext(ctx)
+		require.NoError(t, err)
+		assert.True(t, ran)
+		assert.Equal(t, []string{"Ada"}, greeted)
+
+		stored, err := store.Get(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, StateCompleted, stored.State)
+
+		ran, err = queue.RunNext(ctx)
+		require.NoError(t, err)
+		assert.False(t, ran)
+	})
+
+	t.Run("retries with backoff, then dies", func(t *testing.T) {
+		queue, store, now := newTestQueue(t)
+		Handle(queue, func(ctx context.Context, job *Job, args greetArgs) error {
+			return errors.New("mail server down")
+		})
+
+		job, err := queue.Enqueue(ctx, greetArgs{Name: "Ada"}, EnqueueOptions{MaxAttempts: 3})
+		require.NoError(t, err)
+
+		start := *now
+		for attempt := 1; attempt <= 2; attempt++ {
+			ran, err := queue.RunNext(ctx)
+			require.NoError(t, err)
+			require.True(t, ran)
+
+			stored, err := store.Get(ctx, job.ID)
+			require.NoError(t, err)
+			assert.Equal(t, StateScheduled, stored.State)
+			assert.Equal(t, attempt, stored.Attempts)
+			assert.Equal(t, "mail server down", *stored.LastError)
+			assert.True(t, now.Add(time.Duration(attempt)*time.Minute).Equal(stored.RunAt))
+
+			ran, err = queue.RunNext(ctx)
+			require.NoError(t, err)
+			assert.False(t, ran, "not before the backoff")
+			*now = stored.RunAt
+		}
+
+		ran, err := queue.RunNext(ctx)
+		require.NoError(t, err)
+		require.True(t, ran)
+		stored, err := store.Get(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, StateDead, stored.State)
+		assert.Equal(t, 3, stored.Attempts)
+		assert.True(t, now.After(start))
+
+		dead, err := store.ListDead(ctx, 10)
+		require.NoError(t, err)
+		assert.Len(t, dead, 1)
+	})
+
+	t.Run("permanent errors, panics and bad arguments", func(t *testing.T) {
+		queue, store, _ := newTestQueue(t)
+		Handle(queue, func(ctx context.Context, job *Job, args greetArgs) error {
+			switch args.Name {
+			case "gone":
+				return Permanent(errors.New("group deleted"))
+			case "panic":
+				panic("boom")
+			}
+			return nil
+		})
+
+		gone, err := queue.Enqueue(ctx, greetArgs{Name: "gone"}, EnqueueOptions{})
+		require.NoError(t, err)
+		_, err = queue.RunNext(ctx)
+		require.NoError(t, err)
+		stored, err := store.Get(ctx, gone.ID)
+		require.NoError(t, err)
+		assert.Equal(t, StateDead, stored.State, "permanent errors skip the remaining attempts")
+
+		panicked, err := queue.Enqueue(ctx, greetArgs{Name: "panic"}, EnqueueOptions{})
+		require.NoError(t, err)
+		_, err = queue.RunNext(ctx)
+		require.NoError(t, err)
+		stored, err = store.Get(ctx, panicked.ID)
+		require.NoError(t, err)
+		assert.Equal(t, StateScheduled, stored.State)
+		assert.Equal(t, "panic: boom", *stored.LastError)
+
+		malformed := newTestJob("greet", queue.now())
+		malformed.Args = []byte(`{"name": 42}`)
+		require.NoError(t, store.Insert(ctx, malformed))
+		_, err = queue.RunNext(ctx)
+		require.NoError(t, err)
+		stored, err = store.Get(ctx, malformed.ID)
+		require.NoError(t, err)
+		assert.Equal(t, StateDead, stored.State)
+	})
+
+	t.Run("scheduled and unique jobs", func(t *testing.T) {
+		queue, _, now := newTestQueue(t)
+		var runs int
+		Handle(queue, func(ctx context.Context, job *Job, args greetArgs) error {
+			runs++
+			return nil
+		})
+
+		_, err := queue.Enqueue(ctx, greetArgs{Name: "Ada"}, EnqueueOptions{
+			UniqueKey: "ada",
+			RunAt:     now.Add(time.Hour),
+		})
+		require.NoError(t, err)
+		_, err = queue.Enqueue(ctx, greetArgs{Name: "Ada"}, EnqueueOptions{UniqueKey: "ada"})
+		assert.ErrorIs(t, err, ErrDuplicateJob)
+
+		ran, err := queue.RunNext(ctx)
+		require.NoError(t, err)
+		assert.False(t, ran)
+
+		*now = now.Add(time.Hour)
+		ran, err = queue.RunNext(ctx)
+		require.NoError(t, err)
+		assert.True(t, ran)
+		assert.Equal(t, 1, runs)
+	})
+
+	t.Run("maintenance rescues jobs of stopped workers", func(t *testing.T) {
+		queue, store, now := newTestQueue(t)
+		Handle(queue, func(ctx context.Context, job *Job, args greetArgs) error { return nil })
+
+		job, err := queue.Enqueue(ctx, greetArgs{Name: "Ada"}, EnqueueOptions{})
+		require.NoError(t, err)
+		_, err = store.Claim(ctx, "stopped-worker", []string{"greet"}, *now, now.Add(time.Minute), 1)
+		require.NoError(t, err)
+
+		*now = now.Add(2 * time.Minute)
+		require.NoError(t, queue.Maintain(ctx))
+		ran, err := queue.RunNext(ctx)
+		require.NoError(t, err)
+		assert.True(t, ran)
+
+		stored, err := store.Get(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, StateCompleted, stored.State)
+		assert.Equal(t, 2, stored.Attempts)
+	})
+}
+
+func TestQueue_StartStop(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("runs enqueued jobs and waits for them on stop", func(t *testing.T) {
+		store := NewMemoryStore()
+		config := DefaultConfig()
+		config.Workers = 2
+		queue := NewQueue(store, config)
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var finished atomic.Bool
+		Handle(queue, func(ctx context.Context, job *Job, args greetArgs) error {
+			close(started)
+			<-release
+			finished.Store(true)
+			return nil
+		})
+		queue.Start()
+
+		job, err := queue.Enqueue(ctx, greetArgs{Name: "Ada"}, EnqueueOptions{})
+		require.NoError(t, err)
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the job did not start")
+		}
+
+		stopped := make(chan error)
+		go func() { stopped <- queue.Stop(ctx) }()
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+
+		require.NoError(t, <-stopped)
+		assert.True(t, finished.Load(), "stop waits for running jobs")
+		stored, err := store.Get(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, StateCompleted, stored.State)
+	})
+
+	t.Run("cancels running jobs when stop times out", func(t *testing.T) {
+		store := NewMemoryStore()
+		queue := NewQueue(store, DefaultConfig())
+
+		started := make(chan struct{})
+		Handle(queue, func(ctx context.Context, job *Job, args greetArgs) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		queue.Start()
+
+		job, err := queue.Enqueue(ctx, greetArgs{Name: "Ada"}, EnqueueOptions{})
+		require.NoError(t, err)
+		<-started
+
+		stopCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, queue.Stop(stopCtx), context.DeadlineExceeded)
+
+		stored, err := store.Get(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, StateScheduled, stored.State, "cancelled jobs are retried")
+	})
+}
+
+func TestConfig_Backoff(t *testing.T) {
+	config := DefaultConfig()
+	for attempt, base := range map[int]time.Duration{
+		1:  10 * time.Second,
+		2:  20 * time.Second,
+		4:  80 * time.Second,
+		30: time.Hour,
+	} {
+		delay := config.Backoff(attempt)
+		assert.GreaterOrEqual(t, delay, base, "attempt %d", attempt)
+		assert.LessOrEqual(t, delay, base+base/10, "attempt %d", attempt)
+	}
+}
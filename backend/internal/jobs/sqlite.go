@@ -0,0 +1,249 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sqliteTimeFormat stores timestamps as fixed-width UTC text, as the
+// repositories do, so they compare chronologically as strings
+const sqliteTimeFormat = "2006-01-02T15:04:05.000000Z"
+
+// SQLiteStore keeps jobs in SQLite. SQLite has a single writer, so a claim
+// is one UPDATE that no other worker can interleave with.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore creates a job store over a migrated SQLite database
+func NewSQLiteStore(db *sql.DB) *SQLiteStore {
+	return &SQLiteStore{db: db}
+}
+
+// sqliteTime formats a timestamp for storage
+func sqliteTime(t time.Time) string {
+	return t.UTC().Round(time.Microsecond).Format(sqliteTimeFormat)
+}
+
+// parseSQLiteTime parses a stored timestamp
+func parseSQLiteTime(text string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, text)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q: %w", text, err)
+	}
+	return t, nil
+}
+
+// parseSQLiteNullTime parses an optional stored timestamp
+func parseSQLiteNullTime(text sql.NullString) (*time.Time, error) {
+	if !text.Valid {
+		return nil, nil
+	}
+	t, err := parseSQLiteTime(text.String)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// scanSQLiteJob scans a row of jobColumns
+func scanSQLiteJob(row rowScanner, job *Job) error {
+	var state, args, runAt, createdAt string
+	var lockedUntil, finishedAt sql.NullString
+	err := row.Scan(&job.ID, &job.Kind, &args, &state, &job.UniqueKey, &job.Attempts, &job.MaxAttempts,
+		&runAt, &job.LockedBy, &lockedUntil, &job.LastError, &createdAt, &finishedAt)
+	if err != nil {
+		return err
+	}
+	job.State = State(state)
+	job.Args = []byte(args)
+
+	if job.RunAt, err = parseSQLiteTime(runAt); err != nil {
+		return err
+	}
+	if job.CreatedAt, err = parseSQLiteTime(createdAt); err != nil {
+		return err
+	}
+	if job.LockedUntil, err = parseSQLiteNullTime(lockedUntil); err != nil {
+		return err
+	}
+	job.FinishedAt, err = parseSQLiteNullTime(finishedAt)
+	return err
+}
+
+// querySQLiteJobs runs a query returning jobColumns
+func querySQLiteJobs(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]Job, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []Job
+	for rows.Next() {
+		var job Job
+		if err := scanSQLiteJob(rows, &job); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// Insert adds a scheduled job
+func (s *SQLiteStore) Insert(ctx context.Context, job *Job) error {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO jobs (id, kind, args, state, unique_key, attempts, max_attempts, run_at, created_at)
+		VALUES (?, ?, ?, 'scheduled', ?, 0, ?, ?, ?)
+		ON CONFLICT (kind, unique_key) WHERE unique_key IS NOT NULL DO NOTHING`,
+		strings.ToLower(job.ID), job.Kind, string(job.Args), job.UniqueKey, job.MaxAttempts,
+		sqliteTime(job.RunAt), sqliteTime(job.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to insert job: %w", err)
+	}
+	return insertedOrDuplicate(result, job)
+}
+
+// Get retrieves a job by ID
+func (s *SQLiteStore) Get(ctx context.Context, id string) (*Job, error) {
+	var job Job
+	err := scanSQLiteJob(s.db.QueryRowContext(ctx,
+		`SELECT `+jobColumns+` FROM jobs WHERE id = ?`, strings.ToLower(id)), &job)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	return &job, nil
+}
+
+// Claim hands up to limit runnable jobs of the given kinds to worker
+func (s *SQLiteStore) Claim(ctx context.Context, worker string, kinds []string, now, lockedUntil time.Time, limit int) ([]Job, error) {
+	if len(kinds) == 0 {
+		return nil, nil
+	}
+
+	args := []interface{}{worker, sqliteTime(lockedUntil), sqliteTime(now)}
+	for _, kind := range kinds {
+		args = append(args, kind)
+	}
+	args = append(args, limit)
+
+	jobs, err := querySQLiteJobs(ctx, s.db, `
+		UPDATE jobs
+		SET state = 'running', attempts = attempts + 1, locked_by = ?, locked_until = ?
+		WHERE id IN (
+			SELECT id FROM jobs
+			WHERE state = 'scheduled' AND run_at <= ?
+				AND kind IN (?`+strings.Repeat(", ?", len(kinds)-1)+`)
+			ORDER BY run_at, id
+			LIMIT ?
+		)
+		RETURNING `+jobColumns, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim jobs: %w", err)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].RunAt.Equal(jobs[j].RunAt) {
+			return jobs[i].RunAt.Before(jobs[j].RunAt)
+		}
+		return jobs[i].ID < jobs[j].ID
+	})
+	return jobs, nil
+}
+
+// finishHeld updates a job the worker still holds, or returns ErrLockLost
+func (s *SQLiteStore) finishHeld(ctx context.Context, job *Job, set string, args ...interface{}) error {
+	if job.LockedBy == nil {
+		return fmt.Errorf("%w: %s", ErrLockLost, job.ID)
+	}
+	args = append(args, strings.ToLower(job.ID), *job.LockedBy)
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE jobs SET `+set+`, locked_by = NULL, locked_until = NULL
+		WHERE id = ? AND state = 'running' AND locked_by = ?`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+	return heldOrLost(result, job)
+}
+
+// Complete marks a job the worker holds completed
+func (s *SQLiteStore) Complete(ctx context.Context, job *Job, now time.Time) error {
+	return s.finishHeld(ctx, job, `state = 'completed', finished_at = ?`, sqliteTime(now))
+}
+
+// Retry schedules a job the worker holds to run again at runAt
+func (s *SQLiteStore) Retry(ctx context.Context, job *Job, runAt time.Time, lastError string) error {
+	return s.finishHeld(ctx, job, `state = 'scheduled', run_at = ?, last_error = ?`, sqliteTime(runAt), lastError)
+}
+
+// Kill moves a job the worker holds to the dead letter queue
+func (s *SQLiteStore) Kill(ctx context.Context, job *Job, now time.Time, lastError string) error {
+	return s.finishHeld(ctx, job, `state = 'dead', finished_at = ?, last_error = ?`, sqliteTime(now), lastError)
+}
+
+// RescueExpired reschedules running jobs whose lock expired by now
+func (s *SQLiteStore) RescueExpired(ctx context.Context, now time.Time) (int, error) {
+	at := sqliteTime(now)
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE jobs
+		SET state = CASE WHEN attempts >= max_attempts THEN 'dead' ELSE 'scheduled' END,
+			finished_at = CASE WHEN attempts >= max_attempts THEN ? END,
+			run_at = ?, last_error = ?, locked_by = NULL, locked_until = NULL
+		WHERE state = 'running' AND locked_until <= ?`,
+		at, at, lockLostMessage, at)
+	if err != nil {
+		return 0, fmt.Errorf("failed to rescue jobs: %w", err)
+	}
+	rescued, err := result.RowsAffected()
+	return int(rescued), err
+}
+
+// Prune deletes jobs completed before before
+func (s *SQLiteStore) Prune(ctx context.Context, before time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM jobs WHERE state = 'completed' AND finished_at < ?`, sqliteTime(before))
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune jobs: %w", err)
+	}
+	pruned, err := result.RowsAffected()
+	return int(pruned), err
+}
+
+// ListDead retrieves up to limit dead jobs, most recently failed first
+func (s *SQLiteStore) ListDead(ctx context.Context, limit int) ([]Job, error) {
+	jobs, err := querySQLiteJobs(ctx, s.db, `
+		SELECT `+jobColumns+` FROM jobs
+		WHERE state = 'dead' AND finished_at IS NOT NULL
+		ORDER BY finished_at DESC, id
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// Requeue schedules a dead job to run at now with fresh attempts
+func (s *SQLiteStore) Requeue(ctx context.Context, id string, now time.Time) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE jobs SET state = 'scheduled', attempts = 0, run_at = ?, finished_at = NULL
+		WHERE id = ? AND state = 'dead'`, sqliteTime(now), strings.ToLower(id))
+	if err != nil {
+		return fmt.Errorf("failed to requeue job: %w", err)
+	}
+	requeued, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to requeue job: %w", err)
+	}
+	if requeued == 0 {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	return nil
+}
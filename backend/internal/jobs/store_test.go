@@ -0,0 +1,369 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"collaborative-bucket-list/migrations"
+	"collaborative-bucket-list/pkg/database"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStoreFunc returns an empty Store for one test
+type newStoreFunc func(t *testing.T) Store
+
+func TestMemoryStore_Conformance(t *testing.T) {
+	runStoreConformanceTests(t, func(t *testing.T) Store {
+		return NewMemoryStore()
+	})
+}
+
+func TestSQLiteStore_Conformance(t *testing.T) {
+	runStoreConformanceTests(t, func(t *testing.T) Store {
+		db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "test.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+
+		migrator, err := database.NewSQLiteMigrator(db, migrations.SQLiteFS)
+		require.NoError(t, err)
+		require.NoError(t, migrator.Up(context.Background()))
+		return NewSQLiteStore(db)
+	})
+}
+
+func TestPostgresStore_Conformance(t *testing.T) {
+	runStoreConformanceTests(t, func(t *testing.T) Store {
+		return NewPostgresStore(setupPostgresTestDB(t))
+	})
+}
+
+// setupPostgresTestDB connects to the test database, skipping the test if
+// there is none, and empties the jobs table
+func setupPostgresTestDB(t *testing.T) *sql.DB {
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		getEnvOrDefault("TEST_DB_HOST", "localhost"),
+		getEnvOrDefault("TEST_DB_PORT", "5432"),
+		getEnvOrDefault("TEST_DB_USER", "postgres"),
+		getEnvOrDefault("TEST_DB_PASSWORD", "postgres"),
+		getEnvOrDefault("TEST_DB_NAME", "collaborative_bucket_list"),
+		getEnvOrDefault("TEST_DB_SSL_MODE", "disable"))
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Skipf("Skipping database tests: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		t.Skipf("Skipping database tests: database not available: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Up(context.Background()))
+	_, err = db.Exec("DELETE FROM jobs")
+	require.NoError(t, err)
+	return db
+}
+
+// getEnvOrDefault returns environment variable value or default if not set
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// newTestJob returns a scheduled job of kind that can run at runAt
+func newTestJob(kind string, runAt time.Time) *Job {
+	return &Job{
+		ID:          uuid.New().String(),
+		Kind:        kind,
+		Args:        []byte(`{"n":1}`),
+		State:       StateScheduled,
+		MaxAttempts: 3,
+		RunAt:       runAt,
+		CreatedAt:   runAt,
+	}
+}
+
+// runStoreConformanceTests checks the behaviour every Store must share
+func runStoreConformanceTests(t *testing.T, newStore newStoreFunc) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	lease := now.Add(time.Minute)
+
+	t.Run("insert and get", func(t *testing.T) {
+		store := newStore(t)
+		job := newTestJob("email", now)
+		require.NoError(t, store.Insert(ctx, job))
+
+		stored, err := store.Get(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "email", stored.Kind)
+		assert.JSONEq(t, `{"n":1}`, string(stored.Args))
+		assert.Equal(t, StateScheduled, stored.State)
+		assert.Zero(t, stored.Attempts)
+		assert.Equal(t, 3, stored.MaxAttempts)
+		assert.True(t, now.Equal(stored.RunAt))
+
+		_, err = store.Get(ctx, uuid.New().String())
+		assert.ErrorIs(t, err, ErrJobNotFound)
+		_, err = store.Get(ctx, "not-a-uuid")
+		assert.ErrorIs(t, err, ErrJobNotFound)
+	})
+
+	t.Run("unique keys", func(t *testing.T) {
+		store := newStore(t)
+		key := "group-1"
+		first := newTestJob("email", now)
+		first.UniqueKey = &key
+		require.NoError(t, store.Insert(ctx, first))
+
+		second := newTestJob("email", now)
+		second.UniqueKey = &key
+		assert.ErrorIs(t, store.Insert(ctx, second), ErrDuplicateJob)
+
+		// Keys are per kind, and jobs without one never clash
+		other := newTestJob("webhook", now)
+		other.UniqueKey = &key
+		require.NoError(t, store.Insert(ctx, other))
+		require.NoError(t, store.Insert(ctx, newTestJob("email", now)))
+		require.NoError(t, store.Insert(ctx, newTestJob("email", now)))
+
+		// The key stays taken after the job completes, until it is pruned
+		claimed, err := store.Claim(ctx, "worker", []string{"email"}, now, lease, 10)
+		require.NoError(t, err)
+		for i := range claimed {
+			require.NoError(t, store.Complete(ctx, &claimed[i], now))
+		}
+		assert.ErrorIs(t, store.Insert(ctx, second), ErrDuplicateJob)
+
+		pruned, err := store.Prune(ctx, now.Add(time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, 3, pruned)
+		assert.NoError(t, store.Insert(ctx, second))
+	})
+
+	t.Run("claim", func(t *testing.T) {
+		store := newStore(t)
+		later := newTestJob("email", now.Add(-time.Second))
+		sooner := newTestJob("email", now.Add(-time.Minute))
+		future := newTestJob("email", now.Add(time.Hour))
+		unknown := newTestJob("fax", now.Add(-time.Hour))
+		for _, job := range []*Job{later, sooner, future, unknown} {
+			require.NoError(t, store.Insert(ctx, job))
+		}
+
+		claimed, err := store.Claim(ctx, "worker-1", []string{"email", "sms"}, now, lease, 10)
+		require.NoError(t, err)
+		require.Len(t, claimed, 2, "future jobs and unknown kinds are left")
+		assert.Equal(t, sooner.ID, claimed[0].ID, "soonest first")
+		assert.Equal(t, later.ID, claimed[1].ID)
+		assert.Equal(t, StateRunning, claimed[0].State)
+		assert.Equal(t, 1, claimed[0].Attempts)
+		require.NotNil(t, claimed[0].LockedBy)
+		assert.Equal(t, "worker-1", *claimed[0].LockedBy)
+		require.NotNil(t, claimed[0].LockedUntil)
+		assert.True(t, lease.Equal(*claimed[0].LockedUntil))
+
+		claimed, err = store.Claim(ctx, "worker-2", []string{"email"}, now, lease, 10)
+		require.NoError(t, err)
+		assert.Empty(t, claimed, "running jobs are not claimed again")
+
+		claimed, err = store.Claim(ctx, "worker-2", []string{"email"}, now.Add(2*time.Hour), lease, 1)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, future.ID, claimed[0].ID)
+	})
+
+	t.Run("concurrent claims get different jobs", func(t *testing.T) {
+		store := newStore(t)
+		for i := 0; i < 20; i++ {
+			require.NoError(t, store.Insert(ctx, newTestJob("email", now)))
+		}
+
+		var mu sync.Mutex
+		seen := map[string]int{}
+		var wg sync.WaitGroup
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+			go func(worker string) {
+				defer wg.Done()
+				for {
+					claimed, err := store.Claim(ctx, worker, []string{"email"}, now, lease, 3)
+					if !assert.NoError(t, err) || len(claimed) == 0 {
+						return
+					}
+					mu.Lock()
+					for _, job := range claimed {
+						seen[job.ID]++
+					}
+					mu.Unlock()
+				}
+			}(fmt.Sprintf("worker-%d", w))
+		}
+		wg.Wait()
+
+		assert.Len(t, seen, 20)
+		for id, count := range seen {
+			assert.Equal(t, 1, count, "job %s was claimed %d times", id, count)
+		}
+	})
+
+	t.Run("complete, retry and kill", func(t *testing.T) {
+		store := newStore(t)
+		for i := 0; i < 3; i++ {
+			require.NoError(t, store.Insert(ctx, newTestJob("email", now)))
+		}
+		claimed, err := store.Claim(ctx, "worker", []string{"email"}, now, lease, 3)
+		require.NoError(t, err)
+		require.Len(t, claimed, 3)
+
+		require.NoError(t, store.Complete(ctx, &claimed[0], now))
+		job, err := store.Get(ctx, claimed[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, StateCompleted, job.State)
+		require.NotNil(t, job.FinishedAt)
+		assert.Nil(t, job.LockedBy)
+
+		retryAt := now.Add(time.Minute)
+		require.NoError(t, store.Retry(ctx, &claimed[1], retryAt, "smtp timeout"))
+		job, err = store.Get(ctx, claimed[1].ID)
+		require.NoError(t, err)
+		assert.Equal(t, StateScheduled, job.State)
+		assert.True(t, retryAt.Equal(job.RunAt))
+		require.NotNil(t, job.LastError)
+		assert.Equal(t, "smtp timeout", *job.LastError)
+		assert.Equal(t, 1, job.Attempts)
+
+		require.NoError(t, store.Kill(ctx, &claimed[2], now, "bad address"))
+		job, err = store.Get(ctx, claimed[2].ID)
+		require.NoError(t, err)
+		assert.Equal(t, StateDead, job.State)
+		assert.Equal(t, "bad address", *job.LastError)
+
+		// Finishing a job twice means the worker no longer held it
+		assert.ErrorIs(t, store.Complete(ctx, &claimed[0], now), ErrLockLost)
+		assert.ErrorIs(t, store.Kill(ctx, &claimed[1], now, "late"), ErrLockLost)
+	})
+
+	t.Run("expired locks", func(t *testing.T) {
+		store := newStore(t)
+		retried := newTestJob("email", now)
+		exhausted := newTestJob("email", now)
+		exhausted.MaxAttempts = 1
+		held := newTestJob("sms", now)
+		for _, job := range []*Job{retried, exhausted, held} {
+			require.NoError(t, store.Insert(ctx, job))
+		}
+		stale, err := store.Claim(ctx, "dead-worker", []string{"email"}, now, now.Add(time.Second), 10)
+		require.NoError(t, err)
+		require.Len(t, stale, 2)
+		_, err = store.Claim(ctx, "live-worker", []string{"sms"}, now, now.Add(time.Hour), 10)
+		require.NoError(t, err)
+
+		rescued, err := store.RescueExpired(ctx, now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, 2, rescued)
+
+		job, err := store.Get(ctx, retried.ID)
+		require.NoError(t, err)
+		assert.Equal(t, StateScheduled, job.State)
+		assert.Equal(t, 1, job.Attempts)
+		require.NotNil(t, job.LastError)
+
+		job, err = store.Get(ctx, exhausted.ID)
+		require.NoError(t, err)
+		assert.Equal(t, StateDead, job.State)
+
+		job, err = store.Get(ctx, held.ID)
+		require.NoError(t, err)
+		assert.Equal(t, StateRunning, job.State, "unexpired locks are kept")
+
+		// The old worker's outcome is ignored once the job was rescued
+		for i := range stale {
+			assert.ErrorIs(t, store.Complete(ctx, &stale[i], now), ErrLockLost)
+		}
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		store := newStore(t)
+		old := newTestJob("email", now)
+		recent := newTestJob("email", now)
+		dead := newTestJob("email", now)
+		for _, job := range []*Job{old, recent, dead} {
+			require.NoError(t, store.Insert(ctx, job))
+		}
+		claimed, err := store.Claim(ctx, "worker", []string{"email"}, now, lease, 3)
+		require.NoError(t, err)
+		for i := range claimed {
+			switch claimed[i].ID {
+			case old.ID:
+				require.NoError(t, store.Complete(ctx, &claimed[i], now.Add(-2*time.Hour)))
+			case recent.ID:
+				require.NoError(t, store.Complete(ctx, &claimed[i], now))
+			default:
+				require.NoError(t, store.Kill(ctx, &claimed[i], now.Add(-2*time.Hour), "failed"))
+			}
+		}
+
+		pruned, err := store.Prune(ctx, now.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, pruned)
+
+		_, err = store.Get(ctx, old.ID)
+		assert.ErrorIs(t, err, ErrJobNotFound)
+		_, err = store.Get(ctx, recent.ID)
+		assert.NoError(t, err)
+		_, err = store.Get(ctx, dead.ID)
+		assert.NoError(t, err, "dead jobs are kept")
+	})
+
+	t.Run("dead letter queue", func(t *testing.T) {
+		store := newStore(t)
+		first := newTestJob("email", now)
+		second := newTestJob("email", now)
+		require.NoError(t, store.Insert(ctx, first))
+		require.NoError(t, store.Insert(ctx, second))
+		claimed, err := store.Claim(ctx, "worker", []string{"email"}, now, lease, 2)
+		require.NoError(t, err)
+		for i := range claimed {
+			finished := now
+			if claimed[i].ID == second.ID {
+				finished = now.Add(time.Second)
+			}
+			require.NoError(t, store.Kill(ctx, &claimed[i], finished, "failed"))
+		}
+
+		dead, err := store.ListDead(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, dead, 2)
+		assert.Equal(t, second.ID, dead[0].ID, "most recently failed first")
+
+		requeueAt := now.Add(time.Minute)
+		require.NoError(t, store.Requeue(ctx, second.ID, requeueAt))
+		job, err := store.Get(ctx, second.ID)
+		require.NoError(t, err)
+		assert.Equal(t, StateScheduled, job.State)
+		assert.Zero(t, job.Attempts)
+		assert.Nil(t, job.FinishedAt)
+		assert.True(t, requeueAt.Equal(job.RunAt))
+
+		dead, err = store.ListDead(ctx, 10)
+		require.NoError(t, err)
+		assert.Len(t, dead, 1)
+
+		assert.ErrorIs(t, store.Requeue(ctx, second.ID, now), ErrJobNotFound, "only dead jobs are requeued")
+		assert.ErrorIs(t, store.Requeue(ctx, uuid.New().String(), now), ErrJobNotFound)
+	})
+}
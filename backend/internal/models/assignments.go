@@ -0,0 +1,46 @@
+package models
+
+import "strings"
+
+// AssignItemRequest assigns a member of an item's group to organize it.
+// MemberID is the member making the change.
+type AssignItemRequest struct {
+	AssigneeID string `json:"assigneeId" binding:"required"`
+	MemberID   string `json:"memberId" binding:"required"`
+}
+
+// AssignedItem is an item assigned to one of a user's memberships, listed
+// across the user's groups
+type AssignedItem struct {
+	BucketListItem `json:",inline"`
+	GroupName      string `json:"groupName"`
+	// MemberID is the user's membership the item is assigned to
+	MemberID string `json:"memberId"`
+}
+
+func (req *AssignItemRequest) Validate() ValidationResult {
+	var allErrors []ValidationError
+
+	if !ValidateUUID(req.AssigneeID).IsValid {
+		allErrors = append(allErrors, ValidationError{
+			Field:   "assigneeId",
+			Message: "Assignee ID must be a member ID",
+		})
+	}
+	if strings.TrimSpace(req.MemberID) == "" {
+		allErrors = append(allErrors, ValidationError{
+			Field:   "memberId",
+			Message: "Member ID is required",
+		})
+	}
+
+	return ValidationResult{
+		IsValid: len(allErrors) == 0,
+		Errors:  allErrors,
+	}
+}
+
+func (req *AssignItemRequest) Sanitize() {
+	req.AssigneeID = strings.TrimSpace(req.AssigneeID)
+	req.MemberID = strings.TrimSpace(req.MemberID)
+}
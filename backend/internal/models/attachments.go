@@ -0,0 +1,239 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Attachment is a photo or file uploaded to a bucket list item. The file
+// itself lives in a blob store under StorageKey; URL is a signed download
+// link filled in when the attachment is returned to a client.
+//
+// Images are processed in the background after upload: their metadata is
+// stripped and thumbnails are rendered. Width and Height are known once
+// ProcessingStatus is processed.
+type Attachment struct {
+	ID               string                `json:"id" db:"id"`
+	ItemID           string                `json:"itemId" db:"item_id"`
+	GroupID          string                `json:"groupId" db:"group_id"`
+	MemberID         string                `json:"memberId" db:"member_id"`
+	FileName         string                `json:"fileName" db:"file_name"`
+	ContentType      string                `json:"contentType" db:"content_type"`
+	Size             int64                 `json:"size" db:"size"`
+	Width            *int                  `json:"width,omitempty" db:"width"`
+	Height           *int                  `json:"height,omitempty" db:"height"`
+	ProcessingStatus string                `json:"processingStatus" db:"processing_status"`
+	StorageKey       string                `json:"-" db:"storage_key"`
+	CreatedAt        time.Time             `json:"createdAt" db:"created_at"`
+	URL              string                `json:"url,omitempty" db:"-"`
+	Thumbnails       []AttachmentThumbnail `json:"thumbnails,omitempty" db:"-"`
+}
+
+// AttachmentThumbnail is a scaled JPEG rendition of an image attachment
+type AttachmentThumbnail struct {
+	Size       string `json:"size"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	URL        string `json:"url,omitempty"`
+	StorageKey string `json:"-"`
+}
+
+// Attachment processing statuses
+const (
+	AttachmentPending   = "pending"
+	AttachmentProcessed = "processed"
+	AttachmentFailed    = "failed"
+)
+
+// ThumbnailSize is a named bound on the longest side of a thumbnail
+type ThumbnailSize struct {
+	Name    string
+	MaxSide int
+}
+
+// ThumbnailSizes are the thumbnails rendered for image attachments
+var ThumbnailSizes = []ThumbnailSize{
+	{Name: "small", MaxSide: 160},
+	{Name: "medium", MaxSide: 480},
+	{Name: "large", MaxSide: 1280},
+}
+
+// Constants for attachment validation
+const (
+	MaxAttachmentSize           = 10 << 20
+	MaxAttachmentFileNameLength = 255
+)
+
+// AttachmentContentTypes are the content types attachments may have
+var AttachmentContentTypes = []string{
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+	"application/pdf",
+}
+
+// AttachmentStorageKey returns the blob store key of an attachment. Keys
+// are grouped by group and item so a whole group's files share a prefix.
+func AttachmentStorageKey(groupID, itemID, attachmentID string) string {
+	return fmt.Sprintf("groups/%s/items/%s/%s", groupID, itemID, attachmentID)
+}
+
+// AttachmentThumbnailKey returns the blob store key of a thumbnail of the
+// attachment stored under storageKey
+func AttachmentThumbnailKey(storageKey, size string) string {
+	return storageKey + "-" + size
+}
+
+// IsImage reports whether the attachment is an image that gets thumbnails
+func (a *Attachment) IsImage() bool {
+	return strings.HasPrefix(a.ContentType, "image/")
+}
+
+// ThumbnailVariants returns the thumbnails of a processed image attachment
+// with their dimensions, which keep the aspect ratio and never exceed the
+// original's
+func (a *Attachment) ThumbnailVariants() []AttachmentThumbnail {
+	if !a.IsImage() || a.ProcessingStatus != AttachmentProcessed || a.Width == nil || a.Height == nil {
+		return nil
+	}
+
+	width, height := *a.Width, *a.Height
+	thumbnails := make([]AttachmentThumbnail, 0, len(ThumbnailSizes))
+	for _, size := range ThumbnailSizes {
+		w, h := fitThumbnail(width, height, size.MaxSide)
+		thumbnails = append(thumbnails, AttachmentThumbnail{
+			Size:       size.Name,
+			Width:      w,
+			Height:     h,
+			StorageKey: AttachmentThumbnailKey(a.StorageKey, size.Name),
+		})
+	}
+	return thumbnails
+}
+
+// fitThumbnail scales width by height down so the longest side is at most
+// maxSide, keeping both at least one pixel
+func fitThumbnail(width, height, maxSide int) (int, int) {
+	if width <= maxSide && height <= maxSide {
+		return width, height
+	}
+	if width >= height {
+		return maxSide, max(1, (height*maxSide+width/2)/width)
+	}
+	return max(1, (width*maxSide+height/2)/height), maxSide
+}
+
+func ValidateAttachmentContentType(contentType string) ValidationResult {
+	var errors []ValidationError
+
+	allowed := false
+	for _, t := range AttachmentContentTypes {
+		if contentType == t {
+			allowed = true
+		}
+	}
+	if !allowed {
+		errors = append(errors, ValidationError{
+			Field:   "file",
+			Message: fmt.Sprintf("Files must be one of %s", strings.Join(AttachmentContentTypes, ", ")),
+		})
+	}
+
+	return ValidationResult{
+		IsValid: len(errors) == 0,
+		Errors:  errors,
+	}
+}
+
+func ValidateAttachmentSize(size int64) ValidationResult {
+	var errors []ValidationError
+
+	if size <= 0 {
+		errors = append(errors, ValidationError{
+			Field:   "file",
+			Message: "File is empty",
+		})
+	} else if size > MaxAttachmentSize {
+		errors = append(errors, ValidationError{
+			Field:   "file",
+			Message: fmt.Sprintf("Files must be no more than %d MB", MaxAttachmentSize>>20),
+		})
+	}
+
+	return ValidationResult{
+		IsValid: len(errors) == 0,
+		Errors:  errors,
+	}
+}
+
+// SanitizeFileName reduces an uploaded file name to its base name without
+// control characters, at most MaxAttachmentFileNameLength bytes long
+func SanitizeFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+
+	// Truncate before the extension so the type stays recognizable
+	if len(name) > MaxAttachmentFileNameLength {
+		ext := path.Ext(name)
+		if len(ext) > 16 {
+			ext = ""
+		}
+		base := name[:len(name)-len(ext)]
+		for len(base)+len(ext) > MaxAttachmentFileNameLength {
+			_, size := utf8.DecodeLastRuneInString(base)
+			base = base[:len(base)-size]
+		}
+		name = base + ext
+	}
+
+	return name
+}
+
+func (a *Attachment) Sanitize() {
+	a.FileName = SanitizeFileName(a.FileName)
+	if a.ProcessingStatus == "" {
+		a.ProcessingStatus = AttachmentPending
+	}
+}
+
+func (a *Attachment) IsValid() error {
+	if validation := ValidateAttachmentContentType(a.ContentType); !validation.IsValid {
+		return errors.New(validation.Errors[0].Message)
+	}
+	if validation := ValidateAttachmentSize(a.Size); !validation.IsValid {
+		return errors.New(validation.Errors[0].Message)
+	}
+	if strings.TrimSpace(a.ItemID) == "" {
+		return errors.New("item ID is required")
+	}
+	if strings.TrimSpace(a.MemberID) == "" {
+		return errors.New("member ID is required")
+	}
+	if strings.TrimSpace(a.StorageKey) == "" {
+		return errors.New("storage key is required")
+	}
+	switch a.ProcessingStatus {
+	case "", AttachmentPending, AttachmentProcessed, AttachmentFailed:
+	default:
+		return fmt.Errorf("invalid processing status: %s", a.ProcessingStatus)
+	}
+	if (a.Width != nil && *a.Width <= 0) || (a.Height != nil && *a.Height <= 0) {
+		return errors.New("image dimensions must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Comment is a message about a bucket list item. A comment with a ParentID
+// is a reply; replies cannot have replies of their own.
+type Comment struct {
+	ID        string     `json:"id" db:"id"`
+	ItemID    string     `json:"itemId" db:"item_id"`
+	GroupID   string     `json:"groupId" db:"group_id"`
+	ParentID  *string    `json:"parentId,omitempty" db:"parent_id"`
+	MemberID  string     `json:"memberId" db:"member_id"`
+	Body      string     `json:"body" db:"body"`
+	CreatedAt time.Time  `json:"createdAt" db:"created_at"`
+	EditedAt  *time.Time `json:"editedAt,omitempty" db:"edited_at"`
+}
+
+// CommentThread is a top-level comment with its replies, oldest first
+type CommentThread struct {
+	Comment `json:",inline"`
+	Replies []Comment `json:"replies"`
+}
+
+// Constants for comment validation
+const (
+	MaxCommentLength = 2000
+)
+
+// CreateCommentRequest is the body of a request commenting on an item
+type CreateCommentRequest struct {
+	MemberID string  `json:"memberId" binding:"required"`
+	Body     string  `json:"body" binding:"required"`
+	ParentID *string `json:"parentId,omitempty"`
+}
+
+// UpdateCommentRequest is the body of a request editing a comment
+type UpdateCommentRequest struct {
+	MemberID string `json:"memberId" binding:"required"`
+	Body     string `json:"body" binding:"required"`
+}
+
+func ValidateCommentBody(body string) ValidationResult {
+	var errors []ValidationError
+
+	body = strings.TrimSpace(body)
+	if len(body) == 0 {
+		errors = append(errors, ValidationError{
+			Field:   "body",
+			Message: "Comment is required",
+		})
+	} else if len(body) > MaxCommentLength {
+		errors = append(errors, ValidationError{
+			Field:   "body",
+			Message: fmt.Sprintf("Comment must be no more than %d characters", MaxCommentLength),
+		})
+	}
+
+	return ValidationResult{
+		IsValid: len(errors) == 0,
+		Errors:  errors,
+	}
+}
+
+func (req *CreateCommentRequest) Validate() ValidationResult {
+	var allErrors []ValidationError
+
+	if strings.TrimSpace(req.MemberID) == "" {
+		allErrors = append(allErrors, ValidationError{
+			Field:   "memberId",
+			Message: "Member ID is required",
+		})
+	}
+	allErrors = append(allErrors, ValidateCommentBody(req.Body).Errors...)
+	if req.ParentID != nil && !ValidateUUID(*req.ParentID).IsValid {
+		allErrors = append(allErrors, ValidationError{
+			Field:   "parentId",
+			Message: "Parent ID must be a valid UUID",
+		})
+	}
+
+	return ValidationResult{
+		IsValid: len(allErrors) == 0,
+		Errors:  allErrors,
+	}
+}
+
+func (req *UpdateCommentRequest) Validate() ValidationResult {
+	var allErrors []ValidationError
+
+	if strings.TrimSpace(req.MemberID) == "" {
+		allErrors = append(allErrors, ValidationError{
+			Field:   "memberId",
+			Message: "Member ID is required",
+		})
+	}
+	allErrors = append(allErrors, ValidateCommentBody(req.Body).Errors...)
+
+	return ValidationResult{
+		IsValid: len(allErrors) == 0,
+		Errors:  allErrors,
+	}
+}
+
+var (
+	commentSpaceRegex     = regexp.MustCompile(`[^\S\n]+`)
+	commentBlankLineRegex = regexp.MustCompile(`\n{3,}`)
+)
+
+// SanitizeCommentBody trims a comment and collapses runs of spaces. Unlike
+// SanitizeString it keeps line breaks, allowing at most one blank line in a
+// row.
+func SanitizeCommentBody(body string) string {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(commentSpaceRegex.ReplaceAllString(line, " "))
+	}
+	body = strings.TrimSpace(strings.Join(lines, "\n"))
+	return commentBlankLineRegex.ReplaceAllString(body, "\n\n")
+}
+
+func (c *Comment) Sanitize() {
+	c.Body = SanitizeCommentBody(c.Body)
+}
+
+func (req *CreateCommentRequest) Sanitize() {
+	req.MemberID = strings.TrimSpace(req.MemberID)
+	req.Body = SanitizeCommentBody(req.Body)
+	if req.ParentID != nil {
+		parentID := strings.TrimSpace(*req.ParentID)
+		req.ParentID = &parentID
+	}
+}
+
+func (req *UpdateCommentRequest) Sanitize() {
+	req.MemberID = strings.TrimSpace(req.MemberID)
+	req.Body = SanitizeCommentBody(req.Body)
+}
+
+func (c *Comment) IsValid() error {
+	if validation := ValidateCommentBody(c.Body); !validation.IsValid {
+		return errors.New(validation.Errors[0].Message)
+	}
+	if strings.TrimSpace(c.ItemID) == "" {
+		return errors.New("item ID is required")
+	}
+	if strings.TrimSpace(c.MemberID) == "" {
+		return errors.New("member ID is required")
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+)
+
+// DeadlineStatus is where a group stands with its deadline
+type DeadlineStatus string
+
+// Deadline statuses. A group whose items are all done is completed whatever
+// its deadline; a group without a deadline is active until then.
+const (
+	DeadlineActive    DeadlineStatus = "active"
+	DeadlineDueSoon   DeadlineStatus = "due-soon"
+	DeadlineOverdue   DeadlineStatus = "overdue"
+	DeadlineCompleted DeadlineStatus = "completed"
+)
+
+// DueSoonWindow is how long before its deadline a group is due soon
+const DueSoonWindow = 7 * 24 * time.Hour
+
+// DeadlineState is a group's status derived from its deadline and progress
+// at one point in time, so it is not stored
+type DeadlineState struct {
+	Status DeadlineStatus `json:"status"`
+	// SecondsRemaining is the time left until the deadline, zero once it
+	// has passed. It is left out for groups without a deadline.
+	SecondsRemaining *int64 `json:"secondsRemaining,omitempty"`
+	// Locked reports whether the list has become read-only
+	Locked bool `json:"locked"`
+}
+
+// IsLocked reports whether the group's list is read-only at now: its
+// creator chose to lock it and its deadline has passed
+func (g *Group) IsLocked(now time.Time) bool {
+	return g.LockAfterDeadline && g.Deadline != nil && !now.Before(*g.Deadline)
+}
+
+// DeriveDeadlineState returns the group's deadline state at now, given how
+// many of its items there are and how many are completed
+func (g *Group) DeriveDeadlineState(now time.Time, itemCount, completedCount int) DeadlineState {
+	state := DeadlineState{
+		Status: DeadlineActive,
+		Locked: g.IsLocked(now),
+	}
+
+	if g.Deadline != nil {
+		remaining := g.Deadline.Sub(now)
+		if remaining < 0 {
+			remaining = 0
+		}
+		seconds := int64(remaining / time.Second)
+		state.SecondsRemaining = &seconds
+
+		switch {
+		case remaining == 0:
+			state.Status = DeadlineOverdue
+		case remaining <= DueSoonWindow:
+			state.Status = DeadlineDueSoon
+		}
+	}
+
+	if itemCount > 0 && completedCount == itemCount {
+		state.Status = DeadlineCompleted
+	}
+	return state
+}
+
+// UpdateDeadlineState sets the summary's deadline state at now
+func (s *GroupSummary) UpdateDeadlineState(now time.Time) {
+	s.DeadlineState = s.DeriveDeadlineState(now, s.ItemCount, s.CompletedCount)
+}
+
+// UpdateDeadlineState sets the group's deadline state at now
+func (d *GroupWithDetails) UpdateDeadlineState(now time.Time) {
+	completed := 0
+	for _, item := range d.Items {
+		if item.Completed {
+			completed++
+		}
+	}
+	d.DeadlineState = d.DeriveDeadlineState(now, len(d.Items), completed)
+}
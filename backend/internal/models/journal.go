@@ -0,0 +1,164 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CompletionJournal is what a group records about a completed item: how it
+// went, a rating and the day it actually happened, which may be well before
+// it was ticked off. It is cleared when the item is marked incomplete.
+type CompletionJournal struct {
+	CompletionNote   *string `json:"completionNote,omitempty" db:"completion_note"`
+	CompletionRating *int    `json:"completionRating,omitempty" db:"completion_rating"`
+	// OccurredOn is a date formatted as DateLayout
+	OccurredOn *string `json:"occurredOn,omitempty" db:"occurred_on"`
+}
+
+// Constants for completion journal validation
+const (
+	MaxCompletionNoteLength = 2000
+	MinCompletionRating     = 1
+	MaxCompletionRating     = 5
+)
+
+// DateLayout is the format of calendar dates in requests and responses
+const DateLayout = "2006-01-02"
+
+// UpdateJournalRequest is the body of a request replacing the journal of a
+// completed item. Fields left out are cleared.
+type UpdateJournalRequest struct {
+	MemberID          string `json:"memberId" binding:"required"`
+	CompletionJournal `json:",inline"`
+}
+
+// Memory is a completed item on a group's memories timeline, dated by the
+// day it happened
+type Memory struct {
+	BucketListItem `json:",inline"`
+	// Date is OccurredOn, or the day the item was completed when that is
+	// not set
+	Date string `json:"date"`
+}
+
+// IsEmpty reports whether nothing has been recorded in the journal
+func (j *CompletionJournal) IsEmpty() bool {
+	return j.CompletionNote == nil && j.CompletionRating == nil && j.OccurredOn == nil
+}
+
+// Sanitize trims the note, dropping it when blank
+func (j *CompletionJournal) Sanitize() {
+	if j.CompletionNote != nil {
+		note := strings.TrimSpace(*j.CompletionNote)
+		if note == "" {
+			j.CompletionNote = nil
+		} else {
+			j.CompletionNote = &note
+		}
+	}
+}
+
+// Validate checks the note length, the rating range and the date format.
+// Whether the date is in the future depends on the group's timezone, so it
+// is checked by ValidateOccurredOn.
+func (j *CompletionJournal) Validate() ValidationResult {
+	var errors []ValidationError
+
+	if j.CompletionNote != nil && len(strings.TrimSpace(*j.CompletionNote)) > MaxCompletionNoteLength {
+		errors = append(errors, ValidationError{
+			Field:   "completionNote",
+			Message: fmt.Sprintf("Note must be no more than %d characters", MaxCompletionNoteLength),
+		})
+	}
+
+	if j.CompletionRating != nil && (*j.CompletionRating < MinCompletionRating || *j.CompletionRating > MaxCompletionRating) {
+		errors = append(errors, ValidationError{
+			Field:   "completionRating",
+			Message: fmt.Sprintf("Rating must be between %d and %d", MinCompletionRating, MaxCompletionRating),
+		})
+	}
+
+	if j.OccurredOn != nil {
+		if _, err := time.Parse(DateLayout, *j.OccurredOn); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   "occurredOn",
+				Message: "Date must be formatted as YYYY-MM-DD",
+			})
+		}
+	}
+
+	return ValidationResult{
+		IsValid: len(errors) == 0,
+		Errors:  errors,
+	}
+}
+
+// ValidateOccurredOn checks that the item did not happen after today in
+// loc, the timezone of the item's group. A malformed date is left to
+// Validate.
+func (j *CompletionJournal) ValidateOccurredOn(now time.Time, loc *time.Location) ValidationResult {
+	if j.OccurredOn == nil {
+		return ValidationResult{IsValid: true}
+	}
+
+	date, err := time.Parse(DateLayout, *j.OccurredOn)
+	if err != nil || date.Format(DateLayout) <= now.In(loc).Format(DateLayout) {
+		return ValidationResult{IsValid: true}
+	}
+
+	return ValidationResult{
+		IsValid: false,
+		Errors: []ValidationError{{
+			Field:   "occurredOn",
+			Message: "Date cannot be in the future",
+		}},
+	}
+}
+
+func (req *UpdateJournalRequest) Validate() ValidationResult {
+	var allErrors []ValidationError
+
+	if strings.TrimSpace(req.MemberID) == "" {
+		allErrors = append(allErrors, ValidationError{
+			Field:   "memberId",
+			Message: "Member ID is required",
+		})
+	}
+	allErrors = append(allErrors, req.CompletionJournal.Validate().Errors...)
+
+	return ValidationResult{
+		IsValid: len(allErrors) == 0,
+		Errors:  allErrors,
+	}
+}
+
+// BuildMemories returns the completed items among items as a timeline,
+// most recent first. Items without a journal date are dated by the day in
+// loc they were completed.
+func BuildMemories(items []BucketListItem, loc *time.Location) []Memory {
+	memories := []Memory{}
+	for _, item := range items {
+		if !item.Completed || item.CompletedAt == nil {
+			continue
+		}
+		date := item.CompletedAt.In(loc).Format(DateLayout)
+		if item.OccurredOn != nil {
+			date = *item.OccurredOn
+		}
+		memories = append(memories, Memory{BucketListItem: item, Date: date})
+	}
+
+	sort.SliceStable(memories, func(i, j int) bool {
+		a, b := memories[i], memories[j]
+		if a.Date != b.Date {
+			return a.Date > b.Date
+		}
+		if !a.CompletedAt.Equal(*b.CompletedAt) {
+			return a.CompletedAt.After(*b.CompletedAt)
+		}
+		return a.ID < b.ID
+	})
+	return memories
+}
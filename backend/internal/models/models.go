@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strings"
 	"time"
+	"unicode"
 )
 
 // Group represents a bucket list group
@@ -13,8 +14,15 @@ type Group struct {
 	ID        string     `json:"id" db:"id"`
 	Name      string     `json:"name" db:"name"`
 	Deadline  *time.Time `json:"deadline,omitempty" db:"deadline"`
-	CreatedAt time.Time  `json:"createdAt" db:"created_at"`
-	CreatedBy string     `json:"createdBy" db:"created_by"`
+	// DeadlineDate is the last day before the deadline in the group's
+	// timezone. It is set by Localize.
+	DeadlineDate *string `json:"deadlineDate,omitempty" db:"-"`
+	// Timezone is the IANA name of the zone the group's dates are in
+	Timezone  string    `json:"timezone" db:"timezone"`
+	// LockAfterDeadline makes the list read-only once the deadline passes
+	LockAfterDeadline bool      `json:"lockAfterDeadline" db:"lock_after_deadline"`
+	CreatedAt         time.Time `json:"createdAt" db:"created_at"`
+	CreatedBy         string    `json:"createdBy" db:"created_by"`
 }
 
 // Member represents a group member
@@ -36,15 +44,37 @@ type BucketListItem struct {
 	Completed   bool       `json:"completed" db:"completed"`
 	CompletedBy *string    `json:"completedBy,omitempty" db:"completed_by"`
 	CompletedAt *time.Time `json:"completedAt,omitempty" db:"completed_at"`
-	CreatedBy   string     `json:"createdBy" db:"created_by"`
-	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
+	// CompletionJournal is recorded once the item is completed
+	CompletionJournal `json:",inline"`
+	// ItemSchedule is when the group plans to do the item
+	ItemSchedule `json:",inline"`
+	// CreatedBy is cleared when the member who added the item is deleted
+	CreatedBy *string   `json:"createdBy,omitempty" db:"created_by"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	// TagIDs lists the item's tags in ID order. They are set with
+	// TagRepository.SetItemTags, not when the item is created or updated.
+	TagIDs []string `json:"tagIds,omitempty" db:"-"`
+	// AssigneeIDs lists the members organizing the item, in the order they
+	// were assigned. They are set with BucketItemRepository.SetAssignment.
+	AssigneeIDs []string `json:"assigneeIds,omitempty" db:"-"`
 }
 
 // GroupWithDetails includes group with members and items
 type GroupWithDetails struct {
 	Group   `json:",inline"`
+	// DeadlineState is set by UpdateDeadlineState
+	DeadlineState `json:",inline"`
 	Members []Member         `json:"members"`
 	Items   []BucketListItem `json:"items"`
+	Tags    []Tag            `json:"tags"`
+	// CommentCounts maps the ID of every item to its number of comments,
+	// replies included
+	CommentCounts map[string]int `json:"commentCounts"`
+	// Reactions maps the ID of every item to its votes and emoji reactions
+	Reactions map[string]ItemReactions `json:"reactions"`
+	// Attachments maps the ID of every item to its attachments, oldest
+	// first
+	Attachments map[string][]Attachment `json:"attachments"`
 }
 
 // GroupSummary provides summary information for dashboard
@@ -54,6 +84,25 @@ type GroupSummary struct {
 	ItemCount       int     `json:"itemCount"`
 	CompletedCount  int     `json:"completedCount"`
 	ProgressPercent float64 `json:"progressPercent"`
+	// DeadlineState is set by UpdateDeadlineState
+	DeadlineState `json:",inline"`
+}
+
+// ItemSearchResult is a bucket list item matching a search query. The
+// highlights are HTML-escaped, with matching words wrapped in <mark> tags.
+type ItemSearchResult struct {
+	BucketListItem       `json:",inline"`
+	GroupName            string  `json:"groupName"`
+	Rank                 float64 `json:"rank"`
+	TitleHighlight       string  `json:"titleHighlight"`
+	DescriptionHighlight *string `json:"descriptionHighlight,omitempty"`
+}
+
+// GroupSearchResult is a group whose name matches a search query
+type GroupSearchResult struct {
+	Group         `json:",inline"`
+	Rank          float64 `json:"rank"`
+	NameHighlight string  `json:"nameHighlight"`
 }
 
 // SupabaseUser represents a user from Supabase
@@ -64,8 +113,28 @@ type SupabaseUser struct {
 
 // Request/Response types for API
 type CreateGroupRequest struct {
-	Name     string     `json:"name" binding:"required"`
-	Deadline *time.Time `json:"deadline,omitempty"`
+	Name string `json:"name" binding:"required"`
+	// Deadline is a date (YYYY-MM-DD), ending at midnight in the group's
+	// timezone, or an RFC 3339 time
+	Deadline *string `json:"deadline,omitempty"`
+	// Timezone is an IANA timezone name, DefaultTimezone if left out
+	Timezone string `json:"timezone,omitempty"`
+	// LockAfterDeadline makes the list read-only once the deadline passes
+	LockAfterDeadline bool `json:"lockAfterDeadline,omitempty"`
+	// ReminderDays are the days before the deadline members are reminded
+	// of it, DefaultReminderDays if left out
+	ReminderDays *[]int `json:"reminderDays,omitempty"`
+}
+
+// UpdateGroupRequest is the body of a request changing a group's settings.
+// Fields left out are unchanged; an empty deadline removes it.
+type UpdateGroupRequest struct {
+	Name *string `json:"name,omitempty"`
+	// Deadline is given as in CreateGroupRequest, in the group's new
+	// timezone when that changes too
+	Deadline          *string `json:"deadline,omitempty"`
+	Timezone          *string `json:"timezone,omitempty"`
+	LockAfterDeadline *bool   `json:"lockAfterDeadline,omitempty"`
 }
 
 type JoinGroupRequest struct {
@@ -77,11 +146,15 @@ type CreateItemRequest struct {
 	Title       string  `json:"title" binding:"required"`
 	Description *string `json:"description,omitempty"`
 	MemberID    string  `json:"memberId" binding:"required"`
+	// ItemSchedule may plan the item when it is added
+	ItemSchedule `json:",inline"`
 }
 
 type ToggleCompletionRequest struct {
 	Completed bool   `json:"completed"`
 	MemberID  string `json:"memberId" binding:"required"`
+	// CompletionJournal may be recorded when completing the item
+	CompletionJournal `json:",inline"`
 }
 
 // WebSocket event types
@@ -126,6 +199,9 @@ const (
 	MaxItemTitleLength       = 200
 	MinItemTitleLength       = 1
 	MaxItemDescriptionLength = 1000
+	MaxSearchQueryLength     = 100
+	DefaultSearchLimit       = 20
+	MaxSearchLimit           = 50
 )
 
 // Validation functions
@@ -242,6 +318,34 @@ func ValidateDeadline(deadline *time.Time) ValidationResult {
 	}
 }
 
+// ValidateSearchQuery checks a full-text search query
+func ValidateSearchQuery(query string) ValidationResult {
+	var errors []ValidationError
+
+	trimmed := strings.TrimSpace(query)
+	if trimmed == "" {
+		errors = append(errors, ValidationError{
+			Field:   "q",
+			Message: "Search query is required",
+		})
+	} else if len(trimmed) > MaxSearchQueryLength {
+		errors = append(errors, ValidationError{
+			Field:   "q",
+			Message: fmt.Sprintf("Search query must be no more than %d characters", MaxSearchQueryLength),
+		})
+	} else if !strings.ContainsFunc(trimmed, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) {
+		errors = append(errors, ValidationError{
+			Field:   "q",
+			Message: "Search query must contain a letter or digit",
+		})
+	}
+
+	return ValidationResult{
+		IsValid: len(errors) == 0,
+		Errors:  errors,
+	}
+}
+
 func ValidateUUID(id string) ValidationResult {
 	var errors []ValidationError
 	
@@ -265,11 +369,16 @@ func ValidateUUID(id string) ValidationResult {
 // Validation methods for structs
 func (req *CreateGroupRequest) Validate() ValidationResult {
 	nameValidation := ValidateGroupName(req.Name)
-	deadlineValidation := ValidateDeadline(req.Deadline)
+	timezoneValidation := ValidateTimezone(req.Timezone)
+	_, deadlineErrors := validateDeadlineInput(req.Deadline, req.Timezone)
 	
 	var allErrors []ValidationError
 	allErrors = append(allErrors, nameValidation.Errors...)
-	allErrors = append(allErrors, deadlineValidation.Errors...)
+	allErrors = append(allErrors, timezoneValidation.Errors...)
+	allErrors = append(allErrors, deadlineErrors...)
+	if req.ReminderDays != nil {
+		allErrors = append(allErrors, ValidateReminderDays(*req.ReminderDays).Errors...)
+	}
 	
 	return ValidationResult{
 		IsValid: len(allErrors) == 0,
@@ -277,6 +386,12 @@ func (req *CreateGroupRequest) Validate() ValidationResult {
 	}
 }
 
+// ParsedDeadline returns the deadline of a validated request
+func (req *CreateGroupRequest) ParsedDeadline() *time.Time {
+	deadline, _ := validateDeadlineInput(req.Deadline, req.Timezone)
+	return deadline
+}
+
 func (req *JoinGroupRequest) Validate() ValidationResult {
 	return ValidateMemberName(req.MemberName)
 }
@@ -296,6 +411,9 @@ func (req *CreateItemRequest) Validate() ValidationResult {
 		})
 	}
 	
+	// The group deadline is checked once the group is known
+	errors = append(errors, req.ItemSchedule.Validate(nil).Errors...)
+	
 	return ValidationResult{
 		IsValid: len(errors) == 0,
 		Errors:  errors,
@@ -312,6 +430,14 @@ func (req *ToggleCompletionRequest) Validate() ValidationResult {
 		})
 	}
 	
+	if !req.Completed && !req.CompletionJournal.IsEmpty() {
+		errors = append(errors, ValidationError{
+			Field:   "completed",
+			Message: "A journal can only be recorded when completing an item",
+		})
+	}
+	errors = append(errors, req.CompletionJournal.Validate().Errors...)
+	
 	return ValidationResult{
 		IsValid: len(errors) == 0,
 		Errors:  errors,
@@ -328,6 +454,10 @@ func SanitizeString(input string) string {
 
 func (g *Group) Sanitize() {
 	g.Name = SanitizeString(g.Name)
+	g.Timezone = strings.TrimSpace(g.Timezone)
+	if g.Timezone == "" {
+		g.Timezone = DefaultTimezone
+	}
 }
 
 func (m *Member) Sanitize() {
@@ -340,10 +470,23 @@ func (b *BucketListItem) Sanitize() {
 		sanitized := SanitizeString(*b.Description)
 		b.Description = &sanitized
 	}
+	// Only completed items keep a journal
+	if b.Completed {
+		b.CompletionJournal.Sanitize()
+	} else {
+		b.CompletionJournal = CompletionJournal{}
+	}
 }
 
 func (req *CreateGroupRequest) Sanitize() {
 	req.Name = SanitizeString(req.Name)
+	req.Timezone = strings.TrimSpace(req.Timezone)
+	if req.Timezone == "" {
+		req.Timezone = DefaultTimezone
+	}
+	if req.Deadline != nil && strings.TrimSpace(*req.Deadline) == "" {
+		req.Deadline = nil
+	}
 }
 
 func (req *JoinGroupRequest) Sanitize() {
@@ -365,9 +508,10 @@ func (g *Group) IsValid() error {
 		return errors.New(validation.Errors[0].Message)
 	}
 	
-	deadlineValidation := ValidateDeadline(g.Deadline)
-	if !deadlineValidation.IsValid {
-		return errors.New(deadlineValidation.Errors[0].Message)
+	// A group's deadline may pass; requests setting it check it is ahead
+	timezoneValidation := ValidateTimezone(g.Timezone)
+	if !timezoneValidation.IsValid {
+		return errors.New(timezoneValidation.Errors[0].Message)
 	}
 	
 	if strings.TrimSpace(g.CreatedBy) == "" {
@@ -405,9 +549,17 @@ func (b *BucketListItem) IsValid() error {
 		return errors.New("group ID is required")
 	}
 	
-	if strings.TrimSpace(b.CreatedBy) == "" {
+	if b.CreatedBy == nil || strings.TrimSpace(*b.CreatedBy) == "" {
 		return errors.New("created by member ID is required")
 	}
 	
+	if journalValidation := b.CompletionJournal.Validate(); !journalValidation.IsValid {
+		return errors.New(journalValidation.Errors[0].Message)
+	}
+	
+	if scheduleValidation := b.ItemSchedule.Validate(nil); !scheduleValidation.IsValid {
+		return errors.New(scheduleValidation.Errors[0].Message)
+	}
+	
 	return nil
 }
\ No newline at end of file
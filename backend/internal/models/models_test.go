@@ -1,6 +1,8 @@
 package models
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 )
@@ -69,6 +71,80 @@ func TestValidateItemTitle(t *testing.T) {
 	}
 }
 
+func TestValidateSearchQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{"valid query", "hiking trip", true},
+		{"empty query", "   ", false},
+		{"punctuation only", "?!*", false},
+		{"too long", strings.Repeat("a", MaxSearchQueryLength+1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateSearchQuery(tt.input)
+			if result.IsValid != tt.expected {
+				t.Errorf("ValidateSearchQuery(%q) = %v, want %v", tt.input, result.IsValid, tt.expected)
+			}
+		})
+	}
+}
+
+func TestItemListOptionsValidate(t *testing.T) {
+	earlier := time.Now()
+	later := earlier.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		opts     ItemListOptions
+		expected bool
+	}{
+		{"defaults", ItemListOptions{}, true},
+		{"title sort", ItemListOptions{Sort: ItemSortTitle, Limit: MaxPageLimit}, true},
+		{"unknown sort", ItemListOptions{Sort: "random"}, false},
+		{"invalid creator", ItemListOptions{CreatedBy: "not-a-uuid"}, false},
+		{"reversed range", ItemListOptions{CreatedAfter: &later, CreatedBefore: &earlier}, false},
+		{"limit too large", ItemListOptions{Limit: MaxPageLimit + 1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.opts.Validate()
+			if result.IsValid != tt.expected {
+				t.Errorf("Validate() = %v, want %v (%v)", result.IsValid, tt.expected, result.Errors)
+			}
+		})
+	}
+}
+
+func TestGroupSummaryListOptionsValidate(t *testing.T) {
+	low, high, tooHigh := 10.0, 90.0, 101.0
+
+	tests := []struct {
+		name     string
+		opts     GroupSummaryListOptions
+		expected bool
+	}{
+		{"defaults", GroupSummaryListOptions{}, true},
+		{"progress range", GroupSummaryListOptions{Sort: GroupSortProgress, MinProgress: &low, MaxProgress: &high}, true},
+		{"unknown sort", GroupSummaryListOptions{Sort: "name"}, false},
+		{"progress out of range", GroupSummaryListOptions{MaxProgress: &tooHigh}, false},
+		{"reversed progress", GroupSummaryListOptions{MinProgress: &high, MaxProgress: &low}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.opts.Validate()
+			if result.IsValid != tt.expected {
+				t.Errorf("Validate() = %v, want %v (%v)", result.IsValid, tt.expected, result.Errors)
+			}
+		})
+	}
+}
+
 func TestValidateItemDescription(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -115,8 +191,11 @@ func TestValidateDeadline(t *testing.T) {
 }
 
 func TestCreateGroupRequestValidate(t *testing.T) {
-	future := time.Now().Add(24 * time.Hour)
-	past := time.Now().Add(-24 * time.Hour)
+	future := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+	past := time.Now().Add(-24 * time.Hour).Format(time.RFC3339)
+	yesterday := time.Now().In(time.UTC).AddDate(0, 0, -2).Format(DateLayout)
+	today := time.Now().In(time.UTC).Format(DateLayout)
+	malformed := "31/12/2026"
 
 	tests := []struct {
 		name     string
@@ -133,6 +212,26 @@ func TestCreateGroupRequestValidate(t *testing.T) {
 			CreateGroupRequest{Name: "", Deadline: &past},
 			false,
 		},
+		{
+			"deadline ending today",
+			CreateGroupRequest{Name: "My Group", Deadline: &today, Timezone: "UTC"},
+			true,
+		},
+		{
+			"deadline date passed",
+			CreateGroupRequest{Name: "My Group", Deadline: &yesterday, Timezone: "Pacific/Kiritimati"},
+			false,
+		},
+		{
+			"malformed deadline",
+			CreateGroupRequest{Name: "My Group", Deadline: &malformed},
+			false,
+		},
+		{
+			"unknown timezone",
+			CreateGroupRequest{Name: "My Group", Timezone: "Mars/Olympus_Mons"},
+			false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -193,6 +292,755 @@ func TestCreateItemRequestValidate(t *testing.T) {
 	}
 }
 
+func TestValidateTagColor(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{"lowercase hex", "#1e90ff", true},
+		{"uppercase hex", "#1E90FF", true},
+		{"missing hash", "1e90ff", false},
+		{"short form", "#fff", false},
+		{"named color", "red", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateTagColor(tt.input)
+			if result.IsValid != tt.expected {
+				t.Errorf("ValidateTagColor(%q) = %v, want %v", tt.input, result.IsValid, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTagRequestsValidate(t *testing.T) {
+	name, color, empty := "Food", "#ff0000", ""
+	tagIDs := make([]string, MaxTagsPerItem+1)
+	for i := range tagIDs {
+		tagIDs[i] = "123e4567-e89b-42d3-a456-426614174000"
+	}
+
+	tests := []struct {
+		name     string
+		request  interface{ Validate() ValidationResult }
+		expected bool
+	}{
+		{"create with color", &CreateTagRequest{Name: "Food", Color: "#ff0000"}, true},
+		{"create without color", &CreateTagRequest{Name: "Food"}, true},
+		{"create with long name", &CreateTagRequest{Name: strings.Repeat("a", MaxTagNameLength+1)}, false},
+		{"update name", &UpdateTagRequest{Name: &name}, true},
+		{"update color", &UpdateTagRequest{Color: &color}, true},
+		{"update nothing", &UpdateTagRequest{}, false},
+		{"update to empty name", &UpdateTagRequest{Name: &empty}, false},
+		{"set tags", &SetItemTagsRequest{TagIDs: tagIDs[:2], MemberID: "member-123"}, true},
+		{"clear tags", &SetItemTagsRequest{MemberID: "member-123"}, true},
+		{"too many tags", &SetItemTagsRequest{TagIDs: tagIDs, MemberID: "member-123"}, false},
+		{"malformed tag ID", &SetItemTagsRequest{TagIDs: []string{"food"}, MemberID: "member-123"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.request.Validate()
+			if result.IsValid != tt.expected {
+				t.Errorf("Validate() = %v, want %v", result.IsValid, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCommentRequestsValidate(t *testing.T) {
+	parentID, badParentID := "123e4567-e89b-42d3-a456-426614174000", "comment-1"
+
+	tests := []struct {
+		name     string
+		request  interface{ Validate() ValidationResult }
+		expected bool
+	}{
+		{"create", &CreateCommentRequest{MemberID: "member-123", Body: "Which weekend?"}, true},
+		{"create reply", &CreateCommentRequest{MemberID: "member-123", Body: "The first", ParentID: &parentID}, true},
+		{"create with malformed parent ID", &CreateCommentRequest{MemberID: "member-123", Body: "Hi", ParentID: &badParentID}, false},
+		{"create without member", &CreateCommentRequest{Body: "Hi"}, false},
+		{"create blank", &CreateCommentRequest{MemberID: "member-123", Body: " \n "}, false},
+		{"create too long", &CreateCommentRequest{MemberID: "member-123", Body: strings.Repeat("a", MaxCommentLength+1)}, false},
+		{"update", &UpdateCommentRequest{MemberID: "member-123", Body: "Booked"}, true},
+		{"update blank", &UpdateCommentRequest{MemberID: "member-123", Body: ""}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.request.Validate()
+			if result.IsValid != tt.expected {
+				t.Errorf("Validate() = %v, want %v", result.IsValid, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAssignItemRequestValidate(t *testing.T) {
+	assigneeID := "123e4567-e89b-42d3-a456-426614174000"
+
+	tests := []struct {
+		name     string
+		request  AssignItemRequest
+		expected bool
+	}{
+		{"valid", AssignItemRequest{AssigneeID: assigneeID, MemberID: "member-123"}, true},
+		{"malformed assignee ID", AssignItemRequest{AssigneeID: "member-1", MemberID: "member-123"}, false},
+		{"without assignee", AssignItemRequest{MemberID: "member-123"}, false},
+		{"without member", AssignItemRequest{AssigneeID: assigneeID, MemberID: "  "}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.request.Validate()
+			if result.IsValid != tt.expected {
+				t.Errorf("Validate() = %v, want %v", result.IsValid, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSanitizeCommentBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"trims and collapses spaces", "  I'll \t book  it  ", "I'll book it"},
+		{"keeps line breaks", "Day one\nDay two", "Day one\nDay two"},
+		{"normalizes CRLF", "Day one\r\nDay two", "Day one\nDay two"},
+		{"allows one blank line", "Plan:\n\n\n\n- book", "Plan:\n\n- book"},
+		{"trims lines", "Plan:  \n   - book", "Plan:\n- book"},
+		{"trims blank edges", "\n\n Hi \n\n", "Hi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := SanitizeCommentBody(tt.input); result != tt.expected {
+				t.Errorf("SanitizeCommentBody() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateEmoji(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{"single emoji", "👍", true},
+		{"with variation selector", "❤️", true},
+		{"with skin tone", "👍🏽", true},
+		{"ZWJ sequence", "👩‍🚀", true},
+		{"flag", "🏳️‍🌈", true},
+		{"surrounding spaces", " 🎉 ", true},
+		{"empty", "", false},
+		{"text", "yes", false},
+		{"emoji with text", "👍 yes", false},
+		{"joiner only", "\u200d", false},
+		{"too long", strings.Repeat("🎉", MaxEmojiLength/4+1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateEmoji(tt.input)
+			if result.IsValid != tt.expected {
+				t.Errorf("ValidateEmoji(%q) = %v, want %v", tt.input, result.IsValid, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateAttachment(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		size        int64
+		expected    bool
+	}{
+		{"jpeg photo", "image/jpeg", 2 << 20, true},
+		{"pdf", "application/pdf", 1, true},
+		{"largest allowed", "image/png", MaxAttachmentSize, true},
+		{"too large", "image/png", MaxAttachmentSize + 1, false},
+		{"empty", "image/png", 0, false},
+		{"html", "text/html", 100, false},
+		{"svg", "image/svg+xml", 100, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typeResult := ValidateAttachmentContentType(tt.contentType)
+			sizeResult := ValidateAttachmentSize(tt.size)
+			if result := typeResult.IsValid && sizeResult.IsValid; result != tt.expected {
+				t.Errorf("validating %s of %d bytes = %v, want %v", tt.contentType, tt.size, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	long := strings.Repeat("a", MaxAttachmentFileNameLength) + ".jpg"
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain", "beach.jpg", "beach.jpg"},
+		{"unix path", "../../etc/passwd", "passwd"},
+		{"windows path", `C:\Users\me\beach.jpg`, "beach.jpg"},
+		{"control characters", "be\x00ach\n.jpg", "beach.jpg"},
+		{"surrounding spaces", "  beach.jpg  ", "beach.jpg"},
+		{"empty", "", "attachment"},
+		{"only a directory", "photos/", "photos"},
+		{"dot", ".", "attachment"},
+		{"long name keeps extension", long, strings.Repeat("a", MaxAttachmentFileNameLength-4) + ".jpg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := SanitizeFileName(tt.input); result != tt.expected {
+				t.Errorf("SanitizeFileName(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestThumbnailVariants(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+
+	tests := []struct {
+		name     string
+		width    int
+		height   int
+		expected [][2]int
+	}{
+		{"landscape", 4000, 3000, [][2]int{{160, 120}, {480, 360}, {1280, 960}}},
+		{"portrait", 300, 2000, [][2]int{{24, 160}, {72, 480}, {192, 1280}}},
+		{"never upscaled", 200, 100, [][2]int{{160, 80}, {200, 100}, {200, 100}}},
+		{"thin strip keeps a pixel", 5000, 1, [][2]int{{160, 1}, {480, 1}, {1280, 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attachment := Attachment{
+				ContentType:      "image/jpeg",
+				Width:            intPtr(tt.width),
+				Height:           intPtr(tt.height),
+				ProcessingStatus: AttachmentProcessed,
+				StorageKey:       "groups/g/items/i/a",
+			}
+
+			variants := attachment.ThumbnailVariants()
+			if len(variants) != len(ThumbnailSizes) {
+				t.Fatalf("got %d thumbnails, want %d", len(variants), len(ThumbnailSizes))
+			}
+			for i, variant := range variants {
+				if variant.Width != tt.expected[i][0] || variant.Height != tt.expected[i][1] {
+					t.Errorf("%s thumbnail is %dx%d, want %dx%d", variant.Size, variant.Width, variant.Height, tt.expected[i][0], tt.expected[i][1])
+				}
+				if want := "groups/g/items/i/a-" + ThumbnailSizes[i].Name; variant.StorageKey != want {
+					t.Errorf("%s thumbnail key = %q, want %q", variant.Size, variant.StorageKey, want)
+				}
+			}
+		})
+	}
+
+	pending := Attachment{ContentType: "image/jpeg", Width: intPtr(10), Height: intPtr(10), ProcessingStatus: AttachmentPending}
+	if variants := pending.ThumbnailVariants(); len(variants) != 0 {
+		t.Errorf("pending attachment has %d thumbnails, want none", len(variants))
+	}
+	pdf := Attachment{ContentType: "application/pdf", ProcessingStatus: AttachmentProcessed}
+	if variants := pdf.ThumbnailVariants(); len(variants) != 0 {
+		t.Errorf("PDF has %d thumbnails, want none", len(variants))
+	}
+}
+
+func TestCompletionJournalValidate(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+	intPtr := func(i int) *int { return &i }
+
+	tests := []struct {
+		name    string
+		journal CompletionJournal
+		field   string
+	}{
+		{"empty", CompletionJournal{}, ""},
+		{"complete", CompletionJournal{CompletionNote: strPtr("Loved it"), CompletionRating: intPtr(5), OccurredOn: strPtr("2023-08-14")}, ""},
+		{"note too long", CompletionJournal{CompletionNote: strPtr(strings.Repeat("a", MaxCompletionNoteLength+1))}, "completionNote"},
+		{"rating too low", CompletionJournal{CompletionRating: intPtr(0)}, "completionRating"},
+		{"rating too high", CompletionJournal{CompletionRating: intPtr(6)}, "completionRating"},
+		{"malformed date", CompletionJournal{OccurredOn: strPtr("14/08/2023")}, "occurredOn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.journal.Validate()
+			if result.IsValid != (tt.field == "") {
+				t.Fatalf("Validate() IsValid = %v, errors %v", result.IsValid, result.Errors)
+			}
+			if tt.field != "" && result.Errors[0].Field != tt.field {
+				t.Errorf("Validate() error field = %q, want %q", result.Errors[0].Field, tt.field)
+			}
+		})
+	}
+}
+
+func TestCompletionJournalValidateOccurredOn(t *testing.T) {
+	// Already June 2 in Auckland and still June 1 in Los Angeles
+	now := time.Date(2024, 6, 1, 20, 0, 0, 0, time.UTC)
+	auckland, err := LoadTimezone("Pacific/Auckland")
+	if err != nil {
+		t.Fatal(err)
+	}
+	losAngeles, err := LoadTimezone("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		occurredOn string
+		loc        *time.Location
+		valid      bool
+	}{
+		{"today in UTC", "2024-06-01", time.UTC, true},
+		{"tomorrow in UTC", "2024-06-02", time.UTC, false},
+		{"today east of UTC", "2024-06-02", auckland, true},
+		{"tomorrow east of UTC", "2024-06-03", auckland, false},
+		{"today west of UTC", "2024-06-01", losAngeles, true},
+		{"tomorrow west of UTC", "2024-06-02", losAngeles, false},
+		{"malformed date", "14/08/2023", time.UTC, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			journal := CompletionJournal{OccurredOn: &tt.occurredOn}
+			result := journal.ValidateOccurredOn(now, tt.loc)
+			if result.IsValid != tt.valid {
+				t.Errorf("ValidateOccurredOn() IsValid = %v, want %v", result.IsValid, tt.valid)
+			}
+		})
+	}
+}
+
+func TestToggleCompletionRequestJournal(t *testing.T) {
+	note := "Worth the wait"
+	req := ToggleCompletionRequest{
+		Completed:         false,
+		MemberID:          "member",
+		CompletionJournal: CompletionJournal{CompletionNote: &note},
+	}
+	if req.Validate().IsValid {
+		t.Error("a journal is accepted when marking an item incomplete")
+	}
+
+	req.Completed = true
+	if result := req.Validate(); !result.IsValid {
+		t.Errorf("a journal is rejected when completing an item: %v", result.Errors)
+	}
+}
+
+func TestBuildMemories(t *testing.T) {
+	at := func(day int) *time.Time {
+		completedAt := time.Date(2024, 6, day, 9, 0, 0, 0, time.UTC)
+		return &completedAt
+	}
+	occurredOn := "2023-08-14"
+
+	items := []BucketListItem{
+		{ID: "open", Title: "Not yet"},
+		{ID: "early", Completed: true, CompletedAt: at(2)},
+		{ID: "late", Completed: true, CompletedAt: at(5)},
+		{ID: "backdated", Completed: true, CompletedAt: at(6), CompletionJournal: CompletionJournal{OccurredOn: &occurredOn}},
+	}
+
+	memories := BuildMemories(items, time.UTC)
+	expected := []struct{ id, date string }{
+		{"late", "2024-06-05"},
+		{"early", "2024-06-02"},
+		{"backdated", "2023-08-14"},
+	}
+	if len(memories) != len(expected) {
+		t.Fatalf("BuildMemories() returned %d memories, want %d", len(memories), len(expected))
+	}
+	for i, want := range expected {
+		if memories[i].ID != want.id || memories[i].Date != want.date {
+			t.Errorf("memory %d = %s on %s, want %s on %s", i, memories[i].ID, memories[i].Date, want.id, want.date)
+		}
+	}
+
+	// Completion days are in the group's timezone
+	honolulu := time.FixedZone("HST", -10*60*60)
+	if memories := BuildMemories(items[:2], honolulu); len(memories) != 1 || memories[0].Date != "2024-06-01" {
+		t.Errorf("BuildMemories() in Honolulu = %+v, want early on 2024-06-01", memories)
+	}
+
+	if memories := BuildMemories(nil, time.UTC); memories == nil || len(memories) != 0 {
+		t.Errorf("BuildMemories(nil) = %v, want an empty list", memories)
+	}
+}
+
+func TestItemScheduleValidate(t *testing.T) {
+	at := func(day, hour int) *time.Time {
+		value := time.Date(2026, 7, day, hour, 0, 0, 0, time.UTC)
+		return &value
+	}
+	deadline := at(10, 0)
+
+	tests := []struct {
+		name     string
+		schedule ItemSchedule
+		deadline *time.Time
+		expected bool
+	}{
+		{"unscheduled", ItemSchedule{}, deadline, true},
+		{"point in time", ItemSchedule{ScheduledStart: at(4, 18)}, deadline, true},
+		{"span", ItemSchedule{ScheduledStart: at(4, 18), ScheduledEnd: at(5, 2)}, deadline, true},
+		{"span ending at the deadline", ItemSchedule{ScheduledStart: at(9, 12), ScheduledEnd: at(10, 0)}, deadline, true},
+		{"no deadline", ItemSchedule{ScheduledStart: at(20, 9)}, nil, true},
+		{"end without start", ItemSchedule{ScheduledEnd: at(4, 18)}, deadline, false},
+		{"end before start", ItemSchedule{ScheduledStart: at(4, 18), ScheduledEnd: at(4, 17)}, deadline, false},
+		{"empty span", ItemSchedule{ScheduledStart: at(4, 18), ScheduledEnd: at(4, 18)}, deadline, false},
+		{"starts at the deadline", ItemSchedule{ScheduledStart: at(10, 0)}, deadline, false},
+		{"ends after the deadline", ItemSchedule{ScheduledStart: at(9, 12), ScheduledEnd: at(10, 1)}, deadline, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.schedule.Validate(tt.deadline)
+			if result.IsValid != tt.expected {
+				t.Errorf("Validate() = %v, want %v: %v", result.IsValid, tt.expected, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateSchedulesByDeadline(t *testing.T) {
+	at := func(day int) *time.Time {
+		value := time.Date(2026, 7, day, 12, 0, 0, 0, time.UTC)
+		return &value
+	}
+	items := []BucketListItem{
+		{ID: "early", Title: "Early", ItemSchedule: ItemSchedule{ScheduledStart: at(4)}},
+		{ID: "late", Title: "Late", ItemSchedule: ItemSchedule{ScheduledStart: at(8)}},
+		{ID: "overrunning", Title: "Overrunning", ItemSchedule: ItemSchedule{ScheduledStart: at(5), ScheduledEnd: at(7)}},
+		{ID: "unscheduled", Title: "Someday"},
+	}
+
+	result := ValidateSchedulesByDeadline(items, at(6))
+	if result.IsValid || len(result.Errors) != 2 {
+		t.Fatalf("ValidateSchedulesByDeadline() = %v, want the late and overrunning items", result.Errors)
+	}
+	if !strings.Contains(result.Errors[0].Message, "late") || !strings.Contains(result.Errors[1].Message, "overrunning") {
+		t.Errorf("ValidateSchedulesByDeadline() errors = %v", result.Errors)
+	}
+
+	if result := ValidateSchedulesByDeadline(items, nil); !result.IsValid {
+		t.Errorf("ValidateSchedulesByDeadline() without a deadline = %v", result.Errors)
+	}
+}
+
+func TestParseCalendarRange(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC)
+
+	t.Run("defaults to six weeks from today", func(t *testing.T) {
+		r, result := ParseCalendarRange("", "", now, tokyo)
+		if !result.IsValid {
+			t.Fatalf("ParseCalendarRange() errors = %v", result.Errors)
+		}
+		// It is already the 19th in Tokyo
+		if want := time.Date(2026, 10, 19, 0, 0, 0, 0, tokyo); !r.From.Equal(want) {
+			t.Errorf("From = %v, want %v", r.From, want)
+		}
+		if want := r.From.AddDate(0, 0, DefaultCalendarDays); !r.To.Equal(want) {
+			t.Errorf("To = %v, want %v", r.To, want)
+		}
+	})
+
+	t.Run("inclusive dates", func(t *testing.T) {
+		r, result := ParseCalendarRange("2026-10-01", "2026-10-31", now, time.UTC)
+		if !result.IsValid {
+			t.Fatalf("ParseCalendarRange() errors = %v", result.Errors)
+		}
+		if want := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC); !r.To.Equal(want) {
+			t.Errorf("To = %v, want %v", r.To, want)
+		}
+	})
+
+	tests := []struct {
+		name, from, to string
+	}{
+		{"malformed from", "10/01/2026", "2026-10-31"},
+		{"malformed to", "2026-10-01", "soon"},
+		{"to before from", "2026-10-31", "2026-10-01"},
+		{"too long", "2026-01-01", "2027-01-02"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, result := ParseCalendarRange(tt.from, tt.to, now, time.UTC); result.IsValid {
+				t.Error("ParseCalendarRange() accepted an invalid range")
+			}
+		})
+	}
+}
+
+func TestBuildCalendar(t *testing.T) {
+	at := func(day, hour int) *time.Time {
+		value := time.Date(2026, 7, day, hour, 0, 0, 0, time.UTC)
+		return &value
+	}
+	items := []BucketListItem{
+		{ID: "unscheduled"},
+		{ID: "dinner", ItemSchedule: ItemSchedule{ScheduledStart: at(4, 19)}},
+		{ID: "festival", ItemSchedule: ItemSchedule{ScheduledStart: at(3, 10), ScheduledEnd: at(5, 0)}},
+		{ID: "brunch", ItemSchedule: ItemSchedule{ScheduledStart: at(4, 11)}},
+		{ID: "later", ItemSchedule: ItemSchedule{ScheduledStart: at(20, 9)}},
+	}
+	r := CalendarRange{From: *at(4, 0), To: *at(6, 0)}
+
+	calendar := BuildCalendar(items, r)
+	if calendar.From != "2026-07-04" || calendar.To != "2026-07-05" || calendar.Timezone != "UTC" {
+		t.Errorf("calendar covers %s to %s in %s", calendar.From, calendar.To, calendar.Timezone)
+	}
+	// The festival ends at midnight, so it is not on the 5th
+	if len(calendar.Days) != 1 || calendar.Days[0].Date != "2026-07-04" {
+		t.Fatalf("BuildCalendar() days = %+v, want only 2026-07-04", calendar.Days)
+	}
+	var ids []string
+	for _, item := range calendar.Days[0].Items {
+		ids = append(ids, item.ID)
+	}
+	if want := []string{"festival", "brunch", "dinner"}; strings.Join(ids, ",") != strings.Join(want, ",") {
+		t.Errorf("items on 2026-07-04 = %v, want %v", ids, want)
+	}
+
+	// Days are laid out in the range's location: in Tokyo the festival ends
+	// and dinner starts on the morning of the 5th
+	tokyo := time.FixedZone("JST", 9*60*60)
+	calendar = BuildCalendar(items, CalendarRange{
+		From: time.Date(2026, 7, 5, 0, 0, 0, 0, tokyo),
+		To:   time.Date(2026, 7, 6, 0, 0, 0, 0, tokyo),
+	})
+	ids = nil
+	for _, day := range calendar.Days {
+		for _, item := range day.Items {
+			ids = append(ids, day.Date+" "+item.ID)
+		}
+	}
+	if want := []string{"2026-07-05 festival", "2026-07-05 dinner"}; strings.Join(ids, ",") != strings.Join(want, ",") {
+		t.Errorf("items in Tokyo = %v, want %v", ids, want)
+	}
+
+	if calendar := BuildCalendar(nil, r); calendar.Days == nil || len(calendar.Days) != 0 {
+		t.Errorf("BuildCalendar(nil) days = %v, want an empty list", calendar.Days)
+	}
+}
+
+func TestParseDeadline(t *testing.T) {
+	tokyo, err := LoadTimezone("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("LoadTimezone() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Time
+	}{
+		{"date ends at midnight in the zone", "2026-12-31", time.Date(2026, 12, 31, 15, 0, 0, 0, time.UTC)},
+		{"time keeps its offset", "2026-12-31T18:00:00+01:00", time.Date(2026, 12, 31, 17, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deadline, err := ParseDeadline(tt.input, tokyo)
+			if err != nil || !deadline.Equal(tt.expected) {
+				t.Errorf("ParseDeadline(%q) = %v, %v, want %v", tt.input, deadline, err, tt.expected)
+			}
+		})
+	}
+
+	if _, err := ParseDeadline("next friday", tokyo); err == nil {
+		t.Error("ParseDeadline() accepted a malformed deadline")
+	}
+}
+
+func TestLoadTimezone(t *testing.T) {
+	for _, name := range []string{"", "UTC", "Asia/Tokyo", "America/New_York"} {
+		if _, err := LoadTimezone(name); err != nil {
+			t.Errorf("LoadTimezone(%q) error = %v", name, err)
+		}
+	}
+	for _, name := range []string{"Local", "Mars/Olympus_Mons", "+09:00"} {
+		if _, err := LoadTimezone(name); !errors.Is(err, ErrInvalidTimezone) {
+			t.Errorf("LoadTimezone(%q) error = %v, want ErrInvalidTimezone", name, err)
+		}
+	}
+}
+
+func TestGroupLocalize(t *testing.T) {
+	deadline := time.Date(2026, 12, 31, 15, 0, 0, 0, time.UTC)
+	group := Group{Name: "Tokyo trip", Deadline: &deadline, Timezone: "Asia/Tokyo"}
+	group.Localize()
+
+	if group.DeadlineDate == nil || *group.DeadlineDate != "2026-12-31" {
+		t.Errorf("DeadlineDate = %v, want 2026-12-31", group.DeadlineDate)
+	}
+	if group.Deadline.Location().String() != "Asia/Tokyo" || !group.Deadline.Equal(deadline) {
+		t.Errorf("Deadline = %v, want %v in Asia/Tokyo", group.Deadline, deadline)
+	}
+
+	group = Group{Name: "No deadline"}
+	group.Localize()
+	if group.Timezone != DefaultTimezone || group.DeadlineDate != nil {
+		t.Errorf("Localize() without a deadline = %s, %v", group.Timezone, group.DeadlineDate)
+	}
+}
+
+func TestUpdateGroupRequestApply(t *testing.T) {
+	newGroup := func() *Group {
+		deadline := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+		return &Group{Name: "Trip", Deadline: &deadline, Timezone: "UTC"}
+	}
+
+	// A deadline ending a day keeps its date in the new zone
+	group := newGroup()
+	tokyo := "Asia/Tokyo"
+	req := UpdateGroupRequest{Timezone: &tokyo}
+	if result := req.Apply(group); !result.IsValid {
+		t.Fatalf("Apply() = %v", result.Errors)
+	}
+	if want := time.Date(2098, 12, 31, 15, 0, 0, 0, time.UTC); !group.Deadline.Equal(want) || *group.DeadlineDate != "2098-12-31" {
+		t.Errorf("deadline = %v on %s, want %v", group.Deadline, *group.DeadlineDate, want)
+	}
+
+	// A new date is read in the new zone
+	group = newGroup()
+	date := "2099-06-30"
+	req = UpdateGroupRequest{Timezone: &tokyo, Deadline: &date}
+	if result := req.Apply(group); !result.IsValid {
+		t.Fatalf("Apply() = %v", result.Errors)
+	}
+	if want := time.Date(2099, 6, 30, 15, 0, 0, 0, time.UTC); !group.Deadline.Equal(want) {
+		t.Errorf("deadline = %v, want %v", group.Deadline, want)
+	}
+
+	// An empty deadline removes it
+	group = newGroup()
+	empty := ""
+	req = UpdateGroupRequest{Deadline: &empty}
+	if result := req.Apply(group); !result.IsValid || group.Deadline != nil {
+		t.Errorf("Apply() = %v, deadline %v", result.Errors, group.Deadline)
+	}
+
+	// A passed deadline is rejected
+	group = newGroup()
+	past := "2001-01-01"
+	req = UpdateGroupRequest{Deadline: &past}
+	if req.Apply(group).IsValid {
+		t.Error("Apply() accepted a passed deadline")
+	}
+}
+
+func TestValidateReminderDays(t *testing.T) {
+	tests := []struct {
+		name     string
+		days     []int
+		expected bool
+	}{
+		{"default schedule", DefaultReminderDays, true},
+		{"no reminders", nil, true},
+		{"furthest allowed", []int{MaxReminderDays}, true},
+		{"zero days", []int{0}, false},
+		{"too far ahead", []int{MaxReminderDays + 1}, false},
+		{"too many", []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateReminderDays(tt.days)
+			if result.IsValid != tt.expected {
+				t.Errorf("ValidateReminderDays(%v) = %v, want %v", tt.days, result.IsValid, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeReminderDays(t *testing.T) {
+	got := NormalizeReminderDays([]int{1, 30, 7, 1})
+	want := []int{30, 7, 1}
+	if len(got) != len(want) {
+		t.Fatalf("NormalizeReminderDays() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("NormalizeReminderDays() = %v, want %v", got, want)
+		}
+	}
+
+	request := &CreateGroupRequest{Name: "Trip"}
+	if schedule := request.ReminderSchedule(); len(schedule) != len(DefaultReminderDays) {
+		t.Errorf("ReminderSchedule() = %v, want %v", schedule, DefaultReminderDays)
+	}
+	request.ReminderDays = &[]int{}
+	if schedule := request.ReminderSchedule(); len(schedule) != 0 {
+		t.Errorf("ReminderSchedule() = %v, want no reminders", schedule)
+	}
+}
+
+func TestDeriveDeadlineState(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		deadline := now.Add(d)
+		return &deadline
+	}
+
+	tests := []struct {
+		name      string
+		group     Group
+		items     int
+		completed int
+		status    DeadlineStatus
+		seconds   *int64
+		locked    bool
+	}{
+		{"no deadline", Group{}, 2, 1, DeadlineActive, nil, false},
+		{"far off", Group{Deadline: at(30 * 24 * time.Hour)}, 0, 0, DeadlineActive, int64Ptr(30 * 24 * 3600), false},
+		{"due soon", Group{Deadline: at(DueSoonWindow)}, 3, 0, DeadlineDueSoon, int64Ptr(7 * 24 * 3600), false},
+		{"overdue", Group{Deadline: at(-time.Hour)}, 3, 2, DeadlineOverdue, int64Ptr(0), false},
+		{"overdue and locked", Group{Deadline: at(-time.Hour), LockAfterDeadline: true}, 1, 0, DeadlineOverdue, int64Ptr(0), true},
+		{"locked at the deadline", Group{Deadline: at(0), LockAfterDeadline: true}, 1, 0, DeadlineOverdue, int64Ptr(0), true},
+		{"lock before the deadline", Group{Deadline: at(time.Hour), LockAfterDeadline: true}, 1, 0, DeadlineDueSoon, int64Ptr(3600), false},
+		{"completed after the deadline", Group{Deadline: at(-time.Hour)}, 2, 2, DeadlineCompleted, int64Ptr(0), false},
+		{"completed without a deadline", Group{}, 1, 1, DeadlineCompleted, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := tt.group.DeriveDeadlineState(now, tt.items, tt.completed)
+			if state.Status != tt.status {
+				t.Errorf("Status = %s, want %s", state.Status, tt.status)
+			}
+			if (state.SecondsRemaining == nil) != (tt.seconds == nil) ||
+				(tt.seconds != nil && *state.SecondsRemaining != *tt.seconds) {
+				t.Errorf("SecondsRemaining = %v, want %v", state.SecondsRemaining, tt.seconds)
+			}
+			if state.Locked != tt.locked {
+				t.Errorf("Locked = %v, want %v", state.Locked, tt.locked)
+			}
+		})
+	}
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
 func TestSanitizeString(t *testing.T) {
 	tests := []struct {
 		name     string
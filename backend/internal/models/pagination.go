@@ -0,0 +1,218 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Page size limits for listing endpoints
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 100
+)
+
+// ItemSort is the order of a page of bucket list items
+type ItemSort string
+
+const (
+	// ItemSortNewest lists the most recently added items first
+	ItemSortNewest ItemSort = "newest"
+	// ItemSortOldest lists the earliest added items first
+	ItemSortOldest ItemSort = "oldest"
+	// ItemSortTitle lists items alphabetically, ignoring case
+	ItemSortTitle ItemSort = "title"
+	// ItemSortMostWanted lists the items with the most votes first
+	ItemSortMostWanted ItemSort = "most-wanted"
+)
+
+// GroupSort is the order of a page of group summaries
+type GroupSort string
+
+const (
+	// GroupSortNewest lists the most recently created groups first
+	GroupSortNewest GroupSort = "newest"
+	// GroupSortDeadline lists the soonest deadlines first and groups without
+	// a deadline last
+	GroupSortDeadline GroupSort = "deadline"
+	// GroupSortProgress lists the most complete groups first
+	GroupSortProgress GroupSort = "progress"
+)
+
+// ItemListOptions filters, sorts and pages the items of a group. Zero values
+// apply no filter, the newest-first order and the default page size.
+type ItemListOptions struct {
+	Completed *bool
+	CreatedBy string
+	// TagID limits the page to items carrying the tag
+	TagID string
+	// AssignedTo limits the page to items assigned to the member
+	AssignedTo string
+	// CreatedAfter is inclusive and CreatedBefore exclusive
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Sort          ItemSort
+	// Cursor is the NextCursor of the previous page
+	Cursor string
+	Limit  int
+}
+
+// ItemPage is one page of bucket list items
+type ItemPage struct {
+	Items      []BucketListItem `json:"items"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+}
+
+// GroupSummaryListOptions filters, sorts and pages a user's group summaries.
+// Deadline filters exclude groups without a deadline.
+type GroupSummaryListOptions struct {
+	// DeadlineAfter is inclusive and DeadlineBefore exclusive
+	DeadlineAfter  *time.Time
+	DeadlineBefore *time.Time
+	// MinProgress and MaxProgress are inclusive percentages
+	MinProgress *float64
+	MaxProgress *float64
+	Sort        GroupSort
+	// Cursor is the NextCursor of the previous page
+	Cursor string
+	Limit  int
+}
+
+// GroupSummaryPage is one page of group summaries
+type GroupSummaryPage struct {
+	Groups     []GroupSummary `json:"groups"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
+
+// PageLimit returns the page size for a requested limit, which is the
+// default when no limit was requested
+func PageLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultPageLimit
+	}
+	if limit > MaxPageLimit {
+		return MaxPageLimit
+	}
+	return limit
+}
+
+// SortOrDefault returns the sort, or ItemSortNewest if none was chosen
+func (s ItemSort) SortOrDefault() ItemSort {
+	if s == "" {
+		return ItemSortNewest
+	}
+	return s
+}
+
+// SortOrDefault returns the sort, or GroupSortNewest if none was chosen
+func (s GroupSort) SortOrDefault() GroupSort {
+	if s == "" {
+		return GroupSortNewest
+	}
+	return s
+}
+
+// Validate checks the item list options
+func (o *ItemListOptions) Validate() ValidationResult {
+	var errors []ValidationError
+
+	switch o.Sort {
+	case "", ItemSortNewest, ItemSortOldest, ItemSortTitle, ItemSortMostWanted:
+	default:
+		errors = append(errors, ValidationError{
+			Field:   "sort",
+			Message: fmt.Sprintf("Sort must be one of %s, %s, %s or %s", ItemSortNewest, ItemSortOldest, ItemSortTitle, ItemSortMostWanted),
+		})
+	}
+
+	if o.CreatedBy != "" && !ValidateUUID(o.CreatedBy).IsValid {
+		errors = append(errors, ValidationError{
+			Field:   "createdBy",
+			Message: "createdBy must be a member ID",
+		})
+	}
+
+	if o.TagID != "" && !ValidateUUID(o.TagID).IsValid {
+		errors = append(errors, ValidationError{
+			Field:   "tag",
+			Message: "tag must be a tag ID",
+		})
+	}
+
+	if o.AssignedTo != "" && !ValidateUUID(o.AssignedTo).IsValid {
+		errors = append(errors, ValidationError{
+			Field:   "assignedTo",
+			Message: "assignedTo must be a member ID",
+		})
+	}
+
+	if o.CreatedAfter != nil && o.CreatedBefore != nil && !o.CreatedAfter.Before(*o.CreatedBefore) {
+		errors = append(errors, ValidationError{
+			Field:   "createdBefore",
+			Message: "createdBefore must be later than createdAfter",
+		})
+	}
+
+	errors = append(errors, validatePageLimit(o.Limit)...)
+
+	return ValidationResult{
+		IsValid: len(errors) == 0,
+		Errors:  errors,
+	}
+}
+
+// Validate checks the group summary list options
+func (o *GroupSummaryListOptions) Validate() ValidationResult {
+	var errors []ValidationError
+
+	switch o.Sort {
+	case "", GroupSortNewest, GroupSortDeadline, GroupSortProgress:
+	default:
+		errors = append(errors, ValidationError{
+			Field:   "sort",
+			Message: fmt.Sprintf("Sort must be one of %s, %s or %s", GroupSortNewest, GroupSortDeadline, GroupSortProgress),
+		})
+	}
+
+	if o.DeadlineAfter != nil && o.DeadlineBefore != nil && !o.DeadlineAfter.Before(*o.DeadlineBefore) {
+		errors = append(errors, ValidationError{
+			Field:   "deadlineBefore",
+			Message: "deadlineBefore must be later than deadlineAfter",
+		})
+	}
+
+	errors = append(errors, validateProgress("minProgress", o.MinProgress)...)
+	errors = append(errors, validateProgress("maxProgress", o.MaxProgress)...)
+	if o.MinProgress != nil && o.MaxProgress != nil && *o.MinProgress > *o.MaxProgress {
+		errors = append(errors, ValidationError{
+			Field:   "maxProgress",
+			Message: "maxProgress must not be less than minProgress",
+		})
+	}
+
+	errors = append(errors, validatePageLimit(o.Limit)...)
+
+	return ValidationResult{
+		IsValid: len(errors) == 0,
+		Errors:  errors,
+	}
+}
+
+func validatePageLimit(limit int) []ValidationError {
+	if limit < 0 || limit > MaxPageLimit {
+		return []ValidationError{{
+			Field:   "limit",
+			Message: fmt.Sprintf("limit must be between 1 and %d", MaxPageLimit),
+		}}
+	}
+	return nil
+}
+
+func validateProgress(field string, progress *float64) []ValidationError {
+	if progress != nil && (*progress < 0 || *progress > 100) {
+		return []ValidationError{{
+			Field:   field,
+			Message: fmt.Sprintf("%s must be between 0 and 100", field),
+		}}
+	}
+	return nil
+}
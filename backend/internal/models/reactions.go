@@ -0,0 +1,128 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Vote is a member's upvote of a bucket list item. A member votes for an
+// item at most once.
+type Vote struct {
+	ItemID    string    `json:"itemId" db:"item_id"`
+	MemberID  string    `json:"memberId" db:"member_id"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// Reaction is a member's emoji reaction to a bucket list item. A member can
+// react with several emoji, each once.
+type Reaction struct {
+	ItemID    string    `json:"itemId" db:"item_id"`
+	MemberID  string    `json:"memberId" db:"member_id"`
+	Emoji     string    `json:"emoji" db:"emoji"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// ReactionCount is the number of members who reacted to an item with an
+// emoji, and who they are in the order they reacted
+type ReactionCount struct {
+	Emoji     string   `json:"emoji"`
+	Count     int      `json:"count"`
+	MemberIDs []string `json:"memberIds"`
+}
+
+// ItemReactions aggregates the votes and reactions of an item. Voters are
+// listed in the order they voted and reactions in the order each emoji was
+// first used.
+type ItemReactions struct {
+	ItemID    string          `json:"itemId"`
+	Votes     int             `json:"votes"`
+	VoterIDs  []string        `json:"voterIds"`
+	Reactions []ReactionCount `json:"reactions"`
+}
+
+// Constants for reaction validation
+const (
+	// MaxEmojiLength is in bytes, enough for emoji built from several code
+	// points such as flags, skin tones and ZWJ sequences
+	MaxEmojiLength = 32
+)
+
+// emojiJoiners are the code points that modify or join emoji without being
+// symbols themselves: zero width joiner, variation selectors, the keycap
+// mark and tag characters
+var emojiJoiners = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x200d, Hi: 0x200d, Stride: 1},
+		{Lo: 0x20e3, Hi: 0x20e3, Stride: 1},
+		{Lo: 0xfe0e, Hi: 0xfe0f, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f3fb, Hi: 0x1f3ff, Stride: 1},
+		{Lo: 0xe0020, Hi: 0xe007f, Stride: 1},
+	},
+}
+
+func ValidateEmoji(emoji string) ValidationResult {
+	var errors []ValidationError
+
+	emoji = strings.TrimSpace(emoji)
+
+	if len(emoji) == 0 {
+		errors = append(errors, ValidationError{
+			Field:   "emoji",
+			Message: "Emoji is required",
+		})
+	} else if len(emoji) > MaxEmojiLength {
+		errors = append(errors, ValidationError{
+			Field:   "emoji",
+			Message: fmt.Sprintf("Emoji must be no more than %d bytes", MaxEmojiLength),
+		})
+	} else if !isEmoji(emoji) {
+		errors = append(errors, ValidationError{
+			Field:   "emoji",
+			Message: "Reactions must be an emoji",
+		})
+	}
+
+	return ValidationResult{
+		IsValid: len(errors) == 0,
+		Errors:  errors,
+	}
+}
+
+// isEmoji reports whether s is made of symbols and emoji modifiers only,
+// with at least one symbol
+func isEmoji(s string) bool {
+	symbols := 0
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.So, r):
+			symbols++
+		case unicode.Is(emojiJoiners, r):
+		default:
+			return false
+		}
+	}
+	return symbols > 0
+}
+
+// Sanitize trims whitespace around the emoji
+func (r *Reaction) Sanitize() {
+	r.Emoji = strings.TrimSpace(r.Emoji)
+}
+
+func (r *Reaction) IsValid() error {
+	if validation := ValidateEmoji(r.Emoji); !validation.IsValid {
+		return errors.New(validation.Errors[0].Message)
+	}
+	if strings.TrimSpace(r.ItemID) == "" {
+		return errors.New("item ID is required")
+	}
+	if strings.TrimSpace(r.MemberID) == "" {
+		return errors.New("member ID is required")
+	}
+	return nil
+}
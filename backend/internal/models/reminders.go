@@ -0,0 +1,95 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Limits of reminder schedules
+const (
+	MaxReminderDays = 365
+	MaxReminders    = 10
+)
+
+// DefaultReminderDays is the reminder schedule of groups created without one
+var DefaultReminderDays = []int{30, 7, 1}
+
+// UpdateRemindersRequest is the body of a request replacing a group's
+// reminder schedule: the days before its deadline that members are
+// reminded of it. An empty schedule sends no reminders.
+type UpdateRemindersRequest struct {
+	Days []int `json:"days"`
+}
+
+// DueReminder is a reminder of a group's deadline that falls due at
+// RemindAt, DaysBefore days before the deadline
+type DueReminder struct {
+	Group      Group     `json:"group"`
+	DaysBefore int       `json:"daysBefore"`
+	RemindAt   time.Time `json:"remindAt"`
+}
+
+// RemindAt returns when the reminder days before deadline falls due
+func RemindAt(deadline time.Time, days int) time.Time {
+	return deadline.Add(-time.Duration(days) * 24 * time.Hour)
+}
+
+// ValidateReminderDays checks that a reminder schedule has at most
+// MaxReminders days, each between 1 and MaxReminderDays
+func ValidateReminderDays(days []int) ValidationResult {
+	var errors []ValidationError
+
+	if len(days) > MaxReminders {
+		errors = append(errors, ValidationError{
+			Field:   "reminderDays",
+			Message: fmt.Sprintf("At most %d reminders can be scheduled", MaxReminders),
+		})
+	}
+	for _, day := range days {
+		if day < 1 || day > MaxReminderDays {
+			errors = append(errors, ValidationError{
+				Field:   "reminderDays",
+				Message: fmt.Sprintf("Reminders must be between 1 and %d days before the deadline", MaxReminderDays),
+			})
+			break
+		}
+	}
+
+	return ValidationResult{
+		IsValid: len(errors) == 0,
+		Errors:  errors,
+	}
+}
+
+// NormalizeReminderDays returns a reminder schedule without repeated days,
+// furthest from the deadline first
+func NormalizeReminderDays(days []int) []int {
+	normalized := make([]int, 0, len(days))
+	seen := make(map[int]bool, len(days))
+	for _, day := range days {
+		if !seen[day] {
+			seen[day] = true
+			normalized = append(normalized, day)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(normalized)))
+	return normalized
+}
+
+func (req *UpdateRemindersRequest) Validate() ValidationResult {
+	return ValidateReminderDays(req.Days)
+}
+
+func (req *UpdateRemindersRequest) Sanitize() {
+	req.Days = NormalizeReminderDays(req.Days)
+}
+
+// ReminderSchedule returns the reminder days of a validated request,
+// DefaultReminderDays if it has none
+func (req *CreateGroupRequest) ReminderSchedule() []int {
+	if req.ReminderDays == nil {
+		return NormalizeReminderDays(DefaultReminderDays)
+	}
+	return NormalizeReminderDays(*req.ReminderDays)
+}
@@ -0,0 +1,237 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ItemSchedule is when a group plans to do an item: a point in time when
+// only ScheduledStart is set, or the span up to ScheduledEnd
+type ItemSchedule struct {
+	ScheduledStart *time.Time `json:"scheduledStart,omitempty" db:"scheduled_start"`
+	ScheduledEnd   *time.Time `json:"scheduledEnd,omitempty" db:"scheduled_end"`
+}
+
+// Constants for calendar ranges
+const (
+	DefaultCalendarDays = 42
+	MaxCalendarDays     = 366
+)
+
+// UpdateScheduleRequest is the body of a request rescheduling an item.
+// Fields left out are cleared, so an empty schedule unschedules the item.
+type UpdateScheduleRequest struct {
+	MemberID     string `json:"memberId" binding:"required"`
+	ItemSchedule `json:",inline"`
+}
+
+// CalendarRange is the days a calendar covers, from the midnight starting
+// From up to the midnight starting To, in From's location
+type CalendarRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// Calendar lays out a group's scheduled items by day. Only days with items
+// are listed; an item spanning several days is listed on each of them.
+type Calendar struct {
+	// From and To are the first and last days covered, formatted as
+	// DateLayout
+	From     string        `json:"from"`
+	To       string        `json:"to"`
+	Timezone string        `json:"timezone"`
+	Days     []CalendarDay `json:"days"`
+}
+
+// CalendarDay lists the items scheduled on one day, by start time
+type CalendarDay struct {
+	Date  string           `json:"date"`
+	Items []BucketListItem `json:"items"`
+}
+
+// IsEmpty reports whether the item is not scheduled
+func (s *ItemSchedule) IsEmpty() bool {
+	return s.ScheduledStart == nil && s.ScheduledEnd == nil
+}
+
+// Validate checks that the schedule ends after it starts and, when the
+// group has a deadline, that the item is over by then
+func (s *ItemSchedule) Validate(deadline *time.Time) ValidationResult {
+	var errors []ValidationError
+
+	switch {
+	case s.ScheduledEnd != nil && s.ScheduledStart == nil:
+		errors = append(errors, ValidationError{
+			Field:   "scheduledStart",
+			Message: "A scheduled end needs a scheduled start",
+		})
+	case s.ScheduledEnd != nil && !s.ScheduledEnd.After(*s.ScheduledStart):
+		errors = append(errors, ValidationError{
+			Field:   "scheduledEnd",
+			Message: "Scheduled end must be after the scheduled start",
+		})
+	}
+
+	if deadline != nil {
+		if s.ScheduledStart != nil && !s.ScheduledStart.Before(*deadline) {
+			errors = append(errors, ValidationError{
+				Field:   "scheduledStart",
+				Message: "Item must be scheduled before the group deadline",
+			})
+		} else if s.ScheduledEnd != nil && s.ScheduledEnd.After(*deadline) {
+			errors = append(errors, ValidationError{
+				Field:   "scheduledEnd",
+				Message: "Item must end by the group deadline",
+			})
+		}
+	}
+
+	return ValidationResult{
+		IsValid: len(errors) == 0,
+		Errors:  errors,
+	}
+}
+
+// ValidateSchedulesByDeadline checks that every scheduled item among items
+// is over by deadline, reporting each one that is not. A group's items are
+// checked again when its deadline or timezone changes.
+func ValidateSchedulesByDeadline(items []BucketListItem, deadline *time.Time) ValidationResult {
+	var errors []ValidationError
+
+	if deadline != nil {
+		for _, item := range items {
+			if !item.ItemSchedule.IsEmpty() && !item.ItemSchedule.Validate(deadline).IsValid {
+				errors = append(errors, ValidationError{
+					Field:   "deadline",
+					Message: fmt.Sprintf("Item %s (%q) is scheduled after the deadline", item.ID, item.Title),
+				})
+			}
+		}
+	}
+
+	return ValidationResult{
+		IsValid: len(errors) == 0,
+		Errors:  errors,
+	}
+}
+
+func (req *UpdateScheduleRequest) Validate() ValidationResult {
+	var allErrors []ValidationError
+
+	if strings.TrimSpace(req.MemberID) == "" {
+		allErrors = append(allErrors, ValidationError{
+			Field:   "memberId",
+			Message: "Member ID is required",
+		})
+	}
+	// The group deadline is checked once the item's group is known
+	allErrors = append(allErrors, req.ItemSchedule.Validate(nil).Errors...)
+
+	return ValidationResult{
+		IsValid: len(allErrors) == 0,
+		Errors:  allErrors,
+	}
+}
+
+func (req *UpdateScheduleRequest) Sanitize() {
+	req.MemberID = strings.TrimSpace(req.MemberID)
+}
+
+// ParseCalendarRange parses the from and to dates of a calendar, both
+// inclusive and formatted as DateLayout, as days in loc. from defaults to
+// the day of now and to to DefaultCalendarDays later.
+func ParseCalendarRange(from, to string, now time.Time, loc *time.Location) (CalendarRange, ValidationResult) {
+	var errors []ValidationError
+	parse := func(field, value string) time.Time {
+		day, err := time.ParseInLocation(DateLayout, value, loc)
+		if err != nil {
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Message: field + " must be a date (YYYY-MM-DD)",
+			})
+		}
+		return day
+	}
+
+	var r CalendarRange
+	if from == "" {
+		year, month, day := now.In(loc).Date()
+		r.From = time.Date(year, month, day, 0, 0, 0, 0, loc)
+	} else {
+		r.From = parse("from", from)
+	}
+	if to == "" {
+		r.To = r.From.AddDate(0, 0, DefaultCalendarDays)
+	} else {
+		r.To = parse("to", to).AddDate(0, 0, 1)
+	}
+
+	if len(errors) == 0 {
+		if !r.To.After(r.From) {
+			errors = append(errors, ValidationError{
+				Field:   "to",
+				Message: "to must not be before from",
+			})
+		} else if r.To.After(r.From.AddDate(0, 0, MaxCalendarDays)) {
+			errors = append(errors, ValidationError{
+				Field:   "to",
+				Message: fmt.Sprintf("A calendar can cover at most %d days", MaxCalendarDays),
+			})
+		}
+	}
+
+	return r, ValidationResult{
+		IsValid: len(errors) == 0,
+		Errors:  errors,
+	}
+}
+
+// BuildCalendar lays out the scheduled items among items on the days of r.
+// An item without an end is on the day it starts.
+func BuildCalendar(items []BucketListItem, r CalendarRange) Calendar {
+	var scheduled []BucketListItem
+	for _, item := range items {
+		if item.ScheduledStart != nil {
+			scheduled = append(scheduled, item)
+		}
+	}
+	sort.SliceStable(scheduled, func(i, j int) bool {
+		if !scheduled[i].ScheduledStart.Equal(*scheduled[j].ScheduledStart) {
+			return scheduled[i].ScheduledStart.Before(*scheduled[j].ScheduledStart)
+		}
+		return scheduled[i].ID < scheduled[j].ID
+	})
+
+	calendar := Calendar{
+		From:     r.From.Format(DateLayout),
+		To:       r.To.AddDate(0, 0, -1).Format(DateLayout),
+		Timezone: r.From.Location().String(),
+		Days:     []CalendarDay{},
+	}
+	for day := r.From; day.Before(r.To); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+
+		var dayItems []BucketListItem
+		for _, item := range scheduled {
+			if item.ScheduledStart.Before(next) && scheduleEnd(item.ItemSchedule).After(day) {
+				dayItems = append(dayItems, item)
+			}
+		}
+		if len(dayItems) > 0 {
+			calendar.Days = append(calendar.Days, CalendarDay{Date: day.Format(DateLayout), Items: dayItems})
+		}
+	}
+
+	return calendar
+}
+
+// scheduleEnd is the end of a scheduled item's span. An item without an
+// end occupies the instant it starts.
+func scheduleEnd(s ItemSchedule) time.Time {
+	if s.ScheduledEnd != nil {
+		return *s.ScheduledEnd
+	}
+	return s.ScheduledStart.Add(time.Nanosecond)
+}
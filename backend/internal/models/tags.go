@@ -0,0 +1,203 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Tag is a group-scoped label for bucket list items, such as "travel" or
+// "food". Names are unique within a group, ignoring case.
+type Tag struct {
+	ID        string    `json:"id" db:"id"`
+	GroupID   string    `json:"groupId" db:"group_id"`
+	Name      string    `json:"name" db:"name"`
+	Color     string    `json:"color" db:"color"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// TagStats is a tag with the completion counts of the items carrying it
+type TagStats struct {
+	Tag             `json:",inline"`
+	ItemCount       int     `json:"itemCount"`
+	CompletedCount  int     `json:"completedCount"`
+	ProgressPercent float64 `json:"progressPercent"`
+}
+
+// Constants for tag validation
+const (
+	MaxTagNameLength = 30
+	MaxTagsPerItem   = 10
+	// DefaultTagColor is used for tags created without a color
+	DefaultTagColor = "#6b7280"
+)
+
+var tagColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// CreateTagRequest is the body of a request creating a tag
+type CreateTagRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Color string `json:"color,omitempty"`
+}
+
+// UpdateTagRequest is the body of a request renaming or recoloring a tag.
+// Omitted fields are left unchanged.
+type UpdateTagRequest struct {
+	Name  *string `json:"name,omitempty"`
+	Color *string `json:"color,omitempty"`
+}
+
+// SetItemTagsRequest replaces the tags of an item
+type SetItemTagsRequest struct {
+	TagIDs   []string `json:"tagIds"`
+	MemberID string   `json:"memberId" binding:"required"`
+}
+
+func ValidateTagName(name string) ValidationResult {
+	var errors []ValidationError
+
+	name = strings.TrimSpace(name)
+	if len(name) == 0 {
+		errors = append(errors, ValidationError{
+			Field:   "name",
+			Message: "Tag name is required",
+		})
+	} else if len(name) > MaxTagNameLength {
+		errors = append(errors, ValidationError{
+			Field:   "name",
+			Message: fmt.Sprintf("Tag name must be no more than %d characters", MaxTagNameLength),
+		})
+	}
+
+	return ValidationResult{
+		IsValid: len(errors) == 0,
+		Errors:  errors,
+	}
+}
+
+func ValidateTagColor(color string) ValidationResult {
+	var errors []ValidationError
+
+	if !tagColorPattern.MatchString(color) {
+		errors = append(errors, ValidationError{
+			Field:   "color",
+			Message: "Tag color must be a hex color such as #1e90ff",
+		})
+	}
+
+	return ValidationResult{
+		IsValid: len(errors) == 0,
+		Errors:  errors,
+	}
+}
+
+func (req *CreateTagRequest) Validate() ValidationResult {
+	var allErrors []ValidationError
+
+	allErrors = append(allErrors, ValidateTagName(req.Name).Errors...)
+	if req.Color != "" {
+		allErrors = append(allErrors, ValidateTagColor(req.Color).Errors...)
+	}
+
+	return ValidationResult{
+		IsValid: len(allErrors) == 0,
+		Errors:  allErrors,
+	}
+}
+
+func (req *UpdateTagRequest) Validate() ValidationResult {
+	var allErrors []ValidationError
+
+	if req.Name == nil && req.Color == nil {
+		allErrors = append(allErrors, ValidationError{
+			Field:   "name",
+			Message: "Name or color is required",
+		})
+	}
+	if req.Name != nil {
+		allErrors = append(allErrors, ValidateTagName(*req.Name).Errors...)
+	}
+	if req.Color != nil {
+		allErrors = append(allErrors, ValidateTagColor(*req.Color).Errors...)
+	}
+
+	return ValidationResult{
+		IsValid: len(allErrors) == 0,
+		Errors:  allErrors,
+	}
+}
+
+func (req *SetItemTagsRequest) Validate() ValidationResult {
+	var allErrors []ValidationError
+
+	if strings.TrimSpace(req.MemberID) == "" {
+		allErrors = append(allErrors, ValidationError{
+			Field:   "memberId",
+			Message: "Member ID is required",
+		})
+	}
+	if len(req.TagIDs) > MaxTagsPerItem {
+		allErrors = append(allErrors, ValidationError{
+			Field:   "tagIds",
+			Message: fmt.Sprintf("An item can have no more than %d tags", MaxTagsPerItem),
+		})
+	}
+	for _, tagID := range req.TagIDs {
+		if !ValidateUUID(tagID).IsValid {
+			allErrors = append(allErrors, ValidationError{
+				Field:   "tagIds",
+				Message: "Tag IDs must be valid UUIDs",
+			})
+			break
+		}
+	}
+
+	return ValidationResult{
+		IsValid: len(allErrors) == 0,
+		Errors:  allErrors,
+	}
+}
+
+// Sanitize trims the tag name and lowercases its color
+func (t *Tag) Sanitize() {
+	t.Name = SanitizeString(t.Name)
+	t.Color = strings.ToLower(t.Color)
+}
+
+func (req *CreateTagRequest) Sanitize() {
+	req.Name = SanitizeString(req.Name)
+	req.Color = strings.TrimSpace(req.Color)
+}
+
+func (req *UpdateTagRequest) Sanitize() {
+	if req.Name != nil {
+		name := SanitizeString(*req.Name)
+		req.Name = &name
+	}
+	if req.Color != nil {
+		color := strings.TrimSpace(*req.Color)
+		req.Color = &color
+	}
+}
+
+func (req *SetItemTagsRequest) Sanitize() {
+	req.MemberID = strings.TrimSpace(req.MemberID)
+	for i, tagID := range req.TagIDs {
+		req.TagIDs[i] = strings.TrimSpace(tagID)
+	}
+}
+
+func (t *Tag) IsValid() error {
+	if validation := ValidateTagName(t.Name); !validation.IsValid {
+		return errors.New(validation.Errors[0].Message)
+	}
+	if validation := ValidateTagColor(t.Color); !validation.IsValid {
+		return errors.New(validation.Errors[0].Message)
+	}
+	if strings.TrimSpace(t.GroupID) == "" {
+		return errors.New("group ID is required")
+	}
+	return nil
+}
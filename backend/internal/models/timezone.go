@@ -0,0 +1,218 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimezone is the timezone of groups created without one
+const DefaultTimezone = "UTC"
+
+// ErrInvalidTimezone is returned for names that are not IANA timezones
+var ErrInvalidTimezone = errors.New("invalid timezone")
+
+// locations caches loaded timezones by name, since loading one reads the
+// timezone database
+var locations sync.Map
+
+// LoadTimezone returns the location of an IANA timezone name such as
+// "Asia/Tokyo". An empty name is DefaultTimezone. "Local" is rejected, as
+// the server's zone means nothing to a group.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		name = DefaultTimezone
+	}
+	if cached, ok := locations.Load(name); ok {
+		return cached.(*time.Location), nil
+	}
+
+	if name == "Local" {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTimezone, name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTimezone, name)
+	}
+
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// ValidateTimezone checks that a timezone is empty or an IANA name
+func ValidateTimezone(name string) ValidationResult {
+	var errors []ValidationError
+
+	if _, err := LoadTimezone(name); err != nil {
+		errors = append(errors, ValidationError{
+			Field:   "timezone",
+			Message: "Timezone must be an IANA timezone name, like Europe/Paris",
+		})
+	}
+
+	return ValidationResult{
+		IsValid: len(errors) == 0,
+		Errors:  errors,
+	}
+}
+
+// ParseDeadline parses a deadline given as a date (YYYY-MM-DD), which ends
+// at the midnight after it in loc, or as an RFC 3339 time
+func ParseDeadline(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if day, err := time.ParseInLocation(DateLayout, value, loc); err == nil {
+		return day.AddDate(0, 0, 1), nil
+	}
+	if deadline, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return deadline, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid deadline: %q", value)
+}
+
+// validateDeadlineInput checks a deadline given as in ParseDeadline, in the
+// timezone named timezone, returning the parsed deadline if it is valid
+func validateDeadlineInput(value *string, timezone string) (*time.Time, []ValidationError) {
+	if value == nil {
+		return nil, nil
+	}
+
+	loc, err := LoadTimezone(timezone)
+	if err != nil {
+		// Reported by ValidateTimezone
+		return nil, nil
+	}
+
+	deadline, err := ParseDeadline(*value, loc)
+	if err != nil {
+		return nil, []ValidationError{{
+			Field:   "deadline",
+			Message: "Deadline must be a date (YYYY-MM-DD) or an RFC 3339 time",
+		}}
+	}
+
+	if validation := ValidateDeadline(&deadline); !validation.IsValid {
+		return nil, validation.Errors
+	}
+	return &deadline, nil
+}
+
+// Location returns the group's timezone, DefaultTimezone if it has none
+func (g *Group) Location() *time.Location {
+	if loc, err := LoadTimezone(g.Timezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// Localize renders the group's times in its timezone and fills in its
+// DeadlineDate. Stores call it on the groups they return.
+func (g *Group) Localize() {
+	if g.Timezone == "" {
+		g.Timezone = DefaultTimezone
+	}
+	loc := g.Location()
+
+	g.CreatedAt = g.CreatedAt.In(loc)
+	g.DeadlineDate = nil
+	if g.Deadline != nil {
+		deadline := g.Deadline.In(loc)
+		g.Deadline = &deadline
+		date := DeadlineDate(deadline)
+		g.DeadlineDate = &date
+	}
+}
+
+// DeadlineDate is the last day before a deadline, in the deadline's
+// location: the date it was given as, for a deadline ending a day
+func DeadlineDate(deadline time.Time) string {
+	return deadline.Add(-time.Nanosecond).Format(DateLayout)
+}
+
+// EndsDay reports whether a deadline falls at midnight in its location,
+// ending the day before it
+func EndsDay(deadline time.Time) bool {
+	hour, minute, second := deadline.Clock()
+	return hour == 0 && minute == 0 && second == 0 && deadline.Nanosecond() == 0
+}
+
+func (req *UpdateGroupRequest) Validate() ValidationResult {
+	var allErrors []ValidationError
+
+	if req.Name != nil {
+		allErrors = append(allErrors, ValidateGroupName(*req.Name).Errors...)
+	}
+	if req.Timezone != nil {
+		allErrors = append(allErrors, ValidateTimezone(*req.Timezone).Errors...)
+	}
+	// Dates are checked against the group's timezone by Apply
+	if req.Deadline != nil && *req.Deadline != "" {
+		if _, err := ParseDeadline(*req.Deadline, time.UTC); err != nil {
+			allErrors = append(allErrors, ValidationError{
+				Field:   "deadline",
+				Message: "Deadline must be a date (YYYY-MM-DD) or an RFC 3339 time",
+			})
+		}
+	}
+
+	return ValidationResult{
+		IsValid: len(allErrors) == 0,
+		Errors:  allErrors,
+	}
+}
+
+func (req *UpdateGroupRequest) Sanitize() {
+	if req.Name != nil {
+		sanitized := SanitizeString(*req.Name)
+		req.Name = &sanitized
+	}
+	if req.Timezone != nil {
+		timezone := strings.TrimSpace(*req.Timezone)
+		if timezone == "" {
+			timezone = DefaultTimezone
+		}
+		req.Timezone = &timezone
+	}
+	if req.Deadline != nil {
+		deadline := strings.TrimSpace(*req.Deadline)
+		req.Deadline = &deadline
+	}
+}
+
+// Apply makes the changes of a validated request to g, checking that a new
+// deadline is ahead. Moving a group to another timezone keeps a deadline
+// that ends a day on the same date in the new zone.
+func (req *UpdateGroupRequest) Apply(g *Group) ValidationResult {
+	if req.Name != nil {
+		g.Name = *req.Name
+	}
+	if req.LockAfterDeadline != nil {
+		g.LockAfterDeadline = *req.LockAfterDeadline
+	}
+
+	if req.Timezone != nil && *req.Timezone != g.Timezone {
+		oldLoc := g.Location()
+		g.Timezone = *req.Timezone
+		if g.Deadline != nil && EndsDay(g.Deadline.In(oldLoc)) {
+			year, month, day := g.Deadline.In(oldLoc).Date()
+			deadline := time.Date(year, month, day, 0, 0, 0, 0, g.Location())
+			g.Deadline = &deadline
+		}
+	}
+
+	if req.Deadline != nil {
+		if *req.Deadline == "" {
+			g.Deadline = nil
+		} else {
+			deadline, errors := validateDeadlineInput(req.Deadline, g.Timezone)
+			if len(errors) > 0 {
+				return ValidationResult{IsValid: false, Errors: errors}
+			}
+			g.Deadline = deadline
+		}
+	}
+
+	g.Localize()
+	return ValidationResult{IsValid: true}
+}
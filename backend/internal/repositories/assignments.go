@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// setItemAssigneeIDs fills in the assignees of items from a map of item ID
+// to member IDs in the order they were assigned
+func setItemAssigneeIDs(items []models.BucketListItem, assigneeIDs map[string][]string) {
+	for i := range items {
+		items[i].AssigneeIDs = assigneeIDs[items[i].ID]
+	}
+}
+
+// checkAssignable returns an error unless the item exists and the member
+// belongs to its group. Only members of the item's group can be assigned.
+// query selects the item's and the member's group IDs from args.
+func checkAssignable(ctx context.Context, db dbExecutor, itemID, query string, args ...interface{}) error {
+	var itemGroupID, memberGroupID sql.NullString
+	if err := db.QueryRowContext(ctx, query, args...).Scan(&itemGroupID, &memberGroupID); err != nil {
+		return fmt.Errorf("failed to assign item: %w", err)
+	}
+	if !itemGroupID.Valid {
+		return fmt.Errorf("%w: %s", ErrItemNotFound, itemID)
+	}
+	if memberGroupID != itemGroupID {
+		return fmt.Errorf("failed to assign item: %w", ErrMemberNotFound)
+	}
+	return nil
+}
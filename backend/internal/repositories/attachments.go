@@ -0,0 +1,17 @@
+package repositories
+
+import "collaborative-bucket-list/internal/models"
+
+// itemAttachments returns the attachments of items from the attachments of
+// the items that have any, with an empty list for the rest
+func itemAttachments(items []models.BucketListItem, byItem map[string][]models.Attachment) map[string][]models.Attachment {
+	all := make(map[string][]models.Attachment, len(items))
+	for _, item := range items {
+		attachments := byItem[item.ID]
+		if attachments == nil {
+			attachments = []models.Attachment{}
+		}
+		all[item.ID] = attachments
+	}
+	return all
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"collaborative-bucket-list/internal/models"
+)
+
+// commentThreads groups comments into threads. Comments must be ordered
+// oldest first; threads and their replies keep that order.
+func commentThreads(comments []models.Comment) []models.CommentThread {
+	threads := []models.CommentThread{}
+	index := make(map[string]int)
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			index[comment.ID] = len(threads)
+			threads = append(threads, models.CommentThread{Comment: comment, Replies: []models.Comment{}})
+		}
+	}
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			continue
+		}
+		if i, ok := index[*comment.ParentID]; ok {
+			threads[i].Replies = append(threads[i].Replies, comment)
+		}
+	}
+	return threads
+}
+
+// checkReplyParent returns ErrInvalidReply unless a parent comment can be
+// replied to on an item
+func checkReplyParent(parent models.Comment, itemID string) error {
+	if parent.ItemID != itemID || parent.ParentID != nil {
+		return ErrInvalidReply
+	}
+	return nil
+}
+
+// itemCommentCounts returns the comment counts of items from the counts of
+// the items that have comments, with zero for the rest
+func itemCommentCounts(items []models.BucketListItem, counts map[string]int) map[string]int {
+	all := make(map[string]int, len(items))
+	for _, item := range items {
+		all[item.ID] = counts[item.ID]
+	}
+	return all
+}
@@ -0,0 +1,2217 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRepositoriesFunc returns an empty RepositoryManager for one test
+type newRepositoriesFunc func(t *testing.T) RepositoryManager
+
+// runConformanceTests checks the behaviour every RepositoryManager
+// implementation must share, so storage backends are interchangeable
+func runConformanceTests(t *testing.T, newRepos newRepositoriesFunc) {
+	t.Run("Groups", func(t *testing.T) { testGroupConformance(t, newRepos) })
+	t.Run("Members", func(t *testing.T) { testMemberConformance(t, newRepos) })
+	t.Run("BucketItems", func(t *testing.T) { testBucketItemConformance(t, newRepos) })
+	t.Run("Transactions", func(t *testing.T) { testTransactionConformance(t, newRepos) })
+	t.Run("Search", func(t *testing.T) { testSearchConformance(t, newRepos) })
+	t.Run("Pagination", func(t *testing.T) { testPaginationConformance(t, newRepos) })
+	t.Run("Tags", func(t *testing.T) { testTagConformance(t, newRepos) })
+	t.Run("Comments", func(t *testing.T) { testCommentConformance(t, newRepos) })
+	t.Run("Reactions", func(t *testing.T) { testReactionConformance(t, newRepos) })
+	t.Run("Attachments", func(t *testing.T) { testAttachmentConformance(t, newRepos) })
+}
+
+func TestMemoryRepositoryManager_Conformance(t *testing.T) {
+	runConformanceTests(t, func(t *testing.T) RepositoryManager {
+		return NewMemoryRepositoryManager()
+	})
+}
+
+func TestPostgresRepositoryManager_Conformance(t *testing.T) {
+	runConformanceTests(t, func(t *testing.T) RepositoryManager {
+		db := setupTestDB(t)
+		t.Cleanup(func() { db.Close() })
+		return NewPostgresRepositoryManager(db)
+	})
+}
+
+func TestSQLiteRepositoryManager_Conformance(t *testing.T) {
+	runConformanceTests(t, func(t *testing.T) RepositoryManager {
+		return NewSQLiteRepositoryManager(setupSQLiteTestDB(t))
+	})
+}
+
+// seedGroup creates a group with a creator member
+func seedGroup(t *testing.T, repos RepositoryManager) (*models.Group, *models.Member) {
+	t.Helper()
+	ctx := context.Background()
+
+	group := createTestGroup()
+	require.NoError(t, repos.Groups().Create(ctx, group))
+
+	creator := createTestMember(group.ID)
+	creator.UserID = &group.CreatedBy
+	creator.IsCreator = true
+	require.NoError(t, repos.Members().Create(ctx, creator))
+
+	return group, creator
+}
+
+func testGroupConformance(t *testing.T, newRepos newRepositoriesFunc) {
+	ctx := context.Background()
+
+	t.Run("create and get", func(t *testing.T) {
+		repos := newRepos(t)
+		deadline := time.Now().Add(30 * 24 * time.Hour)
+		group := createTestGroup()
+		group.Name = "  Summer Trip  "
+		group.Deadline = &deadline
+		require.NoError(t, repos.Groups().Create(ctx, group))
+
+		retrieved, err := repos.Groups().GetByID(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Summer Trip", retrieved.Name, "names are sanitized")
+		assert.Equal(t, group.CreatedBy, retrieved.CreatedBy)
+		// Timestamps keep microsecond precision
+		assert.True(t, group.CreatedAt.Round(time.Microsecond).Equal(retrieved.CreatedAt))
+		require.NotNil(t, retrieved.Deadline)
+		assert.True(t, deadline.Round(time.Microsecond).Equal(*retrieved.Deadline))
+
+		err = repos.Groups().Create(ctx, group)
+		assert.ErrorIs(t, err, ErrDuplicateID)
+	})
+
+	t.Run("timezone", func(t *testing.T) {
+		repos := newRepos(t)
+		tokyo, err := models.LoadTimezone("Asia/Tokyo")
+		require.NoError(t, err)
+		deadline, err := models.ParseDeadline("2099-12-31", tokyo)
+		require.NoError(t, err)
+
+		group := createTestGroup()
+		group.Deadline = &deadline
+		group.Timezone = "Asia/Tokyo"
+		require.NoError(t, repos.Groups().Create(ctx, group))
+
+		// Deadlines are returned in the group's timezone
+		retrieved, err := repos.Groups().GetByID(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Asia/Tokyo", retrieved.Timezone)
+		require.NotNil(t, retrieved.Deadline)
+		assert.True(t, deadline.Equal(*retrieved.Deadline))
+		assert.Equal(t, "Asia/Tokyo", retrieved.Deadline.Location().String())
+		require.NotNil(t, retrieved.DeadlineDate)
+		assert.Equal(t, "2099-12-31", *retrieved.DeadlineDate)
+
+		summaries, err := repos.Groups().GetSummariesByUserID(ctx, group.CreatedBy)
+		require.NoError(t, err)
+		require.Len(t, summaries, 1)
+		assert.Equal(t, "Asia/Tokyo", summaries[0].Timezone)
+		require.NotNil(t, summaries[0].DeadlineDate)
+		assert.Equal(t, "2099-12-31", *summaries[0].DeadlineDate)
+
+		retrieved.Timezone = "Europe/Paris"
+		require.NoError(t, repos.Groups().Update(ctx, retrieved))
+		retrieved, err = repos.Groups().GetByID(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Europe/Paris", retrieved.Timezone)
+
+		// Groups without a timezone are in UTC
+		plain, _ := seedGroup(t, repos)
+		retrieved, err = repos.Groups().GetByID(ctx, plain.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.DefaultTimezone, retrieved.Timezone)
+		assert.Nil(t, retrieved.DeadlineDate)
+
+		group = createTestGroup()
+		group.Timezone = "Mars/Olympus_Mons"
+		assert.Error(t, repos.Groups().Create(ctx, group))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repos := newRepos(t)
+		id := uuid.New().String()
+
+		_, err := repos.Groups().GetByID(ctx, id)
+		assert.ErrorIs(t, err, ErrGroupNotFound)
+		assert.Equal(t, "group not found: "+id, err.Error())
+
+		_, err = repos.Groups().GetWithDetails(ctx, id)
+		assert.ErrorIs(t, err, ErrGroupNotFound)
+		assert.ErrorIs(t, repos.Groups().Delete(ctx, id), ErrGroupNotFound)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repos := newRepos(t)
+		group, _ := seedGroup(t, repos)
+
+		group.Name = "Renamed Group"
+		require.NoError(t, repos.Groups().Update(ctx, group))
+
+		retrieved, err := repos.Groups().GetByID(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed Group", retrieved.Name)
+
+		missing := createTestGroup()
+		assert.ErrorIs(t, repos.Groups().Update(ctx, missing), ErrGroupNotFound)
+	})
+
+	t.Run("deadline lock", func(t *testing.T) {
+		repos := newRepos(t)
+		deadline := time.Now().Add(time.Hour)
+		group := createTestGroup()
+		group.Deadline = &deadline
+		group.LockAfterDeadline = true
+		require.NoError(t, repos.Groups().Create(ctx, group))
+
+		retrieved, err := repos.Groups().GetByID(ctx, group.ID)
+		require.NoError(t, err)
+		assert.True(t, retrieved.LockAfterDeadline)
+
+		summaries, err := repos.Groups().GetSummariesByUserID(ctx, group.CreatedBy)
+		require.NoError(t, err)
+		require.Len(t, summaries, 1)
+		assert.True(t, summaries[0].LockAfterDeadline)
+
+		retrieved.LockAfterDeadline = false
+		require.NoError(t, repos.Groups().Update(ctx, retrieved))
+		retrieved, err = repos.Groups().GetByID(ctx, group.ID)
+		require.NoError(t, err)
+		assert.False(t, retrieved.LockAfterDeadline)
+	})
+
+	t.Run("get by deadline between", func(t *testing.T) {
+		repos := newRepos(t)
+		now := time.Now().Truncate(time.Microsecond)
+
+		createWithDeadline := func(deadline time.Time) *models.Group {
+			group := createTestGroup()
+			group.Deadline = &deadline
+			require.NoError(t, repos.Groups().Create(ctx, group))
+			return group
+		}
+		later := createWithDeadline(now.Add(2 * time.Hour))
+		sooner := createWithDeadline(now.Add(time.Hour))
+		createWithDeadline(now)                     // not after the start
+		createWithDeadline(now.Add(3 * time.Hour)) // after the end
+		require.NoError(t, repos.Groups().Create(ctx, createTestGroup()))
+
+		groups, err := repos.Groups().GetByDeadlineBetween(ctx, now, now.Add(2*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, groups, 2)
+		assert.Equal(t, sooner.ID, groups[0].ID, "soonest first")
+		assert.Equal(t, later.ID, groups[1].ID, "the end is included")
+		require.NotNil(t, groups[0].DeadlineDate)
+
+		groups, err = repos.Groups().GetByDeadlineBetween(ctx, now.Add(4*time.Hour), now.Add(5*time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, groups)
+	})
+
+	t.Run("reminder days", func(t *testing.T) {
+		repos := newRepos(t)
+		group := createTestGroup()
+		require.NoError(t, repos.Groups().Create(ctx, group))
+
+		days, err := repos.Groups().GetReminderDays(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Empty(t, days)
+
+		require.NoError(t, repos.Groups().SetReminderDays(ctx, group.ID, []int{30, 7, 1}))
+		require.NoError(t, repos.Groups().SetReminderDays(ctx, group.ID, []int{14, 2}))
+		days, err = repos.Groups().GetReminderDays(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []int{14, 2}, days, "replaced, furthest first")
+
+		require.NoError(t, repos.Groups().SetReminderDays(ctx, group.ID, nil))
+		days, err = repos.Groups().GetReminderDays(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Empty(t, days)
+
+		missing := uuid.New().String()
+		_, err = repos.Groups().GetReminderDays(ctx, missing)
+		assert.ErrorIs(t, err, ErrGroupNotFound)
+		assert.ErrorIs(t, repos.Groups().SetReminderDays(ctx, missing, []int{1}), ErrGroupNotFound)
+	})
+
+	t.Run("get due reminders", func(t *testing.T) {
+		repos := newRepos(t)
+		now := time.Now().Truncate(time.Microsecond)
+		day := 24 * time.Hour
+
+		createWithReminders := func(deadline time.Time, days ...int) *models.Group {
+			group := createTestGroup()
+			group.Deadline = &deadline
+			require.NoError(t, repos.Groups().Create(ctx, group))
+			require.NoError(t, repos.Groups().SetReminderDays(ctx, group.ID, days))
+			return group
+		}
+		// The week-before reminder falls due in an hour
+		week := createWithReminders(now.Add(7*day+time.Hour), 30, 7, 1)
+		// Both reminders fall due in two hours
+		both := createWithReminders(now.Add(2*time.Hour+3*day), 3, 2)
+		createWithReminders(now.Add(2*time.Hour + 3*day)) // no reminders
+		createWithReminders(now.Add(day), 1)              // not after the start
+		// The day-before reminder falls due after the end, in three hours
+		afterEnd := createWithReminders(now.Add(3*time.Hour+day), 1)
+		require.NoError(t, repos.Groups().Create(ctx, createTestGroup()))
+
+		reminders, err := repos.Groups().GetDueReminders(ctx, now, now.Add(2*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, reminders, 2)
+		assert.Equal(t, week.ID, reminders[0].Group.ID, "earliest first")
+		assert.Equal(t, 7, reminders[0].DaysBefore)
+		assert.True(t, now.Add(time.Hour).Equal(reminders[0].RemindAt))
+		assert.Equal(t, both.ID, reminders[1].Group.ID, "the end is included")
+		assert.Equal(t, 3, reminders[1].DaysBefore)
+		require.NotNil(t, reminders[1].Group.DeadlineDate)
+
+		reminders, err = repos.Groups().GetDueReminders(ctx, now.Add(2*time.Hour), now.Add(26*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, reminders, 2)
+		assert.Equal(t, afterEnd.ID, reminders[0].Group.ID)
+		assert.Equal(t, 1, reminders[0].DaysBefore)
+		assert.Equal(t, both.ID, reminders[1].Group.ID)
+		assert.Equal(t, 2, reminders[1].DaysBefore)
+	})
+
+	t.Run("delete cascades", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		item := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+
+		require.NoError(t, repos.Groups().Delete(ctx, group.ID))
+
+		_, err := repos.Members().GetByID(ctx, creator.ID)
+		assert.ErrorIs(t, err, ErrMemberNotFound)
+		_, err = repos.BucketItems().GetByID(ctx, item.ID)
+		assert.ErrorIs(t, err, ErrItemNotFound)
+	})
+
+	t.Run("get by user ID", func(t *testing.T) {
+		repos := newRepos(t)
+		userID := uuid.New().String()
+		older := createTestGroup()
+		older.CreatedBy = userID
+		older.CreatedAt = time.Now().Add(-time.Hour)
+		newer := createTestGroup()
+		newer.CreatedBy = userID
+		require.NoError(t, repos.Groups().Create(ctx, older))
+		require.NoError(t, repos.Groups().Create(ctx, newer))
+		require.NoError(t, repos.Groups().Create(ctx, createTestGroup()))
+
+		groups, err := repos.Groups().GetByUserID(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, groups, 2)
+		assert.Equal(t, newer.ID, groups[0].ID)
+		assert.Equal(t, older.ID, groups[1].ID)
+
+		groups, err = repos.Groups().GetByUserID(ctx, uuid.New().String())
+		require.NoError(t, err)
+		assert.Empty(t, groups)
+	})
+
+	t.Run("details and summaries", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+
+		// A second group the user joined rather than created
+		otherGroup, _ := seedGroup(t, repos)
+		joined := createTestMember(otherGroup.ID)
+		joined.UserID = &group.CreatedBy
+		require.NoError(t, repos.Members().Create(ctx, joined))
+
+		for i := 0; i < 4; i++ {
+			item := createTestBucketItem(group.ID, creator.ID)
+			item.CreatedAt = time.Now().Add(time.Duration(i) * time.Second)
+			require.NoError(t, repos.BucketItems().Create(ctx, item))
+			if i == 0 {
+				require.NoError(t, repos.BucketItems().ToggleCompletion(ctx, item.ID, creator.ID, true))
+			}
+		}
+
+		details, err := repos.Groups().GetWithDetails(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Equal(t, group.ID, details.ID)
+		assert.Len(t, details.Members, 1)
+		require.Len(t, details.Items, 4)
+		assert.True(t, details.Items[0].CreatedAt.After(details.Items[3].CreatedAt), "items are newest first")
+
+		summaries, err := repos.Groups().GetSummariesByUserID(ctx, group.CreatedBy)
+		require.NoError(t, err)
+		require.Len(t, summaries, 2)
+
+		byID := map[string]models.GroupSummary{}
+		for _, summary := range summaries {
+			byID[summary.ID] = summary
+		}
+		assert.Equal(t, 1, byID[group.ID].MemberCount)
+		assert.Equal(t, 4, byID[group.ID].ItemCount)
+		assert.Equal(t, 1, byID[group.ID].CompletedCount)
+		assert.InDelta(t, 25.0, byID[group.ID].ProgressPercent, 0.001)
+		assert.Equal(t, 2, byID[otherGroup.ID].MemberCount)
+		assert.Equal(t, 0.0, byID[otherGroup.ID].ProgressPercent)
+	})
+}
+
+func testMemberConformance(t *testing.T, newRepos newRepositoriesFunc) {
+	ctx := context.Background()
+
+	t.Run("create and get", func(t *testing.T) {
+		repos := newRepos(t)
+		group, _ := seedGroup(t, repos)
+
+		member := createTestMember(group.ID)
+		require.NoError(t, repos.Members().Create(ctx, member))
+
+		retrieved, err := repos.Members().GetByID(ctx, member.ID)
+		require.NoError(t, err)
+		assert.Equal(t, member.Name, retrieved.Name)
+		require.NotNil(t, retrieved.UserID)
+		assert.Equal(t, *member.UserID, *retrieved.UserID)
+
+		// Returned models are copies
+		*retrieved.UserID = "changed"
+		again, err := repos.Members().GetByID(ctx, member.ID)
+		require.NoError(t, err)
+		assert.Equal(t, *member.UserID, *again.UserID)
+
+		_, err = repos.Members().GetByID(ctx, uuid.New().String())
+		assert.ErrorIs(t, err, ErrMemberNotFound)
+	})
+
+	t.Run("constraint violations", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+
+		duplicate := createTestMember(group.ID)
+		duplicate.UserID = creator.UserID
+		assert.ErrorIs(t, repos.Members().Create(ctx, duplicate), ErrAlreadyMember)
+
+		assert.ErrorIs(t, repos.Members().Create(ctx, createTestMember(uuid.New().String())), ErrGroupNotFound)
+
+		sameID := createTestMember(group.ID)
+		sameID.ID = creator.ID
+		assert.ErrorIs(t, repos.Members().Create(ctx, sameID), ErrDuplicateID)
+
+		// Any number of anonymous members may join
+		for i := 0; i < 2; i++ {
+			anonymous := createTestMember(group.ID)
+			anonymous.UserID = nil
+			assert.NoError(t, repos.Members().Create(ctx, anonymous))
+		}
+
+		invalid := createTestMember(group.ID)
+		invalid.Name = ""
+		assert.Error(t, repos.Members().Create(ctx, invalid))
+	})
+
+	t.Run("listing", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		otherGroup, _ := seedGroup(t, repos)
+
+		later := createTestMember(group.ID)
+		later.JoinedAt = time.Now().Add(time.Minute)
+		require.NoError(t, repos.Members().Create(ctx, later))
+
+		members, err := repos.Members().GetByGroupID(ctx, group.ID)
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+		assert.Equal(t, creator.ID, members[0].ID, "earliest joined first")
+		assert.Equal(t, later.ID, members[1].ID)
+
+		membership := createTestMember(otherGroup.ID)
+		membership.UserID = creator.UserID
+		membership.JoinedAt = time.Now().Add(time.Hour)
+		require.NoError(t, repos.Members().Create(ctx, membership))
+
+		memberships, err := repos.Members().GetByUserID(ctx, *creator.UserID)
+		require.NoError(t, err)
+		require.Len(t, memberships, 2)
+		assert.Equal(t, membership.ID, memberships[0].ID, "latest joined first")
+
+		exists, err := repos.Members().ExistsByGroupAndUser(ctx, group.ID, *creator.UserID)
+		require.NoError(t, err)
+		assert.True(t, exists)
+		exists, err = repos.Members().ExistsByGroupAndUser(ctx, group.ID, uuid.New().String())
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		found, err := repos.Members().GetCreatorByGroupID(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Equal(t, creator.ID, found.ID)
+		_, err = repos.Members().GetCreatorByGroupID(ctx, uuid.New().String())
+		assert.Error(t, err)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+
+		member := createTestMember(group.ID)
+		require.NoError(t, repos.Members().Create(ctx, member))
+
+		member.Name = "Renamed Member"
+		require.NoError(t, repos.Members().Update(ctx, member))
+		retrieved, err := repos.Members().GetByID(ctx, member.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed Member", retrieved.Name)
+
+		member.UserID = creator.UserID
+		assert.ErrorIs(t, repos.Members().Update(ctx, member), ErrAlreadyMember)
+
+		assert.ErrorIs(t, repos.Members().Update(ctx, createTestMember(group.ID)), ErrMemberNotFound)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		leaving := createTestMember(group.ID)
+		require.NoError(t, repos.Members().Create(ctx, leaving))
+
+		added := createTestBucketItem(group.ID, leaving.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, added))
+		completed := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, completed))
+		require.NoError(t, repos.BucketItems().ToggleCompletion(ctx, completed.ID, leaving.ID, true))
+
+		require.NoError(t, repos.Members().Delete(ctx, leaving.ID))
+
+		// The group keeps the items the member added or completed
+		retrieved, err := repos.BucketItems().GetByID(ctx, added.ID)
+		require.NoError(t, err)
+		assert.Nil(t, retrieved.CreatedBy)
+		retrieved, err = repos.BucketItems().GetByID(ctx, completed.ID)
+		require.NoError(t, err)
+		assert.True(t, retrieved.Completed)
+		assert.Nil(t, retrieved.CompletedBy)
+
+		assert.ErrorIs(t, repos.Members().Delete(ctx, leaving.ID), ErrMemberNotFound)
+	})
+}
+
+func testBucketItemConformance(t *testing.T, newRepos newRepositoriesFunc) {
+	ctx := context.Background()
+
+	t.Run("create and get", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+
+		item := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+
+		retrieved, err := repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, item.Title, retrieved.Title)
+		assert.Equal(t, *item.Description, *retrieved.Description)
+		assert.False(t, retrieved.Completed)
+		assert.Nil(t, retrieved.CompletedBy)
+		assert.Nil(t, retrieved.CompletedAt)
+
+		noDescription := createTestBucketItem(group.ID, creator.ID)
+		noDescription.Description = nil
+		require.NoError(t, repos.BucketItems().Create(ctx, noDescription))
+		retrieved, err = repos.BucketItems().GetByID(ctx, noDescription.ID)
+		require.NoError(t, err)
+		assert.Nil(t, retrieved.Description)
+
+		_, err = repos.BucketItems().GetByID(ctx, uuid.New().String())
+		assert.ErrorIs(t, err, ErrItemNotFound)
+	})
+
+	t.Run("constraint violations", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+
+		unknownCreator := createTestBucketItem(group.ID, uuid.New().String())
+		assert.ErrorIs(t, repos.BucketItems().Create(ctx, unknownCreator), ErrMemberNotFound)
+
+		unknownGroup := createTestBucketItem(uuid.New().String(), creator.ID)
+		assert.ErrorIs(t, repos.BucketItems().Create(ctx, unknownGroup), ErrGroupNotFound)
+
+		item := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+		duplicate := createTestBucketItem(group.ID, creator.ID)
+		duplicate.ID = item.ID
+		assert.ErrorIs(t, repos.BucketItems().Create(ctx, duplicate), ErrDuplicateID)
+
+		assert.ErrorIs(t, repos.BucketItems().ToggleCompletion(ctx, item.ID, uuid.New().String(), true), ErrMemberNotFound)
+	})
+
+	t.Run("completion", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		item := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+
+		require.NoError(t, repos.BucketItems().ToggleCompletion(ctx, item.ID, creator.ID, true))
+		retrieved, err := repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.True(t, retrieved.Completed)
+		require.NotNil(t, retrieved.CompletedBy)
+		assert.Equal(t, creator.ID, *retrieved.CompletedBy)
+		require.NotNil(t, retrieved.CompletedAt)
+		assert.WithinDuration(t, time.Now(), *retrieved.CompletedAt, time.Minute)
+
+		total, completed, err := repos.BucketItems().GetCompletionStats(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, 1, completed)
+
+		require.NoError(t, repos.BucketItems().ToggleCompletion(ctx, item.ID, creator.ID, false))
+		retrieved, err = repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.False(t, retrieved.Completed)
+		assert.Nil(t, retrieved.CompletedBy)
+		assert.Nil(t, retrieved.CompletedAt)
+
+		err = repos.BucketItems().ToggleCompletion(ctx, uuid.New().String(), creator.ID, true)
+		assert.ErrorIs(t, err, ErrItemNotFound)
+	})
+
+	t.Run("completion journal", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		item := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+
+		note, rating, occurredOn := "  Terrifying, then wonderful  ", 5, "2024-06-01"
+		journal := models.CompletionJournal{CompletionNote: &note, CompletionRating: &rating, OccurredOn: &occurredOn}
+		err := repos.BucketItems().UpdateCompletionJournal(ctx, item.ID, journal)
+		assert.ErrorIs(t, err, ErrItemNotCompleted)
+		err = repos.BucketItems().UpdateCompletionJournal(ctx, uuid.New().String(), journal)
+		assert.ErrorIs(t, err, ErrItemNotFound)
+
+		require.NoError(t, repos.BucketItems().ToggleCompletion(ctx, item.ID, creator.ID, true))
+		require.NoError(t, repos.BucketItems().UpdateCompletionJournal(ctx, item.ID, journal))
+
+		retrieved, err := repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		require.NotNil(t, retrieved.CompletionNote)
+		assert.Equal(t, "Terrifying, then wonderful", *retrieved.CompletionNote)
+		require.NotNil(t, retrieved.CompletionRating)
+		assert.Equal(t, 5, *retrieved.CompletionRating)
+		require.NotNil(t, retrieved.OccurredOn)
+		assert.Equal(t, "2024-06-01", *retrieved.OccurredOn)
+
+		// Every read path returns the journal
+		items, err := repos.BucketItems().GetByGroupID(ctx, group.ID)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, retrieved.CompletionJournal, items[0].CompletionJournal)
+
+		page, err := repos.BucketItems().List(ctx, group.ID, models.ItemListOptions{})
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		assert.Equal(t, retrieved.CompletionJournal, page.Items[0].CompletionJournal)
+
+		details, err := repos.Groups().GetWithDetails(ctx, group.ID)
+		require.NoError(t, err)
+		require.Len(t, details.Items, 1)
+		assert.Equal(t, retrieved.CompletionJournal, details.Items[0].CompletionJournal)
+
+		results, err := repos.BucketItems().Search(ctx, group.ID, "bucket", 10)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, retrieved.CompletionJournal, results[0].CompletionJournal)
+
+		// Invalid journals are rejected
+		rating = 6
+		assert.Error(t, repos.BucketItems().UpdateCompletionJournal(ctx, item.ID, journal))
+
+		// An empty journal clears it
+		require.NoError(t, repos.BucketItems().UpdateCompletionJournal(ctx, item.ID, models.CompletionJournal{}))
+		retrieved, err = repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.True(t, retrieved.CompletionJournal.IsEmpty())
+
+		// Updates write the journal
+		rating = 4
+		retrieved.CompletionJournal = journal
+		require.NoError(t, repos.BucketItems().Update(ctx, retrieved))
+		retrieved, err = repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		require.NotNil(t, retrieved.CompletionRating)
+		assert.Equal(t, 4, *retrieved.CompletionRating)
+
+		// Marking the item incomplete clears it too
+		require.NoError(t, repos.BucketItems().UpdateCompletionJournal(ctx, item.ID, journal))
+		require.NoError(t, repos.BucketItems().ToggleCompletion(ctx, item.ID, creator.ID, false))
+		retrieved, err = repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.True(t, retrieved.CompletionJournal.IsEmpty())
+	})
+
+	t.Run("schedule", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+
+		start := time.Date(2026, 7, 4, 18, 30, 0, 0, time.UTC)
+		end := start.Add(3 * time.Hour)
+		item := createTestBucketItem(group.ID, creator.ID)
+		item.ScheduledStart = &start
+		item.ScheduledEnd = &end
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+		unscheduled := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, unscheduled))
+
+		retrieved, err := repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		require.NotNil(t, retrieved.ScheduledStart)
+		require.NotNil(t, retrieved.ScheduledEnd)
+		assert.True(t, start.Equal(*retrieved.ScheduledStart))
+		assert.True(t, end.Equal(*retrieved.ScheduledEnd))
+
+		items, err := repos.BucketItems().GetByGroupID(ctx, group.ID)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		for _, listed := range items {
+			assert.Equal(t, listed.ID == item.ID, listed.ScheduledStart != nil)
+		}
+
+		// Updating an item keeps its schedule
+		retrieved.Title = "Fireworks"
+		require.NoError(t, repos.BucketItems().Update(ctx, retrieved))
+		retrieved, err = repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		require.NotNil(t, retrieved.ScheduledStart)
+		assert.True(t, start.Equal(*retrieved.ScheduledStart))
+
+		// A start without an end is a point in time
+		moved := start.AddDate(0, 0, 1)
+		require.NoError(t, repos.BucketItems().UpdateSchedule(ctx, item.ID, models.ItemSchedule{ScheduledStart: &moved}))
+		retrieved, err = repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		require.NotNil(t, retrieved.ScheduledStart)
+		assert.True(t, moved.Equal(*retrieved.ScheduledStart))
+		assert.Nil(t, retrieved.ScheduledEnd)
+
+		// An end before the start is rejected and the schedule kept
+		early := moved.Add(-time.Hour)
+		err = repos.BucketItems().UpdateSchedule(ctx, item.ID, models.ItemSchedule{ScheduledStart: &moved, ScheduledEnd: &early})
+		assert.Error(t, err)
+		err = repos.BucketItems().UpdateSchedule(ctx, uuid.New().String(), models.ItemSchedule{ScheduledStart: &moved})
+		assert.ErrorIs(t, err, ErrItemNotFound)
+
+		// An empty schedule unschedules the item
+		require.NoError(t, repos.BucketItems().UpdateSchedule(ctx, item.ID, models.ItemSchedule{}))
+		retrieved, err = repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.True(t, retrieved.ItemSchedule.IsEmpty())
+	})
+
+	t.Run("calendar token hash", func(t *testing.T) {
+		repos := newRepos(t)
+		group, _ := seedGroup(t, repos)
+		other, _ := seedGroup(t, repos)
+
+		tokenHash, err := repos.Groups().GetCalendarTokenHash(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Empty(t, tokenHash)
+
+		// The first token hash sticks until it is revoked
+		tokenHash, err = repos.Groups().EnsureCalendarTokenHash(ctx, group.ID, "first")
+		require.NoError(t, err)
+		assert.Equal(t, "first", tokenHash)
+		tokenHash, err = repos.Groups().EnsureCalendarTokenHash(ctx, group.ID, "second")
+		require.NoError(t, err)
+		assert.Equal(t, "first", tokenHash)
+		tokenHash, err = repos.Groups().GetCalendarTokenHash(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "first", tokenHash)
+
+		tokenHash, err = repos.Groups().GetCalendarTokenHash(ctx, other.ID)
+		require.NoError(t, err)
+		assert.Empty(t, tokenHash)
+
+		require.NoError(t, repos.Groups().RevokeCalendarToken(ctx, group.ID))
+		tokenHash, err = repos.Groups().GetCalendarTokenHash(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Empty(t, tokenHash)
+		tokenHash, err = repos.Groups().EnsureCalendarTokenHash(ctx, group.ID, "second")
+		require.NoError(t, err)
+		assert.Equal(t, "second", tokenHash)
+
+		missing := uuid.New().String()
+		_, err = repos.Groups().GetCalendarTokenHash(ctx, missing)
+		assert.ErrorIs(t, err, ErrGroupNotFound)
+		_, err = repos.Groups().EnsureCalendarTokenHash(ctx, missing, "third")
+		assert.ErrorIs(t, err, ErrGroupNotFound)
+		assert.ErrorIs(t, repos.Groups().RevokeCalendarToken(ctx, missing), ErrGroupNotFound)
+	})
+
+	t.Run("assignment", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		otherGroup, outsider := seedGroup(t, repos)
+		member := createTestMember(group.ID)
+		require.NoError(t, repos.Members().Create(ctx, member))
+
+		older := createTestBucketItem(group.ID, creator.ID)
+		older.Title = "Older"
+		older.CreatedAt = time.Now().Add(-time.Hour)
+		item := createTestBucketItem(group.ID, creator.ID)
+		item.Title = "Assigned"
+		item.AssigneeIDs = []string{member.ID}
+		unassigned := createTestBucketItem(group.ID, creator.ID)
+		foreign := createTestBucketItem(otherGroup.ID, outsider.ID)
+		for _, created := range []*models.BucketListItem{older, item, unassigned, foreign} {
+			require.NoError(t, repos.BucketItems().Create(ctx, created))
+		}
+
+		retrieved, err := repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Empty(t, retrieved.AssigneeIDs, "items are assigned with SetAssignment only")
+
+		// Assigning twice changes nothing and assignees keep their order
+		require.NoError(t, repos.BucketItems().SetAssignment(ctx, item.ID, member.ID, true))
+		require.NoError(t, repos.BucketItems().SetAssignment(ctx, item.ID, creator.ID, true))
+		require.NoError(t, repos.BucketItems().SetAssignment(ctx, item.ID, member.ID, true))
+		require.NoError(t, repos.BucketItems().SetAssignment(ctx, older.ID, creator.ID, true))
+		require.NoError(t, repos.BucketItems().SetAssignment(ctx, foreign.ID, outsider.ID, true))
+		expected := []string{member.ID, creator.ID}
+
+		retrieved, err = repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, retrieved.AssigneeIDs)
+
+		// Updating an item keeps its assignees
+		require.NoError(t, repos.BucketItems().Update(ctx, retrieved))
+		retrieved, err = repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, retrieved.AssigneeIDs)
+
+		items, err := repos.BucketItems().GetByGroupID(ctx, group.ID)
+		require.NoError(t, err)
+		require.Len(t, items, 3)
+		for _, listed := range items {
+			if listed.ID == item.ID {
+				assert.Equal(t, expected, listed.AssigneeIDs)
+			}
+		}
+
+		details, err := repos.Groups().GetWithDetails(ctx, group.ID)
+		require.NoError(t, err)
+		for _, listed := range details.Items {
+			if listed.ID == unassigned.ID {
+				assert.Empty(t, listed.AssigneeIDs)
+			}
+		}
+
+		page, err := repos.BucketItems().List(ctx, group.ID, models.ItemListOptions{AssignedTo: member.ID})
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		assert.Equal(t, item.ID, page.Items[0].ID)
+		assert.Equal(t, expected, page.Items[0].AssigneeIDs)
+
+		results, err := repos.BucketItems().Search(ctx, group.ID, "assigned", 10)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, expected, results[0].AssigneeIDs)
+
+		// Items assigned to any of the members, newest first
+		assigned, err := repos.BucketItems().GetAssignedTo(ctx, []string{creator.ID, outsider.ID})
+		require.NoError(t, err)
+		require.Len(t, assigned, 3)
+		assert.Equal(t, older.ID, assigned[2].ID)
+		assert.ElementsMatch(t, []string{item.ID, foreign.ID}, []string{assigned[0].ID, assigned[1].ID})
+		for _, listed := range assigned {
+			if listed.ID == foreign.ID {
+				assert.Equal(t, []string{outsider.ID}, listed.AssigneeIDs)
+			}
+		}
+		none, err := repos.BucketItems().GetAssignedTo(ctx, []string{uuid.New().String()})
+		require.NoError(t, err)
+		assert.Empty(t, none)
+
+		// Only members of the item's group can be assigned
+		err = repos.BucketItems().SetAssignment(ctx, item.ID, outsider.ID, true)
+		assert.ErrorIs(t, err, ErrMemberNotFound)
+		err = repos.BucketItems().SetAssignment(ctx, item.ID, uuid.New().String(), true)
+		assert.ErrorIs(t, err, ErrMemberNotFound)
+		err = repos.BucketItems().SetAssignment(ctx, uuid.New().String(), member.ID, true)
+		assert.ErrorIs(t, err, ErrItemNotFound)
+
+		// Unassigning a member who was never assigned is a no-op
+		require.NoError(t, repos.BucketItems().SetAssignment(ctx, item.ID, creator.ID, false))
+		require.NoError(t, repos.BucketItems().SetAssignment(ctx, item.ID, creator.ID, false))
+		retrieved, err = repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{member.ID}, retrieved.AssigneeIDs)
+
+		// Deleting a member removes their assignments
+		require.NoError(t, repos.Members().Delete(ctx, member.ID))
+		retrieved, err = repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Empty(t, retrieved.AssigneeIDs)
+	})
+
+	t.Run("update and delete", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		item := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+
+		item.Title = "Updated Title"
+		require.NoError(t, repos.BucketItems().Update(ctx, item))
+		retrieved, err := repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Updated Title", retrieved.Title)
+
+		items, err := repos.BucketItems().GetByGroupID(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Len(t, items, 1)
+
+		require.NoError(t, repos.BucketItems().Delete(ctx, item.ID))
+		assert.ErrorIs(t, repos.BucketItems().Delete(ctx, item.ID), ErrItemNotFound)
+		assert.ErrorIs(t, repos.BucketItems().Update(ctx, item), ErrItemNotFound)
+
+		items, err = repos.BucketItems().GetByGroupID(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Empty(t, items)
+	})
+}
+
+func testTransactionConformance(t *testing.T, newRepos newRepositoriesFunc) {
+	ctx := context.Background()
+
+	t.Run("commit", func(t *testing.T) {
+		repos := newRepos(t)
+		group := createTestGroup()
+
+		err := repos.WithTx(ctx, func(tx RepositoryManager) error {
+			if err := tx.Groups().Create(ctx, group); err != nil {
+				return err
+			}
+			return tx.Members().Create(ctx, createTestMember(group.ID))
+		})
+		require.NoError(t, err)
+
+		members, err := repos.Members().GetByGroupID(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Len(t, members, 1)
+	})
+
+	t.Run("rollback on error", func(t *testing.T) {
+		repos := newRepos(t)
+		group := createTestGroup()
+		failure := errors.New("member creation failed")
+
+		err := repos.WithTx(ctx, func(tx RepositoryManager) error {
+			if err := tx.Groups().Create(ctx, group); err != nil {
+				return err
+			}
+			// Visible inside the transaction
+			if _, err := tx.Groups().GetByID(ctx, group.ID); err != nil {
+				return err
+			}
+			return failure
+		})
+		assert.ErrorIs(t, err, failure)
+
+		_, err = repos.Groups().GetByID(ctx, group.ID)
+		assert.ErrorIs(t, err, ErrGroupNotFound)
+	})
+
+	t.Run("rollback on panic", func(t *testing.T) {
+		repos := newRepos(t)
+		group := createTestGroup()
+
+		assert.Panics(t, func() {
+			repos.WithTx(ctx, func(tx RepositoryManager) error {
+				if err := tx.Groups().Create(ctx, group); err != nil {
+					return err
+				}
+				panic("boom")
+			})
+		})
+
+		_, err := repos.Groups().GetByID(ctx, group.ID)
+		assert.ErrorIs(t, err, ErrGroupNotFound)
+	})
+
+	t.Run("nested commit", func(t *testing.T) {
+		repos := newRepos(t)
+		group := createTestGroup()
+		member := createTestMember(group.ID)
+
+		err := repos.WithTx(ctx, func(tx RepositoryManager) error {
+			if err := tx.Groups().Create(ctx, group); err != nil {
+				return err
+			}
+			return tx.WithTx(ctx, func(nested RepositoryManager) error {
+				return nested.Members().Create(ctx, member)
+			})
+		})
+		require.NoError(t, err)
+
+		_, err = repos.Members().GetByID(ctx, member.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("nested failure rolls back only the nested call", func(t *testing.T) {
+		repos := newRepos(t)
+		group := createTestGroup()
+		kept := createTestMember(group.ID)
+		discarded := createTestMember(group.ID)
+
+		err := repos.WithTx(ctx, func(tx RepositoryManager) error {
+			if err := tx.Groups().Create(ctx, group); err != nil {
+				return err
+			}
+
+			err := tx.WithTx(ctx, func(nested RepositoryManager) error {
+				if err := nested.Members().Create(ctx, discarded); err != nil {
+					return err
+				}
+				// A constraint violation inside the savepoint
+				return nested.Members().Create(ctx, createTestMember(uuid.New().String()))
+			})
+			if !errors.Is(err, ErrGroupNotFound) {
+				return fmt.Errorf("expected group not found error, got %v", err)
+			}
+
+			// The transaction is still usable after the nested failure
+			return tx.Members().Create(ctx, kept)
+		})
+		require.NoError(t, err)
+
+		_, err = repos.Groups().GetByID(ctx, group.ID)
+		assert.NoError(t, err)
+		_, err = repos.Members().GetByID(ctx, kept.ID)
+		assert.NoError(t, err)
+		_, err = repos.Members().GetByID(ctx, discarded.ID)
+		assert.ErrorIs(t, err, ErrMemberNotFound)
+	})
+
+	t.Run("nested panic rolls back the transaction", func(t *testing.T) {
+		repos := newRepos(t)
+		group := createTestGroup()
+
+		assert.Panics(t, func() {
+			repos.WithTx(ctx, func(tx RepositoryManager) error {
+				if err := tx.Groups().Create(ctx, group); err != nil {
+					return err
+				}
+				return tx.WithTx(ctx, func(RepositoryManager) error {
+					panic("boom")
+				})
+			})
+		})
+
+		_, err := repos.Groups().GetByID(ctx, group.ID)
+		assert.ErrorIs(t, err, ErrGroupNotFound)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		repos := newRepos(t)
+		group := createTestGroup()
+
+		err := repos.WithTxOptions(ctx, TxOptions{Isolation: IsolationSerializable},
+			func(tx RepositoryManager) error {
+				return tx.Groups().Create(ctx, group)
+			})
+		require.NoError(t, err)
+
+		err = repos.WithTxOptions(ctx, TxOptions{ReadOnly: true}, func(tx RepositoryManager) error {
+			_, err := tx.Groups().GetByID(ctx, group.ID)
+			return err
+		})
+		assert.NoError(t, err)
+	})
+}
+
+
+func testSearchConformance(t *testing.T, newRepos newRepositoriesFunc) {
+	ctx := context.Background()
+
+	addItem := func(t *testing.T, repos RepositoryManager, groupID, memberID, title string, description *string) *models.BucketListItem {
+		t.Helper()
+		item := createTestBucketItem(groupID, memberID)
+		item.Title = title
+		item.Description = description
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+		return item
+	}
+	strPtr := func(s string) *string { return &s }
+
+	t.Run("items in a group", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		other, otherCreator := seedGroup(t, repos)
+
+		titleMatch := addItem(t, repos, group.ID, creator.ID, "Go hiking in the Alps", strPtr("Pack boots"))
+		descriptionMatch := addItem(t, repos, group.ID, creator.ID, "Mountain weekend", strPtr("A long hike to the summit"))
+		addItem(t, repos, group.ID, creator.ID, "Learn to surf", nil)
+		addItem(t, repos, other.ID, otherCreator.ID, "Hiking in another group", nil)
+
+		results, err := repos.BucketItems().Search(ctx, group.ID, "hik", 10)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, titleMatch.ID, results[0].ID)
+		assert.Equal(t, descriptionMatch.ID, results[1].ID)
+		assert.Greater(t, results[0].Rank, results[1].Rank)
+		assert.Equal(t, group.Name, results[0].GroupName)
+		assert.Contains(t, results[0].TitleHighlight, "<mark>hiking</mark>")
+		require.NotNil(t, results[1].DescriptionHighlight)
+		assert.Contains(t, *results[1].DescriptionHighlight, "<mark>hike</mark>")
+
+		results, err = repos.BucketItems().Search(ctx, group.ID, "hik", 1)
+		require.NoError(t, err)
+		assert.Len(t, results, 1)
+
+		results, err = repos.BucketItems().Search(ctx, group.ID, "hiking surf", 10)
+		require.NoError(t, err)
+		assert.Empty(t, results, "every term must match")
+
+		results, err = repos.BucketItems().Search(ctx, group.ID, "scuba", 10)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+
+	t.Run("highlights are escaped", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		addItem(t, repos, group.ID, creator.ID, "Safari <b>trip</b>", nil)
+
+		results, err := repos.BucketItems().Search(ctx, group.ID, "safari", 10)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.NotContains(t, results[0].TitleHighlight, "<b>")
+		assert.Contains(t, results[0].TitleHighlight, "<mark>Safari</mark>")
+	})
+
+	t.Run("across a user's groups", func(t *testing.T) {
+		repos := newRepos(t)
+		created, creator := seedGroup(t, repos)
+		joined, joinedCreator := seedGroup(t, repos)
+		stranger, strangerCreator := seedGroup(t, repos)
+
+		member := createTestMember(joined.ID)
+		member.UserID = &created.CreatedBy
+		require.NoError(t, repos.Members().Create(ctx, member))
+
+		created.Name = "Travel plans"
+		require.NoError(t, repos.Groups().Update(ctx, created))
+		stranger.Name = "Travel club"
+		require.NoError(t, repos.Groups().Update(ctx, stranger))
+
+		addItem(t, repos, created.ID, creator.ID, "Travel to Japan", nil)
+		addItem(t, repos, joined.ID, joinedCreator.ID, "Travel by train", nil)
+		addItem(t, repos, stranger.ID, strangerCreator.ID, "Travel by boat", nil)
+
+		groups, err := repos.Groups().SearchByUserID(ctx, created.CreatedBy, "travel", 10)
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		assert.Equal(t, created.ID, groups[0].ID)
+		assert.Contains(t, groups[0].NameHighlight, "<mark>Travel</mark>")
+
+		items, err := repos.BucketItems().SearchByUserID(ctx, created.CreatedBy, "travel", 10)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		for _, item := range items {
+			assert.NotEqual(t, stranger.ID, item.GroupID)
+		}
+
+		items, err = repos.BucketItems().SearchByUserID(ctx, uuid.New().String(), "travel", 10)
+		require.NoError(t, err)
+		assert.Empty(t, items)
+	})
+}
+
+
+func testPaginationConformance(t *testing.T, newRepos newRepositoriesFunc) {
+	ctx := context.Background()
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// listAllItems follows cursors until the last page, returning the item titles
+	listAllItems := func(t *testing.T, repos RepositoryManager, groupID string, opts models.ItemListOptions) []string {
+		t.Helper()
+		var titles []string
+		for pages := 0; ; pages++ {
+			require.Less(t, pages, 20, "pagination did not terminate")
+			page, err := repos.BucketItems().List(ctx, groupID, opts)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(page.Items), models.PageLimit(opts.Limit))
+			for _, item := range page.Items {
+				titles = append(titles, item.Title)
+			}
+			if page.NextCursor == "" {
+				return titles
+			}
+			opts.Cursor = page.NextCursor
+		}
+	}
+
+	t.Run("items", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		other := createTestMember(group.ID)
+		require.NoError(t, repos.Members().Create(ctx, other))
+
+		titles := []string{"delta", "Alpha", "echo", "charlie", "Bravo"}
+		for i, title := range titles {
+			item := createTestBucketItem(group.ID, creator.ID)
+			item.Title = title
+			item.CreatedAt = base.Add(time.Duration(i) * time.Hour)
+			if i%2 == 1 {
+				item.CreatedBy = &other.ID
+			}
+			require.NoError(t, repos.BucketItems().Create(ctx, item))
+			if i < 2 {
+				require.NoError(t, repos.BucketItems().ToggleCompletion(ctx, item.ID, creator.ID, true))
+			}
+		}
+		// Items created at the same time are ordered by ID
+		for _, title := range []string{"tie one", "tie two"} {
+			item := createTestBucketItem(group.ID, creator.ID)
+			item.Title = title
+			item.CreatedAt = base.Add(10 * time.Hour)
+			require.NoError(t, repos.BucketItems().Create(ctx, item))
+		}
+
+		newest := listAllItems(t, repos, group.ID, models.ItemListOptions{Limit: 2})
+		require.Len(t, newest, 7)
+		assert.ElementsMatch(t, []string{"tie one", "tie two"}, newest[:2])
+		assert.Equal(t, []string{"Bravo", "charlie", "echo", "Alpha", "delta"}, newest[2:])
+
+		oldest := listAllItems(t, repos, group.ID, models.ItemListOptions{Sort: models.ItemSortOldest, Limit: 3})
+		assert.Equal(t, []string{"delta", "Alpha", "echo", "charlie", "Bravo"}, oldest[:5])
+		assert.Equal(t, newest[:2], []string{oldest[6], oldest[5]}, "ties break by ID in both directions")
+
+		byTitle := listAllItems(t, repos, group.ID, models.ItemListOptions{Sort: models.ItemSortTitle, Limit: 3})
+		assert.Equal(t, []string{"Alpha", "Bravo", "charlie", "delta", "echo", "tie one", "tie two"}, byTitle)
+
+		completed, notCompleted := true, false
+		assert.ElementsMatch(t, []string{"delta", "Alpha"},
+			listAllItems(t, repos, group.ID, models.ItemListOptions{Completed: &completed}))
+		assert.Len(t, listAllItems(t, repos, group.ID, models.ItemListOptions{Completed: &notCompleted}), 5)
+		assert.ElementsMatch(t, []string{"Alpha", "charlie"},
+			listAllItems(t, repos, group.ID, models.ItemListOptions{CreatedBy: other.ID}))
+
+		after, before := base.Add(time.Hour), base.Add(3*time.Hour)
+		assert.Equal(t, []string{"echo", "Alpha"},
+			listAllItems(t, repos, group.ID, models.ItemListOptions{CreatedAfter: &after, CreatedBefore: &before}))
+
+		empty, err := repos.BucketItems().List(ctx, uuid.New().String(), models.ItemListOptions{})
+		require.NoError(t, err)
+		assert.NotNil(t, empty.Items)
+		assert.Empty(t, empty.Items)
+		assert.Empty(t, empty.NextCursor)
+	})
+
+	t.Run("invalid cursors", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		for i := 0; i < 2; i++ {
+			require.NoError(t, repos.BucketItems().Create(ctx, createTestBucketItem(group.ID, creator.ID)))
+		}
+
+		page, err := repos.BucketItems().List(ctx, group.ID, models.ItemListOptions{Limit: 1})
+		require.NoError(t, err)
+		require.NotEmpty(t, page.NextCursor)
+
+		_, err = repos.BucketItems().List(ctx, group.ID, models.ItemListOptions{Sort: models.ItemSortTitle, Cursor: page.NextCursor})
+		assert.ErrorIs(t, err, ErrInvalidCursor, "cursors are tied to their sort")
+		_, err = repos.BucketItems().List(ctx, group.ID, models.ItemListOptions{Cursor: "not a cursor"})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+		_, err = repos.Groups().ListSummariesByUserID(ctx, group.CreatedBy, models.GroupSummaryListOptions{Cursor: "e30"})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+
+	t.Run("group summaries", func(t *testing.T) {
+		repos := newRepos(t)
+		userID := uuid.New().String()
+		future := time.Now().UTC().Truncate(time.Hour).AddDate(1, 0, 0)
+
+		// name, deadline offset in days (0 for none), items, completed items
+		specs := []struct {
+			name      string
+			deadline  int
+			items     int
+			completed int
+		}{
+			{"no deadline", 0, 2, 1},
+			{"far", 30, 4, 4},
+			{"soon", 3, 4, 1},
+			{"empty", 10, 0, 0},
+		}
+		for i, spec := range specs {
+			group := createTestGroup()
+			group.Name = spec.name
+			group.CreatedBy = userID
+			group.CreatedAt = base.Add(time.Duration(i) * time.Hour)
+			if spec.deadline > 0 {
+				deadline := future.AddDate(0, 0, spec.deadline)
+				group.Deadline = &deadline
+			}
+			require.NoError(t, repos.Groups().Create(ctx, group))
+
+			member := createTestMember(group.ID)
+			member.UserID = &userID
+			member.IsCreator = true
+			require.NoError(t, repos.Members().Create(ctx, member))
+
+			for j := 0; j < spec.items; j++ {
+				item := createTestBucketItem(group.ID, member.ID)
+				require.NoError(t, repos.BucketItems().Create(ctx, item))
+				if j < spec.completed {
+					require.NoError(t, repos.BucketItems().ToggleCompletion(ctx, item.ID, member.ID, true))
+				}
+			}
+		}
+		seedGroup(t, repos) // another user's group
+
+		listAll := func(t *testing.T, opts models.GroupSummaryListOptions) []string {
+			t.Helper()
+			var names []string
+			for pages := 0; ; pages++ {
+				require.Less(t, pages, 20, "pagination did not terminate")
+				page, err := repos.Groups().ListSummariesByUserID(ctx, userID, opts)
+				require.NoError(t, err)
+				for _, summary := range page.Groups {
+					names = append(names, summary.Name)
+				}
+				if page.NextCursor == "" {
+					return names
+				}
+				opts.Cursor = page.NextCursor
+			}
+		}
+
+		assert.Equal(t, []string{"empty", "soon", "far", "no deadline"}, listAll(t, models.GroupSummaryListOptions{Limit: 1}))
+		assert.Equal(t, []string{"soon", "empty", "far", "no deadline"},
+			listAll(t, models.GroupSummaryListOptions{Sort: models.GroupSortDeadline, Limit: 3}))
+		assert.Equal(t, []string{"far", "no deadline", "soon", "empty"},
+			listAll(t, models.GroupSummaryListOptions{Sort: models.GroupSortProgress, Limit: 1}))
+
+		minProgress, maxProgress := 25.0, 50.0
+		assert.Equal(t, []string{"soon", "no deadline"},
+			listAll(t, models.GroupSummaryListOptions{MinProgress: &minProgress, MaxProgress: &maxProgress}))
+
+		deadlineAfter, deadlineBefore := future.AddDate(0, 0, 5), future.AddDate(0, 0, 31)
+		assert.Equal(t, []string{"empty", "far"}, listAll(t, models.GroupSummaryListOptions{
+			Sort: models.GroupSortDeadline, DeadlineAfter: &deadlineAfter, DeadlineBefore: &deadlineBefore,
+		}))
+
+		page, err := repos.Groups().ListSummariesByUserID(ctx, userID, models.GroupSummaryListOptions{Sort: models.GroupSortProgress})
+		require.NoError(t, err)
+		require.Len(t, page.Groups, 4)
+		assert.Equal(t, 100.0, page.Groups[0].ProgressPercent)
+		assert.Equal(t, 4, page.Groups[0].ItemCount)
+		assert.Equal(t, 4, page.Groups[0].CompletedCount)
+		assert.Equal(t, 1, page.Groups[0].MemberCount)
+	})
+}
+
+func createTestTag(groupID, name string) *models.Tag {
+	return &models.Tag{
+		ID:        uuid.New().String(),
+		GroupID:   groupID,
+		Name:      name,
+		Color:     models.DefaultTagColor,
+		CreatedAt: time.Now(),
+	}
+}
+
+func testTagConformance(t *testing.T, newRepos newRepositoriesFunc) {
+	ctx := context.Background()
+
+	t.Run("create, get and update", func(t *testing.T) {
+		repos := newRepos(t)
+		group, _ := seedGroup(t, repos)
+
+		tag := createTestTag(group.ID, "  Travel  ")
+		tag.Color = "#1E90FF"
+		require.NoError(t, repos.Tags().Create(ctx, tag))
+
+		retrieved, err := repos.Tags().GetByID(ctx, tag.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Travel", retrieved.Name, "names are sanitized")
+		assert.Equal(t, "#1e90ff", retrieved.Color, "colors are lowercased")
+		assert.Equal(t, group.ID, retrieved.GroupID)
+
+		retrieved.Name = "Trips"
+		retrieved.Color = "#00ff00"
+		require.NoError(t, repos.Tags().Update(ctx, retrieved))
+		updated, err := repos.Tags().GetByID(ctx, tag.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Trips", updated.Name)
+		assert.Equal(t, "#00ff00", updated.Color)
+
+		_, err = repos.Tags().GetByID(ctx, uuid.New().String())
+		assert.ErrorIs(t, err, ErrTagNotFound)
+		assert.ErrorIs(t, repos.Tags().Update(ctx, createTestTag(group.ID, "missing")), ErrTagNotFound)
+		assert.ErrorIs(t, repos.Tags().Delete(ctx, uuid.New().String()), ErrTagNotFound)
+	})
+
+	t.Run("names are unique per group ignoring case", func(t *testing.T) {
+		repos := newRepos(t)
+		group, _ := seedGroup(t, repos)
+		otherGroup, _ := seedGroup(t, repos)
+
+		food := createTestTag(group.ID, "Food")
+		require.NoError(t, repos.Tags().Create(ctx, food))
+		assert.ErrorIs(t, repos.Tags().Create(ctx, createTestTag(group.ID, "FOOD")), ErrDuplicateTagName)
+		assert.NoError(t, repos.Tags().Create(ctx, createTestTag(otherGroup.ID, "food")))
+
+		travel := createTestTag(group.ID, "Travel")
+		require.NoError(t, repos.Tags().Create(ctx, travel))
+		travel.Name = "food"
+		assert.ErrorIs(t, repos.Tags().Update(ctx, travel), ErrDuplicateTagName)
+
+		food.Name = "FOOD"
+		assert.NoError(t, repos.Tags().Update(ctx, food), "a tag can change the case of its own name")
+
+		assert.ErrorIs(t, repos.Tags().Create(ctx, createTestTag(uuid.New().String(), "orphan")), ErrGroupNotFound)
+	})
+
+	t.Run("item tagging", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		otherGroup, _ := seedGroup(t, repos)
+
+		food := createTestTag(group.ID, "food")
+		adventure := createTestTag(group.ID, "Adventure")
+		foreign := createTestTag(otherGroup.ID, "foreign")
+		for _, tag := range []*models.Tag{food, adventure, foreign} {
+			require.NoError(t, repos.Tags().Create(ctx, tag))
+		}
+
+		tags, err := repos.Tags().GetByGroupID(ctx, group.ID)
+		require.NoError(t, err)
+		require.Len(t, tags, 2)
+		assert.Equal(t, "Adventure", tags[0].Name, "tags are ordered by name ignoring case")
+		assert.Equal(t, "food", tags[1].Name)
+
+		item := createTestBucketItem(group.ID, creator.ID)
+		item.TagIDs = []string{food.ID}
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+		untagged := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, untagged))
+
+		retrieved, err := repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Empty(t, retrieved.TagIDs, "items are tagged with SetItemTags only")
+
+		// Duplicates are ignored and tags come back in ID order
+		require.NoError(t, repos.Tags().SetItemTags(ctx, item.ID, []string{food.ID, adventure.ID, food.ID}))
+		expected := []string{food.ID, adventure.ID}
+		sort.Strings(expected)
+
+		retrieved, err = repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, retrieved.TagIDs)
+
+		// Updating an item keeps its tags
+		retrieved.Title = "Renamed"
+		require.NoError(t, repos.BucketItems().Update(ctx, retrieved))
+		retrieved, err = repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, retrieved.TagIDs)
+
+		items, err := repos.BucketItems().GetByGroupID(ctx, group.ID)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		for _, listed := range items {
+			if listed.ID == item.ID {
+				assert.Equal(t, expected, listed.TagIDs)
+			} else {
+				assert.Empty(t, listed.TagIDs)
+			}
+		}
+
+		details, err := repos.Groups().GetWithDetails(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Len(t, details.Tags, 2)
+		for _, listed := range details.Items {
+			if listed.ID == item.ID {
+				assert.Equal(t, expected, listed.TagIDs)
+			}
+		}
+
+		page, err := repos.BucketItems().List(ctx, group.ID, models.ItemListOptions{TagID: adventure.ID})
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		assert.Equal(t, item.ID, page.Items[0].ID)
+		assert.Equal(t, expected, page.Items[0].TagIDs)
+
+		results, err := repos.BucketItems().Search(ctx, group.ID, "renamed", 10)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, expected, results[0].TagIDs)
+
+		// A tag of another group is rejected and the item keeps its tags
+		err = repos.Tags().SetItemTags(ctx, item.ID, []string{food.ID, foreign.ID})
+		assert.ErrorIs(t, err, ErrTagNotFound)
+		err = repos.Tags().SetItemTags(ctx, item.ID, []string{uuid.New().String()})
+		assert.ErrorIs(t, err, ErrTagNotFound)
+		retrieved, err = repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, retrieved.TagIDs)
+
+		err = repos.Tags().SetItemTags(ctx, uuid.New().String(), []string{food.ID})
+		assert.ErrorIs(t, err, ErrItemNotFound)
+
+		// An empty list clears the tags
+		require.NoError(t, repos.Tags().SetItemTags(ctx, item.ID, nil))
+		retrieved, err = repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Empty(t, retrieved.TagIDs)
+	})
+
+	t.Run("stats by tag", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		otherGroup, otherCreator := seedGroup(t, repos)
+
+		food := createTestTag(group.ID, "food")
+		travel := createTestTag(group.ID, "travel")
+		unused := createTestTag(group.ID, "unused")
+		foreign := createTestTag(otherGroup.ID, "food")
+		for _, tag := range []*models.Tag{food, travel, unused, foreign} {
+			require.NoError(t, repos.Tags().Create(ctx, tag))
+		}
+
+		// food: 3 items, 2 completed; travel: 1 item, 0 completed
+		for i := 0; i < 3; i++ {
+			item := createTestBucketItem(group.ID, creator.ID)
+			require.NoError(t, repos.BucketItems().Create(ctx, item))
+			tagIDs := []string{food.ID}
+			if i == 2 {
+				tagIDs = append(tagIDs, travel.ID)
+			}
+			require.NoError(t, repos.Tags().SetItemTags(ctx, item.ID, tagIDs))
+			if i < 2 {
+				require.NoError(t, repos.BucketItems().ToggleCompletion(ctx, item.ID, creator.ID, true))
+			}
+		}
+		otherItem := createTestBucketItem(otherGroup.ID, otherCreator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, otherItem))
+		require.NoError(t, repos.Tags().SetItemTags(ctx, otherItem.ID, []string{foreign.ID}))
+
+		stats, err := repos.BucketItems().GetCompletionStatsByTag(ctx, group.ID)
+		require.NoError(t, err)
+		require.Len(t, stats, 3)
+
+		assert.Equal(t, food.ID, stats[0].ID)
+		assert.Equal(t, 3, stats[0].ItemCount)
+		assert.Equal(t, 2, stats[0].CompletedCount)
+		assert.InDelta(t, 200.0/3, stats[0].ProgressPercent, 0.001)
+
+		assert.Equal(t, travel.ID, stats[1].ID)
+		assert.Equal(t, 1, stats[1].ItemCount)
+		assert.Equal(t, 0, stats[1].CompletedCount)
+		assert.Equal(t, 0.0, stats[1].ProgressPercent)
+
+		assert.Equal(t, "unused", stats[2].Name)
+		assert.Equal(t, 0, stats[2].ItemCount)
+
+		empty, err := repos.BucketItems().GetCompletionStatsByTag(ctx, uuid.New().String())
+		require.NoError(t, err)
+		assert.Empty(t, empty)
+	})
+
+	t.Run("deletes cascade", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+
+		food := createTestTag(group.ID, "food")
+		travel := createTestTag(group.ID, "travel")
+		require.NoError(t, repos.Tags().Create(ctx, food))
+		require.NoError(t, repos.Tags().Create(ctx, travel))
+
+		item := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+		require.NoError(t, repos.Tags().SetItemTags(ctx, item.ID, []string{food.ID, travel.ID}))
+
+		require.NoError(t, repos.Tags().Delete(ctx, food.ID))
+		retrieved, err := repos.BucketItems().GetByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{travel.ID}, retrieved.TagIDs, "deleting a tag removes it from items")
+
+		require.NoError(t, repos.BucketItems().Delete(ctx, item.ID))
+		stats, err := repos.BucketItems().GetCompletionStatsByTag(ctx, group.ID)
+		require.NoError(t, err)
+		require.Len(t, stats, 1)
+		assert.Equal(t, 0, stats[0].ItemCount, "deleting an item removes its taggings")
+
+		require.NoError(t, repos.Groups().Delete(ctx, group.ID))
+		_, err = repos.Tags().GetByID(ctx, travel.ID)
+		assert.ErrorIs(t, err, ErrTagNotFound, "deleting a group deletes its tags")
+	})
+}
+
+func createTestComment(itemID, memberID string, parentID *string) *models.Comment {
+	return &models.Comment{
+		ID:        uuid.New().String(),
+		ItemID:    itemID,
+		ParentID:  parentID,
+		MemberID:  memberID,
+		Body:      "Which weekend works for everyone?",
+		CreatedAt: time.Now(),
+	}
+}
+
+func testCommentConformance(t *testing.T, newRepos newRepositoriesFunc) {
+	ctx := context.Background()
+
+	t.Run("create, get and update", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+
+		item := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+
+		comment := createTestComment(item.ID, creator.ID, nil)
+		comment.Body = "  I'll   book it.\r\n\n\n\nSee you there  "
+		require.NoError(t, repos.Comments().Create(ctx, comment))
+		assert.Equal(t, group.ID, comment.GroupID, "the group is taken from the item")
+
+		retrieved, err := repos.Comments().GetByID(ctx, comment.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "I'll book it.\n\nSee you there", retrieved.Body, "bodies are sanitized but keep line breaks")
+		assert.Equal(t, group.ID, retrieved.GroupID)
+		assert.Equal(t, creator.ID, retrieved.MemberID)
+		assert.Nil(t, retrieved.ParentID)
+		assert.Nil(t, retrieved.EditedAt)
+
+		retrieved.Body = "Booked!"
+		require.NoError(t, repos.Comments().Update(ctx, retrieved))
+		require.NotNil(t, retrieved.EditedAt)
+
+		updated, err := repos.Comments().GetByID(ctx, comment.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Booked!", updated.Body)
+		require.NotNil(t, updated.EditedAt)
+		assert.WithinDuration(t, *retrieved.EditedAt, *updated.EditedAt, time.Millisecond)
+
+		_, err = repos.Comments().GetByID(ctx, uuid.New().String())
+		assert.ErrorIs(t, err, ErrCommentNotFound)
+		assert.ErrorIs(t, repos.Comments().Update(ctx, createTestComment(item.ID, creator.ID, nil)), ErrCommentNotFound)
+		assert.ErrorIs(t, repos.Comments().Delete(ctx, uuid.New().String()), ErrCommentNotFound)
+
+		err = repos.Comments().Create(ctx, createTestComment(uuid.New().String(), creator.ID, nil))
+		assert.ErrorIs(t, err, ErrItemNotFound)
+		err = repos.Comments().Create(ctx, createTestComment(item.ID, uuid.New().String(), nil))
+		assert.ErrorIs(t, err, ErrMemberNotFound)
+	})
+
+	t.Run("threads", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+
+		item := createTestBucketItem(group.ID, creator.ID)
+		otherItem := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+		require.NoError(t, repos.BucketItems().Create(ctx, otherItem))
+
+		base := time.Now().Add(-time.Hour)
+		first := createTestComment(item.ID, creator.ID, nil)
+		first.CreatedAt = base
+		second := createTestComment(item.ID, creator.ID, nil)
+		second.CreatedAt = base.Add(time.Minute)
+		reply := createTestComment(item.ID, creator.ID, &first.ID)
+		reply.CreatedAt = base.Add(2 * time.Minute)
+		laterReply := createTestComment(item.ID, creator.ID, &first.ID)
+		laterReply.CreatedAt = base.Add(3 * time.Minute)
+		// Created out of order, so the order comes from the timestamps
+		for _, comment := range []*models.Comment{second, first, laterReply, reply} {
+			require.NoError(t, repos.Comments().Create(ctx, comment))
+		}
+
+		threads, err := repos.Comments().GetByItemID(ctx, item.ID)
+		require.NoError(t, err)
+		require.Len(t, threads, 2)
+		assert.Equal(t, first.ID, threads[0].ID, "threads are ordered oldest first")
+		require.Len(t, threads[0].Replies, 2)
+		assert.Equal(t, reply.ID, threads[0].Replies[0].ID, "replies are ordered oldest first")
+		assert.Equal(t, laterReply.ID, threads[0].Replies[1].ID)
+		require.NotNil(t, threads[0].Replies[0].ParentID)
+		assert.Equal(t, first.ID, *threads[0].Replies[0].ParentID)
+		assert.Equal(t, second.ID, threads[1].ID)
+		assert.NotNil(t, threads[1].Replies)
+		assert.Empty(t, threads[1].Replies)
+
+		// Replies cannot nest and must stay on the parent's item
+		err = repos.Comments().Create(ctx, createTestComment(item.ID, creator.ID, &reply.ID))
+		assert.ErrorIs(t, err, ErrInvalidReply)
+		err = repos.Comments().Create(ctx, createTestComment(otherItem.ID, creator.ID, &first.ID))
+		assert.ErrorIs(t, err, ErrInvalidReply)
+		missing := uuid.New().String()
+		err = repos.Comments().Create(ctx, createTestComment(item.ID, creator.ID, &missing))
+		assert.ErrorIs(t, err, ErrCommentNotFound)
+
+		none, err := repos.Comments().GetByItemID(ctx, otherItem.ID)
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+
+	t.Run("counts", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		otherGroup, otherCreator := seedGroup(t, repos)
+
+		item := createTestBucketItem(group.ID, creator.ID)
+		quiet := createTestBucketItem(group.ID, creator.ID)
+		otherItem := createTestBucketItem(otherGroup.ID, otherCreator.ID)
+		for _, it := range []*models.BucketListItem{item, quiet, otherItem} {
+			require.NoError(t, repos.BucketItems().Create(ctx, it))
+		}
+
+		parent := createTestComment(item.ID, creator.ID, nil)
+		require.NoError(t, repos.Comments().Create(ctx, parent))
+		require.NoError(t, repos.Comments().Create(ctx, createTestComment(item.ID, creator.ID, &parent.ID)))
+		require.NoError(t, repos.Comments().Create(ctx, createTestComment(item.ID, creator.ID, nil)))
+		require.NoError(t, repos.Comments().Create(ctx, createTestComment(otherItem.ID, otherCreator.ID, nil)))
+
+		counts, err := repos.Comments().CountByGroupID(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{item.ID: 3}, counts)
+
+		details, err := repos.Groups().GetWithDetails(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{item.ID: 3, quiet.ID: 0}, details.CommentCounts,
+			"every item of the group has a count, replies included")
+	})
+
+	t.Run("deletes cascade", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+
+		member := createTestMember(group.ID)
+		require.NoError(t, repos.Members().Create(ctx, member))
+
+		item := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+
+		parent := createTestComment(item.ID, creator.ID, nil)
+		require.NoError(t, repos.Comments().Create(ctx, parent))
+		reply := createTestComment(item.ID, creator.ID, &parent.ID)
+		require.NoError(t, repos.Comments().Create(ctx, reply))
+
+		require.NoError(t, repos.Comments().Delete(ctx, parent.ID))
+		_, err := repos.Comments().GetByID(ctx, reply.ID)
+		assert.ErrorIs(t, err, ErrCommentNotFound, "deleting a comment deletes its replies")
+
+		byMember := createTestComment(item.ID, member.ID, nil)
+		require.NoError(t, repos.Comments().Create(ctx, byMember))
+		require.NoError(t, repos.Members().Delete(ctx, member.ID))
+		_, err = repos.Comments().GetByID(ctx, byMember.ID)
+		assert.ErrorIs(t, err, ErrCommentNotFound, "deleting a member deletes their comments")
+
+		onItem := createTestComment(item.ID, creator.ID, nil)
+		require.NoError(t, repos.Comments().Create(ctx, onItem))
+		require.NoError(t, repos.BucketItems().Delete(ctx, item.ID))
+		_, err = repos.Comments().GetByID(ctx, onItem.ID)
+		assert.ErrorIs(t, err, ErrCommentNotFound, "deleting an item deletes its comments")
+
+		otherItem := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, otherItem))
+		inGroup := createTestComment(otherItem.ID, creator.ID, nil)
+		require.NoError(t, repos.Comments().Create(ctx, inGroup))
+		require.NoError(t, repos.Groups().Delete(ctx, group.ID))
+		_, err = repos.Comments().GetByID(ctx, inGroup.ID)
+		assert.ErrorIs(t, err, ErrCommentNotFound, "deleting a group deletes its comments")
+	})
+
+	t.Run("rolled back with the transaction", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+
+		item := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+
+		comment := createTestComment(item.ID, creator.ID, nil)
+		err := repos.WithTx(ctx, func(txRepos RepositoryManager) error {
+			require.NoError(t, txRepos.Comments().Create(ctx, comment))
+			return errors.New("abort")
+		})
+		require.Error(t, err)
+
+		_, err = repos.Comments().GetByID(ctx, comment.ID)
+		assert.ErrorIs(t, err, ErrCommentNotFound)
+	})
+}
+
+func createTestReaction(itemID, memberID, emoji string) *models.Reaction {
+	return &models.Reaction{
+		ItemID:    itemID,
+		MemberID:  memberID,
+		Emoji:     emoji,
+		CreatedAt: time.Now(),
+	}
+}
+
+func testReactionConformance(t *testing.T, newRepos newRepositoriesFunc) {
+	ctx := context.Background()
+
+	t.Run("votes", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		member := createTestMember(group.ID)
+		require.NoError(t, repos.Members().Create(ctx, member))
+
+		item := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+
+		// Voting twice still counts once
+		require.NoError(t, repos.Reactions().SetVote(ctx, item.ID, creator.ID, true))
+		require.NoError(t, repos.Reactions().SetVote(ctx, item.ID, creator.ID, true))
+		require.NoError(t, repos.Reactions().SetVote(ctx, item.ID, member.ID, true))
+
+		reactions, err := repos.Reactions().GetByItemID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, item.ID, reactions.ItemID)
+		assert.Equal(t, 2, reactions.Votes)
+		assert.ElementsMatch(t, []string{creator.ID, member.ID}, reactions.VoterIDs)
+
+		// Withdrawing a vote that was never cast is a no-op
+		require.NoError(t, repos.Reactions().SetVote(ctx, item.ID, creator.ID, false))
+		require.NoError(t, repos.Reactions().SetVote(ctx, item.ID, creator.ID, false))
+		reactions, err = repos.Reactions().GetByItemID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, reactions.Votes)
+		assert.Equal(t, []string{member.ID}, reactions.VoterIDs)
+
+		err = repos.Reactions().SetVote(ctx, uuid.New().String(), creator.ID, true)
+		assert.ErrorIs(t, err, ErrItemNotFound)
+		err = repos.Reactions().SetVote(ctx, item.ID, uuid.New().String(), true)
+		assert.ErrorIs(t, err, ErrMemberNotFound)
+
+		none, err := repos.Reactions().GetByItemID(ctx, uuid.New().String())
+		require.NoError(t, err)
+		assert.Zero(t, none.Votes)
+		assert.NotNil(t, none.VoterIDs)
+		assert.NotNil(t, none.Reactions)
+	})
+
+	t.Run("reactions", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		member := createTestMember(group.ID)
+		require.NoError(t, repos.Members().Create(ctx, member))
+
+		item := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+
+		base := time.Now().Add(-time.Hour)
+		heart := createTestReaction(item.ID, member.ID, "❤️")
+		heart.CreatedAt = base
+		party := createTestReaction(item.ID, creator.ID, " 🎉 ")
+		party.CreatedAt = base.Add(time.Minute)
+		creatorHeart := createTestReaction(item.ID, creator.ID, "❤️")
+		creatorHeart.CreatedAt = base.Add(2 * time.Minute)
+		// Created out of order, so the order comes from the timestamps
+		for _, reaction := range []*models.Reaction{creatorHeart, party, heart} {
+			require.NoError(t, repos.Reactions().AddReaction(ctx, reaction))
+		}
+		// Reacting again with the same emoji is a no-op
+		require.NoError(t, repos.Reactions().AddReaction(ctx, createTestReaction(item.ID, member.ID, "❤️")))
+
+		reactions, err := repos.Reactions().GetByItemID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []models.ReactionCount{
+			{Emoji: "❤️", Count: 2, MemberIDs: []string{member.ID, creator.ID}},
+			{Emoji: "🎉", Count: 1, MemberIDs: []string{creator.ID}},
+		}, reactions.Reactions, "emoji are ordered by first use and trimmed")
+
+		require.NoError(t, repos.Reactions().RemoveReaction(ctx, item.ID, member.ID, "❤️"))
+		require.NoError(t, repos.Reactions().RemoveReaction(ctx, item.ID, member.ID, "🎉"))
+		reactions, err = repos.Reactions().GetByItemID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []models.ReactionCount{
+			{Emoji: "🎉", Count: 1, MemberIDs: []string{creator.ID}},
+			{Emoji: "❤️", Count: 1, MemberIDs: []string{creator.ID}},
+		}, reactions.Reactions)
+
+		err = repos.Reactions().AddReaction(ctx, createTestReaction(item.ID, creator.ID, "yes"))
+		assert.Error(t, err)
+		err = repos.Reactions().AddReaction(ctx, createTestReaction(uuid.New().String(), creator.ID, "👍"))
+		assert.ErrorIs(t, err, ErrItemNotFound)
+		err = repos.Reactions().AddReaction(ctx, createTestReaction(item.ID, uuid.New().String(), "👍"))
+		assert.ErrorIs(t, err, ErrMemberNotFound)
+	})
+
+	t.Run("group details", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		otherGroup, otherCreator := seedGroup(t, repos)
+
+		item := createTestBucketItem(group.ID, creator.ID)
+		quiet := createTestBucketItem(group.ID, creator.ID)
+		otherItem := createTestBucketItem(otherGroup.ID, otherCreator.ID)
+		for _, it := range []*models.BucketListItem{item, quiet, otherItem} {
+			require.NoError(t, repos.BucketItems().Create(ctx, it))
+		}
+
+		require.NoError(t, repos.Reactions().SetVote(ctx, item.ID, creator.ID, true))
+		require.NoError(t, repos.Reactions().AddReaction(ctx, createTestReaction(item.ID, creator.ID, "👍")))
+		require.NoError(t, repos.Reactions().SetVote(ctx, otherItem.ID, otherCreator.ID, true))
+
+		byGroup, err := repos.Reactions().GetByGroupID(ctx, group.ID)
+		require.NoError(t, err)
+		require.Len(t, byGroup, 1)
+		assert.Equal(t, 1, byGroup[item.ID].Votes)
+
+		details, err := repos.Groups().GetWithDetails(ctx, group.ID)
+		require.NoError(t, err)
+		require.Len(t, details.Reactions, 2, "every item of the group has reactions")
+		assert.Equal(t, models.ItemReactions{
+			ItemID:    item.ID,
+			Votes:     1,
+			VoterIDs:  []string{creator.ID},
+			Reactions: []models.ReactionCount{{Emoji: "👍", Count: 1, MemberIDs: []string{creator.ID}}},
+		}, details.Reactions[item.ID])
+		assert.Equal(t, models.ItemReactions{
+			ItemID:    quiet.ID,
+			VoterIDs:  []string{},
+			Reactions: []models.ReactionCount{},
+		}, details.Reactions[quiet.ID])
+	})
+
+	t.Run("most wanted", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		voters := []*models.Member{creator}
+		for i := 0; i < 2; i++ {
+			member := createTestMember(group.ID)
+			require.NoError(t, repos.Members().Create(ctx, member))
+			voters = append(voters, member)
+		}
+
+		// Titles are the number of votes each item gets
+		for _, votes := range []int{1, 0, 3, 2, 1} {
+			item := createTestBucketItem(group.ID, creator.ID)
+			item.Title = fmt.Sprintf("%d votes", votes)
+			require.NoError(t, repos.BucketItems().Create(ctx, item))
+			for _, voter := range voters[:votes] {
+				require.NoError(t, repos.Reactions().SetVote(ctx, item.ID, voter.ID, true))
+			}
+		}
+
+		var titles []string
+		opts := models.ItemListOptions{Sort: models.ItemSortMostWanted, Limit: 2}
+		for pages := 0; ; pages++ {
+			require.Less(t, pages, 10, "pagination did not terminate")
+			page, err := repos.BucketItems().List(ctx, group.ID, opts)
+			require.NoError(t, err)
+			for _, item := range page.Items {
+				titles = append(titles, item.Title)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			opts.Cursor = page.NextCursor
+		}
+		assert.Equal(t, []string{"3 votes", "2 votes", "1 votes", "1 votes", "0 votes"}, titles)
+
+		// Deleting a member withdraws their votes
+		require.NoError(t, repos.Members().Delete(ctx, voters[1].ID))
+		page, err := repos.BucketItems().List(ctx, group.ID, models.ItemListOptions{Sort: models.ItemSortMostWanted, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, page.Items, 2)
+		assert.Equal(t, "3 votes", page.Items[0].Title, "down to two votes")
+		assert.NotEqual(t, "0 votes", page.Items[1].Title)
+	})
+
+	t.Run("deletes cascade", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		member := createTestMember(group.ID)
+		require.NoError(t, repos.Members().Create(ctx, member))
+
+		item := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+		for _, m := range []*models.Member{creator, member} {
+			require.NoError(t, repos.Reactions().SetVote(ctx, item.ID, m.ID, true))
+			require.NoError(t, repos.Reactions().AddReaction(ctx, createTestReaction(item.ID, m.ID, "🔥")))
+		}
+
+		require.NoError(t, repos.Members().Delete(ctx, member.ID))
+		reactions, err := repos.Reactions().GetByItemID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{creator.ID}, reactions.VoterIDs, "deleting a member deletes their votes")
+		require.Len(t, reactions.Reactions, 1)
+		assert.Equal(t, []string{creator.ID}, reactions.Reactions[0].MemberIDs, "deleting a member deletes their reactions")
+
+		require.NoError(t, repos.BucketItems().Delete(ctx, item.ID))
+		byGroup, err := repos.Reactions().GetByGroupID(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Empty(t, byGroup, "deleting an item deletes its votes and reactions")
+	})
+
+	t.Run("rolled back with the transaction", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+
+		item := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+
+		err := repos.WithTx(ctx, func(txRepos RepositoryManager) error {
+			require.NoError(t, txRepos.Reactions().SetVote(ctx, item.ID, creator.ID, true))
+			require.NoError(t, txRepos.Reactions().AddReaction(ctx, createTestReaction(item.ID, creator.ID, "👍")))
+			return errors.New("abort")
+		})
+		require.Error(t, err)
+
+		reactions, err := repos.Reactions().GetByItemID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Zero(t, reactions.Votes)
+		assert.Empty(t, reactions.Reactions)
+	})
+}
+
+func createTestAttachment(item *models.BucketListItem, memberID string) *models.Attachment {
+	id := uuid.New().String()
+	return &models.Attachment{
+		ID:          id,
+		ItemID:      item.ID,
+		MemberID:    memberID,
+		FileName:    "photo.jpg",
+		ContentType: "image/jpeg",
+		Size:        1024,
+		StorageKey:  models.AttachmentStorageKey(item.GroupID, item.ID, id),
+		CreatedAt:   time.Now(),
+	}
+}
+
+func testAttachmentConformance(t *testing.T, newRepos newRepositoriesFunc) {
+	ctx := context.Background()
+
+	t.Run("create, get and list", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+
+		item := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+
+		first := createTestAttachment(item, creator.ID)
+		first.FileName = `C:\photos\beach day.jpg`
+		first.CreatedAt = time.Now().Add(-time.Minute)
+		second := createTestAttachment(item, creator.ID)
+		second.ContentType = "application/pdf"
+		second.FileName = "tickets.pdf"
+		// Created out of order, so the order comes from the timestamps
+		require.NoError(t, repos.Attachments().Create(ctx, second))
+		require.NoError(t, repos.Attachments().Create(ctx, first))
+		assert.Equal(t, group.ID, first.GroupID, "the group comes from the item")
+		assert.Equal(t, "beach day.jpg", first.FileName, "file names are reduced to their base name")
+
+		got, err := repos.Attachments().GetByID(ctx, first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, first.ItemID, got.ItemID)
+		assert.Equal(t, group.ID, got.GroupID)
+		assert.Equal(t, creator.ID, got.MemberID)
+		assert.Equal(t, "beach day.jpg", got.FileName)
+		assert.Equal(t, "image/jpeg", got.ContentType)
+		assert.Equal(t, int64(1024), got.Size)
+		assert.Equal(t, first.StorageKey, got.StorageKey)
+		assert.WithinDuration(t, first.CreatedAt, got.CreatedAt, time.Millisecond)
+
+		list, err := repos.Attachments().GetByItemID(ctx, item.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, first.ID, list[0].ID)
+		assert.Equal(t, second.ID, list[1].ID)
+
+		none, err := repos.Attachments().GetByItemID(ctx, uuid.New().String())
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+
+	t.Run("constraint violations", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+
+		item := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+
+		_, err := repos.Attachments().GetByID(ctx, uuid.New().String())
+		assert.ErrorIs(t, err, ErrAttachmentNotFound)
+		assert.ErrorIs(t, repos.Attachments().Delete(ctx, uuid.New().String()), ErrAttachmentNotFound)
+
+		missingItem := createTestAttachment(item, creator.ID)
+		missingItem.ItemID = uuid.New().String()
+		assert.ErrorIs(t, repos.Attachments().Create(ctx, missingItem), ErrItemNotFound)
+
+		missingMember := createTestAttachment(item, uuid.New().String())
+		assert.ErrorIs(t, repos.Attachments().Create(ctx, missingMember), ErrMemberNotFound)
+
+		attachment := createTestAttachment(item, creator.ID)
+		require.NoError(t, repos.Attachments().Create(ctx, attachment))
+		duplicate := createTestAttachment(item, creator.ID)
+		duplicate.ID = attachment.ID
+		assert.ErrorIs(t, repos.Attachments().Create(ctx, duplicate), ErrDuplicateID)
+
+		invalid := createTestAttachment(item, creator.ID)
+		invalid.ContentType = "text/html"
+		assert.Error(t, repos.Attachments().Create(ctx, invalid))
+	})
+
+	t.Run("deletes queue blob deletions", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+		member := createTestMember(group.ID)
+		require.NoError(t, repos.Members().Create(ctx, member))
+
+		item := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+		otherItem := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, otherItem))
+
+		direct := createTestAttachment(item, creator.ID)
+		byMember := createTestAttachment(item, member.ID)
+		onItem := createTestAttachment(item, creator.ID)
+		inGroup := createTestAttachment(otherItem, creator.ID)
+		for _, attachment := range []*models.Attachment{direct, byMember, onItem, inGroup} {
+			require.NoError(t, repos.Attachments().Create(ctx, attachment))
+		}
+
+		pending, err := repos.Attachments().PendingBlobDeletions(ctx, 10)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+
+		require.NoError(t, repos.Attachments().Delete(ctx, direct.ID))
+		require.NoError(t, repos.Members().Delete(ctx, member.ID))
+		_, err = repos.Attachments().GetByID(ctx, byMember.ID)
+		assert.ErrorIs(t, err, ErrAttachmentNotFound, "deleting a member deletes their attachments")
+
+		require.NoError(t, repos.BucketItems().Delete(ctx, item.ID))
+		_, err = repos.Attachments().GetByID(ctx, onItem.ID)
+		assert.ErrorIs(t, err, ErrAttachmentNotFound, "deleting an item deletes its attachments")
+
+		require.NoError(t, repos.Groups().Delete(ctx, group.ID))
+		_, err = repos.Attachments().GetByID(ctx, inGroup.ID)
+		assert.ErrorIs(t, err, ErrAttachmentNotFound, "deleting a group deletes its attachments")
+
+		pending, err = repos.Attachments().PendingBlobDeletions(ctx, 10)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{direct.StorageKey, byMember.StorageKey, onItem.StorageKey, inGroup.StorageKey},
+			pending, "every delete queues the blob")
+
+		limited, err := repos.Attachments().PendingBlobDeletions(ctx, 2)
+		require.NoError(t, err)
+		assert.Len(t, limited, 2)
+
+		require.NoError(t, repos.Attachments().ClearBlobDeletions(ctx, limited))
+		require.NoError(t, repos.Attachments().ClearBlobDeletions(ctx, nil))
+		pending, err = repos.Attachments().PendingBlobDeletions(ctx, 10)
+		require.NoError(t, err)
+		assert.Len(t, pending, 2)
+		assert.NotContains(t, pending, limited[0])
+		assert.NotContains(t, pending, limited[1])
+	})
+
+	t.Run("processing", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+
+		item := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+		bare := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, bare))
+
+		photo := createTestAttachment(item, creator.ID)
+		photo.CreatedAt = time.Now().Add(-time.Minute)
+		pdf := createTestAttachment(item, creator.ID)
+		pdf.ContentType = "application/pdf"
+		require.NoError(t, repos.Attachments().Create(ctx, photo))
+		require.NoError(t, repos.Attachments().Create(ctx, pdf))
+		assert.Equal(t, models.AttachmentPending, photo.ProcessingStatus, "new attachments are pending")
+
+		pending, err := repos.Attachments().GetPendingProcessing(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 2)
+		assert.Equal(t, photo.ID, pending[0].ID, "oldest first")
+		assert.Nil(t, pending[0].Width)
+		assert.Nil(t, pending[0].Height)
+
+		limited, err := repos.Attachments().GetPendingProcessing(ctx, 1)
+		require.NoError(t, err)
+		assert.Len(t, limited, 1)
+
+		width, height := 1600, 1200
+		photo.Width, photo.Height = &width, &height
+		photo.Size = 900
+		photo.ProcessingStatus = models.AttachmentProcessed
+		require.NoError(t, repos.Attachments().UpdateProcessing(ctx, photo))
+		pdf.ProcessingStatus = models.AttachmentFailed
+		require.NoError(t, repos.Attachments().UpdateProcessing(ctx, pdf))
+
+		got, err := repos.Attachments().GetByID(ctx, photo.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.AttachmentProcessed, got.ProcessingStatus)
+		assert.Equal(t, int64(900), got.Size)
+		require.NotNil(t, got.Width)
+		require.NotNil(t, got.Height)
+		assert.Equal(t, 1600, *got.Width)
+		assert.Equal(t, 1200, *got.Height)
+
+		pending, err = repos.Attachments().GetPendingProcessing(ctx, 10)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+
+		missing := createTestAttachment(item, creator.ID)
+		assert.ErrorIs(t, repos.Attachments().UpdateProcessing(ctx, missing), ErrAttachmentNotFound)
+		photo.ProcessingStatus = "done"
+		assert.Error(t, repos.Attachments().UpdateProcessing(ctx, photo))
+
+		// Group details list the attachments of every item
+		byItem, err := repos.Attachments().GetByGroupID(ctx, group.ID)
+		require.NoError(t, err)
+		require.Len(t, byItem[item.ID], 2)
+		assert.Equal(t, photo.ID, byItem[item.ID][0].ID)
+		assert.NotContains(t, byItem, bare.ID)
+
+		details, err := repos.Groups().GetWithDetails(ctx, group.ID)
+		require.NoError(t, err)
+		require.Len(t, details.Attachments[item.ID], 2)
+		assert.Equal(t, photo.ID, details.Attachments[item.ID][0].ID)
+		assert.Equal(t, 1600, *details.Attachments[item.ID][0].Width)
+		assert.Equal(t, models.AttachmentFailed, details.Attachments[item.ID][1].ProcessingStatus)
+		assert.NotNil(t, details.Attachments[bare.ID])
+		assert.Empty(t, details.Attachments[bare.ID])
+	})
+
+	t.Run("rolled back with the transaction", func(t *testing.T) {
+		repos := newRepos(t)
+		group, creator := seedGroup(t, repos)
+
+		item := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+		kept := createTestAttachment(item, creator.ID)
+		require.NoError(t, repos.Attachments().Create(ctx, kept))
+
+		added := createTestAttachment(item, creator.ID)
+		err := repos.WithTx(ctx, func(txRepos RepositoryManager) error {
+			require.NoError(t, txRepos.Attachments().Create(ctx, added))
+			require.NoError(t, txRepos.Attachments().Delete(ctx, kept.ID))
+			return errors.New("abort")
+		})
+		require.Error(t, err)
+
+		_, err = repos.Attachments().GetByID(ctx, added.ID)
+		assert.ErrorIs(t, err, ErrAttachmentNotFound)
+		_, err = repos.Attachments().GetByID(ctx, kept.ID)
+		assert.NoError(t, err)
+
+		pending, err := repos.Attachments().PendingBlobDeletions(ctx, 10)
+		require.NoError(t, err)
+		assert.Empty(t, pending, "a rolled back delete queues nothing")
+	})
+}
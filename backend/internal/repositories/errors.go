@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// Domain errors returned by repositories. Not-found errors are wrapped with
+// the ID that was looked up, so use errors.Is to test for them.
+var (
+	ErrGroupNotFound      = errors.New("group not found")
+	ErrMemberNotFound     = errors.New("member not found")
+	ErrItemNotFound       = errors.New("bucket item not found")
+	ErrTagNotFound        = errors.New("tag not found")
+	ErrCommentNotFound    = errors.New("comment not found")
+	ErrAttachmentNotFound = errors.New("attachment not found")
+
+	// ErrItemNotCompleted is returned when recording the journal of an item
+	// that is not completed
+	ErrItemNotCompleted = errors.New("bucket item is not completed")
+
+	// ErrAlreadyMember is returned when a user joins a group they belong to
+	ErrAlreadyMember = errors.New("user is already a member of this group")
+
+	// ErrDuplicateTagName is returned when a group already has a tag with
+	// the same name, ignoring case
+	ErrDuplicateTagName = errors.New("a tag with this name already exists in the group")
+
+	// ErrInvalidReply is returned when a reply's parent comment is on
+	// another item or is itself a reply
+	ErrInvalidReply = errors.New("comments can only reply to top-level comments on the same item")
+
+	// ErrDuplicateID is returned when a record with the same ID exists
+	ErrDuplicateID = errors.New("a record with this ID already exists")
+
+	// ErrInvalidCursor is returned for a page cursor that is malformed or
+	// was issued for a different sort
+	ErrInvalidCursor = errors.New("invalid page cursor")
+)
+
+// PostgreSQL error codes for constraint violations
+const (
+	pqUniqueViolation     = "23505"
+	pqForeignKeyViolation = "23503"
+)
+
+// constraintErrors maps constraint names to the domain error they signal
+var constraintErrors = map[string]error{
+	"members_group_user_unique":      ErrAlreadyMember,
+	"members_group_id_fkey":          ErrGroupNotFound,
+	"bucket_items_group_id_fkey":     ErrGroupNotFound,
+	"bucket_items_created_by_fkey":   ErrMemberNotFound,
+	"bucket_items_completed_by_fkey": ErrMemberNotFound,
+	"groups_pkey":                    ErrDuplicateID,
+	"members_pkey":                   ErrDuplicateID,
+	"bucket_items_pkey":              ErrDuplicateID,
+	"tags_group_name_unique":         ErrDuplicateTagName,
+	"tags_group_id_fkey":             ErrGroupNotFound,
+	"tags_pkey":                      ErrDuplicateID,
+	"item_tags_item_id_fkey":         ErrItemNotFound,
+	"item_tags_tag_id_fkey":          ErrTagNotFound,
+	"comments_pkey":                  ErrDuplicateID,
+	"comments_item_id_fkey":          ErrItemNotFound,
+	"comments_group_id_fkey":         ErrGroupNotFound,
+	"comments_member_id_fkey":        ErrMemberNotFound,
+	"comments_parent_id_fkey":        ErrCommentNotFound,
+	"attachments_pkey":               ErrDuplicateID,
+	"attachments_item_id_fkey":       ErrItemNotFound,
+	"attachments_group_id_fkey":      ErrGroupNotFound,
+	"attachments_member_id_fkey":     ErrMemberNotFound,
+	"item_votes_item_id_fkey":        ErrItemNotFound,
+	"item_votes_member_id_fkey":      ErrMemberNotFound,
+	"item_reactions_item_id_fkey":    ErrItemNotFound,
+	"item_reactions_member_id_fkey":  ErrMemberNotFound,
+	"item_assignees_item_id_fkey":    ErrItemNotFound,
+	"item_assignees_member_id_fkey":  ErrMemberNotFound,
+}
+
+// mapConstraintError translates a constraint violation into a domain error.
+// Other errors are returned unchanged.
+func mapConstraintError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+	if pqErr.Code != pqUniqueViolation && pqErr.Code != pqForeignKeyViolation {
+		return err
+	}
+
+	if domainErr, ok := constraintErrors[pqErr.Constraint]; ok {
+		return fmt.Errorf("%w (%s)", domainErr, pqErr.Constraint)
+	}
+	return err
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapConstraintError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{
+			name:     "duplicate membership",
+			err:      &pq.Error{Code: pqUniqueViolation, Constraint: "members_group_user_unique"},
+			expected: ErrAlreadyMember,
+		},
+		{
+			name:     "unknown creator",
+			err:      &pq.Error{Code: pqForeignKeyViolation, Constraint: "bucket_items_created_by_fkey"},
+			expected: ErrMemberNotFound,
+		},
+		{
+			name:     "unknown group",
+			err:      &pq.Error{Code: pqForeignKeyViolation, Constraint: "members_group_id_fkey"},
+			expected: ErrGroupNotFound,
+		},
+		{
+			name:     "wrapped duplicate ID",
+			err:      fmt.Errorf("exec: %w", &pq.Error{Code: pqUniqueViolation, Constraint: "bucket_items_pkey"}),
+			expected: ErrDuplicateID,
+		},
+		{
+			name:     "duplicate tag name",
+			err:      &pq.Error{Code: pqUniqueViolation, Constraint: "tags_group_name_unique"},
+			expected: ErrDuplicateTagName,
+		},
+		{
+			name:     "unknown tag",
+			err:      &pq.Error{Code: pqForeignKeyViolation, Constraint: "item_tags_tag_id_fkey"},
+			expected: ErrTagNotFound,
+		},
+		{
+			name:     "deleted parent comment",
+			err:      &pq.Error{Code: pqForeignKeyViolation, Constraint: "comments_parent_id_fkey"},
+			expected: ErrCommentNotFound,
+		},
+		{
+			name:     "vote by unknown member",
+			err:      &pq.Error{Code: pqForeignKeyViolation, Constraint: "item_votes_member_id_fkey"},
+			expected: ErrMemberNotFound,
+		},
+		{
+			name:     "attachment by unknown member",
+			err:      &pq.Error{Code: pqForeignKeyViolation, Constraint: "attachments_member_id_fkey"},
+			expected: ErrMemberNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, mapConstraintError(tt.err), tt.expected)
+		})
+	}
+
+	t.Run("other errors are unchanged", func(t *testing.T) {
+		other := errors.New("connection reset")
+		assert.Equal(t, other, mapConstraintError(other))
+
+		notNull := &pq.Error{Code: "23502", Constraint: "members_group_user_unique"}
+		assert.Equal(t, error(notNull), mapConstraintError(notNull))
+	})
+}
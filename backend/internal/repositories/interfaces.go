@@ -2,7 +2,7 @@ package repositories
 
 import (
 	"context"
-	"database/sql"
+	"time"
 
 	"collaborative-bucket-list/internal/models"
 )
@@ -18,6 +18,10 @@ type GroupRepository interface {
 	// GetByUserID retrieves all groups created by a specific user
 	GetByUserID(ctx context.Context, userID string) ([]models.Group, error)
 	
+	// GetByDeadlineBetween retrieves the groups whose deadline is after
+	// after and no later than until, soonest first
+	GetByDeadlineBetween(ctx context.Context, after, until time.Time) ([]models.Group, error)
+	
 	// Update updates an existing group
 	Update(ctx context.Context, group *models.Group) error
 	
@@ -29,11 +33,44 @@ type GroupRepository interface {
 	
 	// GetSummariesByUserID retrieves group summaries for a user's dashboard
 	GetSummariesByUserID(ctx context.Context, userID string) ([]models.GroupSummary, error)
+	
+	// ListSummariesByUserID retrieves a filtered, sorted page of a user's
+	// group summaries. Returns ErrInvalidCursor for a bad cursor.
+	ListSummariesByUserID(ctx context.Context, userID string, opts models.GroupSummaryListOptions) (*models.GroupSummaryPage, error)
+	
+	// SearchByUserID searches the names of the groups a user created or
+	// belongs to, best matches first
+	SearchByUserID(ctx context.Context, userID, query string, limit int) ([]models.GroupSearchResult, error)
+	
+	// GetCalendarTokenHash returns the hash of the token of a group's
+	// calendar feed, or "" if the feed is not enabled
+	GetCalendarTokenHash(ctx context.Context, groupID string) (string, error)
+	
+	// EnsureCalendarTokenHash enables a group's calendar feed with the token
+	// hashing to candidateHash, unless it already has one, and returns the
+	// hash of the token in use
+	EnsureCalendarTokenHash(ctx context.Context, groupID, candidateHash string) (string, error)
+	
+	// RevokeCalendarToken disables a group's calendar feed, invalidating
+	// every subscription to it
+	RevokeCalendarToken(ctx context.Context, groupID string) error
+	
+	// GetReminderDays returns the days before its deadline a group's
+	// members are reminded of it, furthest first
+	GetReminderDays(ctx context.Context, groupID string) ([]int, error)
+	
+	// SetReminderDays replaces a group's reminder schedule
+	SetReminderDays(ctx context.Context, groupID string, days []int) error
+	
+	// GetDueReminders retrieves the reminders of group deadlines that fall
+	// due after after and no later than until, earliest first
+	GetDueReminders(ctx context.Context, after, until time.Time) ([]models.DueReminder, error)
 }
 
 // MemberRepository defines the interface for member data operations
 type MemberRepository interface {
-	// Create creates a new member
+	// Create creates a new member. Returns ErrAlreadyMember if the user is
+	// already a member of the group.
 	Create(ctx context.Context, member *models.Member) error
 	
 	// GetByID retrieves a member by their ID
@@ -48,7 +85,8 @@ type MemberRepository interface {
 	// Update updates an existing member
 	Update(ctx context.Context, member *models.Member) error
 	
-	// Delete deletes a member by ID
+	// Delete deletes a member by ID. Items the member added or completed
+	// stay with the group without naming them.
 	Delete(ctx context.Context, id string) error
 	
 	// ExistsByGroupAndUser checks if a user is already a member of a group
@@ -69,17 +107,156 @@ type BucketItemRepository interface {
 	// GetByGroupID retrieves all items for a specific group
 	GetByGroupID(ctx context.Context, groupID string) ([]models.BucketListItem, error)
 	
+	// List retrieves a filtered, sorted page of a group's items. Returns
+	// ErrInvalidCursor for a bad cursor.
+	List(ctx context.Context, groupID string, opts models.ItemListOptions) (*models.ItemPage, error)
+	
 	// Update updates an existing bucket list item
 	Update(ctx context.Context, item *models.BucketListItem) error
 	
 	// Delete deletes a bucket list item by ID
 	Delete(ctx context.Context, id string) error
 	
-	// ToggleCompletion toggles the completion status of an item
+	// ToggleCompletion toggles the completion status of an item. Marking
+	// an item incomplete clears its completion journal.
 	ToggleCompletion(ctx context.Context, itemID, memberID string, completed bool) error
 	
+	// UpdateCompletionJournal replaces the journal of a completed item.
+	// Returns ErrItemNotCompleted if the item is not completed.
+	UpdateCompletionJournal(ctx context.Context, itemID string, journal models.CompletionJournal) error
+	
+	// UpdateSchedule replaces the schedule of an item. An empty schedule
+	// unschedules it.
+	UpdateSchedule(ctx context.Context, itemID string, schedule models.ItemSchedule) error
+	
+	// SetAssignment assigns a member to organize an item or removes the
+	// assignment. Assigning twice, or unassigning a member who was never
+	// assigned, changes nothing. Returns ErrMemberNotFound if the member
+	// does not belong to the item's group.
+	SetAssignment(ctx context.Context, itemID, memberID string, assigned bool) error
+	
+	// GetAssignedTo retrieves the items assigned to any of the members,
+	// newest first
+	GetAssignedTo(ctx context.Context, memberIDs []string) ([]models.BucketListItem, error)
+	
 	// GetCompletionStats returns completion statistics for a group
 	GetCompletionStats(ctx context.Context, groupID string) (total, completed int, err error)
+	
+	// GetCompletionStatsByTag returns completion statistics for every tag of
+	// a group, including tags no item carries, ordered by name
+	GetCompletionStatsByTag(ctx context.Context, groupID string) ([]models.TagStats, error)
+	
+	// Search finds the items of a group whose title or description contains
+	// words starting with every word of the query, best matches first
+	Search(ctx context.Context, groupID, query string, limit int) ([]models.ItemSearchResult, error)
+	
+	// SearchByUserID is Search across every group a user created or belongs to
+	SearchByUserID(ctx context.Context, userID, query string, limit int) ([]models.ItemSearchResult, error)
+}
+
+// TagRepository defines the interface for tag data operations
+type TagRepository interface {
+	// Create creates a new tag. Returns ErrDuplicateTagName if the group
+	// already has a tag with the same name, ignoring case.
+	Create(ctx context.Context, tag *models.Tag) error
+	
+	// GetByID retrieves a tag by its ID
+	GetByID(ctx context.Context, id string) (*models.Tag, error)
+	
+	// GetByGroupID retrieves all tags of a group, ordered by name
+	GetByGroupID(ctx context.Context, groupID string) ([]models.Tag, error)
+	
+	// Update renames or recolors an existing tag
+	Update(ctx context.Context, tag *models.Tag) error
+	
+	// Delete deletes a tag by ID, removing it from every item
+	Delete(ctx context.Context, id string) error
+	
+	// SetItemTags replaces the tags of an item. Returns ErrTagNotFound if a
+	// tag does not exist or belongs to another group than the item.
+	SetItemTags(ctx context.Context, itemID string, tagIDs []string) error
+}
+
+// CommentRepository defines the interface for item comment data operations
+type CommentRepository interface {
+	// Create creates a new comment and sets its group ID from its item.
+	// Returns ErrInvalidReply if the parent comment is on another item or
+	// is itself a reply.
+	Create(ctx context.Context, comment *models.Comment) error
+	
+	// GetByID retrieves a comment by its ID
+	GetByID(ctx context.Context, id string) (*models.Comment, error)
+	
+	// GetByItemID retrieves the comment threads of an item, oldest first
+	GetByItemID(ctx context.Context, itemID string) ([]models.CommentThread, error)
+	
+	// Update edits the body of a comment and sets its edited time
+	Update(ctx context.Context, comment *models.Comment) error
+	
+	// Delete deletes a comment by ID, along with its replies
+	Delete(ctx context.Context, id string) error
+	
+	// CountByGroupID returns the number of comments on each item of a group
+	// that has any
+	CountByGroupID(ctx context.Context, groupID string) (map[string]int, error)
+}
+
+// ReactionRepository defines the interface for item vote and emoji reaction
+// data operations
+type ReactionRepository interface {
+	// SetVote adds or removes a member's vote for an item. Voting twice, or
+	// removing a vote the member never cast, changes nothing.
+	SetVote(ctx context.Context, itemID, memberID string, voted bool) error
+	
+	// AddReaction adds a member's emoji reaction to an item. Reacting twice
+	// with the same emoji changes nothing.
+	AddReaction(ctx context.Context, reaction *models.Reaction) error
+	
+	// RemoveReaction removes a member's emoji reaction from an item, if any
+	RemoveReaction(ctx context.Context, itemID, memberID, emoji string) error
+	
+	// GetByItemID aggregates the votes and reactions of an item
+	GetByItemID(ctx context.Context, itemID string) (*models.ItemReactions, error)
+	
+	// GetByGroupID aggregates the votes and reactions of each item of a
+	// group that has any
+	GetByGroupID(ctx context.Context, groupID string) (map[string]models.ItemReactions, error)
+}
+
+// AttachmentRepository defines the interface for item attachment data
+// operations. Deleting an attachment, directly or with its item, group or
+// uploader, queues its blob for deletion from the blob store.
+type AttachmentRepository interface {
+	// Create creates a new attachment and sets its group ID from its item
+	Create(ctx context.Context, attachment *models.Attachment) error
+	
+	// GetByID retrieves an attachment by its ID
+	GetByID(ctx context.Context, id string) (*models.Attachment, error)
+	
+	// GetByItemID retrieves the attachments of an item, oldest first
+	GetByItemID(ctx context.Context, itemID string) ([]models.Attachment, error)
+	
+	// GetByGroupID retrieves the attachments of each item of a group that
+	// has any, oldest first
+	GetByGroupID(ctx context.Context, groupID string) (map[string][]models.Attachment, error)
+	
+	// GetPendingProcessing returns up to limit attachments that have not
+	// been processed yet, oldest first
+	GetPendingProcessing(ctx context.Context, limit int) ([]models.Attachment, error)
+	
+	// UpdateProcessing records the size, dimensions and processing status
+	// of a processed attachment
+	UpdateProcessing(ctx context.Context, attachment *models.Attachment) error
+	
+	// Delete deletes an attachment by ID
+	Delete(ctx context.Context, id string) error
+	
+	// PendingBlobDeletions returns up to limit storage keys of deleted
+	// attachments whose blobs have not been deleted yet, oldest first
+	PendingBlobDeletions(ctx context.Context, limit int) ([]string, error)
+	
+	// ClearBlobDeletions marks the blobs of storage keys as deleted
+	ClearBlobDeletions(ctx context.Context, keys []string) error
 }
 
 // Repositories aggregates all repository interfaces
@@ -87,12 +264,26 @@ type Repositories struct {
 	Groups      GroupRepository
 	Members     MemberRepository
 	BucketItems BucketItemRepository
+	Tags        TagRepository
+	Comments    CommentRepository
+	Reactions   ReactionRepository
+	Attachments AttachmentRepository
 }
 
 // Transactional interface for operations that need database transactions
 type Transactional interface {
-	// WithTx executes a function within a database transaction
-	WithTx(ctx context.Context, fn func(tx *sql.Tx) error) error
+	// WithTx executes a function within a transaction. Repositories obtained
+	// from the RepositoryManager passed to fn are scoped to the transaction,
+	// which is rolled back if fn returns an error or panics. Calling WithTx
+	// on that manager nests the call in a savepoint, so a failing nested call
+	// undoes only its own work. A transaction that fails with a
+	// serialization failure or deadlock is retried from the start, so fn
+	// must not have side effects outside the repositories.
+	WithTx(ctx context.Context, fn func(repos RepositoryManager) error) error
+
+	// WithTxOptions is WithTx with an isolation level, read-only mode and
+	// retry limit. Nested calls cannot change the isolation level.
+	WithTxOptions(ctx context.Context, opts TxOptions, fn func(repos RepositoryManager) error) error
 }
 
 // RepositoryManager manages repository instances and transactions
@@ -101,4 +292,8 @@ type RepositoryManager interface {
 	Groups() GroupRepository
 	Members() MemberRepository
 	BucketItems() BucketItemRepository
+	Tags() TagRepository
+	Comments() CommentRepository
+	Reactions() ReactionRepository
+	Attachments() AttachmentRepository
 }
\ No newline at end of file
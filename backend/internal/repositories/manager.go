@@ -3,24 +3,40 @@ package repositories
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"errors"
+
+	"github.com/lib/pq"
 )
 
 // PostgresRepositoryManager implements RepositoryManager for PostgreSQL
 type PostgresRepositoryManager struct {
 	db          *sql.DB
+	tx          *sqlTxState
 	groups      GroupRepository
 	members     MemberRepository
 	bucketItems BucketItemRepository
+	tags        TagRepository
+	comments    CommentRepository
+	reactions   ReactionRepository
+	attachments AttachmentRepository
 }
 
 // NewPostgresRepositoryManager creates a new PostgreSQL repository manager
 func NewPostgresRepositoryManager(db *sql.DB) *PostgresRepositoryManager {
+	manager := newPostgresRepositoryManager(db)
+	manager.db = db
+	return manager
+}
+
+func newPostgresRepositoryManager(db dbExecutor) *PostgresRepositoryManager {
 	return &PostgresRepositoryManager{
-		db:          db,
 		groups:      NewPostgresGroupRepository(db),
 		members:     NewPostgresMemberRepository(db),
 		bucketItems: NewPostgresBucketItemRepository(db),
+		tags:        NewPostgresTagRepository(db),
+		comments:    NewPostgresCommentRepository(db),
+		reactions:   NewPostgresReactionRepository(db),
+		attachments: NewPostgresAttachmentRepository(db),
 	}
 }
 
@@ -39,70 +55,87 @@ func (m *PostgresRepositoryManager) BucketItems() BucketItemRepository {
 	return m.bucketItems
 }
 
-// WithTx executes a function within a database transaction
-func (m *PostgresRepositoryManager) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
-	tx, err := m.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
+// Tags returns the tag repository
+func (m *PostgresRepositoryManager) Tags() TagRepository {
+	return m.tags
+}
 
-	defer func() {
-		if p := recover(); p != nil {
-			tx.Rollback()
-			panic(p) // Re-throw panic after rollback
-		}
-	}()
-
-	if err := fn(tx); err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return fmt.Errorf("transaction failed: %v, rollback failed: %w", err, rbErr)
-		}
-		return err
-	}
+// Comments returns the comment repository
+func (m *PostgresRepositoryManager) Comments() CommentRepository {
+	return m.comments
+}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
+// Reactions returns the reaction repository
+func (m *PostgresRepositoryManager) Reactions() ReactionRepository {
+	return m.reactions
+}
 
-	return nil
+// Attachments returns the attachment repository
+func (m *PostgresRepositoryManager) Attachments() AttachmentRepository {
+	return m.attachments
 }
 
-// TransactionalRepositoryManager provides transactional repository access
-type TransactionalRepositoryManager struct {
-	tx          *sql.Tx
-	groups      GroupRepository
-	members     MemberRepository
-	bucketItems BucketItemRepository
+// WithTx executes a function within a database transaction
+func (m *PostgresRepositoryManager) WithTx(ctx context.Context, fn func(repos RepositoryManager) error) error {
+	return m.WithTxOptions(ctx, TxOptions{}, fn)
 }
 
-// NewTransactionalRepositoryManager creates repository manager for use within a transaction
-func NewTransactionalRepositoryManager(tx *sql.Tx) *TransactionalRepositoryManager {
-	return &TransactionalRepositoryManager{
-		tx:          tx,
-		groups:      NewPostgresGroupRepository(tx),
-		members:     NewPostgresMemberRepository(tx),
-		bucketItems: NewPostgresBucketItemRepository(tx),
+// WithTxOptions executes a function within a database transaction, or within
+// a savepoint when the manager is already scoped to a transaction
+func (m *PostgresRepositoryManager) WithTxOptions(ctx context.Context, opts TxOptions, fn func(repos RepositoryManager) error) error {
+	if m.tx != nil {
+		return runSavepoint(ctx, m.tx, opts.Isolation, func(nested *sqlTxState) error {
+			return fn(newPostgresTxRepositoryManager(nested))
+		})
 	}
-}
 
-// Groups returns the group repository
-func (m *TransactionalRepositoryManager) Groups() GroupRepository {
-	return m.groups
+	txOpts := &sql.TxOptions{Isolation: postgresIsolation(opts.Isolation), ReadOnly: opts.ReadOnly}
+	isolation := opts.Isolation
+	if isolation == IsolationDefault {
+		isolation = IsolationReadCommitted
+	}
+
+	return runSQLTx(ctx, m.db, txOpts, opts.maxRetries(), isPostgresRetryable, func(tx *sql.Tx) error {
+		return fn(newPostgresTxRepositoryManager(&sqlTxState{tx: tx, isolation: isolation}))
+	})
 }
 
-// Members returns the member repository
-func (m *TransactionalRepositoryManager) Members() MemberRepository {
-	return m.members
+// newPostgresTxRepositoryManager creates a repository manager scoped to a
+// transaction
+func newPostgresTxRepositoryManager(state *sqlTxState) *PostgresRepositoryManager {
+	manager := newPostgresRepositoryManager(state.tx)
+	manager.tx = state
+	return manager
 }
 
-// BucketItems returns the bucket item repository
-func (m *TransactionalRepositoryManager) BucketItems() BucketItemRepository {
-	return m.bucketItems
+// postgresIsolation maps an isolation level to database/sql
+func postgresIsolation(level IsolationLevel) sql.IsolationLevel {
+	switch level {
+	case IsolationReadCommitted:
+		return sql.LevelReadCommitted
+	case IsolationRepeatableRead:
+		return sql.LevelRepeatableRead
+	case IsolationSerializable:
+		return sql.LevelSerializable
+	default:
+		return sql.LevelDefault
+	}
 }
 
-// WithTx is not supported within a transactional manager
-func (m *TransactionalRepositoryManager) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
-	return fmt.Errorf("nested transactions are not supported")
+// PostgreSQL error codes of failures that succeed when retried
+const (
+	pqSerializationFailure = "40001"
+	pqDeadlockDetected     = "40P01"
+)
+
+// isPostgresRetryable reports whether a transaction failed with a
+// serialization failure or deadlock
+func isPostgresRetryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == pqSerializationFailure || pqErr.Code == pqDeadlockDetected
 }
 
 // Helper function to create repositories that work with both *sql.DB and *sql.Tx
@@ -25,13 +25,10 @@ func TestPostgresRepositoryManager_Creation(t *testing.T) {
 	})
 }
 
-func TestTransactionalRepositoryManager_Creation(t *testing.T) {
-	t.Run("transactional manager creation without transaction", func(t *testing.T) {
-		// Test that we can create a transactional manager instance
-		var tx *sql.Tx // nil transaction for structure testing
-		
-		// This should not panic and should create the manager
-		manager := NewTransactionalRepositoryManager(tx)
+func TestPostgresTxRepositoryManager_Creation(t *testing.T) {
+	t.Run("transaction-scoped manager creation without transaction", func(t *testing.T) {
+		// nil transaction for structure testing
+		manager := newPostgresTxRepositoryManager(&sqlTxState{})
 		
 		// Verify that all repository getters return non-nil interfaces
 		assert.NotNil(t, manager.Groups())
@@ -39,16 +36,16 @@ func TestTransactionalRepositoryManager_Creation(t *testing.T) {
 		assert.NotNil(t, manager.BucketItems())
 	})
 	
-	t.Run("nested transactions not supported", func(t *testing.T) {
-		var tx *sql.Tx
-		manager := NewTransactionalRepositoryManager(tx)
+	t.Run("nested transactions cannot change isolation level", func(t *testing.T) {
+		manager := newPostgresTxRepositoryManager(&sqlTxState{isolation: IsolationReadCommitted})
 		
-		err := manager.WithTx(context.Background(), func(tx *sql.Tx) error {
-			return nil
-		})
+		err := manager.WithTxOptions(context.Background(), TxOptions{Isolation: IsolationSerializable},
+			func(repos RepositoryManager) error {
+				return nil
+			})
 		
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "nested transactions are not supported")
+		assert.ErrorIs(t, err, ErrIsolationChange)
 	})
 }
 
@@ -61,12 +58,10 @@ func TestRepositoryInterfaces(t *testing.T) {
 		var _ RepositoryManager = manager
 	})
 	
-	t.Run("transactional manager implements RepositoryManager interface", func(t *testing.T) {
-		var tx *sql.Tx
-		manager := NewTransactionalRepositoryManager(tx)
-		
-		// Verify that the transactional manager implements the RepositoryManager interface
-		var _ RepositoryManager = manager
+	t.Run("other managers implement RepositoryManager interface", func(t *testing.T) {
+		var _ RepositoryManager = newPostgresTxRepositoryManager(&sqlTxState{})
+		var _ RepositoryManager = NewSQLiteRepositoryManager(nil)
+		var _ RepositoryManager = NewMemoryRepositoryManager()
 	})
 	
 	t.Run("repositories implement their respective interfaces", func(t *testing.T) {
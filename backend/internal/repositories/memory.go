@@ -0,0 +1,318 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// memoryState holds the rows of an in-memory store. Models are stored by
+// value and copied on the way in and out, so callers never share pointers
+// with the store.
+type memoryState struct {
+	mu          sync.RWMutex
+	groups      map[string]models.Group
+	members     map[string]models.Member
+	items       map[string]models.BucketListItem
+	tags        map[string]models.Tag
+	comments    map[string]models.Comment
+	votes       map[voteKey]models.Vote
+	reactions   map[reactionKey]models.Reaction
+	attachments map[string]models.Attachment
+	// calendarTokens maps group IDs to the hashes of their calendar feed
+	// tokens
+	calendarTokens map[string]string
+	// reminderDays maps group IDs to their reminder schedules
+	reminderDays map[string][]int
+	// blobDeletions maps the storage keys of deleted attachments to the
+	// time they were queued
+	blobDeletions map[string]time.Time
+}
+
+func newMemoryState() *memoryState {
+	return &memoryState{
+		groups:         make(map[string]models.Group),
+		members:        make(map[string]models.Member),
+		items:          make(map[string]models.BucketListItem),
+		tags:           make(map[string]models.Tag),
+		comments:       make(map[string]models.Comment),
+		votes:          make(map[voteKey]models.Vote),
+		reactions:      make(map[reactionKey]models.Reaction),
+		attachments:    make(map[string]models.Attachment),
+		calendarTokens: make(map[string]string),
+		reminderDays:   make(map[string][]int),
+		blobDeletions:  make(map[string]time.Time),
+	}
+}
+
+// snapshot returns a deep copy of the state. The caller must hold mu.
+func (s *memoryState) snapshot() *memoryState {
+	snapshot := newMemoryState()
+	for id, group := range s.groups {
+		snapshot.groups[id] = cloneGroup(group)
+	}
+	for id, member := range s.members {
+		snapshot.members[id] = cloneMember(member)
+	}
+	for id, item := range s.items {
+		snapshot.items[id] = cloneItem(item)
+	}
+	for id, tag := range s.tags {
+		snapshot.tags[id] = cloneTag(tag)
+	}
+	for id, comment := range s.comments {
+		snapshot.comments[id] = cloneComment(comment)
+	}
+	for key, vote := range s.votes {
+		snapshot.votes[key] = vote
+	}
+	for key, reaction := range s.reactions {
+		snapshot.reactions[key] = reaction
+	}
+	for id, attachment := range s.attachments {
+		snapshot.attachments[id] = cloneAttachment(attachment)
+	}
+	for groupID, token := range s.calendarTokens {
+		snapshot.calendarTokens[groupID] = token
+	}
+	for groupID, days := range s.reminderDays {
+		snapshot.reminderDays[groupID] = append([]int(nil), days...)
+	}
+	for key, queuedAt := range s.blobDeletions {
+		snapshot.blobDeletions[key] = queuedAt
+	}
+	return snapshot
+}
+
+// MemoryRepositoryManager implements RepositoryManager with in-memory maps.
+// It needs no database and is safe for concurrent use, which makes it
+// suitable for tests and for running the server with STORAGE=memory. Data
+// is lost when the process exits.
+type MemoryRepositoryManager struct {
+	state       *memoryState
+	inTx        bool
+	groups      GroupRepository
+	members     MemberRepository
+	bucketItems BucketItemRepository
+	tags        TagRepository
+	comments    CommentRepository
+	reactions   ReactionRepository
+	attachments AttachmentRepository
+}
+
+// NewMemoryRepositoryManager creates an empty in-memory repository manager
+func NewMemoryRepositoryManager() *MemoryRepositoryManager {
+	return newMemoryRepositoryManager(newMemoryState(), false)
+}
+
+func newMemoryRepositoryManager(state *memoryState, inTx bool) *MemoryRepositoryManager {
+	return &MemoryRepositoryManager{
+		state:       state,
+		inTx:        inTx,
+		groups:      &MemoryGroupRepository{state: state},
+		members:     &MemoryMemberRepository{state: state},
+		bucketItems: &MemoryBucketItemRepository{state: state},
+		tags:        &MemoryTagRepository{state: state},
+		comments:    &MemoryCommentRepository{state: state},
+		reactions:   &MemoryReactionRepository{state: state},
+		attachments: &MemoryAttachmentRepository{state: state},
+	}
+}
+
+// Groups returns the group repository
+func (m *MemoryRepositoryManager) Groups() GroupRepository {
+	return m.groups
+}
+
+// Members returns the member repository
+func (m *MemoryRepositoryManager) Members() MemberRepository {
+	return m.members
+}
+
+// BucketItems returns the bucket item repository
+func (m *MemoryRepositoryManager) BucketItems() BucketItemRepository {
+	return m.bucketItems
+}
+
+// Tags returns the tag repository
+func (m *MemoryRepositoryManager) Tags() TagRepository {
+	return m.tags
+}
+
+// Comments returns the comment repository
+func (m *MemoryRepositoryManager) Comments() CommentRepository {
+	return m.comments
+}
+
+// Reactions returns the reaction repository
+func (m *MemoryRepositoryManager) Reactions() ReactionRepository {
+	return m.reactions
+}
+
+// Attachments returns the attachment repository
+func (m *MemoryRepositoryManager) Attachments() AttachmentRepository {
+	return m.attachments
+}
+
+// WithTx runs fn against a private copy of the store and publishes the copy
+// only if fn succeeds. Transactions hold the store's write lock, so they
+// are serializable; fn must use the repositories it is given, since calls
+// through the outer manager would wait for the transaction to finish.
+func (m *MemoryRepositoryManager) WithTx(ctx context.Context, fn func(repos RepositoryManager) error) error {
+	return m.WithTxOptions(ctx, TxOptions{}, fn)
+}
+
+// WithTxOptions is WithTx; every memory transaction is already serializable
+// and never needs retrying, so the options are ignored. Nested calls run
+// like savepoints: a failing nested call undoes only its own changes.
+func (m *MemoryRepositoryManager) WithTxOptions(ctx context.Context, opts TxOptions, fn func(repos RepositoryManager) error) error {
+	if m.inTx {
+		return m.withSavepoint(fn)
+	}
+
+	m.state.mu.Lock()
+	defer m.state.mu.Unlock()
+
+	// A panic in fn leaves the store untouched, like a rollback
+	tx := m.state.snapshot()
+	if err := fn(newMemoryRepositoryManager(tx, true)); err != nil {
+		return err
+	}
+
+	m.state.groups = tx.groups
+	m.state.members = tx.members
+	m.state.items = tx.items
+	m.state.tags = tx.tags
+	m.state.comments = tx.comments
+	m.state.votes = tx.votes
+	m.state.reactions = tx.reactions
+	m.state.attachments = tx.attachments
+	m.state.calendarTokens = tx.calendarTokens
+	m.state.reminderDays = tx.reminderDays
+	m.state.blobDeletions = tx.blobDeletions
+	return nil
+}
+
+// withSavepoint runs fn on the transaction's own state and restores a copy
+// taken beforehand if fn fails or panics
+func (m *MemoryRepositoryManager) withSavepoint(fn func(repos RepositoryManager) error) (err error) {
+	m.state.mu.RLock()
+	saved := m.state.snapshot()
+	m.state.mu.RUnlock()
+
+	restore := func() {
+		m.state.mu.Lock()
+		defer m.state.mu.Unlock()
+		m.state.groups = saved.groups
+		m.state.members = saved.members
+		m.state.items = saved.items
+		m.state.tags = saved.tags
+		m.state.comments = saved.comments
+		m.state.votes = saved.votes
+		m.state.reactions = saved.reactions
+		m.state.attachments = saved.attachments
+		m.state.calendarTokens = saved.calendarTokens
+		m.state.reminderDays = saved.reminderDays
+		m.state.blobDeletions = saved.blobDeletions
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			restore()
+			panic(p)
+		}
+	}()
+
+	if err := fn(m); err != nil {
+		restore()
+		return err
+	}
+	return nil
+}
+
+// memoryTime rounds a timestamp to the microsecond precision of PostgreSQL
+// TIMESTAMPTZ columns, so both stores return the same values
+func memoryTime(t time.Time) time.Time {
+	return t.Round(time.Microsecond)
+}
+
+func memoryTimePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	rounded := memoryTime(*t)
+	return &rounded
+}
+
+func cloneString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	clone := *s
+	return &clone
+}
+
+func cloneGroup(group models.Group) models.Group {
+	group.Deadline = memoryTimePtr(group.Deadline)
+	group.CreatedAt = memoryTime(group.CreatedAt)
+	group.Localize()
+	return group
+}
+
+func cloneMember(member models.Member) models.Member {
+	member.UserID = cloneString(member.UserID)
+	member.JoinedAt = memoryTime(member.JoinedAt)
+	return member
+}
+
+func cloneItem(item models.BucketListItem) models.BucketListItem {
+	item.Description = cloneString(item.Description)
+	item.CompletedBy = cloneString(item.CompletedBy)
+	item.CreatedBy = cloneString(item.CreatedBy)
+	item.CompletedAt = memoryTimePtr(item.CompletedAt)
+	item.CompletionNote = cloneString(item.CompletionNote)
+	item.OccurredOn = cloneString(item.OccurredOn)
+	if item.CompletionRating != nil {
+		rating := *item.CompletionRating
+		item.CompletionRating = &rating
+	}
+	item.ScheduledStart = memoryTimePtr(item.ScheduledStart)
+	item.ScheduledEnd = memoryTimePtr(item.ScheduledEnd)
+	item.CreatedAt = memoryTime(item.CreatedAt)
+	if item.TagIDs != nil {
+		item.TagIDs = append([]string(nil), item.TagIDs...)
+	}
+	if item.AssigneeIDs != nil {
+		item.AssigneeIDs = append([]string(nil), item.AssigneeIDs...)
+	}
+	return item
+}
+
+func cloneTag(tag models.Tag) models.Tag {
+	tag.CreatedAt = memoryTime(tag.CreatedAt)
+	return tag
+}
+
+func cloneComment(comment models.Comment) models.Comment {
+	comment.ParentID = cloneString(comment.ParentID)
+	comment.CreatedAt = memoryTime(comment.CreatedAt)
+	comment.EditedAt = memoryTimePtr(comment.EditedAt)
+	return comment
+}
+
+func cloneAttachment(attachment models.Attachment) models.Attachment {
+	attachment.CreatedAt = memoryTime(attachment.CreatedAt)
+	attachment.URL = ""
+	attachment.Thumbnails = nil
+	if attachment.Width != nil {
+		width := *attachment.Width
+		attachment.Width = &width
+	}
+	if attachment.Height != nil {
+		height := *attachment.Height
+		attachment.Height = &height
+	}
+	return attachment
+}
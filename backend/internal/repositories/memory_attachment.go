@@ -0,0 +1,220 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// MemoryAttachmentRepository implements AttachmentRepository in memory
+type MemoryAttachmentRepository struct {
+	state *memoryState
+}
+
+// Create records a new attachment, taking its group from its item
+func (r *MemoryAttachmentRepository) Create(ctx context.Context, attachment *models.Attachment) error {
+	if err := attachment.IsValid(); err != nil {
+		return fmt.Errorf("invalid attachment data: %w", err)
+	}
+
+	attachment.Sanitize()
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	if _, exists := r.state.attachments[attachment.ID]; exists {
+		return fmt.Errorf("failed to create attachment: %w", ErrDuplicateID)
+	}
+	item, exists := r.state.items[attachment.ItemID]
+	if !exists {
+		return fmt.Errorf("failed to create attachment: %w", ErrItemNotFound)
+	}
+	if _, exists := r.state.members[attachment.MemberID]; !exists {
+		return fmt.Errorf("failed to create attachment: %w", ErrMemberNotFound)
+	}
+	for _, existing := range r.state.attachments {
+		if existing.StorageKey == attachment.StorageKey {
+			return fmt.Errorf("failed to create attachment: %w", ErrDuplicateID)
+		}
+	}
+
+	attachment.GroupID = item.GroupID
+	r.state.attachments[attachment.ID] = cloneAttachment(*attachment)
+
+	return nil
+}
+
+// GetByID retrieves an attachment by its ID
+func (r *MemoryAttachmentRepository) GetByID(ctx context.Context, id string) (*models.Attachment, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	attachment, exists := r.state.attachments[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentNotFound, id)
+	}
+
+	attachment = cloneAttachment(attachment)
+	return &attachment, nil
+}
+
+// GetByItemID retrieves the attachments of an item, oldest first
+func (r *MemoryAttachmentRepository) GetByItemID(ctx context.Context, itemID string) ([]models.Attachment, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	var attachments []models.Attachment
+	for _, attachment := range r.state.attachments {
+		if attachment.ItemID == itemID {
+			attachments = append(attachments, cloneAttachment(attachment))
+		}
+	}
+
+	sortAttachments(attachments)
+	return attachments, nil
+}
+
+// GetByGroupID retrieves the attachments of each item of a group that has
+// any, oldest first
+func (r *MemoryAttachmentRepository) GetByGroupID(ctx context.Context, groupID string) (map[string][]models.Attachment, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	return attachmentsOfGroup(r.state, groupID), nil
+}
+
+// GetPendingProcessing returns up to limit attachments that have not been
+// processed yet, oldest first
+func (r *MemoryAttachmentRepository) GetPendingProcessing(ctx context.Context, limit int) ([]models.Attachment, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	var attachments []models.Attachment
+	for _, attachment := range r.state.attachments {
+		if attachment.ProcessingStatus == models.AttachmentPending {
+			attachments = append(attachments, cloneAttachment(attachment))
+		}
+	}
+
+	sortAttachments(attachments)
+	if len(attachments) > limit {
+		attachments = attachments[:limit]
+	}
+	return attachments, nil
+}
+
+// UpdateProcessing records the outcome of processing an attachment: its
+// size, dimensions and processing status
+func (r *MemoryAttachmentRepository) UpdateProcessing(ctx context.Context, attachment *models.Attachment) error {
+	if err := attachment.IsValid(); err != nil {
+		return fmt.Errorf("invalid attachment data: %w", err)
+	}
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	existing, exists := r.state.attachments[attachment.ID]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrAttachmentNotFound, attachment.ID)
+	}
+
+	existing.Size = attachment.Size
+	existing.Width = attachment.Width
+	existing.Height = attachment.Height
+	existing.ProcessingStatus = attachment.ProcessingStatus
+	r.state.attachments[attachment.ID] = cloneAttachment(existing)
+
+	return nil
+}
+
+// Delete deletes an attachment by ID and queues its blob for deletion
+func (r *MemoryAttachmentRepository) Delete(ctx context.Context, id string) error {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	if _, exists := r.state.attachments[id]; !exists {
+		return fmt.Errorf("%w: %s", ErrAttachmentNotFound, id)
+	}
+
+	deleteAttachment(r.state, id)
+
+	return nil
+}
+
+// PendingBlobDeletions returns up to limit storage keys of deleted
+// attachments whose blobs still need deleting, oldest first
+func (r *MemoryAttachmentRepository) PendingBlobDeletions(ctx context.Context, limit int) ([]string, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	keys := make([]string, 0, len(r.state.blobDeletions))
+	for key := range r.state.blobDeletions {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := r.state.blobDeletions[keys[i]], r.state.blobDeletions[keys[j]]
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	return keys, nil
+}
+
+// ClearBlobDeletions removes storage keys whose blobs have been deleted
+// from the queue
+func (r *MemoryAttachmentRepository) ClearBlobDeletions(ctx context.Context, keys []string) error {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	for _, key := range keys {
+		delete(r.state.blobDeletions, key)
+	}
+
+	return nil
+}
+
+// attachmentsOfGroup returns the attachments of each item of a group that
+// has any, oldest first. The caller must hold the state lock.
+func attachmentsOfGroup(state *memoryState, groupID string) map[string][]models.Attachment {
+	byItem := make(map[string][]models.Attachment)
+	for _, attachment := range state.attachments {
+		if attachment.GroupID == groupID {
+			byItem[attachment.ItemID] = append(byItem[attachment.ItemID], cloneAttachment(attachment))
+		}
+	}
+	for _, attachments := range byItem {
+		sortAttachments(attachments)
+	}
+	return byItem
+}
+
+// sortAttachments sorts attachments oldest first
+func sortAttachments(attachments []models.Attachment) {
+	sort.Slice(attachments, func(i, j int) bool {
+		if !attachments[i].CreatedAt.Equal(attachments[j].CreatedAt) {
+			return attachments[i].CreatedAt.Before(attachments[j].CreatedAt)
+		}
+		return attachments[i].ID < attachments[j].ID
+	})
+}
+
+// deleteAttachment deletes an attachment and queues its blob for deletion,
+// like the trigger of the SQL stores. The caller must hold the state lock.
+func deleteAttachment(state *memoryState, id string) {
+	attachment := state.attachments[id]
+	delete(state.attachments, id)
+	if _, queued := state.blobDeletions[attachment.StorageKey]; !queued {
+		state.blobDeletions[attachment.StorageKey] = memoryTime(time.Now())
+	}
+}
@@ -0,0 +1,400 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// MemoryBucketItemRepository implements BucketItemRepository in memory
+type MemoryBucketItemRepository struct {
+	state *memoryState
+}
+
+// Create creates a new bucket list item
+func (r *MemoryBucketItemRepository) Create(ctx context.Context, item *models.BucketListItem) error {
+	if err := item.IsValid(); err != nil {
+		return fmt.Errorf("invalid bucket item data: %w", err)
+	}
+
+	item.Sanitize()
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	if _, exists := r.state.items[item.ID]; exists {
+		return fmt.Errorf("failed to create bucket item: %w", ErrDuplicateID)
+	}
+	if _, exists := r.state.groups[item.GroupID]; !exists {
+		return fmt.Errorf("failed to create bucket item: %w", ErrGroupNotFound)
+	}
+	if item.CreatedBy == nil {
+		return fmt.Errorf("failed to create bucket item: %w", ErrMemberNotFound)
+	}
+	if _, exists := r.state.members[*item.CreatedBy]; !exists {
+		return fmt.Errorf("failed to create bucket item: %w", ErrMemberNotFound)
+	}
+	if item.CompletedBy != nil {
+		if _, exists := r.state.members[*item.CompletedBy]; !exists {
+			return fmt.Errorf("failed to create bucket item: %w", ErrMemberNotFound)
+		}
+	}
+
+	stored := cloneItem(*item)
+	stored.TagIDs = nil
+	stored.AssigneeIDs = nil
+	r.state.items[item.ID] = stored
+
+	return nil
+}
+
+// GetByID retrieves a bucket list item by its ID
+func (r *MemoryBucketItemRepository) GetByID(ctx context.Context, id string) (*models.BucketListItem, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	item, exists := r.state.items[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrItemNotFound, id)
+	}
+
+	item = cloneItem(item)
+	return &item, nil
+}
+
+// GetByGroupID retrieves all items for a specific group
+func (r *MemoryBucketItemRepository) GetByGroupID(ctx context.Context, groupID string) ([]models.BucketListItem, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	return itemsOfGroup(r.state, groupID), nil
+}
+
+// List retrieves a filtered, sorted page of a group's items
+func (r *MemoryBucketItemRepository) List(ctx context.Context, groupID string, opts models.ItemListOptions) (*models.ItemPage, error) {
+	ks, err := itemKeyset(opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := ks.decode(opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	limit := models.PageLimit(opts.Limit)
+
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	var votes map[string]int
+	if ks.kind == sortKeyInt {
+		votes = voteCounts(r.state)
+	}
+	position := func(item models.BucketListItem) (interface{}, string) {
+		if ks.kind == sortKeyInt {
+			return int64(votes[item.ID]), item.ID
+		}
+		return itemSortKey(ks, item, strings.ToLower(item.Title)), item.ID
+	}
+
+	var items []models.BucketListItem
+	for _, item := range r.state.items {
+		if item.GroupID != groupID || !itemMatches(item, opts) {
+			continue
+		}
+		if key, id := position(item); ks.follows(key, id, cursor) {
+			items = append(items, cloneItem(item))
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		iKey, iID := position(items[i])
+		jKey, jID := position(items[j])
+		return ks.less(iKey, iID, jKey, jID)
+	})
+	if len(items) > limit+1 {
+		items = items[:limit+1]
+	}
+
+	page := &models.ItemPage{}
+	page.Items, page.NextCursor = trimPage(items, limit, ks, func(i int) (interface{}, string) {
+		return position(items[i])
+	})
+	return page, nil
+}
+
+// Update updates an existing bucket list item
+func (r *MemoryBucketItemRepository) Update(ctx context.Context, item *models.BucketListItem) error {
+	if err := item.IsValid(); err != nil {
+		return fmt.Errorf("invalid bucket item data: %w", err)
+	}
+
+	item.Sanitize()
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	existing, exists := r.state.items[item.ID]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrItemNotFound, item.ID)
+	}
+	if item.CompletedBy != nil {
+		if _, exists := r.state.members[*item.CompletedBy]; !exists {
+			return fmt.Errorf("failed to update bucket item: %w", ErrMemberNotFound)
+		}
+	}
+
+	existing.Title = item.Title
+	existing.Description = item.Description
+	existing.Completed = item.Completed
+	existing.CompletedBy = item.CompletedBy
+	existing.CompletedAt = item.CompletedAt
+	existing.CompletionJournal = item.CompletionJournal
+	existing.ItemSchedule = item.ItemSchedule
+	r.state.items[item.ID] = cloneItem(existing)
+
+	return nil
+}
+
+// Delete deletes a bucket list item by ID
+func (r *MemoryBucketItemRepository) Delete(ctx context.Context, id string) error {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	if _, exists := r.state.items[id]; !exists {
+		return fmt.Errorf("%w: %s", ErrItemNotFound, id)
+	}
+
+	delete(r.state.items, id)
+	deleteOrphans(r.state)
+
+	return nil
+}
+
+// ToggleCompletion toggles the completion status of an item
+func (r *MemoryBucketItemRepository) ToggleCompletion(ctx context.Context, itemID, memberID string, completed bool) error {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	item, exists := r.state.items[itemID]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrItemNotFound, itemID)
+	}
+
+	if completed {
+		// Mark as completed
+		if _, exists := r.state.members[memberID]; !exists {
+			return fmt.Errorf("failed to toggle completion status: %w", ErrMemberNotFound)
+		}
+		now := time.Now()
+		item.Completed = true
+		item.CompletedBy = &memberID
+		item.CompletedAt = &now
+	} else {
+		// Mark as not completed
+		item.Completed = false
+		item.CompletedBy = nil
+		item.CompletedAt = nil
+		item.CompletionJournal = models.CompletionJournal{}
+	}
+	r.state.items[itemID] = cloneItem(item)
+
+	return nil
+}
+
+// UpdateCompletionJournal replaces the journal of a completed item
+func (r *MemoryBucketItemRepository) UpdateCompletionJournal(ctx context.Context, itemID string, journal models.CompletionJournal) error {
+	journal.Sanitize()
+	if validation := journal.Validate(); !validation.IsValid {
+		return fmt.Errorf("invalid completion journal: %s", validation.Errors[0].Message)
+	}
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	item, exists := r.state.items[itemID]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrItemNotFound, itemID)
+	}
+	if !item.Completed {
+		return fmt.Errorf("%w: %s", ErrItemNotCompleted, itemID)
+	}
+
+	item.CompletionJournal = journal
+	r.state.items[itemID] = cloneItem(item)
+
+	return nil
+}
+
+// UpdateSchedule replaces the schedule of an item
+func (r *MemoryBucketItemRepository) UpdateSchedule(ctx context.Context, itemID string, schedule models.ItemSchedule) error {
+	if validation := schedule.Validate(nil); !validation.IsValid {
+		return fmt.Errorf("invalid item schedule: %s", validation.Errors[0].Message)
+	}
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	item, exists := r.state.items[itemID]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrItemNotFound, itemID)
+	}
+
+	item.ItemSchedule = schedule
+	r.state.items[itemID] = cloneItem(item)
+
+	return nil
+}
+
+// SetAssignment assigns a member to organize an item or removes the
+// assignment
+func (r *MemoryBucketItemRepository) SetAssignment(ctx context.Context, itemID, memberID string, assigned bool) error {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	item, exists := r.state.items[itemID]
+	if !exists {
+		if !assigned {
+			return nil
+		}
+		return fmt.Errorf("%w: %s", ErrItemNotFound, itemID)
+	}
+
+	if !assigned {
+		item.AssigneeIDs = slices.DeleteFunc(item.AssigneeIDs, func(id string) bool { return id == memberID })
+		if len(item.AssigneeIDs) == 0 {
+			item.AssigneeIDs = nil
+		}
+		r.state.items[itemID] = item
+		return nil
+	}
+
+	// Only members of the item's group can be assigned
+	if member, exists := r.state.members[memberID]; !exists || member.GroupID != item.GroupID {
+		return fmt.Errorf("failed to assign item: %w", ErrMemberNotFound)
+	}
+	if !slices.Contains(item.AssigneeIDs, memberID) {
+		item.AssigneeIDs = append(item.AssigneeIDs, memberID)
+		r.state.items[itemID] = item
+	}
+
+	return nil
+}
+
+// GetAssignedTo retrieves the items assigned to any of the members, newest
+// first
+func (r *MemoryBucketItemRepository) GetAssignedTo(ctx context.Context, memberIDs []string) ([]models.BucketListItem, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	var items []models.BucketListItem
+	for _, item := range r.state.items {
+		if slices.ContainsFunc(item.AssigneeIDs, func(id string) bool { return slices.Contains(memberIDs, id) }) {
+			items = append(items, cloneItem(item))
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].CreatedAt.Equal(items[j].CreatedAt) {
+			return items[i].CreatedAt.After(items[j].CreatedAt)
+		}
+		return items[i].ID < items[j].ID
+	})
+	return items, nil
+}
+
+// GetCompletionStats returns completion statistics for a group
+func (r *MemoryBucketItemRepository) GetCompletionStats(ctx context.Context, groupID string) (total, completed int, err error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	for _, item := range r.state.items {
+		if item.GroupID == groupID {
+			total++
+			if item.Completed {
+				completed++
+			}
+		}
+	}
+
+	return total, completed, nil
+}
+
+// GetCompletionStatsByTag returns completion statistics for every tag of a
+// group
+func (r *MemoryBucketItemRepository) GetCompletionStatsByTag(ctx context.Context, groupID string) ([]models.TagStats, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	var stats []models.TagStats
+	for _, tag := range tagsOfGroup(r.state, groupID) {
+		tagStats := models.TagStats{Tag: tag}
+		for _, item := range r.state.items {
+			if slices.Contains(item.TagIDs, tag.ID) {
+				tagStats.ItemCount++
+				if item.Completed {
+					tagStats.CompletedCount++
+				}
+			}
+		}
+		stats = append(stats, tagStats)
+	}
+
+	setTagProgress(stats)
+	return stats, nil
+}
+
+// Search finds the items of a group matching a query
+func (r *MemoryBucketItemRepository) Search(ctx context.Context, groupID, query string, limit int) ([]models.ItemSearchResult, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	return searchItems(r.state, map[string]bool{groupID: true}, query, limit), nil
+}
+
+// SearchByUserID finds matching items in every group a user created or
+// belongs to
+func (r *MemoryBucketItemRepository) SearchByUserID(ctx context.Context, userID, query string, limit int) ([]models.ItemSearchResult, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	return searchItems(r.state, userGroupIDs(r.state, userID), query, limit), nil
+}
+
+// searchItems matches the items of the given groups against a query. The
+// caller must hold the state lock.
+func searchItems(state *memoryState, groupIDs map[string]bool, query string, limit int) []models.ItemSearchResult {
+	matcher := newTextMatcher(query)
+
+	var results []models.ItemSearchResult
+	for _, item := range state.items {
+		if !groupIDs[item.GroupID] {
+			continue
+		}
+		if rank, ok := matcher.rank(item.Title, item.Description); ok {
+			results = append(results, matcher.itemSearchResult(cloneItem(item), state.groups[item.GroupID].Name, rank))
+		}
+	}
+
+	return sortItemSearchResults(results, limit)
+}
+
+// itemsOfGroup returns the items of a group, newest first. The caller must
+// hold the state lock.
+func itemsOfGroup(state *memoryState, groupID string) []models.BucketListItem {
+	var items []models.BucketListItem
+	for _, item := range state.items {
+		if item.GroupID == groupID {
+			items = append(items, cloneItem(item))
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+	return items
+}
@@ -0,0 +1,202 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// MemoryCommentRepository implements CommentRepository in memory
+type MemoryCommentRepository struct {
+	state *memoryState
+}
+
+// Create creates a new comment, taking its group from its item
+func (r *MemoryCommentRepository) Create(ctx context.Context, comment *models.Comment) error {
+	if err := comment.IsValid(); err != nil {
+		return fmt.Errorf("invalid comment data: %w", err)
+	}
+
+	comment.Sanitize()
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	if _, exists := r.state.comments[comment.ID]; exists {
+		return fmt.Errorf("failed to create comment: %w", ErrDuplicateID)
+	}
+	item, exists := r.state.items[comment.ItemID]
+	if !exists {
+		return fmt.Errorf("failed to create comment: %w", ErrItemNotFound)
+	}
+	if _, exists := r.state.members[comment.MemberID]; !exists {
+		return fmt.Errorf("failed to create comment: %w", ErrMemberNotFound)
+	}
+	if comment.ParentID != nil {
+		parent, exists := r.state.comments[*comment.ParentID]
+		if !exists {
+			return fmt.Errorf("failed to create comment: %w: %s", ErrCommentNotFound, *comment.ParentID)
+		}
+		if err := checkReplyParent(parent, comment.ItemID); err != nil {
+			return fmt.Errorf("failed to create comment: %w", err)
+		}
+	}
+
+	comment.GroupID = item.GroupID
+	comment.EditedAt = nil
+	r.state.comments[comment.ID] = cloneComment(*comment)
+
+	return nil
+}
+
+// GetByID retrieves a comment by its ID
+func (r *MemoryCommentRepository) GetByID(ctx context.Context, id string) (*models.Comment, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	comment, exists := r.state.comments[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrCommentNotFound, id)
+	}
+
+	comment = cloneComment(comment)
+	return &comment, nil
+}
+
+// GetByItemID retrieves the comment threads of an item, oldest first
+func (r *MemoryCommentRepository) GetByItemID(ctx context.Context, itemID string) ([]models.CommentThread, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	var comments []models.Comment
+	for _, comment := range r.state.comments {
+		if comment.ItemID == itemID {
+			comments = append(comments, cloneComment(comment))
+		}
+	}
+
+	sort.Slice(comments, func(i, j int) bool {
+		if !comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+			return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+		}
+		return comments[i].ID < comments[j].ID
+	})
+	return commentThreads(comments), nil
+}
+
+// Update edits the body of a comment and sets its edited time
+func (r *MemoryCommentRepository) Update(ctx context.Context, comment *models.Comment) error {
+	if err := comment.IsValid(); err != nil {
+		return fmt.Errorf("invalid comment data: %w", err)
+	}
+
+	comment.Sanitize()
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	existing, exists := r.state.comments[comment.ID]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrCommentNotFound, comment.ID)
+	}
+
+	editedAt := memoryTime(time.Now())
+	existing.Body = comment.Body
+	existing.EditedAt = &editedAt
+	r.state.comments[comment.ID] = cloneComment(existing)
+	comment.EditedAt = &editedAt
+
+	return nil
+}
+
+// Delete deletes a comment by ID, along with its replies
+func (r *MemoryCommentRepository) Delete(ctx context.Context, id string) error {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	if _, exists := r.state.comments[id]; !exists {
+		return fmt.Errorf("%w: %s", ErrCommentNotFound, id)
+	}
+
+	delete(r.state.comments, id)
+	deleteOrphans(r.state)
+
+	return nil
+}
+
+// CountByGroupID returns the number of comments on each item of a group
+// that has any
+func (r *MemoryCommentRepository) CountByGroupID(ctx context.Context, groupID string) (map[string]int, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	return commentCountsOfGroup(r.state, groupID), nil
+}
+
+// deleteOrphans deletes the comments, votes, reactions, assignments and
+// attachments whose item, member or parent comment is gone, like the foreign
+// keys of the SQL stores. The caller must hold the state lock.
+func deleteOrphans(state *memoryState) {
+	for id, comment := range state.comments {
+		_, itemExists := state.items[comment.ItemID]
+		_, memberExists := state.members[comment.MemberID]
+		if !itemExists || !memberExists {
+			delete(state.comments, id)
+		}
+	}
+	for id, comment := range state.comments {
+		if comment.ParentID == nil {
+			continue
+		}
+		if _, exists := state.comments[*comment.ParentID]; !exists {
+			delete(state.comments, id)
+		}
+	}
+	for key := range state.votes {
+		_, itemExists := state.items[key.itemID]
+		_, memberExists := state.members[key.memberID]
+		if !itemExists || !memberExists {
+			delete(state.votes, key)
+		}
+	}
+	for key := range state.reactions {
+		_, itemExists := state.items[key.itemID]
+		_, memberExists := state.members[key.memberID]
+		if !itemExists || !memberExists {
+			delete(state.reactions, key)
+		}
+	}
+	memberGone := func(memberID string) bool {
+		_, exists := state.members[memberID]
+		return !exists
+	}
+	for id, item := range state.items {
+		if slices.ContainsFunc(item.AssigneeIDs, memberGone) {
+			item.AssigneeIDs = slices.DeleteFunc(item.AssigneeIDs, memberGone)
+			state.items[id] = item
+		}
+	}
+	for id, attachment := range state.attachments {
+		_, itemExists := state.items[attachment.ItemID]
+		_, memberExists := state.members[attachment.MemberID]
+		if !itemExists || !memberExists {
+			deleteAttachment(state, id)
+		}
+	}
+}
+
+// commentCountsOfGroup counts the comments of each item of a group that has
+// any. The caller must hold the state lock.
+func commentCountsOfGroup(state *memoryState, groupID string) map[string]int {
+	counts := make(map[string]int)
+	for _, comment := range state.comments {
+		if comment.GroupID == groupID {
+			counts[comment.ItemID]++
+		}
+	}
+	return counts
+}
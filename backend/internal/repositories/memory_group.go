@@ -0,0 +1,380 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// MemoryGroupRepository implements GroupRepository in memory
+type MemoryGroupRepository struct {
+	state *memoryState
+}
+
+// Create creates a new group
+func (r *MemoryGroupRepository) Create(ctx context.Context, group *models.Group) error {
+	if err := group.IsValid(); err != nil {
+		return fmt.Errorf("invalid group data: %w", err)
+	}
+
+	group.Sanitize()
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	if _, exists := r.state.groups[group.ID]; exists {
+		return fmt.Errorf("failed to create group: %w", ErrDuplicateID)
+	}
+	r.state.groups[group.ID] = cloneGroup(*group)
+
+	return nil
+}
+
+// GetByID retrieves a group by its ID
+func (r *MemoryGroupRepository) GetByID(ctx context.Context, id string) (*models.Group, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	group, exists := r.state.groups[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrGroupNotFound, id)
+	}
+
+	group = cloneGroup(group)
+	return &group, nil
+}
+
+// GetByUserID retrieves all groups created by a specific user
+func (r *MemoryGroupRepository) GetByUserID(ctx context.Context, userID string) ([]models.Group, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	var groups []models.Group
+	for _, group := range r.state.groups {
+		if group.CreatedBy == userID {
+			groups = append(groups, cloneGroup(group))
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].CreatedAt.After(groups[j].CreatedAt)
+	})
+	return groups, nil
+}
+
+// GetByDeadlineBetween retrieves the groups whose deadline is after after
+// and no later than until, soonest first
+func (r *MemoryGroupRepository) GetByDeadlineBetween(ctx context.Context, after, until time.Time) ([]models.Group, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	var groups []models.Group
+	for _, group := range r.state.groups {
+		if group.Deadline != nil && group.Deadline.After(after) && !group.Deadline.After(until) {
+			groups = append(groups, cloneGroup(group))
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if !groups[i].Deadline.Equal(*groups[j].Deadline) {
+			return groups[i].Deadline.Before(*groups[j].Deadline)
+		}
+		return groups[i].ID < groups[j].ID
+	})
+	return groups, nil
+}
+
+// Update updates an existing group
+func (r *MemoryGroupRepository) Update(ctx context.Context, group *models.Group) error {
+	if err := group.IsValid(); err != nil {
+		return fmt.Errorf("invalid group data: %w", err)
+	}
+
+	group.Sanitize()
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	existing, exists := r.state.groups[group.ID]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrGroupNotFound, group.ID)
+	}
+
+	existing.Name = group.Name
+	existing.Deadline = group.Deadline
+	existing.Timezone = group.Timezone
+	existing.LockAfterDeadline = group.LockAfterDeadline
+	r.state.groups[group.ID] = cloneGroup(existing)
+
+	return nil
+}
+
+// Delete deletes a group by ID, along with its members and items
+func (r *MemoryGroupRepository) Delete(ctx context.Context, id string) error {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	if _, exists := r.state.groups[id]; !exists {
+		return fmt.Errorf("%w: %s", ErrGroupNotFound, id)
+	}
+
+	delete(r.state.groups, id)
+	delete(r.state.calendarTokens, id)
+	delete(r.state.reminderDays, id)
+	for memberID, member := range r.state.members {
+		if member.GroupID == id {
+			delete(r.state.members, memberID)
+		}
+	}
+	for itemID, item := range r.state.items {
+		if item.GroupID == id {
+			delete(r.state.items, itemID)
+		}
+	}
+	for tagID, tag := range r.state.tags {
+		if tag.GroupID == id {
+			delete(r.state.tags, tagID)
+		}
+	}
+	deleteOrphans(r.state)
+
+	return nil
+}
+
+// GetWithDetails retrieves a group with all its members and items
+func (r *MemoryGroupRepository) GetWithDetails(ctx context.Context, id string) (*models.GroupWithDetails, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	group, exists := r.state.groups[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrGroupNotFound, id)
+	}
+
+	items := itemsOfGroup(r.state, id)
+	return &models.GroupWithDetails{
+		Group:         cloneGroup(group),
+		Members:       membersOfGroup(r.state, id),
+		Items:         items,
+		Tags:          tagsOfGroup(r.state, id),
+		CommentCounts: itemCommentCounts(items, commentCountsOfGroup(r.state, id)),
+		Reactions:     itemReactions(items, reactionsOfGroup(r.state, id)),
+		Attachments:   itemAttachments(items, attachmentsOfGroup(r.state, id)),
+	}, nil
+}
+
+// GetSummariesByUserID retrieves group summaries for a user's dashboard
+func (r *MemoryGroupRepository) GetSummariesByUserID(ctx context.Context, userID string) ([]models.GroupSummary, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	summaries := userSummaries(r.state, userID)
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].CreatedAt.After(summaries[j].CreatedAt)
+	})
+	return summaries, nil
+}
+
+// ListSummariesByUserID retrieves a filtered, sorted page of a user's group
+// summaries
+func (r *MemoryGroupRepository) ListSummariesByUserID(ctx context.Context, userID string, opts models.GroupSummaryListOptions) (*models.GroupSummaryPage, error) {
+	ks, err := groupKeyset(opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := ks.decode(opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	limit := models.PageLimit(opts.Limit)
+
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	var summaries []models.GroupSummary
+	for _, summary := range userSummaries(r.state, userID) {
+		if summaryMatches(summary, opts) && ks.follows(summarySortKey(ks, summary), summary.ID, cursor) {
+			summaries = append(summaries, summary)
+		}
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return ks.less(summarySortKey(ks, summaries[i]), summaries[i].ID, summarySortKey(ks, summaries[j]), summaries[j].ID)
+	})
+	if len(summaries) > limit+1 {
+		summaries = summaries[:limit+1]
+	}
+
+	return summaryPage(summaries, ks, limit), nil
+}
+
+// userSummaries returns the summaries of the groups a user created or is a
+// member of, in no particular order. The caller must hold the state lock.
+func userSummaries(state *memoryState, userID string) []models.GroupSummary {
+	var summaries []models.GroupSummary
+	for id := range userGroupIDs(state, userID) {
+		summary := models.GroupSummary{Group: cloneGroup(state.groups[id])}
+		for _, member := range state.members {
+			if member.GroupID == id {
+				summary.MemberCount++
+			}
+		}
+		for _, item := range state.items {
+			if item.GroupID == id {
+				summary.ItemCount++
+				if item.Completed {
+					summary.CompletedCount++
+				}
+			}
+		}
+
+		// Calculate progress percentage
+		if summary.ItemCount > 0 {
+			summary.ProgressPercent = float64(summary.CompletedCount) / float64(summary.ItemCount) * 100
+		}
+
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// SearchByUserID searches the names of the groups a user created or belongs to
+func (r *MemoryGroupRepository) SearchByUserID(ctx context.Context, userID, query string, limit int) ([]models.GroupSearchResult, error) {
+	matcher := newTextMatcher(query)
+
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	var results []models.GroupSearchResult
+	for id := range userGroupIDs(r.state, userID) {
+		group := r.state.groups[id]
+		if rank, ok := matcher.rank(group.Name, nil); ok {
+			results = append(results, matcher.groupSearchResult(cloneGroup(group), rank))
+		}
+	}
+
+	return sortGroupSearchResults(results, limit), nil
+}
+
+// userGroupIDs returns the IDs of the groups a user created or is a member
+// of. The caller must hold the state lock.
+func userGroupIDs(state *memoryState, userID string) map[string]bool {
+	groupIDs := make(map[string]bool)
+	for id, group := range state.groups {
+		if group.CreatedBy == userID {
+			groupIDs[id] = true
+		}
+	}
+	for _, member := range state.members {
+		if member.UserID != nil && *member.UserID == userID {
+			groupIDs[member.GroupID] = true
+		}
+	}
+	return groupIDs
+}
+
+// GetCalendarTokenHash returns the hash of the token of a group's calendar
+// feed
+func (r *MemoryGroupRepository) GetCalendarTokenHash(ctx context.Context, groupID string) (string, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	if _, exists := r.state.groups[groupID]; !exists {
+		return "", fmt.Errorf("%w: %s", ErrGroupNotFound, groupID)
+	}
+
+	return r.state.calendarTokens[groupID], nil
+}
+
+// EnsureCalendarTokenHash enables a group's calendar feed unless it already is
+func (r *MemoryGroupRepository) EnsureCalendarTokenHash(ctx context.Context, groupID, candidateHash string) (string, error) {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	if _, exists := r.state.groups[groupID]; !exists {
+		return "", fmt.Errorf("%w: %s", ErrGroupNotFound, groupID)
+	}
+
+	if tokenHash, exists := r.state.calendarTokens[groupID]; exists {
+		return tokenHash, nil
+	}
+	r.state.calendarTokens[groupID] = candidateHash
+
+	return candidateHash, nil
+}
+
+// RevokeCalendarToken disables a group's calendar feed
+func (r *MemoryGroupRepository) RevokeCalendarToken(ctx context.Context, groupID string) error {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	if _, exists := r.state.groups[groupID]; !exists {
+		return fmt.Errorf("%w: %s", ErrGroupNotFound, groupID)
+	}
+
+	delete(r.state.calendarTokens, groupID)
+
+	return nil
+}
+
+// GetReminderDays returns a group's reminder schedule
+func (r *MemoryGroupRepository) GetReminderDays(ctx context.Context, groupID string) ([]int, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	if _, exists := r.state.groups[groupID]; !exists {
+		return nil, fmt.Errorf("%w: %s", ErrGroupNotFound, groupID)
+	}
+
+	return models.NormalizeReminderDays(r.state.reminderDays[groupID]), nil
+}
+
+// SetReminderDays replaces a group's reminder schedule
+func (r *MemoryGroupRepository) SetReminderDays(ctx context.Context, groupID string, days []int) error {
+	if validation := models.ValidateReminderDays(days); !validation.IsValid {
+		return fmt.Errorf("invalid reminder days: %v", days)
+	}
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	if _, exists := r.state.groups[groupID]; !exists {
+		return fmt.Errorf("%w: %s", ErrGroupNotFound, groupID)
+	}
+
+	r.state.reminderDays[groupID] = models.NormalizeReminderDays(days)
+
+	return nil
+}
+
+// GetDueReminders retrieves the reminders falling due after after and no
+// later than until, earliest first
+func (r *MemoryGroupRepository) GetDueReminders(ctx context.Context, after, until time.Time) ([]models.DueReminder, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	var reminders []models.DueReminder
+	for groupID, days := range r.state.reminderDays {
+		group := cloneGroup(r.state.groups[groupID])
+		if group.Deadline == nil {
+			continue
+		}
+		for _, day := range days {
+			remindAt := models.RemindAt(*group.Deadline, day)
+			if remindAt.After(after) && !remindAt.After(until) {
+				reminders = append(reminders, models.DueReminder{
+					Group:      group,
+					DaysBefore: day,
+					RemindAt:   remindAt,
+				})
+			}
+		}
+	}
+
+	sortDueReminders(reminders)
+	return reminders, nil
+}
@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// MemoryMemberRepository implements MemberRepository in memory
+type MemoryMemberRepository struct {
+	state *memoryState
+}
+
+// Create creates a new member
+func (r *MemoryMemberRepository) Create(ctx context.Context, member *models.Member) error {
+	if err := member.IsValid(); err != nil {
+		return fmt.Errorf("invalid member data: %w", err)
+	}
+
+	member.Sanitize()
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	if _, exists := r.state.members[member.ID]; exists {
+		return fmt.Errorf("failed to create member: %w", ErrDuplicateID)
+	}
+	if _, exists := r.state.groups[member.GroupID]; !exists {
+		return fmt.Errorf("failed to create member: %w", ErrGroupNotFound)
+	}
+	if member.UserID != nil && userIsMember(r.state, member.GroupID, *member.UserID, member.ID) {
+		return fmt.Errorf("failed to create member: %w", ErrAlreadyMember)
+	}
+
+	r.state.members[member.ID] = cloneMember(*member)
+
+	return nil
+}
+
+// GetByID retrieves a member by their ID
+func (r *MemoryMemberRepository) GetByID(ctx context.Context, id string) (*models.Member, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	member, exists := r.state.members[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrMemberNotFound, id)
+	}
+
+	member = cloneMember(member)
+	return &member, nil
+}
+
+// GetByGroupID retrieves all members of a specific group
+func (r *MemoryMemberRepository) GetByGroupID(ctx context.Context, groupID string) ([]models.Member, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	return membersOfGroup(r.state, groupID), nil
+}
+
+// GetByUserID retrieves all memberships for a specific user
+func (r *MemoryMemberRepository) GetByUserID(ctx context.Context, userID string) ([]models.Member, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	var members []models.Member
+	for _, member := range r.state.members {
+		if member.UserID != nil && *member.UserID == userID {
+			members = append(members, cloneMember(member))
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].JoinedAt.After(members[j].JoinedAt)
+	})
+	return members, nil
+}
+
+// Update updates an existing member
+func (r *MemoryMemberRepository) Update(ctx context.Context, member *models.Member) error {
+	if err := member.IsValid(); err != nil {
+		return fmt.Errorf("invalid member data: %w", err)
+	}
+
+	member.Sanitize()
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	existing, exists := r.state.members[member.ID]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrMemberNotFound, member.ID)
+	}
+	if member.UserID != nil && userIsMember(r.state, existing.GroupID, *member.UserID, member.ID) {
+		return fmt.Errorf("failed to update member: %w", ErrAlreadyMember)
+	}
+
+	existing.Name = member.Name
+	existing.UserID = member.UserID
+	r.state.members[member.ID] = cloneMember(existing)
+
+	return nil
+}
+
+// Delete deletes a member by ID. Items the member added are deleted with
+// them; items they completed stay completed without a completer.
+func (r *MemoryMemberRepository) Delete(ctx context.Context, id string) error {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	if _, exists := r.state.members[id]; !exists {
+		return fmt.Errorf("%w: %s", ErrMemberNotFound, id)
+	}
+
+	delete(r.state.members, id)
+	for itemID, item := range r.state.items {
+		if item.CreatedBy != nil && *item.CreatedBy == id {
+			item.CreatedBy = nil
+		}
+		if item.CompletedBy != nil && *item.CompletedBy == id {
+			item.CompletedBy = nil
+		}
+		r.state.items[itemID] = item
+	}
+	deleteOrphans(r.state)
+
+	return nil
+}
+
+// ExistsByGroupAndUser checks if a user is already a member of a group
+func (r *MemoryMemberRepository) ExistsByGroupAndUser(ctx context.Context, groupID, userID string) (bool, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	return userIsMember(r.state, groupID, userID, ""), nil
+}
+
+// GetCreatorByGroupID retrieves the creator member of a group
+func (r *MemoryMemberRepository) GetCreatorByGroupID(ctx context.Context, groupID string) (*models.Member, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	for _, member := range r.state.members {
+		if member.GroupID == groupID && member.IsCreator {
+			member = cloneMember(member)
+			return &member, nil
+		}
+	}
+
+	return nil, fmt.Errorf("group creator not found for group: %s", groupID)
+}
+
+// userIsMember reports whether a user has a membership in a group other than
+// excludeID. The caller must hold the state lock.
+func userIsMember(state *memoryState, groupID, userID, excludeID string) bool {
+	for id, member := range state.members {
+		if id != excludeID && member.GroupID == groupID && member.UserID != nil && *member.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// membersOfGroup returns the members of a group, earliest joined first. The
+// caller must hold the state lock.
+func membersOfGroup(state *memoryState, groupID string) []models.Member {
+	var members []models.Member
+	for _, member := range state.members {
+		if member.GroupID == groupID {
+			members = append(members, cloneMember(member))
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].JoinedAt.Before(members[j].JoinedAt)
+	})
+	return members
+}
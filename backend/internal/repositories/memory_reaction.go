@@ -0,0 +1,173 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// voteKey identifies a member's vote for an item
+type voteKey struct {
+	itemID   string
+	memberID string
+}
+
+// reactionKey identifies a member's emoji reaction to an item
+type reactionKey struct {
+	itemID   string
+	memberID string
+	emoji    string
+}
+
+// MemoryReactionRepository implements ReactionRepository in memory
+type MemoryReactionRepository struct {
+	state *memoryState
+}
+
+// SetVote adds or removes a member's vote for an item
+func (r *MemoryReactionRepository) SetVote(ctx context.Context, itemID, memberID string, voted bool) error {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	key := voteKey{itemID: itemID, memberID: memberID}
+	if !voted {
+		delete(r.state.votes, key)
+		return nil
+	}
+
+	if err := checkReactionRefs(r.state, itemID, memberID); err != nil {
+		return fmt.Errorf("failed to add vote: %w", err)
+	}
+	if _, exists := r.state.votes[key]; !exists {
+		r.state.votes[key] = models.Vote{ItemID: itemID, MemberID: memberID, CreatedAt: memoryTime(time.Now())}
+	}
+
+	return nil
+}
+
+// AddReaction adds a member's emoji reaction to an item
+func (r *MemoryReactionRepository) AddReaction(ctx context.Context, reaction *models.Reaction) error {
+	if err := reaction.IsValid(); err != nil {
+		return fmt.Errorf("invalid reaction data: %w", err)
+	}
+
+	reaction.Sanitize()
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	if err := checkReactionRefs(r.state, reaction.ItemID, reaction.MemberID); err != nil {
+		return fmt.Errorf("failed to add reaction: %w", err)
+	}
+	key := reactionKey{itemID: reaction.ItemID, memberID: reaction.MemberID, emoji: reaction.Emoji}
+	if _, exists := r.state.reactions[key]; !exists {
+		added := *reaction
+		added.CreatedAt = memoryTime(added.CreatedAt)
+		r.state.reactions[key] = added
+	}
+
+	return nil
+}
+
+// RemoveReaction removes a member's emoji reaction from an item, if any
+func (r *MemoryReactionRepository) RemoveReaction(ctx context.Context, itemID, memberID, emoji string) error {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	delete(r.state.reactions, reactionKey{itemID: itemID, memberID: memberID, emoji: emoji})
+
+	return nil
+}
+
+// GetByItemID aggregates the votes and reactions of an item
+func (r *MemoryReactionRepository) GetByItemID(ctx context.Context, itemID string) (*models.ItemReactions, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	byItem := reactionsOf(r.state, func(id string) bool { return id == itemID })
+	aggregate, ok := byItem[itemID]
+	if !ok {
+		aggregate = emptyItemReactions(itemID)
+	}
+	return &aggregate, nil
+}
+
+// GetByGroupID aggregates the votes and reactions of each item of a group
+// that has any
+func (r *MemoryReactionRepository) GetByGroupID(ctx context.Context, groupID string) (map[string]models.ItemReactions, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	return reactionsOfGroup(r.state, groupID), nil
+}
+
+// checkReactionRefs checks that the item and member of a vote or reaction
+// exist. The caller must hold the state lock.
+func checkReactionRefs(state *memoryState, itemID, memberID string) error {
+	if _, exists := state.items[itemID]; !exists {
+		return fmt.Errorf("%w: %s", ErrItemNotFound, itemID)
+	}
+	if _, exists := state.members[memberID]; !exists {
+		return fmt.Errorf("%w: %s", ErrMemberNotFound, memberID)
+	}
+	return nil
+}
+
+// reactionsOfGroup aggregates the votes and reactions of each item of a
+// group that has any. The caller must hold the state lock.
+func reactionsOfGroup(state *memoryState, groupID string) map[string]models.ItemReactions {
+	return reactionsOf(state, func(itemID string) bool {
+		return state.items[itemID].GroupID == groupID
+	})
+}
+
+// reactionsOf aggregates the votes and reactions of the items an ID filter
+// accepts, in the order of the SQL stores. The caller must hold the state
+// lock.
+func reactionsOf(state *memoryState, accept func(itemID string) bool) map[string]models.ItemReactions {
+	var votes []models.Vote
+	for _, vote := range state.votes {
+		if accept(vote.ItemID) {
+			votes = append(votes, vote)
+		}
+	}
+	sort.Slice(votes, func(i, j int) bool {
+		if !votes[i].CreatedAt.Equal(votes[j].CreatedAt) {
+			return votes[i].CreatedAt.Before(votes[j].CreatedAt)
+		}
+		return votes[i].MemberID < votes[j].MemberID
+	})
+
+	var reactions []models.Reaction
+	for _, reaction := range state.reactions {
+		if accept(reaction.ItemID) {
+			reactions = append(reactions, reaction)
+		}
+	}
+	sort.Slice(reactions, func(i, j int) bool {
+		a, b := reactions[i], reactions[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		if c := strings.Compare(a.Emoji, b.Emoji); c != 0 {
+			return c < 0
+		}
+		return a.MemberID < b.MemberID
+	})
+
+	return aggregateReactions(votes, reactions)
+}
+
+// voteCounts returns the number of votes of every item that has any. The
+// caller must hold the state lock.
+func voteCounts(state *memoryState) map[string]int {
+	counts := make(map[string]int)
+	for key := range state.votes {
+		counts[key.itemID]++
+	}
+	return counts
+}
@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// MemoryTagRepository implements TagRepository in memory
+type MemoryTagRepository struct {
+	state *memoryState
+}
+
+// Create creates a new tag
+func (r *MemoryTagRepository) Create(ctx context.Context, tag *models.Tag) error {
+	if err := tag.IsValid(); err != nil {
+		return fmt.Errorf("invalid tag data: %w", err)
+	}
+
+	tag.Sanitize()
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	if _, exists := r.state.tags[tag.ID]; exists {
+		return fmt.Errorf("failed to create tag: %w", ErrDuplicateID)
+	}
+	if _, exists := r.state.groups[tag.GroupID]; !exists {
+		return fmt.Errorf("failed to create tag: %w", ErrGroupNotFound)
+	}
+	if tagNameTaken(r.state, *tag) {
+		return fmt.Errorf("failed to create tag: %w", ErrDuplicateTagName)
+	}
+
+	r.state.tags[tag.ID] = cloneTag(*tag)
+
+	return nil
+}
+
+// GetByID retrieves a tag by its ID
+func (r *MemoryTagRepository) GetByID(ctx context.Context, id string) (*models.Tag, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	tag, exists := r.state.tags[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrTagNotFound, id)
+	}
+
+	tag = cloneTag(tag)
+	return &tag, nil
+}
+
+// GetByGroupID retrieves all tags of a group, ordered by name
+func (r *MemoryTagRepository) GetByGroupID(ctx context.Context, groupID string) ([]models.Tag, error) {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	return tagsOfGroup(r.state, groupID), nil
+}
+
+// Update renames or recolors an existing tag
+func (r *MemoryTagRepository) Update(ctx context.Context, tag *models.Tag) error {
+	if err := tag.IsValid(); err != nil {
+		return fmt.Errorf("invalid tag data: %w", err)
+	}
+
+	tag.Sanitize()
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	existing, exists := r.state.tags[tag.ID]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrTagNotFound, tag.ID)
+	}
+
+	existing.Name = tag.Name
+	existing.Color = tag.Color
+	if tagNameTaken(r.state, existing) {
+		return fmt.Errorf("failed to update tag: %w", ErrDuplicateTagName)
+	}
+	r.state.tags[tag.ID] = cloneTag(existing)
+
+	return nil
+}
+
+// Delete deletes a tag by ID, removing it from every item
+func (r *MemoryTagRepository) Delete(ctx context.Context, id string) error {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	if _, exists := r.state.tags[id]; !exists {
+		return fmt.Errorf("%w: %s", ErrTagNotFound, id)
+	}
+
+	delete(r.state.tags, id)
+	for itemID, item := range r.state.items {
+		for i, tagID := range item.TagIDs {
+			if tagID == id {
+				item.TagIDs = append(item.TagIDs[:i:i], item.TagIDs[i+1:]...)
+				r.state.items[itemID] = item
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetItemTags replaces the tags of an item
+func (r *MemoryTagRepository) SetItemTags(ctx context.Context, itemID string, tagIDs []string) error {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	item, exists := r.state.items[itemID]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrItemNotFound, itemID)
+	}
+
+	tagIDs = uniqueTagIDs(tagIDs)
+	for _, tagID := range tagIDs {
+		if tag, exists := r.state.tags[tagID]; !exists || tag.GroupID != item.GroupID {
+			return fmt.Errorf("failed to set item tags: %w", ErrTagNotFound)
+		}
+	}
+
+	item.TagIDs = nil
+	if len(tagIDs) > 0 {
+		item.TagIDs = tagIDs
+	}
+	r.state.items[itemID] = item
+
+	return nil
+}
+
+// tagNameTaken reports whether another tag of the tag's group has the same
+// name, ignoring case. The caller must hold the state lock.
+func tagNameTaken(state *memoryState, tag models.Tag) bool {
+	for _, other := range state.tags {
+		if other.ID != tag.ID && other.GroupID == tag.GroupID && strings.EqualFold(other.Name, tag.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// tagsOfGroup returns the tags of a group, ordered by name. The caller must
+// hold the state lock.
+func tagsOfGroup(state *memoryState, groupID string) []models.Tag {
+	var tags []models.Tag
+	for _, tag := range state.tags {
+		if tag.GroupID == groupID {
+			tags = append(tags, cloneTag(tag))
+		}
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		iName, jName := strings.ToLower(tags[i].Name), strings.ToLower(tags[j].Name)
+		if iName != jName {
+			return iName < jName
+		}
+		return tags[i].ID < tags[j].ID
+	})
+	return tags
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepositoryManager_ConcurrentAccess(t *testing.T) {
+	repos := NewMemoryRepositoryManager()
+	ctx := context.Background()
+	group, creator := seedGroup(t, repos)
+
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			member := createTestMember(group.ID)
+			assert.NoError(t, repos.Members().Create(ctx, member))
+
+			item := createTestBucketItem(group.ID, member.ID)
+			assert.NoError(t, repos.BucketItems().Create(ctx, item))
+			assert.NoError(t, repos.BucketItems().ToggleCompletion(ctx, item.ID, creator.ID, i%2 == 0))
+
+			_, err := repos.Groups().GetWithDetails(ctx, group.ID)
+			assert.NoError(t, err)
+
+			// Transactions interleave with plain writes
+			assert.NoError(t, repos.WithTx(ctx, func(tx RepositoryManager) error {
+				return tx.Members().Create(ctx, createTestMember(group.ID))
+			}))
+		}(i)
+	}
+	wg.Wait()
+
+	members, err := repos.Members().GetByGroupID(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Len(t, members, 1+2*workers)
+
+	total, completed, err := repos.BucketItems().GetCompletionStats(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, workers, total)
+	assert.Equal(t, workers/2, completed)
+}
@@ -0,0 +1,440 @@
+package repositories
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// sortKeyKind is the type of the value a listing is sorted by
+type sortKeyKind int
+
+const (
+	sortKeyTime sortKeyKind = iota
+	sortKeyText
+	sortKeyFloat
+	sortKeyInt
+)
+
+// noDeadline stands in for a missing deadline when sorting by deadline, so
+// groups without one sort after every other group
+var noDeadline = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// keyset is an order a listing is paged by: a sort key, with the record ID
+// breaking ties. Pages continue after the key and ID of the last record of
+// the previous page, so inserts and deletes never shift a page.
+type keyset struct {
+	sort string
+	kind sortKeyKind
+	desc bool
+}
+
+// itemKeysets are the keysets of the item sorts
+var itemKeysets = map[models.ItemSort]keyset{
+	models.ItemSortNewest:     {sort: string(models.ItemSortNewest), kind: sortKeyTime, desc: true},
+	models.ItemSortOldest:     {sort: string(models.ItemSortOldest), kind: sortKeyTime},
+	models.ItemSortTitle:      {sort: string(models.ItemSortTitle), kind: sortKeyText},
+	models.ItemSortMostWanted: {sort: string(models.ItemSortMostWanted), kind: sortKeyInt, desc: true},
+}
+
+// groupKeysets are the keysets of the group summary sorts
+var groupKeysets = map[models.GroupSort]keyset{
+	models.GroupSortNewest:   {sort: string(models.GroupSortNewest), kind: sortKeyTime, desc: true},
+	models.GroupSortDeadline: {sort: string(models.GroupSortDeadline), kind: sortKeyTime},
+	models.GroupSortProgress: {sort: string(models.GroupSortProgress), kind: sortKeyFloat, desc: true},
+}
+
+// itemKeyset returns the keyset of an item sort
+func itemKeyset(sort models.ItemSort) (keyset, error) {
+	ks, ok := itemKeysets[sort.SortOrDefault()]
+	if !ok {
+		return keyset{}, fmt.Errorf("unknown item sort: %s", sort)
+	}
+	return ks, nil
+}
+
+// groupKeyset returns the keyset of a group summary sort
+func groupKeyset(sort models.GroupSort) (keyset, error) {
+	ks, ok := groupKeysets[sort.SortOrDefault()]
+	if !ok {
+		return keyset{}, fmt.Errorf("unknown group sort: %s", sort)
+	}
+	return ks, nil
+}
+
+// pageCursor is the decoded position a page starts after
+type pageCursor struct {
+	key interface{}
+	id  string
+}
+
+// encodedCursor is the JSON inside an opaque cursor
+type encodedCursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   string `json:"i"`
+}
+
+// encode returns the opaque cursor for a page continuing after a record
+func (k keyset) encode(key interface{}, id string) string {
+	var text string
+	switch v := key.(type) {
+	case time.Time:
+		text = v.UTC().Format(time.RFC3339Nano)
+	case float64:
+		text = strconv.FormatFloat(v, 'g', -1, 64)
+	case int64:
+		text = strconv.FormatInt(v, 10)
+	case string:
+		text = v
+	}
+
+	data, _ := json.Marshal(encodedCursor{Sort: k.sort, Key: text, ID: id})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decode parses an opaque cursor. An empty cursor decodes to nil; cursors
+// that are malformed or were issued for another sort are ErrInvalidCursor.
+func (k keyset) decode(cursor string) (*pageCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var encoded encodedCursor
+	if err := json.Unmarshal(data, &encoded); err != nil || encoded.Sort != k.sort || encoded.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	decoded := &pageCursor{id: encoded.ID}
+	switch k.kind {
+	case sortKeyTime:
+		decoded.key, err = time.Parse(time.RFC3339Nano, encoded.Key)
+	case sortKeyFloat:
+		decoded.key, err = strconv.ParseFloat(encoded.Key, 64)
+	case sortKeyInt:
+		decoded.key, err = strconv.ParseInt(encoded.Key, 10, 64)
+	default:
+		decoded.key = encoded.Key
+	}
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return decoded, nil
+}
+
+// trimPage cuts records fetched one past the limit down to the page and
+// returns the cursor of the next page, or "" if this is the last page
+func trimPage[T any](records []T, limit int, ks keyset, position func(i int) (key interface{}, id string)) ([]T, string) {
+	if records == nil {
+		records = []T{}
+	}
+	if len(records) <= limit {
+		return records, ""
+	}
+	key, id := position(limit - 1)
+	return records[:limit], ks.encode(key, id)
+}
+
+// compareSortKeys orders two keys of the same kind
+func compareSortKeys(a, b interface{}) int {
+	switch a := a.(type) {
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	case float64:
+		b := b.(float64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	case int64:
+		return cmp.Compare(a, b.(int64))
+	default:
+		return strings.Compare(a.(string), b.(string))
+	}
+}
+
+// less reports whether record a comes before record b
+func (k keyset) less(aKey interface{}, aID string, bKey interface{}, bID string) bool {
+	c := compareSortKeys(aKey, bKey)
+	if c == 0 {
+		c = strings.Compare(aID, bID)
+	}
+	if k.desc {
+		return c > 0
+	}
+	return c < 0
+}
+
+// follows reports whether a record comes after the cursor
+func (k keyset) follows(key interface{}, id string, cursor *pageCursor) bool {
+	return cursor == nil || k.less(cursor.key, cursor.id, key, id)
+}
+
+// sqlCondition is the condition selecting the rows after the cursor, given
+// the sort key and ID expressions and the placeholders of the cursor values
+func (k keyset) sqlCondition(keyExpr, idExpr, keyArg, idArg string) string {
+	op := ">"
+	if k.desc {
+		op = "<"
+	}
+	return fmt.Sprintf("(%s, %s) %s (%s, %s)", keyExpr, idExpr, op, keyArg, idArg)
+}
+
+// sqlOrder is the ORDER BY list of the keyset
+func (k keyset) sqlOrder(keyExpr, idExpr string) string {
+	dir := "ASC"
+	if k.desc {
+		dir = "DESC"
+	}
+	return fmt.Sprintf("%s %s, %s %s", keyExpr, dir, idExpr, dir)
+}
+
+// sqlQueryBuilder collects the conditions and arguments of a listing query,
+// converting arguments to the form the database stores them in
+type sqlQueryBuilder struct {
+	conditions  []string
+	args        []interface{}
+	placeholder func(n int) string
+	convertTime func(t time.Time) interface{}
+	convertID   func(id string) interface{}
+}
+
+func newPostgresQueryBuilder() *sqlQueryBuilder {
+	return &sqlQueryBuilder{
+		placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+		convertTime: func(t time.Time) interface{} { return t },
+		convertID:   func(id string) interface{} { return id },
+	}
+}
+
+func newSQLiteQueryBuilder() *sqlQueryBuilder {
+	return &sqlQueryBuilder{
+		placeholder: func(n int) string { return "?" + strconv.Itoa(n) },
+		convertTime: func(t time.Time) interface{} { return sqliteTime(t) },
+		convertID:   func(id string) interface{} { return sqliteID(id) },
+	}
+}
+
+// arg adds an argument and returns its placeholder
+func (b *sqlQueryBuilder) arg(value interface{}) string {
+	if t, ok := value.(time.Time); ok {
+		value = b.convertTime(t)
+	}
+	b.args = append(b.args, value)
+	return b.placeholder(len(b.args))
+}
+
+// idArg adds an ID argument and returns its placeholder
+func (b *sqlQueryBuilder) idArg(id string) string {
+	b.args = append(b.args, b.convertID(id))
+	return b.placeholder(len(b.args))
+}
+
+// where adds a condition
+func (b *sqlQueryBuilder) where(condition string) {
+	b.conditions = append(b.conditions, condition)
+}
+
+// whereClause joins the conditions
+func (b *sqlQueryBuilder) whereClause() string {
+	if len(b.conditions) == 0 {
+		return "TRUE"
+	}
+	return strings.Join(b.conditions, " AND ")
+}
+
+// itemSortExpressions are the SQL sort keys of the item sorts
+var itemSortExpressions = map[string]string{
+	string(models.ItemSortNewest):     "created_at",
+	string(models.ItemSortOldest):     "created_at",
+	string(models.ItemSortTitle):      "lower(title)",
+	string(models.ItemSortMostWanted): "(SELECT COUNT(*) FROM item_votes WHERE item_votes.item_id = bucket_items.id)",
+}
+
+// buildItemListQuery builds the query for a page of a group's items, which
+// selects the given columns followed by the sort key. It fetches one row
+// more than the limit to tell whether another page follows.
+func buildItemListQuery(b *sqlQueryBuilder, columns, groupID string, opts models.ItemListOptions, ks keyset, cursor *pageCursor, limit int) string {
+	sortExpr := itemSortExpressions[ks.sort]
+
+	b.where("group_id = " + b.idArg(groupID))
+	if opts.Completed != nil {
+		b.where("completed = " + b.arg(*opts.Completed))
+	}
+	if opts.CreatedBy != "" {
+		b.where("created_by = " + b.idArg(opts.CreatedBy))
+	}
+	if opts.TagID != "" {
+		b.where("id IN (SELECT item_id FROM item_tags WHERE tag_id = " + b.idArg(opts.TagID) + ")")
+	}
+	if opts.AssignedTo != "" {
+		b.where("id IN (SELECT item_id FROM item_assignees WHERE member_id = " + b.idArg(opts.AssignedTo) + ")")
+	}
+	if opts.CreatedAfter != nil {
+		b.where("created_at >= " + b.arg(*opts.CreatedAfter))
+	}
+	if opts.CreatedBefore != nil {
+		b.where("created_at < " + b.arg(*opts.CreatedBefore))
+	}
+	if cursor != nil {
+		b.where(ks.sqlCondition(sortExpr, "id", b.arg(cursor.key), b.idArg(cursor.id)))
+	}
+
+	return `SELECT ` + columns + `, ` + sortExpr + `
+		FROM bucket_items
+		WHERE ` + b.whereClause() + `
+		ORDER BY ` + ks.sqlOrder(sortExpr, "id") + `
+		LIMIT ` + b.arg(limit+1)
+}
+
+// itemPage assembles a page from the items and sort keys fetched by a query
+// from buildItemListQuery
+func itemPage(items []models.BucketListItem, sortKeys []string, ks keyset, limit int) *models.ItemPage {
+	page := &models.ItemPage{}
+	page.Items, page.NextCursor = trimPage(items, limit, ks, func(i int) (interface{}, string) {
+		return itemSortKey(ks, items[i], sortKeys[i]), items[i].ID
+	})
+	return page
+}
+
+// summaryPage assembles a page from the summaries fetched by a query from
+// buildSummaryListQuery
+func summaryPage(summaries []models.GroupSummary, ks keyset, limit int) *models.GroupSummaryPage {
+	page := &models.GroupSummaryPage{}
+	page.Groups, page.NextCursor = trimPage(summaries, limit, ks, func(i int) (interface{}, string) {
+		return summarySortKey(ks, summaries[i]), summaries[i].ID
+	})
+	return page
+}
+
+// itemSortKey is the sort key of an item. Title keys and vote counts come
+// from the database as text, since it lowercases titles and counts votes
+// itself.
+func itemSortKey(ks keyset, item models.BucketListItem, sortKey string) interface{} {
+	switch ks.kind {
+	case sortKeyText:
+		return sortKey
+	case sortKeyInt:
+		votes, _ := strconv.ParseInt(sortKey, 10, 64)
+		return votes
+	}
+	return item.CreatedAt
+}
+
+// buildSummaryListQuery builds the query for a page of a user's group
+// summaries. summaries is a query selecting the group columns and the
+// member_count, item_count and completed_count of each group, progress the
+// expression computing the progress percentage from them. The query selects
+// the group columns, counts and progress.
+func buildSummaryListQuery(b *sqlQueryBuilder, summaries, progress string, opts models.GroupSummaryListOptions, ks keyset, cursor *pageCursor, limit int) string {
+	var sortExpr string
+	switch models.GroupSort(ks.sort) {
+	case models.GroupSortDeadline:
+		sortExpr = "COALESCE(deadline, " + b.arg(noDeadline) + ")"
+	case models.GroupSortProgress:
+		sortExpr = "progress"
+	default:
+		sortExpr = "created_at"
+	}
+
+	if opts.DeadlineAfter != nil {
+		b.where("deadline >= " + b.arg(*opts.DeadlineAfter))
+	}
+	if opts.DeadlineBefore != nil {
+		b.where("deadline < " + b.arg(*opts.DeadlineBefore))
+	}
+	if opts.MinProgress != nil {
+		b.where("progress >= " + b.arg(*opts.MinProgress))
+	}
+	if opts.MaxProgress != nil {
+		b.where("progress <= " + b.arg(*opts.MaxProgress))
+	}
+	if cursor != nil {
+		b.where(ks.sqlCondition(sortExpr, "id", b.arg(cursor.key), b.idArg(cursor.id)))
+	}
+
+	return `
+		WITH summaries AS (` + summaries + `),
+		ranked AS (
+			SELECT id, name, deadline, timezone, lock_after_deadline, created_at, created_by,
+				member_count, item_count, completed_count,
+				CASE WHEN item_count > 0 THEN ` + progress + ` ELSE 0 END AS progress
+			FROM summaries
+		)
+		SELECT id, name, deadline, timezone, lock_after_deadline, created_at, created_by,
+			member_count, item_count, completed_count, progress
+		FROM ranked
+		WHERE ` + b.whereClause() + `
+		ORDER BY ` + ks.sqlOrder(sortExpr, "id") + `
+		LIMIT ` + b.arg(limit+1)
+}
+
+// summarySortKey is the sort key of a group summary
+func summarySortKey(ks keyset, summary models.GroupSummary) interface{} {
+	switch models.GroupSort(ks.sort) {
+	case models.GroupSortDeadline:
+		if summary.Deadline == nil {
+			return noDeadline
+		}
+		return *summary.Deadline
+	case models.GroupSortProgress:
+		return summary.ProgressPercent
+	default:
+		return summary.CreatedAt
+	}
+}
+
+// summaryMatches reports whether a summary passes the filters of opts
+func summaryMatches(summary models.GroupSummary, opts models.GroupSummaryListOptions) bool {
+	if opts.DeadlineAfter != nil && (summary.Deadline == nil || summary.Deadline.Before(*opts.DeadlineAfter)) {
+		return false
+	}
+	if opts.DeadlineBefore != nil && (summary.Deadline == nil || !summary.Deadline.Before(*opts.DeadlineBefore)) {
+		return false
+	}
+	if opts.MinProgress != nil && summary.ProgressPercent < *opts.MinProgress {
+		return false
+	}
+	if opts.MaxProgress != nil && summary.ProgressPercent > *opts.MaxProgress {
+		return false
+	}
+	return true
+}
+
+// itemMatches reports whether an item passes the filters of opts
+func itemMatches(item models.BucketListItem, opts models.ItemListOptions) bool {
+	if opts.Completed != nil && item.Completed != *opts.Completed {
+		return false
+	}
+	if opts.CreatedBy != "" && (item.CreatedBy == nil || *item.CreatedBy != opts.CreatedBy) {
+		return false
+	}
+	if opts.TagID != "" && !slices.Contains(item.TagIDs, opts.TagID) {
+		return false
+	}
+	if opts.AssignedTo != "" && !slices.Contains(item.AssigneeIDs, opts.AssignedTo) {
+		return false
+	}
+	if opts.CreatedAfter != nil && item.CreatedAt.Before(*opts.CreatedAfter) {
+		return false
+	}
+	if opts.CreatedBefore != nil && !item.CreatedAt.Before(*opts.CreatedBefore) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeysetCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 123456789, time.UTC)
+
+	tests := []struct {
+		name string
+		ks   keyset
+		key  interface{}
+	}{
+		{"time", itemKeysets[models.ItemSortNewest], createdAt},
+		{"text", itemKeysets[models.ItemSortTitle], "visit paris"},
+		{"float", groupKeysets[models.GroupSortProgress], 100.0 / 3},
+		{"int", itemKeysets[models.ItemSortMostWanted], int64(42)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := tt.ks.decode(tt.ks.encode(tt.key, "id-1"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.key, cursor.key)
+			assert.Equal(t, "id-1", cursor.id)
+		})
+	}
+}
+
+func TestKeysetDecodeInvalid(t *testing.T) {
+	newest := itemKeysets[models.ItemSortNewest]
+
+	cursor, err := newest.decode("")
+	assert.NoError(t, err)
+	assert.Nil(t, cursor)
+
+	for _, invalid := range []string{
+		"%%%",
+		"bm90IGpzb24",
+		itemKeysets[models.ItemSortOldest].encode(time.Now(), "id-1"),
+		itemKeysets[models.ItemSortTitle].encode("not a time", "id-1"),
+		newest.encode(time.Now(), ""),
+	} {
+		_, err := newest.decode(invalid)
+		assert.ErrorIs(t, err, ErrInvalidCursor, invalid)
+	}
+}
+
+func TestKeysetSQL(t *testing.T) {
+	newest := itemKeysets[models.ItemSortNewest]
+	assert.Equal(t, "(created_at, id) < ($2, $3)", newest.sqlCondition("created_at", "id", "$2", "$3"))
+	assert.Equal(t, "created_at DESC, id DESC", newest.sqlOrder("created_at", "id"))
+
+	title := itemKeysets[models.ItemSortTitle]
+	assert.Equal(t, "(lower(title), id) > (?2, ?3)", title.sqlCondition("lower(title)", "id", "?2", "?3"))
+	assert.Equal(t, "lower(title) ASC, id ASC", title.sqlOrder("lower(title)", "id"))
+
+	mostWanted := itemKeysets[models.ItemSortMostWanted]
+	assert.Equal(t, "votes DESC, id DESC", mostWanted.sqlOrder("votes", "id"))
+}
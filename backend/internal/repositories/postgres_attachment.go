@@ -0,0 +1,242 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"collaborative-bucket-list/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// PostgresAttachmentRepository implements AttachmentRepository for PostgreSQL
+type PostgresAttachmentRepository struct {
+	db dbExecutor
+}
+
+// NewPostgresAttachmentRepository creates a new PostgreSQL attachment repository
+func NewPostgresAttachmentRepository(db dbExecutor) *PostgresAttachmentRepository {
+	return &PostgresAttachmentRepository{db: db}
+}
+
+// Create records a new attachment, taking its group from its item
+func (r *PostgresAttachmentRepository) Create(ctx context.Context, attachment *models.Attachment) error {
+	if err := attachment.IsValid(); err != nil {
+		return fmt.Errorf("invalid attachment data: %w", err)
+	}
+
+	attachment.Sanitize()
+
+	query := `
+		INSERT INTO attachments (id, item_id, group_id, member_id, file_name, content_type, size,
+			width, height, processing_status, storage_key, created_at)
+		SELECT $1::uuid, id, group_id, $3::uuid, $4::text, $5::text, $6::bigint,
+			$7::integer, $8::integer, $9::text, $10::text, $11::timestamptz
+		FROM bucket_items
+		WHERE id = $2
+		RETURNING group_id`
+
+	err := r.db.QueryRowContext(ctx, query, attachment.ID, attachment.ItemID, attachment.MemberID,
+		attachment.FileName, attachment.ContentType, attachment.Size, attachment.Width, attachment.Height,
+		attachment.ProcessingStatus, attachment.StorageKey, attachment.CreatedAt).Scan(&attachment.GroupID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("failed to create attachment: %w", ErrItemNotFound)
+		}
+		return fmt.Errorf("failed to create attachment: %w", mapConstraintError(err))
+	}
+
+	return nil
+}
+
+// GetByID retrieves an attachment by its ID
+func (r *PostgresAttachmentRepository) GetByID(ctx context.Context, id string) (*models.Attachment, error) {
+	query := `SELECT ` + postgresAttachmentColumns + ` FROM attachments WHERE id = $1`
+
+	var attachment models.Attachment
+	err := scanPostgresAttachment(r.db.QueryRowContext(ctx, query, id), &attachment)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %s", ErrAttachmentNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return &attachment, nil
+}
+
+// GetByItemID retrieves the attachments of an item, oldest first
+func (r *PostgresAttachmentRepository) GetByItemID(ctx context.Context, itemID string) ([]models.Attachment, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+postgresAttachmentColumns+`
+		FROM attachments
+		WHERE item_id = $1
+		ORDER BY created_at ASC, id ASC`, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments by item ID: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []models.Attachment
+	for rows.Next() {
+		var attachment models.Attachment
+		if err := scanPostgresAttachment(rows, &attachment); err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// GetByGroupID retrieves the attachments of each item of a group that has
+// any, oldest first
+func (r *PostgresAttachmentRepository) GetByGroupID(ctx context.Context, groupID string) (map[string][]models.Attachment, error) {
+	return queryPostgresAttachments(ctx, r.db, groupID)
+}
+
+// GetPendingProcessing returns up to limit attachments that have not been
+// processed yet, oldest first
+func (r *PostgresAttachmentRepository) GetPendingProcessing(ctx context.Context, limit int) ([]models.Attachment, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+postgresAttachmentColumns+`
+		FROM attachments
+		WHERE processing_status = 'pending'
+		ORDER BY created_at ASC, id ASC
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []models.Attachment
+	for rows.Next() {
+		var attachment models.Attachment
+		if err := scanPostgresAttachment(rows, &attachment); err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// UpdateProcessing records the outcome of processing an attachment: its
+// size, dimensions and processing status
+func (r *PostgresAttachmentRepository) UpdateProcessing(ctx context.Context, attachment *models.Attachment) error {
+	if err := attachment.IsValid(); err != nil {
+		return fmt.Errorf("invalid attachment data: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE attachments
+		SET size = $2, width = $3, height = $4, processing_status = $5
+		WHERE id = $1`,
+		attachment.ID, attachment.Size, attachment.Width, attachment.Height, attachment.ProcessingStatus)
+	if err != nil {
+		return fmt.Errorf("failed to update attachment processing: %w", mapConstraintError(err))
+	}
+
+	return rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrAttachmentNotFound, attachment.ID))
+}
+
+// queryPostgresAttachments retrieves the attachments of each item of a
+// group that has any, oldest first
+func queryPostgresAttachments(ctx context.Context, db dbExecutor, groupID string) (map[string][]models.Attachment, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+postgresAttachmentColumns+`
+		FROM attachments
+		WHERE group_id = $1
+		ORDER BY created_at ASC, id ASC`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments by group ID: %w", err)
+	}
+	defer rows.Close()
+
+	byItem := make(map[string][]models.Attachment)
+	for rows.Next() {
+		var attachment models.Attachment
+		if err := scanPostgresAttachment(rows, &attachment); err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		byItem[attachment.ItemID] = append(byItem[attachment.ItemID], attachment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attachments: %w", err)
+	}
+
+	return byItem, nil
+}
+
+// Delete deletes an attachment by ID. A trigger queues its blob for
+// deletion.
+func (r *PostgresAttachmentRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM attachments WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	return rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrAttachmentNotFound, id))
+}
+
+// PendingBlobDeletions returns up to limit storage keys of deleted
+// attachments whose blobs still need deleting, oldest first
+func (r *PostgresAttachmentRepository) PendingBlobDeletions(ctx context.Context, limit int) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT storage_key
+		FROM blob_deletions
+		ORDER BY queued_at ASC, storage_key ASC
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending blob deletions: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan blob deletion: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating blob deletions: %w", err)
+	}
+
+	return keys, nil
+}
+
+// ClearBlobDeletions removes storage keys whose blobs have been deleted
+// from the queue
+func (r *PostgresAttachmentRepository) ClearBlobDeletions(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	_, err := r.db.ExecContext(ctx, `DELETE FROM blob_deletions WHERE storage_key = ANY($1::text[])`, pq.Array(keys))
+	if err != nil {
+		return fmt.Errorf("failed to clear blob deletions: %w", err)
+	}
+
+	return nil
+}
+
+const postgresAttachmentColumns = `id, item_id, group_id, member_id, file_name, content_type, size, width, height, processing_status, storage_key, created_at`
+
+func scanPostgresAttachment(row rowScanner, attachment *models.Attachment) error {
+	return row.Scan(&attachment.ID, &attachment.ItemID, &attachment.GroupID, &attachment.MemberID,
+		&attachment.FileName, &attachment.ContentType, &attachment.Size, &attachment.Width, &attachment.Height,
+		&attachment.ProcessingStatus, &attachment.StorageKey, &attachment.CreatedAt)
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"collaborative-bucket-list/internal/models"
+
+	"github.com/lib/pq"
 )
 
 // PostgresBucketItemRepository implements BucketItemRepository for PostgreSQL
@@ -31,14 +33,16 @@ func (r *PostgresBucketItemRepository) Create(ctx context.Context, item *models.
 
 	query := `
 		INSERT INTO bucket_items (id, group_id, title, description, completed, 
-								 completed_by, completed_at, created_by, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+								 completed_by, completed_at, completion_note, completion_rating,
+								 occurred_on, scheduled_start, scheduled_end, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
 
 	_, err := r.db.ExecContext(ctx, query,
 		item.ID, item.GroupID, item.Title, item.Description, item.Completed,
-		item.CompletedBy, item.CompletedAt, item.CreatedBy, item.CreatedAt)
+		item.CompletedBy, item.CompletedAt, item.CompletionNote, item.CompletionRating,
+		item.OccurredOn, item.ScheduledStart, item.ScheduledEnd, item.CreatedBy, item.CreatedAt)
 	if err != nil {
-		return fmt.Errorf("failed to create bucket item: %w", err)
+		return fmt.Errorf("failed to create bucket item: %w", mapConstraintError(err))
 	}
 
 	return nil
@@ -47,30 +51,31 @@ func (r *PostgresBucketItemRepository) Create(ctx context.Context, item *models.
 // GetByID retrieves a bucket list item by its ID
 func (r *PostgresBucketItemRepository) GetByID(ctx context.Context, id string) (*models.BucketListItem, error) {
 	query := `
-		SELECT id, group_id, title, description, completed, completed_by,
-			   completed_at, created_by, created_at
+		SELECT ` + postgresItemColumns + `
 		FROM bucket_items
 		WHERE id = $1`
 
 	var item models.BucketListItem
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&item.ID, &item.GroupID, &item.Title, &item.Description, &item.Completed,
-		&item.CompletedBy, &item.CompletedAt, &item.CreatedBy, &item.CreatedAt)
+	err := r.db.QueryRowContext(ctx, query, id).Scan(postgresItemFields(&item)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("bucket item not found: %s", id)
+			return nil, fmt.Errorf("%w: %s", ErrItemNotFound, id)
 		}
 		return nil, fmt.Errorf("failed to get bucket item: %w", err)
 	}
 
-	return &item, nil
+	items := []models.BucketListItem{item}
+	if err := loadPostgresItemLinks(ctx, r.db, items); err != nil {
+		return nil, err
+	}
+
+	return &items[0], nil
 }
 
 // GetByGroupID retrieves all items for a specific group
 func (r *PostgresBucketItemRepository) GetByGroupID(ctx context.Context, groupID string) ([]models.BucketListItem, error) {
 	query := `
-		SELECT id, group_id, title, description, completed, completed_by,
-			   completed_at, created_by, created_at
+		SELECT ` + postgresItemColumns + `
 		FROM bucket_items
 		WHERE group_id = $1
 		ORDER BY created_at DESC`
@@ -84,9 +89,7 @@ func (r *PostgresBucketItemRepository) GetByGroupID(ctx context.Context, groupID
 	var items []models.BucketListItem
 	for rows.Next() {
 		var item models.BucketListItem
-		err := rows.Scan(&item.ID, &item.GroupID, &item.Title, &item.Description,
-			&item.Completed, &item.CompletedBy, &item.CompletedAt, &item.CreatedBy, &item.CreatedAt)
-		if err != nil {
+		if err := rows.Scan(postgresItemFields(&item)...); err != nil {
 			return nil, fmt.Errorf("failed to scan bucket item: %w", err)
 		}
 		items = append(items, item)
@@ -96,9 +99,57 @@ func (r *PostgresBucketItemRepository) GetByGroupID(ctx context.Context, groupID
 		return nil, fmt.Errorf("error iterating bucket items: %w", err)
 	}
 
+	if err := loadPostgresItemLinks(ctx, r.db, items); err != nil {
+		return nil, err
+	}
+
 	return items, nil
 }
 
+// List retrieves a filtered, sorted page of a group's items
+func (r *PostgresBucketItemRepository) List(ctx context.Context, groupID string, opts models.ItemListOptions) (*models.ItemPage, error) {
+	ks, err := itemKeyset(opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := ks.decode(opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	limit := models.PageLimit(opts.Limit)
+
+	b := newPostgresQueryBuilder()
+	query := buildItemListQuery(b, postgresItemColumns, groupID, opts, ks, cursor, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bucket items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.BucketListItem
+	var sortKeys []string
+	for rows.Next() {
+		var item models.BucketListItem
+		var sortKey sql.NullString
+		if err := rows.Scan(append(postgresItemFields(&item), &sortKey)...); err != nil {
+			return nil, fmt.Errorf("failed to scan bucket item: %w", err)
+		}
+		items = append(items, item)
+		sortKeys = append(sortKeys, sortKey.String)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bucket items: %w", err)
+	}
+
+	if err := loadPostgresItemLinks(ctx, r.db, items); err != nil {
+		return nil, err
+	}
+
+	return itemPage(items, sortKeys, ks, limit), nil
+}
+
 // Update updates an existing bucket list item
 func (r *PostgresBucketItemRepository) Update(ctx context.Context, item *models.BucketListItem) error {
 	if err := item.IsValid(); err != nil {
@@ -109,13 +160,16 @@ func (r *PostgresBucketItemRepository) Update(ctx context.Context, item *models.
 
 	query := `
 		UPDATE bucket_items
-		SET title = $2, description = $3, completed = $4, completed_by = $5, completed_at = $6
+		SET title = $2, description = $3, completed = $4, completed_by = $5, completed_at = $6,
+			completion_note = $7, completion_rating = $8, occurred_on = $9,
+			scheduled_start = $10, scheduled_end = $11
 		WHERE id = $1`
 
 	result, err := r.db.ExecContext(ctx, query,
-		item.ID, item.Title, item.Description, item.Completed, item.CompletedBy, item.CompletedAt)
+		item.ID, item.Title, item.Description, item.Completed, item.CompletedBy, item.CompletedAt,
+		item.CompletionNote, item.CompletionRating, item.OccurredOn, item.ScheduledStart, item.ScheduledEnd)
 	if err != nil {
-		return fmt.Errorf("failed to update bucket item: %w", err)
+		return fmt.Errorf("failed to update bucket item: %w", mapConstraintError(err))
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -124,7 +178,7 @@ func (r *PostgresBucketItemRepository) Update(ctx context.Context, item *models.
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("bucket item not found: %s", item.ID)
+		return fmt.Errorf("%w: %s", ErrItemNotFound, item.ID)
 	}
 
 	return nil
@@ -145,7 +199,7 @@ func (r *PostgresBucketItemRepository) Delete(ctx context.Context, id string) er
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("bucket item not found: %s", id)
+		return fmt.Errorf("%w: %s", ErrItemNotFound, id)
 	}
 
 	return nil
@@ -167,14 +221,76 @@ func (r *PostgresBucketItemRepository) ToggleCompletion(ctx context.Context, ite
 		// Mark as not completed
 		query = `
 			UPDATE bucket_items
-			SET completed = false, completed_by = NULL, completed_at = NULL
+			SET completed = false, completed_by = NULL, completed_at = NULL,
+				completion_note = NULL, completion_rating = NULL, occurred_on = NULL
 			WHERE id = $1`
 		args = []interface{}{itemID}
 	}
 
 	result, err := r.db.ExecContext(ctx, query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to toggle completion status: %w", err)
+		return fmt.Errorf("failed to toggle completion status: %w", mapConstraintError(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: %s", ErrItemNotFound, itemID)
+	}
+
+	return nil
+}
+
+// UpdateCompletionJournal replaces the journal of a completed item
+func (r *PostgresBucketItemRepository) UpdateCompletionJournal(ctx context.Context, itemID string, journal models.CompletionJournal) error {
+	journal.Sanitize()
+	if validation := journal.Validate(); !validation.IsValid {
+		return fmt.Errorf("invalid completion journal: %s", validation.Errors[0].Message)
+	}
+
+	query := `
+		UPDATE bucket_items
+		SET completion_note = $2, completion_rating = $3, occurred_on = $4
+		WHERE id = $1 AND completed`
+
+	result, err := r.db.ExecContext(ctx, query,
+		itemID, journal.CompletionNote, journal.CompletionRating, journal.OccurredOn)
+	if err != nil {
+		return fmt.Errorf("failed to update completion journal: %w", mapConstraintError(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		if _, err := r.GetByID(ctx, itemID); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: %s", ErrItemNotCompleted, itemID)
+	}
+
+	return nil
+}
+
+// UpdateSchedule replaces the schedule of an item
+func (r *PostgresBucketItemRepository) UpdateSchedule(ctx context.Context, itemID string, schedule models.ItemSchedule) error {
+	if validation := schedule.Validate(nil); !validation.IsValid {
+		return fmt.Errorf("invalid item schedule: %s", validation.Errors[0].Message)
+	}
+
+	query := `
+		UPDATE bucket_items
+		SET scheduled_start = $2, scheduled_end = $3
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, itemID, schedule.ScheduledStart, schedule.ScheduledEnd)
+	if err != nil {
+		return fmt.Errorf("failed to update item schedule: %w", mapConstraintError(err))
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -183,12 +299,78 @@ func (r *PostgresBucketItemRepository) ToggleCompletion(ctx context.Context, ite
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("bucket item not found: %s", itemID)
+		return fmt.Errorf("%w: %s", ErrItemNotFound, itemID)
 	}
 
 	return nil
 }
 
+// SetAssignment assigns a member to organize an item or removes the
+// assignment
+func (r *PostgresBucketItemRepository) SetAssignment(ctx context.Context, itemID, memberID string, assigned bool) error {
+	if !assigned {
+		_, err := r.db.ExecContext(ctx, `DELETE FROM item_assignees WHERE item_id = $1 AND member_id = $2`, itemID, memberID)
+		if err != nil {
+			return fmt.Errorf("failed to unassign item: %w", err)
+		}
+		return nil
+	}
+
+	err := checkAssignable(ctx, r.db, itemID, `
+		SELECT (SELECT group_id FROM bucket_items WHERE id = $1),
+			   (SELECT group_id FROM members WHERE id = $2)`, itemID, memberID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO item_assignees (item_id, member_id, assigned_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (item_id, member_id) DO NOTHING`
+
+	_, err = r.db.ExecContext(ctx, query, itemID, memberID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to assign item: %w", mapConstraintError(err))
+	}
+
+	return nil
+}
+
+// GetAssignedTo retrieves the items assigned to any of the members, newest
+// first
+func (r *PostgresBucketItemRepository) GetAssignedTo(ctx context.Context, memberIDs []string) ([]models.BucketListItem, error) {
+	query := `
+		SELECT ` + postgresItemColumns + `
+		FROM bucket_items
+		WHERE id IN (SELECT item_id FROM item_assignees WHERE member_id = ANY($1::uuid[]))
+		ORDER BY created_at DESC, id`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(memberIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assigned items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.BucketListItem
+	for rows.Next() {
+		var item models.BucketListItem
+		if err := rows.Scan(postgresItemFields(&item)...); err != nil {
+			return nil, fmt.Errorf("failed to scan bucket item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bucket items: %w", err)
+	}
+
+	if err := loadPostgresItemLinks(ctx, r.db, items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
 // GetCompletionStats returns completion statistics for a group
 func (r *PostgresBucketItemRepository) GetCompletionStats(ctx context.Context, groupID string) (total, completed int, err error) {
 	query := `
@@ -204,4 +386,197 @@ func (r *PostgresBucketItemRepository) GetCompletionStats(ctx context.Context, g
 	}
 
 	return total, completed, nil
-}
\ No newline at end of file
+}
+
+// GetCompletionStatsByTag returns completion statistics for every tag of a
+// group
+func (r *PostgresBucketItemRepository) GetCompletionStatsByTag(ctx context.Context, groupID string) ([]models.TagStats, error) {
+	query := `
+		SELECT
+			t.id, t.group_id, t.name, t.color, t.created_at,
+			COUNT(bi.id) as item_count,
+			COUNT(CASE WHEN bi.completed = true THEN 1 END) as completed_count
+		FROM tags t
+		LEFT JOIN item_tags it ON it.tag_id = t.id
+		LEFT JOIN bucket_items bi ON bi.id = it.item_id
+		WHERE t.group_id = $1
+		GROUP BY t.id, t.group_id, t.name, t.color, t.created_at
+		ORDER BY lower(t.name), t.id`
+
+	rows, err := r.db.QueryContext(ctx, query, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag completion stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []models.TagStats
+	for rows.Next() {
+		var tagStats models.TagStats
+		err := rows.Scan(&tagStats.ID, &tagStats.GroupID, &tagStats.Name, &tagStats.Color,
+			&tagStats.CreatedAt, &tagStats.ItemCount, &tagStats.CompletedCount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tag completion stats: %w", err)
+		}
+		stats = append(stats, tagStats)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tag completion stats: %w", err)
+	}
+
+	setTagProgress(stats)
+	return stats, nil
+}
+
+// Search finds the items of a group matching a full-text query
+func (r *PostgresBucketItemRepository) Search(ctx context.Context, groupID, query string, limit int) ([]models.ItemSearchResult, error) {
+	return r.search(ctx, `bi.group_id = $1`, groupID, query, limit)
+}
+
+// SearchByUserID finds matching items in every group a user created or
+// belongs to
+func (r *PostgresBucketItemRepository) SearchByUserID(ctx context.Context, userID, query string, limit int) ([]models.ItemSearchResult, error) {
+	return r.search(ctx, `(g.created_by = $1 OR g.id IN (SELECT group_id FROM members WHERE user_id = $1))`,
+		userID, query, limit)
+}
+
+// search runs a ranked prefix query over the items selected by scope, which
+// refers to its argument as $1
+func (r *PostgresBucketItemRepository) search(ctx context.Context, scope, scopeArg, query string, limit int) ([]models.ItemSearchResult, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	sqlQuery := `
+		SELECT ` + qualifyColumns("bi", postgresItemColumns) + `, g.name,
+			   ts_rank_cd(bi.search_vector, q) AS rank,
+			   ts_headline('english', ` + postgresStripMarkers("bi.title") + `, q, $3),
+			   CASE WHEN bi.description IS NOT NULL
+			        THEN ts_headline('english', ` + postgresStripMarkers("bi.description") + `, q, $4)
+			   END
+		FROM bucket_items bi
+		JOIN groups g ON g.id = bi.group_id
+		CROSS JOIN to_tsquery('english', $2) AS q
+		WHERE ` + scope + ` AND bi.search_vector @@ q
+		ORDER BY rank DESC, bi.created_at DESC
+		LIMIT $5`
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, scopeArg, postgresPrefixQuery(terms),
+		postgresHighlightAllOptions, postgresSnippetOptions, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search bucket items: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.ItemSearchResult
+	for rows.Next() {
+		var result models.ItemSearchResult
+		var description sql.NullString
+		err := rows.Scan(append(postgresItemFields(&result.BucketListItem),
+			&result.GroupName, &result.Rank, &result.TitleHighlight, &description)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+
+		result.TitleHighlight = renderHighlight(result.TitleHighlight)
+		if description.Valid {
+			snippet := renderHighlight(description.String)
+			result.DescriptionHighlight = &snippet
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating search results: %w", err)
+	}
+
+	err = loadSearchResultLinks(results, func(items []models.BucketListItem) error {
+		return loadPostgresItemLinks(ctx, r.db, items)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+const postgresItemColumns = `id, group_id, title, description, completed, completed_by, completed_at,
+	completion_note, completion_rating, occurred_on, scheduled_start, scheduled_end, created_by, created_at`
+
+// postgresItemFields returns the scan destinations of postgresItemColumns
+func postgresItemFields(item *models.BucketListItem) []interface{} {
+	return []interface{}{&item.ID, &item.GroupID, &item.Title, &item.Description, &item.Completed,
+		&item.CompletedBy, &item.CompletedAt, &item.CompletionNote, &item.CompletionRating,
+		postgresDateScanner{&item.OccurredOn}, &item.ScheduledStart, &item.ScheduledEnd,
+		&item.CreatedBy, &item.CreatedAt}
+}
+
+// postgresDateScanner scans a nullable DATE column into a date formatted as
+// models.DateLayout
+type postgresDateScanner struct {
+	date **string
+}
+
+func (s postgresDateScanner) Scan(src interface{}) error {
+	var date string
+	switch v := src.(type) {
+	case nil:
+		*s.date = nil
+		return nil
+	case time.Time:
+		date = v.Format(models.DateLayout)
+	case []byte:
+		date = string(v)
+	case string:
+		date = v
+	default:
+		return fmt.Errorf("cannot scan %T into a date", src)
+	}
+	if len(date) > len(models.DateLayout) {
+		date = date[:len(models.DateLayout)]
+	}
+	*s.date = &date
+	return nil
+}
+
+// loadPostgresItemLinks fills in the tags and assignees of items
+func loadPostgresItemLinks(ctx context.Context, db dbExecutor, items []models.BucketListItem) error {
+	if err := loadPostgresItemTags(ctx, db, items); err != nil {
+		return err
+	}
+	return loadPostgresItemAssignees(ctx, db, items)
+}
+
+// loadPostgresItemAssignees fills in the assignees of items
+func loadPostgresItemAssignees(ctx context.Context, db dbExecutor, items []models.BucketListItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT item_id, member_id
+		FROM item_assignees
+		WHERE item_id = ANY($1::uuid[])
+		ORDER BY item_id, assigned_at, member_id`, pq.Array(itemIDs(items)))
+	if err != nil {
+		return fmt.Errorf("failed to get item assignees: %w", err)
+	}
+	defer rows.Close()
+
+	assigneeIDs := make(map[string][]string)
+	for rows.Next() {
+		var itemID, memberID string
+		if err := rows.Scan(&itemID, &memberID); err != nil {
+			return fmt.Errorf("failed to scan item assignee: %w", err)
+		}
+		assigneeIDs[itemID] = append(assigneeIDs[itemID], memberID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating item assignees: %w", err)
+	}
+
+	setItemAssigneeIDs(items, assigneeIDs)
+	return nil
+}
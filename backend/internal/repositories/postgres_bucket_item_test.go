@@ -20,7 +20,7 @@ func createTestBucketItem(groupID, memberID string) *models.BucketListItem {
 		Title:       "Test Bucket Item",
 		Description: &description,
 		Completed:   false,
-		CreatedBy:   memberID,
+		CreatedBy:   &memberID,
 		CreatedAt:   time.Now(),
 	}
 }
@@ -77,7 +77,7 @@ func TestPostgresBucketItemRepository_Create(t *testing.T) {
 			ID:        uuid.New().String(),
 			GroupID:   group.ID,
 			Title:     "", // Invalid: empty title
-			CreatedBy: member.ID,
+			CreatedBy: &member.ID,
 			CreatedAt: time.Now(),
 		}
 		
@@ -85,6 +85,22 @@ func TestPostgresBucketItemRepository_Create(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "invalid bucket item data")
 	})
+	
+	t.Run("unknown creator", func(t *testing.T) {
+		item := createTestBucketItem(group.ID, uuid.New().String())
+		err := itemRepo.Create(ctx, item)
+		assert.ErrorIs(t, err, ErrMemberNotFound)
+	})
+	
+	t.Run("duplicate ID", func(t *testing.T) {
+		item := createTestBucketItem(group.ID, member.ID)
+		require.NoError(t, itemRepo.Create(ctx, item))
+		
+		duplicate := createTestBucketItem(group.ID, member.ID)
+		duplicate.ID = item.ID
+		err := itemRepo.Create(ctx, duplicate)
+		assert.ErrorIs(t, err, ErrDuplicateID)
+	})
 }
 
 func TestPostgresBucketItemRepository_GetByID(t *testing.T) {
@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// PostgresCommentRepository implements CommentRepository for PostgreSQL
+type PostgresCommentRepository struct {
+	db dbExecutor
+}
+
+// NewPostgresCommentRepository creates a new PostgreSQL comment repository
+func NewPostgresCommentRepository(db dbExecutor) *PostgresCommentRepository {
+	return &PostgresCommentRepository{db: db}
+}
+
+// Create creates a new comment, taking its group from its item
+func (r *PostgresCommentRepository) Create(ctx context.Context, comment *models.Comment) error {
+	if err := comment.IsValid(); err != nil {
+		return fmt.Errorf("invalid comment data: %w", err)
+	}
+
+	comment.Sanitize()
+
+	if comment.ParentID != nil {
+		parent, err := r.GetByID(ctx, *comment.ParentID)
+		if err != nil {
+			return fmt.Errorf("failed to create comment: %w", err)
+		}
+		if err := checkReplyParent(*parent, comment.ItemID); err != nil {
+			return fmt.Errorf("failed to create comment: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO comments (id, item_id, group_id, parent_id, member_id, body, created_at)
+		SELECT $1::uuid, id, group_id, $3::uuid, $4::uuid, $5::text, $6::timestamptz
+		FROM bucket_items
+		WHERE id = $2
+		RETURNING group_id`
+
+	err := r.db.QueryRowContext(ctx, query, comment.ID, comment.ItemID, comment.ParentID,
+		comment.MemberID, comment.Body, comment.CreatedAt).Scan(&comment.GroupID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("failed to create comment: %w", ErrItemNotFound)
+		}
+		return fmt.Errorf("failed to create comment: %w", mapConstraintError(err))
+	}
+
+	return nil
+}
+
+// GetByID retrieves a comment by its ID
+func (r *PostgresCommentRepository) GetByID(ctx context.Context, id string) (*models.Comment, error) {
+	query := `SELECT ` + postgresCommentColumns + ` FROM comments WHERE id = $1`
+
+	var comment models.Comment
+	err := scanPostgresComment(r.db.QueryRowContext(ctx, query, id), &comment)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %s", ErrCommentNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	return &comment, nil
+}
+
+// GetByItemID retrieves the comment threads of an item, oldest first
+func (r *PostgresCommentRepository) GetByItemID(ctx context.Context, itemID string) ([]models.CommentThread, error) {
+	query := `
+		SELECT ` + postgresCommentColumns + `
+		FROM comments
+		WHERE item_id = $1
+		ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments by item ID: %w", err)
+	}
+	defer rows.Close()
+
+	var comments []models.Comment
+	for rows.Next() {
+		var comment models.Comment
+		if err := scanPostgresComment(rows, &comment); err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comments: %w", err)
+	}
+
+	return commentThreads(comments), nil
+}
+
+// Update edits the body of a comment and sets its edited time
+func (r *PostgresCommentRepository) Update(ctx context.Context, comment *models.Comment) error {
+	if err := comment.IsValid(); err != nil {
+		return fmt.Errorf("invalid comment data: %w", err)
+	}
+
+	comment.Sanitize()
+	editedAt := time.Now()
+
+	result, err := r.db.ExecContext(ctx, `UPDATE comments SET body = $1, edited_at = $2 WHERE id = $3`,
+		comment.Body, editedAt, comment.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	if err := rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrCommentNotFound, comment.ID)); err != nil {
+		return err
+	}
+	comment.EditedAt = &editedAt
+	return nil
+}
+
+// Delete deletes a comment by ID. The foreign key deletes its replies.
+func (r *PostgresCommentRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM comments WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	return rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrCommentNotFound, id))
+}
+
+// CountByGroupID returns the number of comments on each item of a group
+// that has any
+func (r *PostgresCommentRepository) CountByGroupID(ctx context.Context, groupID string) (map[string]int, error) {
+	counts, err := queryPostgresCommentCounts(ctx, r.db, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	return counts, nil
+}
+
+const postgresCommentColumns = `id, item_id, group_id, parent_id, member_id, body, created_at, edited_at`
+
+func scanPostgresComment(row rowScanner, comment *models.Comment) error {
+	return row.Scan(&comment.ID, &comment.ItemID, &comment.GroupID, &comment.ParentID,
+		&comment.MemberID, &comment.Body, &comment.CreatedAt, &comment.EditedAt)
+}
+
+// queryPostgresCommentCounts counts the comments of each item of a group
+// that has any
+func queryPostgresCommentCounts(ctx context.Context, db dbExecutor, groupID string) (map[string]int, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT item_id, COUNT(*)
+		FROM comments
+		WHERE group_id = $1
+		GROUP BY item_id`, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var itemID string
+		var count int
+		if err := rows.Scan(&itemID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan comment count: %w", err)
+		}
+		counts[itemID] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comment counts: %w", err)
+	}
+
+	return counts, nil
+}
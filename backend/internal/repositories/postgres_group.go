@@ -4,8 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"collaborative-bucket-list/internal/models"
+
+	"github.com/lib/pq"
 )
 
 // PostgresGroupRepository implements GroupRepository for PostgreSQL
@@ -29,13 +32,13 @@ func (r *PostgresGroupRepository) Create(ctx context.Context, group *models.Grou
 	group.Sanitize()
 
 	query := `
-		INSERT INTO groups (id, name, deadline, created_at, created_by)
-		VALUES ($1, $2, $3, $4, $5)`
+		INSERT INTO groups (id, name, deadline, timezone, lock_after_deadline, created_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
 
 	_, err := r.db.ExecContext(ctx, query,
-		group.ID, group.Name, group.Deadline, group.CreatedAt, group.CreatedBy)
+		group.ID, group.Name, group.Deadline, group.Timezone, group.LockAfterDeadline, group.CreatedAt, group.CreatedBy)
 	if err != nil {
-		return fmt.Errorf("failed to create group: %w", err)
+		return fmt.Errorf("failed to create group: %w", mapConstraintError(err))
 	}
 
 	return nil
@@ -44,19 +47,20 @@ func (r *PostgresGroupRepository) Create(ctx context.Context, group *models.Grou
 // GetByID retrieves a group by its ID
 func (r *PostgresGroupRepository) GetByID(ctx context.Context, id string) (*models.Group, error) {
 	query := `
-		SELECT id, name, deadline, created_at, created_by
+		SELECT id, name, deadline, timezone, lock_after_deadline, created_at, created_by
 		FROM groups
 		WHERE id = $1`
 
 	var group models.Group
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&group.ID, &group.Name, &group.Deadline, &group.CreatedAt, &group.CreatedBy)
+		&group.ID, &group.Name, &group.Deadline, &group.Timezone, &group.LockAfterDeadline, &group.CreatedAt, &group.CreatedBy)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("group not found: %s", id)
+			return nil, fmt.Errorf("%w: %s", ErrGroupNotFound, id)
 		}
 		return nil, fmt.Errorf("failed to get group: %w", err)
 	}
+	group.Localize()
 
 	return &group, nil
 }
@@ -64,7 +68,7 @@ func (r *PostgresGroupRepository) GetByID(ctx context.Context, id string) (*mode
 // GetByUserID retrieves all groups created by a specific user
 func (r *PostgresGroupRepository) GetByUserID(ctx context.Context, userID string) ([]models.Group, error) {
 	query := `
-		SELECT id, name, deadline, created_at, created_by
+		SELECT id, name, deadline, timezone, lock_after_deadline, created_at, created_by
 		FROM groups
 		WHERE created_by = $1
 		ORDER BY created_at DESC`
@@ -78,10 +82,44 @@ func (r *PostgresGroupRepository) GetByUserID(ctx context.Context, userID string
 	var groups []models.Group
 	for rows.Next() {
 		var group models.Group
-		err := rows.Scan(&group.ID, &group.Name, &group.Deadline, &group.CreatedAt, &group.CreatedBy)
+		err := rows.Scan(&group.ID, &group.Name, &group.Deadline, &group.Timezone, &group.LockAfterDeadline, &group.CreatedAt, &group.CreatedBy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan group: %w", err)
+		}
+		group.Localize()
+		groups = append(groups, group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+// GetByDeadlineBetween retrieves the groups whose deadline is after after
+// and no later than until, soonest first
+func (r *PostgresGroupRepository) GetByDeadlineBetween(ctx context.Context, after, until time.Time) ([]models.Group, error) {
+	query := `
+		SELECT id, name, deadline, timezone, lock_after_deadline, created_at, created_by
+		FROM groups
+		WHERE deadline > $1 AND deadline <= $2
+		ORDER BY deadline ASC, id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, after, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups by deadline: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []models.Group
+	for rows.Next() {
+		var group models.Group
+		err := rows.Scan(&group.ID, &group.Name, &group.Deadline, &group.Timezone, &group.LockAfterDeadline, &group.CreatedAt, &group.CreatedBy)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan group: %w", err)
 		}
+		group.Localize()
 		groups = append(groups, group)
 	}
 
@@ -102,10 +140,10 @@ func (r *PostgresGroupRepository) Update(ctx context.Context, group *models.Grou
 
 	query := `
 		UPDATE groups
-		SET name = $2, deadline = $3
+		SET name = $2, deadline = $3, timezone = $4, lock_after_deadline = $5
 		WHERE id = $1`
 
-	result, err := r.db.ExecContext(ctx, query, group.ID, group.Name, group.Deadline)
+	result, err := r.db.ExecContext(ctx, query, group.ID, group.Name, group.Deadline, group.Timezone, group.LockAfterDeadline)
 	if err != nil {
 		return fmt.Errorf("failed to update group: %w", err)
 	}
@@ -116,7 +154,7 @@ func (r *PostgresGroupRepository) Update(ctx context.Context, group *models.Grou
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("group not found: %s", group.ID)
+		return fmt.Errorf("%w: %s", ErrGroupNotFound, group.ID)
 	}
 
 	return nil
@@ -137,7 +175,7 @@ func (r *PostgresGroupRepository) Delete(ctx context.Context, id string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("group not found: %s", id)
+		return fmt.Errorf("%w: %s", ErrGroupNotFound, id)
 	}
 
 	return nil
@@ -181,8 +219,7 @@ func (r *PostgresGroupRepository) GetWithDetails(ctx context.Context, id string)
 
 	// Get bucket list items
 	itemsQuery := `
-		SELECT id, group_id, title, description, completed, completed_by,
-			   completed_at, created_by, created_at
+		SELECT ` + postgresItemColumns + `
 		FROM bucket_items
 		WHERE group_id = $1
 		ORDER BY created_at DESC`
@@ -196,9 +233,7 @@ func (r *PostgresGroupRepository) GetWithDetails(ctx context.Context, id string)
 	var items []models.BucketListItem
 	for itemRows.Next() {
 		var item models.BucketListItem
-		err := itemRows.Scan(&item.ID, &item.GroupID, &item.Title, &item.Description,
-			&item.Completed, &item.CompletedBy, &item.CompletedAt, &item.CreatedBy, &item.CreatedAt)
-		if err != nil {
+		if err := itemRows.Scan(postgresItemFields(&item)...); err != nil {
 			return nil, fmt.Errorf("failed to scan bucket item: %w", err)
 		}
 		items = append(items, item)
@@ -208,10 +243,42 @@ func (r *PostgresGroupRepository) GetWithDetails(ctx context.Context, id string)
 		return nil, fmt.Errorf("error iterating bucket items: %w", err)
 	}
 
+	if err := loadPostgresItemLinks(ctx, r.db, items); err != nil {
+		return nil, err
+	}
+
+	// Get tags
+	tags, err := queryPostgresTags(ctx, r.db, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group tags: %w", err)
+	}
+
+	// Get comment counts
+	counts, err := queryPostgresCommentCounts(ctx, r.db, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	// Get votes and reactions
+	reactions, err := queryPostgresReactions(ctx, r.db, postgresGroupItemsCondition, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item reactions: %w", err)
+	}
+
+	// Get attachments
+	attachments, err := queryPostgresAttachments(ctx, r.db, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item attachments: %w", err)
+	}
+
 	return &models.GroupWithDetails{
-		Group:   *group,
-		Members: members,
-		Items:   items,
+		Group:         *group,
+		Members:       members,
+		Items:         items,
+		Tags:          tags,
+		CommentCounts: itemCommentCounts(items, counts),
+		Reactions:     itemReactions(items, reactions),
+		Attachments:   itemAttachments(items, attachments),
 	}, nil
 }
 
@@ -219,7 +286,7 @@ func (r *PostgresGroupRepository) GetWithDetails(ctx context.Context, id string)
 func (r *PostgresGroupRepository) GetSummariesByUserID(ctx context.Context, userID string) ([]models.GroupSummary, error) {
 	query := `
 		SELECT 
-			g.id, g.name, g.deadline, g.created_at, g.created_by,
+			g.id, g.name, g.deadline, g.timezone, g.lock_after_deadline, g.created_at, g.created_by,
 			COUNT(DISTINCT m.id) as member_count,
 			COUNT(DISTINCT bi.id) as item_count,
 			COUNT(DISTINCT CASE WHEN bi.completed = true THEN bi.id END) as completed_count
@@ -229,7 +296,7 @@ func (r *PostgresGroupRepository) GetSummariesByUserID(ctx context.Context, user
 		WHERE g.created_by = $1 OR g.id IN (
 			SELECT group_id FROM members WHERE user_id = $1
 		)
-		GROUP BY g.id, g.name, g.deadline, g.created_at, g.created_by
+		GROUP BY g.id, g.name, g.deadline, g.timezone, g.lock_after_deadline, g.created_at, g.created_by
 		ORDER BY g.created_at DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, userID)
@@ -244,11 +311,12 @@ func (r *PostgresGroupRepository) GetSummariesByUserID(ctx context.Context, user
 		var memberCount, itemCount, completedCount int
 
 		err := rows.Scan(
-			&summary.ID, &summary.Name, &summary.Deadline, &summary.CreatedAt, &summary.CreatedBy,
+			&summary.ID, &summary.Name, &summary.Deadline, &summary.Timezone, &summary.LockAfterDeadline, &summary.CreatedAt, &summary.CreatedBy,
 			&memberCount, &itemCount, &completedCount)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan group summary: %w", err)
 		}
+		summary.Localize()
 
 		summary.MemberCount = memberCount
 		summary.ItemCount = itemCount
@@ -269,4 +337,267 @@ func (r *PostgresGroupRepository) GetSummariesByUserID(ctx context.Context, user
 	}
 
 	return summaries, nil
-}
\ No newline at end of file
+}
+
+// ListSummariesByUserID retrieves a filtered, sorted page of a user's group
+// summaries
+func (r *PostgresGroupRepository) ListSummariesByUserID(ctx context.Context, userID string, opts models.GroupSummaryListOptions) (*models.GroupSummaryPage, error) {
+	ks, err := groupKeyset(opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := ks.decode(opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	limit := models.PageLimit(opts.Limit)
+
+	b := newPostgresQueryBuilder()
+	user := b.idArg(userID)
+	userSummaries := `
+			SELECT g.id, g.name, g.deadline, g.timezone, g.lock_after_deadline, g.created_at, g.created_by,
+				(SELECT COUNT(*) FROM members m WHERE m.group_id = g.id) AS member_count,
+				(SELECT COUNT(*) FROM bucket_items bi WHERE bi.group_id = g.id) AS item_count,
+				(SELECT COUNT(*) FROM bucket_items bi WHERE bi.group_id = g.id AND bi.completed) AS completed_count
+			FROM groups g
+			WHERE g.created_by = ` + user + ` OR g.id IN (SELECT group_id FROM members WHERE user_id = ` + user + `)`
+	query := buildSummaryListQuery(b, userSummaries, "completed_count::float8 / item_count * 100", opts, ks, cursor, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list group summaries: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []models.GroupSummary
+	for rows.Next() {
+		var summary models.GroupSummary
+		err := rows.Scan(
+			&summary.ID, &summary.Name, &summary.Deadline, &summary.Timezone, &summary.LockAfterDeadline, &summary.CreatedAt, &summary.CreatedBy,
+			&summary.MemberCount, &summary.ItemCount, &summary.CompletedCount, &summary.ProgressPercent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan group summary: %w", err)
+		}
+		summary.Localize()
+		summaries = append(summaries, summary)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating group summaries: %w", err)
+	}
+
+	return summaryPage(summaries, ks, limit), nil
+}
+
+// SearchByUserID searches the names of the groups a user created or belongs to
+func (r *PostgresGroupRepository) SearchByUserID(ctx context.Context, userID, query string, limit int) ([]models.GroupSearchResult, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	sqlQuery := `
+		SELECT g.id, g.name, g.deadline, g.timezone, g.lock_after_deadline, g.created_at, g.created_by,
+			   ts_rank_cd(g.search_vector, q) AS rank,
+			   ts_headline('english', ` + postgresStripMarkers("g.name") + `, q, $3)
+		FROM groups g
+		CROSS JOIN to_tsquery('english', $2) AS q
+		WHERE (g.created_by = $1 OR g.id IN (SELECT group_id FROM members WHERE user_id = $1))
+			AND g.search_vector @@ q
+		ORDER BY rank DESC, g.created_at DESC
+		LIMIT $4`
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, userID, postgresPrefixQuery(terms),
+		postgresHighlightAllOptions, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search groups: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.GroupSearchResult
+	for rows.Next() {
+		var result models.GroupSearchResult
+		err := rows.Scan(&result.ID, &result.Name, &result.Deadline, &result.Timezone, &result.LockAfterDeadline, &result.CreatedAt, &result.CreatedBy,
+			&result.Rank, &result.NameHighlight)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		result.Localize()
+
+		result.NameHighlight = renderHighlight(result.NameHighlight)
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating search results: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetCalendarTokenHash returns the hash of the token of a group's calendar
+// feed
+func (r *PostgresGroupRepository) GetCalendarTokenHash(ctx context.Context, groupID string) (string, error) {
+	query := `SELECT calendar_token_hash FROM groups WHERE id = $1`
+
+	var tokenHash sql.NullString
+	if err := r.db.QueryRowContext(ctx, query, groupID).Scan(&tokenHash); err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("%w: %s", ErrGroupNotFound, groupID)
+		}
+		return "", fmt.Errorf("failed to get calendar token: %w", err)
+	}
+
+	return tokenHash.String, nil
+}
+
+// EnsureCalendarTokenHash enables a group's calendar feed unless it already is
+func (r *PostgresGroupRepository) EnsureCalendarTokenHash(ctx context.Context, groupID, candidateHash string) (string, error) {
+	query := `
+		UPDATE groups
+		SET calendar_token_hash = COALESCE(calendar_token_hash, $2)
+		WHERE id = $1
+		RETURNING calendar_token_hash`
+
+	var tokenHash string
+	if err := r.db.QueryRowContext(ctx, query, groupID, candidateHash).Scan(&tokenHash); err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("%w: %s", ErrGroupNotFound, groupID)
+		}
+		return "", fmt.Errorf("failed to set calendar token: %w", err)
+	}
+
+	return tokenHash, nil
+}
+
+// RevokeCalendarToken disables a group's calendar feed
+func (r *PostgresGroupRepository) RevokeCalendarToken(ctx context.Context, groupID string) error {
+	query := `UPDATE groups SET calendar_token_hash = NULL WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, groupID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke calendar token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: %s", ErrGroupNotFound, groupID)
+	}
+
+	return nil
+}
+
+// GetReminderDays returns a group's reminder schedule
+func (r *PostgresGroupRepository) GetReminderDays(ctx context.Context, groupID string) ([]int, error) {
+	query := `
+		SELECT rem.days_before
+		FROM groups g
+		LEFT JOIN group_reminders rem ON rem.group_id = g.id
+		WHERE g.id = $1
+		ORDER BY rem.days_before DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reminder days: %w", err)
+	}
+	defer rows.Close()
+
+	found := false
+	days := []int{}
+	for rows.Next() {
+		found = true
+		var day sql.NullInt64
+		if err := rows.Scan(&day); err != nil {
+			return nil, fmt.Errorf("failed to scan reminder day: %w", err)
+		}
+		if day.Valid {
+			days = append(days, int(day.Int64))
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reminder days: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrGroupNotFound, groupID)
+	}
+
+	return days, nil
+}
+
+// SetReminderDays replaces a group's reminder schedule. Run it in a
+// transaction so the group never loses its old schedule without gaining
+// the new one.
+func (r *PostgresGroupRepository) SetReminderDays(ctx context.Context, groupID string, days []int) error {
+	days = models.NormalizeReminderDays(days)
+
+	// Lock the group so concurrent calls replace its schedule one after another
+	var id string
+	err := r.db.QueryRowContext(ctx, `SELECT id FROM groups WHERE id = $1 FOR UPDATE`, groupID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: %s", ErrGroupNotFound, groupID)
+		}
+		return fmt.Errorf("failed to set reminder days: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM group_reminders WHERE group_id = $1`, groupID); err != nil {
+		return fmt.Errorf("failed to set reminder days: %w", err)
+	}
+
+	if len(days) > 0 {
+		_, err = r.db.ExecContext(ctx, `
+			INSERT INTO group_reminders (group_id, days_before)
+			SELECT $1, unnest($2::int[])`, groupID, pq.Array(days))
+		if err != nil {
+			return fmt.Errorf("failed to set reminder days: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetDueReminders retrieves the reminders falling due after after and no
+// later than until, earliest first. A reminder falls due a whole number of
+// 24-hour days before its deadline.
+func (r *PostgresGroupRepository) GetDueReminders(ctx context.Context, after, until time.Time) ([]models.DueReminder, error) {
+	query := `
+		SELECT g.id, g.name, g.deadline, g.timezone, g.lock_after_deadline, g.created_at, g.created_by,
+			rem.days_before
+		FROM groups g
+		JOIN group_reminders rem ON rem.group_id = g.id
+		WHERE g.deadline > $1 AND g.deadline <= $3
+			AND g.deadline - rem.days_before * INTERVAL '24 hours' > $1
+			AND g.deadline - rem.days_before * INTERVAL '24 hours' <= $2`
+
+	rows, err := r.db.QueryContext(ctx, query, after, until, until.Add(maxReminderLead))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var reminders []models.DueReminder
+	for rows.Next() {
+		var reminder models.DueReminder
+		group := &reminder.Group
+		err := rows.Scan(&group.ID, &group.Name, &group.Deadline, &group.Timezone, &group.LockAfterDeadline, &group.CreatedAt, &group.CreatedBy,
+			&reminder.DaysBefore)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		group.Localize()
+		reminder.RemindAt = models.RemindAt(*group.Deadline, reminder.DaysBefore)
+		reminders = append(reminders, reminder)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reminders: %w", err)
+	}
+
+	sortDueReminders(reminders)
+	return reminders, nil
+}
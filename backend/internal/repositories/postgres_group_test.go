@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/migrations"
+	"collaborative-bucket-list/pkg/database"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -76,54 +78,12 @@ func createTestDatabase(t *testing.T, db *sql.DB, dbname string) {
 	}
 }
 
-// runTestMigrations runs the database migrations for tests
+// runTestMigrations applies the embedded migrations so tests run against
+// the same schema as the server
 func runTestMigrations(t *testing.T, db *sql.DB) {
-	// Enable UUID extension
-	_, err := db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\"")
-	if err != nil {
-		t.Logf("Warning: Could not create uuid-ossp extension: %v", err)
-	}
-	
-	// Create groups table
-	createGroupsTable := `
-		CREATE TABLE IF NOT EXISTS groups (
-			id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-			name TEXT NOT NULL,
-			deadline TIMESTAMPTZ,
-			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-			created_by UUID NOT NULL
-		)`
-	_, err = db.Exec(createGroupsTable)
-	require.NoError(t, err, "Failed to create groups table")
-	
-	// Create members table
-	createMembersTable := `
-		CREATE TABLE IF NOT EXISTS members (
-			id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-			group_id UUID NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
-			user_id UUID,
-			name TEXT NOT NULL,
-			joined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-			is_creator BOOLEAN DEFAULT FALSE
-		)`
-	_, err = db.Exec(createMembersTable)
-	require.NoError(t, err, "Failed to create members table")
-	
-	// Create bucket_items table
-	createBucketItemsTable := `
-		CREATE TABLE IF NOT EXISTS bucket_items (
-			id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-			group_id UUID NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
-			title TEXT NOT NULL,
-			description TEXT,
-			completed BOOLEAN DEFAULT FALSE,
-			completed_by UUID REFERENCES members(id),
-			completed_at TIMESTAMPTZ,
-			created_by UUID NOT NULL REFERENCES members(id),
-			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
-		)`
-	_, err = db.Exec(createBucketItemsTable)
-	require.NoError(t, err, "Failed to create bucket_items table")
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	require.NoError(t, err, "Failed to load migrations")
+	require.NoError(t, migrator.Up(context.Background()), "Failed to run migrations")
 }
 
 // cleanupTables removes all data from test tables
@@ -374,14 +334,14 @@ func TestPostgresGroupRepository_GetWithDetails(t *testing.T) {
 			ID:        uuid.New().String(),
 			GroupID:   group.ID,
 			Title:     "Item 1",
-			CreatedBy: member1.ID,
+			CreatedBy: &member1.ID,
 			CreatedAt: time.Now(),
 		}
 		item2 := &models.BucketListItem{
 			ID:        uuid.New().String(),
 			GroupID:   group.ID,
 			Title:     "Item 2",
-			CreatedBy: member2.ID,
+			CreatedBy: &member2.ID,
 			CreatedAt: time.Now(),
 		}
 		
@@ -444,7 +404,7 @@ func TestPostgresGroupRepository_GetSummariesByUserID(t *testing.T) {
 			GroupID:   group.ID,
 			Title:     "Item 1",
 			Completed: false,
-			CreatedBy: member.ID,
+			CreatedBy: &member.ID,
 			CreatedAt: time.Now(),
 		}
 		item2 := &models.BucketListItem{
@@ -452,7 +412,7 @@ func TestPostgresGroupRepository_GetSummariesByUserID(t *testing.T) {
 			GroupID:   group.ID,
 			Title:     "Item 2",
 			Completed: true,
-			CreatedBy: member.ID,
+			CreatedBy: &member.ID,
 			CreatedAt: time.Now(),
 		}
 		
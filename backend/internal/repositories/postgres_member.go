@@ -35,7 +35,7 @@ func (r *PostgresMemberRepository) Create(ctx context.Context, member *models.Me
 	_, err := r.db.ExecContext(ctx, query,
 		member.ID, member.GroupID, member.UserID, member.Name, member.JoinedAt, member.IsCreator)
 	if err != nil {
-		return fmt.Errorf("failed to create member: %w", err)
+		return fmt.Errorf("failed to create member: %w", mapConstraintError(err))
 	}
 
 	return nil
@@ -53,7 +53,7 @@ func (r *PostgresMemberRepository) GetByID(ctx context.Context, id string) (*mod
 		&member.ID, &member.GroupID, &member.UserID, &member.Name, &member.JoinedAt, &member.IsCreator)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("member not found: %s", id)
+			return nil, fmt.Errorf("%w: %s", ErrMemberNotFound, id)
 		}
 		return nil, fmt.Errorf("failed to get member: %w", err)
 	}
@@ -140,7 +140,7 @@ func (r *PostgresMemberRepository) Update(ctx context.Context, member *models.Me
 
 	result, err := r.db.ExecContext(ctx, query, member.ID, member.Name, member.UserID)
 	if err != nil {
-		return fmt.Errorf("failed to update member: %w", err)
+		return fmt.Errorf("failed to update member: %w", mapConstraintError(err))
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -149,7 +149,7 @@ func (r *PostgresMemberRepository) Update(ctx context.Context, member *models.Me
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("member not found: %s", member.ID)
+		return fmt.Errorf("%w: %s", ErrMemberNotFound, member.ID)
 	}
 
 	return nil
@@ -170,7 +170,7 @@ func (r *PostgresMemberRepository) Delete(ctx context.Context, id string) error
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("member not found: %s", id)
+		return fmt.Errorf("%w: %s", ErrMemberNotFound, id)
 	}
 
 	return nil
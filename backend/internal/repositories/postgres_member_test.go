@@ -83,6 +83,22 @@ func TestPostgresMemberRepository_Create(t *testing.T) {
 		assert.Nil(t, retrieved.UserID)
 		assert.Equal(t, "Anonymous Member", retrieved.Name)
 	})
+	
+	t.Run("same user joining twice", func(t *testing.T) {
+		member := createTestMember(group.ID)
+		require.NoError(t, memberRepo.Create(ctx, member))
+		
+		duplicate := createTestMember(group.ID)
+		duplicate.UserID = member.UserID
+		err := memberRepo.Create(ctx, duplicate)
+		assert.ErrorIs(t, err, ErrAlreadyMember)
+	})
+	
+	t.Run("non-existent group", func(t *testing.T) {
+		member := createTestMember(uuid.New().String())
+		err := memberRepo.Create(ctx, member)
+		assert.ErrorIs(t, err, ErrGroupNotFound)
+	})
 }
 
 func TestPostgresMemberRepository_GetByID(t *testing.T) {
@@ -301,12 +317,39 @@ func TestPostgresMemberRepository_Delete(t *testing.T) {
 		assert.Contains(t, err.Error(), "member not found")
 	})
 	
+	t.Run("member with items", func(t *testing.T) {
+		itemRepo := NewPostgresBucketItemRepository(db)
+		creator := createTestMember(group.ID)
+		require.NoError(t, memberRepo.Create(ctx, creator))
+		leaving := createTestMember(group.ID)
+		require.NoError(t, memberRepo.Create(ctx, leaving))
+		
+		added := createTestBucketItem(group.ID, leaving.ID)
+		require.NoError(t, itemRepo.Create(ctx, added))
+		completed := createTestBucketItem(group.ID, creator.ID)
+		require.NoError(t, itemRepo.Create(ctx, completed))
+		require.NoError(t, itemRepo.ToggleCompletion(ctx, completed.ID, leaving.ID, true))
+		
+		require.NoError(t, memberRepo.Delete(ctx, leaving.ID))
+		
+		// Items the member added go with them
+		_, err := itemRepo.GetByID(ctx, added.ID)
+		assert.ErrorIs(t, err, ErrItemNotFound)
+		
+		// Items they completed stay completed without a completer
+		retrieved, err := itemRepo.GetByID(ctx, completed.ID)
+		require.NoError(t, err)
+		assert.True(t, retrieved.Completed)
+		assert.Nil(t, retrieved.CompletedBy)
+	})
+	
 	t.Run("non-existent member", func(t *testing.T) {
 		nonExistentID := uuid.New().String()
 		
 		err := memberRepo.Delete(ctx, nonExistentID)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "member not found")
+		assert.ErrorIs(t, err, ErrMemberNotFound)
 	})
 }
 
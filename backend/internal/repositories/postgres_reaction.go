@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// PostgresReactionRepository implements ReactionRepository for PostgreSQL
+type PostgresReactionRepository struct {
+	db dbExecutor
+}
+
+// NewPostgresReactionRepository creates a new PostgreSQL reaction repository
+func NewPostgresReactionRepository(db dbExecutor) *PostgresReactionRepository {
+	return &PostgresReactionRepository{db: db}
+}
+
+// SetVote adds or removes a member's vote for an item
+func (r *PostgresReactionRepository) SetVote(ctx context.Context, itemID, memberID string, voted bool) error {
+	if !voted {
+		_, err := r.db.ExecContext(ctx, `DELETE FROM item_votes WHERE item_id = $1 AND member_id = $2`, itemID, memberID)
+		if err != nil {
+			return fmt.Errorf("failed to remove vote: %w", err)
+		}
+		return nil
+	}
+
+	query := `
+		INSERT INTO item_votes (item_id, member_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (item_id, member_id) DO NOTHING`
+
+	_, err := r.db.ExecContext(ctx, query, itemID, memberID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to add vote: %w", mapConstraintError(err))
+	}
+
+	return nil
+}
+
+// AddReaction adds a member's emoji reaction to an item
+func (r *PostgresReactionRepository) AddReaction(ctx context.Context, reaction *models.Reaction) error {
+	if err := reaction.IsValid(); err != nil {
+		return fmt.Errorf("invalid reaction data: %w", err)
+	}
+
+	reaction.Sanitize()
+
+	query := `
+		INSERT INTO item_reactions (item_id, member_id, emoji, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (item_id, member_id, emoji) DO NOTHING`
+
+	_, err := r.db.ExecContext(ctx, query, reaction.ItemID, reaction.MemberID, reaction.Emoji, reaction.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add reaction: %w", mapConstraintError(err))
+	}
+
+	return nil
+}
+
+// RemoveReaction removes a member's emoji reaction from an item, if any
+func (r *PostgresReactionRepository) RemoveReaction(ctx context.Context, itemID, memberID, emoji string) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM item_reactions
+		WHERE item_id = $1 AND member_id = $2 AND emoji = $3`, itemID, memberID, emoji)
+	if err != nil {
+		return fmt.Errorf("failed to remove reaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByItemID aggregates the votes and reactions of an item
+func (r *PostgresReactionRepository) GetByItemID(ctx context.Context, itemID string) (*models.ItemReactions, error) {
+	byItem, err := queryPostgresReactions(ctx, r.db, `item_id = $1`, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregate, ok := byItem[itemID]
+	if !ok {
+		aggregate = emptyItemReactions(itemID)
+	}
+	return &aggregate, nil
+}
+
+// GetByGroupID aggregates the votes and reactions of each item of a group
+// that has any
+func (r *PostgresReactionRepository) GetByGroupID(ctx context.Context, groupID string) (map[string]models.ItemReactions, error) {
+	return queryPostgresReactions(ctx, r.db, postgresGroupItemsCondition, groupID)
+}
+
+// postgresGroupItemsCondition selects the votes or reactions of the items
+// of the group in $1
+const postgresGroupItemsCondition = `item_id IN (SELECT id FROM bucket_items WHERE group_id = $1)`
+
+// queryPostgresReactions aggregates the votes and reactions matching a
+// condition on their item_id with one argument
+func queryPostgresReactions(ctx context.Context, db dbExecutor, condition string, arg string) (map[string]models.ItemReactions, error) {
+	voteRows, err := db.QueryContext(ctx, `
+		SELECT item_id, member_id, created_at
+		FROM item_votes
+		WHERE `+condition+`
+		ORDER BY created_at ASC, member_id ASC`, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get votes: %w", err)
+	}
+	defer voteRows.Close()
+
+	var votes []models.Vote
+	for voteRows.Next() {
+		var vote models.Vote
+		if err := voteRows.Scan(&vote.ItemID, &vote.MemberID, &vote.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan vote: %w", err)
+		}
+		votes = append(votes, vote)
+	}
+
+	if err = voteRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating votes: %w", err)
+	}
+
+	reactionRows, err := db.QueryContext(ctx, `
+		SELECT item_id, member_id, emoji, created_at
+		FROM item_reactions
+		WHERE `+condition+`
+		ORDER BY created_at ASC, emoji ASC, member_id ASC`, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reactions: %w", err)
+	}
+	defer reactionRows.Close()
+
+	var reactions []models.Reaction
+	for reactionRows.Next() {
+		var reaction models.Reaction
+		err := reactionRows.Scan(&reaction.ItemID, &reaction.MemberID, &reaction.Emoji, &reaction.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reaction: %w", err)
+		}
+		reactions = append(reactions, reaction)
+	}
+
+	if err = reactionRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reactions: %w", err)
+	}
+
+	return aggregateReactions(votes, reactions), nil
+}
@@ -0,0 +1,204 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"collaborative-bucket-list/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// PostgresTagRepository implements TagRepository for PostgreSQL
+type PostgresTagRepository struct {
+	db dbExecutor
+}
+
+// NewPostgresTagRepository creates a new PostgreSQL tag repository
+func NewPostgresTagRepository(db dbExecutor) *PostgresTagRepository {
+	return &PostgresTagRepository{db: db}
+}
+
+// Create creates a new tag
+func (r *PostgresTagRepository) Create(ctx context.Context, tag *models.Tag) error {
+	if err := tag.IsValid(); err != nil {
+		return fmt.Errorf("invalid tag data: %w", err)
+	}
+
+	tag.Sanitize()
+
+	query := `
+		INSERT INTO tags (id, group_id, name, color, created_at)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := r.db.ExecContext(ctx, query, tag.ID, tag.GroupID, tag.Name, tag.Color, tag.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create tag: %w", mapConstraintError(err))
+	}
+
+	return nil
+}
+
+// GetByID retrieves a tag by its ID
+func (r *PostgresTagRepository) GetByID(ctx context.Context, id string) (*models.Tag, error) {
+	query := `
+		SELECT id, group_id, name, color, created_at
+		FROM tags
+		WHERE id = $1`
+
+	var tag models.Tag
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&tag.ID, &tag.GroupID, &tag.Name, &tag.Color, &tag.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %s", ErrTagNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	return &tag, nil
+}
+
+// GetByGroupID retrieves all tags of a group, ordered by name
+func (r *PostgresTagRepository) GetByGroupID(ctx context.Context, groupID string) ([]models.Tag, error) {
+	tags, err := queryPostgresTags(ctx, r.db, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags by group ID: %w", err)
+	}
+
+	return tags, nil
+}
+
+// Update renames or recolors an existing tag
+func (r *PostgresTagRepository) Update(ctx context.Context, tag *models.Tag) error {
+	if err := tag.IsValid(); err != nil {
+		return fmt.Errorf("invalid tag data: %w", err)
+	}
+
+	tag.Sanitize()
+
+	query := `UPDATE tags SET name = $2, color = $3 WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, tag.ID, tag.Name, tag.Color)
+	if err != nil {
+		return fmt.Errorf("failed to update tag: %w", mapConstraintError(err))
+	}
+
+	return rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrTagNotFound, tag.ID))
+}
+
+// Delete deletes a tag by ID. The foreign key removes it from every item.
+func (r *PostgresTagRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tags WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	return rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrTagNotFound, id))
+}
+
+// SetItemTags replaces the tags of an item. Run it in a transaction so the
+// item never loses its old tags without gaining the new ones.
+func (r *PostgresTagRepository) SetItemTags(ctx context.Context, itemID string, tagIDs []string) error {
+	tagIDs = uniqueTagIDs(tagIDs)
+
+	// Lock the item so concurrent calls replace its tags one after another
+	var groupID string
+	err := r.db.QueryRowContext(ctx, `SELECT group_id FROM bucket_items WHERE id = $1 FOR UPDATE`, itemID).Scan(&groupID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: %s", ErrItemNotFound, itemID)
+		}
+		return fmt.Errorf("failed to set item tags: %w", err)
+	}
+
+	var found int
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM tags WHERE group_id = $1 AND id = ANY($2::uuid[])`,
+		groupID, pq.Array(tagIDs)).Scan(&found)
+	if err != nil {
+		return fmt.Errorf("failed to set item tags: %w", err)
+	}
+	if found != len(tagIDs) {
+		return fmt.Errorf("failed to set item tags: %w", ErrTagNotFound)
+	}
+
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM item_tags WHERE item_id = $1`, itemID); err != nil {
+		return fmt.Errorf("failed to set item tags: %w", err)
+	}
+
+	if len(tagIDs) > 0 {
+		_, err = r.db.ExecContext(ctx, `
+			INSERT INTO item_tags (item_id, tag_id)
+			SELECT $1, unnest($2::uuid[])`, itemID, pq.Array(tagIDs))
+		if err != nil {
+			return fmt.Errorf("failed to set item tags: %w", mapConstraintError(err))
+		}
+	}
+
+	return nil
+}
+
+// queryPostgresTags retrieves the tags of a group, ordered by name
+func queryPostgresTags(ctx context.Context, db dbExecutor, groupID string) ([]models.Tag, error) {
+	query := `
+		SELECT id, group_id, name, color, created_at
+		FROM tags
+		WHERE group_id = $1
+		ORDER BY lower(name), id`
+
+	rows, err := db.QueryContext(ctx, query, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []models.Tag
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.ID, &tag.GroupID, &tag.Name, &tag.Color, &tag.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// loadPostgresItemTags fills in the tags of items
+func loadPostgresItemTags(ctx context.Context, db dbExecutor, items []models.BucketListItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT item_id, tag_id
+		FROM item_tags
+		WHERE item_id = ANY($1::uuid[])
+		ORDER BY item_id, tag_id`, pq.Array(itemIDs(items)))
+	if err != nil {
+		return fmt.Errorf("failed to get item tags: %w", err)
+	}
+	defer rows.Close()
+
+	tagIDs := make(map[string][]string)
+	for rows.Next() {
+		var itemID, tagID string
+		if err := rows.Scan(&itemID, &tagID); err != nil {
+			return fmt.Errorf("failed to scan item tag: %w", err)
+		}
+		tagIDs[itemID] = append(tagIDs[itemID], tagID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating item tags: %w", err)
+	}
+
+	setItemTagIDs(items, tagIDs)
+	return nil
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"collaborative-bucket-list/internal/models"
+)
+
+// emptyItemReactions returns the reactions of an item without any
+func emptyItemReactions(itemID string) models.ItemReactions {
+	return models.ItemReactions{
+		ItemID:    itemID,
+		VoterIDs:  []string{},
+		Reactions: []models.ReactionCount{},
+	}
+}
+
+// aggregateReactions totals votes and reactions by item. Votes and
+// reactions must be ordered oldest first, which sets the order of voters,
+// emoji and reacting members.
+func aggregateReactions(votes []models.Vote, reactions []models.Reaction) map[string]models.ItemReactions {
+	byItem := make(map[string]models.ItemReactions)
+	get := func(itemID string) models.ItemReactions {
+		if aggregate, ok := byItem[itemID]; ok {
+			return aggregate
+		}
+		return emptyItemReactions(itemID)
+	}
+
+	for _, vote := range votes {
+		aggregate := get(vote.ItemID)
+		aggregate.Votes++
+		aggregate.VoterIDs = append(aggregate.VoterIDs, vote.MemberID)
+		byItem[vote.ItemID] = aggregate
+	}
+
+	for _, reaction := range reactions {
+		aggregate := get(reaction.ItemID)
+		i := 0
+		for i < len(aggregate.Reactions) && aggregate.Reactions[i].Emoji != reaction.Emoji {
+			i++
+		}
+		if i == len(aggregate.Reactions) {
+			aggregate.Reactions = append(aggregate.Reactions, models.ReactionCount{Emoji: reaction.Emoji})
+		}
+		aggregate.Reactions[i].Count++
+		aggregate.Reactions[i].MemberIDs = append(aggregate.Reactions[i].MemberIDs, reaction.MemberID)
+		byItem[reaction.ItemID] = aggregate
+	}
+
+	return byItem
+}
+
+// itemReactions returns the reactions of items from the reactions of the
+// items that have any, with empty reactions for the rest
+func itemReactions(items []models.BucketListItem, byItem map[string]models.ItemReactions) map[string]models.ItemReactions {
+	all := make(map[string]models.ItemReactions, len(items))
+	for _, item := range items {
+		aggregate, ok := byItem[item.ID]
+		if !ok {
+			aggregate = emptyItemReactions(item.ID)
+		}
+		all[item.ID] = aggregate
+	}
+	return all
+}
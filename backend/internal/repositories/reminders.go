@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"sort"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// maxReminderLead is the furthest ahead of its deadline a reminder falls
+// due, bounding the deadlines whose reminders can fall due in a window
+const maxReminderLead = models.MaxReminderDays * 24 * time.Hour
+
+// sortDueReminders orders reminders by when they fall due, then by group
+// and furthest reminder first
+func sortDueReminders(reminders []models.DueReminder) {
+	sort.Slice(reminders, func(i, j int) bool {
+		a, b := reminders[i], reminders[j]
+		if !a.RemindAt.Equal(b.RemindAt) {
+			return a.RemindAt.Before(b.RemindAt)
+		}
+		if a.Group.ID != b.Group.ID {
+			return a.Group.ID < b.Group.ID
+		}
+		return a.DaysBefore > b.DaysBefore
+	})
+}
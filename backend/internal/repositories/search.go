@@ -0,0 +1,291 @@
+package repositories
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// maxSearchTerms bounds the number of words taken from a search query
+const maxSearchTerms = 8
+
+// Markers placed around matches before a highlight is HTML-escaped. They
+// are control characters and are stripped from the text being highlighted
+// first, so the markers in a headline are always the ones search added.
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+// Snippet bounds for descriptions, in words
+const (
+	snippetMaxWords     = 30
+	snippetLeadingWords = 5
+)
+
+// searchTerms splits a query into lowercase words of letters and digits.
+// Every term must match for a row to be found; the last one may be
+// incomplete, so all terms match as word prefixes.
+func searchTerms(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > maxSearchTerms {
+		words = words[:maxSearchTerms]
+	}
+	return words
+}
+
+// qualifyColumns prefixes each of a comma-separated list of columns with a
+// table alias, for reusing column lists in joins
+func qualifyColumns(alias, columns string) string {
+	parts := strings.Split(columns, ",")
+	for i, column := range parts {
+		parts[i] = alias + "." + strings.TrimSpace(column)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// postgresPrefixQuery builds a to_tsquery expression requiring every term as
+// a prefix. Terms hold only letters and digits, so no escaping is needed.
+func postgresPrefixQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = term + ":*"
+	}
+	return strings.Join(parts, " & ")
+}
+
+// postgresHeadlineOptions configure ts_headline for titles and names, which
+// are highlighted in full, and for descriptions, which are cut to snippets
+const (
+	postgresHighlightAllOptions = "HighlightAll=true, StartSel=" + highlightStart + ", StopSel=" + highlightStop
+	postgresSnippetOptions      = "MaxWords=30, MinWords=10, MaxFragments=2, FragmentDelimiter=\" … \", " +
+		"StartSel=" + highlightStart + ", StopSel=" + highlightStop
+)
+
+// renderHighlight HTML-escapes a marked-up headline and turns the markers
+// into <mark> tags
+func renderHighlight(headline string) string {
+	escaped := html.EscapeString(headline)
+	escaped = strings.ReplaceAll(escaped, highlightStart, "<mark>")
+	return strings.ReplaceAll(escaped, highlightStop, "</mark>")
+}
+
+// textMatcher implements prefix search in Go for the stores without a
+// full-text index. Unlike PostgreSQL it does not stem words.
+type textMatcher struct {
+	terms []string
+}
+
+func newTextMatcher(query string) textMatcher {
+	return textMatcher{terms: searchTerms(query)}
+}
+
+// textWord is a word of a text and its byte offsets
+type textWord struct {
+	lower      string
+	start, end int
+}
+
+// splitWords returns the words of text, with their offsets
+func splitWords(text string) []textWord {
+	var words []textWord
+	start := -1
+	for i, r := range text {
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWordRune && start < 0 {
+			start = i
+		} else if !isWordRune && start >= 0 {
+			words = append(words, textWord{lower: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, textWord{lower: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return words
+}
+
+// matchesWord reports whether a word starts with one of the terms
+func (m textMatcher) matchesWord(word string) bool {
+	for _, term := range m.terms {
+		if strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsTerm reports whether a word of text starts with term
+func containsTerm(words []textWord, term string) bool {
+	for _, word := range words {
+		if strings.HasPrefix(word.lower, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// rank scores a document whose primary text (a title or name) weighs 1 and
+// whose secondary text weighs 0.4, as PostgreSQL's default A and B weights
+// do. ok is false unless every term matches one of the texts.
+func (m textMatcher) rank(primary string, secondary *string) (score float64, ok bool) {
+	if len(m.terms) == 0 {
+		return 0, false
+	}
+
+	primaryWords := splitWords(primary)
+	var secondaryWords []textWord
+	if secondary != nil {
+		secondaryWords = splitWords(*secondary)
+	}
+
+	for _, term := range m.terms {
+		switch {
+		case containsTerm(primaryWords, term):
+			score += 1
+		case containsTerm(secondaryWords, term):
+			score += 0.4
+		default:
+			return 0, false
+		}
+	}
+	return score / float64(len(m.terms)), true
+}
+
+// stripHighlightMarkers removes marker characters from text to highlight
+func stripHighlightMarkers(text string) string {
+	return strings.NewReplacer(highlightStart, "", highlightStop, "").Replace(text)
+}
+
+// postgresStripMarkers is stripHighlightMarkers for a SQL expression
+func postgresStripMarkers(expr string) string {
+	return "translate(" + expr + ", chr(2) || chr(3), '')"
+}
+
+// highlight marks every matching word of text
+func (m textMatcher) highlight(text string) string {
+	text = stripHighlightMarkers(text)
+	return renderHighlight(m.mark(text, splitWords(text)))
+}
+
+// mark wraps the matching words in highlight markers
+func (m textMatcher) mark(text string, words []textWord) string {
+	var b strings.Builder
+	last := 0
+	for _, word := range words {
+		if !m.matchesWord(word.lower) {
+			continue
+		}
+		b.WriteString(text[last:word.start])
+		b.WriteString(highlightStart)
+		b.WriteString(text[word.start:word.end])
+		b.WriteString(highlightStop)
+		last = word.end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// snippet highlights a window of text around its first match
+func (m textMatcher) snippet(text string) string {
+	text = stripHighlightMarkers(text)
+	words := splitWords(text)
+	if len(words) <= snippetMaxWords {
+		return m.highlight(text)
+	}
+
+	first := 0
+	for i, word := range words {
+		if m.matchesWord(word.lower) {
+			first = i
+			break
+		}
+	}
+
+	from := first - snippetLeadingWords
+	if from < 0 {
+		from = 0
+	}
+	to := from + snippetMaxWords
+	if to > len(words) {
+		to = len(words)
+		from = to - snippetMaxWords
+	}
+
+	start, end := words[from].start, words[to-1].end
+	marked := m.mark(text[start:end], shiftWords(words[from:to], start))
+	if from > 0 {
+		marked = "… " + marked
+	}
+	if to < len(words) {
+		marked += " …"
+	}
+	return renderHighlight(marked)
+}
+
+// shiftWords moves word offsets to a substring starting at offset
+func shiftWords(words []textWord, offset int) []textWord {
+	shifted := make([]textWord, len(words))
+	for i, word := range words {
+		shifted[i] = textWord{lower: word.lower, start: word.start - offset, end: word.end - offset}
+	}
+	return shifted
+}
+
+// itemSearchResult builds a search result for an item matched in Go
+func (m textMatcher) itemSearchResult(item models.BucketListItem, groupName string, rank float64) models.ItemSearchResult {
+	result := models.ItemSearchResult{
+		BucketListItem: item,
+		GroupName:      groupName,
+		Rank:           rank,
+		TitleHighlight: m.highlight(item.Title),
+	}
+	if item.Description != nil {
+		snippet := m.snippet(*item.Description)
+		result.DescriptionHighlight = &snippet
+	}
+	return result
+}
+
+// sortItemSearchResults orders results by rank, newest first among equals, and
+// applies the limit
+func sortItemSearchResults(results []models.ItemSearchResult, limit int) []models.ItemSearchResult {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].CreatedAt.After(results[j].CreatedAt)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// sortGroupSearchResults is sortItemSearchResults for groups
+func sortGroupSearchResults(results []models.GroupSearchResult, limit int) []models.GroupSearchResult {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].CreatedAt.After(results[j].CreatedAt)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// groupSearchResult builds a search result for a group matched in Go
+func (m textMatcher) groupSearchResult(group models.Group, rank float64) models.GroupSearchResult {
+	return models.GroupSearchResult{
+		Group:         group,
+		Rank:          rank,
+		NameHighlight: m.highlight(group.Name),
+	}
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"go", "hiking", "2025"}, searchTerms("  Go HIKING, 2025! "))
+	assert.Empty(t, searchTerms("!!! ---"))
+	assert.Len(t, searchTerms(strings.Repeat("word ", 20)), maxSearchTerms)
+}
+
+func TestPostgresPrefixQuery(t *testing.T) {
+	assert.Equal(t, "go:* & hik:*", postgresPrefixQuery([]string{"go", "hik"}))
+}
+
+func TestRenderHighlight(t *testing.T) {
+	headline := "a " + highlightStart + "<b>" + highlightStop + " & c"
+	assert.Equal(t, "a <mark>&lt;b&gt;</mark> &amp; c", renderHighlight(headline))
+}
+
+func TestTextMatcher(t *testing.T) {
+	matcher := newTextMatcher("hik")
+
+	t.Run("rank", func(t *testing.T) {
+		description := "a long hike"
+		titleRank, ok := matcher.rank("Hiking trip", nil)
+		assert.True(t, ok)
+		descriptionRank, ok := matcher.rank("Weekend", &description)
+		assert.True(t, ok)
+		assert.Greater(t, titleRank, descriptionRank)
+
+		_, ok = matcher.rank("Shiking", nil)
+		assert.False(t, ok, "terms match at word starts only")
+	})
+
+	t.Run("highlight strips injected markers", func(t *testing.T) {
+		assert.Equal(t, "<mark>Hiking</mark> x", matcher.highlight("Hiking "+highlightStart+"x"+highlightStop))
+	})
+
+	t.Run("snippet", func(t *testing.T) {
+		words := strings.Fields(strings.Repeat("filler ", 50))
+		words[20] = "hike"
+		snippet := matcher.snippet(strings.Join(words, " "))
+
+		assert.True(t, strings.HasPrefix(snippet, "… "))
+		assert.True(t, strings.HasSuffix(snippet, " …"))
+		assert.Contains(t, snippet, "<mark>hike</mark>")
+		assert.Len(t, strings.Fields(strings.Trim(snippet, "… ")), snippetMaxWords)
+	})
+}
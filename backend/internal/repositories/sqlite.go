@@ -0,0 +1,355 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+
+	"github.com/google/uuid"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// SQLiteRepositoryManager implements RepositoryManager for SQLite. The
+// schema comes from migrations/sqlite; see database.OpenSQLite for the
+// connection settings it expects.
+type SQLiteRepositoryManager struct {
+	db          *sql.DB
+	tx          *sqlTxState
+	groups      GroupRepository
+	members     MemberRepository
+	bucketItems BucketItemRepository
+	tags        TagRepository
+	comments    CommentRepository
+	reactions   ReactionRepository
+	attachments AttachmentRepository
+}
+
+// NewSQLiteRepositoryManager creates a new SQLite repository manager
+func NewSQLiteRepositoryManager(db *sql.DB) *SQLiteRepositoryManager {
+	manager := newSQLiteRepositoryManager(db)
+	manager.db = db
+	return manager
+}
+
+func newSQLiteRepositoryManager(db dbExecutor) *SQLiteRepositoryManager {
+	return &SQLiteRepositoryManager{
+		groups:      &SQLiteGroupRepository{db: db},
+		members:     &SQLiteMemberRepository{db: db},
+		bucketItems: &SQLiteBucketItemRepository{db: db},
+		tags:        &SQLiteTagRepository{db: db},
+		comments:    &SQLiteCommentRepository{db: db},
+		reactions:   &SQLiteReactionRepository{db: db},
+		attachments: &SQLiteAttachmentRepository{db: db},
+	}
+}
+
+// Groups returns the group repository
+func (m *SQLiteRepositoryManager) Groups() GroupRepository {
+	return m.groups
+}
+
+// Members returns the member repository
+func (m *SQLiteRepositoryManager) Members() MemberRepository {
+	return m.members
+}
+
+// BucketItems returns the bucket item repository
+func (m *SQLiteRepositoryManager) BucketItems() BucketItemRepository {
+	return m.bucketItems
+}
+
+// Tags returns the tag repository
+func (m *SQLiteRepositoryManager) Tags() TagRepository {
+	return m.tags
+}
+
+// Comments returns the comment repository
+func (m *SQLiteRepositoryManager) Comments() CommentRepository {
+	return m.comments
+}
+
+// Reactions returns the reaction repository
+func (m *SQLiteRepositoryManager) Reactions() ReactionRepository {
+	return m.reactions
+}
+
+// Attachments returns the attachment repository
+func (m *SQLiteRepositoryManager) Attachments() AttachmentRepository {
+	return m.attachments
+}
+
+// WithTx executes a function within a database transaction
+func (m *SQLiteRepositoryManager) WithTx(ctx context.Context, fn func(repos RepositoryManager) error) error {
+	return m.WithTxOptions(ctx, TxOptions{}, fn)
+}
+
+// WithTxOptions executes a function within a database transaction, or within
+// a savepoint when the manager is already scoped to a transaction. SQLite
+// transactions are always serializable, so every isolation level is
+// accepted and nested calls may ask for any level.
+func (m *SQLiteRepositoryManager) WithTxOptions(ctx context.Context, opts TxOptions, fn func(repos RepositoryManager) error) error {
+	if m.tx != nil {
+		return runSavepoint(ctx, m.tx, IsolationDefault, func(nested *sqlTxState) error {
+			return fn(newSQLiteTxRepositoryManager(nested))
+		})
+	}
+
+	txOpts := &sql.TxOptions{ReadOnly: opts.ReadOnly}
+	return runSQLTx(ctx, m.db, txOpts, opts.maxRetries(), isSQLiteRetryable, func(tx *sql.Tx) error {
+		return fn(newSQLiteTxRepositoryManager(&sqlTxState{tx: tx, isolation: IsolationSerializable}))
+	})
+}
+
+// newSQLiteTxRepositoryManager creates a repository manager scoped to a
+// transaction
+func newSQLiteTxRepositoryManager(state *sqlTxState) *SQLiteRepositoryManager {
+	manager := newSQLiteRepositoryManager(state.tx)
+	manager.tx = state
+	return manager
+}
+
+// isSQLiteRetryable reports whether a transaction failed because another
+// connection held the database lock past the busy timeout
+func isSQLiteRetryable(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	// Extended result codes keep the primary code in the low byte
+	code := sqliteErr.Code() & 0xff
+	return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+}
+
+// sqliteTimeFormat stores timestamps as fixed-width UTC text with the
+// microsecond precision of PostgreSQL, so they compare chronologically as
+// strings and round-trip to the same values
+const sqliteTimeFormat = "2006-01-02T15:04:05.000000Z"
+
+// sqliteTime formats a timestamp for storage
+func sqliteTime(t time.Time) string {
+	return t.UTC().Round(time.Microsecond).Format(sqliteTimeFormat)
+}
+
+// sqliteNullTime formats an optional timestamp for storage
+func sqliteNullTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return sqliteTime(*t)
+}
+
+// parseSQLiteTime parses a stored timestamp
+func parseSQLiteTime(value interface{}) (time.Time, error) {
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	case time.Time:
+		return v, nil
+	default:
+		return time.Time{}, fmt.Errorf("cannot scan %T into timestamp", value)
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, text)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q: %w", text, err)
+	}
+	return t, nil
+}
+
+// sqliteTimeScanner scans a stored timestamp into a time.Time
+type sqliteTimeScanner struct {
+	dest *time.Time
+}
+
+// Scan implements sql.Scanner
+func (s sqliteTimeScanner) Scan(value interface{}) error {
+	t, err := parseSQLiteTime(value)
+	if err != nil {
+		return err
+	}
+	*s.dest = t
+	return nil
+}
+
+// sqliteNullTimeScanner scans an optional stored timestamp into a *time.Time
+type sqliteNullTimeScanner struct {
+	dest **time.Time
+}
+
+// Scan implements sql.Scanner
+func (s sqliteNullTimeScanner) Scan(value interface{}) error {
+	if value == nil {
+		*s.dest = nil
+		return nil
+	}
+	t, err := parseSQLiteTime(value)
+	if err != nil {
+		return err
+	}
+	*s.dest = &t
+	return nil
+}
+
+// sqliteUUID returns the canonical lowercase form of an ID being written.
+// Malformed IDs are rejected, as PostgreSQL's uuid type rejects them.
+func sqliteUUID(id string) (string, error) {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return "", fmt.Errorf("invalid UUID %q", id)
+	}
+	return parsed.String(), nil
+}
+
+// sqliteNullUUID is sqliteUUID for optional IDs
+func sqliteNullUUID(id *string) (interface{}, error) {
+	if id == nil {
+		return nil, nil
+	}
+	return sqliteUUID(*id)
+}
+
+// sqliteID returns the canonical form of an ID being looked up, so IDs match
+// regardless of case as they do in PostgreSQL. Malformed IDs are passed
+// through and simply match nothing.
+func sqliteID(id string) string {
+	if parsed, err := uuid.Parse(id); err == nil {
+		return parsed.String()
+	}
+	return id
+}
+
+// mapSQLiteError translates a SQLite constraint violation into a domain
+// error. SQLite does not report which foreign key failed, so the caller
+// passes the error that a foreign key violation stands for; other errors
+// are returned unchanged.
+func mapSQLiteError(err error, foreignKeyErr error) error {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		return fmt.Errorf("%w (primary key)", ErrDuplicateID)
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE:
+		if strings.Contains(sqliteErr.Error(), "members.group_id, members.user_id") {
+			return fmt.Errorf("%w (members_group_user_unique)", ErrAlreadyMember)
+		}
+		if strings.Contains(sqliteErr.Error(), "tags_group_name_unique") {
+			return fmt.Errorf("%w (tags_group_name_unique)", ErrDuplicateTagName)
+		}
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		if foreignKeyErr != nil {
+			return fmt.Errorf("%w (foreign key)", foreignKeyErr)
+		}
+	}
+	return err
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+const sqliteGroupColumns = `id, name, deadline, timezone, lock_after_deadline, created_at, created_by`
+
+func scanSQLiteGroup(row rowScanner, group *models.Group) error {
+	err := row.Scan(&group.ID, &group.Name, sqliteNullTimeScanner{&group.Deadline},
+		&group.Timezone, &group.LockAfterDeadline, sqliteTimeScanner{&group.CreatedAt}, &group.CreatedBy)
+	if err != nil {
+		return err
+	}
+	group.Localize()
+	return nil
+}
+
+const sqliteMemberColumns = `id, group_id, user_id, name, joined_at, is_creator`
+
+func scanSQLiteMember(row rowScanner, member *models.Member) error {
+	return row.Scan(&member.ID, &member.GroupID, &member.UserID, &member.Name,
+		sqliteTimeScanner{&member.JoinedAt}, &member.IsCreator)
+}
+
+const sqliteItemColumns = `id, group_id, title, description, completed, completed_by,
+	completed_at, completion_note, completion_rating, occurred_on, scheduled_start, scheduled_end,
+	created_by, created_at`
+
+func scanSQLiteItem(row rowScanner, item *models.BucketListItem, extra ...interface{}) error {
+	return row.Scan(append([]interface{}{&item.ID, &item.GroupID, &item.Title, &item.Description,
+		&item.Completed, &item.CompletedBy, sqliteNullTimeScanner{&item.CompletedAt},
+		&item.CompletionNote, &item.CompletionRating, &item.OccurredOn,
+		sqliteNullTimeScanner{&item.ScheduledStart}, sqliteNullTimeScanner{&item.ScheduledEnd},
+		&item.CreatedBy, sqliteTimeScanner{&item.CreatedAt}}, extra...)...)
+}
+
+// querySQLiteMembers runs a query selecting sqliteMemberColumns
+func querySQLiteMembers(ctx context.Context, db dbExecutor, query string, args ...interface{}) ([]models.Member, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []models.Member
+	for rows.Next() {
+		var member models.Member
+		if err := scanSQLiteMember(rows, &member); err != nil {
+			return nil, fmt.Errorf("failed to scan member: %w", err)
+		}
+		members = append(members, member)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating members: %w", err)
+	}
+
+	return members, nil
+}
+
+// querySQLiteItems runs a query selecting sqliteItemColumns
+func querySQLiteItems(ctx context.Context, db dbExecutor, query string, args ...interface{}) ([]models.BucketListItem, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.BucketListItem
+	for rows.Next() {
+		var item models.BucketListItem
+		if err := scanSQLiteItem(rows, &item); err != nil {
+			return nil, fmt.Errorf("failed to scan bucket item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bucket items: %w", err)
+	}
+
+	return items, nil
+}
+
+// rowsAffectedOrNotFound returns notFound when an update or delete matched
+// no rows
+func rowsAffectedOrNotFound(result sql.Result, notFound error) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return notFound
+	}
+
+	return nil
+}
@@ -0,0 +1,251 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// SQLiteAttachmentRepository implements AttachmentRepository for SQLite
+type SQLiteAttachmentRepository struct {
+	db dbExecutor
+}
+
+// Create records a new attachment, taking its group from its item
+func (r *SQLiteAttachmentRepository) Create(ctx context.Context, attachment *models.Attachment) error {
+	if err := attachment.IsValid(); err != nil {
+		return fmt.Errorf("invalid attachment data: %w", err)
+	}
+
+	attachment.Sanitize()
+
+	id, err := sqliteUUID(attachment.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+	memberID, err := sqliteUUID(attachment.MemberID)
+	if err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	query := `
+		INSERT INTO attachments (id, item_id, group_id, member_id, file_name, content_type, size,
+			width, height, processing_status, storage_key, created_at)
+		SELECT ?, id, group_id, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		FROM bucket_items
+		WHERE id = ?
+		RETURNING group_id`
+
+	err = r.db.QueryRowContext(ctx, query, id, memberID, attachment.FileName, attachment.ContentType,
+		attachment.Size, attachment.Width, attachment.Height, attachment.ProcessingStatus,
+		attachment.StorageKey, sqliteTime(attachment.CreatedAt),
+		sqliteID(attachment.ItemID)).Scan(&attachment.GroupID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("failed to create attachment: %w", ErrItemNotFound)
+		}
+		return fmt.Errorf("failed to create attachment: %w", mapSQLiteError(err, ErrMemberNotFound))
+	}
+
+	return nil
+}
+
+// GetByID retrieves an attachment by its ID
+func (r *SQLiteAttachmentRepository) GetByID(ctx context.Context, id string) (*models.Attachment, error) {
+	query := `SELECT ` + sqliteAttachmentColumns + ` FROM attachments WHERE id = ?`
+
+	var attachment models.Attachment
+	err := scanSQLiteAttachment(r.db.QueryRowContext(ctx, query, sqliteID(id)), &attachment)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %s", ErrAttachmentNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return &attachment, nil
+}
+
+// GetByItemID retrieves the attachments of an item, oldest first
+func (r *SQLiteAttachmentRepository) GetByItemID(ctx context.Context, itemID string) ([]models.Attachment, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+sqliteAttachmentColumns+`
+		FROM attachments
+		WHERE item_id = ?
+		ORDER BY created_at ASC, id ASC`, sqliteID(itemID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments by item ID: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []models.Attachment
+	for rows.Next() {
+		var attachment models.Attachment
+		if err := scanSQLiteAttachment(rows, &attachment); err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// GetByGroupID retrieves the attachments of each item of a group that has
+// any, oldest first
+func (r *SQLiteAttachmentRepository) GetByGroupID(ctx context.Context, groupID string) (map[string][]models.Attachment, error) {
+	return querySQLiteAttachments(ctx, r.db, groupID)
+}
+
+// GetPendingProcessing returns up to limit attachments that have not been
+// processed yet, oldest first
+func (r *SQLiteAttachmentRepository) GetPendingProcessing(ctx context.Context, limit int) ([]models.Attachment, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+sqliteAttachmentColumns+`
+		FROM attachments
+		WHERE processing_status = 'pending'
+		ORDER BY created_at ASC, id ASC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []models.Attachment
+	for rows.Next() {
+		var attachment models.Attachment
+		if err := scanSQLiteAttachment(rows, &attachment); err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// UpdateProcessing records the outcome of processing an attachment: its
+// size, dimensions and processing status
+func (r *SQLiteAttachmentRepository) UpdateProcessing(ctx context.Context, attachment *models.Attachment) error {
+	if err := attachment.IsValid(); err != nil {
+		return fmt.Errorf("invalid attachment data: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE attachments
+		SET size = ?, width = ?, height = ?, processing_status = ?
+		WHERE id = ?`,
+		attachment.Size, attachment.Width, attachment.Height, attachment.ProcessingStatus, sqliteID(attachment.ID))
+	if err != nil {
+		return fmt.Errorf("failed to update attachment processing: %w", err)
+	}
+
+	return rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrAttachmentNotFound, attachment.ID))
+}
+
+// querySQLiteAttachments retrieves the attachments of each item of a group
+// that has any, oldest first
+func querySQLiteAttachments(ctx context.Context, db dbExecutor, groupID string) (map[string][]models.Attachment, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+sqliteAttachmentColumns+`
+		FROM attachments
+		WHERE group_id = ?
+		ORDER BY created_at ASC, id ASC`, sqliteID(groupID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments by group ID: %w", err)
+	}
+	defer rows.Close()
+
+	byItem := make(map[string][]models.Attachment)
+	for rows.Next() {
+		var attachment models.Attachment
+		if err := scanSQLiteAttachment(rows, &attachment); err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		byItem[attachment.ItemID] = append(byItem[attachment.ItemID], attachment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attachments: %w", err)
+	}
+
+	return byItem, nil
+}
+
+// Delete deletes an attachment by ID. A trigger queues its blob for
+// deletion.
+func (r *SQLiteAttachmentRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM attachments WHERE id = ?`, sqliteID(id))
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	return rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrAttachmentNotFound, id))
+}
+
+// PendingBlobDeletions returns up to limit storage keys of deleted
+// attachments whose blobs still need deleting, oldest first
+func (r *SQLiteAttachmentRepository) PendingBlobDeletions(ctx context.Context, limit int) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT storage_key
+		FROM blob_deletions
+		ORDER BY queued_at ASC, storage_key ASC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending blob deletions: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan blob deletion: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating blob deletions: %w", err)
+	}
+
+	return keys, nil
+}
+
+// ClearBlobDeletions removes storage keys whose blobs have been deleted
+// from the queue
+func (r *SQLiteAttachmentRepository) ClearBlobDeletions(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	encoded, err := json.Marshal(keys)
+	if err != nil {
+		return fmt.Errorf("failed to clear blob deletions: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		DELETE FROM blob_deletions WHERE storage_key IN (SELECT value FROM json_each(?))`, string(encoded))
+	if err != nil {
+		return fmt.Errorf("failed to clear blob deletions: %w", err)
+	}
+
+	return nil
+}
+
+const sqliteAttachmentColumns = `id, item_id, group_id, member_id, file_name, content_type, size, width, height, processing_status, storage_key, created_at`
+
+func scanSQLiteAttachment(row rowScanner, attachment *models.Attachment) error {
+	return row.Scan(&attachment.ID, &attachment.ItemID, &attachment.GroupID, &attachment.MemberID,
+		&attachment.FileName, &attachment.ContentType, &attachment.Size, &attachment.Width, &attachment.Height,
+		&attachment.ProcessingStatus, &attachment.StorageKey, sqliteTimeScanner{&attachment.CreatedAt})
+}
@@ -0,0 +1,508 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// SQLiteBucketItemRepository implements BucketItemRepository for SQLite
+type SQLiteBucketItemRepository struct {
+	db dbExecutor
+}
+
+// Create creates a new bucket list item
+func (r *SQLiteBucketItemRepository) Create(ctx context.Context, item *models.BucketListItem) error {
+	if err := item.IsValid(); err != nil {
+		return fmt.Errorf("invalid bucket item data: %w", err)
+	}
+
+	item.Sanitize()
+
+	id, err := sqliteUUID(item.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create bucket item: %w", err)
+	}
+	groupID, err := sqliteUUID(item.GroupID)
+	if err != nil {
+		return fmt.Errorf("failed to create bucket item: %w", err)
+	}
+	createdBy, err := sqliteNullUUID(item.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("failed to create bucket item: %w", err)
+	}
+	completedBy, err := sqliteNullUUID(item.CompletedBy)
+	if err != nil {
+		return fmt.Errorf("failed to create bucket item: %w", err)
+	}
+
+	query := `
+		INSERT INTO bucket_items (id, group_id, title, description, completed,
+								 completed_by, completed_at, completion_note, completion_rating,
+								 occurred_on, scheduled_start, scheduled_end, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		id, groupID, item.Title, item.Description, item.Completed,
+		completedBy, sqliteNullTime(item.CompletedAt), item.CompletionNote, item.CompletionRating,
+		item.OccurredOn, sqliteNullTime(item.ScheduledStart), sqliteNullTime(item.ScheduledEnd),
+		createdBy, sqliteTime(item.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to create bucket item: %w", mapSQLiteError(err, r.missingReference(ctx, groupID)))
+	}
+
+	return nil
+}
+
+// missingReference tells which reference of a new item failed its foreign
+// key: the group if it does not exist, otherwise a member
+func (r *SQLiteBucketItemRepository) missingReference(ctx context.Context, groupID string) error {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM groups WHERE id = ?)`, groupID).Scan(&exists)
+	if err == nil && !exists {
+		return ErrGroupNotFound
+	}
+	return ErrMemberNotFound
+}
+
+// GetByID retrieves a bucket list item by its ID
+func (r *SQLiteBucketItemRepository) GetByID(ctx context.Context, id string) (*models.BucketListItem, error) {
+	query := `SELECT ` + sqliteItemColumns + ` FROM bucket_items WHERE id = ?`
+
+	var item models.BucketListItem
+	err := scanSQLiteItem(r.db.QueryRowContext(ctx, query, sqliteID(id)), &item)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %s", ErrItemNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get bucket item: %w", err)
+	}
+
+	items := []models.BucketListItem{item}
+	if err := loadSQLiteItemLinks(ctx, r.db, items); err != nil {
+		return nil, err
+	}
+
+	return &items[0], nil
+}
+
+// GetByGroupID retrieves all items for a specific group
+func (r *SQLiteBucketItemRepository) GetByGroupID(ctx context.Context, groupID string) ([]models.BucketListItem, error) {
+	items, err := querySQLiteItems(ctx, r.db, `
+		SELECT `+sqliteItemColumns+`
+		FROM bucket_items
+		WHERE group_id = ?
+		ORDER BY created_at DESC`, sqliteID(groupID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bucket items by group ID: %w", err)
+	}
+
+	if err := loadSQLiteItemLinks(ctx, r.db, items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// List retrieves a filtered, sorted page of a group's items
+func (r *SQLiteBucketItemRepository) List(ctx context.Context, groupID string, opts models.ItemListOptions) (*models.ItemPage, error) {
+	ks, err := itemKeyset(opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := ks.decode(opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	limit := models.PageLimit(opts.Limit)
+
+	b := newSQLiteQueryBuilder()
+	query := buildItemListQuery(b, sqliteItemColumns, groupID, opts, ks, cursor, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bucket items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.BucketListItem
+	var sortKeys []string
+	for rows.Next() {
+		var item models.BucketListItem
+		var sortKey string
+		if err := scanSQLiteItem(rows, &item, &sortKey); err != nil {
+			return nil, fmt.Errorf("failed to scan bucket item: %w", err)
+		}
+		items = append(items, item)
+		sortKeys = append(sortKeys, sortKey)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bucket items: %w", err)
+	}
+
+	if err := loadSQLiteItemLinks(ctx, r.db, items); err != nil {
+		return nil, err
+	}
+
+	return itemPage(items, sortKeys, ks, limit), nil
+}
+
+// Update updates an existing bucket list item
+func (r *SQLiteBucketItemRepository) Update(ctx context.Context, item *models.BucketListItem) error {
+	if err := item.IsValid(); err != nil {
+		return fmt.Errorf("invalid bucket item data: %w", err)
+	}
+
+	item.Sanitize()
+
+	completedBy, err := sqliteNullUUID(item.CompletedBy)
+	if err != nil {
+		return fmt.Errorf("failed to update bucket item: %w", err)
+	}
+
+	query := `
+		UPDATE bucket_items
+		SET title = ?, description = ?, completed = ?, completed_by = ?, completed_at = ?,
+			completion_note = ?, completion_rating = ?, occurred_on = ?,
+			scheduled_start = ?, scheduled_end = ?
+		WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query,
+		item.Title, item.Description, item.Completed, completedBy,
+		sqliteNullTime(item.CompletedAt), item.CompletionNote, item.CompletionRating,
+		item.OccurredOn, sqliteNullTime(item.ScheduledStart), sqliteNullTime(item.ScheduledEnd),
+		sqliteID(item.ID))
+	if err != nil {
+		return fmt.Errorf("failed to update bucket item: %w", mapSQLiteError(err, ErrMemberNotFound))
+	}
+
+	return rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrItemNotFound, item.ID))
+}
+
+// Delete deletes a bucket list item by ID
+func (r *SQLiteBucketItemRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM bucket_items WHERE id = ?`, sqliteID(id))
+	if err != nil {
+		return fmt.Errorf("failed to delete bucket item: %w", err)
+	}
+
+	return rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrItemNotFound, id))
+}
+
+// ToggleCompletion toggles the completion status of an item
+func (r *SQLiteBucketItemRepository) ToggleCompletion(ctx context.Context, itemID, memberID string, completed bool) error {
+	var query string
+	var args []interface{}
+
+	if completed {
+		// Mark as completed
+		completedBy, err := sqliteUUID(memberID)
+		if err != nil {
+			return fmt.Errorf("failed to toggle completion status: %w", err)
+		}
+		query = `
+			UPDATE bucket_items
+			SET completed = 1, completed_by = ?, completed_at = ?
+			WHERE id = ?`
+		args = []interface{}{completedBy, sqliteTime(time.Now()), sqliteID(itemID)}
+	} else {
+		// Mark as not completed
+		query = `
+			UPDATE bucket_items
+			SET completed = 0, completed_by = NULL, completed_at = NULL,
+				completion_note = NULL, completion_rating = NULL, occurred_on = NULL
+			WHERE id = ?`
+		args = []interface{}{sqliteID(itemID)}
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to toggle completion status: %w", mapSQLiteError(err, ErrMemberNotFound))
+	}
+
+	return rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrItemNotFound, itemID))
+}
+
+// UpdateCompletionJournal replaces the journal of a completed item
+func (r *SQLiteBucketItemRepository) UpdateCompletionJournal(ctx context.Context, itemID string, journal models.CompletionJournal) error {
+	journal.Sanitize()
+	if validation := journal.Validate(); !validation.IsValid {
+		return fmt.Errorf("invalid completion journal: %s", validation.Errors[0].Message)
+	}
+
+	query := `
+		UPDATE bucket_items
+		SET completion_note = ?, completion_rating = ?, occurred_on = ?
+		WHERE id = ? AND completed = 1`
+
+	result, err := r.db.ExecContext(ctx, query,
+		journal.CompletionNote, journal.CompletionRating, journal.OccurredOn, sqliteID(itemID))
+	if err != nil {
+		return fmt.Errorf("failed to update completion journal: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		if _, err := r.GetByID(ctx, itemID); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: %s", ErrItemNotCompleted, itemID)
+	}
+
+	return nil
+}
+
+// UpdateSchedule replaces the schedule of an item
+func (r *SQLiteBucketItemRepository) UpdateSchedule(ctx context.Context, itemID string, schedule models.ItemSchedule) error {
+	if validation := schedule.Validate(nil); !validation.IsValid {
+		return fmt.Errorf("invalid item schedule: %s", validation.Errors[0].Message)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE bucket_items
+		SET scheduled_start = ?, scheduled_end = ?
+		WHERE id = ?`,
+		sqliteNullTime(schedule.ScheduledStart), sqliteNullTime(schedule.ScheduledEnd), sqliteID(itemID))
+	if err != nil {
+		return fmt.Errorf("failed to update item schedule: %w", err)
+	}
+
+	return rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrItemNotFound, itemID))
+}
+
+// SetAssignment assigns a member to organize an item or removes the
+// assignment
+func (r *SQLiteBucketItemRepository) SetAssignment(ctx context.Context, itemID, memberID string, assigned bool) error {
+	if !assigned {
+		_, err := r.db.ExecContext(ctx, `DELETE FROM item_assignees WHERE item_id = ? AND member_id = ?`,
+			sqliteID(itemID), sqliteID(memberID))
+		if err != nil {
+			return fmt.Errorf("failed to unassign item: %w", err)
+		}
+		return nil
+	}
+
+	item, err := sqliteUUID(itemID)
+	if err != nil {
+		return fmt.Errorf("failed to assign item: %w", err)
+	}
+	member, err := sqliteUUID(memberID)
+	if err != nil {
+		return fmt.Errorf("failed to assign item: %w", err)
+	}
+
+	err = checkAssignable(ctx, r.db, itemID, `
+		SELECT (SELECT group_id FROM bucket_items WHERE id = ?),
+			   (SELECT group_id FROM members WHERE id = ?)`, item, member)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO item_assignees (item_id, member_id, assigned_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (item_id, member_id) DO NOTHING`
+
+	_, err = r.db.ExecContext(ctx, query, item, member, sqliteTime(time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to assign item: %w", mapSQLiteError(err, ErrMemberNotFound))
+	}
+
+	return nil
+}
+
+// GetAssignedTo retrieves the items assigned to any of the members, newest
+// first
+func (r *SQLiteBucketItemRepository) GetAssignedTo(ctx context.Context, memberIDs []string) ([]models.BucketListItem, error) {
+	members := make([]string, len(memberIDs))
+	for i, id := range memberIDs {
+		members[i] = sqliteID(id)
+	}
+	ids, err := json.Marshal(members)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assigned items: %w", err)
+	}
+
+	items, err := querySQLiteItems(ctx, r.db, `
+		SELECT `+sqliteItemColumns+`
+		FROM bucket_items
+		WHERE id IN (SELECT item_id FROM item_assignees WHERE member_id IN (SELECT value FROM json_each(?)))
+		ORDER BY created_at DESC, id`, string(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assigned items: %w", err)
+	}
+
+	if err := loadSQLiteItemLinks(ctx, r.db, items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// GetCompletionStats returns completion statistics for a group
+func (r *SQLiteBucketItemRepository) GetCompletionStats(ctx context.Context, groupID string) (total, completed int, err error) {
+	query := `
+		SELECT COUNT(*), COALESCE(SUM(completed), 0)
+		FROM bucket_items
+		WHERE group_id = ?`
+
+	err = r.db.QueryRowContext(ctx, query, sqliteID(groupID)).Scan(&total, &completed)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get completion stats: %w", err)
+	}
+
+	return total, completed, nil
+}
+
+// GetCompletionStatsByTag returns completion statistics for every tag of a
+// group
+func (r *SQLiteBucketItemRepository) GetCompletionStatsByTag(ctx context.Context, groupID string) ([]models.TagStats, error) {
+	query := `
+		SELECT t.id, t.group_id, t.name, t.color, t.created_at,
+			COUNT(bi.id), COALESCE(SUM(bi.completed), 0)
+		FROM tags t
+		LEFT JOIN item_tags it ON it.tag_id = t.id
+		LEFT JOIN bucket_items bi ON bi.id = it.item_id
+		WHERE t.group_id = ?
+		GROUP BY t.id
+		ORDER BY lower(t.name), t.id`
+
+	rows, err := r.db.QueryContext(ctx, query, sqliteID(groupID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag completion stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []models.TagStats
+	for rows.Next() {
+		var tagStats models.TagStats
+		err := rows.Scan(&tagStats.ID, &tagStats.GroupID, &tagStats.Name, &tagStats.Color,
+			sqliteTimeScanner{&tagStats.CreatedAt}, &tagStats.ItemCount, &tagStats.CompletedCount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tag completion stats: %w", err)
+		}
+		stats = append(stats, tagStats)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tag completion stats: %w", err)
+	}
+
+	setTagProgress(stats)
+	return stats, nil
+}
+
+// Search finds the items of a group matching a query. SQLite has no
+// full-text index here, so items are matched in Go.
+func (r *SQLiteBucketItemRepository) Search(ctx context.Context, groupID, query string, limit int) ([]models.ItemSearchResult, error) {
+	return r.search(ctx, `bi.group_id = ?1`, sqliteID(groupID), query, limit)
+}
+
+// SearchByUserID finds matching items in every group a user created or
+// belongs to
+func (r *SQLiteBucketItemRepository) SearchByUserID(ctx context.Context, userID, query string, limit int) ([]models.ItemSearchResult, error) {
+	return r.search(ctx, `(g.created_by = ?1 OR g.id IN (SELECT group_id FROM members WHERE user_id = ?1))`,
+		sqliteID(userID), query, limit)
+}
+
+// search matches the items selected by scope, which refers to its argument
+// as ?1
+func (r *SQLiteBucketItemRepository) search(ctx context.Context, scope, scopeArg, query string, limit int) ([]models.ItemSearchResult, error) {
+	matcher := newTextMatcher(query)
+	if len(matcher.terms) == 0 {
+		return nil, nil
+	}
+
+	sqlQuery := `
+		SELECT ` + qualifyColumns("bi", sqliteItemColumns) + `, g.name
+		FROM bucket_items bi
+		JOIN groups g ON g.id = bi.group_id
+		WHERE ` + scope
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, scopeArg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search bucket items: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.ItemSearchResult
+	for rows.Next() {
+		var item models.BucketListItem
+		var groupName string
+		if err := scanSQLiteItem(rows, &item, &groupName); err != nil {
+			return nil, fmt.Errorf("failed to scan bucket item: %w", err)
+		}
+		if rank, ok := matcher.rank(item.Title, item.Description); ok {
+			results = append(results, matcher.itemSearchResult(item, groupName, rank))
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bucket items: %w", err)
+	}
+
+	results = sortItemSearchResults(results, limit)
+	err = loadSearchResultLinks(results, func(items []models.BucketListItem) error {
+		return loadSQLiteItemLinks(ctx, r.db, items)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// loadSQLiteItemLinks fills in the tags and assignees of items
+func loadSQLiteItemLinks(ctx context.Context, db dbExecutor, items []models.BucketListItem) error {
+	if err := loadSQLiteItemTags(ctx, db, items); err != nil {
+		return err
+	}
+	return loadSQLiteItemAssignees(ctx, db, items)
+}
+
+// loadSQLiteItemAssignees fills in the assignees of items
+func loadSQLiteItemAssignees(ctx context.Context, db dbExecutor, items []models.BucketListItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	ids, err := json.Marshal(itemIDs(items))
+	if err != nil {
+		return fmt.Errorf("failed to get item assignees: %w", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT item_id, member_id
+		FROM item_assignees
+		WHERE item_id IN (SELECT value FROM json_each(?))
+		ORDER BY item_id, assigned_at, member_id`, string(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get item assignees: %w", err)
+	}
+	defer rows.Close()
+
+	assigneeIDs := make(map[string][]string)
+	for rows.Next() {
+		var itemID, memberID string
+		if err := rows.Scan(&itemID, &memberID); err != nil {
+			return fmt.Errorf("failed to scan item assignee: %w", err)
+		}
+		assigneeIDs[itemID] = append(assigneeIDs[itemID], memberID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating item assignees: %w", err)
+	}
+
+	setItemAssigneeIDs(items, assigneeIDs)
+	return nil
+}
@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// SQLiteCommentRepository implements CommentRepository for SQLite
+type SQLiteCommentRepository struct {
+	db dbExecutor
+}
+
+// Create creates a new comment, taking its group from its item
+func (r *SQLiteCommentRepository) Create(ctx context.Context, comment *models.Comment) error {
+	if err := comment.IsValid(); err != nil {
+		return fmt.Errorf("invalid comment data: %w", err)
+	}
+
+	comment.Sanitize()
+
+	id, err := sqliteUUID(comment.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+	memberID, err := sqliteUUID(comment.MemberID)
+	if err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+	parentID, err := sqliteNullUUID(comment.ParentID)
+	if err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	if comment.ParentID != nil {
+		parent, err := r.GetByID(ctx, *comment.ParentID)
+		if err != nil {
+			return fmt.Errorf("failed to create comment: %w", err)
+		}
+		if err := checkReplyParent(*parent, sqliteID(comment.ItemID)); err != nil {
+			return fmt.Errorf("failed to create comment: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO comments (id, item_id, group_id, parent_id, member_id, body, created_at)
+		SELECT ?, id, group_id, ?, ?, ?, ?
+		FROM bucket_items
+		WHERE id = ?
+		RETURNING group_id`
+
+	err = r.db.QueryRowContext(ctx, query, id, parentID, memberID, comment.Body,
+		sqliteTime(comment.CreatedAt), sqliteID(comment.ItemID)).Scan(&comment.GroupID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("failed to create comment: %w", ErrItemNotFound)
+		}
+		return fmt.Errorf("failed to create comment: %w", mapSQLiteError(err, ErrMemberNotFound))
+	}
+
+	return nil
+}
+
+// GetByID retrieves a comment by its ID
+func (r *SQLiteCommentRepository) GetByID(ctx context.Context, id string) (*models.Comment, error) {
+	query := `SELECT ` + sqliteCommentColumns + ` FROM comments WHERE id = ?`
+
+	var comment models.Comment
+	err := scanSQLiteComment(r.db.QueryRowContext(ctx, query, sqliteID(id)), &comment)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %s", ErrCommentNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	return &comment, nil
+}
+
+// GetByItemID retrieves the comment threads of an item, oldest first
+func (r *SQLiteCommentRepository) GetByItemID(ctx context.Context, itemID string) ([]models.CommentThread, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+sqliteCommentColumns+`
+		FROM comments
+		WHERE item_id = ?
+		ORDER BY created_at ASC, id ASC`, sqliteID(itemID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments by item ID: %w", err)
+	}
+	defer rows.Close()
+
+	var comments []models.Comment
+	for rows.Next() {
+		var comment models.Comment
+		if err := scanSQLiteComment(rows, &comment); err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comments: %w", err)
+	}
+
+	return commentThreads(comments), nil
+}
+
+// Update edits the body of a comment and sets its edited time
+func (r *SQLiteCommentRepository) Update(ctx context.Context, comment *models.Comment) error {
+	if err := comment.IsValid(); err != nil {
+		return fmt.Errorf("invalid comment data: %w", err)
+	}
+
+	comment.Sanitize()
+	editedAt := time.Now()
+
+	result, err := r.db.ExecContext(ctx, `UPDATE comments SET body = ?, edited_at = ? WHERE id = ?`,
+		comment.Body, sqliteTime(editedAt), sqliteID(comment.ID))
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	if err := rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrCommentNotFound, comment.ID)); err != nil {
+		return err
+	}
+	comment.EditedAt = &editedAt
+	return nil
+}
+
+// Delete deletes a comment by ID. The foreign key deletes its replies.
+func (r *SQLiteCommentRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM comments WHERE id = ?`, sqliteID(id))
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	return rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrCommentNotFound, id))
+}
+
+// CountByGroupID returns the number of comments on each item of a group
+// that has any
+func (r *SQLiteCommentRepository) CountByGroupID(ctx context.Context, groupID string) (map[string]int, error) {
+	counts, err := querySQLiteCommentCounts(ctx, r.db, sqliteID(groupID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	return counts, nil
+}
+
+const sqliteCommentColumns = `id, item_id, group_id, parent_id, member_id, body, created_at, edited_at`
+
+func scanSQLiteComment(row rowScanner, comment *models.Comment) error {
+	return row.Scan(&comment.ID, &comment.ItemID, &comment.GroupID, &comment.ParentID,
+		&comment.MemberID, &comment.Body, sqliteTimeScanner{&comment.CreatedAt},
+		sqliteNullTimeScanner{&comment.EditedAt})
+}
+
+// querySQLiteCommentCounts counts the comments of each item of a group that
+// has any
+func querySQLiteCommentCounts(ctx context.Context, db dbExecutor, groupID string) (map[string]int, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT item_id, COUNT(*)
+		FROM comments
+		WHERE group_id = ?
+		GROUP BY item_id`, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var itemID string
+		var count int
+		if err := rows.Scan(&itemID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan comment count: %w", err)
+		}
+		counts[itemID] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comment counts: %w", err)
+	}
+
+	return counts, nil
+}
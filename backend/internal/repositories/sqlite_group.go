@@ -0,0 +1,499 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// SQLiteGroupRepository implements GroupRepository for SQLite
+type SQLiteGroupRepository struct {
+	db dbExecutor
+}
+
+// Create creates a new group
+func (r *SQLiteGroupRepository) Create(ctx context.Context, group *models.Group) error {
+	if err := group.IsValid(); err != nil {
+		return fmt.Errorf("invalid group data: %w", err)
+	}
+
+	group.Sanitize()
+
+	id, err := sqliteUUID(group.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create group: %w", err)
+	}
+	createdBy, err := sqliteUUID(group.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("failed to create group: %w", err)
+	}
+
+	query := `
+		INSERT INTO groups (id, name, deadline, timezone, lock_after_deadline, created_at, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		id, group.Name, sqliteNullTime(group.Deadline), group.Timezone, group.LockAfterDeadline, sqliteTime(group.CreatedAt), createdBy)
+	if err != nil {
+		return fmt.Errorf("failed to create group: %w", mapSQLiteError(err, nil))
+	}
+
+	return nil
+}
+
+// GetByID retrieves a group by its ID
+func (r *SQLiteGroupRepository) GetByID(ctx context.Context, id string) (*models.Group, error) {
+	query := `SELECT ` + sqliteGroupColumns + ` FROM groups WHERE id = ?`
+
+	var group models.Group
+	err := scanSQLiteGroup(r.db.QueryRowContext(ctx, query, sqliteID(id)), &group)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %s", ErrGroupNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+
+	return &group, nil
+}
+
+// GetByUserID retrieves all groups created by a specific user
+func (r *SQLiteGroupRepository) GetByUserID(ctx context.Context, userID string) ([]models.Group, error) {
+	query := `
+		SELECT ` + sqliteGroupColumns + `
+		FROM groups
+		WHERE created_by = ?
+		ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, sqliteID(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups by user ID: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []models.Group
+	for rows.Next() {
+		var group models.Group
+		if err := scanSQLiteGroup(rows, &group); err != nil {
+			return nil, fmt.Errorf("failed to scan group: %w", err)
+		}
+		groups = append(groups, group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+// GetByDeadlineBetween retrieves the groups whose deadline is after after
+// and no later than until, soonest first
+func (r *SQLiteGroupRepository) GetByDeadlineBetween(ctx context.Context, after, until time.Time) ([]models.Group, error) {
+	query := `
+		SELECT ` + sqliteGroupColumns + `
+		FROM groups
+		WHERE deadline > ? AND deadline <= ?
+		ORDER BY deadline ASC, id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, sqliteTime(after), sqliteTime(until))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups by deadline: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []models.Group
+	for rows.Next() {
+		var group models.Group
+		if err := scanSQLiteGroup(rows, &group); err != nil {
+			return nil, fmt.Errorf("failed to scan group: %w", err)
+		}
+		groups = append(groups, group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+// Update updates an existing group
+func (r *SQLiteGroupRepository) Update(ctx context.Context, group *models.Group) error {
+	if err := group.IsValid(); err != nil {
+		return fmt.Errorf("invalid group data: %w", err)
+	}
+
+	group.Sanitize()
+
+	query := `UPDATE groups SET name = ?, deadline = ?, timezone = ?, lock_after_deadline = ? WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query,
+		group.Name, sqliteNullTime(group.Deadline), group.Timezone, group.LockAfterDeadline, sqliteID(group.ID))
+	if err != nil {
+		return fmt.Errorf("failed to update group: %w", err)
+	}
+
+	return rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrGroupNotFound, group.ID))
+}
+
+// Delete deletes a group by ID, along with its members and items
+func (r *SQLiteGroupRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM groups WHERE id = ?`, sqliteID(id))
+	if err != nil {
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+
+	return rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrGroupNotFound, id))
+}
+
+// GetWithDetails retrieves a group with all its members and items
+func (r *SQLiteGroupRepository) GetWithDetails(ctx context.Context, id string) (*models.GroupWithDetails, error) {
+	group, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := querySQLiteMembers(ctx, r.db, `
+		SELECT `+sqliteMemberColumns+`
+		FROM members
+		WHERE group_id = ?
+		ORDER BY joined_at ASC`, group.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group members: %w", err)
+	}
+
+	items, err := querySQLiteItems(ctx, r.db, `
+		SELECT `+sqliteItemColumns+`
+		FROM bucket_items
+		WHERE group_id = ?
+		ORDER BY created_at DESC`, group.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bucket items: %w", err)
+	}
+	if err := loadSQLiteItemLinks(ctx, r.db, items); err != nil {
+		return nil, err
+	}
+
+	tags, err := querySQLiteTags(ctx, r.db, group.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group tags: %w", err)
+	}
+
+	counts, err := querySQLiteCommentCounts(ctx, r.db, group.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	reactions, err := querySQLiteReactions(ctx, r.db, sqliteGroupItemsCondition, group.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item reactions: %w", err)
+	}
+
+	attachments, err := querySQLiteAttachments(ctx, r.db, group.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item attachments: %w", err)
+	}
+
+	return &models.GroupWithDetails{
+		Group:         *group,
+		Members:       members,
+		Items:         items,
+		Tags:          tags,
+		CommentCounts: itemCommentCounts(items, counts),
+		Reactions:     itemReactions(items, reactions),
+		Attachments:   itemAttachments(items, attachments),
+	}, nil
+}
+
+// GetSummariesByUserID retrieves group summaries for a user's dashboard
+func (r *SQLiteGroupRepository) GetSummariesByUserID(ctx context.Context, userID string) ([]models.GroupSummary, error) {
+	query := `
+		SELECT
+			g.id, g.name, g.deadline, g.timezone, g.lock_after_deadline, g.created_at, g.created_by,
+			(SELECT COUNT(*) FROM members m WHERE m.group_id = g.id) AS member_count,
+			(SELECT COUNT(*) FROM bucket_items bi WHERE bi.group_id = g.id) AS item_count,
+			(SELECT COUNT(*) FROM bucket_items bi WHERE bi.group_id = g.id AND bi.completed = 1) AS completed_count
+		FROM groups g
+		WHERE g.created_by = ?1 OR g.id IN (
+			SELECT group_id FROM members WHERE user_id = ?1
+		)
+		ORDER BY g.created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, sqliteID(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group summaries: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []models.GroupSummary
+	for rows.Next() {
+		var summary models.GroupSummary
+		err := rows.Scan(
+			&summary.ID, &summary.Name, sqliteNullTimeScanner{&summary.Deadline}, &summary.Timezone, &summary.LockAfterDeadline,
+			sqliteTimeScanner{&summary.CreatedAt}, &summary.CreatedBy,
+			&summary.MemberCount, &summary.ItemCount, &summary.CompletedCount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan group summary: %w", err)
+		}
+		summary.Localize()
+
+		// Calculate progress percentage
+		if summary.ItemCount > 0 {
+			summary.ProgressPercent = float64(summary.CompletedCount) / float64(summary.ItemCount) * 100
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating group summaries: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// ListSummariesByUserID retrieves a filtered, sorted page of a user's group
+// summaries
+func (r *SQLiteGroupRepository) ListSummariesByUserID(ctx context.Context, userID string, opts models.GroupSummaryListOptions) (*models.GroupSummaryPage, error) {
+	ks, err := groupKeyset(opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := ks.decode(opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	limit := models.PageLimit(opts.Limit)
+
+	b := newSQLiteQueryBuilder()
+	user := b.idArg(userID)
+	userSummaries := `
+			SELECT g.id, g.name, g.deadline, g.timezone, g.lock_after_deadline, g.created_at, g.created_by,
+				(SELECT COUNT(*) FROM members m WHERE m.group_id = g.id) AS member_count,
+				(SELECT COUNT(*) FROM bucket_items bi WHERE bi.group_id = g.id) AS item_count,
+				(SELECT COUNT(*) FROM bucket_items bi WHERE bi.group_id = g.id AND bi.completed = 1) AS completed_count
+			FROM groups g
+			WHERE g.created_by = ` + user + ` OR g.id IN (SELECT group_id FROM members WHERE user_id = ` + user + `)`
+	query := buildSummaryListQuery(b, userSummaries, "CAST(completed_count AS REAL) / item_count * 100", opts, ks, cursor, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list group summaries: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []models.GroupSummary
+	for rows.Next() {
+		var summary models.GroupSummary
+		err := rows.Scan(
+			&summary.ID, &summary.Name, sqliteNullTimeScanner{&summary.Deadline}, &summary.Timezone, &summary.LockAfterDeadline,
+			sqliteTimeScanner{&summary.CreatedAt}, &summary.CreatedBy,
+			&summary.MemberCount, &summary.ItemCount, &summary.CompletedCount, &summary.ProgressPercent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan group summary: %w", err)
+		}
+		summary.Localize()
+		summaries = append(summaries, summary)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating group summaries: %w", err)
+	}
+
+	return summaryPage(summaries, ks, limit), nil
+}
+
+// SearchByUserID searches the names of the groups a user created or belongs
+// to. SQLite has no full-text index here, so names are matched in Go.
+func (r *SQLiteGroupRepository) SearchByUserID(ctx context.Context, userID, query string, limit int) ([]models.GroupSearchResult, error) {
+	matcher := newTextMatcher(query)
+	if len(matcher.terms) == 0 {
+		return nil, nil
+	}
+
+	sqlQuery := `
+		SELECT ` + sqliteGroupColumns + `
+		FROM groups
+		WHERE created_by = ?1 OR id IN (SELECT group_id FROM members WHERE user_id = ?1)`
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, sqliteID(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search groups: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.GroupSearchResult
+	for rows.Next() {
+		var group models.Group
+		if err := scanSQLiteGroup(rows, &group); err != nil {
+			return nil, fmt.Errorf("failed to scan group: %w", err)
+		}
+		if rank, ok := matcher.rank(group.Name, nil); ok {
+			results = append(results, matcher.groupSearchResult(group, rank))
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating groups: %w", err)
+	}
+
+	return sortGroupSearchResults(results, limit), nil
+}
+
+// GetCalendarTokenHash returns the hash of the token of a group's calendar
+// feed
+func (r *SQLiteGroupRepository) GetCalendarTokenHash(ctx context.Context, groupID string) (string, error) {
+	query := `SELECT calendar_token_hash FROM groups WHERE id = ?`
+
+	var tokenHash sql.NullString
+	if err := r.db.QueryRowContext(ctx, query, sqliteID(groupID)).Scan(&tokenHash); err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("%w: %s", ErrGroupNotFound, groupID)
+		}
+		return "", fmt.Errorf("failed to get calendar token: %w", err)
+	}
+
+	return tokenHash.String, nil
+}
+
+// EnsureCalendarTokenHash enables a group's calendar feed unless it already is
+func (r *SQLiteGroupRepository) EnsureCalendarTokenHash(ctx context.Context, groupID, candidateHash string) (string, error) {
+	query := `
+		UPDATE groups
+		SET calendar_token_hash = COALESCE(calendar_token_hash, ?)
+		WHERE id = ?
+		RETURNING calendar_token_hash`
+
+	var tokenHash string
+	if err := r.db.QueryRowContext(ctx, query, candidateHash, sqliteID(groupID)).Scan(&tokenHash); err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("%w: %s", ErrGroupNotFound, groupID)
+		}
+		return "", fmt.Errorf("failed to set calendar token: %w", err)
+	}
+
+	return tokenHash, nil
+}
+
+// RevokeCalendarToken disables a group's calendar feed
+func (r *SQLiteGroupRepository) RevokeCalendarToken(ctx context.Context, groupID string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE groups SET calendar_token_hash = NULL WHERE id = ?`, sqliteID(groupID))
+	if err != nil {
+		return fmt.Errorf("failed to revoke calendar token: %w", err)
+	}
+
+	return rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrGroupNotFound, groupID))
+}
+
+// GetReminderDays returns a group's reminder schedule
+func (r *SQLiteGroupRepository) GetReminderDays(ctx context.Context, groupID string) ([]int, error) {
+	query := `
+		SELECT rem.days_before
+		FROM groups g
+		LEFT JOIN group_reminders rem ON rem.group_id = g.id
+		WHERE g.id = ?
+		ORDER BY rem.days_before DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, sqliteID(groupID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reminder days: %w", err)
+	}
+	defer rows.Close()
+
+	found := false
+	days := []int{}
+	for rows.Next() {
+		found = true
+		var day sql.NullInt64
+		if err := rows.Scan(&day); err != nil {
+			return nil, fmt.Errorf("failed to scan reminder day: %w", err)
+		}
+		if day.Valid {
+			days = append(days, int(day.Int64))
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reminder days: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrGroupNotFound, groupID)
+	}
+
+	return days, nil
+}
+
+// SetReminderDays replaces a group's reminder schedule. Run it in a
+// transaction so the group never loses its old schedule without gaining
+// the new one.
+func (r *SQLiteGroupRepository) SetReminderDays(ctx context.Context, groupID string, days []int) error {
+	days = models.NormalizeReminderDays(days)
+
+	var id string
+	err := r.db.QueryRowContext(ctx, `SELECT id FROM groups WHERE id = ?`, sqliteID(groupID)).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: %s", ErrGroupNotFound, groupID)
+		}
+		return fmt.Errorf("failed to set reminder days: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM group_reminders WHERE group_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to set reminder days: %w", err)
+	}
+
+	for _, day := range days {
+		_, err := r.db.ExecContext(ctx, `INSERT INTO group_reminders (group_id, days_before) VALUES (?, ?)`, id, day)
+		if err != nil {
+			return fmt.Errorf("failed to set reminder days: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetDueReminders retrieves the reminders falling due after after and no
+// later than until, earliest first. SQLite cannot do arithmetic on the
+// stored timestamps, so the deadlines that could have a reminder due are
+// read and the reminders checked here.
+func (r *SQLiteGroupRepository) GetDueReminders(ctx context.Context, after, until time.Time) ([]models.DueReminder, error) {
+	query := `
+		SELECT g.id, g.name, g.deadline, g.timezone, g.lock_after_deadline, g.created_at, g.created_by,
+			rem.days_before
+		FROM groups g
+		JOIN group_reminders rem ON rem.group_id = g.id
+		WHERE g.deadline > ? AND g.deadline <= ?`
+
+	rows, err := r.db.QueryContext(ctx, query, sqliteTime(after), sqliteTime(until.Add(maxReminderLead)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var reminders []models.DueReminder
+	for rows.Next() {
+		var reminder models.DueReminder
+		group := &reminder.Group
+		err := rows.Scan(&group.ID, &group.Name, sqliteNullTimeScanner{&group.Deadline}, &group.Timezone,
+			&group.LockAfterDeadline, sqliteTimeScanner{&group.CreatedAt}, &group.CreatedBy, &reminder.DaysBefore)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		group.Localize()
+		reminder.RemindAt = models.RemindAt(*reminder.Group.Deadline, reminder.DaysBefore)
+		if reminder.RemindAt.After(after) && !reminder.RemindAt.After(until) {
+			reminders = append(reminders, reminder)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reminders: %w", err)
+	}
+
+	sortDueReminders(reminders)
+	return reminders, nil
+}
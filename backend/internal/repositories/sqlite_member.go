@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// SQLiteMemberRepository implements MemberRepository for SQLite
+type SQLiteMemberRepository struct {
+	db dbExecutor
+}
+
+// Create creates a new member. It returns ErrAlreadyMember if the user
+// already belongs to the group and ErrGroupNotFound if the group is gone.
+func (r *SQLiteMemberRepository) Create(ctx context.Context, member *models.Member) error {
+	if err := member.IsValid(); err != nil {
+		return fmt.Errorf("invalid member data: %w", err)
+	}
+
+	member.Sanitize()
+
+	id, err := sqliteUUID(member.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create member: %w", err)
+	}
+	groupID, err := sqliteUUID(member.GroupID)
+	if err != nil {
+		return fmt.Errorf("failed to create member: %w", err)
+	}
+	userID, err := sqliteNullUUID(member.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to create member: %w", err)
+	}
+
+	query := `
+		INSERT INTO members (id, group_id, user_id, name, joined_at, is_creator)
+		VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		id, groupID, userID, member.Name, sqliteTime(member.JoinedAt), member.IsCreator)
+	if err != nil {
+		return fmt.Errorf("failed to create member: %w", mapSQLiteError(err, ErrGroupNotFound))
+	}
+
+	return nil
+}
+
+// GetByID retrieves a member by their ID
+func (r *SQLiteMemberRepository) GetByID(ctx context.Context, id string) (*models.Member, error) {
+	query := `SELECT ` + sqliteMemberColumns + ` FROM members WHERE id = ?`
+
+	var member models.Member
+	err := scanSQLiteMember(r.db.QueryRowContext(ctx, query, sqliteID(id)), &member)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %s", ErrMemberNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get member: %w", err)
+	}
+
+	return &member, nil
+}
+
+// GetByGroupID retrieves all members of a specific group
+func (r *SQLiteMemberRepository) GetByGroupID(ctx context.Context, groupID string) ([]models.Member, error) {
+	members, err := querySQLiteMembers(ctx, r.db, `
+		SELECT `+sqliteMemberColumns+`
+		FROM members
+		WHERE group_id = ?
+		ORDER BY joined_at ASC`, sqliteID(groupID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get members by group ID: %w", err)
+	}
+
+	return members, nil
+}
+
+// GetByUserID retrieves all memberships for a specific user
+func (r *SQLiteMemberRepository) GetByUserID(ctx context.Context, userID string) ([]models.Member, error) {
+	members, err := querySQLiteMembers(ctx, r.db, `
+		SELECT `+sqliteMemberColumns+`
+		FROM members
+		WHERE user_id = ?
+		ORDER BY joined_at DESC`, sqliteID(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get members by user ID: %w", err)
+	}
+
+	return members, nil
+}
+
+// Update updates an existing member
+func (r *SQLiteMemberRepository) Update(ctx context.Context, member *models.Member) error {
+	if err := member.IsValid(); err != nil {
+		return fmt.Errorf("invalid member data: %w", err)
+	}
+
+	member.Sanitize()
+
+	userID, err := sqliteNullUUID(member.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to update member: %w", err)
+	}
+
+	query := `UPDATE members SET name = ?, user_id = ? WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, member.Name, userID, sqliteID(member.ID))
+	if err != nil {
+		return fmt.Errorf("failed to update member: %w", mapSQLiteError(err, nil))
+	}
+
+	return rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrMemberNotFound, member.ID))
+}
+
+// Delete deletes a member by ID. Items the member added are deleted with
+// them; items they completed stay completed without a completer.
+func (r *SQLiteMemberRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM members WHERE id = ?`, sqliteID(id))
+	if err != nil {
+		return fmt.Errorf("failed to delete member: %w", err)
+	}
+
+	return rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrMemberNotFound, id))
+}
+
+// ExistsByGroupAndUser checks if a user is already a member of a group
+func (r *SQLiteMemberRepository) ExistsByGroupAndUser(ctx context.Context, groupID, userID string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM members WHERE group_id = ? AND user_id = ?)`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, sqliteID(groupID), sqliteID(userID)).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check member existence: %w", err)
+	}
+
+	return exists, nil
+}
+
+// GetCreatorByGroupID retrieves the creator member of a group
+func (r *SQLiteMemberRepository) GetCreatorByGroupID(ctx context.Context, groupID string) (*models.Member, error) {
+	query := `SELECT ` + sqliteMemberColumns + ` FROM members WHERE group_id = ? AND is_creator = 1`
+
+	var member models.Member
+	err := scanSQLiteMember(r.db.QueryRowContext(ctx, query, sqliteID(groupID)), &member)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("group creator not found for group: %s", groupID)
+		}
+		return nil, fmt.Errorf("failed to get group creator: %w", err)
+	}
+
+	return &member, nil
+}
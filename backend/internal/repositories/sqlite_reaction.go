@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// SQLiteReactionRepository implements ReactionRepository for SQLite
+type SQLiteReactionRepository struct {
+	db dbExecutor
+}
+
+// SetVote adds or removes a member's vote for an item
+func (r *SQLiteReactionRepository) SetVote(ctx context.Context, itemID, memberID string, voted bool) error {
+	if !voted {
+		_, err := r.db.ExecContext(ctx, `DELETE FROM item_votes WHERE item_id = ? AND member_id = ?`,
+			sqliteID(itemID), sqliteID(memberID))
+		if err != nil {
+			return fmt.Errorf("failed to remove vote: %w", err)
+		}
+		return nil
+	}
+
+	item, member, err := r.canonicalIDs(ctx, itemID, memberID)
+	if err != nil {
+		return fmt.Errorf("failed to add vote: %w", err)
+	}
+
+	query := `
+		INSERT INTO item_votes (item_id, member_id, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (item_id, member_id) DO NOTHING`
+
+	_, err = r.db.ExecContext(ctx, query, item, member, sqliteTime(time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to add vote: %w", mapSQLiteError(err, ErrMemberNotFound))
+	}
+
+	return nil
+}
+
+// AddReaction adds a member's emoji reaction to an item
+func (r *SQLiteReactionRepository) AddReaction(ctx context.Context, reaction *models.Reaction) error {
+	if err := reaction.IsValid(); err != nil {
+		return fmt.Errorf("invalid reaction data: %w", err)
+	}
+
+	reaction.Sanitize()
+
+	item, member, err := r.canonicalIDs(ctx, reaction.ItemID, reaction.MemberID)
+	if err != nil {
+		return fmt.Errorf("failed to add reaction: %w", err)
+	}
+
+	query := `
+		INSERT INTO item_reactions (item_id, member_id, emoji, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (item_id, member_id, emoji) DO NOTHING`
+
+	_, err = r.db.ExecContext(ctx, query, item, member, reaction.Emoji, sqliteTime(reaction.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to add reaction: %w", mapSQLiteError(err, ErrMemberNotFound))
+	}
+
+	return nil
+}
+
+// RemoveReaction removes a member's emoji reaction from an item, if any
+func (r *SQLiteReactionRepository) RemoveReaction(ctx context.Context, itemID, memberID, emoji string) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM item_reactions
+		WHERE item_id = ? AND member_id = ? AND emoji = ?`, sqliteID(itemID), sqliteID(memberID), emoji)
+	if err != nil {
+		return fmt.Errorf("failed to remove reaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByItemID aggregates the votes and reactions of an item
+func (r *SQLiteReactionRepository) GetByItemID(ctx context.Context, itemID string) (*models.ItemReactions, error) {
+	byItem, err := querySQLiteReactions(ctx, r.db, `item_id = ?`, sqliteID(itemID))
+	if err != nil {
+		return nil, err
+	}
+
+	aggregate, ok := byItem[sqliteID(itemID)]
+	if !ok {
+		aggregate = emptyItemReactions(sqliteID(itemID))
+	}
+	return &aggregate, nil
+}
+
+// GetByGroupID aggregates the votes and reactions of each item of a group
+// that has any
+func (r *SQLiteReactionRepository) GetByGroupID(ctx context.Context, groupID string) (map[string]models.ItemReactions, error) {
+	return querySQLiteReactions(ctx, r.db, sqliteGroupItemsCondition, sqliteID(groupID))
+}
+
+// canonicalIDs returns the canonical item and member IDs of a vote or
+// reaction. The item is checked here because a foreign key error cannot
+// tell which of the two is missing.
+func (r *SQLiteReactionRepository) canonicalIDs(ctx context.Context, itemID, memberID string) (item, member string, err error) {
+	if item, err = sqliteUUID(itemID); err != nil {
+		return "", "", err
+	}
+	if member, err = sqliteUUID(memberID); err != nil {
+		return "", "", err
+	}
+
+	var exists bool
+	err = r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM bucket_items WHERE id = ?)`, item).Scan(&exists)
+	if err != nil {
+		return "", "", err
+	}
+	if !exists {
+		return "", "", fmt.Errorf("%w: %s", ErrItemNotFound, itemID)
+	}
+
+	return item, member, nil
+}
+
+// sqliteGroupItemsCondition selects the votes or reactions of the items of
+// a group
+const sqliteGroupItemsCondition = `item_id IN (SELECT id FROM bucket_items WHERE group_id = ?)`
+
+// querySQLiteReactions aggregates the votes and reactions matching a
+// condition on their item_id with one argument
+func querySQLiteReactions(ctx context.Context, db dbExecutor, condition string, arg string) (map[string]models.ItemReactions, error) {
+	voteRows, err := db.QueryContext(ctx, `
+		SELECT item_id, member_id, created_at
+		FROM item_votes
+		WHERE `+condition+`
+		ORDER BY created_at ASC, member_id ASC`, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get votes: %w", err)
+	}
+	defer voteRows.Close()
+
+	var votes []models.Vote
+	for voteRows.Next() {
+		var vote models.Vote
+		if err := voteRows.Scan(&vote.ItemID, &vote.MemberID, sqliteTimeScanner{&vote.CreatedAt}); err != nil {
+			return nil, fmt.Errorf("failed to scan vote: %w", err)
+		}
+		votes = append(votes, vote)
+	}
+
+	if err = voteRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating votes: %w", err)
+	}
+
+	reactionRows, err := db.QueryContext(ctx, `
+		SELECT item_id, member_id, emoji, created_at
+		FROM item_reactions
+		WHERE `+condition+`
+		ORDER BY created_at ASC, emoji ASC, member_id ASC`, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reactions: %w", err)
+	}
+	defer reactionRows.Close()
+
+	var reactions []models.Reaction
+	for reactionRows.Next() {
+		var reaction models.Reaction
+		err := reactionRows.Scan(&reaction.ItemID, &reaction.MemberID, &reaction.Emoji,
+			sqliteTimeScanner{&reaction.CreatedAt})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reaction: %w", err)
+		}
+		reactions = append(reactions, reaction)
+	}
+
+	if err = reactionRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reactions: %w", err)
+	}
+
+	return aggregateReactions(votes, reactions), nil
+}
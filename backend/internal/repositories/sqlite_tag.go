@@ -0,0 +1,221 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// SQLiteTagRepository implements TagRepository for SQLite
+type SQLiteTagRepository struct {
+	db dbExecutor
+}
+
+// Create creates a new tag
+func (r *SQLiteTagRepository) Create(ctx context.Context, tag *models.Tag) error {
+	if err := tag.IsValid(); err != nil {
+		return fmt.Errorf("invalid tag data: %w", err)
+	}
+
+	tag.Sanitize()
+
+	id, err := sqliteUUID(tag.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+	groupID, err := sqliteUUID(tag.GroupID)
+	if err != nil {
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	query := `
+		INSERT INTO tags (id, group_id, name, color, created_at)
+		VALUES (?, ?, ?, ?, ?)`
+
+	_, err = r.db.ExecContext(ctx, query, id, groupID, tag.Name, tag.Color, sqliteTime(tag.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to create tag: %w", mapSQLiteError(err, ErrGroupNotFound))
+	}
+
+	return nil
+}
+
+// GetByID retrieves a tag by its ID
+func (r *SQLiteTagRepository) GetByID(ctx context.Context, id string) (*models.Tag, error) {
+	query := `SELECT ` + sqliteTagColumns + ` FROM tags WHERE id = ?`
+
+	var tag models.Tag
+	err := scanSQLiteTag(r.db.QueryRowContext(ctx, query, sqliteID(id)), &tag)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %s", ErrTagNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	return &tag, nil
+}
+
+// GetByGroupID retrieves all tags of a group, ordered by name
+func (r *SQLiteTagRepository) GetByGroupID(ctx context.Context, groupID string) ([]models.Tag, error) {
+	tags, err := querySQLiteTags(ctx, r.db, sqliteID(groupID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags by group ID: %w", err)
+	}
+
+	return tags, nil
+}
+
+// Update renames or recolors an existing tag
+func (r *SQLiteTagRepository) Update(ctx context.Context, tag *models.Tag) error {
+	if err := tag.IsValid(); err != nil {
+		return fmt.Errorf("invalid tag data: %w", err)
+	}
+
+	tag.Sanitize()
+
+	result, err := r.db.ExecContext(ctx, `UPDATE tags SET name = ?, color = ? WHERE id = ?`,
+		tag.Name, tag.Color, sqliteID(tag.ID))
+	if err != nil {
+		return fmt.Errorf("failed to update tag: %w", mapSQLiteError(err, nil))
+	}
+
+	return rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrTagNotFound, tag.ID))
+}
+
+// Delete deletes a tag by ID. The foreign key removes it from every item.
+func (r *SQLiteTagRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tags WHERE id = ?`, sqliteID(id))
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	return rowsAffectedOrNotFound(result, fmt.Errorf("%w: %s", ErrTagNotFound, id))
+}
+
+// SetItemTags replaces the tags of an item. Run it in a transaction so the
+// item never loses its old tags without gaining the new ones.
+func (r *SQLiteTagRepository) SetItemTags(ctx context.Context, itemID string, tagIDs []string) error {
+	canonical := make([]string, len(tagIDs))
+	for i, tagID := range tagIDs {
+		id, err := sqliteUUID(tagID)
+		if err != nil {
+			return fmt.Errorf("failed to set item tags: %w", err)
+		}
+		canonical[i] = id
+	}
+	tagIDs = uniqueTagIDs(canonical)
+
+	var groupID string
+	err := r.db.QueryRowContext(ctx, `SELECT group_id FROM bucket_items WHERE id = ?`, sqliteID(itemID)).Scan(&groupID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: %s", ErrItemNotFound, itemID)
+		}
+		return fmt.Errorf("failed to set item tags: %w", err)
+	}
+
+	ids, err := json.Marshal(tagIDs)
+	if err != nil {
+		return fmt.Errorf("failed to set item tags: %w", err)
+	}
+
+	var found int
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM tags WHERE group_id = ? AND id IN (SELECT value FROM json_each(?))`,
+		groupID, string(ids)).Scan(&found)
+	if err != nil {
+		return fmt.Errorf("failed to set item tags: %w", err)
+	}
+	if found != len(tagIDs) {
+		return fmt.Errorf("failed to set item tags: %w", ErrTagNotFound)
+	}
+
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM item_tags WHERE item_id = ?`, sqliteID(itemID)); err != nil {
+		return fmt.Errorf("failed to set item tags: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO item_tags (item_id, tag_id)
+		SELECT ?, value FROM json_each(?)`, sqliteID(itemID), string(ids))
+	if err != nil {
+		return fmt.Errorf("failed to set item tags: %w", mapSQLiteError(err, ErrTagNotFound))
+	}
+
+	return nil
+}
+
+const sqliteTagColumns = `id, group_id, name, color, created_at`
+
+func scanSQLiteTag(row rowScanner, tag *models.Tag) error {
+	return row.Scan(&tag.ID, &tag.GroupID, &tag.Name, &tag.Color, sqliteTimeScanner{&tag.CreatedAt})
+}
+
+// querySQLiteTags retrieves the tags of a group, ordered by name
+func querySQLiteTags(ctx context.Context, db dbExecutor, groupID string) ([]models.Tag, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+sqliteTagColumns+`
+		FROM tags
+		WHERE group_id = ?
+		ORDER BY lower(name), id`, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []models.Tag
+	for rows.Next() {
+		var tag models.Tag
+		if err := scanSQLiteTag(rows, &tag); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// loadSQLiteItemTags fills in the tags of items
+func loadSQLiteItemTags(ctx context.Context, db dbExecutor, items []models.BucketListItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	ids, err := json.Marshal(itemIDs(items))
+	if err != nil {
+		return fmt.Errorf("failed to get item tags: %w", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT item_id, tag_id
+		FROM item_tags
+		WHERE item_id IN (SELECT value FROM json_each(?))
+		ORDER BY item_id, tag_id`, string(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get item tags: %w", err)
+	}
+	defer rows.Close()
+
+	tagIDs := make(map[string][]string)
+	for rows.Next() {
+		var itemID, tagID string
+		if err := rows.Scan(&itemID, &tagID); err != nil {
+			return fmt.Errorf("failed to scan item tag: %w", err)
+		}
+		tagIDs[itemID] = append(tagIDs[itemID], tagID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating item tags: %w", err)
+	}
+
+	setItemTagIDs(items, tagIDs)
+	return nil
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"collaborative-bucket-list/migrations"
+	"collaborative-bucket-list/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupSQLiteTestDB opens a migrated SQLite database in a temporary file
+func setupSQLiteTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := database.NewSQLiteMigrator(db, migrations.SQLiteFS)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Up(context.Background()))
+
+	return db
+}
+
+func TestSQLiteRepositories_StorageFormat(t *testing.T) {
+	ctx := context.Background()
+	db := setupSQLiteTestDB(t)
+	repos := NewSQLiteRepositoryManager(db)
+
+	t.Run("IDs are stored lowercase and matched regardless of case", func(t *testing.T) {
+		group := createTestGroup()
+		group.ID = strings.ToUpper(group.ID)
+		require.NoError(t, repos.Groups().Create(ctx, group))
+
+		var stored string
+		require.NoError(t, db.QueryRow(`SELECT id FROM groups`).Scan(&stored))
+		assert.Equal(t, strings.ToLower(group.ID), stored)
+
+		found, err := repos.Groups().GetByID(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Equal(t, stored, found.ID)
+	})
+
+	t.Run("malformed IDs are rejected", func(t *testing.T) {
+		group := createTestGroup()
+		group.ID = "not-a-uuid"
+		assert.Error(t, repos.Groups().Create(ctx, group))
+
+		_, err := repos.Groups().GetByID(ctx, "not-a-uuid")
+		assert.True(t, errors.Is(err, ErrGroupNotFound))
+	})
+
+	t.Run("timestamps are stored as UTC with microsecond precision", func(t *testing.T) {
+		zone := time.FixedZone("UTC+2", 2*60*60)
+		createdAt := time.Date(2026, 5, 1, 12, 30, 45, 123456789, zone)
+
+		group := createTestGroup()
+		group.CreatedAt = createdAt
+		require.NoError(t, repos.Groups().Create(ctx, group))
+
+		var stored string
+		require.NoError(t, db.QueryRow(`SELECT created_at FROM groups WHERE id = ?`, group.ID).Scan(&stored))
+		assert.Equal(t, "2026-05-01T10:30:45.123457Z", stored)
+
+		found, err := repos.Groups().GetByID(ctx, group.ID)
+		require.NoError(t, err)
+		assert.True(t, found.CreatedAt.Equal(createdAt.Round(time.Microsecond)))
+		assert.Nil(t, found.Deadline)
+	})
+}
+
+func TestSQLiteMigrations_UpAndDown(t *testing.T) {
+	ctx := context.Background()
+	db := setupSQLiteTestDB(t)
+
+	migrator, err := database.NewSQLiteMigrator(db, migrations.SQLiteFS)
+	require.NoError(t, err)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, statuses)
+	for _, status := range statuses {
+		assert.True(t, status.Applied, status.Filename)
+		assert.False(t, status.Modified, status.Filename)
+	}
+
+	require.NoError(t, migrator.Down(ctx, len(statuses)))
+
+	var tables int
+	require.NoError(t, db.QueryRow(
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name IN ('groups', 'members', 'bucket_items')`,
+	).Scan(&tables))
+	assert.Zero(t, tables)
+
+	require.NoError(t, migrator.Up(ctx))
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"sort"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// itemIDs returns the IDs of items, in order
+func itemIDs(items []models.BucketListItem) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+// setItemTagIDs fills in the tags of items from a map of item ID to tag IDs
+// that lists each item's tags in ID order
+func setItemTagIDs(items []models.BucketListItem, tagIDs map[string][]string) {
+	for i := range items {
+		items[i].TagIDs = tagIDs[items[i].ID]
+	}
+}
+
+// uniqueTagIDs returns tag IDs sorted and without duplicates
+func uniqueTagIDs(tagIDs []string) []string {
+	unique := make([]string, 0, len(tagIDs))
+	seen := make(map[string]bool, len(tagIDs))
+	for _, id := range tagIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+// setTagProgress calculates the progress percentage of tag stats from their
+// counts
+func setTagProgress(stats []models.TagStats) {
+	for i := range stats {
+		if stats[i].ItemCount > 0 {
+			stats[i].ProgressPercent = float64(stats[i].CompletedCount) / float64(stats[i].ItemCount) * 100
+		}
+	}
+}
+
+// loadSearchResultLinks fills in the tags and assignees of search results
+// with a function that fills them in for items
+func loadSearchResultLinks(results []models.ItemSearchResult, load func(items []models.BucketListItem) error) error {
+	items := make([]models.BucketListItem, len(results))
+	for i := range results {
+		items[i] = results[i].BucketListItem
+	}
+	if err := load(items); err != nil {
+		return err
+	}
+	for i := range results {
+		results[i].TagIDs = items[i].TagIDs
+		results[i].AssigneeIDs = items[i].AssigneeIDs
+	}
+	return nil
+}
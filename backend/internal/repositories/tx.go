@@ -0,0 +1,184 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// IsolationLevel is the isolation level of a transaction
+type IsolationLevel int
+
+// Isolation levels supported by WithTxOptions
+const (
+	// IsolationDefault uses the database's default level (read committed
+	// for PostgreSQL)
+	IsolationDefault IsolationLevel = iota
+	IsolationReadCommitted
+	IsolationRepeatableRead
+	IsolationSerializable
+)
+
+// String returns the SQL name of the isolation level
+func (l IsolationLevel) String() string {
+	switch l {
+	case IsolationReadCommitted:
+		return "read committed"
+	case IsolationRepeatableRead:
+		return "repeatable read"
+	case IsolationSerializable:
+		return "serializable"
+	default:
+		return "default"
+	}
+}
+
+// DefaultMaxRetries is how many times a transaction is retried after a
+// serialization failure or deadlock when TxOptions.MaxRetries is zero
+const DefaultMaxRetries = 3
+
+// TxOptions configures a transaction started with WithTxOptions
+type TxOptions struct {
+	Isolation IsolationLevel
+	ReadOnly  bool
+	// MaxRetries limits retries after serialization failures and deadlocks.
+	// Zero means DefaultMaxRetries; a negative value disables retries.
+	MaxRetries int
+}
+
+// maxRetries returns the effective retry limit
+func (o TxOptions) maxRetries() int {
+	if o.MaxRetries == 0 {
+		return DefaultMaxRetries
+	}
+	if o.MaxRetries < 0 {
+		return 0
+	}
+	return o.MaxRetries
+}
+
+// ErrIsolationChange is returned when a nested transaction asks for an
+// isolation level different from the enclosing transaction's
+var ErrIsolationChange = errors.New("cannot change isolation level inside a transaction")
+
+// Retry backoff bounds; the delay doubles per attempt, with jitter
+const (
+	txRetryBaseDelay = 10 * time.Millisecond
+	txRetryMaxDelay  = 500 * time.Millisecond
+)
+
+// sqlTxState describes the transaction a SQL repository manager is scoped
+// to. It is nil for managers working directly on the database.
+type sqlTxState struct {
+	tx        *sql.Tx
+	isolation IsolationLevel
+	// depth is the number of savepoints enclosing the manager
+	depth int
+}
+
+// runSQLTx runs fn in a new transaction, committing if it succeeds and
+// rolling back if it fails or panics. The whole transaction, fn included,
+// is retried when isRetryable reports a serialization failure or deadlock.
+func runSQLTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, maxRetries int,
+	isRetryable func(error) bool, fn func(tx *sql.Tx) error) error {
+	for attempt := 0; ; attempt++ {
+		err := runSQLTxOnce(ctx, db, opts, fn)
+		if err == nil || attempt >= maxRetries || !isRetryable(err) {
+			return err
+		}
+
+		if err := sleepBeforeRetry(ctx, attempt); err != nil {
+			return err
+		}
+	}
+}
+
+func runSQLTxOnce(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-throw panic after rollback
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("transaction failed: %w, rollback failed: %v", err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// sleepBeforeRetry waits an exponentially growing, jittered delay
+func sleepBeforeRetry(ctx context.Context, attempt int) error {
+	delay := txRetryBaseDelay << attempt
+	if delay > txRetryMaxDelay || delay <= 0 {
+		delay = txRetryMaxDelay
+	}
+	delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// runSavepoint runs fn inside a savepoint of an open transaction, so a
+// failing nested call undoes only its own work. Serialization failures are
+// not retried here: they abort the whole transaction, which the outermost
+// call retries.
+func runSavepoint(ctx context.Context, state *sqlTxState, isolation IsolationLevel, fn func(nested *sqlTxState) error) error {
+	if isolation != IsolationDefault && isolation != state.isolation {
+		return fmt.Errorf("%w: enclosing transaction is %s, requested %s", ErrIsolationChange, state.isolation, isolation)
+	}
+
+	nested := &sqlTxState{tx: state.tx, isolation: state.isolation, depth: state.depth + 1}
+	name := fmt.Sprintf("sp_%d", nested.depth)
+
+	if _, err := state.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			state.tx.ExecContext(context.Background(), "ROLLBACK TO SAVEPOINT "+name)
+			panic(p) // Re-throw panic after rollback
+		}
+	}()
+
+	if err := fn(nested); err != nil {
+		// Rolling back to a savepoint keeps it open, so release it as well
+		if _, rbErr := state.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			return fmt.Errorf("transaction failed: %w, rollback to savepoint failed: %v", err, rbErr)
+		}
+		if _, relErr := state.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); relErr != nil {
+			return fmt.Errorf("transaction failed: %w, release savepoint failed: %v", err, relErr)
+		}
+		return err
+	}
+
+	if _, err := state.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSQLTx_Retries(t *testing.T) {
+	ctx := context.Background()
+	db := setupSQLiteTestDB(t)
+	conflict := errors.New("conflict")
+	isConflict := func(err error) bool { return errors.Is(err, conflict) }
+
+	t.Run("retries retryable failures and commits", func(t *testing.T) {
+		repos := NewSQLiteRepositoryManager(db)
+		group := createTestGroup()
+
+		attempts := 0
+		err := runSQLTx(ctx, db, nil, 3, isConflict, func(tx *sql.Tx) error {
+			attempts++
+			if err := newSQLiteTxRepositoryManager(&sqlTxState{tx: tx}).Groups().Create(ctx, group); err != nil {
+				return err
+			}
+			if attempts < 3 {
+				return conflict
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+
+		// Earlier attempts were rolled back, so the insert did not conflict
+		_, err = repos.Groups().GetByID(ctx, group.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		attempts := 0
+		err := runSQLTx(ctx, db, nil, 2, isConflict, func(tx *sql.Tx) error {
+			attempts++
+			return conflict
+		})
+		assert.ErrorIs(t, err, conflict)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		failure := errors.New("failure")
+		attempts := 0
+		err := runSQLTx(ctx, db, nil, 3, isConflict, func(tx *sql.Tx) error {
+			attempts++
+			return failure
+		})
+		assert.ErrorIs(t, err, failure)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		attempts := 0
+		err := runSQLTx(cancelled, db, nil, 3, isConflict, func(tx *sql.Tx) error {
+			attempts++
+			cancel()
+			return conflict
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, attempts)
+	})
+}
+
+func TestIsPostgresRetryable(t *testing.T) {
+	assert.True(t, isPostgresRetryable(&pq.Error{Code: pqSerializationFailure}))
+	assert.True(t, isPostgresRetryable(&pq.Error{Code: pqDeadlockDetected}))
+	assert.False(t, isPostgresRetryable(&pq.Error{Code: pqUniqueViolation}))
+	assert.False(t, isPostgresRetryable(errors.New("serialization failure")))
+}
+
+func TestTxOptions_MaxRetries(t *testing.T) {
+	assert.Equal(t, DefaultMaxRetries, TxOptions{}.maxRetries())
+	assert.Equal(t, 5, TxOptions{MaxRetries: 5}.maxRetries())
+	assert.Equal(t, 0, TxOptions{MaxRetries: -1}.maxRetries())
+}
@@ -0,0 +1,105 @@
+// Package services holds application services that work across the
+// repositories and other backends, such as background maintenance tasks.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+	"collaborative-bucket-list/pkg/storage"
+)
+
+// blobCleanupBatchSize is how many queued blob deletions a sweep handles at
+// a time
+const blobCleanupBatchSize = 100
+
+// BlobCleaner deletes the blobs of deleted attachments and their thumbnails
+// from the blob store. The repositories queue a blob whenever its
+// attachment row goes away, including when its item, group or uploader is
+// deleted, so the database and the store agree even if the process dies in
+// between. Deleting a blob
+// twice is harmless, so several servers can sweep the same queue.
+type BlobCleaner struct {
+	repos repositories.RepositoryManager
+	blobs storage.BlobStore
+}
+
+// NewBlobCleaner creates a blob cleaner
+func NewBlobCleaner(repos repositories.RepositoryManager, blobs storage.BlobStore) *BlobCleaner {
+	return &BlobCleaner{
+		repos: repos,
+		blobs: blobs,
+	}
+}
+
+// Sweep deletes every queued blob and returns how many were deleted. Blobs
+// that fail to delete stay queued for the next sweep.
+func (c *BlobCleaner) Sweep(ctx context.Context) (int, error) {
+	deleted := 0
+	for {
+		keys, err := c.repos.Attachments().PendingBlobDeletions(ctx, blobCleanupBatchSize)
+		if err != nil {
+			return deleted, err
+		}
+
+		var cleared []string
+		var errs []error
+		for _, key := range keys {
+			if err := c.delete(ctx, key); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			cleared = append(cleared, key)
+		}
+
+		if err := c.repos.Attachments().ClearBlobDeletions(ctx, cleared); err != nil {
+			return deleted, err
+		}
+		deleted += len(cleared)
+
+		// Failed keys come first in the next batch, so stop rather than
+		// retry them in a loop
+		if len(errs) > 0 {
+			return deleted, fmt.Errorf("failed to delete %d blobs: %w", len(errs), errors.Join(errs...))
+		}
+		if len(keys) < blobCleanupBatchSize {
+			return deleted, nil
+		}
+	}
+}
+
+// delete deletes the blob of an attachment and any thumbnails rendered
+// from it
+func (c *BlobCleaner) delete(ctx context.Context, key string) error {
+	for _, size := range models.ThumbnailSizes {
+		if err := c.blobs.Delete(ctx, models.AttachmentThumbnailKey(key, size.Name)); err != nil {
+			return err
+		}
+	}
+	return c.blobs.Delete(ctx, key)
+}
+
+// Run sweeps once and then every interval until ctx is done
+func (c *BlobCleaner) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if deleted, err := c.Sweep(ctx); err != nil {
+			log.Printf("Blob cleanup failed after deleting %d blobs: %v", deleted, err)
+		} else if deleted > 0 {
+			log.Printf("Deleted %d blobs of deleted attachments", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
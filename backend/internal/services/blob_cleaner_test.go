@@ -0,0 +1,127 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+	"collaborative-bucket-list/pkg/storage"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyStore is a blob store whose deletes of some keys fail
+type flakyStore struct {
+	storage.BlobStore
+	failing map[string]bool
+}
+
+func (s *flakyStore) Delete(ctx context.Context, key string) error {
+	if s.failing[key] {
+		return errors.New("store unavailable")
+	}
+	return s.BlobStore.Delete(ctx, key)
+}
+
+// seedAttachments creates an item with count attachments whose blobs are in
+// blobs
+func seedAttachments(t *testing.T, repos repositories.RepositoryManager, blobs storage.BlobStore, count int) (*models.BucketListItem, []*models.Attachment) {
+	ctx := context.Background()
+	creatorID := uuid.New().String()
+	group := &models.Group{ID: uuid.New().String(), Name: "Trip", CreatedBy: creatorID, CreatedAt: time.Now()}
+	require.NoError(t, repos.Groups().Create(ctx, group))
+	member := &models.Member{ID: uuid.New().String(), GroupID: group.ID, Name: "Alice", IsCreator: true, JoinedAt: time.Now()}
+	require.NoError(t, repos.Members().Create(ctx, member))
+	item := &models.BucketListItem{ID: uuid.New().String(), GroupID: group.ID, Title: "Beach", CreatedBy: &member.ID, CreatedAt: time.Now()}
+	require.NoError(t, repos.BucketItems().Create(ctx, item))
+
+	var attachments []*models.Attachment
+	for i := 0; i < count; i++ {
+		id := uuid.New().String()
+		attachment := &models.Attachment{
+			ID:          id,
+			ItemID:      item.ID,
+			MemberID:    member.ID,
+			FileName:    fmt.Sprintf("photo-%d.png", i),
+			ContentType: "image/png",
+			Size:        4,
+			StorageKey:  models.AttachmentStorageKey(group.ID, item.ID, id),
+			CreatedAt:   time.Now(),
+		}
+		require.NoError(t, blobs.Put(ctx, attachment.StorageKey, bytes.NewReader([]byte("blob")), 4, "image/png"))
+		require.NoError(t, repos.Attachments().Create(ctx, attachment))
+		attachments = append(attachments, attachment)
+	}
+	return item, attachments
+}
+
+func TestBlobCleaner_Sweep(t *testing.T) {
+	ctx := context.Background()
+	blobs, err := storage.NewLocalStore(t.TempDir(), "http://localhost/blobs", []byte("secret"))
+	require.NoError(t, err)
+	repos := repositories.NewMemoryRepositoryManager()
+
+	item, attachments := seedAttachments(t, repos, blobs, blobCleanupBatchSize+5)
+	kept, _ := seedAttachments(t, repos, blobs, 1)
+
+	cleaner := NewBlobCleaner(repos, blobs)
+	deleted, err := cleaner.Sweep(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, deleted, "nothing is queued yet")
+
+	// Deleting the item deletes its attachments, more than one batch
+	require.NoError(t, repos.BucketItems().Delete(ctx, item.ID))
+	deleted, err = cleaner.Sweep(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, len(attachments), deleted)
+
+	for _, attachment := range attachments {
+		_, err := blobs.Get(ctx, attachment.StorageKey)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	}
+	pending, err := repos.Attachments().PendingBlobDeletions(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	remaining, err := repos.Attachments().GetByItemID(ctx, kept.ID)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	blob, err := blobs.Get(ctx, remaining[0].StorageKey)
+	require.NoError(t, err, "blobs of other items are untouched")
+	blob.Close()
+}
+
+func TestBlobCleaner_SweepKeepsFailedDeletions(t *testing.T) {
+	ctx := context.Background()
+	local, err := storage.NewLocalStore(t.TempDir(), "http://localhost/blobs", []byte("secret"))
+	require.NoError(t, err)
+	repos := repositories.NewMemoryRepositoryManager()
+
+	_, attachments := seedAttachments(t, repos, local, 3)
+	blobs := &flakyStore{BlobStore: local, failing: map[string]bool{attachments[1].StorageKey: true}}
+	for _, attachment := range attachments {
+		require.NoError(t, repos.Attachments().Delete(ctx, attachment.ID))
+	}
+
+	cleaner := NewBlobCleaner(repos, blobs)
+	deleted, err := cleaner.Sweep(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, 2, deleted)
+
+	pending, err := repos.Attachments().PendingBlobDeletions(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{attachments[1].StorageKey}, pending, "the failed blob stays queued")
+
+	// The next sweep retries it
+	blobs.failing = nil
+	deleted, err = cleaner.Sweep(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+}
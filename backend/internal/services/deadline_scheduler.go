@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+	"collaborative-bucket-list/internal/websocket"
+)
+
+// DefaultDeadlineCheckInterval is how often the deadline scheduler looks for
+// deadlines coming up or passing
+const DefaultDeadlineCheckInterval = time.Minute
+
+// DeadlineScheduler tells group rooms about their deadline: deadline-
+// approaching once it is within models.DueSoonWindow and deadline-passed
+// once it has passed. A deadline set or moved to less than the window away
+// is approaching from the first check that finds it; the scheduler tracks
+// the deadlines it announced as approaching so each is announced once while
+// the server runs. Deadlines already due soon when it starts, or passing
+// while it is down, are not announced late. Rooms live in each server's own
+// hub, so every server announces to its own clients.
+type DeadlineScheduler struct {
+	repos repositories.RepositoryManager
+	hub   websocket.HubInterface
+	// checked is the time the previous check covered up to
+	checked time.Time
+	// approaching holds the deadlines within the window at the previous
+	// check by group ID; nil until the first check
+	approaching map[string]time.Time
+}
+
+// NewDeadlineScheduler creates a deadline scheduler whose first check
+// covers the time from now
+func NewDeadlineScheduler(repos repositories.RepositoryManager, hub websocket.HubInterface) *DeadlineScheduler {
+	return &DeadlineScheduler{
+		repos:   repos,
+		hub:     hub,
+		checked: time.Now(),
+	}
+}
+
+// Check announces the deadlines that came within models.DueSoonWindow or
+// passed since the previous check and returns how many events it sent. If
+// it fails, the next check covers the same time again.
+func (s *DeadlineScheduler) Check(ctx context.Context, now time.Time) (int, error) {
+	if !now.After(s.checked) {
+		return 0, nil
+	}
+
+	if s.approaching == nil {
+		// Deadlines already due soon at the start were announced before
+		announced, err := s.repos.Groups().GetByDeadlineBetween(ctx, s.checked, s.checked.Add(models.DueSoonWindow))
+		if err != nil {
+			return 0, err
+		}
+		s.approaching = deadlinesByGroup(announced)
+	}
+
+	dueSoon, err := s.repos.Groups().GetByDeadlineBetween(ctx, now, now.Add(models.DueSoonWindow))
+	if err != nil {
+		return 0, err
+	}
+	passed, err := s.repos.Groups().GetByDeadlineBetween(ctx, s.checked, now)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range dueSoon {
+		group := &dueSoon[i]
+		if announced, ok := s.approaching[group.ID]; ok && announced.Equal(*group.Deadline) {
+			continue
+		}
+		s.announce(websocket.EventDeadlineApproaching, group, now)
+		sent++
+	}
+	for i := range passed {
+		s.announce(websocket.EventDeadlinePassed, &passed[i], now)
+	}
+	s.checked = now
+	s.approaching = deadlinesByGroup(dueSoon)
+	return sent + len(passed), nil
+}
+
+// deadlinesByGroup maps the groups' IDs to their deadlines
+func deadlinesByGroup(groups []models.Group) map[string]time.Time {
+	deadlines := make(map[string]time.Time, len(groups))
+	for _, group := range groups {
+		deadlines[group.ID] = *group.Deadline
+	}
+	return deadlines
+}
+
+// announce broadcasts a deadline event to the group's room
+func (s *DeadlineScheduler) announce(event string, group *models.Group, now time.Time) {
+	state := group.DeriveDeadlineState(now, 0, 0)
+	s.hub.BroadcastToRoom(group.ID, event, websocket.DeadlinePayload{
+		GroupID:          group.ID,
+		Deadline:         *group.Deadline,
+		DeadlineDate:     *group.DeadlineDate,
+		SecondsRemaining: *state.SecondsRemaining,
+		Locked:           state.Locked,
+	})
+}
+
+// Run checks every interval until ctx is done
+func (s *DeadlineScheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if sent, err := s.Check(ctx, now); err != nil {
+				log.Printf("Deadline check failed: %v", err)
+			} else if sent > 0 {
+				log.Printf("Announced %d group deadlines", sent)
+			}
+		}
+	}
+}
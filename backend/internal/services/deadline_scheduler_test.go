@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+	"collaborative-bucket-list/internal/websocket"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHub records the messages broadcast to rooms
+type recordingHub struct {
+	messages []recordedMessage
+}
+
+type recordedMessage struct {
+	RoomID string
+	Type   string
+	Data   interface{}
+}
+
+func (h *recordingHub) BroadcastToRoom(roomID string, messageType string, data interface{}) {
+	h.messages = append(h.messages, recordedMessage{RoomID: roomID, Type: messageType, Data: data})
+}
+
+func TestDeadlineScheduler_Check(t *testing.T) {
+	ctx := context.Background()
+	repos := repositories.NewMemoryRepositoryManager()
+	start := time.Now()
+
+	createGroup := func(deadline time.Time, lock bool) *models.Group {
+		group := &models.Group{
+			ID:                uuid.New().String(),
+			Name:              "Trip",
+			Deadline:          &deadline,
+			LockAfterDeadline: lock,
+			CreatedBy:         uuid.New().String(),
+			CreatedAt:         start,
+		}
+		require.NoError(t, repos.Groups().Create(ctx, group))
+		return group
+	}
+
+	// Due soon between the first two checks
+	approaching := createGroup(start.Add(models.DueSoonWindow+30*time.Second), false)
+	// Passes between the first two checks
+	passing := createGroup(start.Add(30*time.Second), true)
+	// Already due soon when the scheduler starts, so not announced again
+	createGroup(start.Add(time.Hour), false)
+
+	hub := &recordingHub{}
+	scheduler := NewDeadlineScheduler(repos, hub)
+	scheduler.checked = start
+
+	sent, err := scheduler.Check(ctx, start.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	require.Len(t, hub.messages, 2)
+
+	assert.Equal(t, approaching.ID, hub.messages[0].RoomID)
+	assert.Equal(t, websocket.EventDeadlineApproaching, hub.messages[0].Type)
+	payload := hub.messages[0].Data.(websocket.DeadlinePayload)
+	assert.Equal(t, approaching.ID, payload.GroupID)
+	assert.InDelta(t, (models.DueSoonWindow - 30*time.Second).Seconds(), float64(payload.SecondsRemaining), 1)
+	assert.False(t, payload.Locked)
+
+	assert.Equal(t, passing.ID, hub.messages[1].RoomID)
+	assert.Equal(t, websocket.EventDeadlinePassed, hub.messages[1].Type)
+	payload = hub.messages[1].Data.(websocket.DeadlinePayload)
+	assert.Zero(t, payload.SecondsRemaining)
+	assert.True(t, payload.Locked, "the group locks after its deadline")
+
+	// Nothing new crosses a mark in the next check
+	sent, err = scheduler.Check(ctx, start.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+	assert.Len(t, hub.messages, 2)
+
+	// Created already due soon, so approaching from the next check
+	soon := createGroup(start.Add(2*24*time.Hour), false)
+	sent, err = scheduler.Check(ctx, start.Add(3*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	require.Len(t, hub.messages, 3)
+	assert.Equal(t, soon.ID, hub.messages[2].RoomID)
+	assert.Equal(t, websocket.EventDeadlineApproaching, hub.messages[2].Type)
+
+	sent, err = scheduler.Check(ctx, start.Add(4*time.Minute))
+	require.NoError(t, err)
+	assert.Zero(t, sent, "an approaching deadline is announced once")
+
+	// Moving the deadline announces the new one
+	moved := start.Add(24 * time.Hour)
+	soon.Deadline = &moved
+	require.NoError(t, repos.Groups().Update(ctx, soon))
+	sent, err = scheduler.Check(ctx, start.Add(5*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	require.Len(t, hub.messages, 4)
+	assert.Equal(t, soon.ID, hub.messages[3].RoomID)
+	assert.Equal(t, websocket.EventDeadlineApproaching, hub.messages[3].Type)
+}
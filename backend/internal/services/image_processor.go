@@ -0,0 +1,188 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+	"collaborative-bucket-list/pkg/imaging"
+	"collaborative-bucket-list/pkg/storage"
+)
+
+// imageProcessingBatchSize is how many pending attachments a pass loads at
+// a time
+const imageProcessingBatchSize = 20
+
+// ImageProcessor processes attachments after upload. Images have their
+// metadata stripped, so photos do not reveal where they were taken, and get
+// thumbnails in each of models.ThumbnailSizes; their dimensions are recorded
+// on the attachment. Other files are marked processed as they are.
+//
+// Images that cannot be decoded are marked failed and keep no thumbnails.
+// Storage and database errors leave the attachment pending for the next
+// pass. Processing the same attachment twice writes the same blobs, so
+// several servers can share the work.
+type ImageProcessor struct {
+	repos repositories.RepositoryManager
+	blobs storage.BlobStore
+}
+
+// NewImageProcessor creates an image processor
+func NewImageProcessor(repos repositories.RepositoryManager, blobs storage.BlobStore) *ImageProcessor {
+	return &ImageProcessor{
+		repos: repos,
+		blobs: blobs,
+	}
+}
+
+// ProcessPending processes every pending attachment and returns how many
+// were processed or marked failed
+func (p *ImageProcessor) ProcessPending(ctx context.Context) (int, error) {
+	processed := 0
+	for {
+		attachments, err := p.repos.Attachments().GetPendingProcessing(ctx, imageProcessingBatchSize)
+		if err != nil {
+			return processed, err
+		}
+
+		var errs []error
+		for i := range attachments {
+			if err := p.process(ctx, &attachments[i]); err != nil {
+				errs = append(errs, fmt.Errorf("attachment %s: %w", attachments[i].ID, err))
+				continue
+			}
+			processed++
+		}
+
+		// Attachments that failed are still pending and would come back in
+		// the next batch, so stop rather than retry them in a loop
+		if len(errs) > 0 {
+			return processed, fmt.Errorf("failed to process %d attachments: %w", len(errs), errors.Join(errs...))
+		}
+		if len(attachments) < imageProcessingBatchSize {
+			return processed, nil
+		}
+	}
+}
+
+// process strips and thumbnails one attachment and records the outcome
+func (p *ImageProcessor) process(ctx context.Context, attachment *models.Attachment) error {
+	if !attachment.IsImage() {
+		attachment.ProcessingStatus = models.AttachmentProcessed
+		return p.update(ctx, attachment, nil)
+	}
+
+	data, err := p.read(ctx, attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		attachment.ProcessingStatus = models.AttachmentFailed
+		return p.update(ctx, attachment, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	img, err := imaging.Process(data, attachment.ContentType)
+	if err != nil {
+		if !errors.Is(err, imaging.ErrMalformed) && !errors.Is(err, imaging.ErrTooLarge) && !errors.Is(err, imaging.ErrUnsupported) {
+			return err
+		}
+
+		// Still remove what metadata can be found in an image too large
+		// to decode
+		attachment.ProcessingStatus = models.AttachmentFailed
+		if stripped, _, stripErr := imaging.StripMetadata(data, attachment.ContentType); stripErr == nil && len(stripped) != len(data) {
+			if err := p.put(ctx, attachment.StorageKey, stripped, attachment.ContentType); err != nil {
+				return err
+			}
+			attachment.Size = int64(len(stripped))
+		}
+		return p.update(ctx, attachment, nil)
+	}
+
+	attachment.Width = &img.Width
+	attachment.Height = &img.Height
+	attachment.ProcessingStatus = models.AttachmentProcessed
+
+	var written []string
+	for _, thumbnail := range attachment.ThumbnailVariants() {
+		encoded, err := imaging.Thumbnail(img.Decoded, thumbnail.Width, thumbnail.Height)
+		if err != nil {
+			return err
+		}
+		if err := p.put(ctx, thumbnail.StorageKey, encoded, "image/jpeg"); err != nil {
+			return err
+		}
+		written = append(written, thumbnail.StorageKey)
+	}
+
+	if err := p.put(ctx, attachment.StorageKey, img.Original, attachment.ContentType); err != nil {
+		return err
+	}
+	attachment.Size = int64(len(img.Original))
+	written = append(written, attachment.StorageKey)
+
+	return p.update(ctx, attachment, written)
+}
+
+// update records the outcome of processing an attachment. If it was
+// deleted meanwhile, the blobs written for it are deleted too, since its
+// queued blob deletion may already have run.
+func (p *ImageProcessor) update(ctx context.Context, attachment *models.Attachment, written []string) error {
+	err := p.repos.Attachments().UpdateProcessing(ctx, attachment)
+	if !errors.Is(err, repositories.ErrAttachmentNotFound) {
+		return err
+	}
+
+	for _, key := range written {
+		if err := p.blobs.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// read loads a blob, which uploads keep within models.MaxAttachmentSize
+func (p *ImageProcessor) read(ctx context.Context, key string) ([]byte, error) {
+	blob, err := p.blobs.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer blob.Close()
+
+	data, err := io.ReadAll(io.LimitReader(blob, models.MaxAttachmentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob: %w", err)
+	}
+	return data, nil
+}
+
+func (p *ImageProcessor) put(ctx context.Context, key string, data []byte, contentType string) error {
+	return p.blobs.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType)
+}
+
+// Run processes pending attachments once and then every interval until ctx
+// is done
+func (p *ImageProcessor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if processed, err := p.ProcessPending(ctx); err != nil {
+			log.Printf("Attachment processing failed after processing %d attachments: %v", processed, err)
+		} else if processed > 0 {
+			log.Printf("Processed %d attachments", processed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"io"
+	"testing"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+	"collaborative-bucket-list/pkg/storage"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// photoWithLocation returns a width by height JPEG with an EXIF segment
+// carrying a location
+func photoWithLocation(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = 0x80
+	}
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+	data := buf.Bytes()
+
+	exif := []byte("Exif\x00\x00GPS 52.3676N 4.9041E")
+	segment := []byte{0xFF, 0xE1, 0, byte(len(exif) + 2)}
+	segment = append(segment, exif...)
+	return append(append(append([]byte{}, data[:2]...), segment...), data[2:]...)
+}
+
+// uploadAttachment stores data as a new attachment of a new item
+func uploadAttachment(t *testing.T, repos repositories.RepositoryManager, blobs storage.BlobStore, data []byte, contentType string) *models.Attachment {
+	item, _ := seedAttachments(t, repos, blobs, 0)
+	id := uuid.New().String()
+	attachment := &models.Attachment{
+		ID:          id,
+		ItemID:      item.ID,
+		MemberID:    *item.CreatedBy,
+		FileName:    "upload",
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		StorageKey:  models.AttachmentStorageKey(item.GroupID, item.ID, id),
+		CreatedAt:   time.Now(),
+	}
+	require.NoError(t, blobs.Put(context.Background(), attachment.StorageKey, bytes.NewReader(data), attachment.Size, contentType))
+	require.NoError(t, repos.Attachments().Create(context.Background(), attachment))
+	return attachment
+}
+
+func readBlob(t *testing.T, blobs storage.BlobStore, key string) []byte {
+	t.Helper()
+	blob, err := blobs.Get(context.Background(), key)
+	require.NoError(t, err)
+	defer blob.Close()
+	data, err := io.ReadAll(blob)
+	require.NoError(t, err)
+	return data
+}
+
+func newTestStore(t *testing.T) *storage.LocalStore {
+	blobs, err := storage.NewLocalStore(t.TempDir(), "http://localhost/blobs", []byte("secret"))
+	require.NoError(t, err)
+	return blobs
+}
+
+func TestImageProcessor_ProcessPending(t *testing.T) {
+	ctx := context.Background()
+	blobs := newTestStore(t)
+	repos := repositories.NewMemoryRepositoryManager()
+
+	photo := uploadAttachment(t, repos, blobs, photoWithLocation(t, 600, 300), "image/jpeg")
+	pdf := uploadAttachment(t, repos, blobs, []byte("%PDF-1.4 GPS"), "application/pdf")
+	broken := uploadAttachment(t, repos, blobs, []byte("\x89PNG\r\n\x1a\nbroken"), "image/png")
+
+	processor := NewImageProcessor(repos, blobs)
+	processed, err := processor.ProcessPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, processed)
+
+	// The photo is stripped and thumbnailed
+	stored, err := repos.Attachments().GetByID(ctx, photo.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.AttachmentProcessed, stored.ProcessingStatus)
+	require.NotNil(t, stored.Width)
+	require.NotNil(t, stored.Height)
+	assert.Equal(t, 600, *stored.Width)
+	assert.Equal(t, 300, *stored.Height)
+
+	original := readBlob(t, blobs, photo.StorageKey)
+	assert.NotContains(t, string(original), "GPS")
+	assert.Equal(t, int64(len(original)), stored.Size)
+
+	thumbnails := stored.ThumbnailVariants()
+	require.Len(t, thumbnails, len(models.ThumbnailSizes))
+	expected := map[string]image.Rectangle{
+		"small":  image.Rect(0, 0, 160, 80),
+		"medium": image.Rect(0, 0, 480, 240),
+		"large":  image.Rect(0, 0, 600, 300),
+	}
+	for _, thumbnail := range thumbnails {
+		config, err := jpeg.DecodeConfig(bytes.NewReader(readBlob(t, blobs, thumbnail.StorageKey)))
+		require.NoError(t, err, thumbnail.Size)
+		assert.Equal(t, expected[thumbnail.Size], image.Rect(0, 0, config.Width, config.Height), thumbnail.Size)
+	}
+
+	// Other files are left alone
+	stored, err = repos.Attachments().GetByID(ctx, pdf.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.AttachmentProcessed, stored.ProcessingStatus)
+	assert.Nil(t, stored.Width)
+	assert.Empty(t, stored.ThumbnailVariants())
+	assert.Equal(t, "%PDF-1.4 GPS", string(readBlob(t, blobs, pdf.StorageKey)))
+
+	// Undecodable images fail without thumbnails
+	stored, err = repos.Attachments().GetByID(ctx, broken.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.AttachmentFailed, stored.ProcessingStatus)
+	assert.Empty(t, stored.ThumbnailVariants())
+
+	processed, err = processor.ProcessPending(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, processed, "nothing is pending")
+
+	// Deleting the photo deletes its thumbnails too
+	require.NoError(t, repos.Attachments().Delete(ctx, photo.ID))
+	_, err = NewBlobCleaner(repos, blobs).Sweep(ctx)
+	require.NoError(t, err)
+	for _, thumbnail := range thumbnails {
+		_, err := blobs.Get(ctx, thumbnail.StorageKey)
+		assert.ErrorIs(t, err, storage.ErrNotFound, thumbnail.Size)
+	}
+}
+
+// unreliableStore is a blob store whose reads fail until it is fixed, and
+// which runs onPut before each write
+type unreliableStore struct {
+	storage.BlobStore
+	broken bool
+	onPut  func()
+}
+
+func (s *unreliableStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if s.broken {
+		return nil, errors.New("store unavailable")
+	}
+	return s.BlobStore.Get(ctx, key)
+}
+
+func (s *unreliableStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if s.onPut != nil {
+		s.onPut()
+	}
+	return s.BlobStore.Put(ctx, key, r, size, contentType)
+}
+
+func TestImageProcessor_RetriesStoreErrors(t *testing.T) {
+	ctx := context.Background()
+	blobs := &unreliableStore{BlobStore: newTestStore(t)}
+	repos := repositories.NewMemoryRepositoryManager()
+
+	photo := uploadAttachment(t, repos, blobs, photoWithLocation(t, 40, 20), "image/jpeg")
+	missing := uploadAttachment(t, repos, blobs, photoWithLocation(t, 40, 20), "image/jpeg")
+	require.NoError(t, blobs.Delete(ctx, missing.StorageKey))
+
+	blobs.broken = true
+	processor := NewImageProcessor(repos, blobs)
+	processed, err := processor.ProcessPending(ctx)
+	assert.Error(t, err)
+	assert.Zero(t, processed)
+
+	stored, err := repos.Attachments().GetByID(ctx, photo.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.AttachmentPending, stored.ProcessingStatus, "the photo is retried")
+
+	blobs.broken = false
+	processed, err = processor.ProcessPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, processed)
+
+	stored, err = repos.Attachments().GetByID(ctx, photo.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.AttachmentProcessed, stored.ProcessingStatus)
+
+	stored, err = repos.Attachments().GetByID(ctx, missing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.AttachmentFailed, stored.ProcessingStatus, "a missing blob is not retried")
+}
+
+func TestImageProcessor_AttachmentDeletedWhileProcessing(t *testing.T) {
+	ctx := context.Background()
+	blobs := &unreliableStore{BlobStore: newTestStore(t)}
+	repos := repositories.NewMemoryRepositoryManager()
+
+	photo := uploadAttachment(t, repos, blobs, photoWithLocation(t, 40, 20), "image/jpeg")
+	blobs.onPut = func() {
+		if err := repos.Attachments().Delete(ctx, photo.ID); err == nil {
+			// The cleaner runs before the processor finishes
+			_, err := NewBlobCleaner(repos, blobs).Sweep(ctx)
+			require.NoError(t, err)
+		}
+	}
+
+	processed, err := NewImageProcessor(repos, blobs).ProcessPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+
+	// Nothing written for the deleted attachment is left behind
+	keys := []string{photo.StorageKey}
+	for _, size := range models.ThumbnailSizes {
+		keys = append(keys, models.AttachmentThumbnailKey(photo.StorageKey, size.Name))
+	}
+	for _, key := range keys {
+		_, err := blobs.Get(ctx, key)
+		assert.ErrorIs(t, err, storage.ErrNotFound, key)
+	}
+}
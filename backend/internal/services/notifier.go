@@ -0,0 +1,111 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"collaborative-bucket-list/internal/jobs"
+	"collaborative-bucket-list/internal/models"
+)
+
+// webhookTimeout bounds each delivery to the notification webhook
+const webhookTimeout = 10 * time.Second
+
+// Reminder is a reminder to a member that their group's deadline is
+// DaysBefore days away, with the items still to be completed
+type Reminder struct {
+	UserID     string                  `json:"userId"`
+	MemberName string                  `json:"memberName"`
+	Group      models.Group            `json:"group"`
+	DaysBefore int                     `json:"daysBefore"`
+	Items      []models.BucketListItem `json:"items"`
+}
+
+// Notifier delivers notifications to users. An error leaves the
+// notification to be retried; wrap it with jobs.Permanent if retrying
+// cannot help.
+type Notifier interface {
+	NotifyReminder(ctx context.Context, reminder *Reminder) error
+}
+
+// NewNotifierFromEnv creates a WebhookNotifier posting to
+// NOTIFY_WEBHOOK_URL, or a LogNotifier if it is not set
+func NewNotifierFromEnv() Notifier {
+	url := strings.TrimSpace(os.Getenv("NOTIFY_WEBHOOK_URL"))
+	if url == "" {
+		return LogNotifier{}
+	}
+	return NewWebhookNotifier(url)
+}
+
+// LogNotifier writes notifications to the log, for development and for
+// deployments without a delivery service
+type LogNotifier struct{}
+
+// NotifyReminder logs the reminder
+func (LogNotifier) NotifyReminder(ctx context.Context, reminder *Reminder) error {
+	log.Printf("Reminder for user %s: %q is due in %d days with %d items left",
+		reminder.UserID, reminder.Group.Name, reminder.DaysBefore, len(reminder.Items))
+	return nil
+}
+
+// WebhookNotifier posts notifications as JSON to a delivery service that
+// knows how to reach users, such as an email or push gateway
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a notifier posting to url
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// webhookNotification is the body posted to the webhook
+type webhookNotification struct {
+	Type     string    `json:"type"`
+	Reminder *Reminder `json:"reminder"`
+}
+
+// NotifyReminder posts the reminder to the webhook. Client errors other
+// than timeouts and rate limits are permanent.
+func (n *WebhookNotifier) NotifyReminder(ctx context.Context, reminder *Reminder) error {
+	body, err := json.Marshal(webhookNotification{Type: "deadline-reminder", Reminder: reminder})
+	if err != nil {
+		return jobs.Permanent(fmt.Errorf("failed to encode reminder: %w", err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return jobs.Permanent(fmt.Errorf("failed to create webhook request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post reminder: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("webhook responded %s", resp.Status)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return jobs.Permanent(err)
+	}
+	return err
+}
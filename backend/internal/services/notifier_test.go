@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"collaborative-bucket-list/internal/jobs"
+	"collaborative-bucket-list/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	ctx := context.Background()
+	reminder := &Reminder{
+		UserID:     "user-1",
+		MemberName: "Ada",
+		Group:      models.Group{ID: "group-1", Name: "Trip"},
+		DaysBefore: 7,
+		Items:      []models.BucketListItem{{ID: "item-1", Title: "Beach"}},
+	}
+
+	status := http.StatusNoContent
+	var received webhookNotification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	notifier := NewWebhookNotifier(server.URL)
+
+	require.NoError(t, notifier.NotifyReminder(ctx, reminder))
+	assert.Equal(t, "deadline-reminder", received.Type)
+	assert.Equal(t, reminder, received.Reminder)
+
+	status = http.StatusServiceUnavailable
+	err := notifier.NotifyReminder(ctx, reminder)
+	require.Error(t, err)
+	assert.False(t, jobs.IsPermanent(err), "server errors are retried")
+
+	status = http.StatusTooManyRequests
+	err = notifier.NotifyReminder(ctx, reminder)
+	require.Error(t, err)
+	assert.False(t, jobs.IsPermanent(err), "rate limits are retried")
+
+	status = http.StatusBadRequest
+	err = notifier.NotifyReminder(ctx, reminder)
+	require.Error(t, err)
+	assert.True(t, jobs.IsPermanent(err), "rejected reminders are not retried")
+}
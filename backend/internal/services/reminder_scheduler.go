@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"collaborative-bucket-list/internal/jobs"
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+)
+
+// DefaultReminderCheckInterval is how often the reminder scheduler looks
+// for reminders falling due
+const DefaultReminderCheckInterval = time.Minute
+
+// DefaultReminderCatchUp is how far back the reminder scheduler's first
+// check looks, so reminders falling due while no server ran are sent late
+const DefaultReminderCatchUp = 24 * time.Hour
+
+// ReminderArgs are the arguments of a job sending one member a reminder of
+// their group's deadline
+type ReminderArgs struct {
+	GroupID    string    `json:"groupId"`
+	UserID     string    `json:"userId"`
+	Deadline   time.Time `json:"deadline"`
+	DaysBefore int       `json:"daysBefore"`
+}
+
+// Kind names reminder jobs
+func (ReminderArgs) Kind() string { return "deadline-reminder" }
+
+// uniqueKey identifies the reminder, so it is enqueued once however many
+// checks and servers find it. A moved deadline gets fresh reminders.
+func (a ReminderArgs) uniqueKey() string {
+	return fmt.Sprintf("%s:%d:%d:%s", a.GroupID, a.Deadline.Unix(), a.DaysBefore, a.UserID)
+}
+
+// ReminderScheduler reminds group members of their deadline on the days
+// before it in the group's reminder schedule. Each check enqueues a job
+// per member with a linked user for the reminders that fell due since the
+// previous check; the job lists the items still to be completed when it
+// runs. Jobs are unique per reminder and member, so servers checking the
+// same reminders, or a restarted server checking them again, send each
+// one once as long as completed jobs are retained for longer than the
+// catch-up window.
+type ReminderScheduler struct {
+	repos    repositories.RepositoryManager
+	queue    *jobs.Queue
+	notifier Notifier
+	now      func() time.Time
+	// checked is the time the previous check covered up to
+	checked time.Time
+}
+
+// NewReminderScheduler creates a reminder scheduler whose first check
+// covers the catchUp before now, and registers its job handler with queue.
+// It must be created before the queue is started.
+func NewReminderScheduler(repos repositories.RepositoryManager, queue *jobs.Queue, notifier Notifier, catchUp time.Duration) *ReminderScheduler {
+	s := &ReminderScheduler{
+		repos:    repos,
+		queue:    queue,
+		notifier: notifier,
+		now:      time.Now,
+		checked:  time.Now().Add(-catchUp),
+	}
+	jobs.Handle(queue, s.send)
+	return s
+}
+
+// Check enqueues the reminders that fell due since the previous check and
+// returns how many it enqueued. If it fails, the next check covers the
+// same time again.
+func (s *ReminderScheduler) Check(ctx context.Context, now time.Time) (int, error) {
+	if !now.After(s.checked) {
+		return 0, nil
+	}
+
+	due, err := s.repos.Groups().GetDueReminders(ctx, s.checked, now)
+	if err != nil {
+		return 0, err
+	}
+
+	enqueued := 0
+	for i := range due {
+		sent, err := s.enqueue(ctx, &due[i])
+		enqueued += sent
+		if err != nil {
+			return enqueued, err
+		}
+	}
+	s.checked = now
+	return enqueued, nil
+}
+
+// enqueue schedules a reminder job for each member of the reminder's group
+// with a linked user, skipping those already scheduled
+func (s *ReminderScheduler) enqueue(ctx context.Context, reminder *models.DueReminder) (int, error) {
+	members, err := s.repos.Members().GetByGroupID(ctx, reminder.Group.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	enqueued := 0
+	for _, member := range members {
+		if member.UserID == nil {
+			continue
+		}
+
+		args := ReminderArgs{
+			GroupID:    reminder.Group.ID,
+			UserID:     *member.UserID,
+			Deadline:   *reminder.Group.Deadline,
+			DaysBefore: reminder.DaysBefore,
+		}
+		_, err := s.queue.Enqueue(ctx, args, jobs.EnqueueOptions{UniqueKey: args.uniqueKey()})
+		if errors.Is(err, jobs.ErrDuplicateJob) {
+			continue
+		}
+		if err != nil {
+			return enqueued, fmt.Errorf("failed to enqueue reminder: %w", err)
+		}
+		enqueued++
+	}
+	return enqueued, nil
+}
+
+// send delivers a reminder job. Reminders that no longer apply are
+// dropped: the group was deleted, its deadline moved or passed, the day was
+// taken off its schedule, the user left, or every item is completed.
+func (s *ReminderScheduler) send(ctx context.Context, job *jobs.Job, args ReminderArgs) error {
+	group, err := s.repos.Groups().GetByID(ctx, args.GroupID)
+	if errors.Is(err, repositories.ErrGroupNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if group.Deadline == nil || !group.Deadline.Equal(args.Deadline) || !s.now().Before(*group.Deadline) {
+		return nil
+	}
+
+	days, err := s.repos.Groups().GetReminderDays(ctx, group.ID)
+	if err != nil {
+		return err
+	}
+	if !containsDay(days, args.DaysBefore) {
+		return nil
+	}
+
+	members, err := s.repos.Members().GetByGroupID(ctx, group.ID)
+	if err != nil {
+		return err
+	}
+	var member *models.Member
+	for i := range members {
+		if members[i].UserID != nil && *members[i].UserID == args.UserID {
+			member = &members[i]
+			break
+		}
+	}
+	if member == nil {
+		return nil
+	}
+
+	items, err := s.repos.BucketItems().GetByGroupID(ctx, group.ID)
+	if err != nil {
+		return err
+	}
+	remaining := make([]models.BucketListItem, 0, len(items))
+	for _, item := range items {
+		if !item.Completed {
+			remaining = append(remaining, item)
+		}
+	}
+	if len(remaining) == 0 {
+		return nil
+	}
+
+	return s.notifier.NotifyReminder(ctx, &Reminder{
+		UserID:     args.UserID,
+		MemberName: member.Name,
+		Group:      *group,
+		DaysBefore: args.DaysBefore,
+		Items:      remaining,
+	})
+}
+
+// containsDay reports whether a reminder schedule includes day
+func containsDay(days []int, day int) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Run checks every interval until ctx is done
+func (s *ReminderScheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if enqueued, err := s.Check(ctx, now); err != nil {
+				log.Printf("Reminder check failed: %v", err)
+			} else if enqueued > 0 {
+				log.Printf("Enqueued %d deadline reminders", enqueued)
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"collaborative-bucket-list/internal/jobs"
+	"collaborative-bucket-list/internal/models"
+	"collaborative-bucket-list/internal/repositories"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingNotifier records the reminders it is asked to send
+type recordingNotifier struct {
+	reminders []*Reminder
+}
+
+func (n *recordingNotifier) NotifyReminder(ctx context.Context, reminder *Reminder) error {
+	n.reminders = append(n.reminders, reminder)
+	return nil
+}
+
+func TestReminderScheduler(t *testing.T) {
+	ctx := context.Background()
+	repos := repositories.NewMemoryRepositoryManager()
+	start := time.Now()
+
+	// The week-before reminder falls due between the first two checks
+	deadline := start.Add(7*24*time.Hour + 30*time.Second)
+	group := &models.Group{
+		ID:        uuid.New().String(),
+		Name:      "Trip",
+		Deadline:  &deadline,
+		CreatedBy: uuid.New().String(),
+		CreatedAt: start,
+	}
+	require.NoError(t, repos.Groups().Create(ctx, group))
+	require.NoError(t, repos.Groups().SetReminderDays(ctx, group.ID, models.DefaultReminderDays))
+
+	addMember := func(name string, userID *string) *models.Member {
+		member := &models.Member{ID: uuid.New().String(), GroupID: group.ID, UserID: userID, Name: name, JoinedAt: start}
+		require.NoError(t, repos.Members().Create(ctx, member))
+		return member
+	}
+	ada := addMember("Ada", &group.CreatedBy)
+	addMember("Guest", nil)
+	graceID := uuid.New().String()
+	addMember("Grace", &graceID)
+
+	addItem := func(title string, completed bool) {
+		item := &models.BucketListItem{ID: uuid.New().String(), GroupID: group.ID, Title: title, Completed: completed, CreatedBy: &ada.ID, CreatedAt: start}
+		require.NoError(t, repos.BucketItems().Create(ctx, item))
+	}
+	addItem("Beach", false)
+	addItem("Museum", true)
+
+	store := jobs.NewMemoryStore()
+	queue := jobs.NewQueue(store, jobs.DefaultConfig())
+	notifier := &recordingNotifier{}
+	scheduler := NewReminderScheduler(repos, queue, notifier, 0)
+	scheduler.checked = start
+	scheduler.now = func() time.Time { return start.Add(time.Minute) }
+
+	enqueued, err := scheduler.Check(ctx, start.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 2, enqueued, "one reminder per member with a user")
+
+	// A restarted server, or another replica, catching up on the same
+	// reminders enqueues nothing new
+	replica := NewReminderScheduler(repos, jobs.NewQueue(store, jobs.DefaultConfig()), notifier, time.Hour)
+	enqueued, err = replica.Check(ctx, start.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Zero(t, enqueued)
+
+	for {
+		ran, err := queue.RunNext(ctx)
+		require.NoError(t, err)
+		if !ran {
+			break
+		}
+	}
+	require.Len(t, notifier.reminders, 2)
+	users := []string{notifier.reminders[0].UserID, notifier.reminders[1].UserID}
+	assert.ElementsMatch(t, []string{group.CreatedBy, graceID}, users)
+	for _, reminder := range notifier.reminders {
+		assert.Equal(t, 7, reminder.DaysBefore)
+		assert.Equal(t, group.ID, reminder.Group.ID)
+		require.Len(t, reminder.Items, 1, "only incomplete items are listed")
+		assert.Equal(t, "Beach", reminder.Items[0].Title)
+	}
+
+	// Nothing else falls due in the next check
+	enqueued, err = scheduler.Check(ctx, start.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Zero(t, enqueued)
+}
+
+func TestReminderScheduler_DropsStaleReminders(t *testing.T) {
+	ctx := context.Background()
+	repos := repositories.NewMemoryRepositoryManager()
+	now := time.Now()
+
+	deadline := now.Add(24 * time.Hour).Truncate(time.Second)
+	group := &models.Group{ID: uuid.New().String(), Name: "Trip", Deadline: &deadline, CreatedBy: uuid.New().String(), CreatedAt: now}
+	require.NoError(t, repos.Groups().Create(ctx, group))
+	require.NoError(t, repos.Groups().SetReminderDays(ctx, group.ID, []int{1}))
+	member := &models.Member{ID: uuid.New().String(), GroupID: group.ID, UserID: &group.CreatedBy, Name: "Ada", JoinedAt: now}
+	require.NoError(t, repos.Members().Create(ctx, member))
+	item := &models.BucketListItem{ID: uuid.New().String(), GroupID: group.ID, Title: "Beach", CreatedBy: &member.ID, CreatedAt: now}
+	require.NoError(t, repos.BucketItems().Create(ctx, item))
+
+	notifier := &recordingNotifier{}
+	scheduler := NewReminderScheduler(repos, jobs.NewQueue(jobs.NewMemoryStore(), jobs.DefaultConfig()), notifier, 0)
+	scheduler.now = func() time.Time { return now }
+	args := ReminderArgs{GroupID: group.ID, UserID: group.CreatedBy, Deadline: deadline, DaysBefore: 1}
+
+	require.NoError(t, scheduler.send(ctx, nil, args))
+	assert.Len(t, notifier.reminders, 1)
+
+	moved := args
+	moved.Deadline = deadline.Add(time.Hour)
+	require.NoError(t, scheduler.send(ctx, nil, moved), "the deadline moved")
+
+	unscheduled := args
+	unscheduled.DaysBefore = 7
+	require.NoError(t, scheduler.send(ctx, nil, unscheduled), "the day is not on the schedule")
+
+	stranger := args
+	stranger.UserID = uuid.New().String()
+	require.NoError(t, scheduler.send(ctx, nil, stranger), "the user is not a member")
+
+	scheduler.now = func() time.Time { return deadline }
+	require.NoError(t, scheduler.send(ctx, nil, args), "the deadline passed")
+
+	require.NoError(t, repos.Groups().Delete(ctx, group.ID))
+	require.NoError(t, scheduler.send(ctx, nil, args), "the group was deleted")
+
+	assert.Len(t, notifier.reminders, 1)
+}
@@ -1,35 +1,29 @@
 package websocket
 
 import (
+	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
 )
 
-const (
-	// Time allowed to write a message to the peer
-	writeWait = 10 * time.Second
-
-	// Time allowed to read the next pong message from the peer
-	pongWait = 60 * time.Second
-
-	// Send pings to peer with this period. Must be less than pongWait
-	pingPeriod = (pongWait * 9) / 10
-
-	// Maximum message size allowed from peer
-	maxMessageSize = 512
-)
-
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		// Allow connections from any origin for now
-		// In production, you should validate the origin
-		return true
-	},
+// newUpgrader creates the HTTP upgrader for a configuration
+func newUpgrader(config *Config) *websocket.Upgrader {
+	return &websocket.Upgrader{
+		ReadBufferSize:    config.ReadBufferSize,
+		WriteBufferSize:   config.WriteBufferSize,
+		EnableCompression: config.EnableCompression,
+		Subprotocols:      supportedSubprotocols,
+		CheckOrigin: func(r *http.Request) bool {
+			// Allow connections from any origin for now
+			// In production, you should validate the origin
+			return true
+		},
+	}
 }
 
 // Client is a middleman between the websocket connection and the hub
@@ -50,25 +44,116 @@ type Client struct {
 
 	// Event handler for processing WebSocket events
 	eventHandler *EventHandler
+
+	// Protocol state agreed during the hello handshake. Clients that never
+	// send hello stay on the legacy protocol version. The read pump sets it
+	// while the hub reads it, so writes hold protocolMu.
+	protocolMu      sync.RWMutex
+	negotiated      bool
+	protocolVersion int
+	features        []string
+
+	// Close requests; the write pump flushes queued messages before closing
+	closeRequests chan closeRequest
+
+	// Encoding negotiated through the WebSocket subprotocol
+	codec Codec
+
+	// Event ID an event stream subscriber last saw; the hub replays any
+	// later broadcasts when the client registers
+	resumeFrom string
 }
 
-// Message represents a WebSocket message
+// closeRequest asks the write pump to close the connection with a close frame
+type closeRequest struct {
+	code   int
+	reason string
+}
+
+// Message represents a WebSocket message.
+// RequestID is an optional client-chosen correlation ID; commands that carry
+// one are answered with an ack or error echoing the same ID. EventID is set
+// by the hub on room broadcasts and can be used to resume an event stream.
 type Message struct {
-	Type     string      `json:"type"`
-	RoomID   string      `json:"roomId"`
-	MemberID string      `json:"memberId,omitempty"`
-	Data     interface{} `json:"data"`
+	Type      string      `json:"type"`
+	RoomID    string      `json:"roomId"`
+	MemberID  string      `json:"memberId,omitempty"`
+	RequestID string      `json:"requestId,omitempty"`
+	EventID   string      `json:"eventId,omitempty"`
+	Data      interface{} `json:"data"`
 }
 
 // NewClient creates a new WebSocket client
 func NewClient(hub *Hub, conn *websocket.Conn, roomID, memberID string, eventHandler *EventHandler) *Client {
 	return &Client{
-		hub:          hub,
-		conn:         conn,
-		send:         make(chan []byte, 256),
-		roomID:       roomID,
-		memberID:     memberID,
-		eventHandler: eventHandler,
+		hub:           hub,
+		conn:          conn,
+		send:          make(chan []byte, hub.config.SendQueueSize),
+		roomID:        roomID,
+		memberID:      memberID,
+		eventHandler:  eventHandler,
+		closeRequests: make(chan closeRequest, 1),
+	}
+}
+
+// messageCodec returns the codec used for messages sent to this client
+func (c *Client) messageCodec() Codec {
+	if c.codec == nil {
+		return JSONCodec
+	}
+	return c.codec
+}
+
+// negotiatedVersion returns the protocol version this client speaks
+func (c *Client) negotiatedVersion() int {
+	c.protocolMu.RLock()
+	defer c.protocolMu.RUnlock()
+
+	if c.protocolVersion == 0 {
+		return LegacyProtocolVersion
+	}
+	return c.protocolVersion
+}
+
+// setNegotiation records the outcome of the client's hello
+func (c *Client) setNegotiation(negotiation *Negotiation) {
+	c.protocolMu.Lock()
+	defer c.protocolMu.Unlock()
+
+	c.negotiated = true
+	c.protocolVersion = negotiation.ProtocolVersion
+	c.features = negotiation.Features
+}
+
+// hasFeature reports whether the client negotiated a protocol feature
+func (c *Client) hasFeature(feature string) bool {
+	c.protocolMu.RLock()
+	defer c.protocolMu.RUnlock()
+
+	for _, negotiated := range c.features {
+		if negotiated == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsEvent reports whether an event exists in the protocol version the
+// client speaks, so it can be sent to the client. welcome and ack are always
+// accepted since they answer a hello or a command carrying a requestId,
+// which any version can send.
+func (c *Client) acceptsEvent(eventType string) bool {
+	return eventType == EventWelcome || eventType == EventAck ||
+		isEventSupported(eventType, c.negotiatedVersion())
+}
+
+// closeWith asks the write pump to send any queued messages, then close the
+// connection with the given close code and reason
+func (c *Client) closeWith(code int, reason string) {
+	select {
+	case c.closeRequests <- closeRequest{code: code, reason: reason}:
+	default:
+		// A close is already pending
 	}
 }
 
@@ -79,15 +164,16 @@ func (c *Client) readPump() {
 		c.conn.Close()
 	}()
 
-	c.conn.SetReadLimit(maxMessageSize)
-	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	config := c.hub.config
+	c.conn.SetReadLimit(config.HardMessageLimit)
+	c.conn.SetReadDeadline(time.Now().Add(config.PongWait))
 	c.conn.SetPongHandler(func(string) error {
-		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		c.conn.SetReadDeadline(time.Now().Add(config.PongWait))
 		return nil
 	})
 
 	for {
-		_, message, err := c.conn.ReadMessage()
+		frameType, reader, err := c.conn.NextReader()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("WebSocket error: %v", err)
@@ -95,9 +181,32 @@ func (c *Client) readPump() {
 			break
 		}
 
+		// Read one byte past the limit so oversized messages can be detected
+		// without buffering them in full
+		message, err := io.ReadAll(io.LimitReader(reader, config.MaxMessageSize+1))
+		if err != nil {
+			log.Printf("WebSocket read error: %v", err)
+			break
+		}
+
+		if int64(len(message)) > config.MaxMessageSize {
+			// Drain the rest of the message; the connection's read limit
+			// still closes it if the message passes HardMessageLimit
+			if _, err := io.Copy(io.Discard, reader); err != nil {
+				log.Printf("WebSocket error discarding oversized message: %v", err)
+				break
+			}
+			log.Printf("Discarded oversized message from member %s in room %s", c.memberID, c.roomID)
+			if c.eventHandler != nil {
+				c.eventHandler.sendError(c, "", "MESSAGE_TOO_LARGE", "Message exceeds the maximum size",
+					fmt.Sprintf("maximum message size is %d bytes", config.MaxMessageSize))
+			}
+			continue
+		}
+
 		// Process the message through the event handler
 		if c.eventHandler != nil {
-			c.eventHandler.ProcessMessage(c, message)
+			c.eventHandler.ProcessMessage(c, frameType, message)
 		} else {
 			log.Printf("No event handler available for client in room %s", c.roomID)
 		}
@@ -106,7 +215,8 @@ func (c *Client) readPump() {
 
 // writePump pumps messages from the hub to the websocket connection
 func (c *Client) writePump() {
-	ticker := time.NewTicker(pingPeriod)
+	config := c.hub.config
+	ticker := time.NewTicker(config.PingPeriod)
 	defer func() {
 		ticker.Stop()
 		c.conn.Close()
@@ -115,32 +225,45 @@ func (c *Client) writePump() {
 	for {
 		select {
 		case message, ok := <-c.send:
-			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			c.conn.SetWriteDeadline(time.Now().Add(config.WriteWait))
 			if !ok {
 				// The hub closed the channel
 				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
 				return
 			}
 
-			w, err := c.conn.NextWriter(websocket.TextMessage)
-			if err != nil {
+			// Each message is its own frame so binary encodings need no
+			// delimiter; write everything already queued before blocking again
+			if err := c.conn.WriteMessage(c.messageCodec().FrameType(), message); err != nil {
 				return
 			}
-			w.Write(message)
-
-			// Add queued chat messages to the current websocket message
-			n := len(c.send)
-			for i := 0; i < n; i++ {
-				w.Write([]byte{'\n'})
-				w.Write(<-c.send)
+			for n := len(c.send); n > 0; n-- {
+				message, ok := <-c.send
+				if !ok {
+					c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+					return
+				}
+				if err := c.conn.WriteMessage(c.messageCodec().FrameType(), message); err != nil {
+					return
+				}
 			}
 
-			if err := w.Close(); err != nil {
-				return
+		case req := <-c.closeRequests:
+			c.conn.SetWriteDeadline(time.Now().Add(config.WriteWait))
+			for n := len(c.send); n > 0; n-- {
+				message, ok := <-c.send
+				if !ok {
+					break
+				}
+				if err := c.conn.WriteMessage(c.messageCodec().FrameType(), message); err != nil {
+					return
+				}
 			}
+			c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(req.code, req.reason))
+			return
 
 		case <-ticker.C:
-			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			c.conn.SetWriteDeadline(time.Now().Add(config.WriteWait))
 			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				return
 			}
@@ -150,13 +273,15 @@ func (c *Client) writePump() {
 
 // ServeWS handles websocket requests from the peer
 func ServeWS(hub *Hub, w http.ResponseWriter, r *http.Request, roomID, memberID string, eventHandler *EventHandler) {
-	conn, err := upgrader.Upgrade(w, r, nil)
+	conn, err := hub.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade error: %v", err)
 		return
 	}
+	conn.EnableWriteCompression(hub.config.EnableCompression)
 
 	client := NewClient(hub, conn, roomID, memberID, eventHandler)
+	client.codec = codecForSubprotocol(conn.Subprotocol())
 	client.hub.register <- client
 
 	// Allow collection of memory referenced by the caller by doing all work in
@@ -164,5 +289,6 @@ func ServeWS(hub *Hub, w http.ResponseWriter, r *http.Request, roomID, memberID
 	go client.writePump()
 	go client.readPump()
 
-	log.Printf("WebSocket client connected to room %s with member ID %s", roomID, memberID)
+	log.Printf("WebSocket client connected to room %s with member ID %s using %s encoding",
+		roomID, memberID, client.codec.Name())
 }
\ No newline at end of file
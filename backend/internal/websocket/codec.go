@@ -0,0 +1,93 @@
+package websocket
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/gorilla/websocket"
+	"github.com/ugorji/go/codec"
+)
+
+// Subprotocols a client can request in Sec-WebSocket-Protocol to choose the
+// wire encoding. Clients that request none get JSON.
+const (
+	SubprotocolJSON    = "json"
+	SubprotocolMsgpack = "msgpack"
+)
+
+// supportedSubprotocols is in order of server preference
+var supportedSubprotocols = []string{SubprotocolMsgpack, SubprotocolJSON}
+
+// Codec encodes and decodes messages for one wire encoding
+type Codec interface {
+	// Name is the subprotocol that selects this codec
+	Name() string
+
+	// FrameType is the WebSocket frame type messages are sent in
+	FrameType() int
+
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string   { return SubprotocolJSON }
+func (jsonCodec) FrameType() int { return websocket.TextMessage }
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+type msgpackCodec struct {
+	handle *codec.MsgpackHandle
+}
+
+func newMsgpackCodec() msgpackCodec {
+	handle := &codec.MsgpackHandle{WriteExt: true}
+	// Decode maps and strings into the same shapes encoding/json produces so
+	// payloads can be parsed the same way regardless of encoding
+	handle.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	handle.RawToString = true
+	return msgpackCodec{handle: handle}
+}
+
+func (msgpackCodec) Name() string   { return SubprotocolMsgpack }
+func (msgpackCodec) FrameType() int { return websocket.BinaryMessage }
+
+func (c msgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	var out []byte
+	err := codec.NewEncoderBytes(&out, c.handle).Encode(v)
+	return out, err
+}
+
+func (c msgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	return codec.NewDecoderBytes(data, c.handle).Decode(v)
+}
+
+// Shared codec instances; both are safe for concurrent use
+var (
+	JSONCodec    Codec = jsonCodec{}
+	MsgpackCodec Codec = newMsgpackCodec()
+)
+
+// codecForSubprotocol returns the codec selected by a negotiated subprotocol
+func codecForSubprotocol(subprotocol string) Codec {
+	if subprotocol == SubprotocolMsgpack {
+		return MsgpackCodec
+	}
+	return JSONCodec
+}
+
+// codecForFrameType returns the codec for an inbound message: text frames
+// carry JSON and binary frames MessagePack
+func codecForFrameType(frameType int) Codec {
+	if frameType == websocket.BinaryMessage {
+		return MsgpackCodec
+	}
+	return JSONCodec
+}
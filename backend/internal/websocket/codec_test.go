@@ -0,0 +1,116 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMsgpackCodec_RoundTrip(t *testing.T) {
+	msg := Message{
+		Type:      EventAddItem,
+		RoomID:    "test-room",
+		MemberID:  "test-member",
+		RequestID: "req-1",
+		Data: map[string]interface{}{
+			"groupId": "test-room",
+			"item":    map[string]interface{}{"title": "Visit Japan", "description": ""},
+		},
+	}
+
+	encoded, err := MsgpackCodec.Marshal(msg)
+	require.NoError(t, err)
+
+	var decoded Message
+	require.NoError(t, MsgpackCodec.Unmarshal(encoded, &decoded))
+	assert.Equal(t, msg.Type, decoded.Type)
+	assert.Equal(t, msg.RequestID, decoded.RequestID)
+
+	// Payloads decode into the same shape as JSON so parsePayload works
+	var payload AddItemPayload
+	eventHandler := NewEventHandler(NewHub(), NewMockRepositoryManager())
+	require.NoError(t, eventHandler.parsePayload(decoded.Data, &payload))
+	assert.Equal(t, "test-room", payload.GroupID)
+	assert.Equal(t, "Visit Japan", payload.Item.Title)
+}
+
+func TestCodecForFrameType(t *testing.T) {
+	assert.Equal(t, JSONCodec.Name(), codecForFrameType(websocket.TextMessage).Name())
+	assert.Equal(t, MsgpackCodec.Name(), codecForFrameType(websocket.BinaryMessage).Name())
+}
+
+func TestCodecForSubprotocol(t *testing.T) {
+	assert.Equal(t, MsgpackCodec.Name(), codecForSubprotocol(SubprotocolMsgpack).Name())
+	assert.Equal(t, JSONCodec.Name(), codecForSubprotocol(SubprotocolJSON).Name())
+	assert.Equal(t, JSONCodec.Name(), codecForSubprotocol("").Name())
+}
+
+func TestServeWS_NegotiatesEncoding(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	eventHandler := NewEventHandler(hub, NewMockRepositoryManager())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ServeWS(hub, w, r, "test-room", "test-member", eventHandler)
+	}))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	msgpackConn, resp, err := (&websocket.Dialer{Subprotocols: []string{SubprotocolMsgpack}}).Dial(url, nil)
+	require.NoError(t, err)
+	defer msgpackConn.Close()
+	assert.Equal(t, SubprotocolMsgpack, resp.Header.Get("Sec-WebSocket-Protocol"))
+
+	jsonConn, resp, err := (&websocket.Dialer{}).Dial(url, nil)
+	require.NoError(t, err)
+	defer jsonConn.Close()
+	assert.Empty(t, resp.Header.Get("Sec-WebSocket-Protocol"))
+
+	waitForClients(t, hub, "test-room", 2)
+	hub.BroadcastToRoom("test-room", EventItemAdded, map[string]string{"title": "Visit Japan"})
+
+	// The msgpack client receives a binary frame
+	msgpackConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	frameType, data, err := msgpackConn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, websocket.BinaryMessage, frameType)
+	var binaryMsg Message
+	require.NoError(t, MsgpackCodec.Unmarshal(data, &binaryMsg))
+	assert.Equal(t, EventItemAdded, binaryMsg.Type)
+	assert.NotEmpty(t, binaryMsg.EventID)
+
+	// The JSON client receives the same message as a single text frame
+	jsonConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	frameType, data, err = jsonConn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, websocket.TextMessage, frameType)
+	var textMsg Message
+	require.NoError(t, JSONCodec.Unmarshal(data, &textMsg))
+	assert.Equal(t, binaryMsg.EventID, textMsg.EventID)
+
+	// Requests from the msgpack client are decoded and answered in msgpack
+	request, err := MsgpackCodec.Marshal(Message{
+		Type:     "unknown-event",
+		RoomID:   "test-room",
+		MemberID: "test-member",
+	})
+	require.NoError(t, err)
+	require.NoError(t, msgpackConn.WriteMessage(websocket.BinaryMessage, request))
+
+	frameType, data, err = msgpackConn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, websocket.BinaryMessage, frameType)
+	var reply struct {
+		Type string       `json:"type"`
+		Data ErrorPayload `json:"data"`
+	}
+	require.NoError(t, MsgpackCodec.Unmarshal(data, &reply))
+	assert.Equal(t, EventError, reply.Type)
+	assert.Equal(t, "UNKNOWN_EVENT", reply.Data.Code)
+}
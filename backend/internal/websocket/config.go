@@ -0,0 +1,185 @@
+package websocket
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+)
+
+// Config holds the WebSocket connection limits and timings
+type Config struct {
+	// Time allowed to write a message to the peer
+	WriteWait time.Duration
+
+	// Time allowed to read the next pong message from the peer
+	PongWait time.Duration
+
+	// Send pings to peer with this period. Must be less than PongWait
+	PingPeriod time.Duration
+
+	// Largest message the server will process. Bigger messages are
+	// discarded and answered with a MESSAGE_TOO_LARGE error.
+	MaxMessageSize int64
+
+	// Messages above this size close the connection instead of being
+	// discarded, so a client cannot stream unbounded data at the server
+	HardMessageLimit int64
+
+	// Upgrader I/O buffer sizes
+	ReadBufferSize  int
+	WriteBufferSize int
+
+	// Number of outbound messages queued per client before it is dropped
+	SendQueueSize int
+
+	// Negotiate permessage-deflate compression with clients that offer it
+	EnableCompression bool
+
+	// Broadcasts kept per room so event stream subscribers can resume, and
+	// how long the history of an empty room is kept after its last event
+	HistorySize      int
+	HistoryRetention time.Duration
+
+	// Interval between keep-alive comments on event streams
+	StreamKeepAlive time.Duration
+}
+
+// envelopeOverhead is headroom for the Message envelope and the non-text
+// fields of a payload (type, IDs, requestId, keys and punctuation)
+const envelopeOverhead = 2048
+
+// jsonEscapeFactor is the worst-case growth of a string when JSON-encoded:
+// a control character such as \x01 becomes the six bytes \u0001
+const jsonEscapeFactor = 6
+
+// DefaultMaxMessageSize is the largest add-item message a client can send
+// within the model validation limits, assuming worst-case JSON escaping
+func DefaultMaxMessageSize() int64 {
+	text := models.MaxItemTitleLength + models.MaxItemDescriptionLength + models.MaxMemberNameLength
+	return int64(text*jsonEscapeFactor + envelopeOverhead)
+}
+
+// DefaultConfig returns the default WebSocket configuration
+func DefaultConfig() *Config {
+	maxMessageSize := DefaultMaxMessageSize()
+	pongWait := 60 * time.Second
+
+	return &Config{
+		WriteWait:         10 * time.Second,
+		PongWait:          pongWait,
+		PingPeriod:        (pongWait * 9) / 10,
+		MaxMessageSize:    maxMessageSize,
+		HardMessageLimit:  maxMessageSize * 16,
+		ReadBufferSize:    1024,
+		WriteBufferSize:   1024,
+		SendQueueSize:     256,
+		EnableCompression: true,
+		HistorySize:       100,
+		HistoryRetention:  10 * time.Minute,
+		StreamKeepAlive:   15 * time.Second,
+	}
+}
+
+// LoadConfigFromEnv loads WebSocket configuration from environment variables,
+// falling back to DefaultConfig for anything unset or malformed
+func LoadConfigFromEnv() *Config {
+	config := DefaultConfig()
+
+	config.WriteWait = getEnvDuration("WS_WRITE_WAIT", config.WriteWait)
+	config.PongWait = getEnvDuration("WS_PONG_WAIT", config.PongWait)
+	config.PingPeriod = getEnvDuration("WS_PING_PERIOD", (config.PongWait*9)/10)
+	config.MaxMessageSize = getEnvInt64("WS_MAX_MESSAGE_SIZE", config.MaxMessageSize)
+	config.HardMessageLimit = getEnvInt64("WS_HARD_MESSAGE_LIMIT", config.MaxMessageSize*16)
+	config.ReadBufferSize = int(getEnvInt64("WS_READ_BUFFER_SIZE", int64(config.ReadBufferSize)))
+	config.WriteBufferSize = int(getEnvInt64("WS_WRITE_BUFFER_SIZE", int64(config.WriteBufferSize)))
+	config.SendQueueSize = int(getEnvInt64("WS_SEND_QUEUE_SIZE", int64(config.SendQueueSize)))
+	config.EnableCompression = getEnvBool("WS_ENABLE_COMPRESSION", config.EnableCompression)
+	config.HistorySize = int(getEnvInt64("WS_HISTORY_SIZE", int64(config.HistorySize)))
+	config.HistoryRetention = getEnvDuration("WS_HISTORY_RETENTION", config.HistoryRetention)
+	config.StreamKeepAlive = getEnvDuration("SSE_KEEP_ALIVE", config.StreamKeepAlive)
+
+	return config
+}
+
+// Validate checks that the configuration is usable
+func (c *Config) Validate() error {
+	if c.WriteWait <= 0 {
+		return fmt.Errorf("write wait must be positive")
+	}
+	if c.PongWait <= 0 {
+		return fmt.Errorf("pong wait must be positive")
+	}
+	if c.PingPeriod <= 0 || c.PingPeriod >= c.PongWait {
+		return fmt.Errorf("ping period (%s) must be positive and less than pong wait (%s)", c.PingPeriod, c.PongWait)
+	}
+	if c.MaxMessageSize < DefaultMaxMessageSize() {
+		return fmt.Errorf("max message size %d is smaller than the largest valid message (%d bytes)",
+			c.MaxMessageSize, DefaultMaxMessageSize())
+	}
+	if c.HardMessageLimit < c.MaxMessageSize {
+		return fmt.Errorf("hard message limit %d must not be smaller than max message size %d",
+			c.HardMessageLimit, c.MaxMessageSize)
+	}
+	if c.ReadBufferSize <= 0 || c.WriteBufferSize <= 0 {
+		return fmt.Errorf("buffer sizes must be positive")
+	}
+	if c.SendQueueSize <= 0 {
+		return fmt.Errorf("send queue size must be positive")
+	}
+	if c.HistorySize < 0 || c.HistorySize >= c.SendQueueSize {
+		return fmt.Errorf("history size %d must be between 0 and the send queue size %d", c.HistorySize, c.SendQueueSize)
+	}
+	if c.HistoryRetention < 0 {
+		return fmt.Errorf("history retention must not be negative")
+	}
+	if c.StreamKeepAlive <= 0 {
+		return fmt.Errorf("stream keep-alive interval must be positive")
+	}
+	return nil
+}
+
+// getEnvDuration parses a duration such as "30s" from the environment
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid %s %q, using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return d
+}
+
+// getEnvInt64 parses an integer from the environment
+func getEnvInt64(key string, defaultValue int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Printf("Invalid %s %q, using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return n
+}
+
+// getEnvBool parses a boolean from the environment
+func getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid %s %q, using default %t", key, value, defaultValue)
+		return defaultValue
+	}
+	return b
+}
@@ -0,0 +1,122 @@
+package websocket
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"collaborative-bucket-list/internal/models"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultConfig_FitsLargestValidItem(t *testing.T) {
+	config := DefaultConfig()
+	require.NoError(t, config.Validate())
+
+	// Control characters survive sanitization and are escaped as \u00XX
+	description := strings.Repeat("\x01", models.MaxItemDescriptionLength)
+	message := Message{
+		Type:      EventAddItem,
+		RoomID:    "6f1c1d2e-8d1c-4b59-9c3e-0d5b8a3f9a10",
+		MemberID:  "0b8f6a4e-2c2d-4f8e-a7d9-0e3b7c1d5f22",
+		RequestID: "0b8f6a4e-2c2d-4f8e-a7d9-0e3b7c1d5f22",
+		Data: AddItemPayload{
+			GroupID: "6f1c1d2e-8d1c-4b59-9c3e-0d5b8a3f9a10",
+			Item: models.CreateItemRequest{
+				Title:       strings.Repeat("\x01", models.MaxItemTitleLength),
+				Description: &description,
+				MemberID:    "0b8f6a4e-2c2d-4f8e-a7d9-0e3b7c1d5f22",
+			},
+		},
+	}
+
+	messageBytes, err := json.Marshal(message)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, int64(len(messageBytes)), config.MaxMessageSize)
+}
+
+func TestLoadConfigFromEnv(t *testing.T) {
+	os.Setenv("WS_PONG_WAIT", "30s")
+	os.Setenv("WS_MAX_MESSAGE_SIZE", "65536")
+	os.Setenv("WS_READ_BUFFER_SIZE", "4096")
+	os.Setenv("WS_ENABLE_COMPRESSION", "false")
+	defer func() {
+		os.Unsetenv("WS_PONG_WAIT")
+		os.Unsetenv("WS_MAX_MESSAGE_SIZE")
+		os.Unsetenv("WS_READ_BUFFER_SIZE")
+		os.Unsetenv("WS_ENABLE_COMPRESSION")
+	}()
+
+	config := LoadConfigFromEnv()
+
+	assert.Equal(t, 30*time.Second, config.PongWait)
+	assert.Equal(t, 27*time.Second, config.PingPeriod)
+	assert.Equal(t, int64(65536), config.MaxMessageSize)
+	assert.Equal(t, int64(65536*16), config.HardMessageLimit)
+	assert.Equal(t, 4096, config.ReadBufferSize)
+	assert.False(t, config.EnableCompression)
+	assert.NoError(t, config.Validate())
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"ping period not below pong wait", func(c *Config) { c.PingPeriod = c.PongWait }},
+		{"max message size below model limits", func(c *Config) { c.MaxMessageSize = 512 }},
+		{"hard limit below max message size", func(c *Config) { c.HardMessageLimit = c.MaxMessageSize - 1 }},
+		{"zero send queue", func(c *Config) { c.SendQueueSize = 0 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			tt.modify(config)
+			assert.Error(t, config.Validate())
+		})
+	}
+}
+
+func TestServeWS_OversizedMessageKeepsConnection(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxMessageSize = DefaultMaxMessageSize()
+	hub := NewHubWithConfig(config)
+	go hub.Run()
+
+	eventHandler := NewEventHandler(hub, NewMockRepositoryManager())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ServeWS(hub, w, r, "test-room", "test-member", eventHandler)
+	}))
+	defer server.Close()
+
+	dialer := websocket.Dialer{EnableCompression: true}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Oversized message is rejected with an error
+	oversized := strings.Repeat("x", int(config.MaxMessageSize)+1)
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(oversized)))
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var reply struct {
+		Type string       `json:"type"`
+		Data ErrorPayload `json:"data"`
+	}
+	require.NoError(t, conn.ReadJSON(&reply))
+	assert.Equal(t, EventError, reply.Type)
+	assert.Equal(t, "MESSAGE_TOO_LARGE", reply.Data.Code)
+
+	// The connection is still usable afterwards
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"unknown-event","roomId":"test-room","memberId":"test-member"}`)))
+	require.NoError(t, conn.ReadJSON(&reply))
+	assert.Equal(t, "UNKNOWN_EVENT", reply.Data.Code)
+}
@@ -3,11 +3,14 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"time"
 
 	"collaborative-bucket-list/internal/models"
 	"collaborative-bucket-list/internal/repositories"
+
+	"github.com/google/uuid"
 )
 
 // HubInterface defines the interface for WebSocket hub operations
@@ -32,15 +35,31 @@ func NewEventHandler(hub HubInterface, repos repositories.RepositoryManager) *Ev
 // WebSocket event types
 const (
 	// Client to Server events
+	EventHello            = "hello"
 	EventJoinGroup        = "join-group"
 	EventAddItem          = "add-item"
 	EventToggleCompletion = "toggle-completion"
+	EventSetItemTags      = "set-item-tags"
+	EventReact            = "react"
+	EventVote             = "vote"
+	EventAssign           = "assign"
+	EventUnassign         = "unassign"
 
 	// Server to Client events
-	EventMemberJoined = "member-joined"
-	EventItemAdded    = "item-added"
-	EventItemUpdated  = "item-updated"
-	EventError        = "error"
+	EventWelcome             = "welcome"
+	EventMemberJoined        = "member-joined"
+	EventItemAdded           = "item-added"
+	EventItemUpdated         = "item-updated"
+	EventCommentAdded        = "comment-added"
+	EventCommentEdited       = "comment-edited"
+	EventCommentDeleted      = "comment-deleted"
+	EventReactionsUpdated    = "reactions-updated"
+	EventItemAssigned        = "item-assigned"
+	EventDeadlineApproaching = "deadline-approaching"
+	EventDeadlinePassed      = "deadline-passed"
+	EventAck                 = "ack"
+	EventResync              = "resync"
+	EventError               = "error"
 )
 
 // Event payload structures
@@ -59,6 +78,78 @@ type ToggleCompletionPayload struct {
 	ItemID    string `json:"itemId"`
 	Completed bool   `json:"completed"`
 	MemberID  string `json:"memberId"`
+	// The journal may be recorded when completing an item
+	models.CompletionJournal
+}
+
+// SetItemTagsPayload replaces the tags of an item
+type SetItemTagsPayload struct {
+	GroupID  string   `json:"groupId"`
+	ItemID   string   `json:"itemId"`
+	TagIDs   []string `json:"tagIds"`
+	MemberID string   `json:"memberId"`
+}
+
+// ReactPayload adds or removes an emoji reaction to an item
+type ReactPayload struct {
+	GroupID  string `json:"groupId"`
+	ItemID   string `json:"itemId"`
+	Emoji    string `json:"emoji"`
+	Remove   bool   `json:"remove"`
+	MemberID string `json:"memberId"`
+}
+
+// VotePayload adds or removes a member's upvote of an item
+type VotePayload struct {
+	GroupID  string `json:"groupId"`
+	ItemID   string `json:"itemId"`
+	Voted    bool   `json:"voted"`
+	MemberID string `json:"memberId"`
+}
+
+// AssignPayload assigns a member of the group to organize an item, or
+// removes the assignment
+type AssignPayload struct {
+	GroupID    string `json:"groupId"`
+	ItemID     string `json:"itemId"`
+	AssigneeID string `json:"assigneeId"`
+	MemberID   string `json:"memberId"`
+}
+
+// ItemAssignedPayload announces an assignment change. Item carries the
+// item's assignees after the change and MemberID the member who made it.
+type ItemAssignedPayload struct {
+	Item       models.BucketListItem `json:"item"`
+	AssigneeID string                `json:"assigneeId"`
+	Assigned   bool                  `json:"assigned"`
+	MemberID   string                `json:"memberId"`
+}
+
+// DeadlinePayload announces that a group's deadline is within
+// models.DueSoonWindow or has passed. Locked reports whether the list has
+// become read-only.
+type DeadlinePayload struct {
+	GroupID          string    `json:"groupId"`
+	Deadline         time.Time `json:"deadline"`
+	DeadlineDate     string    `json:"deadlineDate"`
+	SecondsRemaining int64     `json:"secondsRemaining"`
+	Locked           bool      `json:"locked"`
+}
+
+// CommentDeletedPayload identifies a deleted comment. Deleting a top-level
+// comment also deletes its replies, which get no events of their own.
+type CommentDeletedPayload struct {
+	ID       string  `json:"id"`
+	ItemID   string  `json:"itemId"`
+	ParentID *string `json:"parentId,omitempty"`
+}
+
+// AckPayload acknowledges a successfully processed command. The envelope
+// carries the command's requestId; Event names the command being answered
+// and Data holds its result.
+type AckPayload struct {
+	Event string      `json:"event"`
+	Data  interface{} `json:"data,omitempty"`
 }
 
 type ErrorPayload struct {
@@ -68,25 +159,40 @@ type ErrorPayload struct {
 }
 
 // ProcessMessage processes incoming WebSocket messages and routes them to appropriate handlers
-func (eh *EventHandler) ProcessMessage(client *Client, messageBytes []byte) {
+func (eh *EventHandler) ProcessMessage(client *Client, frameType int, messageBytes []byte) {
+	// The frame type selects the codec, whatever subprotocol the client
+	// negotiated
 	var msg Message
-	if err := json.Unmarshal(messageBytes, &msg); err != nil {
+	if err := codecForFrameType(frameType).Unmarshal(messageBytes, &msg); err != nil {
 		log.Printf("Error unmarshaling WebSocket message: %v", err)
-		eh.sendError(client, "INVALID_MESSAGE", "Invalid message format", err.Error())
+		eh.sendError(client, "", "INVALID_MESSAGE", "Invalid message format", err.Error())
 		return
 	}
 
 	// Validate that the message is for the correct room
 	if msg.RoomID != client.roomID {
 		log.Printf("Message room ID mismatch: expected %s, got %s", client.roomID, msg.RoomID)
-		eh.sendError(client, "ROOM_MISMATCH", "Message room ID does not match client room", "")
+		eh.sendError(client, msg.RequestID, "ROOM_MISMATCH", "Message room ID does not match client room", "")
 		return
 	}
 
 	// Validate that the message is from the correct member
 	if msg.MemberID != client.memberID {
 		log.Printf("Message member ID mismatch: expected %s, got %s", client.memberID, msg.MemberID)
-		eh.sendError(client, "MEMBER_MISMATCH", "Message member ID does not match client member", "")
+		eh.sendError(client, msg.RequestID, "MEMBER_MISMATCH", "Message member ID does not match client member", "")
+		return
+	}
+
+	// hello is accepted at any protocol version since it is how the version
+	// gets negotiated in the first place
+	if msg.Type == EventHello {
+		eh.handleHello(client, msg.RequestID, msg.Data)
+		return
+	}
+
+	// Reject events the negotiated protocol version does not include
+	if !isEventSupported(msg.Type, client.negotiatedVersion()) && isEventSupported(msg.Type, ProtocolVersion) {
+		eh.sendError(client, msg.RequestID, "UNSUPPORTED_EVENT", "Event is not available in the negotiated protocol version", msg.Type)
 		return
 	}
 
@@ -95,34 +201,44 @@ func (eh *EventHandler) ProcessMessage(client *Client, messageBytes []byte) {
 	// Route message to appropriate handler
 	switch msg.Type {
 	case EventJoinGroup:
-		eh.handleJoinGroup(ctx, client, msg.Data)
+		eh.handleJoinGroup(ctx, client, msg.RequestID, msg.Data)
 	case EventAddItem:
-		eh.handleAddItem(ctx, client, msg.Data)
+		eh.handleAddItem(ctx, client, msg.RequestID, msg.Data)
 	case EventToggleCompletion:
-		eh.handleToggleCompletion(ctx, client, msg.Data)
+		eh.handleToggleCompletion(ctx, client, msg.RequestID, msg.Data)
+	case EventSetItemTags:
+		eh.handleSetItemTags(ctx, client, msg.RequestID, msg.Data)
+	case EventReact:
+		eh.handleReact(ctx, client, msg.RequestID, msg.Data)
+	case EventVote:
+		eh.handleVote(ctx, client, msg.RequestID, msg.Data)
+	case EventAssign:
+		eh.handleAssignment(ctx, client, msg.RequestID, msg.Type, msg.Data, true)
+	case EventUnassign:
+		eh.handleAssignment(ctx, client, msg.RequestID, msg.Type, msg.Data, false)
 	default:
 		log.Printf("Unknown WebSocket event type: %s", msg.Type)
-		eh.sendError(client, "UNKNOWN_EVENT", "Unknown event type", msg.Type)
+		eh.sendError(client, msg.RequestID, "UNKNOWN_EVENT", "Unknown event type", msg.Type)
 	}
 }
 
 // handleJoinGroup handles join-group events
-func (eh *EventHandler) handleJoinGroup(ctx context.Context, client *Client, data interface{}) {
+func (eh *EventHandler) handleJoinGroup(ctx context.Context, client *Client, requestID string, data interface{}) {
 	var payload JoinGroupPayload
 	if err := eh.parsePayload(data, &payload); err != nil {
-		eh.sendError(client, "INVALID_PAYLOAD", "Invalid join-group payload", err.Error())
+		eh.sendError(client, requestID, "INVALID_PAYLOAD", "Invalid join-group payload", err.Error())
 		return
 	}
 
 	// Validate group ID matches client room
 	if payload.GroupID != client.roomID {
-		eh.sendError(client, "GROUP_MISMATCH", "Group ID does not match client room", "")
+		eh.sendError(client, requestID, "GROUP_MISMATCH", "Group ID does not match client room", "")
 		return
 	}
 
 	// Validate member ID matches client member
 	if payload.MemberID != client.memberID {
-		eh.sendError(client, "MEMBER_MISMATCH", "Member ID does not match client member", "")
+		eh.sendError(client, requestID, "MEMBER_MISMATCH", "Member ID does not match client member", "")
 		return
 	}
 
@@ -130,45 +246,46 @@ func (eh *EventHandler) handleJoinGroup(ctx context.Context, client *Client, dat
 	member, err := eh.repos.Members().GetByID(ctx, payload.MemberID)
 	if err != nil {
 		log.Printf("Error fetching member %s: %v", payload.MemberID, err)
-		eh.sendError(client, "MEMBER_NOT_FOUND", "Member not found", "")
+		eh.sendError(client, requestID, "MEMBER_NOT_FOUND", "Member not found", "")
 		return
 	}
 
 	if member.GroupID != payload.GroupID {
 		log.Printf("Member %s does not belong to group %s", payload.MemberID, payload.GroupID)
-		eh.sendError(client, "MEMBER_GROUP_MISMATCH", "Member does not belong to this group", "")
+		eh.sendError(client, requestID, "MEMBER_GROUP_MISMATCH", "Member does not belong to this group", "")
 		return
 	}
 
 	// Broadcast member-joined event to all clients in the room
 	eh.hub.BroadcastToRoom(payload.GroupID, EventMemberJoined, member)
+	eh.sendAck(client, requestID, EventJoinGroup, member)
 	log.Printf("Member %s joined group %s via WebSocket", member.Name, payload.GroupID)
 }
 
 // handleAddItem handles add-item events
-func (eh *EventHandler) handleAddItem(ctx context.Context, client *Client, data interface{}) {
+func (eh *EventHandler) handleAddItem(ctx context.Context, client *Client, requestID string, data interface{}) {
 	var payload AddItemPayload
 	if err := eh.parsePayload(data, &payload); err != nil {
-		eh.sendError(client, "INVALID_PAYLOAD", "Invalid add-item payload", err.Error())
+		eh.sendError(client, requestID, "INVALID_PAYLOAD", "Invalid add-item payload", err.Error())
 		return
 	}
 
 	// Validate group ID matches client room
 	if payload.GroupID != client.roomID {
-		eh.sendError(client, "GROUP_MISMATCH", "Group ID does not match client room", "")
+		eh.sendError(client, requestID, "GROUP_MISMATCH", "Group ID does not match client room", "")
 		return
 	}
 
 	// Validate member ID matches client member
 	if payload.Item.MemberID != client.memberID {
-		eh.sendError(client, "MEMBER_MISMATCH", "Member ID does not match client member", "")
+		eh.sendError(client, requestID, "MEMBER_MISMATCH", "Member ID does not match client member", "")
 		return
 	}
 
 	// Sanitize and validate the item request
 	payload.Item.Sanitize()
 	if validation := payload.Item.Validate(); !validation.IsValid {
-		eh.sendError(client, "VALIDATION_ERROR", "Invalid item data", validation.Errors[0].Message)
+		eh.sendError(client, requestID, "VALIDATION_ERROR", "Invalid item data", validation.Errors[0].Message)
 		return
 	}
 
@@ -176,54 +293,80 @@ func (eh *EventHandler) handleAddItem(ctx context.Context, client *Client, data
 	member, err := eh.repos.Members().GetByID(ctx, payload.Item.MemberID)
 	if err != nil {
 		log.Printf("Error fetching member %s: %v", payload.Item.MemberID, err)
-		eh.sendError(client, "MEMBER_NOT_FOUND", "Member not found", "")
+		eh.sendError(client, requestID, "MEMBER_NOT_FOUND", "Member not found", "")
 		return
 	}
 
 	if member.GroupID != payload.GroupID {
 		log.Printf("Member %s does not belong to group %s", payload.Item.MemberID, payload.GroupID)
-		eh.sendError(client, "MEMBER_GROUP_MISMATCH", "Member does not belong to this group", "")
+		eh.sendError(client, requestID, "MEMBER_GROUP_MISMATCH", "Member does not belong to this group", "")
+		return
+	}
+
+	group, ok := eh.unlockedGroup(ctx, client, requestID, payload.GroupID)
+	if !ok {
+		return
+	}
+
+	// A scheduled item must be planned before the group's deadline
+	if validation := payload.Item.ItemSchedule.Validate(group.Deadline); !validation.IsValid {
+		eh.sendError(client, requestID, "VALIDATION_ERROR", "Invalid item data", validation.Errors[0].Message)
 		return
 	}
 
 	// Create the bucket list item
 	item := &models.BucketListItem{
-		GroupID:     payload.GroupID,
-		Title:       payload.Item.Title,
-		Description: payload.Item.Description,
-		Completed:   false,
-		CreatedBy:   payload.Item.MemberID,
-		CreatedAt:   time.Now(),
+		ID:           uuid.New().String(),
+		GroupID:      payload.GroupID,
+		Title:        payload.Item.Title,
+		Description:  payload.Item.Description,
+		Completed:    false,
+		ItemSchedule: payload.Item.ItemSchedule,
+		CreatedBy:    &payload.Item.MemberID,
+		CreatedAt:    time.Now(),
 	}
 
 	if err := eh.repos.BucketItems().Create(ctx, item); err != nil {
 		log.Printf("Error creating bucket item: %v", err)
-		eh.sendError(client, "CREATE_FAILED", "Failed to create item", err.Error())
+		eh.sendError(client, requestID, "CREATE_FAILED", "Failed to create item", err.Error())
 		return
 	}
 
 	// Broadcast item-added event to all clients in the room
 	eh.hub.BroadcastToRoom(payload.GroupID, EventItemAdded, item)
+	eh.sendAck(client, requestID, EventAddItem, item)
 	log.Printf("Item '%s' added to group %s by member %s", item.Title, payload.GroupID, member.Name)
 }
 
 // handleToggleCompletion handles toggle-completion events
-func (eh *EventHandler) handleToggleCompletion(ctx context.Context, client *Client, data interface{}) {
+func (eh *EventHandler) handleToggleCompletion(ctx context.Context, client *Client, requestID string, data interface{}) {
 	var payload ToggleCompletionPayload
 	if err := eh.parsePayload(data, &payload); err != nil {
-		eh.sendError(client, "INVALID_PAYLOAD", "Invalid toggle-completion payload", err.Error())
+		eh.sendError(client, requestID, "INVALID_PAYLOAD", "Invalid toggle-completion payload", err.Error())
 		return
 	}
 
 	// Validate group ID matches client room
 	if payload.GroupID != client.roomID {
-		eh.sendError(client, "GROUP_MISMATCH", "Group ID does not match client room", "")
+		eh.sendError(client, requestID, "GROUP_MISMATCH", "Group ID does not match client room", "")
 		return
 	}
 
 	// Validate member ID matches client member
 	if payload.MemberID != client.memberID {
-		eh.sendError(client, "MEMBER_MISMATCH", "Member ID does not match client member", "")
+		eh.sendError(client, requestID, "MEMBER_MISMATCH", "Member ID does not match client member", "")
+		return
+	}
+
+	// Sanitize and validate the journal
+	req := models.ToggleCompletionRequest{
+		Completed:         payload.Completed,
+		MemberID:          payload.MemberID,
+		CompletionJournal: payload.CompletionJournal,
+	}
+	req.CompletionJournal.Sanitize()
+	if validation := req.Validate(); !validation.IsValid {
+		eh.sendError(client, requestID, "VALIDATION_ERROR", "Invalid completion", validation.Errors[0].Message)
 		return
 	}
 
@@ -231,13 +374,13 @@ func (eh *EventHandler) handleToggleCompletion(ctx context.Context, client *Clie
 	member, err := eh.repos.Members().GetByID(ctx, payload.MemberID)
 	if err != nil {
 		log.Printf("Error fetching member %s: %v", payload.MemberID, err)
-		eh.sendError(client, "MEMBER_NOT_FOUND", "Member not found", "")
+		eh.sendError(client, requestID, "MEMBER_NOT_FOUND", "Member not found", "")
 		return
 	}
 
 	if member.GroupID != payload.GroupID {
 		log.Printf("Member %s does not belong to group %s", payload.MemberID, payload.GroupID)
-		eh.sendError(client, "MEMBER_GROUP_MISMATCH", "Member does not belong to this group", "")
+		eh.sendError(client, requestID, "MEMBER_GROUP_MISMATCH", "Member does not belong to this group", "")
 		return
 	}
 
@@ -245,20 +388,45 @@ func (eh *EventHandler) handleToggleCompletion(ctx context.Context, client *Clie
 	item, err := eh.repos.BucketItems().GetByID(ctx, payload.ItemID)
 	if err != nil {
 		log.Printf("Error fetching item %s: %v", payload.ItemID, err)
-		eh.sendError(client, "ITEM_NOT_FOUND", "Item not found", "")
+		eh.sendError(client, requestID, "ITEM_NOT_FOUND", "Item not found", "")
 		return
 	}
 
 	if item.GroupID != payload.GroupID {
 		log.Printf("Item %s does not belong to group %s", payload.ItemID, payload.GroupID)
-		eh.sendError(client, "ITEM_GROUP_MISMATCH", "Item does not belong to this group", "")
+		eh.sendError(client, requestID, "ITEM_GROUP_MISMATCH", "Item does not belong to this group", "")
 		return
 	}
 
-	// Toggle the completion status
-	if err := eh.repos.BucketItems().ToggleCompletion(ctx, payload.ItemID, payload.MemberID, payload.Completed); err != nil {
+	group, ok := eh.unlockedGroup(ctx, client, requestID, payload.GroupID)
+	if !ok {
+		return
+	}
+
+	// The item cannot have happened after today in the group's timezone
+	if validation := req.CompletionJournal.ValidateOccurredOn(time.Now(), group.Location()); !validation.IsValid {
+		eh.sendError(client, requestID, "VALIDATION_ERROR", "Invalid completion", validation.Errors[0].Message)
+		return
+	}
+
+	// Toggle the completion status, recording the journal given with it
+	toggle := func(repos repositories.RepositoryManager) error {
+		if err := repos.BucketItems().ToggleCompletion(ctx, payload.ItemID, payload.MemberID, payload.Completed); err != nil {
+			return err
+		}
+		if req.CompletionJournal.IsEmpty() {
+			return nil
+		}
+		return repos.BucketItems().UpdateCompletionJournal(ctx, payload.ItemID, req.CompletionJournal)
+	}
+	if req.CompletionJournal.IsEmpty() {
+		err = toggle(eh.repos)
+	} else {
+		err = eh.repos.WithTx(ctx, toggle)
+	}
+	if err != nil {
 		log.Printf("Error toggling item completion: %v", err)
-		eh.sendError(client, "UPDATE_FAILED", "Failed to update item", err.Error())
+		eh.sendError(client, requestID, "UPDATE_FAILED", "Failed to update item", err.Error())
 		return
 	}
 
@@ -266,12 +434,13 @@ func (eh *EventHandler) handleToggleCompletion(ctx context.Context, client *Clie
 	updatedItem, err := eh.repos.BucketItems().GetByID(ctx, payload.ItemID)
 	if err != nil {
 		log.Printf("Error fetching updated item %s: %v", payload.ItemID, err)
-		eh.sendError(client, "FETCH_FAILED", "Failed to fetch updated item", err.Error())
+		eh.sendError(client, requestID, "FETCH_FAILED", "Failed to fetch updated item", err.Error())
 		return
 	}
 
 	// Broadcast item-updated event to all clients in the room
 	eh.hub.BroadcastToRoom(payload.GroupID, EventItemUpdated, updatedItem)
+	eh.sendAck(client, requestID, EventToggleCompletion, updatedItem)
 	
 	completionStatus := "incomplete"
 	if payload.Completed {
@@ -280,6 +449,298 @@ func (eh *EventHandler) handleToggleCompletion(ctx context.Context, client *Clie
 	log.Printf("Item '%s' marked as %s in group %s by member %s", updatedItem.Title, completionStatus, payload.GroupID, member.Name)
 }
 
+// handleSetItemTags handles set-item-tags events. The updated item is
+// broadcast as item-updated.
+func (eh *EventHandler) handleSetItemTags(ctx context.Context, client *Client, requestID string, data interface{}) {
+	var payload SetItemTagsPayload
+	if err := eh.parsePayload(data, &payload); err != nil {
+		eh.sendError(client, requestID, "INVALID_PAYLOAD", "Invalid set-item-tags payload", err.Error())
+		return
+	}
+
+	// Validate group ID matches client room
+	if payload.GroupID != client.roomID {
+		eh.sendError(client, requestID, "GROUP_MISMATCH", "Group ID does not match client room", "")
+		return
+	}
+
+	// Validate member ID matches client member
+	if payload.MemberID != client.memberID {
+		eh.sendError(client, requestID, "MEMBER_MISMATCH", "Member ID does not match client member", "")
+		return
+	}
+
+	// Sanitize and validate the tags
+	req := models.SetItemTagsRequest{TagIDs: payload.TagIDs, MemberID: payload.MemberID}
+	req.Sanitize()
+	if validation := req.Validate(); !validation.IsValid {
+		eh.sendError(client, requestID, "VALIDATION_ERROR", "Invalid tags", validation.Errors[0].Message)
+		return
+	}
+
+	// Verify the member exists and belongs to the group
+	member, err := eh.repos.Members().GetByID(ctx, payload.MemberID)
+	if err != nil {
+		log.Printf("Error fetching member %s: %v", payload.MemberID, err)
+		eh.sendError(client, requestID, "MEMBER_NOT_FOUND", "Member not found", "")
+		return
+	}
+
+	if member.GroupID != payload.GroupID {
+		log.Printf("Member %s does not belong to group %s", payload.MemberID, payload.GroupID)
+		eh.sendError(client, requestID, "MEMBER_GROUP_MISMATCH", "Member does not belong to this group", "")
+		return
+	}
+
+	// Verify the item exists and belongs to the group
+	item, err := eh.repos.BucketItems().GetByID(ctx, payload.ItemID)
+	if err != nil {
+		log.Printf("Error fetching item %s: %v", payload.ItemID, err)
+		eh.sendError(client, requestID, "ITEM_NOT_FOUND", "Item not found", "")
+		return
+	}
+
+	if item.GroupID != payload.GroupID {
+		log.Printf("Item %s does not belong to group %s", payload.ItemID, payload.GroupID)
+		eh.sendError(client, requestID, "ITEM_GROUP_MISMATCH", "Item does not belong to this group", "")
+		return
+	}
+
+	if _, ok := eh.unlockedGroup(ctx, client, requestID, payload.GroupID); !ok {
+		return
+	}
+
+	// Replace the tags and fetch the updated item
+	var updatedItem *models.BucketListItem
+	err = eh.repos.WithTx(ctx, func(txRepos repositories.RepositoryManager) error {
+		if err := txRepos.Tags().SetItemTags(ctx, payload.ItemID, req.TagIDs); err != nil {
+			return err
+		}
+		var err error
+		updatedItem, err = txRepos.BucketItems().GetByID(ctx, payload.ItemID)
+		return err
+	})
+	if err != nil {
+		log.Printf("Error setting tags of item %s: %v", payload.ItemID, err)
+		if errors.Is(err, repositories.ErrTagNotFound) {
+			eh.sendError(client, requestID, "TAG_NOT_FOUND", "Every tag must be a tag of this group", "")
+			return
+		}
+		eh.sendError(client, requestID, "UPDATE_FAILED", "Failed to update item tags", err.Error())
+		return
+	}
+
+	// Broadcast item-updated event to all clients in the room
+	eh.hub.BroadcastToRoom(payload.GroupID, EventItemUpdated, updatedItem)
+	eh.sendAck(client, requestID, EventSetItemTags, updatedItem)
+	log.Printf("Item '%s' tagged with %d tags in group %s by member %s", updatedItem.Title, len(updatedItem.TagIDs), payload.GroupID, member.Name)
+}
+
+// handleReact handles react events. The item's aggregated reactions are
+// broadcast as reactions-updated.
+func (eh *EventHandler) handleReact(ctx context.Context, client *Client, requestID string, data interface{}) {
+	var payload ReactPayload
+	if err := eh.parsePayload(data, &payload); err != nil {
+		eh.sendError(client, requestID, "INVALID_PAYLOAD", "Invalid react payload", err.Error())
+		return
+	}
+
+	// Sanitize and validate the reaction
+	reaction := models.Reaction{ItemID: payload.ItemID, MemberID: payload.MemberID, Emoji: payload.Emoji, CreatedAt: time.Now()}
+	reaction.Sanitize()
+	if validation := models.ValidateEmoji(reaction.Emoji); !validation.IsValid {
+		eh.sendError(client, requestID, "VALIDATION_ERROR", "Invalid reaction", validation.Errors[0].Message)
+		return
+	}
+
+	member, ok := eh.authorizeItemMember(ctx, client, requestID, payload.GroupID, payload.ItemID, payload.MemberID)
+	if !ok {
+		return
+	}
+	if _, ok := eh.unlockedGroup(ctx, client, requestID, payload.GroupID); !ok {
+		return
+	}
+
+	var err error
+	if payload.Remove {
+		err = eh.repos.Reactions().RemoveReaction(ctx, reaction.ItemID, reaction.MemberID, reaction.Emoji)
+	} else {
+		err = eh.repos.Reactions().AddReaction(ctx, &reaction)
+	}
+	if err != nil {
+		log.Printf("Error updating reactions of item %s: %v", payload.ItemID, err)
+		eh.sendError(client, requestID, "UPDATE_FAILED", "Failed to update reaction", err.Error())
+		return
+	}
+
+	eh.broadcastReactions(ctx, client, requestID, EventReact, payload.GroupID, payload.ItemID)
+	log.Printf("Reaction %s on item %s updated in group %s by member %s", reaction.Emoji, payload.ItemID, payload.GroupID, member.Name)
+}
+
+// handleVote handles vote events. The item's aggregated reactions are
+// broadcast as reactions-updated.
+func (eh *EventHandler) handleVote(ctx context.Context, client *Client, requestID string, data interface{}) {
+	var payload VotePayload
+	if err := eh.parsePayload(data, &payload); err != nil {
+		eh.sendError(client, requestID, "INVALID_PAYLOAD", "Invalid vote payload", err.Error())
+		return
+	}
+
+	member, ok := eh.authorizeItemMember(ctx, client, requestID, payload.GroupID, payload.ItemID, payload.MemberID)
+	if !ok {
+		return
+	}
+	if _, ok := eh.unlockedGroup(ctx, client, requestID, payload.GroupID); !ok {
+		return
+	}
+
+	if err := eh.repos.Reactions().SetVote(ctx, payload.ItemID, payload.MemberID, payload.Voted); err != nil {
+		log.Printf("Error updating vote on item %s: %v", payload.ItemID, err)
+		eh.sendError(client, requestID, "UPDATE_FAILED", "Failed to update vote", err.Error())
+		return
+	}
+
+	eh.broadcastReactions(ctx, client, requestID, EventVote, payload.GroupID, payload.ItemID)
+
+	voteStatus := "withdrawn"
+	if payload.Voted {
+		voteStatus = "cast"
+	}
+	log.Printf("Vote on item %s %s in group %s by member %s", payload.ItemID, voteStatus, payload.GroupID, member.Name)
+}
+
+// handleAssignment handles assign and unassign events. The updated item is
+// broadcast as item-assigned.
+func (eh *EventHandler) handleAssignment(ctx context.Context, client *Client, requestID, event string, data interface{}, assigned bool) {
+	var payload AssignPayload
+	if err := eh.parsePayload(data, &payload); err != nil {
+		eh.sendError(client, requestID, "INVALID_PAYLOAD", "Invalid "+event+" payload", err.Error())
+		return
+	}
+
+	member, ok := eh.authorizeItemMember(ctx, client, requestID, payload.GroupID, payload.ItemID, payload.MemberID)
+	if !ok {
+		return
+	}
+	if _, ok := eh.unlockedGroup(ctx, client, requestID, payload.GroupID); !ok {
+		return
+	}
+
+	// Change the assignment and fetch the updated item
+	var updatedItem *models.BucketListItem
+	err := eh.repos.WithTx(ctx, func(txRepos repositories.RepositoryManager) error {
+		if err := txRepos.BucketItems().SetAssignment(ctx, payload.ItemID, payload.AssigneeID, assigned); err != nil {
+			return err
+		}
+		var err error
+		updatedItem, err = txRepos.BucketItems().GetByID(ctx, payload.ItemID)
+		return err
+	})
+	if err != nil {
+		log.Printf("Error updating assignees of item %s: %v", payload.ItemID, err)
+		if errors.Is(err, repositories.ErrMemberNotFound) {
+			eh.sendError(client, requestID, "UNKNOWN_ASSIGNEE", "The assignee must be a member of this group", "")
+			return
+		}
+		eh.sendError(client, requestID, "UPDATE_FAILED", "Failed to update item assignees", err.Error())
+		return
+	}
+
+	eh.hub.BroadcastToRoom(payload.GroupID, EventItemAssigned, ItemAssignedPayload{
+		Item:       *updatedItem,
+		AssigneeID: payload.AssigneeID,
+		Assigned:   assigned,
+		MemberID:   member.ID,
+	})
+	eh.sendAck(client, requestID, event, updatedItem)
+
+	action := "unassigned from"
+	if assigned {
+		action = "assigned to"
+	}
+	log.Printf("Member %s %s item '%s' in group %s by member %s", payload.AssigneeID, action, updatedItem.Title, payload.GroupID, member.Name)
+}
+
+// authorizeItemMember checks that a command's group and member match the
+// client and that the member and item both belong to the group. It sends
+// the error and returns false when they do not.
+func (eh *EventHandler) authorizeItemMember(ctx context.Context, client *Client, requestID, groupID, itemID, memberID string) (*models.Member, bool) {
+	// Validate group ID matches client room
+	if groupID != client.roomID {
+		eh.sendError(client, requestID, "GROUP_MISMATCH", "Group ID does not match client room", "")
+		return nil, false
+	}
+
+	// Validate member ID matches client member
+	if memberID != client.memberID {
+		eh.sendError(client, requestID, "MEMBER_MISMATCH", "Member ID does not match client member", "")
+		return nil, false
+	}
+
+	// Verify the member exists and belongs to the group
+	member, err := eh.repos.Members().GetByID(ctx, memberID)
+	if err != nil {
+		log.Printf("Error fetching member %s: %v", memberID, err)
+		eh.sendError(client, requestID, "MEMBER_NOT_FOUND", "Member not found", "")
+		return nil, false
+	}
+
+	if member.GroupID != groupID {
+		log.Printf("Member %s does not belong to group %s", memberID, groupID)
+		eh.sendError(client, requestID, "MEMBER_GROUP_MISMATCH", "Member does not belong to this group", "")
+		return nil, false
+	}
+
+	// Verify the item exists and belongs to the group
+	item, err := eh.repos.BucketItems().GetByID(ctx, itemID)
+	if err != nil {
+		log.Printf("Error fetching item %s: %v", itemID, err)
+		eh.sendError(client, requestID, "ITEM_NOT_FOUND", "Item not found", "")
+		return nil, false
+	}
+
+	if item.GroupID != groupID {
+		log.Printf("Item %s does not belong to group %s", itemID, groupID)
+		eh.sendError(client, requestID, "ITEM_GROUP_MISMATCH", "Item does not belong to this group", "")
+		return nil, false
+	}
+
+	return member, true
+}
+
+// unlockedGroup fetches a group whose list a command changes. It sends the
+// error and returns false when the group is missing or its list locked
+// after the deadline.
+func (eh *EventHandler) unlockedGroup(ctx context.Context, client *Client, requestID, groupID string) (*models.Group, bool) {
+	group, err := eh.repos.Groups().GetByID(ctx, groupID)
+	if err != nil {
+		log.Printf("Error fetching group %s: %v", groupID, err)
+		eh.sendError(client, requestID, "GROUP_NOT_FOUND", "Group not found", "")
+		return nil, false
+	}
+
+	if group.IsLocked(time.Now()) {
+		eh.sendError(client, requestID, "GROUP_LOCKED", "The list is read-only since its deadline has passed", "")
+		return nil, false
+	}
+
+	return group, true
+}
+
+// broadcastReactions broadcasts the aggregated reactions of an item as
+// reactions-updated and acks the command with them
+func (eh *EventHandler) broadcastReactions(ctx context.Context, client *Client, requestID, event, groupID, itemID string) {
+	reactions, err := eh.repos.Reactions().GetByItemID(ctx, itemID)
+	if err != nil {
+		log.Printf("Error fetching reactions of item %s: %v", itemID, err)
+		eh.sendError(client, requestID, "FETCH_FAILED", "Failed to fetch item reactions", err.Error())
+		return
+	}
+
+	eh.hub.BroadcastToRoom(groupID, EventReactionsUpdated, reactions)
+	eh.sendAck(client, requestID, event, reactions)
+}
+
 // parsePayload parses WebSocket event payload data
 func (eh *EventHandler) parsePayload(data interface{}, target interface{}) error {
 	// Convert data to JSON bytes and then unmarshal to target struct
@@ -291,8 +752,29 @@ func (eh *EventHandler) parsePayload(data interface{}, target interface{}) error
 	return json.Unmarshal(dataBytes, target)
 }
 
-// sendError sends an error message to a specific client
-func (eh *EventHandler) sendError(client *Client, code, message, details string) {
+// sendAck acknowledges a processed command. Commands sent without a request
+// ID are fire-and-forget and get no ack; every command carrying one gets an
+// ack or an error, whether or not the client negotiated request-ack.
+func (eh *EventHandler) sendAck(client *Client, requestID, event string, data interface{}) {
+	if requestID == "" {
+		return
+	}
+
+	eh.sendToClient(client, Message{
+		Type:      EventAck,
+		RoomID:    client.roomID,
+		MemberID:  client.memberID,
+		RequestID: requestID,
+		Data: AckPayload{
+			Event: event,
+			Data:  data,
+		},
+	})
+}
+
+// sendError sends an error message to a specific client, echoing the request
+// ID of the command that caused it if there was one
+func (eh *EventHandler) sendError(client *Client, requestID, code, message, details string) {
 	errorPayload := ErrorPayload{
 		Code:    code,
 		Message: message,
@@ -300,22 +782,39 @@ func (eh *EventHandler) sendError(client *Client, code, message, details string)
 	}
 
 	errorMessage := Message{
-		Type:     EventError,
-		RoomID:   client.roomID,
-		MemberID: client.memberID,
-		Data:     errorPayload,
+		Type:      EventError,
+		RoomID:    client.roomID,
+		MemberID:  client.memberID,
+		RequestID: requestID,
+		Data:      errorPayload,
+	}
+
+	if eh.sendToClient(client, errorMessage) {
+		log.Printf("Sent error to client in room %s: %s - %s", client.roomID, code, message)
+	} else {
+		log.Printf("Failed to send error to client in room %s: %s - %s", client.roomID, code, message)
+	}
+}
+
+// sendToClient queues a message for a single client without blocking.
+// It reports whether the message was queued; events the client's protocol
+// version does not include are not.
+func (eh *EventHandler) sendToClient(client *Client, message Message) bool {
+	if !client.acceptsEvent(message.Type) {
+		return false
 	}
 
-	messageBytes, err := json.Marshal(errorMessage)
+	messageBytes, err := client.messageCodec().Marshal(message)
 	if err != nil {
-		log.Printf("Error marshaling error message: %v", err)
-		return
+		log.Printf("Error marshaling %s message: %v", message.Type, err)
+		return false
 	}
 
 	select {
 	case client.send <- messageBytes:
-		log.Printf("Sent error to client in room %s: %s - %s", client.roomID, code, message)
+		return true
 	default:
-		log.Printf("Failed to send error to client in room %s (channel full): %s - %s", client.roomID, code, message)
+		log.Printf("Client send channel full in room %s, dropping %s message", client.roomID, message.Type)
+		return false
 	}
-}
\ No newline at end of file
+}
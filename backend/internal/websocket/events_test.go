@@ -2,17 +2,20 @@ package websocket
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"collaborative-bucket-list/internal/models"
 	"collaborative-bucket-list/internal/repositories"
 
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // Mock repository interfaces
@@ -35,6 +38,11 @@ func (m *MockGroupRepository) GetByUserID(ctx context.Context, userID string) ([
 	return args.Get(0).([]models.Group), args.Error(1)
 }
 
+func (m *MockGroupRepository) GetByDeadlineBetween(ctx context.Context, after, until time.Time) ([]models.Group, error) {
+	args := m.Called(ctx, after, until)
+	return args.Get(0).([]models.Group), args.Error(1)
+}
+
 func (m *MockGroupRepository) Update(ctx context.Context, group *models.Group) error {
 	args := m.Called(ctx, group)
 	return args.Error(0)
@@ -55,6 +63,52 @@ func (m *MockGroupRepository) GetSummariesByUserID(ctx context.Context, userID s
 	return args.Get(0).([]models.GroupSummary), args.Error(1)
 }
 
+func (m *MockGroupRepository) SearchByUserID(ctx context.Context, userID, query string, limit int) ([]models.GroupSearchResult, error) {
+	args := m.Called(ctx, userID, query, limit)
+	return args.Get(0).([]models.GroupSearchResult), args.Error(1)
+}
+
+func (m *MockGroupRepository) ListSummariesByUserID(ctx context.Context, userID string, opts models.GroupSummaryListOptions) (*models.GroupSummaryPage, error) {
+	args := m.Called(ctx, userID, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GroupSummaryPage), args.Error(1)
+}
+
+func (m *MockGroupRepository) GetCalendarTokenHash(ctx context.Context, groupID string) (string, error) {
+	args := m.Called(ctx, groupID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockGroupRepository) EnsureCalendarTokenHash(ctx context.Context, groupID, candidateHash string) (string, error) {
+	args := m.Called(ctx, groupID, candidateHash)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockGroupRepository) RevokeCalendarToken(ctx context.Context, groupID string) error {
+	args := m.Called(ctx, groupID)
+	return args.Error(0)
+}
+
+func (m *MockGroupRepository) GetReminderDays(ctx context.Context, groupID string) ([]int, error) {
+	args := m.Called(ctx, groupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockGroupRepository) SetReminderDays(ctx context.Context, groupID string, days []int) error {
+	args := m.Called(ctx, groupID, days)
+	return args.Error(0)
+}
+
+func (m *MockGroupRepository) GetDueReminders(ctx context.Context, after, until time.Time) ([]models.DueReminder, error) {
+	args := m.Called(ctx, after, until)
+	return args.Get(0).([]models.DueReminder), args.Error(1)
+}
+
 type MockMemberRepository struct {
 	mock.Mock
 }
@@ -148,11 +202,216 @@ func (m *MockBucketItemRepository) GetCompletionStats(ctx context.Context, group
 	return args.Int(0), args.Int(1), args.Error(2)
 }
 
+func (m *MockBucketItemRepository) Search(ctx context.Context, groupID, query string, limit int) ([]models.ItemSearchResult, error) {
+	args := m.Called(ctx, groupID, query, limit)
+	return args.Get(0).([]models.ItemSearchResult), args.Error(1)
+}
+
+func (m *MockBucketItemRepository) SearchByUserID(ctx context.Context, userID, query string, limit int) ([]models.ItemSearchResult, error) {
+	args := m.Called(ctx, userID, query, limit)
+	return args.Get(0).([]models.ItemSearchResult), args.Error(1)
+}
+
+func (m *MockBucketItemRepository) List(ctx context.Context, groupID string, opts models.ItemListOptions) (*models.ItemPage, error) {
+	args := m.Called(ctx, groupID, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ItemPage), args.Error(1)
+}
+
+func (m *MockBucketItemRepository) GetCompletionStatsByTag(ctx context.Context, groupID string) ([]models.TagStats, error) {
+	args := m.Called(ctx, groupID)
+	return args.Get(0).([]models.TagStats), args.Error(1)
+}
+
+func (m *MockBucketItemRepository) UpdateCompletionJournal(ctx context.Context, itemID string, journal models.CompletionJournal) error {
+	args := m.Called(ctx, itemID, journal)
+	return args.Error(0)
+}
+
+func (m *MockBucketItemRepository) SetAssignment(ctx context.Context, itemID, memberID string, assigned bool) error {
+	args := m.Called(ctx, itemID, memberID, assigned)
+	return args.Error(0)
+}
+
+func (m *MockBucketItemRepository) GetAssignedTo(ctx context.Context, memberIDs []string) ([]models.BucketListItem, error) {
+	args := m.Called(ctx, memberIDs)
+	return args.Get(0).([]models.BucketListItem), args.Error(1)
+}
+
+func (m *MockBucketItemRepository) UpdateSchedule(ctx context.Context, itemID string, schedule models.ItemSchedule) error {
+	args := m.Called(ctx, itemID, schedule)
+	return args.Error(0)
+}
+
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) Create(ctx context.Context, tag *models.Tag) error {
+	args := m.Called(ctx, tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) GetByID(ctx context.Context, id string) (*models.Tag, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) GetByGroupID(ctx context.Context, groupID string) ([]models.Tag, error) {
+	args := m.Called(ctx, groupID)
+	return args.Get(0).([]models.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) Update(ctx context.Context, tag *models.Tag) error {
+	args := m.Called(ctx, tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) SetItemTags(ctx context.Context, itemID string, tagIDs []string) error {
+	args := m.Called(ctx, itemID, tagIDs)
+	return args.Error(0)
+}
+
+type MockCommentRepository struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepository) Create(ctx context.Context, comment *models.Comment) error {
+	args := m.Called(ctx, comment)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) GetByID(ctx context.Context, id string) (*models.Comment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) GetByItemID(ctx context.Context, itemID string) ([]models.CommentThread, error) {
+	args := m.Called(ctx, itemID)
+	return args.Get(0).([]models.CommentThread), args.Error(1)
+}
+
+func (m *MockCommentRepository) Update(ctx context.Context, comment *models.Comment) error {
+	args := m.Called(ctx, comment)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) CountByGroupID(ctx context.Context, groupID string) (map[string]int, error) {
+	args := m.Called(ctx, groupID)
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+type MockReactionRepository struct {
+	mock.Mock
+}
+
+func (m *MockReactionRepository) SetVote(ctx context.Context, itemID, memberID string, voted bool) error {
+	args := m.Called(ctx, itemID, memberID, voted)
+	return args.Error(0)
+}
+
+func (m *MockReactionRepository) AddReaction(ctx context.Context, reaction *models.Reaction) error {
+	args := m.Called(ctx, reaction)
+	return args.Error(0)
+}
+
+func (m *MockReactionRepository) RemoveReaction(ctx context.Context, itemID, memberID, emoji string) error {
+	args := m.Called(ctx, itemID, memberID, emoji)
+	return args.Error(0)
+}
+
+func (m *MockReactionRepository) GetByItemID(ctx context.Context, itemID string) (*models.ItemReactions, error) {
+	args := m.Called(ctx, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ItemReactions), args.Error(1)
+}
+
+func (m *MockReactionRepository) GetByGroupID(ctx context.Context, groupID string) (map[string]models.ItemReactions, error) {
+	args := m.Called(ctx, groupID)
+	return args.Get(0).(map[string]models.ItemReactions), args.Error(1)
+}
+
+type MockAttachmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentRepository) Create(ctx context.Context, attachment *models.Attachment) error {
+	args := m.Called(ctx, attachment)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) GetByID(ctx context.Context, id string) (*models.Attachment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) GetByItemID(ctx context.Context, itemID string) ([]models.Attachment, error) {
+	args := m.Called(ctx, itemID)
+	return args.Get(0).([]models.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) PendingBlobDeletions(ctx context.Context, limit int) ([]string, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) ClearBlobDeletions(ctx context.Context, keys []string) error {
+	args := m.Called(ctx, keys)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) GetByGroupID(ctx context.Context, groupID string) (map[string][]models.Attachment, error) {
+	args := m.Called(ctx, groupID)
+	return args.Get(0).(map[string][]models.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) GetPendingProcessing(ctx context.Context, limit int) ([]models.Attachment, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]models.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) UpdateProcessing(ctx context.Context, attachment *models.Attachment) error {
+	args := m.Called(ctx, attachment)
+	return args.Error(0)
+}
+
 type MockRepositoryManager struct {
 	mock.Mock
 	groups      *MockGroupRepository
 	members     *MockMemberRepository
 	bucketItems *MockBucketItemRepository
+	tags        *MockTagRepository
+	comments    *MockCommentRepository
+	reactions   *MockReactionRepository
+	attachments *MockAttachmentRepository
 }
 
 func NewMockRepositoryManager() *MockRepositoryManager {
@@ -160,6 +419,10 @@ func NewMockRepositoryManager() *MockRepositoryManager {
 		groups:      &MockGroupRepository{},
 		members:     &MockMemberRepository{},
 		bucketItems: &MockBucketItemRepository{},
+		tags:        &MockTagRepository{},
+		comments:    &MockCommentRepository{},
+		reactions:   &MockReactionRepository{},
+		attachments: &MockAttachmentRepository{},
 	}
 }
 
@@ -175,11 +438,48 @@ func (m *MockRepositoryManager) BucketItems() repositories.BucketItemRepository
 	return m.bucketItems
 }
 
-func (m *MockRepositoryManager) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+func (m *MockRepositoryManager) Tags() repositories.TagRepository {
+	return m.tags
+}
+
+func (m *MockRepositoryManager) Comments() repositories.CommentRepository {
+	return m.comments
+}
+
+func (m *MockRepositoryManager) Reactions() repositories.ReactionRepository {
+	return m.reactions
+}
+
+func (m *MockRepositoryManager) Attachments() repositories.AttachmentRepository {
+	return m.attachments
+}
+
+func (m *MockRepositoryManager) WithTx(ctx context.Context, fn func(repos repositories.RepositoryManager) error) error {
 	args := m.Called(ctx, fn)
 	return args.Error(0)
 }
 
+func (m *MockRepositoryManager) WithTxOptions(ctx context.Context, opts repositories.TxOptions, fn func(repos repositories.RepositoryManager) error) error {
+	args := m.Called(ctx, opts, fn)
+	return args.Error(0)
+}
+
+// expectTx expects a WithTx call and runs its function against the mock
+// repositories, returning the function's error
+func (m *MockRepositoryManager) expectTx() {
+	call := m.On("WithTx", mock.Anything, mock.Anything)
+	call.Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(repositories.RepositoryManager) error)
+		call.ReturnArguments = mock.Arguments{fn(m)}
+	})
+}
+
+// expectOpenGroup expects the group a command changes to be looked up,
+// returning a group that has not locked
+func (m *MockRepositoryManager) expectOpenGroup(groupID string) {
+	m.groups.On("GetByID", mock.Anything, groupID).Return(&models.Group{ID: groupID, Name: "Test Group"}, nil)
+}
+
 // Mock client for testing
 type MockClient struct {
 	*Client
@@ -228,7 +528,7 @@ func TestEventHandler_ProcessMessage_InvalidJSON(t *testing.T) {
 
 	// Test invalid JSON
 	invalidJSON := []byte(`{"invalid": json}`)
-	eventHandler.ProcessMessage(client.Client, invalidJSON)
+	eventHandler.ProcessMessage(client.Client, websocket.TextMessage, invalidJSON)
 
 	// Should not broadcast anything for invalid JSON
 	assert.Empty(t, mockHub.broadcastedMessages)
@@ -266,7 +566,7 @@ func TestEventHandler_HandleJoinGroup_Success(t *testing.T) {
 	}
 
 	messageBytes, _ := json.Marshal(message)
-	eventHandler.ProcessMessage(client.Client, messageBytes)
+	eventHandler.ProcessMessage(client.Client, websocket.TextMessage, messageBytes)
 
 	// Verify member-joined event was broadcasted
 	assert.Len(t, mockHub.broadcastedMessages, 1)
@@ -299,7 +599,7 @@ func TestEventHandler_HandleJoinGroup_MemberNotFound(t *testing.T) {
 	}
 
 	messageBytes, _ := json.Marshal(message)
-	eventHandler.ProcessMessage(client.Client, messageBytes)
+	eventHandler.ProcessMessage(client.Client, websocket.TextMessage, messageBytes)
 
 	// Should not broadcast anything for member not found
 	assert.Empty(t, mockHub.broadcastedMessages)
@@ -324,6 +624,7 @@ func TestEventHandler_HandleAddItem_Success(t *testing.T) {
 	}
 
 	mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+	mockRepos.expectOpenGroup("test-group-id")
 	mockRepos.bucketItems.On("Create", mock.Anything, mock.AnythingOfType("*models.BucketListItem")).Return(nil)
 
 	// Create add-item message
@@ -346,7 +647,7 @@ func TestEventHandler_HandleAddItem_Success(t *testing.T) {
 	}
 
 	messageBytes, _ := json.Marshal(message)
-	eventHandler.ProcessMessage(client.Client, messageBytes)
+	eventHandler.ProcessMessage(client.Client, websocket.TextMessage, messageBytes)
 
 	// Verify item-added event was broadcasted
 	assert.Len(t, mockHub.broadcastedMessages, 1)
@@ -358,13 +659,74 @@ func TestEventHandler_HandleAddItem_Success(t *testing.T) {
 	assert.Equal(t, "Test Item", broadcastedItem.Title)
 	assert.Equal(t, "Test Description", *broadcastedItem.Description)
 	assert.Equal(t, "test-group-id", broadcastedItem.GroupID)
-	assert.Equal(t, "test-member-id", broadcastedItem.CreatedBy)
+	assert.Equal(t, stringPtr("test-member-id"), broadcastedItem.CreatedBy)
 	assert.False(t, broadcastedItem.Completed)
 
 	mockRepos.members.AssertExpectations(t)
 	mockRepos.bucketItems.AssertExpectations(t)
 }
 
+func TestEventHandler_HandleAddItem_Scheduled(t *testing.T) {
+	member := &models.Member{ID: "test-member-id", GroupID: "test-group-id", Name: "Test Member"}
+	deadline := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	group := &models.Group{ID: "test-group-id", Name: "Test Group", Deadline: &deadline}
+
+	addItem := func(eventHandler *EventHandler, client *MockClient, start time.Time) {
+		messageBytes, _ := json.Marshal(Message{
+			Type:     EventAddItem,
+			RoomID:   "test-group-id",
+			MemberID: "test-member-id",
+			Data: AddItemPayload{
+				GroupID: "test-group-id",
+				Item: models.CreateItemRequest{
+					Title:        "Fireworks",
+					MemberID:     "test-member-id",
+					ItemSchedule: models.ItemSchedule{ScheduledStart: &start},
+				},
+			},
+		})
+		eventHandler.ProcessMessage(client.Client, websocket.TextMessage, messageBytes)
+	}
+
+	t.Run("scheduled before the deadline", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockHub := &MockHub{}
+		client := NewMockClient("test-group-id", "test-member-id")
+
+		mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+		mockRepos.groups.On("GetByID", mock.Anything, "test-group-id").Return(group, nil)
+		mockRepos.bucketItems.On("Create", mock.Anything, mock.AnythingOfType("*models.BucketListItem")).Return(nil)
+
+		start := time.Date(2026, 7, 4, 18, 0, 0, 0, time.UTC)
+		addItem(NewEventHandler(mockHub, mockRepos), client, start)
+
+		require.Len(t, mockHub.broadcastedMessages, 1)
+		broadcastedItem := mockHub.broadcastedMessages[0].Data.(*models.BucketListItem)
+		require.NotNil(t, broadcastedItem.ScheduledStart)
+		assert.True(t, start.Equal(*broadcastedItem.ScheduledStart))
+	})
+
+	t.Run("scheduled after the deadline", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockHub := &MockHub{}
+		client := NewMockClient("test-group-id", "test-member-id")
+
+		mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+		mockRepos.groups.On("GetByID", mock.Anything, "test-group-id").Return(group, nil)
+
+		addItem(NewEventHandler(mockHub, mockRepos), client, deadline.AddDate(0, 0, 1))
+
+		assert.Empty(t, mockHub.broadcastedMessages)
+		require.Len(t, client.send, 1)
+		var errMsg struct {
+			Data ErrorPayload `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(<-client.send, &errMsg))
+		assert.Equal(t, "VALIDATION_ERROR", errMsg.Data.Code)
+		mockRepos.bucketItems.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
 func TestEventHandler_HandleToggleCompletion_Success(t *testing.T) {
 	mockRepos := NewMockRepositoryManager()
 	mockHub := &MockHub{}
@@ -388,7 +750,7 @@ func TestEventHandler_HandleToggleCompletion_Success(t *testing.T) {
 		Title:       "Test Item",
 		Description: stringPtr("Test Description"),
 		Completed:   false,
-		CreatedBy:   "test-member-id",
+		CreatedBy:   stringPtr("test-member-id"),
 		CreatedAt:   time.Now(),
 	}
 
@@ -400,11 +762,12 @@ func TestEventHandler_HandleToggleCompletion_Success(t *testing.T) {
 		Completed:   true,
 		CompletedBy: stringPtr("test-member-id"),
 		CompletedAt: timePtr(time.Now()),
-		CreatedBy:   "test-member-id",
+		CreatedBy:   stringPtr("test-member-id"),
 		CreatedAt:   time.Now(),
 	}
 
 	mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+	mockRepos.expectOpenGroup("test-group-id")
 	mockRepos.bucketItems.On("GetByID", mock.Anything, "test-item-id").Return(item, nil).Once()
 	mockRepos.bucketItems.On("ToggleCompletion", mock.Anything, "test-item-id", "test-member-id", true).Return(nil)
 	mockRepos.bucketItems.On("GetByID", mock.Anything, "test-item-id").Return(updatedItem, nil).Once()
@@ -425,7 +788,7 @@ func TestEventHandler_HandleToggleCompletion_Success(t *testing.T) {
 	}
 
 	messageBytes, _ := json.Marshal(message)
-	eventHandler.ProcessMessage(client.Client, messageBytes)
+	eventHandler.ProcessMessage(client.Client, websocket.TextMessage, messageBytes)
 
 	// Verify item-updated event was broadcasted
 	assert.Len(t, mockHub.broadcastedMessages, 1)
@@ -442,6 +805,53 @@ func TestEventHandler_HandleToggleCompletion_Success(t *testing.T) {
 	mockRepos.bucketItems.AssertExpectations(t)
 }
 
+func TestEventHandler_HandleToggleCompletion_WithJournal(t *testing.T) {
+	mockRepos := NewMockRepositoryManager()
+	mockHub := &MockHub{}
+	eventHandler := NewEventHandler(mockHub, mockRepos)
+
+	client := NewMockClient("test-group-id", "test-member-id")
+
+	member := &models.Member{ID: "test-member-id", GroupID: "test-group-id", Name: "Test Member"}
+	item := &models.BucketListItem{ID: "test-item-id", GroupID: "test-group-id", Title: "Skydiving", CreatedBy: stringPtr("test-member-id")}
+	rating := 5
+	journal := models.CompletionJournal{CompletionNote: stringPtr("Terrifying, then wonderful"), CompletionRating: &rating}
+	updatedItem := *item
+	updatedItem.Completed = true
+	updatedItem.CompletionJournal = journal
+
+	mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+	mockRepos.expectOpenGroup("test-group-id")
+	mockRepos.bucketItems.On("GetByID", mock.Anything, "test-item-id").Return(item, nil).Once()
+	mockRepos.bucketItems.On("ToggleCompletion", mock.Anything, "test-item-id", "test-member-id", true).Return(nil)
+	mockRepos.bucketItems.On("UpdateCompletionJournal", mock.Anything, "test-item-id", journal).Return(nil)
+	mockRepos.bucketItems.On("GetByID", mock.Anything, "test-item-id").Return(&updatedItem, nil).Once()
+	mockRepos.expectTx()
+
+	message := Message{
+		Type:     EventToggleCompletion,
+		RoomID:   "test-group-id",
+		MemberID: "test-member-id",
+		Data: ToggleCompletionPayload{
+			GroupID:           "test-group-id",
+			ItemID:            "test-item-id",
+			Completed:         true,
+			MemberID:          "test-member-id",
+			CompletionJournal: journal,
+		},
+	}
+
+	messageBytes, _ := json.Marshal(message)
+	eventHandler.ProcessMessage(client.Client, websocket.TextMessage, messageBytes)
+
+	require.Len(t, mockHub.broadcastedMessages, 1)
+	broadcastedItem := mockHub.broadcastedMessages[0].Data.(*models.BucketListItem)
+	assert.Equal(t, journal, broadcastedItem.CompletionJournal)
+
+	mockRepos.AssertExpectations(t)
+	mockRepos.bucketItems.AssertExpectations(t)
+}
+
 func TestEventHandler_HandleToggleCompletion_ItemNotFound(t *testing.T) {
 	mockRepos := NewMockRepositoryManager()
 	mockHub := &MockHub{}
@@ -476,7 +886,7 @@ func TestEventHandler_HandleToggleCompletion_ItemNotFound(t *testing.T) {
 	}
 
 	messageBytes, _ := json.Marshal(message)
-	eventHandler.ProcessMessage(client.Client, messageBytes)
+	eventHandler.ProcessMessage(client.Client, websocket.TextMessage, messageBytes)
 
 	// Should not broadcast anything for item not found
 	assert.Empty(t, mockHub.broadcastedMessages)
@@ -501,7 +911,7 @@ func TestEventHandler_ProcessMessage_RoomMismatch(t *testing.T) {
 	}
 
 	messageBytes, _ := json.Marshal(message)
-	eventHandler.ProcessMessage(client.Client, messageBytes)
+	eventHandler.ProcessMessage(client.Client, websocket.TextMessage, messageBytes)
 
 	// Should not broadcast anything for room mismatch
 	assert.Empty(t, mockHub.broadcastedMessages)
@@ -523,7 +933,7 @@ func TestEventHandler_ProcessMessage_MemberMismatch(t *testing.T) {
 	}
 
 	messageBytes, _ := json.Marshal(message)
-	eventHandler.ProcessMessage(client.Client, messageBytes)
+	eventHandler.ProcessMessage(client.Client, websocket.TextMessage, messageBytes)
 
 	// Should not broadcast anything for member mismatch
 	assert.Empty(t, mockHub.broadcastedMessages)
@@ -545,12 +955,261 @@ func TestEventHandler_ProcessMessage_UnknownEventType(t *testing.T) {
 	}
 
 	messageBytes, _ := json.Marshal(message)
-	eventHandler.ProcessMessage(client.Client, messageBytes)
+	eventHandler.ProcessMessage(client.Client, websocket.TextMessage, messageBytes)
 
 	// Should not broadcast anything for unknown event type
 	assert.Empty(t, mockHub.broadcastedMessages)
 }
 
+func TestEventHandler_HandleAddItem_AcksRequestID(t *testing.T) {
+	mockRepos := NewMockRepositoryManager()
+	mockHub := &MockHub{}
+	eventHandler := NewEventHandler(mockHub, mockRepos)
+
+	client := NewMockClient("test-group-id", "test-member-id")
+	client.protocolVersion = ProtocolVersion
+
+	member := &models.Member{
+		ID:       "test-member-id",
+		GroupID:  "test-group-id",
+		Name:     "Test Member",
+		JoinedAt: time.Now(),
+	}
+
+	mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+	mockRepos.expectOpenGroup("test-group-id")
+	mockRepos.bucketItems.On("Create", mock.Anything, mock.AnythingOfType("*models.BucketListItem")).Return(nil)
+
+	message := Message{
+		Type:      EventAddItem,
+		RoomID:    "test-group-id",
+		MemberID:  "test-member-id",
+		RequestID: "req-1",
+		Data: AddItemPayload{
+			GroupID: "test-group-id",
+			Item: models.CreateItemRequest{
+				Title:    "Test Item",
+				MemberID: "test-member-id",
+			},
+		},
+	}
+
+	messageBytes, _ := json.Marshal(message)
+	eventHandler.ProcessMessage(client.Client, websocket.TextMessage, messageBytes)
+
+	// The room still gets the broadcast
+	assert.Len(t, mockHub.broadcastedMessages, 1)
+
+	// The sender gets an ack echoing its request ID
+	require.Len(t, client.send, 1)
+	var ack struct {
+		Type      string `json:"type"`
+		RequestID string `json:"requestId"`
+		Data      struct {
+			Event string                `json:"event"`
+			Data  models.BucketListItem `json:"data"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(<-client.send, &ack))
+	assert.Equal(t, EventAck, ack.Type)
+	assert.Equal(t, "req-1", ack.RequestID)
+	assert.Equal(t, EventAddItem, ack.Data.Event)
+	assert.Equal(t, "test-item-id", ack.Data.Data.ID)
+	assert.Equal(t, "Test Item", ack.Data.Data.Title)
+
+	mockRepos.members.AssertExpectations(t)
+	mockRepos.bucketItems.AssertExpectations(t)
+}
+
+func TestEventHandler_NoAckWithoutRequestID(t *testing.T) {
+	mockRepos := NewMockRepositoryManager()
+	mockHub := &MockHub{}
+	eventHandler := NewEventHandler(mockHub, mockRepos)
+
+	client := NewMockClient("test-group-id", "test-member-id")
+
+	member := &models.Member{
+		ID:       "test-member-id",
+		GroupID:  "test-group-id",
+		Name:     "Test Member",
+		JoinedAt: time.Now(),
+	}
+
+	mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+
+	message := Message{
+		Type:     EventJoinGroup,
+		RoomID:   "test-group-id",
+		MemberID: "test-member-id",
+		Data: JoinGroupPayload{
+			GroupID:  "test-group-id",
+			MemberID: "test-member-id",
+		},
+	}
+
+	messageBytes, _ := json.Marshal(message)
+	eventHandler.ProcessMessage(client.Client, websocket.TextMessage, messageBytes)
+
+	assert.Len(t, mockHub.broadcastedMessages, 1)
+	assert.Empty(t, client.send)
+}
+
+func TestEventHandler_ErrorEchoesRequestID(t *testing.T) {
+	mockRepos := NewMockRepositoryManager()
+	mockHub := &MockHub{}
+	eventHandler := NewEventHandler(mockHub, mockRepos)
+
+	client := NewMockClient("test-group-id", "test-member-id")
+
+	mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return((*models.Member)(nil), errors.New("member not found"))
+
+	message := Message{
+		Type:      EventToggleCompletion,
+		RoomID:    "test-group-id",
+		MemberID:  "test-member-id",
+		RequestID: "req-2",
+		Data: ToggleCompletionPayload{
+			GroupID:   "test-group-id",
+			ItemID:    "test-item-id",
+			Completed: true,
+			MemberID:  "test-member-id",
+		},
+	}
+
+	messageBytes, _ := json.Marshal(message)
+	eventHandler.ProcessMessage(client.Client, websocket.TextMessage, messageBytes)
+
+	assert.Empty(t, mockHub.broadcastedMessages)
+
+	require.Len(t, client.send, 1)
+	var errMsg struct {
+		Type      string       `json:"type"`
+		RequestID string       `json:"requestId"`
+		Data      ErrorPayload `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(<-client.send, &errMsg))
+	assert.Equal(t, EventError, errMsg.Type)
+	assert.Equal(t, "req-2", errMsg.RequestID)
+	assert.Equal(t, "MEMBER_NOT_FOUND", errMsg.Data.Code)
+}
+
+func TestEventHandler_LockedGroup(t *testing.T) {
+	member := &models.Member{ID: "test-member-id", GroupID: "test-group-id", Name: "Test Member"}
+	item := &models.BucketListItem{ID: "test-item-id", GroupID: "test-group-id", Title: "Test Item"}
+	passed := time.Now().Add(-time.Hour)
+	locked := &models.Group{ID: "test-group-id", Name: "Test Group", Deadline: &passed, LockAfterDeadline: true}
+
+	send := func(eventHandler *EventHandler, client *MockClient, eventType string, data interface{}) {
+		messageBytes, _ := json.Marshal(Message{
+			Type:     eventType,
+			RoomID:   "test-group-id",
+			MemberID: "test-member-id",
+			Data:     data,
+		})
+		eventHandler.ProcessMessage(client.Client, websocket.TextMessage, messageBytes)
+	}
+	readError := func(t *testing.T, client *MockClient) ErrorPayload {
+		t.Helper()
+		require.Len(t, client.send, 1)
+		var errMsg struct {
+			Data ErrorPayload `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(<-client.send, &errMsg))
+		return errMsg.Data
+	}
+
+	t.Run("add-item", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockHub := &MockHub{}
+		client := NewMockClient("test-group-id", "test-member-id")
+		mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+		mockRepos.groups.On("GetByID", mock.Anything, "test-group-id").Return(locked, nil)
+
+		send(NewEventHandler(mockHub, mockRepos), client, EventAddItem, AddItemPayload{
+			GroupID: "test-group-id",
+			Item:    models.CreateItemRequest{Title: "Too late", MemberID: "test-member-id"},
+		})
+
+		assert.Empty(t, mockHub.broadcastedMessages)
+		assert.Equal(t, "GROUP_LOCKED", readError(t, client).Code)
+		mockRepos.bucketItems.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("toggle-completion", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockHub := &MockHub{}
+		client := NewMockClient("test-group-id", "test-member-id")
+		mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+		mockRepos.bucketItems.On("GetByID", mock.Anything, "test-item-id").Return(item, nil)
+		mockRepos.groups.On("GetByID", mock.Anything, "test-group-id").Return(locked, nil)
+
+		send(NewEventHandler(mockHub, mockRepos), client, EventToggleCompletion, ToggleCompletionPayload{
+			GroupID:   "test-group-id",
+			ItemID:    "test-item-id",
+			Completed: true,
+			MemberID:  "test-member-id",
+		})
+
+		assert.Empty(t, mockHub.broadcastedMessages)
+		assert.Equal(t, "GROUP_LOCKED", readError(t, client).Code)
+		mockRepos.bucketItems.AssertNotCalled(t, "ToggleCompletion", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("assign", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockHub := &MockHub{}
+		client := NewMockClient("test-group-id", "test-member-id")
+		client.protocolVersion = ProtocolVersion
+		mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+		mockRepos.bucketItems.On("GetByID", mock.Anything, "test-item-id").Return(item, nil)
+		mockRepos.groups.On("GetByID", mock.Anything, "test-group-id").Return(locked, nil)
+
+		send(NewEventHandler(mockHub, mockRepos), client, EventAssign, AssignPayload{
+			GroupID: "test-group-id", ItemID: "test-item-id", AssigneeID: "assignee-id", MemberID: "test-member-id",
+		})
+
+		assert.Empty(t, mockHub.broadcastedMessages)
+		assert.Equal(t, "GROUP_LOCKED", readError(t, client).Code)
+		mockRepos.bucketItems.AssertNotCalled(t, "SetAssignment", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("react", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockHub := &MockHub{}
+		client := NewMockClient("test-group-id", "test-member-id")
+		client.protocolVersion = ProtocolVersion
+		mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+		mockRepos.bucketItems.On("GetByID", mock.Anything, "test-item-id").Return(item, nil)
+		mockRepos.groups.On("GetByID", mock.Anything, "test-group-id").Return(locked, nil)
+
+		send(NewEventHandler(mockHub, mockRepos), client, EventReact, ReactPayload{
+			GroupID: "test-group-id", ItemID: "test-item-id", Emoji: "🎉", MemberID: "test-member-id",
+		})
+
+		assert.Empty(t, mockHub.broadcastedMessages)
+		assert.Equal(t, "GROUP_LOCKED", readError(t, client).Code)
+		mockRepos.reactions.AssertNotCalled(t, "AddReaction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("vote", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockHub := &MockHub{}
+		client := NewMockClient("test-group-id", "test-member-id")
+		client.protocolVersion = ProtocolVersion
+		mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+		mockRepos.bucketItems.On("GetByID", mock.Anything, "test-item-id").Return(item, nil)
+		mockRepos.groups.On("GetByID", mock.Anything, "test-group-id").Return(locked, nil)
+
+		send(NewEventHandler(mockHub, mockRepos), client, EventVote, VotePayload{
+			GroupID: "test-group-id", ItemID: "test-item-id", Voted: true, MemberID: "test-member-id",
+		})
+
+		assert.Empty(t, mockHub.broadcastedMessages)
+		assert.Equal(t, "GROUP_LOCKED", readError(t, client).Code)
+		mockRepos.reactions.AssertNotCalled(t, "SetVote", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
 // Helper functions
 func stringPtr(s string) *string {
 	return &s
@@ -558,4 +1217,340 @@ func stringPtr(s string) *string {
 
 func timePtr(t time.Time) *time.Time {
 	return &t
-}
\ No newline at end of file
+}
+func TestEventHandler_HandleSetItemTags(t *testing.T) {
+	member := &models.Member{ID: "test-member-id", GroupID: "test-group-id", Name: "Test Member"}
+	item := &models.BucketListItem{ID: "test-item-id", GroupID: "test-group-id", Title: "Test Item"}
+	tagID := uuid.New().String()
+
+	newMessage := func(tagIDs []string) []byte {
+		messageBytes, _ := json.Marshal(Message{
+			Type:      EventSetItemTags,
+			RoomID:    "test-group-id",
+			MemberID:  "test-member-id",
+			RequestID: "req-1",
+			Data: SetItemTagsPayload{
+				GroupID:  "test-group-id",
+				ItemID:   "test-item-id",
+				TagIDs:   tagIDs,
+				MemberID: "test-member-id",
+			},
+		})
+		return messageBytes
+	}
+
+	t.Run("broadcasts the updated item", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockHub := &MockHub{}
+		eventHandler := NewEventHandler(mockHub, mockRepos)
+		client := NewMockClient("test-group-id", "test-member-id")
+		client.protocolVersion = ProtocolVersion
+
+		tagged := *item
+		tagged.TagIDs = []string{tagID}
+		mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+		mockRepos.expectOpenGroup("test-group-id")
+		mockRepos.bucketItems.On("GetByID", mock.Anything, "test-item-id").Return(item, nil).Once()
+		mockRepos.bucketItems.On("GetByID", mock.Anything, "test-item-id").Return(&tagged, nil).Once()
+		mockRepos.tags.On("SetItemTags", mock.Anything, "test-item-id", []string{tagID}).Return(nil)
+		mockRepos.expectTx()
+
+		eventHandler.ProcessMessage(client.Client, websocket.TextMessage, newMessage([]string{tagID}))
+
+		require.Len(t, mockHub.broadcastedMessages, 1)
+		assert.Equal(t, EventItemUpdated, mockHub.broadcastedMessages[0].MessageType)
+		broadcastedItem := mockHub.broadcastedMessages[0].Data.(*models.BucketListItem)
+		assert.Equal(t, []string{tagID}, broadcastedItem.TagIDs)
+
+		require.Len(t, client.send, 1)
+		var ack struct {
+			Type string     `json:"type"`
+			Data AckPayload `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(<-client.send, &ack))
+		assert.Equal(t, EventAck, ack.Type)
+		assert.Equal(t, EventSetItemTags, ack.Data.Event)
+
+		mockRepos.tags.AssertExpectations(t)
+	})
+
+	t.Run("rejects tags of another group", func(t *testing.T) {
+		mockRepos := NewMockRepositoryManager()
+		mockHub := &MockHub{}
+		eventHandler := NewEventHandler(mockHub, mockRepos)
+		client := NewMockClient("test-group-id", "test-member-id")
+		client.protocolVersion = ProtocolVersion
+
+		mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+		mockRepos.expectOpenGroup("test-group-id")
+		mockRepos.bucketItems.On("GetByID", mock.Anything, "test-item-id").Return(item, nil)
+		mockRepos.tags.On("SetItemTags", mock.Anything, "test-item-id", []string{tagID}).
+			Return(fmt.Errorf("failed to set item tags: %w", repositories.ErrTagNotFound))
+		mockRepos.expectTx()
+
+		eventHandler.ProcessMessage(client.Client, websocket.TextMessage, newMessage([]string{tagID}))
+
+		assert.Empty(t, mockHub.broadcastedMessages)
+		require.Len(t, client.send, 1)
+		var errMsg struct {
+			Data ErrorPayload `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(<-client.send, &errMsg))
+		assert.Equal(t, "TAG_NOT_FOUND", errMsg.Data.Code)
+	})
+
+	t.Run("requires protocol version 3", func(t *testing.T) {
+		mockHub := &MockHub{}
+		eventHandler := NewEventHandler(mockHub, NewMockRepositoryManager())
+		client := NewMockClient("test-group-id", "test-member-id")
+		client.protocolVersion = 2
+
+		eventHandler.ProcessMessage(client.Client, websocket.TextMessage, newMessage([]string{tagID}))
+
+		assert.Empty(t, mockHub.broadcastedMessages)
+		require.Len(t, client.send, 1)
+		var errMsg struct {
+			Data ErrorPayload `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(<-client.send, &errMsg))
+		assert.Equal(t, "UNSUPPORTED_EVENT", errMsg.Data.Code)
+	})
+}
+
+func TestEventHandler_HandleReactionsAndVotes(t *testing.T) {
+	member := &models.Member{ID: "test-member-id", GroupID: "test-group-id", Name: "Test Member"}
+	item := &models.BucketListItem{ID: "test-item-id", GroupID: "test-group-id", Title: "Test Item"}
+
+	newMessage := func(eventType string, data interface{}) []byte {
+		messageBytes, _ := json.Marshal(Message{
+			Type:      eventType,
+			RoomID:    "test-group-id",
+			MemberID:  "test-member-id",
+			RequestID: "req-1",
+			Data:      data,
+		})
+		return messageBytes
+	}
+	newHandler := func() (*MockRepositoryManager, *MockHub, *EventHandler, *MockClient) {
+		mockRepos := NewMockRepositoryManager()
+		mockHub := &MockHub{}
+		client := NewMockClient("test-group-id", "test-member-id")
+		client.protocolVersion = ProtocolVersion
+		return mockRepos, mockHub, NewEventHandler(mockHub, mockRepos), client
+	}
+	readError := func(t *testing.T, client *MockClient) ErrorPayload {
+		t.Helper()
+		require.Len(t, client.send, 1)
+		var errMsg struct {
+			Data ErrorPayload `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(<-client.send, &errMsg))
+		return errMsg.Data
+	}
+
+	t.Run("vote broadcasts the item's reactions", func(t *testing.T) {
+		mockRepos, mockHub, eventHandler, client := newHandler()
+
+		aggregate := &models.ItemReactions{ItemID: "test-item-id", Votes: 1, VoterIDs: []string{"test-member-id"}}
+		mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+		mockRepos.bucketItems.On("GetByID", mock.Anything, "test-item-id").Return(item, nil)
+		mockRepos.expectOpenGroup("test-group-id")
+		mockRepos.reactions.On("SetVote", mock.Anything, "test-item-id", "test-member-id", true).Return(nil)
+		mockRepos.reactions.On("GetByItemID", mock.Anything, "test-item-id").Return(aggregate, nil)
+
+		eventHandler.ProcessMessage(client.Client, websocket.TextMessage, newMessage(EventVote, VotePayload{
+			GroupID: "test-group-id", ItemID: "test-item-id", Voted: true, MemberID: "test-member-id",
+		}))
+
+		require.Len(t, mockHub.broadcastedMessages, 1)
+		assert.Equal(t, "test-group-id", mockHub.broadcastedMessages[0].RoomID)
+		assert.Equal(t, EventReactionsUpdated, mockHub.broadcastedMessages[0].MessageType)
+		assert.Equal(t, aggregate, mockHub.broadcastedMessages[0].Data)
+
+		require.Len(t, client.send, 1)
+		var ack struct {
+			Type string     `json:"type"`
+			Data AckPayload `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(<-client.send, &ack))
+		assert.Equal(t, EventAck, ack.Type)
+		assert.Equal(t, EventVote, ack.Data.Event)
+
+		mockRepos.reactions.AssertExpectations(t)
+	})
+
+	t.Run("react adds and removes emoji", func(t *testing.T) {
+		mockRepos, mockHub, eventHandler, client := newHandler()
+
+		mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+		mockRepos.bucketItems.On("GetByID", mock.Anything, "test-item-id").Return(item, nil)
+		mockRepos.expectOpenGroup("test-group-id")
+		mockRepos.reactions.On("AddReaction", mock.Anything, mock.MatchedBy(func(r *models.Reaction) bool {
+			return r.ItemID == "test-item-id" && r.MemberID == "test-member-id" && r.Emoji == "🎉"
+		})).Return(nil)
+		mockRepos.reactions.On("RemoveReaction", mock.Anything, "test-item-id", "test-member-id", "🎉").Return(nil)
+		mockRepos.reactions.On("GetByItemID", mock.Anything, "test-item-id").
+			Return(&models.ItemReactions{ItemID: "test-item-id"}, nil)
+
+		eventHandler.ProcessMessage(client.Client, websocket.TextMessage, newMessage(EventReact, ReactPayload{
+			GroupID: "test-group-id", ItemID: "test-item-id", Emoji: " 🎉 ", MemberID: "test-member-id",
+		}))
+		eventHandler.ProcessMessage(client.Client, websocket.TextMessage, newMessage(EventReact, ReactPayload{
+			GroupID: "test-group-id", ItemID: "test-item-id", Emoji: "🎉", Remove: true, MemberID: "test-member-id",
+		}))
+
+		require.Len(t, mockHub.broadcastedMessages, 2)
+		assert.Equal(t, EventReactionsUpdated, mockHub.broadcastedMessages[1].MessageType)
+		mockRepos.reactions.AssertExpectations(t)
+	})
+
+	t.Run("rejects text reactions", func(t *testing.T) {
+		_, mockHub, eventHandler, client := newHandler()
+
+		eventHandler.ProcessMessage(client.Client, websocket.TextMessage, newMessage(EventReact, ReactPayload{
+			GroupID: "test-group-id", ItemID: "test-item-id", Emoji: "yes", MemberID: "test-member-id",
+		}))
+
+		assert.Empty(t, mockHub.broadcastedMessages)
+		assert.Equal(t, "VALIDATION_ERROR", readError(t, client).Code)
+	})
+
+	t.Run("rejects items of another group", func(t *testing.T) {
+		mockRepos, mockHub, eventHandler, client := newHandler()
+
+		otherItem := &models.BucketListItem{ID: "test-item-id", GroupID: "other-group-id", Title: "Other"}
+		mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+		mockRepos.bucketItems.On("GetByID", mock.Anything, "test-item-id").Return(otherItem, nil)
+
+		eventHandler.ProcessMessage(client.Client, websocket.TextMessage, newMessage(EventVote, VotePayload{
+			GroupID: "test-group-id", ItemID: "test-item-id", Voted: true, MemberID: "test-member-id",
+		}))
+
+		assert.Empty(t, mockHub.broadcastedMessages)
+		assert.Equal(t, "ITEM_GROUP_MISMATCH", readError(t, client).Code)
+		mockRepos.reactions.AssertNotCalled(t, "SetVote", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("requires protocol version 5", func(t *testing.T) {
+		_, mockHub, eventHandler, client := newHandler()
+		client.protocolVersion = 4
+
+		eventHandler.ProcessMessage(client.Client, websocket.TextMessage, newMessage(EventVote, VotePayload{
+			GroupID: "test-group-id", ItemID: "test-item-id", Voted: true, MemberID: "test-member-id",
+		}))
+
+		assert.Empty(t, mockHub.broadcastedMessages)
+		assert.Equal(t, "UNSUPPORTED_EVENT", readError(t, client).Code)
+	})
+}
+
+func TestEventHandler_HandleAssignment(t *testing.T) {
+	member := &models.Member{ID: "test-member-id", GroupID: "test-group-id", Name: "Test Member"}
+	item := &models.BucketListItem{ID: "test-item-id", GroupID: "test-group-id", Title: "Test Item"}
+
+	newMessage := func(eventType string, data interface{}) []byte {
+		messageBytes, _ := json.Marshal(Message{
+			Type:      eventType,
+			RoomID:    "test-group-id",
+			MemberID:  "test-member-id",
+			RequestID: "req-1",
+			Data:      data,
+		})
+		return messageBytes
+	}
+	newHandler := func() (*MockRepositoryManager, *MockHub, *EventHandler, *MockClient) {
+		mockRepos := NewMockRepositoryManager()
+		mockHub := &MockHub{}
+		client := NewMockClient("test-group-id", "test-member-id")
+		client.protocolVersion = ProtocolVersion
+		return mockRepos, mockHub, NewEventHandler(mockHub, mockRepos), client
+	}
+	readError := func(t *testing.T, client *MockClient) ErrorPayload {
+		t.Helper()
+		require.Len(t, client.send, 1)
+		var errMsg struct {
+			Data ErrorPayload `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(<-client.send, &errMsg))
+		return errMsg.Data
+	}
+	payload := AssignPayload{
+		GroupID: "test-group-id", ItemID: "test-item-id", AssigneeID: "assignee-id", MemberID: "test-member-id",
+	}
+
+	t.Run("assign broadcasts item-assigned", func(t *testing.T) {
+		mockRepos, mockHub, eventHandler, client := newHandler()
+
+		assigned := *item
+		assigned.AssigneeIDs = []string{"assignee-id"}
+		mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+		mockRepos.expectOpenGroup("test-group-id")
+		mockRepos.bucketItems.On("GetByID", mock.Anything, "test-item-id").Return(item, nil).Once()
+		mockRepos.bucketItems.On("SetAssignment", mock.Anything, "test-item-id", "assignee-id", true).Return(nil)
+		mockRepos.bucketItems.On("GetByID", mock.Anything, "test-item-id").Return(&assigned, nil).Once()
+		mockRepos.expectTx()
+
+		eventHandler.ProcessMessage(client.Client, websocket.TextMessage, newMessage(EventAssign, payload))
+
+		require.Len(t, mockHub.broadcastedMessages, 1)
+		assert.Equal(t, "test-group-id", mockHub.broadcastedMessages[0].RoomID)
+		assert.Equal(t, EventItemAssigned, mockHub.broadcastedMessages[0].MessageType)
+		assert.Equal(t, ItemAssignedPayload{
+			Item: assigned, AssigneeID: "assignee-id", Assigned: true, MemberID: "test-member-id",
+		}, mockHub.broadcastedMessages[0].Data)
+
+		require.Len(t, client.send, 1)
+		var ack struct {
+			Type string     `json:"type"`
+			Data AckPayload `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(<-client.send, &ack))
+		assert.Equal(t, EventAck, ack.Type)
+		assert.Equal(t, EventAssign, ack.Data.Event)
+
+		mockRepos.bucketItems.AssertExpectations(t)
+	})
+
+	t.Run("unassign", func(t *testing.T) {
+		mockRepos, mockHub, eventHandler, client := newHandler()
+
+		mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+		mockRepos.expectOpenGroup("test-group-id")
+		mockRepos.bucketItems.On("GetByID", mock.Anything, "test-item-id").Return(item, nil)
+		mockRepos.bucketItems.On("SetAssignment", mock.Anything, "test-item-id", "assignee-id", false).Return(nil)
+		mockRepos.expectTx()
+
+		eventHandler.ProcessMessage(client.Client, websocket.TextMessage, newMessage(EventUnassign, payload))
+
+		require.Len(t, mockHub.broadcastedMessages, 1)
+		broadcast := mockHub.broadcastedMessages[0].Data.(ItemAssignedPayload)
+		assert.False(t, broadcast.Assigned)
+		mockRepos.bucketItems.AssertExpectations(t)
+	})
+
+	t.Run("rejects assignees outside the group", func(t *testing.T) {
+		mockRepos, mockHub, eventHandler, client := newHandler()
+
+		mockRepos.members.On("GetByID", mock.Anything, "test-member-id").Return(member, nil)
+		mockRepos.expectOpenGroup("test-group-id")
+		mockRepos.bucketItems.On("GetByID", mock.Anything, "test-item-id").Return(item, nil)
+		mockRepos.bucketItems.On("SetAssignment", mock.Anything, "test-item-id", "assignee-id", true).
+			Return(fmt.Errorf("failed to assign item: %w", repositories.ErrMemberNotFound))
+		mockRepos.expectTx()
+
+		eventHandler.ProcessMessage(client.Client, websocket.TextMessage, newMessage(EventAssign, payload))
+
+		assert.Empty(t, mockHub.broadcastedMessages)
+		assert.Equal(t, "UNKNOWN_ASSIGNEE", readError(t, client).Code)
+	})
+
+	t.Run("requires protocol version 6", func(t *testing.T) {
+		_, mockHub, eventHandler, client := newHandler()
+		client.protocolVersion = 5
+
+		eventHandler.ProcessMessage(client.Client, websocket.TextMessage, newMessage(EventAssign, payload))
+
+		assert.Empty(t, mockHub.broadcastedMessages)
+		assert.Equal(t, "UNSUPPORTED_EVENT", readError(t, client).Code)
+	})
+}
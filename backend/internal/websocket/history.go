@@ -0,0 +1,79 @@
+package websocket
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// roomHistory keeps the most recent broadcasts of a room so that event
+// stream subscribers can resume after a reconnect
+type roomHistory struct {
+	// Sequence number given to the next broadcast
+	nextSeq uint64
+
+	// Most recent broadcasts, oldest first
+	entries []historyEntry
+
+	// Time of the last broadcast, used to expire idle rooms
+	lastEvent time.Time
+}
+
+type historyEntry struct {
+	seq     uint64
+	message []byte
+}
+
+func newRoomHistory() *roomHistory {
+	return &roomHistory{nextSeq: 1}
+}
+
+// append records a broadcast, dropping the oldest entries beyond limit
+func (rh *roomHistory) append(seq uint64, message []byte, limit int) {
+	rh.entries = append(rh.entries, historyEntry{seq: seq, message: message})
+	if len(rh.entries) > limit {
+		rh.entries = rh.entries[len(rh.entries)-limit:]
+	}
+	rh.lastEvent = time.Now()
+}
+
+// since returns the broadcasts after seq. ok is false when events after seq
+// have already been dropped and the caller cannot be caught up.
+func (rh *roomHistory) since(seq uint64) (messages [][]byte, ok bool) {
+	if seq >= rh.nextSeq {
+		return nil, false
+	}
+	if len(rh.entries) > 0 && rh.entries[0].seq > seq+1 {
+		return nil, false
+	}
+	if len(rh.entries) == 0 && seq+1 < rh.nextSeq {
+		return nil, false
+	}
+
+	for _, entry := range rh.entries {
+		if entry.seq > seq {
+			messages = append(messages, entry.message)
+		}
+	}
+	return messages, true
+}
+
+// formatEventID builds the ID of a broadcast. The hub epoch is included so
+// IDs issued before a restart are never mistaken for current ones.
+func formatEventID(epoch string, seq uint64) string {
+	return fmt.Sprintf("%s-%d", epoch, seq)
+}
+
+// parseEventID splits an event ID into its epoch and sequence number
+func parseEventID(id string) (epoch string, seq uint64, err error) {
+	i := strings.LastIndex(id, "-")
+	if i <= 0 {
+		return "", 0, fmt.Errorf("malformed event ID: %q", id)
+	}
+	seq, err = strconv.ParseUint(id[i+1:], 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("malformed event ID: %q", id)
+	}
+	return id[:i], seq, nil
+}
@@ -3,7 +3,11 @@ package websocket
 import (
 	"encoding/json"
 	"log"
+	"strconv"
 	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
 )
 
 // Hub maintains the set of active clients and broadcasts messages to the clients.
@@ -11,8 +15,8 @@ type Hub struct {
 	// Registered clients grouped by room (group ID)
 	rooms map[string]map[*Client]bool
 
-	// Inbound messages from the clients
-	broadcast chan []byte
+	// Messages to broadcast to a room
+	broadcast chan Message
 
 	// Register requests from the clients
 	register chan *Client
@@ -22,20 +26,43 @@ type Hub struct {
 
 	// Mutex to protect concurrent access to rooms
 	mutex sync.RWMutex
+
+	// Connection limits and timings for clients of this hub
+	config *Config
+
+	// Upgrader built from config
+	upgrader *websocket.Upgrader
+
+	// Recent broadcasts per room for resuming event streams, and the epoch
+	// that makes this hub's event IDs distinct from a previous process's
+	history map[string]*roomHistory
+	epoch   string
 }
 
-// NewHub creates a new WebSocket hub
+// NewHub creates a new WebSocket hub with the default configuration
 func NewHub() *Hub {
+	return NewHubWithConfig(DefaultConfig())
+}
+
+// NewHubWithConfig creates a new WebSocket hub using the given configuration
+func NewHubWithConfig(config *Config) *Hub {
 	return &Hub{
 		rooms:      make(map[string]map[*Client]bool),
-		broadcast:  make(chan []byte),
+		broadcast:  make(chan Message),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
+		config:     config,
+		upgrader:   newUpgrader(config),
+		history:    make(map[string]*roomHistory),
+		epoch:      strconv.FormatInt(time.Now().UnixNano(), 36),
 	}
 }
 
 // Run starts the hub and handles client registration, unregistration, and broadcasting
 func (h *Hub) Run() {
+	pruneTicker := time.NewTicker(time.Minute)
+	defer pruneTicker.Stop()
+
 	for {
 		select {
 		case client := <-h.register:
@@ -46,6 +73,9 @@ func (h *Hub) Run() {
 
 		case message := <-h.broadcast:
 			h.broadcastMessage(message)
+
+		case <-pruneTicker.C:
+			h.pruneHistory()
 		}
 	}
 }
@@ -62,6 +92,62 @@ func (h *Hub) registerClient(client *Client) {
 
 	log.Printf("Client registered to room %s. Room now has %d clients", 
 		client.roomID, len(h.rooms[client.roomID]))
+
+	if client.resumeFrom != "" {
+		h.replayHistory(client)
+	}
+}
+
+// replayHistory queues the broadcasts a resuming client missed. Registration
+// and broadcasts both run on the hub goroutine, so nothing can slip in
+// between the replay and the client's first live message.
+func (h *Hub) replayHistory(client *Client) {
+	messages, ok := h.missedMessages(client.roomID, client.resumeFrom)
+	if !ok {
+		messages = nil
+		resync, err := json.Marshal(Message{Type: EventResync, RoomID: client.roomID})
+		if err == nil {
+			messages = append(messages, resync)
+		}
+		log.Printf("Cannot resume room %s from event %s, asking client to resync", client.roomID, client.resumeFrom)
+	}
+
+	for _, message := range messages {
+		select {
+		case client.send <- message:
+		default:
+			log.Printf("Replay for room %s exceeds client queue, dropping remaining events", client.roomID)
+			return
+		}
+	}
+}
+
+// missedMessages returns the broadcasts in a room after lastEventID
+func (h *Hub) missedMessages(roomID, lastEventID string) ([][]byte, bool) {
+	epoch, seq, err := parseEventID(lastEventID)
+	if err != nil || epoch != h.epoch {
+		return nil, false
+	}
+
+	history, exists := h.history[roomID]
+	if !exists {
+		return nil, false
+	}
+	return history.since(seq)
+}
+
+// pruneHistory forgets the history of rooms that have no clients and have
+// been quiet for longer than the configured retention
+func (h *Hub) pruneHistory() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	cutoff := time.Now().Add(-h.config.HistoryRetention)
+	for roomID, history := range h.history {
+		if _, active := h.rooms[roomID]; !active && history.lastEvent.Before(cutoff) {
+			delete(h.history, roomID)
+		}
+	}
 }
 
 // unregisterClient removes a client from its room and closes the connection
@@ -86,27 +172,58 @@ func (h *Hub) unregisterClient(client *Client) {
 	}
 }
 
-// broadcastMessage sends a message to all clients in the appropriate room
-func (h *Hub) broadcastMessage(message []byte) {
-	var msg Message
-	if err := json.Unmarshal(message, &msg); err != nil {
-		log.Printf("Error unmarshaling broadcast message: %v", err)
+// broadcastMessage assigns the next event ID of the room to a message,
+// records it in the room history and sends it to all clients in the room
+func (h *Hub) broadcastMessage(msg Message) {
+	h.mutex.Lock()
+	history, exists := h.history[msg.RoomID]
+	if !exists {
+		history = newRoomHistory()
+		h.history[msg.RoomID] = history
+	}
+	seq := history.nextSeq
+	history.nextSeq++
+	msg.EventID = formatEventID(h.epoch, seq)
+
+	// History is kept as JSON, the encoding event streams use
+	message, err := JSONCodec.Marshal(msg)
+	if err != nil {
+		h.mutex.Unlock()
+		log.Printf("Error marshaling message: %v", err)
 		return
 	}
+	history.append(seq, message, h.config.HistorySize)
 
-	h.mutex.RLock()
 	clients, exists := h.rooms[msg.RoomID]
-	h.mutex.RUnlock()
+	h.mutex.Unlock()
 
 	if !exists {
 		log.Printf("No room found for ID: %s", msg.RoomID)
 		return
 	}
 
-	// Broadcast to all clients in the room
+	// Encode the message once per encoding in use, not once per client
+	encoded := map[string][]byte{SubprotocolJSON: message}
+
+	// Broadcast to all clients in the room whose protocol version has the event
 	for client := range clients {
+		if !client.acceptsEvent(msg.Type) {
+			continue
+		}
+
+		clientCodec := client.messageCodec()
+		clientMessage, ok := encoded[clientCodec.Name()]
+		if !ok {
+			clientMessage, err = clientCodec.Marshal(msg)
+			if err != nil {
+				log.Printf("Error encoding message as %s: %v", clientCodec.Name(), err)
+				continue
+			}
+			encoded[clientCodec.Name()] = clientMessage
+		}
+
 		select {
-		case client.send <- message:
+		case client.send <- clientMessage:
 		default:
 			// Client's send channel is full, close it and remove from room
 			h.unregisterClient(client)
@@ -118,19 +235,16 @@ func (h *Hub) broadcastMessage(message []byte) {
 
 // BroadcastToRoom sends a message to all clients in a specific room
 func (h *Hub) BroadcastToRoom(roomID string, messageType string, data interface{}) {
-	message := Message{
+	h.broadcast <- Message{
 		Type:   messageType,
 		RoomID: roomID,
 		Data:   data,
 	}
+}
 
-	messageBytes, err := json.Marshal(message)
-	if err != nil {
-		log.Printf("Error marshaling message: %v", err)
-		return
-	}
-
-	h.broadcast <- messageBytes
+// Unsubscribe removes a client from its room
+func (h *Hub) Unsubscribe(client *Client) {
+	h.unregister <- client
 }
 
 // GetRoomClientCount returns the number of clients in a specific room
@@ -0,0 +1,257 @@
+package websocket
+
+import (
+	"fmt"
+	"log"
+
+	"collaborative-bucket-list/internal/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// Protocol versions understood by the server. Version 1 is the original
+// unversioned protocol; clients that never send hello are treated as v1.
+// Version 2 added hello, acks and resync, version 3 set-item-tags,
+// version 4 the comment events, version 5 reactions and votes, version 6
+// item assignment and version 7 the deadline events.
+const (
+	ProtocolVersion       = 7
+	MinProtocolVersion    = 1
+	LegacyProtocolVersion = 1
+)
+
+// Protocol features a client can ask for in its hello
+const (
+	// FeatureRequestAck answers commands carrying a requestId with an ack or error
+	FeatureRequestAck = "request-ack"
+)
+
+// serverFeatures lists every feature the server supports along with the
+// protocol version that introduced it
+var serverFeatures = []struct {
+	Name  string
+	Since int
+}{
+	{FeatureRequestAck, 2},
+}
+
+// HelloPayload is sent by the client to declare its protocol version and
+// the features it would like to use
+type HelloPayload struct {
+	ProtocolVersion int      `json:"protocolVersion"`
+	Features        []string `json:"features,omitempty"`
+	Client          string   `json:"client,omitempty"`
+}
+
+// WelcomePayload is the server's reply to hello. ProtocolVersion and
+// Features are what the connection will use from now on; the Supported
+// fields describe everything the server could offer.
+type WelcomePayload struct {
+	ProtocolVersion    int      `json:"protocolVersion"`
+	MinProtocolVersion int      `json:"minProtocolVersion"`
+	MaxProtocolVersion int      `json:"maxProtocolVersion"`
+	Downgraded         bool     `json:"downgraded"`
+	Features           []string `json:"features"`
+	SupportedFeatures  []string `json:"supportedFeatures"`
+	Events             []string `json:"events"`
+	Encoding           string   `json:"encoding"`
+}
+
+// Event directions
+const (
+	DirectionClientToServer = "client-to-server"
+	DirectionServerToClient = "server-to-client"
+)
+
+// EventDescriptor describes one event of the protocol
+type EventDescriptor struct {
+	Type      string      `json:"type"`
+	Direction string      `json:"direction"`
+	Since     int         `json:"since"`
+	Payload   interface{} `json:"-"`
+}
+
+// protocolEvents is the registry of every event in the protocol. Each entry's
+// Payload is a zero value of the type carried in Message.Data and is used to
+// publish its schema.
+var protocolEvents = []EventDescriptor{
+	{Type: EventHello, Direction: DirectionClientToServer, Since: 2, Payload: HelloPayload{}},
+	{Type: EventJoinGroup, Direction: DirectionClientToServer, Since: 1, Payload: JoinGroupPayload{}},
+	{Type: EventAddItem, Direction: DirectionClientToServer, Since: 1, Payload: AddItemPayload{}},
+	{Type: EventToggleCompletion, Direction: DirectionClientToServer, Since: 1, Payload: ToggleCompletionPayload{}},
+	{Type: EventSetItemTags, Direction: DirectionClientToServer, Since: 3, Payload: SetItemTagsPayload{}},
+	{Type: EventReact, Direction: DirectionClientToServer, Since: 5, Payload: ReactPayload{}},
+	{Type: EventVote, Direction: DirectionClientToServer, Since: 5, Payload: VotePayload{}},
+	{Type: EventAssign, Direction: DirectionClientToServer, Since: 6, Payload: AssignPayload{}},
+	{Type: EventUnassign, Direction: DirectionClientToServer, Since: 6, Payload: AssignPayload{}},
+
+	{Type: EventWelcome, Direction: DirectionServerToClient, Since: 2, Payload: WelcomePayload{}},
+	{Type: EventMemberJoined, Direction: DirectionServerToClient, Since: 1, Payload: models.Member{}},
+	{Type: EventItemAdded, Direction: DirectionServerToClient, Since: 1, Payload: models.BucketListItem{}},
+	{Type: EventItemUpdated, Direction: DirectionServerToClient, Since: 1, Payload: models.BucketListItem{}},
+	{Type: EventCommentAdded, Direction: DirectionServerToClient, Since: 4, Payload: models.Comment{}},
+	{Type: EventCommentEdited, Direction: DirectionServerToClient, Since: 4, Payload: models.Comment{}},
+	{Type: EventCommentDeleted, Direction: DirectionServerToClient, Since: 4, Payload: CommentDeletedPayload{}},
+	{Type: EventReactionsUpdated, Direction: DirectionServerToClient, Since: 5, Payload: models.ItemReactions{}},
+	{Type: EventItemAssigned, Direction: DirectionServerToClient, Since: 6, Payload: ItemAssignedPayload{}},
+	{Type: EventDeadlineApproaching, Direction: DirectionServerToClient, Since: 7, Payload: DeadlinePayload{}},
+	{Type: EventDeadlinePassed, Direction: DirectionServerToClient, Since: 7, Payload: DeadlinePayload{}},
+	{Type: EventAck, Direction: DirectionServerToClient, Since: 2, Payload: AckPayload{}},
+	{Type: EventResync, Direction: DirectionServerToClient, Since: 2, Payload: struct{}{}},
+	{Type: EventError, Direction: DirectionServerToClient, Since: 1, Payload: ErrorPayload{}},
+}
+
+// Negotiation is the outcome of a hello handshake
+type Negotiation struct {
+	ProtocolVersion int
+	Features        []string
+	Downgraded      bool
+}
+
+// Negotiate picks the protocol version and features for a client hello.
+// Clients newer than the server are downgraded to the server's version;
+// clients older than MinProtocolVersion are rejected.
+func Negotiate(hello HelloPayload) (*Negotiation, error) {
+	if hello.ProtocolVersion < MinProtocolVersion {
+		return nil, fmt.Errorf("protocol version %d is not supported, minimum is %d",
+			hello.ProtocolVersion, MinProtocolVersion)
+	}
+
+	negotiation := &Negotiation{
+		ProtocolVersion: hello.ProtocolVersion,
+		Features:        []string{},
+	}
+	if negotiation.ProtocolVersion > ProtocolVersion {
+		negotiation.ProtocolVersion = ProtocolVersion
+		negotiation.Downgraded = true
+	}
+
+	requested := make(map[string]bool, len(hello.Features))
+	for _, feature := range hello.Features {
+		requested[feature] = true
+	}
+	for _, feature := range featuresForVersion(negotiation.ProtocolVersion) {
+		if requested[feature] {
+			negotiation.Features = append(negotiation.Features, feature)
+		}
+	}
+
+	return negotiation, nil
+}
+
+// featuresForVersion returns the features available at a protocol version
+func featuresForVersion(version int) []string {
+	features := make([]string, 0, len(serverFeatures))
+	for _, feature := range serverFeatures {
+		if feature.Since <= version {
+			features = append(features, feature.Name)
+		}
+	}
+	return features
+}
+
+// eventsForVersion returns the event types available at a protocol version
+func eventsForVersion(version int) []string {
+	events := make([]string, 0, len(protocolEvents))
+	for _, event := range protocolEvents {
+		if event.Since <= version {
+			events = append(events, event.Type)
+		}
+	}
+	return events
+}
+
+// isEventSupported reports whether an event exists at a protocol version
+func isEventSupported(eventType string, version int) bool {
+	for _, event := range protocolEvents {
+		if event.Type == eventType {
+			return event.Since <= version
+		}
+	}
+	return false
+}
+
+// ProtocolDescription is the machine-readable description of the protocol
+// published at GET /api/ws/protocol
+type ProtocolDescription struct {
+	ProtocolVersion    int                    `json:"protocolVersion"`
+	MinProtocolVersion int                    `json:"minProtocolVersion"`
+	Features           []string               `json:"features"`
+	Encodings          []string               `json:"encodings"`
+	Envelope           map[string]interface{} `json:"envelope"`
+	Events             []EventSchema          `json:"events"`
+}
+
+// EventSchema pairs an event with the JSON Schema of its payload
+type EventSchema struct {
+	EventDescriptor
+	Schema map[string]interface{} `json:"schema"`
+}
+
+// DescribeProtocol returns the protocol description with a JSON Schema for
+// the message envelope and for every event payload
+func DescribeProtocol() ProtocolDescription {
+	events := make([]EventSchema, 0, len(protocolEvents))
+	for _, event := range protocolEvents {
+		events = append(events, EventSchema{
+			EventDescriptor: event,
+			Schema:          JSONSchema(event.Payload),
+		})
+	}
+
+	return ProtocolDescription{
+		ProtocolVersion:    ProtocolVersion,
+		MinProtocolVersion: MinProtocolVersion,
+		Features:           featuresForVersion(ProtocolVersion),
+		Encodings:          supportedSubprotocols,
+		Envelope:           JSONSchema(Message{}),
+		Events:             events,
+	}
+}
+
+// handleHello negotiates the protocol for a client and replies with welcome.
+// Incompatible clients get an error and are disconnected.
+func (eh *EventHandler) handleHello(client *Client, requestID string, data interface{}) {
+	if client.negotiated {
+		eh.sendError(client, requestID, "ALREADY_NEGOTIATED", "Protocol has already been negotiated", "")
+		return
+	}
+
+	var payload HelloPayload
+	if err := eh.parsePayload(data, &payload); err != nil {
+		eh.sendError(client, requestID, "INVALID_PAYLOAD", "Invalid hello payload", err.Error())
+		return
+	}
+
+	negotiation, err := Negotiate(payload)
+	if err != nil {
+		log.Printf("Rejecting client in room %s: %v", client.roomID, err)
+		eh.sendError(client, requestID, "PROTOCOL_VERSION_UNSUPPORTED", "Protocol version is not supported", err.Error())
+		client.closeWith(websocket.ClosePolicyViolation, "unsupported protocol version")
+		return
+	}
+
+	client.setNegotiation(negotiation)
+
+	welcome := WelcomePayload{
+		ProtocolVersion:    negotiation.ProtocolVersion,
+		MinProtocolVersion: MinProtocolVersion,
+		MaxProtocolVersion: ProtocolVersion,
+		Downgraded:         negotiation.Downgraded,
+		Features:           negotiation.Features,
+		SupportedFeatures:  featuresForVersion(ProtocolVersion),
+		Events:             eventsForVersion(negotiation.ProtocolVersion),
+		Encoding:           client.messageCodec().Name(),
+	}
+
+	eh.sendToClient(client, Message{
+		Type:      EventWelcome,
+		RoomID:    client.roomID,
+		MemberID:  client.memberID,
+		RequestID: requestID,
+		Data:      welcome,
+	})
+
+	log.Printf("Client in room %s negotiated protocol v%d (requested v%d, client %q)",
+		client.roomID, negotiation.ProtocolVersion, payload.ProtocolVersion, payload.Client)
+}
@@ -0,0 +1,251 @@
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	t.Run("current version keeps requested features", func(t *testing.T) {
+		negotiation, err := Negotiate(HelloPayload{
+			ProtocolVersion: ProtocolVersion,
+			Features:        []string{FeatureRequestAck, "time-travel"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, ProtocolVersion, negotiation.ProtocolVersion)
+		assert.False(t, negotiation.Downgraded)
+		assert.Equal(t, []string{FeatureRequestAck}, negotiation.Features)
+	})
+
+	t.Run("newer client is downgraded", func(t *testing.T) {
+		negotiation, err := Negotiate(HelloPayload{ProtocolVersion: ProtocolVersion + 5})
+		require.NoError(t, err)
+		assert.Equal(t, ProtocolVersion, negotiation.ProtocolVersion)
+		assert.True(t, negotiation.Downgraded)
+	})
+
+	t.Run("older client loses newer features", func(t *testing.T) {
+		negotiation, err := Negotiate(HelloPayload{
+			ProtocolVersion: LegacyProtocolVersion,
+			Features:        []string{FeatureRequestAck},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, LegacyProtocolVersion, negotiation.ProtocolVersion)
+		assert.Empty(t, negotiation.Features)
+	})
+
+	t.Run("unsupported version is rejected", func(t *testing.T) {
+		_, err := Negotiate(HelloPayload{ProtocolVersion: MinProtocolVersion - 1})
+		assert.Error(t, err)
+	})
+}
+
+func TestEventHandler_HandleHello(t *testing.T) {
+	eventHandler := NewEventHandler(&MockHub{}, NewMockRepositoryManager())
+	client := NewMockClient("test-group-id", "test-member-id")
+	client.closeRequests = make(chan closeRequest, 1)
+
+	message := Message{
+		Type:      EventHello,
+		RoomID:    "test-group-id",
+		MemberID:  "test-member-id",
+		RequestID: "hello-1",
+		Data: HelloPayload{
+			ProtocolVersion: ProtocolVersion,
+			Features:        []string{FeatureRequestAck},
+		},
+	}
+	messageBytes, _ := json.Marshal(message)
+	eventHandler.ProcessMessage(client.Client, websocket.TextMessage, messageBytes)
+
+	require.Len(t, client.send, 1)
+	var reply struct {
+		Type      string         `json:"type"`
+		RequestID string         `json:"requestId"`
+		Data      WelcomePayload `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(<-client.send, &reply))
+	assert.Equal(t, EventWelcome, reply.Type)
+	assert.Equal(t, "hello-1", reply.RequestID)
+	assert.Equal(t, ProtocolVersion, reply.Data.ProtocolVersion)
+	assert.Equal(t, []string{FeatureRequestAck}, reply.Data.Features)
+	assert.Contains(t, reply.Data.Events, EventAddItem)
+	assert.Empty(t, client.closeRequests)
+
+	// A second hello is refused
+	eventHandler.ProcessMessage(client.Client, websocket.TextMessage, messageBytes)
+	require.Len(t, client.send, 1)
+	var errMsg struct {
+		Data ErrorPayload `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(<-client.send, &errMsg))
+	assert.Equal(t, "ALREADY_NEGOTIATED", errMsg.Data.Code)
+}
+
+func TestEventHandler_FrameTypeSelectsCodec(t *testing.T) {
+	eventHandler := NewEventHandler(&MockHub{}, NewMockRepositoryManager())
+	message := Message{
+		Type:     EventHello,
+		RoomID:   "test-group-id",
+		MemberID: "test-member-id",
+		Data:     HelloPayload{ProtocolVersion: ProtocolVersion},
+	}
+
+	t.Run("binary frame starting with 0x7b is MessagePack", func(t *testing.T) {
+		client := NewMockClient("test-group-id", "test-member-id")
+		messageBytes, _ := json.Marshal(message)
+		require.Equal(t, byte(0x7b), messageBytes[0])
+
+		// In MessagePack 0x7b is the integer 123, not an envelope
+		eventHandler.ProcessMessage(client.Client, websocket.BinaryMessage, messageBytes)
+
+		require.Len(t, client.send, 1)
+		var errMsg struct {
+			Type string       `json:"type"`
+			Data ErrorPayload `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(<-client.send, &errMsg))
+		assert.Equal(t, EventError, errMsg.Type)
+		assert.Equal(t, "INVALID_MESSAGE", errMsg.Data.Code)
+		assert.False(t, client.negotiated)
+	})
+
+	t.Run("binary frame with a MessagePack envelope", func(t *testing.T) {
+		client := NewMockClient("test-group-id", "test-member-id")
+		messageBytes, err := MsgpackCodec.Marshal(message)
+		require.NoError(t, err)
+
+		eventHandler.ProcessMessage(client.Client, websocket.BinaryMessage, messageBytes)
+
+		require.Len(t, client.send, 1)
+		var reply struct {
+			Type string `json:"type"`
+		}
+		require.NoError(t, json.Unmarshal(<-client.send, &reply))
+		assert.Equal(t, EventWelcome, reply.Type)
+	})
+}
+
+func TestEventHandler_HandleHello_RejectsIncompatibleClient(t *testing.T) {
+	eventHandler := NewEventHandler(&MockHub{}, NewMockRepositoryManager())
+	client := NewMockClient("test-group-id", "test-member-id")
+	client.closeRequests = make(chan closeRequest, 1)
+
+	message := Message{
+		Type:     EventHello,
+		RoomID:   "test-group-id",
+		MemberID: "test-member-id",
+		Data:     HelloPayload{ProtocolVersion: 0},
+	}
+	messageBytes, _ := json.Marshal(message)
+	eventHandler.ProcessMessage(client.Client, websocket.TextMessage, messageBytes)
+
+	require.Len(t, client.send, 1)
+	var errMsg struct {
+		Type string       `json:"type"`
+		Data ErrorPayload `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(<-client.send, &errMsg))
+	assert.Equal(t, EventError, errMsg.Type)
+	assert.Equal(t, "PROTOCOL_VERSION_UNSUPPORTED", errMsg.Data.Code)
+
+	// The connection is scheduled to close after the error is flushed
+	require.Len(t, client.closeRequests, 1)
+	assert.False(t, client.negotiated)
+}
+
+func TestEventHandler_LegacyClientCannotUseNewerEvents(t *testing.T) {
+	eventHandler := NewEventHandler(&MockHub{}, NewMockRepositoryManager())
+	client := NewMockClient("test-group-id", "test-member-id")
+
+	message := Message{
+		Type:     EventWelcome,
+		RoomID:   "test-group-id",
+		MemberID: "test-member-id",
+	}
+	messageBytes, _ := json.Marshal(message)
+	eventHandler.ProcessMessage(client.Client, websocket.TextMessage, messageBytes)
+
+	require.Len(t, client.send, 1)
+	var errMsg struct {
+		Data ErrorPayload `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(<-client.send, &errMsg))
+	assert.Equal(t, "UNSUPPORTED_EVENT", errMsg.Data.Code)
+}
+
+func TestDescribeProtocol(t *testing.T) {
+	description := DescribeProtocol()
+
+	assert.Equal(t, ProtocolVersion, description.ProtocolVersion)
+	assert.Equal(t, MinProtocolVersion, description.MinProtocolVersion)
+	assert.Contains(t, description.Features, FeatureRequestAck)
+	assert.Len(t, description.Events, len(protocolEvents))
+
+	for _, event := range description.Events {
+		assert.NotEmpty(t, event.Type)
+		assert.Contains(t, []string{DirectionClientToServer, DirectionServerToClient}, event.Direction)
+		assert.Equal(t, "object", event.Schema["type"], "schema for %s", event.Type)
+	}
+
+	// The whole description must be publishable as JSON
+	_, err := json.Marshal(description)
+	assert.NoError(t, err)
+}
+
+func TestJSONSchema(t *testing.T) {
+	schema := JSONSchema(AddItemPayload{})
+
+	assert.Equal(t, "object", schema["type"])
+	properties := schema["properties"].(map[string]interface{})
+	assert.Contains(t, properties, "groupId")
+
+	item := properties["item"].(map[string]interface{})
+	itemProperties := item["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": []string{"string", "null"}}, itemProperties["description"])
+	assert.ElementsMatch(t, []string{"title", "memberId"}, item["required"])
+
+	envelope := JSONSchema(Message{})
+	assert.ElementsMatch(t, []string{"type", "roomId", "data"}, envelope["required"])
+}
+
+func TestLegacyClientsOnlyGetTheirProtocolVersion(t *testing.T) {
+	hub := NewHub()
+	legacy := NewMockClient("test-group-id", "legacy-member-id")
+	current := NewMockClient("test-group-id", "current-member-id")
+	current.setNegotiation(&Negotiation{ProtocolVersion: ProtocolVersion, Features: []string{FeatureRequestAck}})
+	hub.registerClient(legacy.Client)
+	hub.registerClient(current.Client)
+
+	t.Run("commands with a request ID are acked at any version", func(t *testing.T) {
+		eventHandler := NewEventHandler(hub, NewMockRepositoryManager())
+		for _, client := range []*MockClient{legacy, current} {
+			eventHandler.sendAck(client.Client, "req-1", EventAddItem, nil)
+			eventHandler.sendAck(client.Client, "", EventAddItem, nil)
+
+			require.Len(t, client.send, 1)
+			var ack Message
+			require.NoError(t, json.Unmarshal(<-client.send, &ack))
+			assert.Equal(t, EventAck, ack.Type)
+			assert.Equal(t, "req-1", ack.RequestID)
+		}
+	})
+
+	t.Run("broadcasts skip events newer than the client", func(t *testing.T) {
+		hub.broadcastMessage(Message{Type: EventDeadlinePassed, RoomID: "test-group-id", Data: DeadlinePayload{}})
+		hub.broadcastMessage(Message{Type: EventItemAdded, RoomID: "test-group-id", Data: map[string]string{}})
+
+		require.Len(t, legacy.send, 1)
+		var received Message
+		require.NoError(t, json.Unmarshal(<-legacy.send, &received))
+		assert.Equal(t, EventItemAdded, received.Type)
+
+		require.Len(t, current.send, 2)
+		require.NoError(t, json.Unmarshal(<-current.send, &received))
+		assert.Equal(t, EventDeadlinePassed, received.Type)
+	})
+}
@@ -0,0 +1,109 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// streamRetry tells EventSource clients how long to wait before reconnecting
+const streamRetry = 3 * time.Second
+
+// NewSubscriber creates a read-only client for a Server-Sent Events stream.
+// It joins the room like a WebSocket client but has no connection of its own;
+// its send queue is drained by ServeSSE. If lastEventID is set, broadcasts
+// after that event are replayed on registration. Subscribers cannot send
+// hello, so they get the events of the current protocol version.
+func NewSubscriber(hub *Hub, roomID, memberID, lastEventID string) *Client {
+	client := NewClient(hub, nil, roomID, memberID, nil)
+	client.resumeFrom = lastEventID
+	client.setNegotiation(&Negotiation{ProtocolVersion: ProtocolVersion, Features: []string{}})
+	return client
+}
+
+// ServeSSE streams the broadcasts of a room as Server-Sent Events until the
+// request is cancelled. Each event carries the broadcast's event type and
+// event ID, so a reconnecting EventSource resumes through Last-Event-ID.
+func ServeSSE(hub *Hub, w http.ResponseWriter, r *http.Request, roomID, memberID, lastEventID string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// Stop nginx from buffering the stream
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+	flusher.Flush()
+
+	client := NewSubscriber(hub, roomID, memberID, lastEventID)
+	hub.register <- client
+	defer func() {
+		// The hub may already have dropped a slow subscriber; unregistering
+		// twice is harmless
+		hub.Unsubscribe(client)
+	}()
+
+	log.Printf("Event stream client connected to room %s with member ID %s", roomID, memberID)
+
+	keepAlive := time.NewTicker(hub.config.StreamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			log.Printf("Event stream client disconnected from room %s", roomID)
+			return
+
+		case message, ok := <-client.send:
+			if !ok {
+				// The hub dropped this subscriber; the client reconnects and
+				// resumes from its last event ID
+				return
+			}
+			if err := writeSSEEvent(w, message); err != nil {
+				log.Printf("Error writing event stream for room %s: %v", roomID, err)
+				return
+			}
+			flusher.Flush()
+
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeSSEEvent writes one hub message as a Server-Sent Event. The data line
+// is the same JSON envelope WebSocket clients receive.
+func writeSSEEvent(w http.ResponseWriter, message []byte) error {
+	var envelope struct {
+		Type    string `json:"type"`
+		EventID string `json:"eventId"`
+	}
+	if err := json.Unmarshal(message, &envelope); err != nil {
+		return fmt.Errorf("failed to read message envelope: %w", err)
+	}
+
+	var b strings.Builder
+	if envelope.EventID != "" {
+		fmt.Fprintf(&b, "id: %s\n", envelope.EventID)
+	}
+	fmt.Fprintf(&b, "event: %s\n", envelope.Type)
+	// JSON never contains raw newlines, so the envelope fits on one data line
+	fmt.Fprintf(&b, "data: %s\n\n", message)
+
+	_, err := w.Write([]byte(b.String()))
+	return err
+}
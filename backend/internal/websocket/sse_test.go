@@ -0,0 +1,179 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sseEvent is one parsed Server-Sent Event
+type sseEvent struct {
+	id        string
+	eventType string
+	data      string
+	comment   string
+}
+
+// readSSEEvent reads lines until a blank line ends an event or comment
+func readSSEEvent(t *testing.T, reader *bufio.Reader) sseEvent {
+	t.Helper()
+	var event sseEvent
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "":
+			if event != (sseEvent{}) {
+				return event
+			}
+		case strings.HasPrefix(line, ":"):
+			event.comment = strings.TrimSpace(strings.TrimPrefix(line, ":"))
+		case strings.HasPrefix(line, "id: "):
+			event.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			event.eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+// openStream connects to the test server's event stream
+func openStream(t *testing.T, ctx context.Context, url, lastEventID string) *bufio.Reader {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	retry, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(retry, "retry: "))
+	return reader
+}
+
+// waitForClients waits until a room has the expected number of clients
+func waitForClients(t *testing.T, hub *Hub, roomID string, count int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		return hub.GetRoomClientCount(roomID) == count
+	}, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestServeSSE(t *testing.T) {
+	config := DefaultConfig()
+	config.StreamKeepAlive = 50 * time.Millisecond
+	hub := NewHubWithConfig(config)
+	go hub.Run()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ServeSSE(hub, w, r, "test-room", "", r.Header.Get("Last-Event-ID"))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reader := openStream(t, ctx, server.URL, "")
+	waitForClients(t, hub, "test-room", 1)
+
+	hub.BroadcastToRoom("test-room", EventItemAdded, map[string]string{"title": "First"})
+	first := readSSEEvent(t, reader)
+	assert.Equal(t, EventItemAdded, first.eventType)
+	assert.NotEmpty(t, first.id)
+	assert.Contains(t, first.data, `"title":"First"`)
+	assert.Contains(t, first.data, `"eventId":"`+first.id+`"`)
+
+	// Keep-alive comments arrive while the room is quiet
+	assert.Equal(t, "keep-alive", readSSEEvent(t, reader).comment)
+
+	// Disconnect, miss two events, then resume from the first one
+	cancel()
+	waitForClients(t, hub, "test-room", 0)
+
+	hub.BroadcastToRoom("test-room", EventItemUpdated, map[string]string{"title": "Second"})
+	hub.BroadcastToRoom("test-room", EventItemUpdated, map[string]string{"title": "Third"})
+
+	resumeCtx, resumeCancel := context.WithCancel(context.Background())
+	defer resumeCancel()
+	reader = openStream(t, resumeCtx, server.URL, first.id)
+	second := readSSEEvent(t, reader)
+	third := readSSEEvent(t, reader)
+	assert.Equal(t, EventItemUpdated, second.eventType)
+	assert.Contains(t, second.data, "Second")
+	assert.Contains(t, third.data, "Third")
+	assert.NotEqual(t, second.id, third.id)
+}
+
+func TestServeSSE_UnknownEventIDRequestsResync(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ServeSSE(hub, w, r, "test-room", "", r.Header.Get("Last-Event-ID"))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reader := openStream(t, ctx, server.URL, "previous-process-42")
+	event := readSSEEvent(t, reader)
+	assert.Equal(t, EventResync, event.eventType)
+	assert.Empty(t, event.id)
+}
+
+func TestRoomHistorySince(t *testing.T) {
+	history := newRoomHistory()
+	for seq := uint64(1); seq <= 5; seq++ {
+		history.append(seq, []byte{byte(seq)}, 3)
+		history.nextSeq++
+	}
+
+	// Entries 3-5 are retained
+	messages, ok := history.since(3)
+	require.True(t, ok)
+	assert.Equal(t, [][]byte{{4}, {5}}, messages)
+
+	messages, ok = history.since(2)
+	require.True(t, ok)
+	assert.Len(t, messages, 3)
+
+	messages, ok = history.since(5)
+	require.True(t, ok)
+	assert.Empty(t, messages)
+
+	// Event 2 was dropped, so resuming from 1 would leave a gap
+	_, ok = history.since(1)
+	assert.False(t, ok)
+
+	// Events from the future are unknown
+	_, ok = history.since(9)
+	assert.False(t, ok)
+}
+
+func TestParseEventID(t *testing.T) {
+	epoch, seq, err := parseEventID(formatEventID("abc123", 42))
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", epoch)
+	assert.Equal(t, uint64(42), seq)
+
+	_, _, err = parseEventID("no-sequence-")
+	assert.Error(t, err)
+	_, _, err = parseEventID("42")
+	assert.Error(t, err)
+}
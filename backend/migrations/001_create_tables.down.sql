@@ -0,0 +1,5 @@
+-- Revert: Create initial tables for collaborative bucket list
+
+DROP TABLE IF EXISTS bucket_items;
+DROP TABLE IF EXISTS members;
+DROP TABLE IF EXISTS groups;
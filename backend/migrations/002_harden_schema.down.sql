@@ -0,0 +1,20 @@
+-- Revert: Add indexes, one membership per user and deletion rules
+
+-- Items whose creator was deleted cannot be kept without one
+DELETE FROM bucket_items WHERE created_by IS NULL;
+
+ALTER TABLE bucket_items
+    ALTER COLUMN created_by SET NOT NULL,
+    DROP CONSTRAINT IF EXISTS bucket_items_created_by_fkey,
+    ADD CONSTRAINT bucket_items_created_by_fkey
+        FOREIGN KEY (created_by) REFERENCES members(id),
+    DROP CONSTRAINT IF EXISTS bucket_items_completed_by_fkey,
+    ADD CONSTRAINT bucket_items_completed_by_fkey
+        FOREIGN KEY (completed_by) REFERENCES members(id);
+
+DROP INDEX IF EXISTS idx_bucket_items_completed_by;
+DROP INDEX IF EXISTS idx_bucket_items_created_by;
+DROP INDEX IF EXISTS idx_bucket_items_group_id;
+DROP INDEX IF EXISTS idx_members_user_id;
+DROP INDEX IF EXISTS idx_members_group_id;
+DROP INDEX IF EXISTS members_group_user_unique;
@@ -0,0 +1,50 @@
+-- Migration: Add indexes, one membership per user and deletion rules
+-- Created: 2026-10-18
+
+-- Merge duplicate memberships of the same user in a group into the earliest
+-- one (the creator's, if any), so the unique index below can be built
+CREATE TEMP TABLE duplicate_members ON COMMIT DROP AS
+SELECT id, keep_id
+FROM (
+    SELECT id,
+           FIRST_VALUE(id) OVER (
+               PARTITION BY group_id, user_id
+               ORDER BY is_creator IS TRUE DESC, joined_at, id
+           ) AS keep_id
+    FROM members
+    WHERE user_id IS NOT NULL
+) ranked
+WHERE id <> keep_id;
+
+UPDATE bucket_items b SET created_by = d.keep_id
+FROM duplicate_members d WHERE b.created_by = d.id;
+
+UPDATE bucket_items b SET completed_by = d.keep_id
+FROM duplicate_members d WHERE b.completed_by = d.id;
+
+DELETE FROM members m USING duplicate_members d WHERE m.id = d.id;
+
+-- A user can join a group only once; anonymous members have no user ID
+CREATE UNIQUE INDEX IF NOT EXISTS members_group_user_unique
+    ON members (group_id, user_id)
+    WHERE user_id IS NOT NULL;
+
+-- Lookup indexes for members and items
+CREATE INDEX IF NOT EXISTS idx_members_group_id ON members (group_id);
+CREATE INDEX IF NOT EXISTS idx_members_user_id ON members (user_id) WHERE user_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_bucket_items_group_id ON bucket_items (group_id, created_at DESC);
+
+-- Indexes on the member references keep member deletes from scanning items
+CREATE INDEX IF NOT EXISTS idx_bucket_items_created_by ON bucket_items (created_by);
+CREATE INDEX IF NOT EXISTS idx_bucket_items_completed_by ON bucket_items (completed_by) WHERE completed_by IS NOT NULL;
+
+-- Deleting a member keeps the items they added or completed for the rest of
+-- the group; the items no longer name who added or completed them.
+ALTER TABLE bucket_items
+    ALTER COLUMN created_by DROP NOT NULL,
+    DROP CONSTRAINT IF EXISTS bucket_items_created_by_fkey,
+    ADD CONSTRAINT bucket_items_created_by_fkey
+        FOREIGN KEY (created_by) REFERENCES members(id) ON DELETE SET NULL,
+    DROP CONSTRAINT IF EXISTS bucket_items_completed_by_fkey,
+    ADD CONSTRAINT bucket_items_completed_by_fkey
+        FOREIGN KEY (completed_by) REFERENCES members(id) ON DELETE SET NULL;
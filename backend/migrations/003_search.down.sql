@@ -0,0 +1,7 @@
+-- Revert: Full-text search over items and group names
+
+DROP INDEX IF EXISTS idx_groups_search;
+ALTER TABLE groups DROP COLUMN IF EXISTS search_vector;
+
+DROP INDEX IF EXISTS idx_bucket_items_search;
+ALTER TABLE bucket_items DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,16 @@
+-- Migration: Full-text search over items and group names
+-- Created: 2026-10-18
+
+-- Titles rank above descriptions (weight A over B)
+ALTER TABLE bucket_items ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_bucket_items_search ON bucket_items USING GIN (search_vector);
+
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('english', coalesce(name, ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_groups_search ON groups USING GIN (search_vector);
@@ -0,0 +1,6 @@
+-- Migration: Indexes for keyset pagination of a group's items
+-- Created: 2026-10-18
+
+-- Each sort key is followed by id, the tie-breaker every page continues from
+CREATE INDEX IF NOT EXISTS idx_bucket_items_group_created_id ON bucket_items (group_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_bucket_items_group_title_id ON bucket_items (group_id, lower(title), id);
@@ -0,0 +1,4 @@
+-- Revert: Group-scoped tags and item tagging
+
+DROP TABLE IF EXISTS item_tags;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,25 @@
+-- Migration: Group-scoped tags and item tagging
+-- Created: 2026-10-18
+
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY,
+    group_id UUID NOT NULL,
+    name TEXT NOT NULL,
+    color TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT tags_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE
+);
+
+-- Tag names are unique within a group, ignoring case
+CREATE UNIQUE INDEX IF NOT EXISTS tags_group_name_unique ON tags (group_id, lower(name));
+
+-- Deleting an item or a tag removes its taggings
+CREATE TABLE IF NOT EXISTS item_tags (
+    item_id UUID NOT NULL,
+    tag_id UUID NOT NULL,
+    PRIMARY KEY (item_id, tag_id),
+    CONSTRAINT item_tags_item_id_fkey FOREIGN KEY (item_id) REFERENCES bucket_items(id) ON DELETE CASCADE,
+    CONSTRAINT item_tags_tag_id_fkey FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_tags_tag_id ON item_tags (tag_id);
@@ -0,0 +1,3 @@
+-- Revert: Item comments with one level of replies
+
+DROP TABLE IF EXISTS comments;
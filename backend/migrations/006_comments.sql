@@ -0,0 +1,25 @@
+-- Migration: Item comments with one level of replies
+-- Created: 2026-10-18
+
+-- Comments carry their item's group, so a group's comment counts need no
+-- join. Deleting an item, its group, the author or a parent comment
+-- removes the comment.
+CREATE TABLE IF NOT EXISTS comments (
+    id UUID PRIMARY KEY,
+    item_id UUID NOT NULL,
+    group_id UUID NOT NULL,
+    parent_id UUID,
+    member_id UUID NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    edited_at TIMESTAMPTZ,
+    CONSTRAINT comments_item_id_fkey FOREIGN KEY (item_id) REFERENCES bucket_items(id) ON DELETE CASCADE,
+    CONSTRAINT comments_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE,
+    CONSTRAINT comments_parent_id_fkey FOREIGN KEY (parent_id) REFERENCES comments(id) ON DELETE CASCADE,
+    CONSTRAINT comments_member_id_fkey FOREIGN KEY (member_id) REFERENCES members(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_item_id ON comments (item_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_comments_group_id ON comments (group_id);
+CREATE INDEX IF NOT EXISTS idx_comments_parent_id ON comments (parent_id) WHERE parent_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_comments_member_id ON comments (member_id);
@@ -0,0 +1,28 @@
+-- Migration: Item upvotes and emoji reactions
+-- Created: 2026-10-18
+
+-- A member votes for an item at most once. Deleting the item or the member
+-- removes the vote.
+CREATE TABLE IF NOT EXISTS item_votes (
+    item_id UUID NOT NULL,
+    member_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (item_id, member_id),
+    CONSTRAINT item_votes_item_id_fkey FOREIGN KEY (item_id) REFERENCES bucket_items(id) ON DELETE CASCADE,
+    CONSTRAINT item_votes_member_id_fkey FOREIGN KEY (member_id) REFERENCES members(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_votes_member_id ON item_votes (member_id);
+
+-- A member reacts to an item with each emoji at most once
+CREATE TABLE IF NOT EXISTS item_reactions (
+    item_id UUID NOT NULL,
+    member_id UUID NOT NULL,
+    emoji TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (item_id, member_id, emoji),
+    CONSTRAINT item_reactions_item_id_fkey FOREIGN KEY (item_id) REFERENCES bucket_items(id) ON DELETE CASCADE,
+    CONSTRAINT item_reactions_member_id_fkey FOREIGN KEY (member_id) REFERENCES members(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_reactions_member_id ON item_reactions (member_id);
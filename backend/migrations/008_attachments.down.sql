@@ -0,0 +1,5 @@
+-- Revert: Item attachments kept in a blob store
+
+DROP TABLE IF EXISTS attachments;
+DROP FUNCTION IF EXISTS queue_attachment_blob_deletion();
+DROP TABLE IF EXISTS blob_deletions;
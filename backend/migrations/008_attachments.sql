@@ -0,0 +1,45 @@
+-- Migration: Item attachments kept in a blob store
+-- Created: 2026-10-18
+
+-- Attachments carry their item's group like comments. Deleting an item,
+-- its group or the uploader removes the attachment.
+CREATE TABLE IF NOT EXISTS attachments (
+    id UUID PRIMARY KEY,
+    item_id UUID NOT NULL,
+    group_id UUID NOT NULL,
+    member_id UUID NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    storage_key TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT attachments_storage_key_unique UNIQUE (storage_key),
+    CONSTRAINT attachments_item_id_fkey FOREIGN KEY (item_id) REFERENCES bucket_items(id) ON DELETE CASCADE,
+    CONSTRAINT attachments_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE,
+    CONSTRAINT attachments_member_id_fkey FOREIGN KEY (member_id) REFERENCES members(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_item_id ON attachments (item_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_attachments_group_id ON attachments (group_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_member_id ON attachments (member_id);
+
+-- Blobs of deleted attachments wait here until the blob cleaner removes
+-- them from the store. The trigger catches every delete, cascades
+-- included, and a rolled back delete queues nothing.
+CREATE TABLE IF NOT EXISTS blob_deletions (
+    storage_key TEXT PRIMARY KEY,
+    queued_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE OR REPLACE FUNCTION queue_attachment_blob_deletion() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO blob_deletions (storage_key) VALUES (OLD.storage_key)
+    ON CONFLICT (storage_key) DO NOTHING;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS attachments_queue_blob_deletion ON attachments;
+CREATE TRIGGER attachments_queue_blob_deletion
+    AFTER DELETE ON attachments
+    FOR EACH ROW EXECUTE FUNCTION queue_attachment_blob_deletion();
@@ -0,0 +1,6 @@
+-- Revert: Background processing of image attachments
+
+DROP INDEX IF EXISTS idx_attachments_pending;
+ALTER TABLE attachments DROP COLUMN IF EXISTS processing_status;
+ALTER TABLE attachments DROP COLUMN IF EXISTS height;
+ALTER TABLE attachments DROP COLUMN IF EXISTS width;
@@ -0,0 +1,5 @@
+-- Revert: Completion journal of bucket list items
+
+ALTER TABLE bucket_items DROP COLUMN IF EXISTS occurred_on;
+ALTER TABLE bucket_items DROP COLUMN IF EXISTS completion_rating;
+ALTER TABLE bucket_items DROP COLUMN IF EXISTS completion_note;
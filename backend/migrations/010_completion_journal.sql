@@ -0,0 +1,11 @@
+-- Migration: Completion journal of bucket list items
+-- Created: 2026-10-18
+
+-- What a group records about a completed item is kept next to
+-- completed_at and cleared with it
+ALTER TABLE bucket_items ADD COLUMN IF NOT EXISTS completion_note TEXT
+    CONSTRAINT bucket_items_completion_note_length CHECK (char_length(completion_note) <= 2000);
+ALTER TABLE bucket_items ADD COLUMN IF NOT EXISTS completion_rating SMALLINT
+    CONSTRAINT bucket_items_completion_rating_range CHECK (completion_rating BETWEEN 1 AND 5);
+ALTER TABLE bucket_items ADD COLUMN IF NOT EXISTS occurred_on DATE;
+
@@ -0,0 +1,3 @@
+-- Revert: Item assignment
+
+DROP TABLE IF EXISTS item_assignees;
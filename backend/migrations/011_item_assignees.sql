@@ -0,0 +1,15 @@
+-- Migration: Item assignment
+-- Created: 2026-10-18
+
+-- An item is assigned to any number of members of its group, each at most
+-- once. Deleting the item or the member removes the assignment.
+CREATE TABLE IF NOT EXISTS item_assignees (
+    item_id UUID NOT NULL,
+    member_id UUID NOT NULL,
+    assigned_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (item_id, member_id),
+    CONSTRAINT item_assignees_item_id_fkey FOREIGN KEY (item_id) REFERENCES bucket_items(id) ON DELETE CASCADE,
+    CONSTRAINT item_assignees_member_id_fkey FOREIGN KEY (member_id) REFERENCES members(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_assignees_member_id ON item_assignees (member_id);
@@ -0,0 +1,6 @@
+-- Revert: Scheduled start and end of bucket list items
+
+DROP INDEX IF EXISTS idx_bucket_items_group_scheduled_start;
+ALTER TABLE bucket_items DROP CONSTRAINT IF EXISTS bucket_items_schedule_order;
+ALTER TABLE bucket_items DROP COLUMN IF EXISTS scheduled_end;
+ALTER TABLE bucket_items DROP COLUMN IF EXISTS scheduled_start;
@@ -0,0 +1,14 @@
+-- Migration: Scheduled start and end of bucket list items
+-- Created: 2026-10-18
+
+-- An item may be planned for a point in time or a span. An end needs a
+-- start and comes after it.
+ALTER TABLE bucket_items ADD COLUMN IF NOT EXISTS scheduled_start TIMESTAMPTZ;
+ALTER TABLE bucket_items ADD COLUMN IF NOT EXISTS scheduled_end TIMESTAMPTZ;
+ALTER TABLE bucket_items
+    DROP CONSTRAINT IF EXISTS bucket_items_schedule_order,
+    ADD CONSTRAINT bucket_items_schedule_order
+        CHECK (scheduled_end IS NULL OR (scheduled_start IS NOT NULL AND scheduled_end > scheduled_start));
+
+CREATE INDEX IF NOT EXISTS idx_bucket_items_group_scheduled_start
+    ON bucket_items (group_id, scheduled_start) WHERE scheduled_start IS NOT NULL;
@@ -0,0 +1,10 @@
+-- Migration: Calendar feed tokens of groups
+-- Created: 2026-10-18
+
+-- The SHA-256 hash, in hex, of the secret in a group's calendar feed URL.
+-- Only the hash is kept, so the URL is shown once, when the feed is
+-- enabled; clearing it revokes every subscription.
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS calendar_token_hash TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_groups_calendar_token_hash
+    ON groups (calendar_token_hash) WHERE calendar_token_hash IS NOT NULL;
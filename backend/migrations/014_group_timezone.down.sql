@@ -0,0 +1,3 @@
+-- Revert: Timezone of groups
+
+ALTER TABLE groups DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,6 @@
+-- Migration: Timezone of groups
+-- Created: 2026-10-18
+
+-- The IANA name of the zone a group's dates are in. Deadlines given as
+-- dates end at midnight in this zone. Existing groups were created in UTC.
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC';
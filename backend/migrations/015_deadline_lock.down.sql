@@ -0,0 +1,4 @@
+-- Revert: Locking lists after their deadline
+
+DROP INDEX IF EXISTS idx_groups_deadline;
+ALTER TABLE groups DROP COLUMN IF EXISTS lock_after_deadline;
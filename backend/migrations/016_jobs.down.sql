@@ -0,0 +1,3 @@
+-- Revert: Background job queue
+
+DROP TABLE IF EXISTS jobs;
@@ -0,0 +1,38 @@
+-- Migration: Background job queue
+-- Created: 2026-10-18
+
+-- Background jobs. Workers on every server claim runnable jobs with
+-- SELECT ... FOR UPDATE SKIP LOCKED and hold them until locked_until, so a
+-- job runs on one server at a time and is picked up again if its worker
+-- dies. A job that fails max_attempts times is dead and stays here, as the
+-- dead letter queue, until it is requeued.
+CREATE TABLE IF NOT EXISTS jobs (
+    id UUID PRIMARY KEY,
+    kind VARCHAR(100) NOT NULL,
+    args JSONB NOT NULL DEFAULT '{}',
+    state VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    unique_key VARCHAR(255),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    run_at TIMESTAMPTZ NOT NULL,
+    locked_by VARCHAR(255),
+    locked_until TIMESTAMPTZ,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ,
+    CONSTRAINT jobs_state_check CHECK (state IN ('scheduled', 'running', 'completed', 'dead')),
+    CONSTRAINT jobs_attempts_check CHECK (attempts >= 0 AND max_attempts > 0)
+);
+
+-- A unique key belongs to one job of a kind until that job is pruned
+CREATE UNIQUE INDEX IF NOT EXISTS idx_jobs_unique_key
+    ON jobs (kind, unique_key) WHERE unique_key IS NOT NULL;
+
+-- Runnable jobs in the order workers claim them
+CREATE INDEX IF NOT EXISTS idx_jobs_scheduled ON jobs (run_at, id) WHERE state = 'scheduled';
+
+-- Running jobs whose lock has expired
+CREATE INDEX IF NOT EXISTS idx_jobs_running ON jobs (locked_until) WHERE state = 'running';
+
+-- Finished jobs, to prune completed ones and list dead ones
+CREATE INDEX IF NOT EXISTS idx_jobs_finished ON jobs (state, finished_at) WHERE finished_at IS NOT NULL;
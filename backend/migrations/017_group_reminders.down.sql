@@ -0,0 +1,3 @@
+-- Revert: Deadline reminder schedules
+
+DROP TABLE IF EXISTS group_reminders;
@@ -0,0 +1,18 @@
+-- Migration: Deadline reminder schedules
+-- Created: 2026-10-18
+
+-- The days before its deadline a group's members are reminded of it. The
+-- reminders themselves are background jobs whose unique keys keep them
+-- from being sent twice.
+CREATE TABLE IF NOT EXISTS group_reminders (
+    group_id UUID NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    days_before INTEGER NOT NULL,
+    PRIMARY KEY (group_id, days_before),
+    CONSTRAINT group_reminders_days_before_check CHECK (days_before BETWEEN 1 AND 365)
+);
+
+-- Existing groups get the default schedule
+INSERT INTO group_reminders (group_id, days_before)
+SELECT g.id, d.days_before
+FROM groups g CROSS JOIN (VALUES (30), (7), (1)) AS d(days_before)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,40 @@
+// Package migrations embeds the SQL schema migrations into the binary, so
+// the server does not depend on its working directory to find them.
+//
+// Files are named NNN_description.sql. An optional NNN_description.down.sql
+// next to it reverts the migration. PostgreSQL migrations live in this
+// directory and SQLite migrations in sqlite/; each driver has its own
+// version sequence.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// FS holds the PostgreSQL migrations
+//
+//go:embed *.sql
+var FS embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteFiles embed.FS
+
+// SQLiteFS holds the SQLite migrations
+var SQLiteFS = mustSub(sqliteFiles, "sqlite")
+
+// ForDriver returns the migrations for a database driver name
+func ForDriver(driver string) fs.FS {
+	if driver == "sqlite" {
+		return SQLiteFS
+	}
+	return FS
+}
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
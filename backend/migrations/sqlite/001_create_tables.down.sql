@@ -0,0 +1,5 @@
+-- Revert: Create tables for collaborative bucket list (SQLite)
+
+DROP TABLE IF EXISTS bucket_items;
+DROP TABLE IF EXISTS members;
+DROP TABLE IF EXISTS groups;
@@ -0,0 +1,52 @@
+-- Migration: Create tables for collaborative bucket list (SQLite)
+-- Created: 2026-10-18
+--
+-- Mirrors the PostgreSQL schema after 002_harden_schema.sql. UUIDs are
+-- stored as lowercase text and timestamps as UTC RFC 3339 text with
+-- microsecond precision (2006-01-02T15:04:05.000000Z), so they sort
+-- chronologically as strings.
+
+-- Groups table
+CREATE TABLE IF NOT EXISTS groups (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    deadline TEXT,
+    created_at TEXT NOT NULL,
+    created_by TEXT NOT NULL
+);
+
+-- Members table
+CREATE TABLE IF NOT EXISTS members (
+    id TEXT PRIMARY KEY,
+    group_id TEXT NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    user_id TEXT,
+    name TEXT NOT NULL,
+    joined_at TEXT NOT NULL,
+    is_creator INTEGER NOT NULL DEFAULT 0
+);
+
+-- Bucket list items table. Deleting a member removes the items they added;
+-- items they completed stay completed but no longer name who completed them.
+CREATE TABLE IF NOT EXISTS bucket_items (
+    id TEXT PRIMARY KEY,
+    group_id TEXT NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    description TEXT,
+    completed INTEGER NOT NULL DEFAULT 0,
+    completed_by TEXT REFERENCES members(id) ON DELETE SET NULL,
+    completed_at TEXT,
+    created_by TEXT REFERENCES members(id) ON DELETE SET NULL,
+    created_at TEXT NOT NULL
+);
+
+-- A user can join a group only once; anonymous members have no user ID
+CREATE UNIQUE INDEX IF NOT EXISTS members_group_user_unique
+    ON members (group_id, user_id)
+    WHERE user_id IS NOT NULL;
+
+-- Lookup indexes for members and items
+CREATE INDEX IF NOT EXISTS idx_members_group_id ON members (group_id);
+CREATE INDEX IF NOT EXISTS idx_members_user_id ON members (user_id) WHERE user_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_bucket_items_group_id ON bucket_items (group_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_bucket_items_created_by ON bucket_items (created_by);
+CREATE INDEX IF NOT EXISTS idx_bucket_items_completed_by ON bucket_items (completed_by) WHERE completed_by IS NOT NULL;
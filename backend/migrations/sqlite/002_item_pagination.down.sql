@@ -0,0 +1,4 @@
+-- Revert: Indexes for keyset pagination of a group's items (SQLite)
+
+DROP INDEX IF EXISTS idx_bucket_items_group_title_id;
+DROP INDEX IF EXISTS idx_bucket_items_group_created_id;
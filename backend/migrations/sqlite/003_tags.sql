@@ -0,0 +1,22 @@
+-- Migration: Group-scoped tags and item tagging (SQLite)
+-- Created: 2026-10-18
+
+CREATE TABLE IF NOT EXISTS tags (
+    id TEXT PRIMARY KEY,
+    group_id TEXT NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    color TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+-- Tag names are unique within a group, ignoring case
+CREATE UNIQUE INDEX IF NOT EXISTS tags_group_name_unique ON tags (group_id, lower(name));
+
+-- Deleting an item or a tag removes its taggings
+CREATE TABLE IF NOT EXISTS item_tags (
+    item_id TEXT NOT NULL REFERENCES bucket_items(id) ON DELETE CASCADE,
+    tag_id TEXT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (item_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_tags_tag_id ON item_tags (tag_id);
@@ -0,0 +1,21 @@
+-- Migration: Item comments with one level of replies (SQLite)
+-- Created: 2026-10-18
+
+-- Comments carry their item's group, so a group's comment counts need no
+-- join. Deleting an item, its group, the author or a parent comment
+-- removes the comment.
+CREATE TABLE IF NOT EXISTS comments (
+    id TEXT PRIMARY KEY,
+    item_id TEXT NOT NULL REFERENCES bucket_items(id) ON DELETE CASCADE,
+    group_id TEXT NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    parent_id TEXT REFERENCES comments(id) ON DELETE CASCADE,
+    member_id TEXT NOT NULL REFERENCES members(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    edited_at TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_item_id ON comments (item_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_comments_group_id ON comments (group_id);
+CREATE INDEX IF NOT EXISTS idx_comments_parent_id ON comments (parent_id) WHERE parent_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_comments_member_id ON comments (member_id);
@@ -0,0 +1,4 @@
+-- Revert: Item upvotes and emoji reactions (SQLite)
+
+DROP TABLE IF EXISTS item_reactions;
+DROP TABLE IF EXISTS item_votes;
@@ -0,0 +1,24 @@
+-- Migration: Item upvotes and emoji reactions (SQLite)
+-- Created: 2026-10-18
+
+-- A member votes for an item at most once. Deleting the item or the member
+-- removes the vote.
+CREATE TABLE IF NOT EXISTS item_votes (
+    item_id TEXT NOT NULL REFERENCES bucket_items(id) ON DELETE CASCADE,
+    member_id TEXT NOT NULL REFERENCES members(id) ON DELETE CASCADE,
+    created_at TEXT NOT NULL,
+    PRIMARY KEY (item_id, member_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_votes_member_id ON item_votes (member_id);
+
+-- A member reacts to an item with each emoji at most once
+CREATE TABLE IF NOT EXISTS item_reactions (
+    item_id TEXT NOT NULL REFERENCES bucket_items(id) ON DELETE CASCADE,
+    member_id TEXT NOT NULL REFERENCES members(id) ON DELETE CASCADE,
+    emoji TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    PRIMARY KEY (item_id, member_id, emoji)
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_reactions_member_id ON item_reactions (member_id);
@@ -0,0 +1,5 @@
+-- Revert: Item attachments kept in a blob store (SQLite)
+
+DROP TRIGGER IF EXISTS attachments_queue_blob_deletion;
+DROP TABLE IF EXISTS attachments;
+DROP TABLE IF EXISTS blob_deletions;
@@ -0,0 +1,37 @@
+-- Migration: Item attachments kept in a blob store (SQLite)
+-- Created: 2026-10-18
+
+-- Attachments carry their item's group like comments. Deleting an item,
+-- its group or the uploader removes the attachment.
+CREATE TABLE IF NOT EXISTS attachments (
+    id TEXT PRIMARY KEY,
+    item_id TEXT NOT NULL REFERENCES bucket_items(id) ON DELETE CASCADE,
+    group_id TEXT NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    member_id TEXT NOT NULL REFERENCES members(id) ON DELETE CASCADE,
+    file_name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size INTEGER NOT NULL CHECK (size > 0),
+    storage_key TEXT NOT NULL UNIQUE,
+    created_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_item_id ON attachments (item_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_attachments_group_id ON attachments (group_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_member_id ON attachments (member_id);
+
+-- Blobs of deleted attachments wait here until the blob cleaner removes
+-- them from the store. The trigger catches every delete, cascades
+-- included, and a rolled back delete queues nothing.
+CREATE TABLE IF NOT EXISTS blob_deletions (
+    storage_key TEXT PRIMARY KEY,
+    queued_at TEXT NOT NULL
+);
+
+CREATE TRIGGER IF NOT EXISTS attachments_queue_blob_deletion
+AFTER DELETE ON attachments
+BEGIN
+    -- Same fixed-width format as the repositories write, padded from
+    -- milliseconds to microseconds
+    INSERT OR IGNORE INTO blob_deletions (storage_key, queued_at)
+    VALUES (OLD.storage_key, strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z');
+END;
@@ -0,0 +1,6 @@
+-- Revert: Background processing of image attachments (SQLite)
+
+DROP INDEX IF EXISTS idx_attachments_pending;
+ALTER TABLE attachments DROP COLUMN processing_status;
+ALTER TABLE attachments DROP COLUMN height;
+ALTER TABLE attachments DROP COLUMN width;
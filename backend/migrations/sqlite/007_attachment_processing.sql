@@ -0,0 +1,14 @@
+-- Migration: Background processing of image attachments (SQLite)
+-- Created: 2026-10-18
+
+-- Images are stripped of metadata and thumbnailed after upload. Dimensions
+-- are known once processed; attachments uploaded before this migration are
+-- pending and get processed too.
+ALTER TABLE attachments ADD COLUMN width INTEGER CHECK (width > 0);
+ALTER TABLE attachments ADD COLUMN height INTEGER CHECK (height > 0);
+ALTER TABLE attachments ADD COLUMN processing_status TEXT NOT NULL DEFAULT 'pending'
+    CHECK (processing_status IN ('pending', 'processed', 'failed'));
+
+-- The processor polls the oldest pending attachments
+CREATE INDEX IF NOT EXISTS idx_attachments_pending ON attachments (created_at)
+    WHERE processing_status = 'pending';
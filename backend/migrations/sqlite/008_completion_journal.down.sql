@@ -0,0 +1,5 @@
+-- Revert: Completion journal of bucket list items (SQLite)
+
+ALTER TABLE bucket_items DROP COLUMN occurred_on;
+ALTER TABLE bucket_items DROP COLUMN completion_rating;
+ALTER TABLE bucket_items DROP COLUMN completion_note;
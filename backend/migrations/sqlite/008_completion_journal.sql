@@ -0,0 +1,8 @@
+-- Migration: Completion journal of bucket list items (SQLite)
+-- Created: 2026-10-18
+
+-- What a group records about a completed item is kept next to
+-- completed_at and cleared with it. occurred_on holds YYYY-MM-DD dates.
+ALTER TABLE bucket_items ADD COLUMN completion_note TEXT CHECK (length(completion_note) <= 2000);
+ALTER TABLE bucket_items ADD COLUMN completion_rating INTEGER CHECK (completion_rating BETWEEN 1 AND 5);
+ALTER TABLE bucket_items ADD COLUMN occurred_on TEXT CHECK (occurred_on = date(occurred_on));
@@ -0,0 +1,3 @@
+-- Revert: Item assignment (SQLite)
+
+DROP TABLE IF EXISTS item_assignees;
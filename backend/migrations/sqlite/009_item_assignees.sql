@@ -0,0 +1,13 @@
+-- Migration: Item assignment (SQLite)
+-- Created: 2026-10-18
+
+-- An item is assigned to any number of members of its group, each at most
+-- once. Deleting the item or the member removes the assignment.
+CREATE TABLE IF NOT EXISTS item_assignees (
+    item_id TEXT NOT NULL REFERENCES bucket_items(id) ON DELETE CASCADE,
+    member_id TEXT NOT NULL REFERENCES members(id) ON DELETE CASCADE,
+    assigned_at TEXT NOT NULL,
+    PRIMARY KEY (item_id, member_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_assignees_member_id ON item_assignees (member_id);
@@ -0,0 +1,5 @@
+-- Revert: Scheduled start and end of bucket list items (SQLite)
+
+DROP INDEX IF EXISTS idx_bucket_items_group_scheduled_start;
+ALTER TABLE bucket_items DROP COLUMN scheduled_end;
+ALTER TABLE bucket_items DROP COLUMN scheduled_start;
@@ -0,0 +1,10 @@
+-- Migration: Scheduled start and end of bucket list items (SQLite)
+-- Created: 2026-10-18
+
+-- An item may be planned for a point in time or a span. An end needs a
+-- start; the order of the two is checked by the application.
+ALTER TABLE bucket_items ADD COLUMN scheduled_start TEXT;
+ALTER TABLE bucket_items ADD COLUMN scheduled_end TEXT CHECK (scheduled_end IS NULL OR scheduled_start IS NOT NULL);
+
+CREATE INDEX IF NOT EXISTS idx_bucket_items_group_scheduled_start
+    ON bucket_items (group_id, scheduled_start) WHERE scheduled_start IS NOT NULL;
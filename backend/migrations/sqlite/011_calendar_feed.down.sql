@@ -0,0 +1,4 @@
+-- Revert: Calendar feed tokens of groups (SQLite)
+
+DROP INDEX IF EXISTS idx_groups_calendar_token_hash;
+ALTER TABLE groups DROP COLUMN calendar_token_hash;
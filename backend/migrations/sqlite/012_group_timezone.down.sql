@@ -0,0 +1,3 @@
+-- Revert: Timezone of groups (SQLite)
+
+ALTER TABLE groups DROP COLUMN timezone;
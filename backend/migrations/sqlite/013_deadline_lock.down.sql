@@ -0,0 +1,4 @@
+-- Revert: Locking lists after their deadline (SQLite)
+
+DROP INDEX IF EXISTS idx_groups_deadline;
+ALTER TABLE groups DROP COLUMN lock_after_deadline;
@@ -0,0 +1,10 @@
+-- Migration: Locking lists after their deadline (SQLite)
+-- Created: 2026-10-18
+
+-- Whether a group's list becomes read-only once its deadline passes. The
+-- lock is derived from the deadline when items change, so nothing needs to
+-- run when it passes.
+ALTER TABLE groups ADD COLUMN lock_after_deadline INTEGER NOT NULL DEFAULT 0;
+
+-- Finds the groups whose deadline is coming up or has just passed
+CREATE INDEX IF NOT EXISTS idx_groups_deadline ON groups (deadline) WHERE deadline IS NOT NULL;
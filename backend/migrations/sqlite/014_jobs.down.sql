@@ -0,0 +1,3 @@
+-- Revert: Background job queue (SQLite)
+
+DROP TABLE IF EXISTS jobs;
@@ -0,0 +1,36 @@
+-- Migration: Background job queue (SQLite)
+-- Created: 2026-10-18
+
+-- Background jobs. SQLite has a single writer, so claiming a job is one
+-- UPDATE that no other worker can interleave with. A job that fails
+-- max_attempts times is dead and stays here, as the dead letter queue,
+-- until it is requeued.
+CREATE TABLE IF NOT EXISTS jobs (
+    id TEXT PRIMARY KEY,
+    kind TEXT NOT NULL,
+    args TEXT NOT NULL DEFAULT '{}',
+    state TEXT NOT NULL DEFAULT 'scheduled'
+        CHECK (state IN ('scheduled', 'running', 'completed', 'dead')),
+    unique_key TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0 CHECK (attempts >= 0),
+    max_attempts INTEGER NOT NULL CHECK (max_attempts > 0),
+    run_at TEXT NOT NULL,
+    locked_by TEXT,
+    locked_until TEXT,
+    last_error TEXT,
+    created_at TEXT NOT NULL,
+    finished_at TEXT
+);
+
+-- A unique key belongs to one job of a kind until that job is pruned
+CREATE UNIQUE INDEX IF NOT EXISTS idx_jobs_unique_key
+    ON jobs (kind, unique_key) WHERE unique_key IS NOT NULL;
+
+-- Runnable jobs in the order workers claim them
+CREATE INDEX IF NOT EXISTS idx_jobs_scheduled ON jobs (run_at, id) WHERE state = 'scheduled';
+
+-- Running jobs whose lock has expired
+CREATE INDEX IF NOT EXISTS idx_jobs_running ON jobs (locked_until) WHERE state = 'running';
+
+-- Finished jobs, to prune completed ones and list dead ones
+CREATE INDEX IF NOT EXISTS idx_jobs_finished ON jobs (state, finished_at) WHERE finished_at IS NOT NULL;
@@ -0,0 +1,3 @@
+-- Revert: Deadline reminder schedules (SQLite)
+
+DROP TABLE IF EXISTS group_reminders;
@@ -0,0 +1,16 @@
+-- Migration: Deadline reminder schedules (SQLite)
+-- Created: 2026-10-18
+
+-- The days before its deadline a group's members are reminded of it. The
+-- reminders themselves are background jobs whose unique keys keep them
+-- from being sent twice.
+CREATE TABLE IF NOT EXISTS group_reminders (
+    group_id TEXT NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    days_before INTEGER NOT NULL CHECK (days_before BETWEEN 1 AND 365),
+    PRIMARY KEY (group_id, days_before)
+);
+
+-- Existing groups get the default schedule
+INSERT OR IGNORE INTO group_reminders (group_id, days_before)
+SELECT g.id, d.days_before
+FROM groups g CROSS JOIN (SELECT 30 AS days_before UNION ALL SELECT 7 UNION ALL SELECT 1) AS d;
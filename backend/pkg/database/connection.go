@@ -9,35 +9,65 @@ import (
 	"time"
 
 	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+// Storage drivers selectable with the STORAGE environment variable
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	// DriverMemory keeps data in process memory and opens no database
+	DriverMemory = "memory"
 )
 
 // DB holds the database connection
 var DB *sql.DB
 
+// driver is the driver DB was opened with
+var driver string
+
 // Config holds database configuration
 type Config struct {
+	Driver   string
 	Host     string
 	Port     string
 	User     string
 	Password string
 	DBName   string
 	SSLMode  string
+	// SQLitePath is the database file used by the sqlite driver
+	SQLitePath string
 }
 
 // LoadConfigFromEnv loads database configuration from environment variables
 func LoadConfigFromEnv() *Config {
 	return &Config{
+		Driver:   getEnvOrDefault("STORAGE", DriverPostgres),
 		Host:     getEnvOrDefault("DB_HOST", "localhost"),
 		Port:     getEnvOrDefault("DB_PORT", "5432"),
 		User:     getEnvOrDefault("DB_USER", "postgres"),
 		Password: getEnvOrDefault("DB_PASSWORD", ""),
 		DBName:   getEnvOrDefault("DB_NAME", "collaborative_bucket_list"),
 		SSLMode:  getEnvOrDefault("DB_SSL_MODE", "disable"),
+
+		SQLitePath: getEnvOrDefault("SQLITE_PATH", "bucket_list.db"),
 	}
 }
 
-// Connect establishes a connection to the PostgreSQL database
+// Connect establishes a connection to the database selected by config.Driver
 func Connect(config *Config) error {
+	switch config.Driver {
+	case DriverPostgres, "":
+		return connectPostgres(config)
+	case DriverSQLite:
+		return connectSQLite(config)
+	default:
+		return fmt.Errorf("unsupported database driver %q", config.Driver)
+	}
+}
+
+// connectPostgres establishes a connection to the PostgreSQL database
+func connectPostgres(config *Config) error {
 	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode)
 
@@ -46,6 +76,7 @@ func Connect(config *Config) error {
 	if err != nil {
 		return fmt.Errorf("failed to open database connection: %w", err)
 	}
+	driver = DriverPostgres
 
 	// Configure connection pool
 	DB.SetMaxOpenConns(25)
@@ -61,82 +92,47 @@ func Connect(config *Config) error {
 	return nil
 }
 
-// Close closes the database connection
-func Close() error {
-	if DB != nil {
-		return DB.Close()
+// connectSQLite opens the SQLite database file, creating it if needed
+func connectSQLite(config *Config) error {
+	var err error
+	DB, err = OpenSQLite(config.SQLitePath)
+	if err != nil {
+		return err
 	}
-	return nil
-}
+	driver = DriverSQLite
 
-// RunMigrations executes SQL migration files in order
-func RunMigrations(migrationDir string) error {
-	if DB == nil {
-		return fmt.Errorf("database connection not established")
+	if err = DB.Ping(); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	// Create migrations table if it doesn't exist
-	createMigrationsTable := `
-		CREATE TABLE IF NOT EXISTS migrations (
-			id SERIAL PRIMARY KEY,
-			filename VARCHAR(255) NOT NULL UNIQUE,
-			executed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
-		);
-	`
-	
-	if _, err := DB.Exec(createMigrationsTable); err != nil {
-		return fmt.Errorf("failed to create migrations table: %w", err)
-	}
+	log.Printf("Successfully connected to SQLite database %s", config.SQLitePath)
+	return nil
+}
 
-	// Read migration files
-	migrationFiles := []string{
-		"001_create_tables.sql",
+// OpenSQLite opens a SQLite database with the settings the repositories rely
+// on: foreign keys enforced on every connection, WAL journaling, a busy
+// timeout, and write transactions that take the write lock when they begin
+// rather than failing to upgrade a read lock later. SQLite allows a single writer, so the pool is limited to one
+// connection and concurrent requests queue in database/sql instead of
+// failing with SQLITE_BUSY.
+func OpenSQLite(path string) (*sql.DB, error) {
+	dsn := "file:" + path +
+		"?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_txlock=immediate"
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
+	db.SetMaxOpenConns(1)
 
-	for _, filename := range migrationFiles {
-		// Check if migration has already been executed
-		var count int
-		err := DB.QueryRow("SELECT COUNT(*) FROM migrations WHERE filename = $1", filename).Scan(&count)
-		if err != nil {
-			return fmt.Errorf("failed to check migration status for %s: %w", filename, err)
-		}
-
-		if count > 0 {
-			log.Printf("Migration %s already executed, skipping", filename)
-			continue
-		}
-
-		// Read and execute migration file
-		migrationPath := fmt.Sprintf("%s/%s", migrationDir, filename)
-		content, err := os.ReadFile(migrationPath)
-		if err != nil {
-			return fmt.Errorf("failed to read migration file %s: %w", filename, err)
-		}
-
-		// Execute migration in a transaction
-		tx, err := DB.Begin()
-		if err != nil {
-			return fmt.Errorf("failed to begin transaction for migration %s: %w", filename, err)
-		}
-
-		if _, err = tx.Exec(string(content)); err != nil {
-			tx.Rollback()
-			return fmt.Errorf("failed to execute migration %s: %w", filename, err)
-		}
-
-		// Record migration as executed
-		if _, err = tx.Exec("INSERT INTO migrations (filename) VALUES ($1)", filename); err != nil {
-			tx.Rollback()
-			return fmt.Errorf("failed to record migration %s: %w", filename, err)
-		}
-
-		if err = tx.Commit(); err != nil {
-			return fmt.Errorf("failed to commit migration %s: %w", filename, err)
-		}
-
-		log.Printf("Successfully executed migration: %s", filename)
-	}
+	return db, nil
+}
 
+// Close closes the database connection
+func Close() error {
+	if DB != nil {
+		return DB.Close()
+	}
 	return nil
 }
 
@@ -52,6 +52,8 @@ func TestLoadConfigFromEnvDefaults(t *testing.T) {
 	os.Unsetenv("DB_PASSWORD")
 	os.Unsetenv("DB_NAME")
 	os.Unsetenv("DB_SSL_MODE")
+	os.Unsetenv("STORAGE")
+	os.Unsetenv("SQLITE_PATH")
 
 	config := LoadConfigFromEnv()
 
@@ -73,4 +75,10 @@ func TestLoadConfigFromEnvDefaults(t *testing.T) {
 	if config.SSLMode != "disable" {
 		t.Errorf("Expected default SSLMode to be 'disable', got '%s'", config.SSLMode)
 	}
+	if config.Driver != DriverPostgres {
+		t.Errorf("Expected default Driver to be 'postgres', got '%s'", config.Driver)
+	}
+	if config.SQLitePath != "bucket_list.db" {
+		t.Errorf("Expected default SQLitePath to be 'bucket_list.db', got '%s'", config.SQLitePath)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,527 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationLockID is the key of the advisory lock held while migrating, so
+// replicas starting at the same time apply migrations one at a time
+const migrationLockID int64 = 0x6275636b65746c69 // "bucketli"
+
+// migrationFilePattern matches NNN_description.sql and NNN_description.down.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+?)(\.down)?\.sql$`)
+
+// Migration is one schema migration loaded from the migrations directory
+type Migration struct {
+	Version  int
+	Name     string
+	Filename string
+	Up       string
+	// Down reverts the migration; empty if it has no down file
+	Down     string
+	Checksum string
+}
+
+// MigrationStatus describes a migration and whether it has been applied
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Filename  string
+	Applied   bool
+	AppliedAt *time.Time
+	// Modified is set when the file changed after it was applied
+	Modified bool
+	// Missing is set for applied migrations whose file no longer exists
+	Missing bool
+	HasDown bool
+}
+
+// appliedMigration is a row of the migrations table
+type appliedMigration struct {
+	filename   string
+	checksum   sql.NullString
+	executedAt migrationTime
+}
+
+// migrationTime scans executed_at, which SQLite stores as RFC 3339 text
+type migrationTime time.Time
+
+// Scan implements sql.Scanner
+func (t *migrationTime) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case time.Time:
+		*t = migrationTime(v)
+	case string:
+		return t.parse(v)
+	case []byte:
+		return t.parse(string(v))
+	default:
+		return fmt.Errorf("cannot scan %T into migration time", value)
+	}
+	return nil
+}
+
+func (t *migrationTime) parse(value string) error {
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return fmt.Errorf("invalid migration time %q: %w", value, err)
+	}
+	*t = migrationTime(parsed)
+	return nil
+}
+
+// migrationDialect holds the bookkeeping SQL that differs between drivers
+type migrationDialect struct {
+	createTable    string
+	lock           string
+	unlock         string
+	record         string
+	forget         string
+	recordChecksum string
+}
+
+var postgresMigrationDialect = migrationDialect{
+	// The checksum column is added separately for tables created before
+	// checksums were tracked
+	createTable: `
+		CREATE TABLE IF NOT EXISTS migrations (
+			id SERIAL PRIMARY KEY,
+			filename VARCHAR(255) NOT NULL UNIQUE,
+			executed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		ALTER TABLE migrations ADD COLUMN IF NOT EXISTS checksum VARCHAR(64);
+	`,
+	lock:           "SELECT pg_advisory_lock($1)",
+	unlock:         "SELECT pg_advisory_unlock($1)",
+	record:         "INSERT INTO migrations (filename, checksum) VALUES ($1, $2)",
+	forget:         "DELETE FROM migrations WHERE filename = $1",
+	recordChecksum: "UPDATE migrations SET checksum = $1 WHERE filename = $2",
+}
+
+// SQLite has no advisory locks; its file lock already serializes writers
+var sqliteMigrationDialect = migrationDialect{
+	createTable: `
+		CREATE TABLE IF NOT EXISTS migrations (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			filename TEXT NOT NULL UNIQUE,
+			executed_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+			checksum TEXT
+		);
+	`,
+	record:         "INSERT INTO migrations (filename, checksum) VALUES (?, ?)",
+	forget:         "DELETE FROM migrations WHERE filename = ?",
+	recordChecksum: "UPDATE migrations SET checksum = ? WHERE filename = ?",
+}
+
+// Migrator applies and reverts migrations against a database
+type Migrator struct {
+	db         *sql.DB
+	dialect    migrationDialect
+	migrations []Migration
+}
+
+// NewMigrator creates a PostgreSQL migrator for the migrations found in fsys
+func NewMigrator(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	return newMigrator(db, postgresMigrationDialect, fsys)
+}
+
+// NewSQLiteMigrator creates a SQLite migrator for the migrations found in fsys
+func NewSQLiteMigrator(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	return newMigrator(db, sqliteMigrationDialect, fsys)
+}
+
+// NewMigratorForDriver creates a migrator for the given database driver
+func NewMigratorForDriver(driver string, db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	switch driver {
+	case DriverPostgres:
+		return NewMigrator(db, fsys)
+	case DriverSQLite:
+		return NewSQLiteMigrator(db, fsys)
+	default:
+		return nil, fmt.Errorf("migrations are not supported for driver %q", driver)
+	}
+}
+
+func newMigrator(db *sql.DB, dialect migrationDialect, fsys fs.FS) (*Migrator, error) {
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}, nil
+}
+
+// RunMigrations verifies the applied migrations and applies any pending ones
+// on the connection opened by Connect
+func RunMigrations(fsys fs.FS) error {
+	if DB == nil {
+		return fmt.Errorf("database connection not established")
+	}
+
+	migrator, err := NewMigratorForDriver(driver, DB, fsys)
+	if err != nil {
+		return err
+	}
+	return migrator.Up(context.Background())
+}
+
+// LoadMigrations reads the migrations in the root of fsys, ordered by version
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	downs := make(map[int]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		filename := entry.Name()
+		match := migrationFilePattern.FindStringSubmatch(filename)
+		if match == nil {
+			if strings.HasSuffix(filename, ".sql") {
+				return nil, fmt.Errorf("invalid migration filename %s: expected NNN_description.sql", filename)
+			}
+			continue
+		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", filename, err)
+		}
+		content, err := fs.ReadFile(fsys, filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration file %s: %w", filename, err)
+		}
+
+		if match[3] != "" {
+			if _, exists := downs[version]; exists {
+				return nil, fmt.Errorf("duplicate down migration for version %d", version)
+			}
+			downs[version] = string(content)
+			continue
+		}
+
+		if existing, exists := byVersion[version]; exists {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, existing.Filename, filename)
+		}
+		byVersion[version] = &Migration{
+			Version:  version,
+			Name:     match[2],
+			Filename: filename,
+			Up:       string(content),
+			Checksum: checksum(content),
+		}
+	}
+
+	for version, down := range downs {
+		migration, exists := byVersion[version]
+		if !exists {
+			return nil, fmt.Errorf("down migration for version %d has no matching up migration", version)
+		}
+		migration.Down = down
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// checksum returns the hex SHA-256 of a migration file
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Up applies all pending migrations in order. Applied migrations are first
+// checked against their recorded checksums; a modified file is an error.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.verify(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		pending := 0
+		for _, migration := range m.migrations {
+			if _, done := applied[migration.Filename]; done {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+			pending++
+		}
+
+		if pending == 0 {
+			log.Println("Database schema is up to date")
+		}
+		return nil
+	})
+}
+
+// Down reverts the most recently applied migrations, newest first
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("steps must be at least 1")
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+			migration := m.migrations[i]
+			if _, done := applied[migration.Filename]; !done {
+				continue
+			}
+			if err := m.revert(ctx, conn, migration); err != nil {
+				return err
+			}
+			steps--
+		}
+		return nil
+	})
+}
+
+// Redo reverts and reapplies the most recently applied migration
+func (m *Migrator) Redo(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, done := applied[migration.Filename]; !done {
+				continue
+			}
+			if err := m.revert(ctx, conn, migration); err != nil {
+				return err
+			}
+			return m.apply(ctx, conn, migration)
+		}
+		return fmt.Errorf("no applied migrations to redo")
+	})
+}
+
+// Status lists every known migration, plus applied migrations whose files
+// are missing, ordered by version
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.ensureMigrationsTable(ctx, m.db); err != nil {
+		return nil, err
+	}
+	applied, err := loadApplied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{
+			Version:  migration.Version,
+			Name:     migration.Name,
+			Filename: migration.Filename,
+			HasDown:  migration.Down != "",
+		}
+		if record, done := applied[migration.Filename]; done {
+			appliedAt := time.Time(record.executedAt)
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			status.Modified = record.checksum.Valid && record.checksum.String != migration.Checksum
+			delete(applied, migration.Filename)
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, record := range applied {
+		appliedAt := time.Time(record.executedAt)
+		status := MigrationStatus{Filename: record.filename, Applied: true, AppliedAt: &appliedAt, Missing: true}
+		if match := migrationFilePattern.FindStringSubmatch(record.filename); match != nil {
+			status.Version, _ = strconv.Atoi(match[1])
+			status.Name = match[2]
+		}
+		statuses = append(statuses, status)
+	}
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+// withLock runs fn on a dedicated connection holding the migration lock.
+// Advisory locks belong to a session, so all work must use that connection.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	defer conn.Close()
+
+	if m.dialect.lock != "" {
+		if _, err := conn.ExecContext(ctx, m.dialect.lock, migrationLockID); err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		defer func() {
+			// Use a fresh context so the lock is released even after cancellation
+			if _, err := conn.ExecContext(context.Background(), m.dialect.unlock, migrationLockID); err != nil {
+				log.Printf("Failed to release migration lock: %v", err)
+			}
+		}()
+	}
+
+	if err := m.ensureMigrationsTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// verify checks applied migrations against their files. Migrations recorded
+// before checksums were tracked get their checksum filled in.
+func (m *Migrator) verify(ctx context.Context, conn *sql.Conn) (map[string]appliedMigration, error) {
+	applied, err := loadApplied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Filename] = true
+		record, done := applied[migration.Filename]
+		if !done {
+			continue
+		}
+
+		if !record.checksum.Valid {
+			if _, err := conn.ExecContext(ctx, m.dialect.recordChecksum,
+				migration.Checksum, migration.Filename); err != nil {
+				return nil, fmt.Errorf("failed to record checksum for migration %s: %w", migration.Filename, err)
+			}
+			log.Printf("Recorded checksum for previously applied migration %s", migration.Filename)
+			continue
+		}
+
+		if record.checksum.String != migration.Checksum {
+			return nil, fmt.Errorf("migration %s has been modified since it was applied (checksum %s, applied %s)",
+				migration.Filename, migration.Checksum, record.checksum.String)
+		}
+	}
+
+	for filename := range applied {
+		if !known[filename] {
+			log.Printf("Warning: applied migration %s has no migration file", filename)
+		}
+	}
+	return applied, nil
+}
+
+// apply runs an up migration and records it in one transaction
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction for migration %s: %w", migration.Filename, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, migration.Up); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to execute migration %s: %w", migration.Filename, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, m.dialect.record,
+		migration.Filename, migration.Checksum); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to record migration %s: %w", migration.Filename, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %s: %w", migration.Filename, err)
+	}
+
+	log.Printf("Successfully executed migration: %s", migration.Filename)
+	return nil
+}
+
+// revert runs a down migration and removes its record in one transaction
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	if migration.Down == "" {
+		return fmt.Errorf("migration %s has no down migration", migration.Filename)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction for migration %s: %w", migration.Filename, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, migration.Down); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to revert migration %s: %w", migration.Filename, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, m.dialect.forget, migration.Filename); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to remove record of migration %s: %w", migration.Filename, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit revert of migration %s: %w", migration.Filename, err)
+	}
+
+	log.Printf("Successfully reverted migration: %s", migration.Filename)
+	return nil
+}
+
+// queryExecer is satisfied by both *sql.DB and *sql.Conn
+type queryExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// ensureMigrationsTable creates the migrations table if it does not exist
+func (m *Migrator) ensureMigrationsTable(ctx context.Context, db queryExecer) error {
+	if _, err := db.ExecContext(ctx, m.dialect.createTable); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+	return nil
+}
+
+// loadApplied returns the recorded migrations keyed by filename
+func loadApplied(ctx context.Context, db queryExecer) (map[string]appliedMigration, error) {
+	rows, err := db.QueryContext(ctx, "SELECT filename, checksum, executed_at FROM migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[string]appliedMigration)
+	for rows.Next() {
+		var record appliedMigration
+		if err := rows.Scan(&record.filename, &record.checksum, &record.executedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied[record.filename] = record
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load applied migrations: %w", err)
+	}
+	return applied, nil
+}
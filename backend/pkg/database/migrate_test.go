@@ -0,0 +1,209 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"collaborative-bucket-list/migrations"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"002_add_notes.sql":          {Data: []byte("ALTER TABLE things ADD COLUMN notes TEXT;")},
+		"001_create_things.sql":      {Data: []byte("CREATE TABLE things (id INT);")},
+		"001_create_things.down.sql": {Data: []byte("DROP TABLE things;")},
+		"README.md":                  {Data: []byte("not a migration")},
+	}
+
+	loaded, err := LoadMigrations(fsys)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+
+	assert.Equal(t, 1, loaded[0].Version)
+	assert.Equal(t, "create_things", loaded[0].Name)
+	assert.Equal(t, "001_create_things.sql", loaded[0].Filename)
+	assert.Equal(t, "DROP TABLE things;", loaded[0].Down)
+	assert.Equal(t, checksum([]byte("CREATE TABLE things (id INT);")), loaded[0].Checksum)
+
+	assert.Equal(t, 2, loaded[1].Version)
+	assert.Empty(t, loaded[1].Down)
+}
+
+func TestLoadMigrations_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{
+			name: "duplicate version",
+			fsys: fstest.MapFS{
+				"001_first.sql":  {Data: []byte("SELECT 1;")},
+				"001_second.sql": {Data: []byte("SELECT 2;")},
+			},
+		},
+		{
+			name: "down without up",
+			fsys: fstest.MapFS{
+				"001_first.down.sql": {Data: []byte("SELECT 1;")},
+			},
+		},
+		{
+			name: "malformed filename",
+			fsys: fstest.MapFS{
+				"create_things.sql": {Data: []byte("SELECT 1;")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadMigrations(tt.fsys)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	for _, driver := range []string{DriverPostgres, DriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			loaded, err := LoadMigrations(migrations.ForDriver(driver))
+			require.NoError(t, err)
+			require.NotEmpty(t, loaded)
+
+			for i, migration := range loaded {
+				assert.Equal(t, i+1, migration.Version, "migration versions must be sequential")
+				assert.NotEmpty(t, migration.Up, migration.Filename)
+			}
+		})
+	}
+}
+
+// setupMigrationTestDB connects to the test database with a private schema,
+// so migrations under test do not touch the application tables
+func setupMigrationTestDB(t *testing.T) *sql.DB {
+	schema := fmt.Sprintf("migrate_test_%d", time.Now().UnixNano())
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		getEnvOrDefault("TEST_DB_HOST", "localhost"),
+		getEnvOrDefault("TEST_DB_PORT", "5432"),
+		getEnvOrDefault("TEST_DB_USER", "postgres"),
+		getEnvOrDefault("TEST_DB_PASSWORD", "postgres"),
+		getEnvOrDefault("TEST_DB_NAME", "collaborative_bucket_list"),
+		getEnvOrDefault("TEST_DB_SSL_MODE", "disable"))
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Skipf("Skipping database tests: %v", err)
+	}
+	if err := admin.Ping(); err != nil {
+		admin.Close()
+		t.Skipf("Skipping database tests: database not available: %v", err)
+	}
+	_, err = admin.Exec("CREATE SCHEMA " + schema)
+	require.NoError(t, err)
+
+	db, err := sql.Open("postgres", dsn+" search_path="+schema)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		db.Close()
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		admin.Close()
+	})
+	return db
+}
+
+func TestMigrator(t *testing.T) {
+	runMigratorTests(t, setupMigrationTestDB(t), NewMigrator)
+}
+
+func TestSQLiteMigrator(t *testing.T) {
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "migrate.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	runMigratorTests(t, db, NewSQLiteMigrator)
+}
+
+// runMigratorTests exercises a migrator against an empty database
+func runMigratorTests(t *testing.T, db *sql.DB, newMigrator func(db *sql.DB, fsys fs.FS) (*Migrator, error)) {
+	ctx := context.Background()
+
+	fsys := fstest.MapFS{
+		"001_create_things.sql":      {Data: []byte("CREATE TABLE things (id INT);")},
+		"001_create_things.down.sql": {Data: []byte("DROP TABLE things;")},
+		"002_add_notes.sql":          {Data: []byte("ALTER TABLE things ADD COLUMN notes TEXT;")},
+		"002_add_notes.down.sql":     {Data: []byte("ALTER TABLE things DROP COLUMN notes;")},
+	}
+	migrator, err := newMigrator(db, fsys)
+	require.NoError(t, err)
+
+	t.Run("up applies pending migrations", func(t *testing.T) {
+		require.NoError(t, migrator.Up(ctx))
+		_, err := db.Exec("INSERT INTO things (id, notes) VALUES (1, 'hello')")
+		assert.NoError(t, err)
+
+		// Running again is a no-op
+		require.NoError(t, migrator.Up(ctx))
+
+		statuses, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		require.Len(t, statuses, 2)
+		for _, status := range statuses {
+			assert.True(t, status.Applied)
+			assert.False(t, status.Modified)
+			assert.NotNil(t, status.AppliedAt)
+		}
+	})
+
+	t.Run("down and redo", func(t *testing.T) {
+		require.NoError(t, migrator.Down(ctx, 1))
+		statuses, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		assert.True(t, statuses[0].Applied)
+		assert.False(t, statuses[1].Applied)
+
+		require.NoError(t, migrator.Up(ctx))
+		require.NoError(t, migrator.Redo(ctx))
+		statuses, err = migrator.Status(ctx)
+		require.NoError(t, err)
+		assert.True(t, statuses[1].Applied)
+	})
+
+	t.Run("modified migration is rejected", func(t *testing.T) {
+		modified := fstest.MapFS{}
+		for name, file := range fsys {
+			modified[name] = file
+		}
+		modified["001_create_things.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE things (id BIGINT);")}
+
+		modifiedMigrator, err := newMigrator(db, modified)
+		require.NoError(t, err)
+		err = modifiedMigrator.Up(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "has been modified")
+
+		statuses, err := modifiedMigrator.Status(ctx)
+		require.NoError(t, err)
+		assert.True(t, statuses[0].Modified)
+	})
+
+	t.Run("migration without down file cannot be reverted", func(t *testing.T) {
+		upOnly := fstest.MapFS{
+			"001_create_things.sql":      fsys["001_create_things.sql"],
+			"001_create_things.down.sql": fsys["001_create_things.down.sql"],
+			"002_add_notes.sql":          fsys["002_add_notes.sql"],
+		}
+		upOnlyMigrator, err := newMigrator(db, upOnly)
+		require.NoError(t, err)
+		assert.Error(t, upOnlyMigrator.Down(ctx, 1))
+	})
+}
@@ -0,0 +1,167 @@
+// Package ical writes iCalendar (RFC 5545) feeds that calendar apps such
+// as Google Calendar and Apple Calendar can subscribe to.
+//
+// Only what a read-only feed of events needs is supported: a VCALENDAR of
+// VEVENTs with UTC times or all-day dates. Text is escaped and long lines are folded as the
+// RFC requires, and the output depends only on its input, so an unchanged
+// calendar always encodes to the same bytes.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// dateTimeLayout formats a DATE-TIME in UTC
+const dateTimeLayout = "20060102T150405Z"
+
+// dateLayout formats a DATE
+const dateLayout = "20060102"
+
+// maxLineOctets is the longest a content line may be, excluding its CRLF
+const maxLineOctets = 75
+
+// Calendar is a feed of events
+type Calendar struct {
+	// ProdID identifies the product that created the calendar, like
+	// "-//Example Corp//Example App//EN"
+	ProdID string
+	// Name is the calendar's display name
+	Name string
+	// Timezone is the IANA name of the zone to show the calendar in. Event
+	// times are always in UTC; this is only a hint to the app.
+	Timezone string
+	// RefreshInterval suggests how often subscribers poll the feed; zero
+	// leaves it to the app
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Event is a VEVENT. An event without an End occupies the instant it
+// starts, or the day it starts if it is AllDay.
+type Event struct {
+	// UID identifies the event across every version of the feed, so apps
+	// update it in place instead of duplicating it
+	UID string
+	// Stamp is when the event's information was last revised
+	Stamp time.Time
+	Start time.Time
+	End   time.Time
+	// AllDay events last from the date of Start to the date of End, each
+	// in its own location, with End exclusive
+	AllDay      bool
+	Summary     string
+	Description string
+	URL         string
+}
+
+// Marshal encodes the calendar
+func (c *Calendar) Marshal() []byte {
+	var w writer
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.property("PRODID", c.ProdID)
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		w.text("NAME", c.Name)
+		w.text("X-WR-CALNAME", c.Name)
+	}
+	if c.Timezone != "" {
+		w.property("X-WR-TIMEZONE", c.Timezone)
+	}
+	if c.RefreshInterval > 0 {
+		interval := duration(c.RefreshInterval)
+		w.property("REFRESH-INTERVAL;VALUE=DURATION", interval)
+		w.property("X-PUBLISHED-TTL", interval)
+	}
+
+	for _, event := range c.Events {
+		w.line("BEGIN:VEVENT")
+		w.text("UID", event.UID)
+		w.property("DTSTAMP", formatTime(event.Stamp))
+		if event.AllDay {
+			w.property("DTSTART;VALUE=DATE", event.Start.Format(dateLayout))
+			if !event.End.IsZero() {
+				w.property("DTEND;VALUE=DATE", event.End.Format(dateLayout))
+			}
+		} else {
+			w.property("DTSTART", formatTime(event.Start))
+			if !event.End.IsZero() {
+				w.property("DTEND", formatTime(event.End))
+			}
+		}
+		w.text("SUMMARY", event.Summary)
+		if event.Description != "" {
+			w.text("DESCRIPTION", event.Description)
+		}
+		if event.URL != "" {
+			w.property("URL", event.URL)
+		}
+		w.line("END:VEVENT")
+	}
+
+	w.line("END:VCALENDAR")
+	return w.buf.Bytes()
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+// duration formats d as a DURATION in whole minutes, rounding up
+func duration(d time.Duration) string {
+	minutes := (d + time.Minute - 1) / time.Minute
+	return fmt.Sprintf("PT%dM", minutes)
+}
+
+// escapeText escapes a TEXT value
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// writer writes content lines
+type writer struct {
+	buf bytes.Buffer
+}
+
+// text writes a property with a TEXT value
+func (w *writer) text(name, value string) {
+	w.property(name, escapeText(value))
+}
+
+// property writes a property whose value is already encoded
+func (w *writer) property(name, value string) {
+	w.line(name + ":" + value)
+}
+
+// line writes a content line, folding it after every 75 octets without
+// splitting a UTF-8 sequence
+func (w *writer) line(s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.buf.WriteString(s[:cut])
+		w.buf.WriteString("\r\n ")
+		s = s[cut:]
+		// The space starting a continuation line counts toward its length
+		limit = maxLineOctets - 1
+	}
+	w.buf.WriteString(s)
+	w.buf.WriteString("\r\n")
+}
@@ -0,0 +1,123 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendarMarshal(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	stamp := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	calendar := Calendar{
+		ProdID:          "-//Test//Test//EN",
+		Name:            "Summer, 2026",
+		Timezone:        "Asia/Tokyo",
+		RefreshInterval: time.Hour,
+		Events: []Event{
+			{
+				UID:         "deadline@test",
+				Stamp:       stamp,
+				Start:       time.Date(2026, 9, 1, 9, 0, 0, 0, tokyo),
+				Summary:     "Deadline",
+				Description: "Line one\nLine two; with a \\",
+			},
+			{
+				UID:     "holiday@test",
+				Stamp:   stamp,
+				Start:   time.Date(2026, 8, 10, 0, 0, 0, 0, tokyo),
+				End:     time.Date(2026, 8, 11, 0, 0, 0, 0, tokyo),
+				AllDay:  true,
+				Summary: "Holiday",
+			},
+			{
+				UID:     "item@test",
+				Stamp:   stamp,
+				Start:   time.Date(2026, 7, 4, 18, 0, 0, 0, time.UTC),
+				End:     time.Date(2026, 7, 4, 20, 0, 0, 0, time.UTC),
+				Summary: "Fireworks",
+				URL:     "https://example.com/groups/1",
+			},
+		},
+	}
+
+	expected := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Test//Test//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		`NAME:Summer\, 2026`,
+		`X-WR-CALNAME:Summer\, 2026`,
+		"X-WR-TIMEZONE:Asia/Tokyo",
+		"REFRESH-INTERVAL;VALUE=DURATION:PT60M",
+		"X-PUBLISHED-TTL:PT60M",
+		"BEGIN:VEVENT",
+		"UID:deadline@test",
+		"DTSTAMP:20260102T030405Z",
+		"DTSTART:20260901T000000Z",
+		"SUMMARY:Deadline",
+		`DESCRIPTION:Line one\nLine two\; with a \\`,
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:holiday@test",
+		"DTSTAMP:20260102T030405Z",
+		"DTSTART;VALUE=DATE:20260810",
+		"DTEND;VALUE=DATE:20260811",
+		"SUMMARY:Holiday",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:item@test",
+		"DTSTAMP:20260102T030405Z",
+		"DTSTART:20260704T180000Z",
+		"DTEND:20260704T200000Z",
+		"SUMMARY:Fireworks",
+		"URL:https://example.com/groups/1",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	assert.Equal(t, expected, string(calendar.Marshal()))
+	assert.Equal(t, calendar.Marshal(), calendar.Marshal())
+}
+
+func TestCalendarMarshal_FoldsLongLines(t *testing.T) {
+	summary := strings.Repeat("é", 100)
+	calendar := Calendar{
+		ProdID: "-//Test//Test//EN",
+		Events: []Event{{UID: "long@test", Summary: summary}},
+	}
+	encoded := string(calendar.Marshal())
+
+	var unfolded strings.Builder
+	for i, line := range strings.Split(strings.TrimSuffix(encoded, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineOctets, "line %d", i)
+		assert.True(t, utf8.ValidString(line), "line %d", i)
+		if strings.HasPrefix(line, " ") {
+			unfolded.WriteString(line[1:])
+		} else {
+			unfolded.WriteString("\n" + line)
+		}
+	}
+	require.Contains(t, unfolded.String(), "\nSUMMARY:"+summary+"\n")
+}
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"plain", "plain"},
+		{"a,b;c", `a\,b\;c`},
+		{`back\slash`, `back\\slash`},
+		{"one\r\ntwo\nthree", `one\ntwo\nthree`},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, escapeText(tt.input))
+	}
+}
@@ -0,0 +1,144 @@
+// Package imaging prepares uploaded photos for sharing, in pure Go. It
+// strips EXIF and other metadata that can leak where and when a photo was
+// taken, and renders thumbnails.
+//
+// JPEG, PNG, GIF and WebP are supported. Metadata is removed losslessly by
+// dropping the chunks that carry it, except that JPEG photos rotated by
+// their EXIF orientation are re-encoded upright, since dropping the tag
+// would otherwise turn them on their side.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // registers the GIF decoder
+	"image/jpeg"
+	_ "image/png" // registers the PNG decoder
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // registers the WebP decoder
+)
+
+// MaxPixels bounds the size of images that are decoded, so a small file
+// claiming huge dimensions cannot exhaust memory
+const MaxPixels = 50_000_000
+
+// JPEGQuality is the quality thumbnails and re-encoded photos are saved at
+const JPEGQuality = 85
+
+// ErrUnsupported is returned for content types that are not images this
+// package can process
+var ErrUnsupported = errors.New("unsupported image type")
+
+// ErrTooLarge is returned for images with more than MaxPixels pixels
+var ErrTooLarge = errors.New("image is too large")
+
+// ErrMalformed is returned for files that are not valid images of their
+// content type
+var ErrMalformed = errors.New("malformed image")
+
+// Image is a processed upload
+type Image struct {
+	// Original is the uploaded file without its metadata
+	Original []byte
+	// Decoded is the image as displayed, with any orientation applied
+	Decoded image.Image
+	// Width and Height are the displayed dimensions
+	Width  int
+	Height int
+}
+
+// Process strips the metadata of an image of the given content type and
+// decodes it
+func Process(data []byte, contentType string) (*Image, error) {
+	stripped, orientation, err := StripMetadata(data, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(stripped))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrTooLarge, config.Width, config.Height)
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(stripped))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	if orientation > 1 {
+		decoded = orient(decoded, orientation)
+		if stripped, err = EncodeJPEG(decoded); err != nil {
+			return nil, err
+		}
+	}
+
+	bounds := decoded.Bounds()
+	return &Image{
+		Original: stripped,
+		Decoded:  decoded,
+		Width:    bounds.Dx(),
+		Height:   bounds.Dy(),
+	}, nil
+}
+
+// StripMetadata removes EXIF, XMP, IPTC, comments and other text metadata
+// from an image without re-encoding it. Color profiles are kept. For JPEG
+// it also returns the EXIF orientation, 1 when there is none.
+func StripMetadata(data []byte, contentType string) ([]byte, int, error) {
+	switch contentType {
+	case "image/jpeg":
+		return stripJPEG(data)
+	case "image/png":
+		stripped, err := stripPNG(data)
+		return stripped, 1, err
+	case "image/gif":
+		stripped, err := stripGIF(data)
+		return stripped, 1, err
+	case "image/webp":
+		stripped, err := stripWebP(data)
+		return stripped, 1, err
+	default:
+		return nil, 0, fmt.Errorf("%w: %s", ErrUnsupported, contentType)
+	}
+}
+
+// Thumbnail scales an image to width by height pixels and encodes it as
+// JPEG. Transparent areas become white.
+func Thumbnail(img image.Image, width, height int) ([]byte, error) {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Over, nil)
+	return EncodeJPEG(dst)
+}
+
+// EncodeJPEG encodes an image as JPEG at JPEGQuality, flattening any
+// transparency onto white
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	if !opaque(img) {
+		flat := image.NewRGBA(img.Bounds())
+		draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+		img = flat
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: JPEGQuality}); err != nil {
+		return nil, fmt.Errorf("failed to encode JPEG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// opaque reports whether an image is known to have no transparency
+func opaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
@@ -0,0 +1,257 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testImage returns a width by height image whose top-left pixel is red and
+// the rest blue
+func testImage(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{B: 255, A: 255})
+		}
+	}
+	img.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	return img
+}
+
+// exifSegment builds a JPEG APP1 EXIF segment with an orientation tag and a
+// GPS-looking string the tests look for after stripping
+func exifSegment(orientation uint16) []byte {
+	tiff := []byte("II*\x00")
+	tiff = binary.LittleEndian.AppendUint32(tiff, 8)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 3)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, orientation)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 0)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 0)
+	tiff = append(tiff, "GPS 52.3676N 4.9041E"...)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, jpegAPP1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// encodeJPEG encodes img as JPEG with extra segments after the start of image
+func encodeJPEG(t *testing.T, img image.Image, segments ...[]byte) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+	data := buf.Bytes()
+
+	out := append([]byte{}, data[:2]...)
+	for _, segment := range segments {
+		out = append(out, segment...)
+	}
+	return append(out, data[2:]...)
+}
+
+// pngChunk builds a PNG chunk with its checksum
+func pngChunk(chunkType string, data []byte) []byte {
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	chunk = append(chunk, chunkType...)
+	chunk = append(chunk, data...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+// encodePNG encodes img as PNG with extra chunks after the header chunk
+func encodePNG(t *testing.T, img image.Image, chunks ...[]byte) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	data := buf.Bytes()
+
+	// The signature and the 25 byte IHDR chunk
+	headerEnd := len(pngSignature) + 25
+	out := append([]byte{}, data[:headerEnd]...)
+	for _, chunk := range chunks {
+		out = append(out, chunk...)
+	}
+	return append(out, data[headerEnd:]...)
+}
+
+func TestProcess_JPEG(t *testing.T) {
+	data := encodeJPEG(t, testImage(4, 2),
+		exifSegment(1),
+		append([]byte{0xFF, jpegCOM, 0x00, 0x0B}, "secret   "...),
+	)
+
+	processed, err := Process(data, "image/jpeg")
+	require.NoError(t, err)
+	assert.Equal(t, 4, processed.Width)
+	assert.Equal(t, 2, processed.Height)
+	assert.NotContains(t, string(processed.Original), "GPS")
+	assert.NotContains(t, string(processed.Original), "secret")
+
+	// Without rotation the scan data is kept as uploaded
+	stripped := encodeJPEG(t, testImage(4, 2))
+	assert.Equal(t, len(stripped), len(processed.Original))
+
+	_, err = jpeg.Decode(bytes.NewReader(processed.Original))
+	assert.NoError(t, err)
+}
+
+func TestProcess_JPEGOrientation(t *testing.T) {
+	// Rotated 90° clockwise for display
+	data := encodeJPEG(t, testImage(4, 2), exifSegment(6))
+
+	processed, err := Process(data, "image/jpeg")
+	require.NoError(t, err)
+	assert.Equal(t, 2, processed.Width)
+	assert.Equal(t, 4, processed.Height)
+	assert.NotContains(t, string(processed.Original), "GPS")
+
+	config, err := jpeg.DecodeConfig(bytes.NewReader(processed.Original))
+	require.NoError(t, err)
+	assert.Equal(t, 2, config.Width, "the original is stored upright")
+	assert.Equal(t, 4, config.Height)
+
+	_, orientation, err := StripMetadata(processed.Original, "image/jpeg")
+	require.NoError(t, err)
+	assert.Equal(t, 1, orientation)
+}
+
+func TestProcess_PNG(t *testing.T) {
+	data := encodePNG(t, testImage(3, 3),
+		pngChunk("tEXt", []byte("Author\x00Alice")),
+		pngChunk("tIME", []byte{0x07, 0xE8, 1, 2, 3, 4, 5}),
+	)
+
+	processed, err := Process(data, "image/png")
+	require.NoError(t, err)
+	assert.Equal(t, 3, processed.Width)
+	assert.NotContains(t, string(processed.Original), "Alice")
+	assert.NotContains(t, string(processed.Original), "tIME")
+	assert.Equal(t, encodePNG(t, testImage(3, 3)), processed.Original)
+}
+
+func TestProcess_GIF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, gif.Encode(&buf, testImage(5, 2), nil))
+	data := buf.Bytes()
+
+	// Insert a comment before the trailer
+	comment := []byte{gifExtension, gifComment, 6}
+	comment = append(comment, "secret"...)
+	comment = append(comment, 0)
+	withComment := append(append(append([]byte{}, data[:len(data)-1]...), comment...), gifTrailer)
+
+	processed, err := Process(withComment, "image/gif")
+	require.NoError(t, err)
+	assert.Equal(t, 5, processed.Width)
+	assert.Equal(t, 2, processed.Height)
+	assert.Equal(t, data, processed.Original)
+}
+
+func TestStripMetadata_WebP(t *testing.T) {
+	chunk := func(chunkType string, data []byte) []byte {
+		out := append([]byte(chunkType), binary.LittleEndian.AppendUint32(nil, uint32(len(data)))...)
+		out = append(out, data...)
+		if len(data)%2 == 1 {
+			out = append(out, 0)
+		}
+		return out
+	}
+	webp := func(chunks ...[]byte) []byte {
+		var body []byte
+		for _, c := range chunks {
+			body = append(body, c...)
+		}
+		out := append([]byte("RIFF"), binary.LittleEndian.AppendUint32(nil, uint32(len(body)+4))...)
+		out = append(out, "WEBP"...)
+		return append(out, body...)
+	}
+
+	vp8x := make([]byte, 10)
+	vp8x[0] = webpFlagEXIF | webpFlagXMP
+	data := webp(
+		chunk("VP8X", vp8x),
+		chunk("VP8L", []byte("image")),
+		chunk("EXIF", []byte("GPS 52.3676N")),
+		chunk("XMP ", []byte("<x:xmpmeta/>")),
+	)
+
+	stripped, orientation, err := StripMetadata(data, "image/webp")
+	require.NoError(t, err)
+	assert.Equal(t, 1, orientation)
+	assert.Equal(t, webp(chunk("VP8X", make([]byte, 10)), chunk("VP8L", []byte("image"))), stripped)
+}
+
+func TestProcess_Errors(t *testing.T) {
+	_, err := Process([]byte("%PDF-1.4"), "application/pdf")
+	assert.ErrorIs(t, err, ErrUnsupported)
+
+	_, err = Process([]byte("not a png"), "image/png")
+	assert.ErrorIs(t, err, ErrMalformed)
+
+	_, err = Process([]byte{0xFF, jpegSOI, 0xFF, jpegAPP1, 0xFF, 0xFF}, "image/jpeg")
+	assert.ErrorIs(t, err, ErrMalformed)
+
+	// A tiny file claiming huge dimensions is rejected before decoding
+	data := encodePNG(t, testImage(1, 1))
+	ihdr := data[len(pngSignature)+8 : len(pngSignature)+21]
+	binary.BigEndian.PutUint32(ihdr[0:], 100_000)
+	binary.BigEndian.PutUint32(ihdr[4:], 100_000)
+	huge := append(append([]byte{}, data[:len(pngSignature)]...), pngChunk("IHDR", ihdr)...)
+	huge = append(huge, data[len(pngSignature)+25:]...)
+
+	_, err = Process(huge, "image/png")
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestThumbnail(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 40, 20))
+
+	thumbnail, err := Thumbnail(img, 16, 8)
+	require.NoError(t, err)
+
+	decoded, err := jpeg.Decode(bytes.NewReader(thumbnail))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 16, 8), decoded.Bounds())
+
+	// Transparent pixels become white
+	r, g, b, _ := decoded.At(8, 4).RGBA()
+	assert.Greater(t, r>>8, uint32(240))
+	assert.Greater(t, g>>8, uint32(240))
+	assert.Greater(t, b>>8, uint32(240))
+}
+
+func TestOrient(t *testing.T) {
+	// Where the top-left pixel of a 3 by 2 image ends up
+	tests := []struct {
+		orientation int
+		bounds      image.Rectangle
+		red         image.Point
+	}{
+		{1, image.Rect(0, 0, 3, 2), image.Pt(0, 0)},
+		{2, image.Rect(0, 0, 3, 2), image.Pt(2, 0)},
+		{3, image.Rect(0, 0, 3, 2), image.Pt(2, 1)},
+		{4, image.Rect(0, 0, 3, 2), image.Pt(0, 1)},
+		{5, image.Rect(0, 0, 2, 3), image.Pt(0, 0)},
+		{6, image.Rect(0, 0, 2, 3), image.Pt(1, 0)},
+		{7, image.Rect(0, 0, 2, 3), image.Pt(1, 2)},
+		{8, image.Rect(0, 0, 2, 3), image.Pt(0, 2)},
+	}
+
+	for _, tt := range tests {
+		oriented := orient(testImage(3, 2), tt.orientation)
+		assert.Equal(t, tt.bounds, oriented.Bounds(), "orientation %d", tt.orientation)
+
+		r, _, _, _ := oriented.At(tt.red.X, tt.red.Y).RGBA()
+		assert.Equal(t, uint32(0xFFFF), r, "orientation %d", tt.orientation)
+	}
+}
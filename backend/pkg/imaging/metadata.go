@@ -0,0 +1,324 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// JPEG markers
+const (
+	jpegSOI  = 0xD8
+	jpegEOI  = 0xD9
+	jpegSOS  = 0xDA
+	jpegAPP0 = 0xE0
+	jpegAPP1 = 0xE1
+	jpegAPP2 = 0xE2
+	jpegAP14 = 0xEE
+	jpegAP15 = 0xEF
+	jpegCOM  = 0xFE
+)
+
+// stripJPEG drops the APPn segments other than JFIF, ICC profiles and the
+// Adobe color transform, and comments. Everything from the start of the
+// scan on is copied unchanged. The EXIF orientation is returned, 1 when the
+// file has none.
+func stripJPEG(data []byte) ([]byte, int, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != jpegSOI {
+		return nil, 0, fmt.Errorf("%w: missing JPEG start of image", ErrMalformed)
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xFF, jpegSOI)
+	orientation := 1
+
+	pos := 2
+	for {
+		// Markers may be preceded by any number of 0xFF fill bytes
+		for pos < len(data) && data[pos] == 0xFF && pos+1 < len(data) && data[pos+1] == 0xFF {
+			pos++
+		}
+		if pos+4 > len(data) || data[pos] != 0xFF {
+			return nil, 0, fmt.Errorf("%w: truncated JPEG header", ErrMalformed)
+		}
+
+		marker := data[pos+1]
+		if marker == jpegEOI {
+			return nil, 0, fmt.Errorf("%w: JPEG has no image data", ErrMalformed)
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, 0, fmt.Errorf("%w: bad JPEG segment length", ErrMalformed)
+		}
+		payload := data[pos+4 : end]
+
+		if marker == jpegSOS {
+			return append(out, data[pos:]...), orientation, nil
+		}
+
+		keep := true
+		switch {
+		case marker == jpegAPP1:
+			if bytes.HasPrefix(payload, []byte("Exif\x00\x00")) {
+				orientation = exifOrientation(payload[6:])
+			}
+			keep = false
+		case marker == jpegAPP0:
+			keep = bytes.HasPrefix(payload, []byte("JFIF\x00"))
+		case marker == jpegAPP2:
+			keep = bytes.HasPrefix(payload, []byte("ICC_PROFILE\x00"))
+		case marker == jpegAP14:
+			keep = bytes.HasPrefix(payload, []byte("Adobe"))
+		case marker > jpegAPP2 && marker <= jpegAP15, marker == jpegCOM:
+			keep = false
+		}
+		if keep {
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+}
+
+// exifOrientation reads the orientation tag of an EXIF TIFF structure,
+// returning 1 when it is missing or out of range
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:4]) {
+	case "II*\x00":
+		order = binary.LittleEndian
+	case "MM\x00*":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		// Orientation is a SHORT stored in the first bytes of the value
+		if order.Uint16(tiff[entry:]) == 0x0112 && order.Uint16(tiff[entry+2:]) == 3 {
+			value := int(order.Uint16(tiff[entry+8:]))
+			if value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// pngMetadataChunks are the ancillary PNG chunks that carry text, EXIF or
+// timestamps
+var pngMetadataChunks = map[string]bool{
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"eXIf": true,
+	"tIME": true,
+}
+
+// stripPNG drops the text, EXIF and timestamp chunks of a PNG
+func stripPNG(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, fmt.Errorf("%w: missing PNG signature", ErrMalformed)
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+
+	pos := len(pngSignature)
+	for pos < len(data) {
+		if pos+8 > len(data) {
+			return nil, fmt.Errorf("%w: truncated PNG chunk", ErrMalformed)
+		}
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		chunkType := string(data[pos+4 : pos+8])
+		end := pos + 12 + length
+		if length < 0 || end > len(data) || end < pos {
+			return nil, fmt.Errorf("%w: bad PNG chunk length", ErrMalformed)
+		}
+
+		if !pngMetadataChunks[chunkType] {
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+		if chunkType == "IEND" {
+			return out, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: PNG has no end chunk", ErrMalformed)
+}
+
+// GIF block introducers and extension labels
+const (
+	gifExtension   = 0x21
+	gifImage       = 0x2C
+	gifTrailer     = 0x3B
+	gifComment     = 0xFE
+	gifApplication = 0xFF
+)
+
+// stripGIF drops the comment extensions of a GIF and the application
+// extensions other than the animation loop count, which is where XMP
+// lives
+func stripGIF(data []byte) ([]byte, error) {
+	if len(data) < 13 || (string(data[:6]) != "GIF87a" && string(data[:6]) != "GIF89a") {
+		return nil, fmt.Errorf("%w: missing GIF header", ErrMalformed)
+	}
+
+	// The header, logical screen descriptor and global color table
+	pos := 13
+	if flags := data[10]; flags&0x80 != 0 {
+		pos += 3 << (flags&0x07 + 1)
+	}
+	if pos > len(data) {
+		return nil, fmt.Errorf("%w: truncated GIF color table", ErrMalformed)
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:pos]...)
+
+	for pos < len(data) {
+		start := pos
+		switch data[pos] {
+		case gifTrailer:
+			return append(out, gifTrailer), nil
+
+		case gifExtension:
+			if pos+2 > len(data) {
+				return nil, fmt.Errorf("%w: truncated GIF extension", ErrMalformed)
+			}
+			label := data[pos+1]
+			end, err := skipGIFSubBlocks(data, pos+2)
+			if err != nil {
+				return nil, err
+			}
+			keep := label != gifComment
+			if label == gifApplication {
+				keep = gifLoopExtension(data[pos+2 : end])
+			}
+			if keep {
+				out = append(out, data[start:end]...)
+			}
+			pos = end
+
+		case gifImage:
+			if pos+10 > len(data) {
+				return nil, fmt.Errorf("%w: truncated GIF image descriptor", ErrMalformed)
+			}
+			pos += 10
+			if flags := data[pos-1]; flags&0x80 != 0 {
+				pos += 3 << (flags&0x07 + 1)
+			}
+			// The LZW minimum code size precedes the image data
+			end, err := skipGIFSubBlocks(data, pos+1)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, data[start:end]...)
+			pos = end
+
+		default:
+			return nil, fmt.Errorf("%w: unknown GIF block 0x%02x", ErrMalformed, data[pos])
+		}
+	}
+
+	return nil, fmt.Errorf("%w: GIF has no trailer", ErrMalformed)
+}
+
+// skipGIFSubBlocks returns the position after the sub-blocks starting at
+// pos and their terminator
+func skipGIFSubBlocks(data []byte, pos int) (int, error) {
+	for {
+		if pos >= len(data) {
+			return 0, fmt.Errorf("%w: truncated GIF data", ErrMalformed)
+		}
+		size := int(data[pos])
+		pos++
+		if size == 0 {
+			return pos, nil
+		}
+		pos += size
+	}
+}
+
+// gifLoopExtension reports whether the sub-blocks of an application
+// extension are the NETSCAPE2.0 or ANIMEXTS1.0 animation loop count
+func gifLoopExtension(blocks []byte) bool {
+	if len(blocks) < 12 || blocks[0] != 11 {
+		return false
+	}
+	identifier := string(blocks[1:12])
+	return identifier == "NETSCAPE2.0" || identifier == "ANIMEXTS1.0"
+}
+
+// WebP extended format flags of the VP8X chunk
+const (
+	webpFlagEXIF = 0x08
+	webpFlagXMP  = 0x04
+)
+
+// stripWebP drops the EXIF and XMP chunks of a WebP and clears their flags
+func stripWebP(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, fmt.Errorf("%w: missing WebP header", ErrMalformed)
+	}
+
+	// Ignore anything after the RIFF container
+	if riffEnd := 8 + int(binary.LittleEndian.Uint32(data[4:])); riffEnd >= 12 && riffEnd < len(data) {
+		data = data[:riffEnd]
+	}
+
+	out := make([]byte, 12, len(data))
+	copy(out, data[:12])
+
+	pos := 12
+	for pos < len(data) {
+		if pos+8 > len(data) {
+			return nil, fmt.Errorf("%w: truncated WebP chunk", ErrMalformed)
+		}
+		chunkType := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		// Chunks are padded to an even size
+		end := pos + 8 + size + size%2
+		if size < 0 || end > len(data) || end < pos {
+			if end == len(data)+1 {
+				// Tolerate a missing pad byte on the last chunk
+				end = len(data)
+			} else {
+				return nil, fmt.Errorf("%w: bad WebP chunk size", ErrMalformed)
+			}
+		}
+
+		switch chunkType {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			start := len(out)
+			out = append(out, data[pos:end]...)
+			if size >= 1 {
+				out[start+8] &^= webpFlagEXIF | webpFlagXMP
+			}
+		default:
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return out, nil
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLocalStore creates a local store served at /blobs by a test server
+func newTestLocalStore(t *testing.T) (*LocalStore, string) {
+	dir := t.TempDir()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	store, err := NewLocalStore(dir, server.URL+"/blobs", []byte("test-secret"))
+	require.NoError(t, err)
+	mux.Handle("/blobs/", http.StripPrefix("/blobs", store))
+
+	return store, dir
+}
+
+func TestLocalStore(t *testing.T) {
+	store, dir := newTestLocalStore(t)
+
+	testBlobStore(t, store)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "deleting a blob removes the directories it leaves empty")
+}
+
+func TestLocalStore_SignedURLs(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestLocalStore(t)
+	require.NoError(t, store.Put(ctx, "a/photo.jpg", strings.NewReader("jpeg"), 4, "image/jpeg"))
+
+	signedURL, err := store.SignedURL(ctx, "a/photo.jpg", time.Minute)
+	require.NoError(t, err)
+
+	get := func(rawURL string) int {
+		t.Helper()
+		resp, err := http.Get(rawURL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, get(signedURL))
+
+	// The signature covers the key and the expiry
+	tampered, err := url.Parse(signedURL)
+	require.NoError(t, err)
+	query := tampered.Query()
+	query.Set("expires", "9999999999")
+	tampered.RawQuery = query.Encode()
+	assert.Equal(t, http.StatusForbidden, get(tampered.String()))
+	assert.Equal(t, http.StatusForbidden, get(strings.Replace(signedURL, "photo.jpg", "other.jpg", 1)))
+
+	// URLs stop working once they expire
+	store.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	assert.Equal(t, http.StatusForbidden, get(signedURL))
+}
+
+func TestLocalStore_PutChecksSize(t *testing.T) {
+	ctx := context.Background()
+	store, dir := newTestLocalStore(t)
+
+	assert.Error(t, store.Put(ctx, "short.txt", strings.NewReader("abc"), 4, "text/plain"))
+	assert.Error(t, store.Put(ctx, "long.txt", strings.NewReader("abcde"), 4, "text/plain"))
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	assert.Empty(t, matches, "failed uploads leave no files behind")
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := LoadConfigFromEnv()
+	require.NoError(t, valid.Validate())
+
+	s3 := *valid
+	s3.Backend = BackendS3
+	assert.Error(t, s3.Validate(), "S3 needs a bucket and credentials")
+	s3.S3Bucket, s3.S3AccessKeyID, s3.S3SecretAccessKey = "uploads", "key", "secret"
+	assert.NoError(t, s3.Validate())
+
+	unknown := *valid
+	unknown.Backend = "ftp"
+	assert.Error(t, unknown.Validate())
+
+	expiry := *valid
+	expiry.URLExpiry = 0
+	assert.Error(t, expiry.Validate())
+
+	cleanup := *valid
+	cleanup.CleanupInterval = 0
+	assert.Error(t, cleanup.Validate())
+
+	processing := *valid
+	processing.ProcessInterval = 0
+	assert.Error(t, processing.Validate())
+}
@@ -0,0 +1,213 @@
+// Package storage keeps uploaded files in a blob store: a directory on the
+// local filesystem or an S3-compatible bucket such as AWS S3 or MinIO.
+// Blobs are addressed by slash-separated keys and downloaded through signed,
+// expiring URLs.
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Blob store backends selectable with the BLOB_STORE environment variable
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// ErrNotFound is returned when a blob does not exist
+var ErrNotFound = errors.New("blob not found")
+
+// ErrInvalidKey is returned for keys that are empty, absolute or climb out
+// of the store with ".."
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// BlobStore stores blobs by key
+type BlobStore interface {
+	// Put stores size bytes from r under key, replacing any existing blob
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+
+	// Get opens the blob stored under key. The caller must close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the blob stored under key. Deleting a missing blob is
+	// not an error.
+	Delete(ctx context.Context, key string) error
+
+	// SignedURL returns a URL anyone can download the blob from until it
+	// expires
+	SignedURL(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+// Config holds blob store configuration
+type Config struct {
+	Backend string
+
+	// URLExpiry is how long signed download URLs stay valid
+	URLExpiry time.Duration
+	// CleanupInterval is how often blobs of deleted attachments are removed
+	CleanupInterval time.Duration
+	// ProcessInterval is how often new image attachments are checked for,
+	// to strip their metadata and render thumbnails
+	ProcessInterval time.Duration
+
+	// LocalDir is the directory the local backend stores blobs in
+	LocalDir string
+	// LocalBaseURL is the public URL the local backend's download handler is
+	// served at, and SigningSecret the key its URLs are signed with
+	LocalBaseURL  string
+	SigningSecret string
+
+	// S3-compatible backend settings. Path-style addressing puts the bucket
+	// in the path instead of the host name, which MinIO needs by default.
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3PathStyle       bool
+}
+
+// LoadConfigFromEnv loads blob store configuration from environment
+// variables
+func LoadConfigFromEnv() *Config {
+	return &Config{
+		Backend:         getEnvOrDefault("BLOB_STORE", BackendLocal),
+		URLExpiry:       getEnvDuration("BLOB_URL_EXPIRY", 15*time.Minute),
+		CleanupInterval: getEnvDuration("BLOB_CLEANUP_INTERVAL", time.Minute),
+		ProcessInterval: getEnvDuration("BLOB_PROCESS_INTERVAL", 5*time.Second),
+
+		LocalDir:      getEnvOrDefault("BLOB_DIR", "uploads"),
+		LocalBaseURL:  getEnvOrDefault("BLOB_BASE_URL", "http://localhost:8080/blobs"),
+		SigningSecret: os.Getenv("BLOB_SIGNING_SECRET"),
+
+		S3Endpoint:        getEnvOrDefault("S3_ENDPOINT", "https://s3.amazonaws.com"),
+		S3Region:          getEnvOrDefault("S3_REGION", "us-east-1"),
+		S3Bucket:          os.Getenv("S3_BUCKET"),
+		S3AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+		S3SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		S3PathStyle:       getEnvBool("S3_PATH_STYLE", false),
+	}
+}
+
+// Validate checks that the configuration is usable
+func (c *Config) Validate() error {
+	if c.URLExpiry <= 0 {
+		return fmt.Errorf("signed URL expiry must be positive")
+	}
+	if c.CleanupInterval <= 0 {
+		return fmt.Errorf("blob cleanup interval must be positive")
+	}
+	if c.ProcessInterval <= 0 {
+		return fmt.Errorf("attachment processing interval must be positive")
+	}
+
+	switch c.Backend {
+	case BackendLocal:
+		if c.LocalDir == "" {
+			return fmt.Errorf("local blob directory is required")
+		}
+		if c.LocalBaseURL == "" {
+			return fmt.Errorf("local blob base URL is required")
+		}
+	case BackendS3:
+		if c.S3Endpoint == "" || c.S3Region == "" || c.S3Bucket == "" {
+			return fmt.Errorf("S3 endpoint, region and bucket are required")
+		}
+		if c.S3AccessKeyID == "" || c.S3SecretAccessKey == "" {
+			return fmt.Errorf("S3 access key ID and secret access key are required")
+		}
+	default:
+		return fmt.Errorf("unknown blob store %q: expected local or s3", c.Backend)
+	}
+
+	return nil
+}
+
+// New creates the blob store selected by config.Backend
+func New(config *Config) (BlobStore, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch config.Backend {
+	case BackendS3:
+		return NewS3Store(S3Options{
+			Endpoint:        config.S3Endpoint,
+			Region:          config.S3Region,
+			Bucket:          config.S3Bucket,
+			AccessKeyID:     config.S3AccessKeyID,
+			SecretAccessKey: config.S3SecretAccessKey,
+			PathStyle:       config.S3PathStyle,
+		})
+	default:
+		secret := []byte(config.SigningSecret)
+		if len(secret) == 0 {
+			// Signed URLs stop working on restart without a configured secret
+			log.Println("BLOB_SIGNING_SECRET is not set; using a random secret for this process")
+			secret = make([]byte, 32)
+			if _, err := rand.Read(secret); err != nil {
+				return nil, fmt.Errorf("failed to generate signing secret: %w", err)
+			}
+		}
+		return NewLocalStore(config.LocalDir, config.LocalBaseURL, secret)
+	}
+}
+
+// validateKey rejects keys that could escape the store. Keys are
+// slash-separated and relative, without empty, "." or ".." segments.
+func validateKey(key string) error {
+	if key == "" || strings.ContainsRune(key, '\\') {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	return nil
+}
+
+// getEnvOrDefault returns the environment variable value or a default
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvDuration parses a duration such as "15m" from the environment
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid %s %q, using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return d
+}
+
+// getEnvBool parses a boolean from the environment
+func getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid %s %q, using default %t", key, value, defaultValue)
+		return defaultValue
+	}
+	return b
+}
@@ -24,7 +24,7 @@ export interface BucketListItem {
   completed: boolean;
   completedBy?: string;
   completedAt?: string;
-  createdBy: string;
+  createdBy?: string;
   createdAt: string;
 }
 